@@ -2,14 +2,25 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 
 	"coldmic/internal/bootstrap"
 	"coldmic/internal/config"
 	"coldmic/internal/domain"
+	"coldmic/internal/eventschema"
+	"coldmic/internal/input"
+	"coldmic/internal/models"
+	"coldmic/internal/ports"
 	"coldmic/internal/usecase"
 )
 
@@ -18,18 +29,61 @@ const (
 	eventPartial = "coldmic:partial"
 	eventFinal   = "coldmic:final"
 	eventError   = "coldmic:error"
+	eventFileJob = "coldmic:file-job"
+	eventCaption = "coldmic:caption"
+	eventNetwork = "coldmic:network"
+	eventIssue   = "coldmic:issue"
+	eventCopied  = "coldmic:copied"
+	eventRetry   = "coldmic:low-confidence"
+	eventSpans   = "coldmic:final-spans"
+	eventModel   = "coldmic:model-progress"
+	eventPlay    = "coldmic:playback"
 )
 
+// triggerLearnTimeout bounds how long LearnTriggerDevice waits for a press.
+const triggerLearnTimeout = 15 * time.Second
+
 var eventsEmit = runtime.EventsEmit
 var windowMinimise = runtime.WindowMinimise
 
 // App is the Wails application root.
 type App struct {
-	ctx context.Context
+	ctx   context.Context
+	state atomic.Pointer[appServices]
+
+	// mu serializes workspace switches with the background workers they
+	// restart.
+	mu             sync.Mutex
+	stopBackground context.CancelFunc
+}
 
-	session *usecase.SessionService
-	cfg     config.Config
-	bootErr error
+// appServices is one workspace's service graph. SetWorkspace builds a new
+// one and swaps it in whole, so bindings running meanwhile see either the
+// old graph or the new one, never a mix.
+type appServices struct {
+	session  *usecase.SessionService
+	control  *usecase.SessionController
+	correct  *usecase.Corrector
+	urls     *usecase.URLTranscriber
+	meeting  *usecase.MeetingController
+	backup   *usecase.HistoryBackup
+	history  *usecase.TranscriptHistory
+	wipe     *usecase.PanicWipe
+	batch    *usecase.BatchPool
+	tokens   ports.AccessTokenStore
+	forms    ports.FormSchemaStore
+	targets  *bootstrap.TargetSwitcher
+	hotkeys  *usecase.HotkeyDispatcher
+	trigger  *usecase.ButtonTrigger
+	provider ports.TranscriptionProvider
+	validate ports.ProviderValidator
+	models   *models.Manager
+	waves    ports.WaveformReader
+	playback *usecase.AudioPlayback
+	usage    *bootstrap.ResourceMonitor
+	meter    *usecase.UsageMeter
+	cfg      config.Config
+	bootErr  error
 }
 
 func NewApp() *App {
@@ -40,17 +94,127 @@ func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
 
 	services, err := bootstrap.Build(a, &wailsClipboard{})
+	if err == nil {
+		err = bootstrap.Preflight(ctx, services)
+	}
 	if err != nil {
-		a.bootErr = err
-		a.SessionError(domain.ErrorCodeStartup, err.Error())
+		a.state.Store(&appServices{bootErr: err})
+		a.SessionError(domain.ErrorCodeFor(err, domain.ErrorCodeStartup), err.Error())
 		return
 	}
 
-	a.cfg = services.Config
-	a.session = services.Session
+	a.mu.Lock()
+	a.use(services)
+	a.mu.Unlock()
 	a.SessionStateChanged(domain.SessionStateIdle, domain.SessionReasonMicCold)
 }
 
+// current returns the installed service graph, which is empty before
+// startup.
+func (a *App) current() *appServices {
+	if s := a.state.Load(); s != nil {
+		return s
+	}
+	return &appServices{}
+}
+
+// use installs a service graph and (re)starts its background workers.
+// Callers hold a.mu.
+func (a *App) use(services bootstrap.Services) {
+	if a.stopBackground != nil {
+		a.stopBackground()
+	}
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.stopBackground = cancel
+
+	previous := a.state.Swap(&appServices{
+		cfg:      services.Config,
+		session:  services.Session,
+		control:  services.Controller,
+		correct:  services.Corrector,
+		urls:     services.URLs,
+		meeting:  services.Meeting,
+		backup:   services.Backup,
+		batch:    services.Batch,
+		tokens:   services.Tokens,
+		forms:    services.Forms,
+		targets:  services.Targets,
+		hotkeys:  services.Hotkeys,
+		trigger:  services.Trigger,
+		provider: services.Provider,
+		validate: services.Validator,
+		models:   services.Models,
+		waves:    services.Waveforms,
+		history:  services.Revisions,
+		wipe:     services.Wipe,
+		playback: services.Playback,
+		usage:    services.Resources,
+		meter:    services.Usage,
+	})
+	if previous != nil && previous.playback != nil {
+		_ = previous.playback.Stop()
+	}
+	go func() {
+		_ = services.Batch.Run(ctx)
+	}()
+	if services.Watcher != nil {
+		go func() {
+			if err := services.Watcher.Run(ctx); err != nil {
+				a.SessionError(domain.ErrorCodeStartup, err.Error())
+			}
+		}()
+	}
+	if services.Backup != nil {
+		go func() {
+			_ = services.Backup.Run(ctx)
+		}()
+	}
+	if services.Network != nil {
+		go func() {
+			_ = services.Network.Run(ctx)
+		}()
+	}
+	go func() {
+		_ = services.Trigger.Run(ctx)
+	}()
+	if services.MIDI != nil {
+		go func() {
+			_ = services.MIDI.Run(ctx)
+		}()
+	}
+	if services.Gamepad != nil {
+		go func() {
+			_ = services.Gamepad.Run(ctx)
+		}()
+	}
+}
+
+// SetWorkspace switches history, rules, and provider settings to the named
+// workspace. The current workspace stays active if the new one fails to load
+// or its provider check fails.
+func (a *App) SetWorkspace(name string) error {
+	if a.ctx == nil {
+		return fmt.Errorf("application is not initialized")
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s := a.current()
+	if (s.session != nil && s.session.Status().Active) || (s.meeting != nil && s.meeting.Active()) {
+		return errors.New("stop recording before switching workspaces")
+	}
+
+	services, err := bootstrap.BuildWorkspace(a, &wailsClipboard{}, name)
+	if err != nil {
+		return err
+	}
+	if err := bootstrap.Preflight(a.ctx, services); err != nil {
+		return err
+	}
+	a.use(services)
+	a.SessionStateChanged(domain.SessionStateIdle, domain.SessionReasonMicCold)
+	return nil
+}
+
 // beforeClose keeps the app running and minimizes the window instead.
 func (a *App) beforeClose(ctx context.Context) bool {
 	windowMinimise(ctx)
@@ -59,76 +223,669 @@ func (a *App) beforeClose(ctx context.Context) bool {
 
 // StartPTT starts push-to-talk recording.
 func (a *App) StartPTT() (domain.Status, error) {
-	if err := a.requireReady(); err != nil {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
 		return domain.Status{}, err
 	}
-	if err := a.session.Start(a.ctx); err != nil {
-		a.SessionError(domain.ErrorCodeTranscription, err.Error())
+	if err := s.session.Start(a.ctx); err != nil {
+		a.SessionError(domain.ErrorCodeFor(err, domain.ErrorCodeTranscription), err.Error())
 		return domain.Status{}, err
 	}
-	return a.session.Status(), nil
+	return s.session.Status(), nil
 }
 
 // StopPTT stops recording and returns processed transcript output.
 func (a *App) StopPTT() (domain.StopResult, error) {
-	if err := a.requireReady(); err != nil {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
 		return domain.StopResult{}, err
 	}
-	result, err := a.session.Stop(a.ctx)
+	result, err := s.session.Stop(a.ctx)
 	if err != nil {
-		a.SessionError(domain.ErrorCodeTranscription, err.Error())
+		a.SessionError(domain.ErrorCodeFor(err, domain.ErrorCodeTranscription), err.Error())
 		return domain.StopResult{}, err
 	}
 	return result, nil
 }
 
+// RetryLastSession re-transcribes the last low-confidence recording with the
+// retry model and copies the new transcript.
+func (a *App) RetryLastSession() (domain.StopResult, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return domain.StopResult{}, err
+	}
+	return s.session.RetryLastSession(a.ctx)
+}
+
+// CorrectWord replaces original with corrected in the last transcript, copies
+// it again and adds a substitution rule so the fix sticks.
+func (a *App) CorrectWord(original string, corrected string) (domain.StopResult, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return domain.StopResult{}, err
+	}
+	result, err := s.correct.Correct(a.ctx, original, corrected)
+	if err != nil && !errors.Is(err, domain.ErrCorrectionNotFound) && !errors.Is(err, domain.ErrNoTranscriptAvailable) {
+		a.SessionError(domain.ErrorCodeRules, err.Error())
+	}
+	return result, err
+}
+
+// AddTemporaryRule adds a rule, in rules-file syntax, for the current
+// recording, or the next one when idle, without saving it to the rules file.
+func (a *App) AddTemporaryRule(line string) error {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return err
+	}
+	return s.session.AddTemporaryRule(line)
+}
+
 // AbortPTT discards an in-progress recording.
 func (a *App) AbortPTT() error {
-	if err := a.requireReady(); err != nil {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
 		return err
 	}
-	if err := a.session.Abort(); err != nil {
+	if err := s.session.Abort(); err != nil {
 		if errors.Is(err, domain.ErrNoActiveSession) {
 			return nil
 		}
-		a.SessionError(domain.ErrorCodeTranscription, err.Error())
+		a.SessionError(domain.ErrorCodeFor(err, domain.ErrorCodeTranscription), err.Error())
+		return err
+	}
+	return nil
+}
+
+// StartMeeting starts multi-track meeting capture (mic and desktop audio).
+func (a *App) StartMeeting() error {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return err
+	}
+	if err := s.meeting.Start(a.ctx); err != nil {
+		a.SessionError(domain.ErrorCodeFor(err, domain.ErrorCodeTranscription), err.Error())
+		return err
+	}
+	return nil
+}
+
+// StopMeeting stops meeting capture and returns the dialogue transcript.
+func (a *App) StopMeeting() (domain.StopResult, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return domain.StopResult{}, err
+	}
+	result, err := s.meeting.Stop(a.ctx)
+	if err != nil {
+		a.SessionError(domain.ErrorCodeFor(err, domain.ErrorCodeTranscription), err.Error())
+		return domain.StopResult{}, err
+	}
+	return result, nil
+}
+
+// AbortMeeting discards an in-progress meeting capture.
+func (a *App) AbortMeeting() error {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return err
+	}
+	if err := s.meeting.Abort(); err != nil && !errors.Is(err, domain.ErrNoActiveSession) {
+		a.SessionError(domain.ErrorCodeFor(err, domain.ErrorCodeTranscription), err.Error())
+		return err
+	}
+	return nil
+}
+
+// SwitchMeetingProvider points meeting transcription at another Deepgram
+// endpoint and/or model, e.g. a self-hosted instance when leaving the office
+// network. Empty values keep the configured ones. An active meeting hands
+// over at the next pause in speech without losing transcript text.
+func (a *App) SwitchMeetingProvider(baseURL string, model string) error {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return err
+	}
+	cfg := s.cfg.Deepgram
+	if baseURL = strings.TrimSpace(baseURL); baseURL != "" {
+		cfg.APIBaseURL = baseURL
+	}
+	if model = strings.TrimSpace(model); model != "" {
+		cfg.Model = model
+	}
+	provider, err := bootstrap.DeepgramProvider(s.cfg, cfg)
+	if err == nil {
+		err = s.meeting.SetProvider(provider)
+	}
+	if err != nil {
+		a.SessionError(domain.ErrorCodeFor(err, domain.ErrorCodeTranscription), err.Error())
 		return err
 	}
 	return nil
 }
 
+// SetForm switches dictation into form-filling mode using the named schema
+// from the forms directory. An empty name returns to plain transcripts.
+func (a *App) SetForm(name string) error {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return err
+	}
+	if name = strings.ToLower(strings.TrimSpace(name)); name == "" {
+		return s.control.SetForm(nil)
+	}
+	schema, err := s.forms.Load(name)
+	if err != nil {
+		a.SessionError(domain.ErrorCodeForm, err.Error())
+		return err
+	}
+	if err := s.control.SetForm(&schema); err != nil {
+		a.SessionError(domain.ErrorCodeForm, err.Error())
+		return err
+	}
+	return nil
+}
+
+// SetTarget formats later transcripts for the named output target, a built-in
+// one such as "git-commit" or one defined in the targets file. "clipboard"
+// copies transcripts unchanged.
+func (a *App) SetTarget(name string) error {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return err
+	}
+	if _, err := s.targets.Select(name); err != nil {
+		a.SessionError(domain.ErrorCodeTarget, err.Error())
+		return err
+	}
+	return nil
+}
+
+// ListTargets returns the built-in output targets and those defined in the
+// targets file, marking the active one.
+func (a *App) ListTargets() ([]domain.OutputTargetInfo, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return nil, err
+	}
+	return s.targets.List(), nil
+}
+
+// ListHotkeys returns the hotkeys defined in the hotkeys file.
+func (a *App) ListHotkeys() ([]domain.Hotkey, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return nil, err
+	}
+	return s.hotkeys.List(), nil
+}
+
+// LearnTriggerDevice waits for a button press on any input device, such as a
+// USB foot pedal, and uses that button for push-to-talk until restart. Save
+// the returned path and key code as COLDMIC_TRIGGER_DEVICE and
+// COLDMIC_TRIGGER_KEYCODE to keep it.
+func (a *App) LearnTriggerDevice() (domain.TriggerDevice, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return domain.TriggerDevice{}, err
+	}
+	ctx, cancel := context.WithTimeout(a.ctx, triggerLearnTimeout)
+	defer cancel()
+	device, err := input.NewEvdevLearner().Learn(ctx)
+	if err != nil {
+		a.SessionError(domain.ErrorCodeTrigger, err.Error())
+		return domain.TriggerDevice{}, err
+	}
+	s.trigger.SetSource(input.NewEvdevButton(device.Path, device.KeyCode))
+	return device, nil
+}
+
+// TriggerHotkey runs the named hotkey, such as one starting dictation to a
+// target or copying the last transcript again.
+func (a *App) TriggerHotkey(name string) (domain.HotkeyResult, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return domain.HotkeyResult{}, err
+	}
+	result, err := s.hotkeys.Trigger(a.ctx, name)
+	if err != nil && !errors.Is(err, domain.ErrHotkeyNotFound) {
+		a.SessionError(domain.ErrorCodeFor(err, domain.ErrorCodeTranscription), err.Error())
+	}
+	return result, err
+}
+
+// ConfirmIssue creates the issue drafted by the last dictation to an issue
+// target and returns it with its URL.
+func (a *App) ConfirmIssue() (domain.Issue, error) {
+	target, err := a.issueTarget()
+	if err != nil {
+		return domain.Issue{}, err
+	}
+	issue, err := target.Confirm(a.ctx)
+	if err != nil {
+		a.SessionError(domain.ErrorCodeTarget, err.Error())
+		return domain.Issue{}, err
+	}
+	return issue, nil
+}
+
+// DiscardIssue drops the pending issue draft without creating it.
+func (a *App) DiscardIssue() error {
+	target, err := a.issueTarget()
+	if err != nil {
+		return err
+	}
+	return target.Discard()
+}
+
+func (a *App) issueTarget() (*usecase.IssueTarget, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return nil, err
+	}
+	var target *usecase.IssueTarget
+	ok := false
+	if s.targets != nil {
+		target, ok = s.targets.Active().(*usecase.IssueTarget)
+	}
+	if !ok {
+		return nil, errors.New("the active output target does not create issues")
+	}
+	return target, nil
+}
+
+// ListForms returns the names of the form schemas in the forms directory.
+func (a *App) ListForms() ([]string, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return nil, err
+	}
+	return s.forms.List()
+}
+
+// TranscribeURL downloads audio from a URL and returns its processed transcript.
+func (a *App) TranscribeURL(sourceURL string) (domain.FileTranscript, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return domain.FileTranscript{}, err
+	}
+	result, err := s.urls.TranscribeURL(a.ctx, sourceURL)
+	if err != nil {
+		a.SessionError(domain.ErrorCodeFor(err, domain.ErrorCodeTranscription), err.Error())
+		return domain.FileTranscript{}, err
+	}
+	return result, nil
+}
+
+// TranscribeFiles queues audio files for background transcription into
+// history. Progress is reported through file job events.
+func (a *App) TranscribeFiles(paths []string) ([]domain.FileJob, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return nil, err
+	}
+	jobs := make([]domain.FileJob, 0, len(paths))
+	for _, path := range paths {
+		if path = strings.TrimSpace(path); path != "" {
+			jobs = append(jobs, s.batch.Submit(path, ""))
+		}
+	}
+	return jobs, nil
+}
+
+// CancelFileJob stops a queued or running file transcription job.
+func (a *App) CancelFileJob(id string) error {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return err
+	}
+	return s.batch.Cancel(id)
+}
+
+// GetJobs lists queued, running, and recently finished file jobs.
+func (a *App) GetJobs() ([]domain.FileJob, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return nil, err
+	}
+	return s.batch.Jobs(a.ctx)
+}
+
+// RetryFileJob queues a failed or canceled file job again.
+func (a *App) RetryFileJob(id string) (domain.FileJob, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return domain.FileJob{}, err
+	}
+	return s.batch.Retry(a.ctx, id)
+}
+
+// EditTranscript stores text as the user's version of a history entry,
+// keeping the raw and rules versions; empty text removes the edit.
+func (a *App) EditTranscript(id string, text string) (domain.HistoryEntry, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return domain.HistoryEntry{}, err
+	}
+	return s.history.Edit(a.ctx, id, text)
+}
+
+// CompareTranscript returns the raw, rules and edited versions of a history
+// entry with a word diff for each stage.
+func (a *App) CompareTranscript(id string) (domain.TranscriptComparison, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return domain.TranscriptComparison{}, err
+	}
+	return s.history.Compare(a.ctx, id)
+}
+
+// ReapplyRules runs the current rules over the history entries matching
+// filter and returns how many changed; their first rules output is kept.
+func (a *App) ReapplyRules(filter domain.HistoryFilter) (int, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return 0, err
+	}
+	return s.history.Reapply(a.ctx, filter)
+}
+
+// ExportConfigBundle writes settings, rules, Deepgram profiles and forms to
+// path as one bundle. Secrets are left out and only listed by name.
+func (a *App) ExportConfigBundle(path string) error {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := config.ExportBundle(s.cfg, file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// ImportConfigBundle installs the files of a bundle written by
+// ExportConfigBundle and saves its settings as an env file to load on the
+// next start. Secrets listed in the result still have to be set.
+func (a *App) ImportConfigBundle(path string) (config.ImportResult, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return config.ImportResult{}, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return config.ImportResult{}, err
+	}
+	defer file.Close()
+	return config.ImportBundle(s.cfg, file)
+}
+
+// RestoreHistoryBackup merges the latest remote history backup into local
+// history and returns the number of restored entries.
+func (a *App) RestoreHistoryBackup() (int, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return 0, err
+	}
+	if s.backup == nil {
+		return 0, errors.New("history backup is not configured")
+	}
+	return s.backup.Restore(a.ctx)
+}
+
+// PanicWipe is the emergency action for something sensitive dictated on the
+// wrong machine: it aborts any recording, stops playback, clears the
+// clipboard and forgets in-memory transcripts. With COLDMIC_PANIC_PURGE it
+// also deletes today's history and recordings.
+func (a *App) PanicWipe() (domain.PanicWipeResult, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return domain.PanicWipeResult{}, err
+	}
+	if s.playback != nil {
+		_ = s.playback.Stop()
+	}
+	return s.wipe.Wipe(a.ctx)
+}
+
+// CreateAccessToken issues a daemon control API token limited to scope
+// ("status", "history", or "full"). The secret is only returned once.
+func (a *App) CreateAccessToken(label string, scope string) (string, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return "", err
+	}
+	secret, _, err := s.tokens.Create(a.ctx, label, domain.TokenScope(strings.ToLower(strings.TrimSpace(scope))))
+	if err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// GetSessionLog returns the internal event log of the current or most
+// recent session for bug reports. It holds no transcript text.
+func (a *App) GetSessionLog() (domain.SessionLog, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return domain.SessionLog{}, err
+	}
+	return s.control.SessionLog()
+}
+
 // GetStatus returns the current session status.
 func (a *App) GetStatus() domain.Status {
-	if a.session == nil {
-		if a.bootErr != nil {
-			return domain.Status{State: domain.SessionStateError, Active: false, Message: a.bootErr.Error()}
+	s := a.current()
+	if s.session == nil {
+		if s.bootErr != nil {
+			return domain.Status{State: domain.SessionStateError, Active: false, Message: s.bootErr.Error()}
 		}
 		return domain.Status{State: domain.SessionStateIdle, Active: false}
 	}
-	return a.session.Status()
+	return s.session.Status()
 }
 
 // GetRuntimeInfo returns non-sensitive config for the UI.
 func (a *App) GetRuntimeInfo() map[string]string {
-	if a.bootErr != nil {
-		return map[string]string{"error": a.bootErr.Error()}
+	s := a.current()
+	if s.bootErr != nil {
+		return map[string]string{"error": s.bootErr.Error()}
 	}
 
 	return map[string]string{
-		"provider":         "Deepgram",
-		"model":            a.cfg.Deepgram.Model,
-		"language":         a.cfg.Deepgram.Language,
-		"rulesFile":        a.cfg.Rules.Path,
-		"audioInput":       a.cfg.Audio.InputDevice,
-		"audioInputFormat": a.cfg.Audio.InputFormat,
+		"workspace":        s.cfg.Workspace,
+		"provider":         bootstrap.Capabilities(s.cfg, s.provider).Provider,
+		"model":            bootstrap.ProviderModel(s.cfg),
+		"language":         bootstrap.ProviderLanguage(s.cfg),
+		"rulesFile":        s.cfg.Rules.Path,
+		"audioInput":       s.cfg.Audio.InputDevice,
+		"audioInputFormat": s.cfg.Audio.InputFormat,
+	}
+}
+
+// GetCapabilities reports what the configured provider offers, including the
+// detected and active acceleration backends of local providers.
+func (a *App) GetCapabilities() (domain.Capabilities, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return domain.Capabilities{}, err
+	}
+	return bootstrap.Capabilities(s.cfg, s.provider), nil
+}
+
+// CheckProvider checks the primary provider's credentials now, reporting
+// an unreachable provider as well as a rejected key.
+func (a *App) CheckProvider() error {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return err
+	}
+	if s.validate == nil {
+		return fmt.Errorf("provider %s cannot check its credentials", bootstrap.Capabilities(s.cfg, s.provider).Provider)
+	}
+	ctx, cancel := context.WithTimeout(a.ctx, 10*time.Second)
+	defer cancel()
+	return s.validate.Validate(ctx)
+}
+
+// GetResourceUsage reports the memory, goroutines and CPU time of the
+// process and which background subsystems are running.
+func (a *App) GetResourceUsage() (domain.ResourceUsage, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return domain.ResourceUsage{}, err
 	}
+	return s.usage.Usage(), nil
+}
+
+// GetEventSchema returns the versioned JSON schema of the session, partial,
+// final and error event payloads and StopResult.
+func (a *App) GetEventSchema() string {
+	return string(eventschema.Document())
 }
 
-func (a *App) requireReady() error {
-	if a.bootErr != nil {
-		return a.bootErr
+// SimulateEvent emits a session, partial, final or error event with payload,
+// whose fields are those of the event schema, as if a session produced it.
+// It lets UI development reach rare states without recording, and needs
+// COLDMIC_SIMULATE_EVENTS=true.
+func (a *App) SimulateEvent(kind string, payload map[string]any) error {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return err
+	}
+	if !s.cfg.SimulateEvents {
+		return errors.New("simulated events are disabled; set COLDMIC_SIMULATE_EVENTS=true")
+	}
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("invalid %s event payload: %w", kind, err)
+	}
+	switch kind {
+	case "session":
+		var event domain.SessionEvent
+		if err := json.Unmarshal(encoded, &event); err != nil {
+			return fmt.Errorf("invalid session event payload: %w", err)
+		}
+		if event.State == "" {
+			return errors.New("session event needs a state")
+		}
+		a.SessionStateChanged(event.State, event.Reason)
+	case "partial":
+		var event domain.PartialEvent
+		if err := json.Unmarshal(encoded, &event); err != nil {
+			return fmt.Errorf("invalid partial event payload: %w", err)
+		}
+		a.PartialTranscript(event.Text)
+	case "final":
+		var event domain.FinalEvent
+		if err := json.Unmarshal(encoded, &event); err != nil {
+			return fmt.Errorf("invalid final event payload: %w", err)
+		}
+		a.FinalTranscript(event.Raw, event.Transformed, event.SessionID, event.Warnings)
+	case "error":
+		var event domain.ErrorEvent
+		if err := json.Unmarshal(encoded, &event); err != nil {
+			return fmt.Errorf("invalid error event payload: %w", err)
+		}
+		if event.Code == "" {
+			return errors.New("error event needs a code")
+		}
+		a.SessionError(event.Code, event.Detail)
+	default:
+		return fmt.Errorf("unknown event kind %q (expected session, partial, final or error)", kind)
 	}
-	if a.session == nil {
+	return nil
+}
+
+// GetUsage returns the provider usage and estimated cost of month, such as
+// "2026-10", or of the current month when month is empty.
+func (a *App) GetUsage(month string) (domain.UsageTotal, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return domain.UsageTotal{}, err
+	}
+	return s.meter.Month(a.ctx, month)
+}
+
+// GetWaveform returns downsampled peaks for the audio saved by a meeting so
+// the history view can draw a timeline under its utterance offsets.
+func (a *App) GetWaveform(sessionID string) (domain.Waveform, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return domain.Waveform{}, err
+	}
+	return s.waves.Waveform(sessionID, 0)
+}
+
+// PlaySessionAudio plays a meeting's saved audio from fromSeconds, replacing
+// any playback in progress; positions are emitted as playback events.
+func (a *App) PlaySessionAudio(sessionID string, fromSeconds float64) error {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return err
+	}
+	return s.playback.Play(a.ctx, sessionID, time.Duration(fromSeconds*float64(time.Second)))
+}
+
+// SeekToWord plays a recent meeting's saved audio from the start of word
+// wordIndex, counting words across the meeting's segments in order.
+func (a *App) SeekToWord(sessionID string, wordIndex int) error {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return err
+	}
+	offset, err := s.meeting.WordOffset(sessionID, wordIndex)
+	if err != nil {
+		return err
+	}
+	return s.playback.Play(a.ctx, sessionID, offset)
+}
+
+// StopSessionAudio stops playback started by PlaySessionAudio.
+func (a *App) StopSessionAudio() error {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return err
+	}
+	return s.playback.Stop()
+}
+
+// ListLocalModels returns the downloadable models and whether each is installed.
+func (a *App) ListLocalModels() ([]domain.LocalModel, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return nil, err
+	}
+	return s.models.List(), nil
+}
+
+// DownloadModel fetches and verifies the named model, emitting progress events.
+func (a *App) DownloadModel(name string) (domain.LocalModel, error) {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return domain.LocalModel{}, err
+	}
+	return s.models.Download(a.ctx, name)
+}
+
+// DeleteModel removes the named model from disk.
+func (a *App) DeleteModel(name string) error {
+	s := a.current()
+	if err := s.requireReady(); err != nil {
+		return err
+	}
+	return s.models.Delete(name)
+}
+
+func (s *appServices) requireReady() error {
+	if s.bootErr != nil {
+		return s.bootErr
+	}
+	if s.session == nil {
 		return fmt.Errorf("application is not initialized")
 	}
 	return nil
@@ -139,10 +896,11 @@ func (a *App) SessionStateChanged(state domain.SessionState, reason domain.Sessi
 	if a.ctx == nil {
 		return
 	}
-	eventsEmit(a.ctx, eventSession, map[string]string{
-		"state":   string(state),
-		"reason":  string(reason),
-		"message": sessionReasonMessage(reason),
+	eventsEmit(a.ctx, eventSession, domain.SessionEvent{
+		SchemaVersion: domain.EventSchemaVersion,
+		State:         state,
+		Reason:        reason,
+		Message:       sessionReasonMessage(reason),
 	})
 }
 
@@ -151,18 +909,20 @@ func (a *App) PartialTranscript(text string) {
 	if a.ctx == nil {
 		return
 	}
-	eventsEmit(a.ctx, eventPartial, map[string]string{"text": text})
+	eventsEmit(a.ctx, eventPartial, domain.PartialEvent{SchemaVersion: domain.EventSchemaVersion, Text: text})
 }
 
 // FinalTranscript emits final transcript output.
-func (a *App) FinalTranscript(raw string, transformed string, sessionID string) {
+func (a *App) FinalTranscript(raw string, transformed string, sessionID string, warnings []domain.SessionWarning) {
 	if a.ctx == nil {
 		return
 	}
-	eventsEmit(a.ctx, eventFinal, map[string]string{
-		"raw":         raw,
-		"transformed": transformed,
-		"sessionId":   sessionID,
+	eventsEmit(a.ctx, eventFinal, domain.FinalEvent{
+		SchemaVersion: domain.EventSchemaVersion,
+		Raw:           raw,
+		Transformed:   transformed,
+		SessionID:     sessionID,
+		Warnings:      warnings,
 	})
 }
 
@@ -171,13 +931,153 @@ func (a *App) SessionError(code domain.ErrorCode, detail string) {
 	if a.ctx == nil {
 		return
 	}
-	eventsEmit(a.ctx, eventError, map[string]string{
-		"code":    string(code),
-		"message": errorMessage(code, detail),
-		"detail":  detail,
+	eventsEmit(a.ctx, eventError, domain.ErrorEvent{
+		SchemaVersion: domain.EventSchemaVersion,
+		Code:          code,
+		Message:       errorMessage(code, detail),
+		Detail:        detail,
 	})
 }
 
+// FileJobChanged emits watch-folder and batch transcription progress.
+func (a *App) FileJobChanged(job domain.FileJob) {
+	if a.ctx == nil {
+		return
+	}
+	eventsEmit(a.ctx, eventFileJob, map[string]string{
+		"id":         job.ID,
+		"sourcePath": job.SourcePath,
+		"outputPath": job.OutputPath,
+		"state":      string(job.State),
+		"error":      job.Error,
+	})
+}
+
+// Caption emits translated captions on a stream separate from transcripts.
+func (a *App) Caption(caption domain.Caption) {
+	if a.ctx == nil {
+		return
+	}
+	eventsEmit(a.ctx, eventCaption, map[string]string{
+		"kind":       string(caption.Kind),
+		"sourceText": caption.SourceText,
+		"text":       caption.Text,
+		"language":   caption.Language,
+		"sessionId":  caption.SessionID,
+	})
+}
+
+// TranscriptCopied emits the transcript-copied payload for the frontend and
+// clipboard manager integrations.
+func (a *App) TranscriptCopied(_ context.Context, event domain.CopyEvent) error {
+	if a.ctx == nil {
+		return nil
+	}
+	eventsEmit(a.ctx, eventCopied, map[string]string{
+		"sessionId":     event.SessionID,
+		"text":          event.Text,
+		"rawTranscript": event.RawTranscript,
+		"target":        event.Target,
+		"form":          event.Form,
+		"copiedAt":      event.CopiedAt.Format(time.RFC3339Nano),
+	})
+	return nil
+}
+
+// ConfidenceSpans emits the final transcript split by word confidence so
+// uncertain words can be highlighted.
+func (a *App) ConfidenceSpans(transcript domain.ConfidenceTranscript) {
+	if a.ctx == nil {
+		return
+	}
+	eventsEmit(a.ctx, eventSpans, transcript)
+}
+
+// PlaybackPosition emits the position of saved-audio playback.
+func (a *App) PlaybackPosition(position domain.PlaybackPosition) {
+	if a.ctx == nil {
+		return
+	}
+	eventsEmit(a.ctx, eventPlay, position)
+}
+
+// ModelProgress emits model download progress to the frontend.
+func (a *App) ModelProgress(progress domain.ModelProgress) {
+	if a.ctx == nil {
+		return
+	}
+	eventsEmit(a.ctx, eventModel, progress)
+}
+
+// LowConfidence offers a retry of a session whose transcript the provider
+// was unsure about.
+func (a *App) LowConfidence(event domain.LowConfidence) {
+	if a.ctx == nil {
+		return
+	}
+	eventsEmit(a.ctx, eventRetry, map[string]string{
+		"sessionId":  event.SessionID,
+		"confidence": strconv.FormatFloat(event.Confidence, 'f', 2, 64),
+		"threshold":  strconv.FormatFloat(event.Threshold, 'f', 2, 64),
+	})
+}
+
+// IssueDraftReady emits a dictated issue awaiting ConfirmIssue or DiscardIssue.
+func (a *App) IssueDraftReady(draft domain.IssueDraft) {
+	if a.ctx == nil {
+		return
+	}
+	eventsEmit(a.ctx, eventIssue, map[string]string{
+		"state":   "draft",
+		"tracker": draft.Tracker,
+		"project": draft.Project,
+		"title":   draft.Title,
+		"body":    draft.Body,
+	})
+}
+
+// IssueCreated emits the key and URL of a confirmed issue.
+func (a *App) IssueCreated(issue domain.Issue) {
+	if a.ctx == nil {
+		return
+	}
+	eventsEmit(a.ctx, eventIssue, map[string]string{
+		"state":   "created",
+		"tracker": issue.Tracker,
+		"key":     issue.Key,
+		"title":   issue.Title,
+		"url":     issue.URL,
+	})
+}
+
+// NetworkChanged emits connectivity changes and provider failover state.
+func (a *App) NetworkChanged(state domain.NetworkState) {
+	if a.ctx == nil {
+		return
+	}
+	eventsEmit(a.ctx, eventNetwork, map[string]any{
+		"online":        state.Online,
+		"metered":       state.Metered,
+		"usingFallback": state.UsingFallback,
+		"message":       networkMessage(state),
+	})
+}
+
+func networkMessage(state domain.NetworkState) string {
+	switch {
+	case state.UsingFallback && !state.Online:
+		return "Offline; transcribing with the fallback provider"
+	case state.UsingFallback:
+		return "Metered connection; transcribing with the fallback provider"
+	case !state.Online:
+		return "Offline; cloud transcription unavailable"
+	case state.Metered:
+		return "Metered connection"
+	default:
+		return "Online"
+	}
+}
+
 func sessionReasonMessage(reason domain.SessionStateReason) string {
 	switch reason {
 	case domain.SessionReasonMicCold:
@@ -200,6 +1100,12 @@ func sessionReasonMessage(reason domain.SessionStateReason) string {
 		return "Transcription failed"
 	case domain.SessionReasonRulesFailed:
 		return "Rules processing failed"
+	case domain.SessionReasonMeetingStarted:
+		return "Meeting recording started"
+	case domain.SessionReasonProviderSwitched:
+		return "Transcription provider switched"
+	case domain.SessionReasonIdleTimeout:
+		return "Recording discarded after no speech was detected"
 	default:
 		return ""
 	}
@@ -219,6 +1125,26 @@ func errorMessage(code domain.ErrorCode, detail string) string {
 		return "Rules processing failed"
 	case domain.ErrorCodeTranscription:
 		return "Transcription error"
+	case domain.ErrorCodeDiskSpace:
+		return "Low disk space; audio saving stopped"
+	case domain.ErrorCodeForm:
+		return "Form filling issue"
+	case domain.ErrorCodeTarget:
+		return "Output target failed"
+	case domain.ErrorCodeCopyHook:
+		return "Post-copy hook failed"
+	case domain.ErrorCodeRecordingHook:
+		return "Recording hook failed"
+	case domain.ErrorCodeTrigger:
+		return "Trigger device failed; retrying"
+	case domain.ErrorCodeReconnecting:
+		return "Connection dropped; reconnecting without losing audio"
+	case domain.ErrorCodeInvalidKey:
+		return "API key rejected; check the provider key and its permissions"
+	case domain.ErrorCodeInsufficientCredits:
+		return "Provider account is out of credits"
+	case domain.ErrorCodeUnsupportedConfig:
+		return "Provider rejected the settings; check the model, language and audio format"
 	default:
 		if detail == "" {
 			return "Unknown error"
@@ -2,10 +2,18 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"coldmic/internal/config"
 	"coldmic/internal/domain"
+	"coldmic/internal/eventschema"
+	"coldmic/internal/usecase"
 )
 
 func TestSessionReasonMessage(t *testing.T) {
@@ -22,6 +30,7 @@ func TestSessionReasonMessage(t *testing.T) {
 		domain.SessionReasonNoTranscript:                   "No transcript captured",
 		domain.SessionReasonTranscriptionFailed:            "Transcription failed",
 		domain.SessionReasonRulesFailed:                    "Rules processing failed",
+		domain.SessionReasonMeetingStarted:                 "Meeting recording started",
 	}
 
 	for reason, want := range cases {
@@ -50,6 +59,11 @@ func TestErrorMessage(t *testing.T) {
 		domain.ErrorCodeClipboard:     "Clipboard write failed",
 		domain.ErrorCodeRules:         "Rules processing failed",
 		domain.ErrorCodeTranscription: "Transcription error",
+		domain.ErrorCodeDiskSpace:     "Low disk space; audio saving stopped",
+		domain.ErrorCodeForm:          "Form filling issue",
+		domain.ErrorCodeTarget:        "Output target failed",
+		domain.ErrorCodeCopyHook:      "Post-copy hook failed",
+		domain.ErrorCodeInvalidKey:    "API key rejected; check the provider key and its permissions",
 	}
 	for code, want := range cases {
 		code := code
@@ -74,13 +88,87 @@ func TestRequireReady(t *testing.T) {
 	t.Parallel()
 
 	app := &App{}
-	if err := app.requireReady(); err == nil {
+	if err := app.current().requireReady(); err == nil {
 		t.Fatalf("expected uninitialized error")
 	}
 
 	bootErr := errors.New("boot")
-	app.bootErr = bootErr
-	if err := app.requireReady(); !errors.Is(err, bootErr) {
+	app.state.Store(&appServices{bootErr: bootErr})
+	if err := app.current().requireReady(); !errors.Is(err, bootErr) {
+		t.Fatalf("expected boot error, got %v", err)
+	}
+}
+
+func TestRestoreHistoryBackupRequiresConfiguration(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(nil, appServices{session: &usecase.SessionService{}})
+	if _, err := app.RestoreHistoryBackup(); err == nil {
+		t.Fatalf("expected unconfigured backup error")
+	}
+}
+
+func TestSetWorkspaceRebuildsScopedServices(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("COLDMIC_WORKSPACE", "")
+	t.Setenv("DEEPGRAM_API_KEY", "shared-key")
+	t.Setenv("DEEPGRAM_API_KEY_CLIENT_A", "client-key")
+	events := captureEvents(t)
+
+	app := &App{ctx: context.Background()}
+	if err := app.SetWorkspace("Bad Name"); err == nil {
+		t.Fatalf("expected invalid workspace name to fail")
+	}
+	// Bindings keep running while the workspace switches.
+	done := make(chan struct{})
+	reading := make(chan struct{})
+	go func() {
+		defer close(reading)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				_ = app.GetStatus()
+				_ = app.GetRuntimeInfo()
+			}
+		}
+	}()
+	err := app.SetWorkspace("Client-A")
+	close(done)
+	<-reading
+	if err != nil {
+		t.Fatalf("set workspace failed: %v", err)
+	}
+	t.Cleanup(app.stopBackground)
+
+	if cfg := app.current().cfg; cfg.Workspace != "client-a" || cfg.Deepgram.APIKey != "client-key" {
+		t.Fatalf("unexpected workspace config: workspace=%q key=%q", cfg.Workspace, cfg.Deepgram.APIKey)
+	}
+	if app.current().session == nil || app.GetRuntimeInfo()["workspace"] != "client-a" {
+		t.Fatalf("expected services for the new workspace")
+	}
+	if len(*events) == 0 || (*events)[len(*events)-1].name != eventSession {
+		t.Fatalf("expected session state event after switching, got %+v", *events)
+	}
+}
+
+func TestGetRuntimeInfoReportsConfiguredProvider(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(nil, appServices{cfg: config.Config{Provider: "groq", Groq: config.GroqConfig{Model: "whisper-large-v3", Language: "de"}}})
+	info := app.GetRuntimeInfo()
+	if info["provider"] != "groq" || info["model"] != "whisper-large-v3" || info["language"] != "de" {
+		t.Fatalf("unexpected runtime info: %+v", info)
+	}
+}
+
+func TestGetCapabilitiesRequiresServices(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(nil, appServices{bootErr: errors.New("boot")})
+	if _, err := app.GetCapabilities(); err == nil || err.Error() != "boot" {
 		t.Fatalf("expected boot error, got %v", err)
 	}
 }
@@ -94,7 +182,7 @@ func TestGetStatusWhenNotInitialized(t *testing.T) {
 		t.Fatalf("unexpected status: %+v", status)
 	}
 
-	app.bootErr = errors.New("boot")
+	app.state.Store(&appServices{bootErr: errors.New("boot")})
 	status = app.GetStatus()
 	if status.State != domain.SessionStateError || status.Active != false || status.Message != "boot" {
 		t.Fatalf("unexpected boot status: %+v", status)
@@ -107,7 +195,7 @@ func TestAppEventEmittersIncludeSessionID(t *testing.T) {
 
 	app.SessionStateChanged(domain.SessionStateIdle, domain.SessionReasonMicCold)
 	app.PartialTranscript("partial")
-	app.FinalTranscript("raw", "final", "session-1")
+	app.FinalTranscript("raw", "final", "session-1", []domain.SessionWarning{{Code: domain.ErrorCodeReconnecting, Message: "reconnected", Count: 2}})
 	app.SessionError(domain.ErrorCodeTranscription, "detail")
 
 	if len(*events) != 4 {
@@ -129,9 +217,86 @@ func TestAppEventEmittersIncludeSessionID(t *testing.T) {
 	if (*events)[2].payload["sessionId"] != "session-1" {
 		t.Fatalf("expected sessionId in final payload, got %+v", (*events)[2].payload)
 	}
+	if !strings.Contains((*events)[2].payload["warnings"], "reconnecting") {
+		t.Fatalf("expected warnings in final payload, got %+v", (*events)[2].payload)
+	}
 	if (*events)[3].name != eventError || (*events)[3].payload["code"] != string(domain.ErrorCodeTranscription) {
 		t.Fatalf("unexpected error event payload: %+v", (*events)[3])
 	}
+	for _, event := range *events {
+		if _, ok := eventschema.Events[event.name]; !ok || event.payload["schemaVersion"] != strconv.Itoa(domain.EventSchemaVersion) {
+			t.Fatalf("expected %s to be a versioned schema event, got %+v", event.name, event.payload)
+		}
+	}
+}
+
+func TestAppSimulateEvent(t *testing.T) {
+	app := newTestApp(context.Background(), appServices{session: &usecase.SessionService{}})
+	events := captureEvents(t)
+
+	if err := app.SimulateEvent("partial", map[string]any{"text": "hi"}); err == nil {
+		t.Fatalf("expected simulated events to need the dev flag")
+	}
+	app.state.Store(&appServices{session: &usecase.SessionService{}, cfg: config.Config{SimulateEvents: true}})
+
+	if err := app.SimulateEvent("session", map[string]any{"state": "idle", "reason": "transcript_clipboard_failed"}); err != nil {
+		t.Fatalf("simulate session failed: %v", err)
+	}
+	if err := app.SimulateEvent("error", map[string]any{"code": "rules", "detail": "boom"}); err != nil {
+		t.Fatalf("simulate error failed: %v", err)
+	}
+	if err := app.SimulateEvent("final", map[string]any{"raw": "raw", "transformed": "final", "sessionId": "session-9"}); err != nil {
+		t.Fatalf("simulate final failed: %v", err)
+	}
+	if len(*events) != 3 {
+		t.Fatalf("expected 3 emitted events, got %+v", *events)
+	}
+	if (*events)[0].name != eventSession || (*events)[0].payload["message"] != "Transcript ready (clipboard write failed)" {
+		t.Fatalf("unexpected session event: %+v", (*events)[0])
+	}
+	if (*events)[1].name != eventError || (*events)[1].payload["code"] != "rules" || (*events)[1].payload["detail"] != "boom" {
+		t.Fatalf("unexpected error event: %+v", (*events)[1])
+	}
+	if (*events)[2].name != eventFinal || (*events)[2].payload["sessionId"] != "session-9" {
+		t.Fatalf("unexpected final event: %+v", (*events)[2])
+	}
+
+	if err := app.SimulateEvent("session", map[string]any{"reason": "mic_cold"}); err == nil {
+		t.Fatalf("expected a session event without a state to fail")
+	}
+	if err := app.SimulateEvent("copied", nil); err == nil {
+		t.Fatalf("expected an unknown kind to fail")
+	}
+}
+
+func TestAppFileJobChangedEmitsEvent(t *testing.T) {
+	app := &App{ctx: context.Background()}
+	events := captureEvents(t)
+
+	app.FileJobChanged(domain.FileJob{ID: "file-1", SourcePath: "/in/a.wav", OutputPath: "/out/a.txt", State: domain.FileJobStateFailed, Error: "boom"})
+
+	if len(*events) != 1 || (*events)[0].name != eventFileJob {
+		t.Fatalf("expected one file job event, got %+v", *events)
+	}
+	payload := (*events)[0].payload
+	if payload["id"] != "file-1" || payload["state"] != string(domain.FileJobStateFailed) || payload["error"] != "boom" {
+		t.Fatalf("unexpected file job payload: %+v", payload)
+	}
+}
+
+func TestAppCaptionEmitsEvent(t *testing.T) {
+	app := &App{ctx: context.Background()}
+	events := captureEvents(t)
+
+	app.Caption(domain.Caption{Kind: domain.TranscriptKindFinal, SourceText: "hallo", Text: "hello", Language: "en", SessionID: "session-3"})
+
+	if len(*events) != 1 || (*events)[0].name != eventCaption {
+		t.Fatalf("expected one caption event, got %+v", *events)
+	}
+	payload := (*events)[0].payload
+	if payload["text"] != "hello" || payload["sourceText"] != "hallo" || payload["sessionId"] != "session-3" {
+		t.Fatalf("unexpected caption payload: %+v", payload)
+	}
 }
 
 func TestAppEventEmittersNoopWithoutContext(t *testing.T) {
@@ -140,7 +305,7 @@ func TestAppEventEmittersNoopWithoutContext(t *testing.T) {
 
 	app.SessionStateChanged(domain.SessionStateIdle, domain.SessionReasonMicCold)
 	app.PartialTranscript("partial")
-	app.FinalTranscript("raw", "final", "session-2")
+	app.FinalTranscript("raw", "final", "session-2", nil)
 	app.SessionError(domain.ErrorCodeTranscription, "detail")
 
 	if len(*events) != 0 {
@@ -170,6 +335,56 @@ func TestBeforeCloseMinimisesWindowAndPreventsQuit(t *testing.T) {
 	}
 }
 
+func TestAppIssueEvents(t *testing.T) {
+	app := &App{ctx: context.Background()}
+	events := captureEvents(t)
+
+	app.IssueDraftReady(domain.IssueDraft{Tracker: "jira", Project: "OPS", Title: "Rotate certs"})
+	app.IssueCreated(domain.Issue{Tracker: "jira", Key: "OPS-12", URL: "https://jira.example.com/browse/OPS-12"})
+
+	if len(*events) != 2 || (*events)[0].name != eventIssue || (*events)[1].name != eventIssue {
+		t.Fatalf("expected two issue events, got %+v", *events)
+	}
+	if (*events)[0].payload["state"] != "draft" || (*events)[0].payload["title"] != "Rotate certs" {
+		t.Fatalf("unexpected draft payload: %+v", (*events)[0].payload)
+	}
+	if (*events)[1].payload["state"] != "created" || (*events)[1].payload["url"] != "https://jira.example.com/browse/OPS-12" {
+		t.Fatalf("unexpected created payload: %+v", (*events)[1].payload)
+	}
+
+	app.state.Store(&appServices{session: &usecase.SessionService{}})
+	if _, err := app.ConfirmIssue(); err == nil {
+		t.Fatalf("expected confirmation without an issue target to fail")
+	}
+}
+
+func TestAppTranscriptCopiedEvent(t *testing.T) {
+	app := &App{ctx: context.Background()}
+	events := captureEvents(t)
+
+	copiedAt := time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC)
+	if err := app.TranscriptCopied(context.Background(), domain.CopyEvent{SessionID: "session-4", Text: "Fix typo", Target: "git-commit", CopiedAt: copiedAt}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*events) != 1 || (*events)[0].name != eventCopied {
+		t.Fatalf("expected one copied event, got %+v", *events)
+	}
+	payload := (*events)[0].payload
+	if payload["sessionId"] != "session-4" || payload["text"] != "Fix typo" || payload["target"] != "git-commit" || payload["copiedAt"] != "2026-10-15T09:30:00Z" {
+		t.Fatalf("unexpected copied payload: %+v", payload)
+	}
+}
+
+func TestAppConfidenceSpansEmitsEvent(t *testing.T) {
+	app := &App{ctx: context.Background()}
+	events := captureEvents(t)
+
+	app.ConfidenceSpans(domain.ConfidenceTranscript{SessionID: "session-5", Spans: []domain.TranscriptSpan{{Text: "hi", Confidence: 0.4, Bucket: domain.ConfidenceLow}}})
+	if len(*events) != 1 || (*events)[0].name != eventSpans {
+		t.Fatalf("expected one spans event, got %+v", *events)
+	}
+}
+
 type emittedEvent struct {
 	name    string
 	payload map[string]string
@@ -183,10 +398,18 @@ func captureEvents(t *testing.T) *[]emittedEvent {
 	eventsEmit = func(_ context.Context, eventName string, optionalData ...interface{}) {
 		payload := map[string]string{}
 		if len(optionalData) > 0 {
-			if data, ok := optionalData[0].(map[string]string); ok {
+			switch data := optionalData[0].(type) {
+			case map[string]string:
 				for key, value := range data {
 					payload[key] = value
 				}
+			case domain.SessionEvent, domain.PartialEvent, domain.FinalEvent, domain.ErrorEvent:
+				encoded, _ := json.Marshal(data)
+				fields := map[string]any{}
+				_ = json.Unmarshal(encoded, &fields)
+				for key, value := range fields {
+					payload[key] = fmt.Sprint(value)
+				}
 			}
 		}
 		events = append(events, emittedEvent{name: eventName, payload: payload})
@@ -197,3 +420,10 @@ func captureEvents(t *testing.T) *[]emittedEvent {
 	})
 	return &events
 }
+
+// newTestApp returns an app with services installed, as startup would.
+func newTestApp(ctx context.Context, services appServices) *App {
+	app := &App{ctx: ctx}
+	app.state.Store(&services)
+	return app
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	coldcli "coldmic/internal/cli"
+	"coldmic/internal/daemon"
 	"coldmic/internal/domain"
 )
 
@@ -28,6 +29,10 @@ type SessionClient interface {
 	Abort(ctx context.Context) (domain.Status, error)
 	Status(ctx context.Context) (domain.Status, error)
 	Transcript(ctx context.Context) (time.Time, domain.StopResult, error)
+	Targets(ctx context.Context) ([]domain.OutputTargetInfo, error)
+	SelectTarget(ctx context.Context, name string) ([]domain.OutputTargetInfo, error)
+	Hotkeys(ctx context.Context) ([]domain.Hotkey, error)
+	TriggerHotkey(ctx context.Context, name string) (domain.HotkeyResult, error)
 }
 
 type sessionClientFactory func(daemonURL string) SessionClient
@@ -40,7 +45,7 @@ type configProvider interface {
 type envConfigProvider struct{}
 
 func (envConfigProvider) DaemonURL() string {
-	return envOrDefault("COLDMIC_DAEMON_URL", "http://127.0.0.1:4317")
+	return envOrDefault("COLDMIC_DAEMON_URL", "unix://"+daemon.DefaultSocketPath())
 }
 
 func (envConfigProvider) ToggleCompatEnabled() bool {
@@ -66,7 +71,9 @@ type CommandRunner struct {
 func NewCommandRunner(factory sessionClientFactory, cfg configProvider, stdout io.Writer, stderr io.Writer) *CommandRunner {
 	if factory == nil {
 		factory = func(daemonURL string) SessionClient {
-			return coldcli.NewClient(daemonURL)
+			client := coldcli.NewClient(daemonURL)
+			client.SetToken(os.Getenv("COLDMIC_DAEMON_TOKEN"))
+			return client
 		}
 	}
 	if cfg == nil {
@@ -96,6 +103,8 @@ func (r *CommandRunner) registerCommands() {
 	r.register("abort", "Abort recording and discard captured audio", r.runAbort)
 	r.register("status", "Show current recording state", r.runStatus)
 	r.register("transcript", "Show latest final transcript", r.runTranscript)
+	r.register("target", "List output targets, or switch to the named one", r.runTarget)
+	r.register("hotkey", "List hotkeys, or trigger the named one", r.runHotkey)
 	r.register("help", "Show this help text", r.runHelp)
 	r.commands["-h"] = r.commands["help"]
 	r.commands["--help"] = r.commands["help"]
@@ -266,9 +275,72 @@ func (r *CommandRunner) runTranscript(args []string) (int, error) {
 	return exitOK, nil
 }
 
+func (r *CommandRunner) runTarget(args []string) (int, error) {
+	cfg, err := r.parseCommonFlags("target", args)
+	if err != nil {
+		return exitGeneric, err
+	}
+
+	client := r.clientFactory(cfg.daemonURL)
+	var targets []domain.OutputTargetInfo
+	switch rest := cfg.args; len(rest) {
+	case 0:
+		targets, err = client.Targets(context.Background())
+	case 1:
+		targets, err = client.SelectTarget(context.Background(), rest[0])
+	default:
+		return exitGeneric, fmt.Errorf("target takes at most one name")
+	}
+	if err != nil {
+		return mapErrorToExitCode(err), err
+	}
+
+	if cfg.outputJSON {
+		writeJSON(r.stdout, cliTargetsOutput{Targets: targets})
+	} else {
+		printTargets(r.stdout, targets)
+	}
+	return exitOK, nil
+}
+
+func (r *CommandRunner) runHotkey(args []string) (int, error) {
+	cfg, err := r.parseCommonFlags("hotkey", args)
+	if err != nil {
+		return exitGeneric, err
+	}
+
+	client := r.clientFactory(cfg.daemonURL)
+	switch rest := cfg.args; len(rest) {
+	case 0:
+		hotkeys, err := client.Hotkeys(context.Background())
+		if err != nil {
+			return mapErrorToExitCode(err), err
+		}
+		if cfg.outputJSON {
+			writeJSON(r.stdout, cliHotkeysOutput{Hotkeys: hotkeys})
+		} else {
+			printHotkeys(r.stdout, hotkeys)
+		}
+	case 1:
+		result, err := client.TriggerHotkey(context.Background(), rest[0])
+		if err != nil {
+			return mapErrorToExitCode(err), err
+		}
+		if cfg.outputJSON {
+			writeJSON(r.stdout, result)
+		} else {
+			printHotkeyResult(r.stdout, result)
+		}
+	default:
+		return exitGeneric, fmt.Errorf("hotkey takes at most one name")
+	}
+	return exitOK, nil
+}
+
 type commonFlags struct {
 	daemonURL  string
 	outputJSON bool
+	args       []string
 }
 
 type statusFlags struct {
@@ -290,6 +362,7 @@ func (r *CommandRunner) parseCommonFlags(name string, args []string) (*commonFla
 	if err := fs.Parse(args); err != nil {
 		return nil, err
 	}
+	cfg.args = fs.Args()
 	return cfg, nil
 }
 
@@ -335,7 +408,7 @@ func (r *CommandRunner) printUsage() {
 	}
 	fmt.Fprintln(r.stdout, "")
 	fmt.Fprintln(r.stdout, "Global flags per command:")
-	fmt.Fprintln(r.stdout, "  --daemon-url URL  Daemon URL (default: COLDMIC_DAEMON_URL or http://127.0.0.1:4317)")
+	fmt.Fprintln(r.stdout, "  --daemon-url URL  Daemon URL (default: COLDMIC_DAEMON_URL or the per-user daemon socket)")
 	fmt.Fprintln(r.stdout, "  --json            Emit JSON output")
 	fmt.Fprintln(r.stdout, "")
 	fmt.Fprintln(r.stdout, "Status flags:")
@@ -355,6 +428,14 @@ type cliStopOutput struct {
 	Result domain.StopResult `json:"result"`
 }
 
+type cliTargetsOutput struct {
+	Targets []domain.OutputTargetInfo `json:"targets"`
+}
+
+type cliHotkeysOutput struct {
+	Hotkeys []domain.Hotkey `json:"hotkeys"`
+}
+
 type cliTranscriptOutput struct {
 	CapturedAt time.Time         `json:"capturedAt"`
 	Result     domain.StopResult `json:"result"`
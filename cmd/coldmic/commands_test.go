@@ -89,6 +89,55 @@ func TestRunStartAndStatusAndStopAndTranscript(t *testing.T) {
 	}
 }
 
+func TestRunTargetListsAndSelects(t *testing.T) {
+	client := &fakeSessionClient{targets: []domain.OutputTargetInfo{
+		{Name: "clipboard", Type: "clipboard", BuiltIn: true, Active: true},
+		{Name: "work-jira", Type: "jira-issue", Description: "Work board"},
+	}}
+	var out bytes.Buffer
+	runner := NewCommandRunner(func(string) SessionClient { return client }, fakeConfig{}, &out, io.Discard)
+
+	if code, err := runner.Run("target", []string{"work-jira"}); err != nil || code != exitOK {
+		t.Fatalf("unexpected result: code=%d err=%v", code, err)
+	}
+	if client.selected != "work-jira" {
+		t.Fatalf("expected work-jira to be selected, got %q", client.selected)
+	}
+	want := "  clipboard type=clipboard\n* work-jira type=jira-issue Work board\n"
+	if out.String() != want {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+
+	if code, err := runner.Run("target", []string{"a", "b"}); err == nil || code != exitGeneric {
+		t.Fatalf("expected more than one name to be refused, got code=%d err=%v", code, err)
+	}
+}
+
+func TestRunHotkeyListsAndTriggers(t *testing.T) {
+	client := &fakeSessionClient{hotkeys: []domain.Hotkey{
+		{Name: "abort", Action: domain.HotkeyAbort},
+		{Name: "email", Keys: "Super+E", Action: domain.HotkeyStart, Target: "email"},
+	}}
+	var out bytes.Buffer
+	runner := NewCommandRunner(func(string) SessionClient { return client }, fakeConfig{}, &out, io.Discard)
+
+	if code, err := runner.Run("hotkey", nil); err != nil || code != exitOK {
+		t.Fatalf("unexpected result: code=%d err=%v", code, err)
+	}
+	want := "abort action=abort\nemail action=start target=email keys=Super+E\n"
+	if out.String() != want {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if code, err := runner.Run("hotkey", []string{"email"}); err != nil || code != exitOK {
+		t.Fatalf("unexpected result: code=%d err=%v", code, err)
+	}
+	if client.triggered != "email" || out.String() != "state=recording active=true\n" {
+		t.Fatalf("expected email to be triggered, got %q output %q", client.triggered, out.String())
+	}
+}
+
 func TestParseCommonFlagsError(t *testing.T) {
 
 	_, err := parseCommonFlags("status", []string{"--json=maybe"})
@@ -510,6 +559,33 @@ type fakeSessionClient struct {
 	abortErr      error
 	statusErr     error
 	transcriptErr error
+
+	targets  []domain.OutputTargetInfo
+	selected string
+
+	hotkeys   []domain.Hotkey
+	triggered string
+}
+
+func (f *fakeSessionClient) Hotkeys(context.Context) ([]domain.Hotkey, error) {
+	return f.hotkeys, nil
+}
+
+func (f *fakeSessionClient) TriggerHotkey(_ context.Context, name string) (domain.HotkeyResult, error) {
+	f.triggered = name
+	return domain.HotkeyResult{Hotkey: name, Action: domain.HotkeyStart, Status: domain.Status{State: domain.SessionStateRecording, Active: true}}, nil
+}
+
+func (f *fakeSessionClient) Targets(context.Context) ([]domain.OutputTargetInfo, error) {
+	return f.targets, nil
+}
+
+func (f *fakeSessionClient) SelectTarget(_ context.Context, name string) ([]domain.OutputTargetInfo, error) {
+	f.selected = name
+	for i := range f.targets {
+		f.targets[i].Active = f.targets[i].Name == name
+	}
+	return f.targets, nil
 }
 
 func (f *fakeSessionClient) Start(context.Context) (domain.Status, error) {
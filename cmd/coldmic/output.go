@@ -18,10 +18,53 @@ func printStatus(w io.Writer, status domain.Status) {
 
 func printStopResult(w io.Writer, status domain.Status, result domain.StopResult) {
 	fmt.Fprintf(w, "state=%s active=%t copied=%t\n", status.State, status.Active, result.Copied)
+	printWarnings(w, result.Warnings)
 	fmt.Fprintln(w, result.FinalTranscript)
 }
 
+func printWarnings(w io.Writer, warnings []domain.SessionWarning) {
+	for _, warning := range warnings {
+		fmt.Fprintf(w, "warning code=%s count=%d message=%q\n", warning.Code, warning.Count, warning.Message)
+	}
+}
+
 func printTranscript(w io.Writer, capturedAt time.Time, result domain.StopResult) {
 	fmt.Fprintf(w, "captured_at=%s copied=%t\n", printTranscriptTime(capturedAt), result.Copied)
+	printWarnings(w, result.Warnings)
 	fmt.Fprintln(w, result.FinalTranscript)
 }
+
+func printTargets(w io.Writer, targets []domain.OutputTargetInfo) {
+	for _, target := range targets {
+		marker := " "
+		if target.Active {
+			marker = "*"
+		}
+		line := fmt.Sprintf("%s %s type=%s", marker, target.Name, target.Type)
+		if target.Description != "" {
+			line += " " + target.Description
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+func printHotkeys(w io.Writer, hotkeys []domain.Hotkey) {
+	for _, hotkey := range hotkeys {
+		line := fmt.Sprintf("%s action=%s", hotkey.Name, hotkey.Action)
+		if hotkey.Target != "" {
+			line += " target=" + hotkey.Target
+		}
+		if hotkey.Keys != "" {
+			line += " keys=" + hotkey.Keys
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+func printHotkeyResult(w io.Writer, result domain.HotkeyResult) {
+	if result.Result == nil {
+		printStatus(w, result.Status)
+		return
+	}
+	printStopResult(w, result.Status, *result.Result)
+}
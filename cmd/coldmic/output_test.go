@@ -39,6 +39,21 @@ func TestPrintStopResult(t *testing.T) {
 	}
 }
 
+func TestPrintStopResultWarnings(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	result := domain.StopResult{
+		FinalTranscript: "final",
+		Warnings:        []domain.SessionWarning{{Code: domain.ErrorCodeReconnecting, Message: "reconnected", Count: 2}},
+	}
+	printStopResult(&buf, domain.Status{State: domain.SessionStateIdle}, result)
+	want := "state=idle active=false copied=false\nwarning code=reconnecting count=2 message=\"reconnected\"\nfinal\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
+
 func TestPrintTranscript(t *testing.T) {
 	t.Parallel()
 
@@ -19,8 +19,8 @@ import (
 )
 
 func main() {
-	addrDefault := envOrDefault("COLDMIC_DAEMON_ADDR", "127.0.0.1:4317")
-	addr := flag.String("addr", addrDefault, "daemon bind address")
+	addrDefault := envOrDefault("COLDMIC_DAEMON_ADDR", "unix:"+daemon.DefaultSocketPath())
+	addr := flag.String("addr", addrDefault, "daemon bind address (host:port on loopback, or unix:/path/to.sock)")
 	flag.Parse()
 
 	var eventSink ports.EventSink = daemon.NoopEventSink{}
@@ -32,6 +32,9 @@ func main() {
 	if err != nil {
 		log.Fatalf("coldmicd bootstrap failed: %v", err)
 	}
+	if err := bootstrap.Preflight(context.Background(), services); err != nil {
+		log.Fatalf("coldmicd provider check failed: %v", err)
+	}
 	debuglog.Printf(
 		"config provider=deepgram model=%s language=%q smart_format=%t audio_format=%s audio_device=%s sample_rate=%d channels=%d rules_file=%q chunk_size=%d streaming_grace_ms=%d api_key_set=%t",
 		services.Config.Deepgram.Model,
@@ -47,9 +50,17 @@ func main() {
 		services.Config.Deepgram.APIKey != "",
 	)
 
+	listener, err := daemon.Listen(*addr)
+	if err != nil {
+		log.Fatalf("coldmicd listen failed: %v", err)
+	}
+
 	api := daemon.NewAPI(services.Session)
+	api.SetTokens(services.Tokens)
+	api.SetHistory(services.History)
+	api.SetTargets(services.Targets)
+	api.SetHotkeys(services.Hotkeys)
 	srv := &http.Server{
-		Addr:              *addr,
 		Handler:           api.Handler(),
 		ReadHeaderTimeout: 5 * time.Second,
 	}
@@ -57,10 +68,65 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	go func() {
+		if err := services.Batch.Run(ctx); err != nil {
+			log.Printf("batch pool stopped: %v", err)
+		}
+	}()
+
+	if services.Watcher != nil {
+		go func() {
+			if err := services.Watcher.Run(ctx); err != nil {
+				log.Printf("folder watcher stopped: %v", err)
+			}
+		}()
+	}
+
+	if services.Network != nil {
+		go func() {
+			if err := services.Network.Run(ctx); err != nil {
+				log.Printf("network monitor stopped: %v", err)
+			}
+		}()
+	}
+
+	if services.Backup != nil {
+		go func() {
+			if err := services.Backup.Run(ctx); err != nil {
+				log.Printf("history backup stopped: %v", err)
+			}
+		}()
+	}
+
+	go func() {
+		if err := services.Trigger.Run(ctx); err != nil {
+			log.Printf("trigger stopped: %v", err)
+		}
+	}()
+
+	if services.MIDI != nil {
+		go func() {
+			if err := services.MIDI.Run(ctx); err != nil {
+				log.Printf("MIDI trigger stopped: %v", err)
+			}
+		}()
+	}
+
+	if services.Gamepad != nil {
+		go func() {
+			if err := services.Gamepad.Run(ctx); err != nil {
+				log.Printf("gamepad trigger stopped: %v", err)
+			}
+		}()
+	}
+
 	errCh := make(chan error, 1)
 	go func() {
 		log.Printf("coldmicd listening on %s", *addr)
-		if serveErr := srv.ListenAndServe(); serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
+		if services.Config.DryRun {
+			log.Printf("coldmicd dry run: transcripts are replayed, no provider is called")
+		}
+		if serveErr := srv.Serve(listener); serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
 			errCh <- serveErr
 			return
 		}
@@ -0,0 +1,64 @@
+// Package a11y delivers status announcements to screen-reader users.
+package a11y
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// SpeechDispatcher speaks announcements with spd-say, the speech-dispatcher
+// client Orca itself uses, so they share the user's voice and rate settings.
+// Urgent messages cancel any message still being spoken.
+type SpeechDispatcher struct {
+	command string
+}
+
+func NewSpeechDispatcher(command string) *SpeechDispatcher {
+	if command == "" {
+		command = "spd-say"
+	}
+	return &SpeechDispatcher{command: command}
+}
+
+func (s *SpeechDispatcher) Announce(ctx context.Context, message string, urgent bool) error {
+	args := []string{"--application-name", "coldmic", "--wait"}
+	if urgent {
+		args = append(args, "--cancel", "--priority", "important")
+	}
+	// "--" keeps dictated text starting with "-" from being read as a flag.
+	return run(ctx, s.command, append(args, "--", message)...)
+}
+
+// Notifier posts announcements as desktop notifications, which Orca and
+// other AT-SPI screen readers read out as they appear.
+type Notifier struct {
+	command string
+}
+
+func NewNotifier(command string) *Notifier {
+	if command == "" {
+		command = "notify-send"
+	}
+	return &Notifier{command: command}
+}
+
+func (n *Notifier) Announce(ctx context.Context, message string, urgent bool) error {
+	urgency := "low"
+	if urgent {
+		urgency = "critical"
+	}
+	return run(ctx, n.command, "--app-name", "coldmic", "--urgency", urgency, "--expire-time", "4000", "--", "coldmic", message)
+}
+
+func run(ctx context.Context, command string, args ...string) error {
+	cmd := exec.CommandContext(ctx, command, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", command, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
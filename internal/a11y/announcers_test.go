@@ -0,0 +1,49 @@
+package a11y
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func recordArgs(t *testing.T) (string, string) {
+	t.Helper()
+	out := filepath.Join(t.TempDir(), "args.txt")
+	script := filepath.Join(t.TempDir(), "announce")
+	if err := os.WriteFile(script, []byte("#!/usr/bin/env bash\nprintf '%s\\n' \"$@\" > "+out+"\n"), 0o700); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+	return script, out
+}
+
+func TestSpeechDispatcherArgs(t *testing.T) {
+	t.Parallel()
+
+	script, out := recordArgs(t)
+	if err := NewSpeechDispatcher(script).Announce(context.Background(), "-5 degrees", true); err != nil {
+		t.Fatalf("announce failed: %v", err)
+	}
+	data, _ := os.ReadFile(out)
+	want := "--application-name\ncoldmic\n--wait\n--cancel\n--priority\nimportant\n--\n-5 degrees\n"
+	if string(data) != want {
+		t.Fatalf("unexpected spd-say args:\n%s", data)
+	}
+}
+
+func TestNotifierArgs(t *testing.T) {
+	t.Parallel()
+
+	script, out := recordArgs(t)
+	if err := NewNotifier(script).Announce(context.Background(), "Copied", false); err != nil {
+		t.Fatalf("announce failed: %v", err)
+	}
+	data, _ := os.ReadFile(out)
+	want := "--app-name\ncoldmic\n--urgency\nlow\n--expire-time\n4000\n--\ncoldmic\nCopied\n"
+	if string(data) != want {
+		t.Fatalf("unexpected notify-send args:\n%s", data)
+	}
+	if err := NewNotifier(filepath.Join(t.TempDir(), "missing")).Announce(context.Background(), "x", false); err == nil {
+		t.Fatalf("expected missing command error")
+	}
+}
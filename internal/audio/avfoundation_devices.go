@@ -0,0 +1,126 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"coldmic/internal/ports"
+)
+
+// AVFoundationInputFormat captures through ffmpeg's AVFoundation input, the
+// capture path on macOS.
+const AVFoundationInputFormat = "avfoundation"
+
+// AVFoundationDevices implements ports.AudioDeviceLister by asking ffmpeg
+// for the AVFoundation audio devices, which ffmpeg addresses by index.
+type AVFoundationDevices struct {
+	run     func(ctx context.Context, args ...string) (string, error)
+	timeout time.Duration
+}
+
+func NewAVFoundationDevices(command string) *AVFoundationDevices {
+	if command == "" {
+		command = "ffmpeg"
+	}
+	return &AVFoundationDevices{
+		// As with DirectShow, ffmpeg prints the listing to stderr and then
+		// fails to open the empty input.
+		run: func(ctx context.Context, args ...string) (string, error) {
+			output, err := exec.CommandContext(ctx, command, args...).CombinedOutput()
+			if err != nil && len(output) == 0 {
+				return "", fmt.Errorf("%s %s failed: %w", command, strings.Join(args, " "), err)
+			}
+			return string(output), nil
+		},
+		timeout: 5 * time.Second,
+	}
+}
+
+// avfoundationDevicePattern matches a device line such as
+// `[AVFoundation indev @ 0x7f8] [1] MacBook Pro Microphone`.
+var avfoundationDevicePattern = regexp.MustCompile(`\]\s+\[(\d+)\]\s+(.+)$`)
+
+// InputDevices lists the AVFoundation audio devices by name, in index order.
+func (d *AVFoundationDevices) InputDevices(ctx context.Context, inputFormat string) ([]string, error) {
+	if inputFormat != AVFoundationInputFormat {
+		return nil, fmt.Errorf("devices are only listed for avfoundation input, not %q", inputFormat)
+	}
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	output, err := d.run(ctx, "-hide_banner", "-f", "avfoundation", "-list_devices", "true", "-i", "")
+	if err != nil {
+		return nil, err
+	}
+	var devices []string
+	audioSection := false
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case strings.Contains(line, "AVFoundation audio devices"):
+			audioSection = true
+			continue
+		case strings.Contains(line, "AVFoundation video devices"):
+			audioSection = false
+			continue
+		}
+		if match := avfoundationDevicePattern.FindStringSubmatch(line); match != nil && audioSection {
+			devices = append(devices, strings.TrimSpace(match[2]))
+		}
+	}
+	return devices, nil
+}
+
+// DeviceFormat is not known for AVFoundation, whose input takes no capture
+// format options; ffmpeg converts from whatever the device delivers.
+func (d *AVFoundationDevices) DeviceFormat(context.Context, string, string) (ports.AudioDeviceFormat, error) {
+	return ports.AudioDeviceFormat{}, fmt.Errorf("device formats are not known for avfoundation input")
+}
+
+// avfoundationInput returns the ffmpeg input for an audio-only AVFoundation
+// capture: ":default" for the system input, ":<index>" for an index, and
+// otherwise the index of the first listed device whose name contains device,
+// ignoring case.
+func avfoundationInput(ctx context.Context, devices ports.AudioDeviceEnumerator, device string) (string, error) {
+	device = strings.TrimPrefix(device, ":")
+	if device == "" || device == "default" {
+		return ":default", nil
+	}
+	if _, err := strconv.Atoi(device); err == nil {
+		return ":" + device, nil
+	}
+	lister, ok := devices.(ports.AudioDeviceLister)
+	if !ok {
+		return ":" + device, nil
+	}
+	names, err := lister.InputDevices(ctx, AVFoundationInputFormat)
+	if err != nil {
+		return "", fmt.Errorf("failed to list capture devices: %w", err)
+	}
+	for index, name := range names {
+		if strings.Contains(strings.ToLower(name), strings.ToLower(device)) {
+			return ":" + strconv.Itoa(index), nil
+		}
+	}
+	return "", fmt.Errorf("capture device %q not found (available: %s)", device, strings.Join(names, ", "))
+}
+
+// microphoneAccess asks macOS for microphone access before capture starts,
+// waiting for the user to answer the first-run prompt or for ctx to end.
+func microphoneAccess(ctx context.Context) error {
+	result := make(chan error, 1)
+	go func() {
+		result <- requestMicrophoneAccess()
+	}()
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,50 @@
+package audio
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const avfoundationListing = `[AVFoundation indev @ 0x7f8e] AVFoundation video devices:
+[AVFoundation indev @ 0x7f8e] [0] FaceTime HD Camera
+[AVFoundation indev @ 0x7f8e] [1] Capture screen 0
+[AVFoundation indev @ 0x7f8e] AVFoundation audio devices:
+[AVFoundation indev @ 0x7f8e] [0] MacBook Pro Microphone
+[AVFoundation indev @ 0x7f8e] [1] Shure MV7
+: Input/output error
+`
+
+func TestAVFoundationDevicesListsAudioDevices(t *testing.T) {
+	t.Parallel()
+
+	devices := NewAVFoundationDevices("")
+	devices.run = func(context.Context, ...string) (string, error) { return avfoundationListing, nil }
+	got, err := devices.InputDevices(context.Background(), AVFoundationInputFormat)
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if want := []string{"MacBook Pro Microphone", "Shure MV7"}; !slices.Equal(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if _, err := devices.InputDevices(context.Background(), "pulse"); err == nil {
+		t.Fatalf("expected non-avfoundation input to fail")
+	}
+}
+
+func TestAVFoundationInputResolvesDevices(t *testing.T) {
+	t.Parallel()
+
+	devices := NewAVFoundationDevices("")
+	devices.run = func(context.Context, ...string) (string, error) { return avfoundationListing, nil }
+	for device, want := range map[string]string{"default": ":default", "": ":default", "2": ":2", ":1": ":1", "shure": ":1", "MacBook": ":0"} {
+		got, err := avfoundationInput(context.Background(), devices, device)
+		if err != nil || got != want {
+			t.Fatalf("device %q: expected %q, got %q %v", device, want, got, err)
+		}
+	}
+	if _, err := avfoundationInput(context.Background(), devices, "Blue Yeti"); err == nil || !strings.Contains(err.Error(), "Shure MV7") {
+		t.Fatalf("expected an unknown device error listing the devices, got %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"coldmic/internal/ports"
+)
+
+// DShowInputFormat captures through ffmpeg's DirectShow input, the capture
+// path on Windows.
+const DShowInputFormat = "dshow"
+
+// DShowDevices implements ports.AudioDeviceEnumerator and
+// ports.AudioDeviceLister by asking ffmpeg about DirectShow devices. Windows
+// has no device named "default", so capture resolves it to the first audio
+// device listed.
+type DShowDevices struct {
+	run     func(ctx context.Context, args ...string) (string, error)
+	timeout time.Duration
+}
+
+func NewDShowDevices(command string) *DShowDevices {
+	if command == "" {
+		command = "ffmpeg"
+	}
+	return &DShowDevices{
+		// ffmpeg prints device listings to stderr and then fails to open the
+		// dummy input, so the output is kept whatever the exit status.
+		run: func(ctx context.Context, args ...string) (string, error) {
+			output, err := exec.CommandContext(ctx, command, args...).CombinedOutput()
+			if err != nil && len(output) == 0 {
+				return "", fmt.Errorf("%s %s failed: %w", command, strings.Join(args, " "), err)
+			}
+			return string(output), nil
+		},
+		timeout: 5 * time.Second,
+	}
+}
+
+var (
+	// dshowDevicePattern matches a device line such as
+	// `[dshow @ 0000] "Microphone (Realtek Audio)" (audio)`; older ffmpeg
+	// builds omit the type and list audio devices under a heading instead.
+	dshowDevicePattern = regexp.MustCompile(`\]\s+"([^"]+)"\s*(?:\((\w+)\))?`)
+	// dshowOptionPattern matches a format line such as
+	// `ch= 2, bits=16, rate= 44100`.
+	dshowOptionPattern = regexp.MustCompile(`ch=\s*(\d+),\s*bits=\s*\d+,\s*rate=\s*(\d+)`)
+)
+
+// InputDevices lists the DirectShow audio capture devices by name.
+func (d *DShowDevices) InputDevices(ctx context.Context, inputFormat string) ([]string, error) {
+	if inputFormat != DShowInputFormat {
+		return nil, fmt.Errorf("devices are only listed for dshow input, not %q", inputFormat)
+	}
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	output, err := d.run(ctx, "-hide_banner", "-list_devices", "true", "-f", "dshow", "-i", "dummy")
+	if err != nil {
+		return nil, err
+	}
+	var devices []string
+	audioSection := false
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.Contains(line, "DirectShow audio devices"):
+			audioSection = true
+			continue
+		case strings.Contains(line, "DirectShow video devices"):
+			audioSection = false
+			continue
+		}
+		match := dshowDevicePattern.FindStringSubmatch(line)
+		if match == nil || strings.Contains(line, "Alternative name") {
+			continue
+		}
+		if match[2] == "audio" || (match[2] == "" && audioSection) {
+			devices = append(devices, match[1])
+		}
+	}
+	return devices, nil
+}
+
+// DeviceFormat returns the first format the device's capture pin offers,
+// which DirectShow treats as its preferred one.
+func (d *DShowDevices) DeviceFormat(ctx context.Context, inputFormat string, device string) (ports.AudioDeviceFormat, error) {
+	if inputFormat != DShowInputFormat {
+		return ports.AudioDeviceFormat{}, fmt.Errorf("device formats are only known for dshow input here, not %q", inputFormat)
+	}
+	device, err := d.resolve(ctx, device)
+	if err != nil {
+		return ports.AudioDeviceFormat{}, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	output, err := d.run(ctx, "-hide_banner", "-list_options", "true", "-f", "dshow", "-i", "audio="+device)
+	if err != nil {
+		return ports.AudioDeviceFormat{}, err
+	}
+	match := dshowOptionPattern.FindStringSubmatch(output)
+	if match == nil {
+		return ports.AudioDeviceFormat{}, fmt.Errorf("no capture formats reported for %s", device)
+	}
+	channels, _ := strconv.Atoi(match[1])
+	rate, _ := strconv.Atoi(match[2])
+	return ports.AudioDeviceFormat{SampleRate: rate, Channels: channels}, nil
+}
+
+// resolve strips the "audio=" prefix and picks the first device for
+// "default".
+func (d *DShowDevices) resolve(ctx context.Context, device string) (string, error) {
+	device = strings.TrimPrefix(device, "audio=")
+	if device != "" && device != "default" {
+		return device, nil
+	}
+	devices, err := d.InputDevices(ctx, DShowInputFormat)
+	if err != nil {
+		return "", err
+	}
+	if len(devices) == 0 {
+		return "", fmt.Errorf("no DirectShow audio capture device found")
+	}
+	return devices[0], nil
+}
@@ -0,0 +1,80 @@
+package audio
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"coldmic/internal/ports"
+)
+
+const dshowListing = `[dshow @ 000001] "Integrated Camera" (video)
+[dshow @ 000001]   Alternative name "@device_pnp_\\?\usb#vid_04f2"
+[dshow @ 000001] "Microphone Array (Realtek(R) Audio)" (audio)
+[dshow @ 000001]   Alternative name "@device_cm_{33D9A762}\wave_{A1B2}"
+[dshow @ 000001] "Headset (Jabra Evolve 65)" (audio)
+dummy: Immediate exit requested
+`
+
+const dshowLegacyListing = `[dshow @ 000002] DirectShow video devices (some may be both video and audio devices)
+[dshow @ 000002]  "Integrated Camera"
+[dshow @ 000002] DirectShow audio devices
+[dshow @ 000002]  "Microphone (USB Audio Device)"
+[dshow @ 000002]     Alternative name "@device_cm_{33D9A762}\wave_{C3D4}"
+`
+
+const dshowOptions = `[dshow @ 000003] DirectShow audio only device options (from audio devices)
+[dshow @ 000003]  Pin "Capture" (alternative pin name "Capture")
+[dshow @ 000003]   ch= 2, bits=16, rate= 48000
+[dshow @ 000003]   ch= 1, bits=16, rate= 44100
+`
+
+func TestDShowDevicesListsAudioDevices(t *testing.T) {
+	t.Parallel()
+
+	for name, listing := range map[string]string{"typed": dshowListing, "legacy": dshowLegacyListing} {
+		devices := NewDShowDevices("")
+		devices.run = func(context.Context, ...string) (string, error) { return listing, nil }
+		got, err := devices.InputDevices(context.Background(), DShowInputFormat)
+		if err != nil {
+			t.Fatalf("%s: list failed: %v", name, err)
+		}
+		want := []string{"Microphone Array (Realtek(R) Audio)", "Headset (Jabra Evolve 65)"}
+		if name == "legacy" {
+			want = []string{"Microphone (USB Audio Device)"}
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("%s: expected %q, got %q", name, want, got)
+		}
+	}
+}
+
+func TestDShowDevicesDeviceFormat(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+	devices := NewDShowDevices("")
+	devices.run = func(_ context.Context, args ...string) (string, error) {
+		calls = append(calls, strings.Join(args, " "))
+		if slices.Contains(args, "-list_devices") {
+			return dshowListing, nil
+		}
+		return dshowOptions, nil
+	}
+
+	format, err := devices.DeviceFormat(context.Background(), DShowInputFormat, "default")
+	if err != nil {
+		t.Fatalf("lookup failed: %v", err)
+	}
+	if format != (ports.AudioDeviceFormat{SampleRate: 48000, Channels: 2}) {
+		t.Fatalf("unexpected format: %+v", format)
+	}
+	if len(calls) != 2 || !strings.HasSuffix(calls[1], "-i audio=Microphone Array (Realtek(R) Audio)") {
+		t.Fatalf("expected the default resolved to the first audio device, got %q", calls)
+	}
+
+	if _, err := devices.DeviceFormat(context.Background(), "pulse", "default"); err == nil {
+		t.Fatalf("expected non-dshow input to fail")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/exec"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 // FFMPEGCapture streams microphone PCM audio using ffmpeg.
 type FFMPEGCapture struct {
 	command string
+	devices ports.AudioDeviceEnumerator
 }
 
 func NewFFMPEGCapture(command string) *FFMPEGCapture {
@@ -28,6 +30,13 @@ func NewFFMPEGCapture(command string) *FFMPEGCapture {
 	return &FFMPEGCapture{command: command}
 }
 
+// SetDeviceEnumerator looks up each device's native format, so capture runs
+// at the rate the device delivers and ffmpeg resamples, rather than asking
+// the device for a rate it may not support.
+func (c *FFMPEGCapture) SetDeviceEnumerator(devices ports.AudioDeviceEnumerator) {
+	c.devices = devices
+}
+
 func (c *FFMPEGCapture) Start(ctx context.Context, cfg ports.AudioConfig) (ports.AudioSession, error) {
 	if cfg.SampleRate <= 0 {
 		cfg.SampleRate = 16000
@@ -42,22 +51,66 @@ func (c *FFMPEGCapture) Start(ctx context.Context, cfg ports.AudioConfig) (ports
 		cfg.InputDevice = "default"
 	}
 
+	input := cfg.InputDevice
+	switch cfg.InputFormat {
+	case DShowInputFormat:
+		device, err := c.dshowDevice(ctx, cfg.InputDevice)
+		if err != nil {
+			return nil, err
+		}
+		cfg.InputDevice = device
+		input = "audio=" + device
+	case AVFoundationInputFormat:
+		if err := microphoneAccess(ctx); err != nil {
+			return nil, err
+		}
+		device, err := avfoundationInput(ctx, c.devices, cfg.InputDevice)
+		if err != nil {
+			return nil, err
+		}
+		input = device
+	}
+
+	// The pulse, alsa and dshow demuxers take the capture format as input
+	// options.
+	var native ports.AudioDeviceFormat
+	takesFormat := cfg.InputFormat == "pulse" || cfg.InputFormat == "alsa" || cfg.InputFormat == DShowInputFormat
+	if takesFormat {
+		native = c.deviceFormat(ctx, cfg)
+	}
+
 	args := []string{
 		"-nostdin",
 		"-hide_banner",
 		"-loglevel", "warning",
 		"-f", cfg.InputFormat,
-		"-i", cfg.InputDevice,
+	}
+	// DirectShow buffers 500ms by default, which delays partial transcripts.
+	if cfg.InputFormat == DShowInputFormat {
+		args = append(args, "-audio_buffer_size", "50")
+	}
+	if takesFormat {
+		if native.SampleRate > 0 {
+			args = append(args, "-sample_rate", strconv.Itoa(native.SampleRate))
+		}
+		if native.Channels > 0 {
+			args = append(args, "-channels", strconv.Itoa(native.Channels))
+		}
+	}
+	args = append(args,
+		"-i", input,
 		"-ac", strconv.Itoa(cfg.Channels),
 		"-ar", strconv.Itoa(cfg.SampleRate),
 		"-f", "s16le",
 		"-",
-	}
+	)
 	debuglog.Printf(
-		"ffmpeg start command=%s input_format=%s input_device=%s sample_rate=%d channels=%d",
+		"ffmpeg start command=%s input_format=%s input_device=%s device_rate=%d device_channels=%d sample_rate=%d channels=%d",
 		c.command,
 		cfg.InputFormat,
 		cfg.InputDevice,
+		native.SampleRate,
+		native.Channels,
 		cfg.SampleRate,
 		cfg.Channels,
 	)
@@ -103,6 +156,47 @@ func (c *FFMPEGCapture) Start(ctx context.Context, cfg ports.AudioConfig) (ports
 	}, nil
 }
 
+// dshowDevice strips the "audio=" prefix from a DirectShow device name and
+// resolves "default" to the first listed audio device, since DirectShow has
+// no default device name.
+func (c *FFMPEGCapture) dshowDevice(ctx context.Context, device string) (string, error) {
+	device = strings.TrimPrefix(device, "audio=")
+	if device != "default" {
+		return device, nil
+	}
+	lister, ok := c.devices.(ports.AudioDeviceLister)
+	if !ok {
+		return "", errors.New("dshow capture needs COLDMIC_AUDIO_INPUT_DEVICE set to a device name")
+	}
+	devices, err := lister.InputDevices(ctx, DShowInputFormat)
+	if err != nil {
+		return "", fmt.Errorf("failed to list capture devices: %w", err)
+	}
+	if len(devices) == 0 {
+		return "", errors.New("no DirectShow audio capture device found")
+	}
+	debuglog.Printf("ffmpeg dshow default device=%s", devices[0])
+	return devices[0], nil
+}
+
+// deviceFormat returns the configured native format, or asks the enumerator
+// when none is configured. A failed lookup leaves the format to ffmpeg.
+func (c *FFMPEGCapture) deviceFormat(ctx context.Context, cfg ports.AudioConfig) ports.AudioDeviceFormat {
+	native := ports.AudioDeviceFormat{SampleRate: cfg.DeviceSampleRate, Channels: cfg.DeviceChannels}
+	if native.SampleRate > 0 || c.devices == nil {
+		return native
+	}
+	detected, err := c.devices.DeviceFormat(ctx, cfg.InputFormat, cfg.InputDevice)
+	if err != nil {
+		debuglog.Printf("ffmpeg device format lookup failed: %v", err)
+		return native
+	}
+	if native.Channels > 0 {
+		detected.Channels = native.Channels
+	}
+	return detected
+}
+
 type ffmpegSession struct {
 	stdout io.ReadCloser
 	stderr *bytes.Buffer
@@ -2,6 +2,7 @@ package audio
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -51,6 +52,116 @@ func TestFFMPEGCaptureStartEarlyExit(t *testing.T) {
 	}
 }
 
+type fakeDevices struct {
+	format ports.AudioDeviceFormat
+	err    error
+	device string
+	inputs []string
+}
+
+func (f *fakeDevices) InputDevices(context.Context, string) ([]string, error) {
+	return f.inputs, f.err
+}
+
+func (f *fakeDevices) DeviceFormat(_ context.Context, _ string, device string) (ports.AudioDeviceFormat, error) {
+	f.device = device
+	return f.format, f.err
+}
+
+func TestFFMPEGCaptureUsesNativeDeviceFormat(t *testing.T) {
+	argsFile := filepath.Join(t.TempDir(), "args")
+	script := writeScript(t, "capture.sh", "#!/usr/bin/env bash\necho \"$@\" > "+argsFile+"\nsleep 2\n")
+	capture := NewFFMPEGCapture(script)
+	devices := &fakeDevices{format: ports.AudioDeviceFormat{SampleRate: 44100, Channels: 2}}
+	capture.SetDeviceEnumerator(devices)
+
+	session, err := capture.Start(context.Background(), ports.AudioConfig{InputDevice: "usb-mic"})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	_ = session.Stop()
+
+	args, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("read args failed: %v", err)
+	}
+	if devices.device != "usb-mic" || !strings.Contains(string(args), "-f pulse -sample_rate 44100 -channels 2 -i usb-mic -ac 1 -ar 16000") {
+		t.Fatalf("expected native capture resampled to 16 kHz mono, got %q", args)
+	}
+}
+
+func TestFFMPEGCaptureResolvesDShowDefaultDevice(t *testing.T) {
+	argsFile := filepath.Join(t.TempDir(), "args")
+	script := writeScript(t, "capture.sh", "#!/usr/bin/env bash\necho \"$@\" > "+argsFile+"\nsleep 2\n")
+	capture := NewFFMPEGCapture(script)
+	devices := &fakeDevices{format: ports.AudioDeviceFormat{SampleRate: 48000, Channels: 2}, inputs: []string{"Headset Microphone", "Line In"}}
+	capture.SetDeviceEnumerator(devices)
+
+	session, err := capture.Start(context.Background(), ports.AudioConfig{InputFormat: DShowInputFormat})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	_ = session.Stop()
+
+	args, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("read args failed: %v", err)
+	}
+	want := "-f dshow -audio_buffer_size 50 -sample_rate 48000 -channels 2 -i audio=Headset Microphone -ac 1 -ar 16000"
+	if devices.device != "Headset Microphone" || !strings.Contains(string(args), want) {
+		t.Fatalf("expected the first DirectShow device captured natively, got %q", args)
+	}
+
+	capture.SetDeviceEnumerator(&fakeDevices{})
+	if _, err := capture.Start(context.Background(), ports.AudioConfig{InputFormat: DShowInputFormat}); err == nil {
+		t.Fatalf("expected an error without any DirectShow device")
+	}
+}
+
+func TestFFMPEGCaptureAVFoundationInput(t *testing.T) {
+	argsFile := filepath.Join(t.TempDir(), "args")
+	script := writeScript(t, "capture.sh", "#!/usr/bin/env bash\necho \"$@\" > "+argsFile+"\nsleep 2\n")
+	capture := NewFFMPEGCapture(script)
+	devices := &fakeDevices{format: ports.AudioDeviceFormat{SampleRate: 48000, Channels: 2}, inputs: []string{"MacBook Pro Microphone", "Shure MV7"}}
+	capture.SetDeviceEnumerator(devices)
+
+	session, err := capture.Start(context.Background(), ports.AudioConfig{InputFormat: AVFoundationInputFormat, InputDevice: "shure"})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	_ = session.Stop()
+
+	args, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("read args failed: %v", err)
+	}
+	if devices.device != "" || !strings.Contains(string(args), "-f avfoundation -i :1 -ac 1 -ar 16000") {
+		t.Fatalf("expected the device index without format options, got %q", args)
+	}
+}
+
+func TestFFMPEGCaptureDeviceFormatFallbacks(t *testing.T) {
+	t.Parallel()
+
+	capture := NewFFMPEGCapture("ffmpeg")
+	if got := capture.deviceFormat(context.Background(), ports.AudioConfig{}); got != (ports.AudioDeviceFormat{}) {
+		t.Fatalf("expected no format without an enumerator, got %+v", got)
+	}
+	capture.SetDeviceEnumerator(&fakeDevices{err: errors.New("pactl missing")})
+	if got := capture.deviceFormat(context.Background(), ports.AudioConfig{}); got != (ports.AudioDeviceFormat{}) {
+		t.Fatalf("expected lookup failure to leave the format to ffmpeg, got %+v", got)
+	}
+	capture.SetDeviceEnumerator(&fakeDevices{format: ports.AudioDeviceFormat{SampleRate: 48000, Channels: 2}})
+	got := capture.deviceFormat(context.Background(), ports.AudioConfig{DeviceChannels: 1})
+	if got != (ports.AudioDeviceFormat{SampleRate: 48000, Channels: 1}) {
+		t.Fatalf("expected configured channels over detected, got %+v", got)
+	}
+	got = capture.deviceFormat(context.Background(), ports.AudioConfig{DeviceSampleRate: 32000})
+	if got != (ports.AudioDeviceFormat{SampleRate: 32000}) {
+		t.Fatalf("expected configured rate without lookup, got %+v", got)
+	}
+}
+
 func TestNormalizeStopErrExitErrorIsIgnored(t *testing.T) {
 	t.Parallel()
 
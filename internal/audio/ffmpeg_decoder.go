@@ -0,0 +1,85 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"sync"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/ports"
+)
+
+// FFMPEGDecoder converts audio files into PCM using ffmpeg.
+type FFMPEGDecoder struct {
+	command string
+}
+
+func NewFFMPEGDecoder(command string) *FFMPEGDecoder {
+	if command == "" {
+		command = "ffmpeg"
+	}
+	return &FFMPEGDecoder{command: command}
+}
+
+func (d *FFMPEGDecoder) Decode(ctx context.Context, path string, cfg ports.AudioConfig) (io.ReadCloser, error) {
+	if cfg.SampleRate <= 0 {
+		cfg.SampleRate = 16000
+	}
+	if cfg.Channels <= 0 {
+		cfg.Channels = 1
+	}
+
+	args := []string{
+		"-nostdin",
+		"-hide_banner",
+		"-loglevel", "warning",
+		"-i", path,
+		"-ac", strconv.Itoa(cfg.Channels),
+		"-ar", strconv.Itoa(cfg.SampleRate),
+		"-f", "s16le",
+		"-",
+	}
+	debuglog.Printf("ffmpeg decode command=%s path=%q sample_rate=%d channels=%d", d.command, path, cfg.SampleRate, cfg.Channels)
+
+	cmd := exec.CommandContext(ctx, d.command, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ffmpeg stdout pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start ffmpeg: %w", err)
+	}
+
+	return &ffmpegDecodeStream{stdout: stdout, stderr: &stderr, cmd: cmd}, nil
+}
+
+type ffmpegDecodeStream struct {
+	stdout io.ReadCloser
+	stderr *bytes.Buffer
+	cmd    *exec.Cmd
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func (s *ffmpegDecodeStream) Read(p []byte) (int, error) {
+	return s.stdout.Read(p)
+}
+
+// Close drains the decoder and reports a failed decode, including ffmpeg stderr.
+func (s *ffmpegDecodeStream) Close() error {
+	s.closeOnce.Do(func() {
+		_, _ = io.Copy(io.Discard, s.stdout)
+		if err := s.cmd.Wait(); err != nil {
+			s.closeErr = fmt.Errorf("ffmpeg decode failed: %w: %s", err, stringsTrimSpaceSafe(s.stderr.String()))
+		}
+	})
+	return s.closeErr
+}
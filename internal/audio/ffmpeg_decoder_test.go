@@ -0,0 +1,47 @@
+package audio
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"coldmic/internal/ports"
+)
+
+func TestFFMPEGDecoderStreamsPCM(t *testing.T) {
+	script := writeScript(t, "decode.sh", "#!/usr/bin/env bash\nprintf 'pcm-data'\n")
+	decoder := NewFFMPEGDecoder(script)
+
+	stream, err := decoder.Decode(context.Background(), "talk.wav", ports.AudioConfig{})
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+
+	data, err := io.ReadAll(stream)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if string(data) != "pcm-data" {
+		t.Fatalf("unexpected pcm: %q", string(data))
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+}
+
+func TestFFMPEGDecoderReportsFailure(t *testing.T) {
+	script := writeScript(t, "decode-fail.sh", "#!/usr/bin/env bash\necho 'invalid data' 1>&2\nexit 1\n")
+	decoder := NewFFMPEGDecoder(script)
+
+	stream, err := decoder.Decode(context.Background(), "bad.wav", ports.AudioConfig{})
+	if err != nil {
+		t.Fatalf("decode start failed: %v", err)
+	}
+	_, _ = io.ReadAll(stream)
+
+	err = stream.Close()
+	if err == nil || !strings.Contains(err.Error(), "invalid data") {
+		t.Fatalf("expected decode failure with stderr, got %v", err)
+	}
+}
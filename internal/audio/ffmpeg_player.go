@@ -0,0 +1,101 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/ports"
+)
+
+// FFMPEGPlayer plays recordings through ffmpeg's pulse output, which also
+// reaches PipeWire through its Pulse server.
+type FFMPEGPlayer struct {
+	command string
+	sink    string
+}
+
+// NewFFMPEGPlayer plays to sink, or to the default sink when it is empty.
+func NewFFMPEGPlayer(command string, sink string) *FFMPEGPlayer {
+	if command == "" {
+		command = "ffmpeg"
+	}
+	return &FFMPEGPlayer{command: command, sink: sink}
+}
+
+func (p *FFMPEGPlayer) Play(ctx context.Context, paths []string, from time.Duration) (ports.Playback, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("no audio to play")
+	}
+	cmd := exec.CommandContext(ctx, p.command, p.args(paths, from)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start ffmpeg playback: %w", err)
+	}
+	debuglog.Printf("ffmpeg playback started pid=%d files=%d from=%s", cmd.Process.Pid, len(paths), from)
+
+	playback := &ffmpegPlayback{process: cmd.Process, done: make(chan struct{})}
+	go func() {
+		err := cmd.Wait()
+		playback.mu.Lock()
+		if !playback.stopped && err != nil {
+			playback.err = fmt.Errorf("ffmpeg playback failed: %w: %s", err, stringsTrimSpaceSafe(stderr.String()))
+		}
+		playback.mu.Unlock()
+		close(playback.done)
+	}()
+	return playback, nil
+}
+
+// args seeks each input to from and mixes the tracks of a multi-track
+// recording into one stream.
+func (p *FFMPEGPlayer) args(paths []string, from time.Duration) []string {
+	args := []string{"-nostdin", "-hide_banner", "-loglevel", "error"}
+	for _, path := range paths {
+		args = append(args, "-ss", strconv.FormatFloat(from.Seconds(), 'f', 3, 64), "-i", path)
+	}
+	if len(paths) > 1 {
+		args = append(args, "-filter_complex", "amix=inputs="+strconv.Itoa(len(paths))+":duration=longest")
+	}
+	args = append(args, "-f", "pulse")
+	if p.sink != "" {
+		args = append(args, "-device", p.sink)
+	}
+	return append(args, "coldmic playback")
+}
+
+type ffmpegPlayback struct {
+	process *os.Process
+	done    chan struct{}
+
+	mu      sync.Mutex
+	stopped bool
+	err     error
+}
+
+func (p *ffmpegPlayback) Wait() error {
+	<-p.done
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// Stop ends playback; a stopped playback reports no error from Wait.
+func (p *ffmpegPlayback) Stop() error {
+	p.mu.Lock()
+	p.stopped = true
+	p.mu.Unlock()
+	if err := p.process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return err
+	}
+	<-p.done
+	return nil
+}
@@ -0,0 +1,45 @@
+package audio
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFFMPEGPlayerMixesTracksFromOffset(t *testing.T) {
+	t.Parallel()
+
+	player := NewFFMPEGPlayer("ffmpeg", "headphones")
+	args := strings.Join(player.args([]string{"me.wav", "them.wav"}, 1500*time.Millisecond), " ")
+	want := "-ss 1.500 -i me.wav -ss 1.500 -i them.wav -filter_complex amix=inputs=2:duration=longest -f pulse -device headphones coldmic playback"
+	if !strings.HasSuffix(args, want) {
+		t.Fatalf("unexpected args: %q", args)
+	}
+	if args := strings.Join(NewFFMPEGPlayer("", "").args([]string{"me.wav"}, 0), " "); strings.Contains(args, "amix") || strings.Contains(args, "-device") {
+		t.Fatalf("expected a single track to the default sink, got %q", args)
+	}
+}
+
+func TestFFMPEGPlayerStopAndFailure(t *testing.T) {
+	t.Parallel()
+
+	playback, err := NewFFMPEGPlayer(writeScript(t, "play.sh", "#!/usr/bin/env bash\nsleep 5\n"), "").Play(context.Background(), []string{"me.wav"}, 0)
+	if err != nil {
+		t.Fatalf("play failed: %v", err)
+	}
+	if err := playback.Stop(); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if err := playback.Wait(); err != nil {
+		t.Fatalf("expected no error after stop, got %v", err)
+	}
+
+	playback, err = NewFFMPEGPlayer(writeScript(t, "fail.sh", "#!/usr/bin/env bash\necho 'no sink' 1>&2\nexit 1\n"), "").Play(context.Background(), []string{"me.wav"}, 0)
+	if err != nil {
+		t.Fatalf("play failed: %v", err)
+	}
+	if err := playback.Wait(); err == nil || !strings.Contains(err.Error(), "no sink") {
+		t.Fatalf("expected playback failure, got %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+//go:build darwin && cgo
+
+package audio
+
+/*
+#cgo CFLAGS: -x objective-c -fobjc-arc
+#cgo LDFLAGS: -framework AVFoundation
+#import <AVFoundation/AVFoundation.h>
+
+// coldmic_microphone_access reports whether the app may record, showing the
+// system prompt first when the user has not decided yet.
+static int coldmic_microphone_access(void) {
+	AVAuthorizationStatus status = [AVCaptureDevice authorizationStatusForMediaType:AVMediaTypeAudio];
+	if (status != AVAuthorizationStatusNotDetermined) {
+		return status == AVAuthorizationStatusAuthorized;
+	}
+	dispatch_semaphore_t answered = dispatch_semaphore_create(0);
+	__block BOOL granted = NO;
+	[AVCaptureDevice requestAccessForMediaType:AVMediaTypeAudio completionHandler:^(BOOL ok) {
+		granted = ok;
+		dispatch_semaphore_signal(answered);
+	}];
+	dispatch_semaphore_wait(answered, DISPATCH_TIME_FOREVER);
+	return granted;
+}
+*/
+import "C"
+
+import "errors"
+
+// requestMicrophoneAccess checks the macOS privacy setting that ffmpeg's
+// capture runs under. Without access AVFoundation records silence rather
+// than failing, so a denial is reported here instead.
+func requestMicrophoneAccess() error {
+	if C.coldmic_microphone_access() == 0 {
+		return errors.New("microphone access denied; allow coldmic in System Settings > Privacy & Security > Microphone")
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build !darwin || !cgo
+
+package audio
+
+// requestMicrophoneAccess has nothing to ask outside macOS.
+func requestMicrophoneAccess() error {
+	return nil
+}
@@ -0,0 +1,107 @@
+package audio
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// pcmConverter converts interleaved s16le PCM between sample rates and
+// channel counts, for capture backends that deliver the device's native
+// format. Channels are averaged down to mono, mono is copied to every
+// channel, and rates are converted by linear interpolation.
+type pcmConverter struct {
+	inRate      int
+	inChannels  int
+	outRate     int
+	outChannels int
+
+	// prev is the last input frame, already in the output channel layout,
+	// and pos the position of the next output frame counted from it.
+	prev []float64
+	pos  float64
+	// partial holds the bytes of an incomplete input frame.
+	partial []byte
+}
+
+func newPCMConverter(inRate, inChannels, outRate, outChannels int) *pcmConverter {
+	return &pcmConverter{inRate: inRate, inChannels: inChannels, outRate: outRate, outChannels: outChannels}
+}
+
+// passthrough reports whether input is already in the output format, in
+// which case convert returns it unchanged.
+func (c *pcmConverter) passthrough() bool {
+	return c.inRate == c.outRate && c.inChannels == c.outChannels
+}
+
+// convert returns in converted to the output format. Input need not end on
+// a frame boundary; the rest is kept for the next call.
+func (c *pcmConverter) convert(in []byte) []byte {
+	if c.passthrough() {
+		return in
+	}
+	frameBytes := c.inChannels * 2
+	if len(c.partial) > 0 {
+		in = append(c.partial, in...)
+		c.partial = nil
+	}
+	whole := len(in) - len(in)%frameBytes
+	if whole < len(in) {
+		c.partial = append([]byte(nil), in[whole:]...)
+	}
+
+	frames := make([][]float64, 0, whole/frameBytes+1)
+	if c.prev != nil {
+		frames = append(frames, c.prev)
+	}
+	for offset := 0; offset < whole; offset += frameBytes {
+		frames = append(frames, c.mapChannels(in[offset:offset+frameBytes]))
+	}
+	if len(frames) == 0 {
+		return nil
+	}
+
+	step := float64(c.inRate) / float64(c.outRate)
+	out := make([]byte, 0, int(float64(len(frames))/step+1)*c.outChannels*2)
+	for {
+		index := int(c.pos)
+		if index >= len(frames) || (index+1 >= len(frames) && c.pos > float64(index)) {
+			break
+		}
+		frac := c.pos - float64(index)
+		for channel := 0; channel < c.outChannels; channel++ {
+			value := frames[index][channel]
+			if frac > 0 {
+				value += (frames[index+1][channel] - value) * frac
+			}
+			out = binary.LittleEndian.AppendUint16(out, uint16(int16(math.Round(value))))
+		}
+		c.pos += step
+	}
+	c.pos -= float64(len(frames) - 1)
+	c.prev = frames[len(frames)-1]
+	return out
+}
+
+// mapChannels converts one input frame to the output channel layout.
+func (c *pcmConverter) mapChannels(frame []byte) []float64 {
+	samples := make([]float64, c.inChannels)
+	for channel := range samples {
+		samples[channel] = float64(int16(binary.LittleEndian.Uint16(frame[channel*2:])))
+	}
+	out := make([]float64, c.outChannels)
+	switch {
+	case c.outChannels == c.inChannels:
+		copy(out, samples)
+	case c.outChannels == 1:
+		var sum float64
+		for _, sample := range samples {
+			sum += sample
+		}
+		out[0] = sum / float64(len(samples))
+	default:
+		for channel := range out {
+			out[channel] = samples[min(channel, len(samples)-1)]
+		}
+	}
+	return out
+}
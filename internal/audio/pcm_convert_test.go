@@ -0,0 +1,69 @@
+package audio
+
+import (
+	"encoding/binary"
+	"slices"
+	"testing"
+)
+
+func pcm(samples ...int16) []byte {
+	out := make([]byte, 0, len(samples)*2)
+	for _, sample := range samples {
+		out = binary.LittleEndian.AppendUint16(out, uint16(sample))
+	}
+	return out
+}
+
+func samples(data []byte) []int16 {
+	out := make([]int16, len(data)/2)
+	for i := range out {
+		out[i] = int16(binary.LittleEndian.Uint16(data[i*2:]))
+	}
+	return out
+}
+
+func TestPCMConverterDownmixesAndDownsamples(t *testing.T) {
+	t.Parallel()
+
+	converter := newPCMConverter(48000, 2, 16000, 1)
+	var out []int16
+	// Left and right differ by 200, so each mono sample is their average.
+	for _, chunk := range [][]byte{
+		pcm(0, 200, 300, 500, 600, 800, 900, 1100),
+		pcm(1200, 1400, 1500, 1700, 1800, 2000),
+		pcm(2100, 2300, 2400, 2600),
+	} {
+		out = append(out, samples(converter.convert(chunk))...)
+	}
+	if want := []int16{100, 1000, 1900}; !slices.Equal(out, want) {
+		t.Fatalf("unexpected samples: got %v want %v", out, want)
+	}
+}
+
+func TestPCMConverterUpsamplesAcrossChunks(t *testing.T) {
+	t.Parallel()
+
+	converter := newPCMConverter(8000, 1, 16000, 2)
+	out := samples(converter.convert(pcm(0, 100)))
+	out = append(out, samples(converter.convert(pcm(200)))...)
+	if want := []int16{0, 0, 50, 50, 100, 100, 150, 150, 200, 200}; !slices.Equal(out, want) {
+		t.Fatalf("unexpected samples: got %v want %v", out, want)
+	}
+}
+
+func TestPCMConverterKeepsPartialFrames(t *testing.T) {
+	t.Parallel()
+
+	converter := newPCMConverter(16000, 2, 16000, 1)
+	data := pcm(10, 30, 50, 70)
+	out := samples(converter.convert(data[:5]))
+	out = append(out, samples(converter.convert(data[5:]))...)
+	if want := []int16{20, 60}; !slices.Equal(out, want) {
+		t.Fatalf("unexpected samples: got %v want %v", out, want)
+	}
+
+	same := newPCMConverter(16000, 1, 16000, 1)
+	if got := same.convert(data); &got[0] != &data[0] {
+		t.Fatalf("expected matching formats to pass through")
+	}
+}
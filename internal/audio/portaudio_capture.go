@@ -0,0 +1,20 @@
+package audio
+
+import "time"
+
+// PortAudioInputFormat selects PortAudioCapture as COLDMIC_AUDIO_INPUT_FORMAT.
+const PortAudioInputFormat = "portaudio"
+
+// portAudioReadInterval is how much audio each blocking read returns.
+const portAudioReadInterval = 50 * time.Millisecond
+
+// PortAudioCapture records through PortAudio, which uses the native audio
+// system of Linux, macOS and Windows, so one configuration records on all
+// three without an ffmpeg input format. The device is opened in its native
+// format and converted to the requested rate and channels. It needs a build
+// with the portaudio tag; other builds report an error on Start.
+type PortAudioCapture struct{}
+
+func NewPortAudioCapture() *PortAudioCapture {
+	return &PortAudioCapture{}
+}
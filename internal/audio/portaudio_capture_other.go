@@ -0,0 +1,14 @@
+//go:build !portaudio
+
+package audio
+
+import (
+	"context"
+	"errors"
+
+	"coldmic/internal/ports"
+)
+
+func (c *PortAudioCapture) Start(context.Context, ports.AudioConfig) (ports.AudioSession, error) {
+	return nil, errors.New("COLDMIC_AUDIO_INPUT_FORMAT=portaudio needs a build with -tags portaudio")
+}
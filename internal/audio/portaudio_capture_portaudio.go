@@ -0,0 +1,167 @@
+//go:build portaudio
+
+package audio
+
+/*
+#cgo pkg-config: portaudio-2.0
+#include <portaudio.h>
+*/
+import "C"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/ports"
+)
+
+func (c *PortAudioCapture) Start(ctx context.Context, cfg ports.AudioConfig) (ports.AudioSession, error) {
+	if cfg.SampleRate <= 0 {
+		cfg.SampleRate = 16000
+	}
+	if cfg.Channels <= 0 {
+		cfg.Channels = 1
+	}
+	if code := C.Pa_Initialize(); code != C.paNoError {
+		return nil, fmt.Errorf("failed to initialize PortAudio: %s", portAudioError(code))
+	}
+	session, err := openPortAudio(ctx, cfg)
+	if err != nil {
+		C.Pa_Terminate()
+		return nil, err
+	}
+	return session, nil
+}
+
+func openPortAudio(ctx context.Context, cfg ports.AudioConfig) (*portAudioSession, error) {
+	device, err := portAudioDevice(cfg.InputDevice)
+	if err != nil {
+		return nil, err
+	}
+	info := C.Pa_GetDeviceInfo(device)
+	rate := cfg.DeviceSampleRate
+	if rate <= 0 {
+		rate = int(info.defaultSampleRate)
+	}
+	channels := cfg.DeviceChannels
+	if channels <= 0 {
+		channels = min(cfg.Channels, int(info.maxInputChannels))
+	}
+
+	params := C.PaStreamParameters{
+		device:           device,
+		channelCount:     C.int(channels),
+		sampleFormat:     C.paInt16,
+		suggestedLatency: info.defaultLowInputLatency,
+	}
+	frames := rate * int(portAudioReadInterval) / int(time.Second)
+	debuglog.Printf(
+		"portaudio start input_device=%q device=%q device_rate=%d device_channels=%d sample_rate=%d channels=%d",
+		cfg.InputDevice,
+		C.GoString(info.name),
+		rate,
+		channels,
+		cfg.SampleRate,
+		cfg.Channels,
+	)
+	var stream unsafe.Pointer
+	if code := C.Pa_OpenStream(&stream, &params, nil, C.double(rate), C.ulong(frames), C.paNoFlag, nil, nil); code != C.paNoError {
+		return nil, fmt.Errorf("failed to open PortAudio device %q: %s", C.GoString(info.name), portAudioError(code))
+	}
+	if code := C.Pa_StartStream(stream); code != C.paNoError {
+		C.Pa_CloseStream(stream)
+		return nil, fmt.Errorf("failed to start PortAudio capture: %s", portAudioError(code))
+	}
+	return &portAudioSession{
+		ctx:       ctx,
+		stream:    stream,
+		frames:    frames,
+		raw:       make([]byte, frames*channels*2),
+		converter: newPCMConverter(rate, channels, cfg.SampleRate, cfg.Channels),
+	}, nil
+}
+
+// portAudioDevice returns the default input device, or the first input
+// device whose name contains name.
+func portAudioDevice(name string) (C.PaDeviceIndex, error) {
+	if name == "" || name == "default" {
+		device := C.Pa_GetDefaultInputDevice()
+		if device < 0 {
+			return 0, errors.New("no default PortAudio input device")
+		}
+		return device, nil
+	}
+	count := C.Pa_GetDeviceCount()
+	for device := C.PaDeviceIndex(0); device < count; device++ {
+		info := C.Pa_GetDeviceInfo(device)
+		if info != nil && info.maxInputChannels > 0 && strings.Contains(strings.ToLower(C.GoString(info.name)), strings.ToLower(name)) {
+			return device, nil
+		}
+	}
+	return 0, fmt.Errorf("no PortAudio input device matches %q", name)
+}
+
+type portAudioSession struct {
+	ctx       context.Context
+	frames    int
+	raw       []byte
+	converter *pcmConverter
+
+	// mu is held by a read in flight, so Stop closes the stream only once
+	// the read has returned.
+	mu      sync.Mutex
+	stream  unsafe.Pointer
+	pending []byte
+}
+
+func (s *portAudioSession) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for len(s.pending) == 0 {
+		if s.stream == nil || s.ctx.Err() != nil {
+			return 0, io.EOF
+		}
+		code := C.Pa_ReadStream(s.stream, unsafe.Pointer(&s.raw[0]), C.ulong(s.frames))
+		// An overflow lost audio the app was too slow to read, but the
+		// stream carries on.
+		if code != C.paNoError && code != C.paInputOverflowed {
+			return 0, fmt.Errorf("PortAudio capture failed: %s", portAudioError(code))
+		}
+		s.pending = s.converter.convert(s.raw)
+	}
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+func (s *portAudioSession) Close() error {
+	return s.Stop()
+}
+
+func (s *portAudioSession) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stream == nil {
+		return nil
+	}
+	var err error
+	if code := C.Pa_StopStream(s.stream); code != C.paNoError {
+		err = fmt.Errorf("failed to stop PortAudio capture: %s", portAudioError(code))
+	}
+	C.Pa_CloseStream(s.stream)
+	C.Pa_Terminate()
+	s.stream = nil
+	debuglog.Printf("portaudio stop completed err=%v", err)
+	return err
+}
+
+func portAudioError(code C.PaError) string {
+	return C.GoString(C.Pa_GetErrorText(code))
+}
@@ -0,0 +1,20 @@
+//go:build !portaudio
+
+package audio
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"coldmic/internal/ports"
+)
+
+func TestPortAudioCaptureNeedsPortAudioBuild(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewPortAudioCapture().Start(context.Background(), ports.AudioConfig{})
+	if err == nil || !strings.Contains(err.Error(), "-tags portaudio") {
+		t.Fatalf("expected a build tag error, got %v", err)
+	}
+}
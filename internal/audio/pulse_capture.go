@@ -0,0 +1,24 @@
+package audio
+
+import "time"
+
+// PulseInputFormat selects PulseCapture as COLDMIC_AUDIO_INPUT_FORMAT.
+const PulseInputFormat = "libpulse"
+
+// pulseReadInterval bounds each blocking read, and with it how long Stop
+// waits for a read in flight.
+const pulseReadInterval = 50 * time.Millisecond
+
+// PulseCapture records from PulseAudio through libpulse-simple instead of
+// an ffmpeg process, so capture needs no ffmpeg and starts without waiting
+// for a child process. The server converts to the requested rate and
+// channels. It needs a build with the libpulse tag; other builds report an
+// error on Start.
+type PulseCapture struct {
+	// name identifies the client and stream in PulseAudio mixers.
+	name string
+}
+
+func NewPulseCapture() *PulseCapture {
+	return &PulseCapture{name: "coldmic"}
+}
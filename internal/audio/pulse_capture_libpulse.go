@@ -0,0 +1,117 @@
+//go:build libpulse
+
+package audio
+
+/*
+#cgo pkg-config: libpulse-simple
+#include <stdlib.h>
+#include <pulse/simple.h>
+#include <pulse/error.h>
+*/
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+	"unsafe"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/ports"
+)
+
+func (c *PulseCapture) Start(ctx context.Context, cfg ports.AudioConfig) (ports.AudioSession, error) {
+	if cfg.SampleRate <= 0 {
+		cfg.SampleRate = 16000
+	}
+	if cfg.Channels <= 0 {
+		cfg.Channels = 1
+	}
+	frame := cfg.Channels * 2
+	chunk := cfg.SampleRate * frame * int(pulseReadInterval) / int(time.Second)
+
+	spec := C.pa_sample_spec{
+		format:   C.PA_SAMPLE_S16LE,
+		rate:     C.uint32_t(cfg.SampleRate),
+		channels: C.uint8_t(cfg.Channels),
+	}
+	// A small fragment size keeps the server from batching seconds of
+	// audio before the first read returns.
+	attr := C.pa_buffer_attr{
+		maxlength: C.uint32_t(0xffffffff),
+		tlength:   C.uint32_t(0xffffffff),
+		prebuf:    C.uint32_t(0xffffffff),
+		minreq:    C.uint32_t(0xffffffff),
+		fragsize:  C.uint32_t(chunk),
+	}
+	name := C.CString(c.name)
+	defer C.free(unsafe.Pointer(name))
+	description := C.CString("dictation")
+	defer C.free(unsafe.Pointer(description))
+	var device *C.char
+	if cfg.InputDevice != "" && cfg.InputDevice != "default" {
+		device = C.CString(cfg.InputDevice)
+		defer C.free(unsafe.Pointer(device))
+	}
+
+	debuglog.Printf("libpulse start input_device=%s sample_rate=%d channels=%d", cfg.InputDevice, cfg.SampleRate, cfg.Channels)
+	var code C.int
+	stream := C.pa_simple_new(nil, name, C.PA_STREAM_RECORD, device, description, &spec, nil, &attr, &code)
+	if stream == nil {
+		return nil, fmt.Errorf("failed to start PulseAudio capture: %s", pulseError(code))
+	}
+
+	return &pulseSession{ctx: ctx, stream: stream, frame: frame, chunk: chunk}, nil
+}
+
+type pulseSession struct {
+	ctx   context.Context
+	frame int
+	chunk int
+
+	// mu is held by a read in flight, so Stop frees the stream only once
+	// the read has returned.
+	mu     sync.Mutex
+	stream *C.pa_simple
+}
+
+func (s *pulseSession) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stream == nil || s.ctx.Err() != nil {
+		return 0, io.EOF
+	}
+	// pa_simple_read fills the whole buffer, so keep each read short and
+	// whole frames long.
+	n := min(len(p), s.chunk)
+	n -= n % s.frame
+	if n == 0 {
+		return 0, io.ErrShortBuffer
+	}
+	var code C.int
+	if C.pa_simple_read(s.stream, unsafe.Pointer(&p[0]), C.size_t(n), &code) < 0 {
+		return 0, fmt.Errorf("PulseAudio capture failed: %s", pulseError(code))
+	}
+	return n, nil
+}
+
+func (s *pulseSession) Close() error {
+	return s.Stop()
+}
+
+func (s *pulseSession) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stream != nil {
+		C.pa_simple_free(s.stream)
+		s.stream = nil
+		debuglog.Printf("libpulse stop completed")
+	}
+	return nil
+}
+
+func pulseError(code C.int) string {
+	return C.GoString(C.pa_strerror(code))
+}
@@ -0,0 +1,14 @@
+//go:build !libpulse
+
+package audio
+
+import (
+	"context"
+	"errors"
+
+	"coldmic/internal/ports"
+)
+
+func (c *PulseCapture) Start(context.Context, ports.AudioConfig) (ports.AudioSession, error) {
+	return nil, errors.New("COLDMIC_AUDIO_INPUT_FORMAT=libpulse needs a build with -tags libpulse")
+}
@@ -0,0 +1,20 @@
+//go:build !libpulse
+
+package audio
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"coldmic/internal/ports"
+)
+
+func TestPulseCaptureNeedsLibpulseBuild(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewPulseCapture().Start(context.Background(), ports.AudioConfig{})
+	if err == nil || !strings.Contains(err.Error(), "-tags libpulse") {
+		t.Fatalf("expected a build tag error, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package audio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"coldmic/internal/ports"
+)
+
+// PulseDevices implements ports.AudioDeviceEnumerator with pactl, which
+// PipeWire also provides.
+type PulseDevices struct {
+	run     func(ctx context.Context, args ...string) (string, error)
+	timeout time.Duration
+}
+
+func NewPulseDevices(command string) *PulseDevices {
+	if command == "" {
+		command = "pactl"
+	}
+	return &PulseDevices{
+		run: func(ctx context.Context, args ...string) (string, error) {
+			output, err := exec.CommandContext(ctx, command, args...).Output()
+			if err != nil {
+				return "", fmt.Errorf("%s %s failed: %w", command, strings.Join(args, " "), err)
+			}
+			return string(output), nil
+		},
+		timeout: 2 * time.Second,
+	}
+}
+
+type pulseSource struct {
+	Name       string `json:"name"`
+	SampleSpec string `json:"sample_specification"`
+}
+
+// sampleSpecPattern matches pactl sample specs such as "s24le 2ch 48000Hz".
+var sampleSpecPattern = regexp.MustCompile(`(\d+)ch (\d+)Hz`)
+
+func (d *PulseDevices) DeviceFormat(ctx context.Context, inputFormat string, device string) (ports.AudioDeviceFormat, error) {
+	if inputFormat != "pulse" {
+		return ports.AudioDeviceFormat{}, fmt.Errorf("device formats are only known for pulse input, not %q", inputFormat)
+	}
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	if device == "" || device == "default" {
+		name, err := d.run(ctx, "get-default-source")
+		if err != nil {
+			return ports.AudioDeviceFormat{}, err
+		}
+		device = strings.TrimSpace(name)
+	}
+	output, err := d.run(ctx, "-f", "json", "list", "sources")
+	if err != nil {
+		return ports.AudioDeviceFormat{}, err
+	}
+	var sources []pulseSource
+	if err := json.Unmarshal([]byte(output), &sources); err != nil {
+		return ports.AudioDeviceFormat{}, fmt.Errorf("invalid pactl sources output: %w", err)
+	}
+	for _, source := range sources {
+		if source.Name != device {
+			continue
+		}
+		match := sampleSpecPattern.FindStringSubmatch(source.SampleSpec)
+		if match == nil {
+			return ports.AudioDeviceFormat{}, fmt.Errorf("unrecognized sample spec %q for %s", source.SampleSpec, device)
+		}
+		channels, _ := strconv.Atoi(match[1])
+		rate, _ := strconv.Atoi(match[2])
+		return ports.AudioDeviceFormat{SampleRate: rate, Channels: channels}, nil
+	}
+	return ports.AudioDeviceFormat{}, fmt.Errorf("capture device %q not found", device)
+}
@@ -0,0 +1,41 @@
+package audio
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"coldmic/internal/ports"
+)
+
+func TestPulseDevicesDeviceFormat(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+	devices := NewPulseDevices("")
+	devices.run = func(_ context.Context, args ...string) (string, error) {
+		calls = append(calls, strings.Join(args, " "))
+		if args[0] == "get-default-source" {
+			return "alsa_input.usb-mic\n", nil
+		}
+		return `[{"name":"alsa_output.monitor","sample_specification":"s16le 2ch 48000Hz"},{"name":"alsa_input.usb-mic","sample_specification":"s24le 1ch 44100Hz"}]`, nil
+	}
+
+	format, err := devices.DeviceFormat(context.Background(), "pulse", "default")
+	if err != nil {
+		t.Fatalf("lookup failed: %v", err)
+	}
+	if format != (ports.AudioDeviceFormat{SampleRate: 44100, Channels: 1}) {
+		t.Fatalf("unexpected format: %+v", format)
+	}
+	if strings.Join(calls, "|") != "get-default-source|-f json list sources" {
+		t.Fatalf("unexpected pactl calls: %q", calls)
+	}
+
+	if _, err := devices.DeviceFormat(context.Background(), "pulse", "missing"); err == nil {
+		t.Fatalf("expected unknown device to fail")
+	}
+	if _, err := devices.DeviceFormat(context.Background(), "alsa", "hw:0"); err == nil {
+		t.Fatalf("expected non-pulse input to fail")
+	}
+}
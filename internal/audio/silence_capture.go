@@ -0,0 +1,69 @@
+package audio
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"coldmic/internal/ports"
+)
+
+// SilenceCapture produces real-time silence in place of a microphone, for
+// development with the replay provider.
+type SilenceCapture struct{}
+
+func NewSilenceCapture() *SilenceCapture {
+	return &SilenceCapture{}
+}
+
+// silenceInterval is how much audio each read delivers.
+const silenceInterval = 100 * time.Millisecond
+
+func (c *SilenceCapture) Start(ctx context.Context, cfg ports.AudioConfig) (ports.AudioSession, error) {
+	if cfg.SampleRate <= 0 {
+		cfg.SampleRate = 16000
+	}
+	if cfg.Channels <= 0 {
+		cfg.Channels = 1
+	}
+	chunk := cfg.SampleRate * cfg.Channels * 2 * int(silenceInterval) / int(time.Second)
+	return &silenceSession{
+		ctx:    ctx,
+		chunk:  make([]byte, chunk),
+		ticker: time.NewTicker(silenceInterval),
+		stop:   make(chan struct{}),
+	}, nil
+}
+
+type silenceSession struct {
+	ctx    context.Context
+	chunk  []byte
+	ticker *time.Ticker
+
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+func (s *silenceSession) Read(p []byte) (int, error) {
+	select {
+	case <-s.ticker.C:
+		return copy(p, s.chunk), nil
+	case <-s.stop:
+		return 0, io.EOF
+	case <-s.ctx.Done():
+		return 0, io.EOF
+	}
+}
+
+func (s *silenceSession) Close() error {
+	return s.Stop()
+}
+
+func (s *silenceSession) Stop() error {
+	s.stopOnce.Do(func() {
+		s.ticker.Stop()
+		close(s.stop)
+	})
+	return nil
+}
@@ -0,0 +1,32 @@
+package audio
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"coldmic/internal/ports"
+)
+
+func TestSilenceCaptureReadsSilenceUntilStopped(t *testing.T) {
+	t.Parallel()
+
+	session, err := NewSilenceCapture().Start(context.Background(), ports.AudioConfig{})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	buf := make([]byte, 8192)
+	n, err := session.Read(buf)
+	if err != nil || n != 3200 {
+		t.Fatalf("expected 100ms of 16 kHz mono audio, got n=%d err=%v", n, err)
+	}
+	for _, b := range buf[:n] {
+		if b != 0 {
+			t.Fatalf("expected silence")
+		}
+	}
+	_ = session.Stop()
+	if _, err := session.Read(buf); err != io.EOF {
+		t.Fatalf("expected EOF after stop, got %v", err)
+	}
+}
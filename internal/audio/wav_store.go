@@ -0,0 +1,287 @@
+package audio
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+const (
+	wavHeaderSize = 44
+	// recordingTimeLayout prefixes every recording name with its start time.
+	recordingTimeLayout    = "20060102-150405"
+	defaultWaveformBuckets = 1000
+)
+
+// WAVStore writes captured PCM as WAV files into a directory.
+type WAVStore struct {
+	dir string
+}
+
+func NewWAVStore(dir string) *WAVStore {
+	return &WAVStore{dir: dir}
+}
+
+// Create opens <dir>/<name>.wav; the header sizes are finalized on Close.
+func (s *WAVStore) Create(name string, cfg ports.AudioConfig) (io.WriteCloser, error) {
+	if cfg.SampleRate <= 0 {
+		cfg.SampleRate = 16000
+	}
+	if cfg.Channels <= 0 {
+		cfg.Channels = 1
+	}
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create recordings directory: %w", err)
+	}
+
+	path := filepath.Join(s.dir, sanitizeRecordingName(name)+".wav")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording file: %w", err)
+	}
+	writer := &wavWriter{file: file, sampleRate: cfg.SampleRate, channels: cfg.Channels}
+	if err := writer.writeHeader(); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return writer, nil
+}
+
+// FreeBytes reports the space available to unprivileged users on the
+// filesystem holding the recordings directory.
+func (s *WAVStore) FreeBytes() (uint64, error) {
+	dir := s.dir
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return freeBytes(dir)
+}
+
+// Waveform reads the newest recordings of a session and reduces each to at
+// most buckets peaks.
+func (s *WAVStore) Waveform(sessionID string, buckets int) (domain.Waveform, error) {
+	if buckets <= 0 {
+		buckets = defaultWaveformBuckets
+	}
+	labels, paths, err := s.sessionRecordings(sessionID)
+	if err != nil {
+		return domain.Waveform{}, err
+	}
+	waveform := domain.Waveform{SessionID: sessionID}
+	for i, path := range paths {
+		track, err := readWaveform(path, buckets)
+		if err != nil {
+			return domain.Waveform{}, err
+		}
+		track.Label = labels[i]
+		waveform.Tracks = append(waveform.Tracks, track)
+	}
+	return waveform, nil
+}
+
+// SessionAudio returns the paths of the newest recordings of a session.
+func (s *WAVStore) SessionAudio(sessionID string) ([]string, error) {
+	_, paths, err := s.sessionRecordings(sessionID)
+	return paths, err
+}
+
+// PurgeSince deletes the recordings started at or after since, judged by the
+// local start time in their names, and returns how many it deleted.
+func (s *WAVStore) PurgeSince(_ context.Context, since time.Time) (int, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to list recordings: %w", err)
+	}
+
+	purged := 0
+	var errs []error
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".wav")
+		if !ok || entry.IsDir() || len(name) < len(recordingTimeLayout) {
+			continue
+		}
+		started, err := time.ParseInLocation(recordingTimeLayout, name[:len(recordingTimeLayout)], time.Local)
+		if err != nil || started.Before(since.Truncate(time.Second)) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, entry.Name())); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete recording: %w", err))
+			continue
+		}
+		purged++
+	}
+	return purged, errors.Join(errs...)
+}
+
+// sessionRecordings finds the files named <start>-<sessionID>-<label>.wav
+// with the latest start time. It wraps os.ErrNotExist when the session saved
+// no audio.
+func (s *WAVStore) sessionRecordings(sessionID string) ([]string, []string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, nil, fmt.Errorf("failed to list recordings: %w", err)
+	}
+
+	// Session IDs restart with the process, so only the latest start time
+	// belongs to the session the caller is looking at.
+	marker := "-" + sanitizeRecordingName(sessionID) + "-"
+	var latest string
+	var labels, paths []string
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".wav")
+		if !ok || entry.IsDir() || len(name) <= len(recordingTimeLayout) {
+			continue
+		}
+		started := name[:len(recordingTimeLayout)]
+		label, ok := strings.CutPrefix(name[len(recordingTimeLayout):], marker)
+		if !ok {
+			continue
+		}
+		if _, err := time.Parse(recordingTimeLayout, started); err != nil {
+			continue
+		}
+		if started > latest {
+			latest, labels, paths = started, nil, nil
+		}
+		if started == latest {
+			labels = append(labels, label)
+			paths = append(paths, filepath.Join(s.dir, entry.Name()))
+		}
+	}
+	if len(paths) == 0 {
+		return nil, nil, fmt.Errorf("no saved audio for session %s: %w", sessionID, os.ErrNotExist)
+	}
+	return labels, paths, nil
+}
+
+// readWaveform scans a 16-bit PCM file written by WAVStore. The header size
+// is not trusted, since a crash before Close leaves it at zero.
+func readWaveform(path string, buckets int) (domain.WaveformTrack, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return domain.WaveformTrack{}, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer file.Close()
+
+	header := make([]byte, wavHeaderSize)
+	if _, err := io.ReadFull(file, header); err != nil {
+		return domain.WaveformTrack{}, fmt.Errorf("failed to read wav header: %w", err)
+	}
+	channels := int(binary.LittleEndian.Uint16(header[22:]))
+	sampleRate := int(binary.LittleEndian.Uint32(header[24:]))
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" || binary.LittleEndian.Uint16(header[34:]) != 16 || channels <= 0 || sampleRate <= 0 {
+		return domain.WaveformTrack{}, fmt.Errorf("unsupported recording format: %s", filepath.Base(path))
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return domain.WaveformTrack{}, fmt.Errorf("failed to stat recording: %w", err)
+	}
+
+	frameSize := channels * 2
+	frames := (info.Size() - wavHeaderSize) / int64(frameSize)
+	track := domain.WaveformTrack{Duration: time.Duration(frames) * time.Second / time.Duration(sampleRate)}
+	if frames <= 0 {
+		return track, nil
+	}
+	if int64(buckets) > frames {
+		buckets = int(frames)
+	}
+	track.Peaks = make([]float64, buckets)
+
+	reader := bufio.NewReader(io.LimitReader(file, frames*int64(frameSize)))
+	frame := make([]byte, frameSize)
+	for i := int64(0); i < frames; i++ {
+		if _, err := io.ReadFull(reader, frame); err != nil {
+			return domain.WaveformTrack{}, fmt.Errorf("failed to read recording: %w", err)
+		}
+		bucket := int(i * int64(buckets) / frames)
+		for c := 0; c < frameSize; c += 2 {
+			sample := float64(int16(binary.LittleEndian.Uint16(frame[c:]))) / 32768
+			if sample < 0 {
+				sample = -sample
+			}
+			track.Peaks[bucket] = max(track.Peaks[bucket], sample)
+		}
+	}
+	return track, nil
+}
+
+type wavWriter struct {
+	file       *os.File
+	sampleRate int
+	channels   int
+	written    uint32
+}
+
+func (w *wavWriter) Write(p []byte) (int, error) {
+	n, err := w.file.Write(p)
+	w.written += uint32(n)
+	return n, err
+}
+
+func (w *wavWriter) Close() error {
+	headerErr := w.writeHeader()
+	closeErr := w.file.Close()
+	return errors.Join(headerErr, closeErr)
+}
+
+func (w *wavWriter) writeHeader() error {
+	blockAlign := w.channels * 2
+	header := make([]byte, wavHeaderSize)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], 36+w.written)
+	copy(header[8:], "WAVE")
+	copy(header[12:], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:], 16)
+	binary.LittleEndian.PutUint16(header[20:], 1)
+	binary.LittleEndian.PutUint16(header[22:], uint16(w.channels))
+	binary.LittleEndian.PutUint32(header[24:], uint32(w.sampleRate))
+	binary.LittleEndian.PutUint32(header[28:], uint32(w.sampleRate*blockAlign))
+	binary.LittleEndian.PutUint16(header[32:], uint16(blockAlign))
+	binary.LittleEndian.PutUint16(header[34:], 16)
+	copy(header[36:], "data")
+	binary.LittleEndian.PutUint32(header[40:], w.written)
+
+	if _, err := w.file.WriteAt(header, 0); err != nil {
+		return fmt.Errorf("failed to write wav header: %w", err)
+	}
+	if _, err := w.file.Seek(int64(wavHeaderSize+w.written), io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek recording file: %w", err)
+	}
+	return nil
+}
+
+func sanitizeRecordingName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+	if name == "" {
+		return "recording"
+	}
+	return name
+}
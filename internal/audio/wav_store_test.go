@@ -0,0 +1,123 @@
+package audio
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"coldmic/internal/ports"
+)
+
+func TestWAVStoreWritesHeaderOnClose(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "recordings")
+	store := NewWAVStore(dir)
+
+	writer, err := store.Create("20260101-120000-Me/Them", ports.AudioConfig{SampleRate: 8000, Channels: 1})
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if _, err := writer.Write([]byte{1, 2, 3, 4}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "20260101-120000-Me_Them.wav"))
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if len(data) != wavHeaderSize+4 || string(data[0:4]) != "RIFF" || string(data[36:40]) != "data" {
+		t.Fatalf("unexpected wav file: %v", data)
+	}
+	if size := binary.LittleEndian.Uint32(data[40:]); size != 4 {
+		t.Fatalf("expected data size 4, got %d", size)
+	}
+	if rate := binary.LittleEndian.Uint32(data[24:]); rate != 8000 {
+		t.Fatalf("expected sample rate 8000, got %d", rate)
+	}
+}
+
+func TestWAVStoreFreeBytesBeforeDirectoryExists(t *testing.T) {
+	t.Parallel()
+
+	store := NewWAVStore(filepath.Join(t.TempDir(), "missing", "recordings"))
+	if free, err := store.FreeBytes(); err != nil || free == 0 {
+		t.Fatalf("expected free space from parent directory, got %d %v", free, err)
+	}
+}
+
+func TestWAVStoreWaveformReadsLatestSessionTracks(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	store := NewWAVStore(dir)
+	write := func(name string, samples ...int16) {
+		t.Helper()
+		writer, err := store.Create(name, ports.AudioConfig{SampleRate: 4, Channels: 1})
+		if err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+		for _, sample := range samples {
+			_ = binary.Write(writer, binary.LittleEndian, sample)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatalf("close failed: %v", err)
+		}
+	}
+	write("20260101-120000-meeting-1-Me", 1000)
+	write("20260102-090000-meeting-1-Me", 0, 16384, -32768, 0, 8192, 0, 0, 0)
+	write("20260102-090000-meeting-1-Them", 0, 0)
+	write("20260102-090000-meeting-10-Me", 1000)
+
+	waveform, err := store.Waveform("meeting-1", 4)
+	if err != nil {
+		t.Fatalf("waveform failed: %v", err)
+	}
+	if len(waveform.Tracks) != 2 || waveform.Tracks[0].Label != "Me" || waveform.Tracks[1].Label != "Them" {
+		t.Fatalf("expected the latest Me and Them tracks, got %+v", waveform.Tracks)
+	}
+	me := waveform.Tracks[0]
+	if me.Duration != 2*time.Second || len(me.Peaks) != 4 || me.Peaks[0] != 0.5 || me.Peaks[1] != 1 || me.Peaks[2] != 0.25 || me.Peaks[3] != 0 {
+		t.Fatalf("unexpected track: %+v", me)
+	}
+	if them := waveform.Tracks[1]; len(them.Peaks) != 2 {
+		t.Fatalf("expected buckets capped at the frame count, got %+v", them)
+	}
+
+	if _, err := store.Waveform("meeting-2", 4); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected not-exist error, got %v", err)
+	}
+}
+
+func TestWAVStorePurgeSinceDeletesRecentRecordings(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	store := NewWAVStore(dir)
+	names := []string{"20260101-235959-meeting-1-Me", "20260102-000000-meeting-2-Me", "20260102-090000-meeting-3-Them", "notes"}
+	for _, name := range names {
+		writer, err := store.Create(name, ports.AudioConfig{})
+		if err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+		_ = writer.Close()
+	}
+
+	purged, err := store.PurgeSince(context.Background(), time.Date(2026, 1, 2, 0, 0, 0, 0, time.Local))
+	if err != nil || purged != 2 {
+		t.Fatalf("expected two purged recordings, got %d %v", purged, err)
+	}
+	for name, kept := range map[string]bool{names[0]: true, names[1]: false, names[2]: false, names[3]: true} {
+		_, err := os.Stat(filepath.Join(dir, name+".wav"))
+		if kept != (err == nil) {
+			t.Fatalf("recording %s: expected kept=%v, got stat error %v", name, kept, err)
+		}
+	}
+}
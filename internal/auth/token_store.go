@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+const secretPrefix = "cm_"
+
+// FileTokenStore keeps SHA-256 hashes of issued tokens in a private JSON file.
+// The file is re-read on every check so tokens created by the desktop app are
+// honored by a running daemon without a restart.
+type FileTokenStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+type storedToken struct {
+	domain.AccessToken
+	Hash string `json:"hash"`
+}
+
+type tokenFile struct {
+	Tokens []storedToken `json:"tokens"`
+}
+
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path}
+}
+
+// Create issues a token and returns its secret, which is not recoverable later.
+func (s *FileTokenStore) Create(_ context.Context, label string, scope domain.TokenScope) (string, domain.AccessToken, error) {
+	if !scope.Valid() {
+		return "", domain.AccessToken{}, fmt.Errorf("unknown token scope %q", scope)
+	}
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", domain.AccessToken{}, fmt.Errorf("failed to generate token: %w", err)
+	}
+	secret := secretPrefix + base64.RawURLEncoding.EncodeToString(raw)
+	hash := hashSecret(secret)
+	token := domain.AccessToken{
+		ID:        hash[:12],
+		Label:     strings.TrimSpace(label),
+		Scope:     scope,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.load()
+	if err != nil {
+		return "", domain.AccessToken{}, err
+	}
+	file.Tokens = append(file.Tokens, storedToken{AccessToken: token, Hash: hash})
+	if err := s.save(file); err != nil {
+		return "", domain.AccessToken{}, err
+	}
+	return secret, token, nil
+}
+
+func (s *FileTokenStore) Verify(_ context.Context, secret string) (domain.AccessToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.load()
+	if err != nil {
+		return domain.AccessToken{}, err
+	}
+	hash := []byte(hashSecret(secret))
+	for _, token := range file.Tokens {
+		if subtle.ConstantTimeCompare(hash, []byte(token.Hash)) == 1 {
+			return token.AccessToken, nil
+		}
+	}
+	return domain.AccessToken{}, domain.ErrInvalidToken
+}
+
+func (s *FileTokenStore) Enforced(_ context.Context) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.load()
+	if err != nil {
+		return false, err
+	}
+	return len(file.Tokens) > 0, nil
+}
+
+func (s *FileTokenStore) load() (tokenFile, error) {
+	var file tokenFile
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return file, nil
+		}
+		return file, fmt.Errorf("failed to read token file: %w", err)
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return file, fmt.Errorf("invalid token file: %w", err)
+	}
+	return file, nil
+}
+
+func (s *FileTokenStore) save(file tokenFile) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create token directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"coldmic/internal/domain"
+)
+
+func TestFileTokenStoreCreateAndVerify(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state", "tokens.json")
+	store := NewFileTokenStore(path)
+
+	if enforced, err := store.Enforced(context.Background()); err != nil || enforced {
+		t.Fatalf("expected no enforcement before tokens exist, got %v %v", enforced, err)
+	}
+
+	secret, token, err := store.Create(context.Background(), " waybar ", domain.TokenScopeStatus)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if !strings.HasPrefix(secret, "cm_") || token.Label != "waybar" || token.Scope != domain.TokenScopeStatus {
+		t.Fatalf("unexpected token: %q %+v", secret, token)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if strings.Contains(string(data), secret) {
+		t.Fatalf("expected only the token hash on disk")
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected private token file, got %v %v", info, err)
+	}
+
+	// A second store instance sees tokens written by the first.
+	verified, err := NewFileTokenStore(path).Verify(context.Background(), secret)
+	if err != nil || verified.ID != token.ID {
+		t.Fatalf("verify failed: %+v %v", verified, err)
+	}
+	if _, err := store.Verify(context.Background(), secret+"x"); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Fatalf("expected invalid token, got %v", err)
+	}
+	if enforced, _ := store.Enforced(context.Background()); !enforced {
+		t.Fatalf("expected enforcement once a token exists")
+	}
+}
+
+func TestFileTokenStoreRejectsUnknownScope(t *testing.T) {
+	t.Parallel()
+
+	store := NewFileTokenStore(filepath.Join(t.TempDir(), "tokens.json"))
+	if _, _, err := store.Create(context.Background(), "x", "admin"); err == nil {
+		t.Fatalf("expected unknown scope error")
+	}
+}
@@ -0,0 +1,137 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEncryptedTargetRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	inner := &memoryTarget{objects: map[string][]byte{}}
+	target := NewEncryptedTarget(inner, "correct horse")
+
+	if err := target.Upload(context.Background(), "bundle", []byte("secret transcript")); err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	if bytes.Contains(inner.objects["bundle"], []byte("secret transcript")) {
+		t.Fatalf("expected ciphertext at rest")
+	}
+
+	plain, err := target.Download(context.Background(), "bundle")
+	if err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	if string(plain) != "secret transcript" {
+		t.Fatalf("unexpected plaintext: %q", plain)
+	}
+
+	if _, err := NewEncryptedTarget(inner, "wrong").Download(context.Background(), "bundle"); err == nil {
+		t.Fatalf("expected wrong passphrase to fail")
+	}
+}
+
+func TestPBKDF2SHA256KnownVector(t *testing.T) {
+	t.Parallel()
+
+	// RFC 7914 section 11 test vector for PBKDF2-HMAC-SHA256.
+	key := pbkdf2SHA256([]byte("passwd"), []byte("salt"), 1, 64)
+	want := "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"
+	if got := hex.EncodeToString(key); got != want {
+		t.Fatalf("unexpected derived key: %s", got)
+	}
+}
+
+func TestWebDAVTargetPutAndGet(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	stored := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "me" || pass != "pw" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			stored[r.URL.Path] = body
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			body, ok := stored[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(body)
+		}
+	}))
+	defer server.Close()
+
+	target := NewWebDAVTarget(WebDAVConfig{BaseURL: server.URL + "/dav/", Username: "me", Password: "pw"})
+	if err := target.Upload(context.Background(), "history.bundle", []byte("data")); err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	if _, ok := stored["/dav/history.bundle"]; !ok {
+		t.Fatalf("expected object under collection, got %v", stored)
+	}
+	data, err := target.Download(context.Background(), "history.bundle")
+	if err != nil || string(data) != "data" {
+		t.Fatalf("unexpected download: %q %v", data, err)
+	}
+	if _, err := target.Download(context.Background(), "missing"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestS3TargetSignsRequests(t *testing.T) {
+	t.Parallel()
+
+	var got *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Clone(context.Background())
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	target := NewS3Target(S3Config{BaseURL: server.URL + "/bucket", Region: "eu-west-1", AccessKey: "AKID", SecretKey: "secret"})
+	target.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	if err := target.Upload(context.Background(), "history.bundle", []byte("data")); err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	if got.Method != http.MethodPut || got.URL.Path != "/bucket/history.bundle" {
+		t.Fatalf("unexpected request %s %s", got.Method, got.URL.Path)
+	}
+	auth := got.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/20260102/eu-west-1/s3/aws4_request") || !strings.Contains(auth, "Signature=") {
+		t.Fatalf("unexpected authorization header: %q", auth)
+	}
+	if got.Header.Get("X-Amz-Date") != "20260102T030405Z" || got.Header.Get("X-Amz-Content-Sha256") != sha256Hex([]byte("data")) {
+		t.Fatalf("unexpected signing headers: %v", got.Header)
+	}
+}
+
+type memoryTarget struct {
+	objects map[string][]byte
+}
+
+func (m *memoryTarget) Upload(_ context.Context, name string, data []byte) error {
+	m.objects[name] = append([]byte(nil), data...)
+	return nil
+}
+
+func (m *memoryTarget) Download(_ context.Context, name string) ([]byte, error) {
+	return m.objects[name], nil
+}
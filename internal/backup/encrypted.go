@@ -0,0 +1,129 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"coldmic/internal/ports"
+)
+
+const (
+	bundleMagic      = "CMB1"
+	saltSize         = 16
+	keyIterations    = 200000
+	encryptionKeyLen = 32
+)
+
+// EncryptedTarget seals objects with AES-256-GCM before handing them to the
+// wrapped target. The key is derived from a passphrase with PBKDF2-SHA256 and
+// a random per-object salt, so the remote side only ever sees ciphertext.
+type EncryptedTarget struct {
+	inner      ports.BackupTarget
+	passphrase []byte
+}
+
+func NewEncryptedTarget(inner ports.BackupTarget, passphrase string) *EncryptedTarget {
+	return &EncryptedTarget{inner: inner, passphrase: []byte(passphrase)}
+}
+
+func (t *EncryptedTarget) Upload(ctx context.Context, name string, data []byte) error {
+	sealed, err := t.seal(data)
+	if err != nil {
+		return err
+	}
+	return t.inner.Upload(ctx, name, sealed)
+}
+
+func (t *EncryptedTarget) Download(ctx context.Context, name string) ([]byte, error) {
+	sealed, err := t.inner.Download(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return t.open(sealed)
+}
+
+func (t *EncryptedTarget) seal(plain []byte) ([]byte, error) {
+	if len(t.passphrase) == 0 {
+		return nil, errors.New("backup passphrase is not configured")
+	}
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	aead, err := t.aead(salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	var out bytes.Buffer
+	out.WriteString(bundleMagic)
+	out.Write(salt)
+	out.Write(nonce)
+	return aead.Seal(out.Bytes(), nonce, plain, []byte(bundleMagic)), nil
+}
+
+func (t *EncryptedTarget) open(sealed []byte) ([]byte, error) {
+	if len(t.passphrase) == 0 {
+		return nil, errors.New("backup passphrase is not configured")
+	}
+	if len(sealed) < len(bundleMagic)+saltSize || string(sealed[:len(bundleMagic)]) != bundleMagic {
+		return nil, errors.New("not a coldmic backup bundle")
+	}
+	salt := sealed[len(bundleMagic) : len(bundleMagic)+saltSize]
+	aead, err := t.aead(salt)
+	if err != nil {
+		return nil, err
+	}
+	rest := sealed[len(bundleMagic)+saltSize:]
+	if len(rest) < aead.NonceSize() {
+		return nil, errors.New("truncated backup bundle")
+	}
+	plain, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], []byte(bundleMagic))
+	if err != nil {
+		return nil, errors.New("failed to decrypt backup: wrong passphrase or corrupt bundle")
+	}
+	return plain, nil
+}
+
+func (t *EncryptedTarget) aead(salt []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2SHA256(t.passphrase, salt, keyIterations, encryptionKeyLen))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// pbkdf2SHA256 implements RFC 8018 PBKDF2 with HMAC-SHA256.
+func pbkdf2SHA256(password []byte, salt []byte, iterations int, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	var key []byte
+	for block := uint32(1); len(key) < keyLen; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		_ = binary.Write(prf, binary.BigEndian, block)
+		u := prf.Sum(nil)
+		t := append([]byte(nil), u...)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
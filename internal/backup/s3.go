@@ -0,0 +1,125 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// S3Config points at an S3-compatible bucket using path-style URLs,
+// e.g. BaseURL "https://s3.eu-central-1.amazonaws.com/my-bucket".
+type S3Config struct {
+	BaseURL   string
+	Region    string
+	AccessKey string
+	SecretKey string
+}
+
+// S3Target implements ports.BackupTarget with SigV4-signed PUT/GET requests.
+type S3Target struct {
+	cfg  S3Config
+	http *http.Client
+	now  func() time.Time
+}
+
+func NewS3Target(cfg S3Config) *S3Target {
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	return &S3Target{cfg: cfg, http: &http.Client{Timeout: 60 * time.Second}, now: time.Now}
+}
+
+func (t *S3Target) Upload(ctx context.Context, name string, data []byte) error {
+	_, err := t.do(ctx, http.MethodPut, name, data)
+	return err
+}
+
+func (t *S3Target) Download(ctx context.Context, name string) ([]byte, error) {
+	return t.do(ctx, http.MethodGet, name, nil)
+}
+
+func (t *S3Target) do(ctx context.Context, method string, name string, payload []byte) ([]byte, error) {
+	endpoint := strings.TrimRight(t.cfg.BaseURL, "/") + "/" + url.PathEscape(name)
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	t.sign(req, payload)
+
+	resp, err := t.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read s3 response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("s3 %s failed: status %d: %s", method, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// sign adds AWS Signature Version 4 headers for the s3 service.
+func (t *S3Target) sign(req *http.Request, payload []byte) {
+	now := t.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + t.cfg.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+t.cfg.SecretKey), day)
+	key = hmacSHA256(key, t.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		t.cfg.AccessKey, scope, signedHeaders, signature,
+	))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
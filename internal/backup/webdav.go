@@ -0,0 +1,71 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// WebDAVConfig points at a WebDAV collection that receives backup objects.
+type WebDAVConfig struct {
+	BaseURL  string
+	Username string
+	Password string
+}
+
+// WebDAVTarget implements ports.BackupTarget with plain PUT/GET requests.
+type WebDAVTarget struct {
+	cfg  WebDAVConfig
+	http *http.Client
+}
+
+func NewWebDAVTarget(cfg WebDAVConfig) *WebDAVTarget {
+	return &WebDAVTarget{cfg: cfg, http: &http.Client{Timeout: 60 * time.Second}}
+}
+
+func (t *WebDAVTarget) Upload(ctx context.Context, name string, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, t.objectURL(name), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	_, err = t.do(req)
+	return err
+}
+
+func (t *WebDAVTarget) Download(ctx context.Context, name string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.objectURL(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	return t.do(req)
+}
+
+func (t *WebDAVTarget) do(req *http.Request) ([]byte, error) {
+	if t.cfg.Username != "" || t.cfg.Password != "" {
+		req.SetBasicAuth(t.cfg.Username, t.cfg.Password)
+	}
+	resp, err := t.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("webdav request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webdav response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("webdav %s failed: status %d", req.Method, resp.StatusCode)
+	}
+	return body, nil
+}
+
+func (t *WebDAVTarget) objectURL(name string) string {
+	return strings.TrimRight(t.cfg.BaseURL, "/") + "/" + url.PathEscape(name)
+}
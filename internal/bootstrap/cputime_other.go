@@ -0,0 +1,9 @@
+//go:build !unix
+
+package bootstrap
+
+import "time"
+
+func cpuTime() time.Duration {
+	return 0
+}
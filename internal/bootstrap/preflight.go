@@ -0,0 +1,32 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+)
+
+// preflightTimeout bounds the startup credential check.
+const preflightTimeout = 5 * time.Second
+
+// Preflight checks the primary provider's credentials when
+// COLDMIC_PREFLIGHT is on, failing only when the provider rejects them. An
+// unreachable provider is logged and left to the first recording, so
+// starting offline still works.
+func Preflight(ctx context.Context, services Services) error {
+	if !services.Config.Session.Preflight || services.Validator == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, preflightTimeout)
+	defer cancel()
+	err := services.Validator.Validate(ctx)
+	var providerErr *domain.ProviderError
+	if err != nil && !errors.As(err, &providerErr) {
+		debuglog.Printf("provider preflight skipped: %v", err)
+		return nil
+	}
+	return err
+}
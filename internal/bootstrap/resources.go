@@ -0,0 +1,49 @@
+package bootstrap
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+// processStart is when the process started, for ResourceUsage.Uptime.
+var processStart = time.Now()
+
+// ResourceMonitor reports what the process costs and which background
+// subsystems are running.
+type ResourceMonitor struct {
+	services Services
+}
+
+// Usage reads memory, goroutine and CPU counters along with the state of
+// each subsystem.
+func (m *ResourceMonitor) Usage() domain.ResourceUsage {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	return domain.ResourceUsage{
+		HeapBytes:  mem.HeapAlloc,
+		SysBytes:   mem.Sys,
+		Goroutines: runtime.NumGoroutine(),
+		CPUTime:    cpuTime(),
+		Uptime:     time.Since(processStart),
+		Subsystems: m.subsystems(),
+	}
+}
+
+func (m *ResourceMonitor) subsystems() []domain.SubsystemUsage {
+	s := m.services
+	workers := s.Batch.Workers()
+	active := s.Session.Status().Active
+	return []domain.SubsystemUsage{
+		{Name: "session", Configured: true, Running: active},
+		{Name: "batch", Configured: true, Running: workers > 0, Detail: fmt.Sprintf("%d workers", workers)},
+		{Name: "watcher", Configured: s.Watcher != nil, Running: s.Watcher != nil && s.Watcher.Running()},
+		{Name: "backup", Configured: s.Backup != nil, Running: s.Backup != nil && s.Backup.Running()},
+		{Name: "network", Configured: s.Network != nil, Running: s.Network != nil && s.Network.Running()},
+		{Name: "trigger", Configured: s.Trigger.Armed(), Running: s.Trigger.Running()},
+		{Name: "midi", Configured: s.MIDI != nil, Running: s.MIDI != nil && s.MIDI.Running()},
+		{Name: "gamepad", Configured: s.Gamepad != nil, Running: s.Gamepad != nil && s.Gamepad.Running()},
+	}
+}
@@ -0,0 +1,104 @@
+package bootstrap
+
+import (
+	"strings"
+	"sync"
+
+	"coldmic/internal/config"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+	"coldmic/internal/usecase"
+)
+
+// TargetSwitcher selects output targets by name for a session controller,
+// together with the provider the target's Deepgram profile asks for. The
+// desktop app and the daemon API share it. It alone installs the dictation
+// provider, so a target switch and a network failover cannot undo each
+// other.
+type TargetSwitcher struct {
+	cfg        config.Config
+	controller *usecase.SessionController
+	primary    ports.TranscriptionProvider
+	eventSink  ports.EventSink
+
+	mu       sync.Mutex
+	active   string
+	target   ports.OutputTarget
+	profile  ports.TranscriptionProvider
+	fallback ports.TranscriptionProvider
+}
+
+func NewTargetSwitcher(cfg config.Config, controller *usecase.SessionController, primary ports.TranscriptionProvider, eventSink ports.EventSink) *TargetSwitcher {
+	return &TargetSwitcher{cfg: cfg, controller: controller, primary: primary, eventSink: eventSink}
+}
+
+// Select formats transcripts finished afterwards for the named target. An
+// unknown name leaves the current target in place.
+func (s *TargetSwitcher) Select(name string) (ports.OutputTarget, error) {
+	target, err := NewTarget(s.cfg, name, s.eventSink)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := TargetProvider(s.cfg, name, s.primary)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.controller.SetTarget(target)
+	s.active = strings.ToLower(strings.TrimSpace(name))
+	s.target = target
+	s.profile = provider
+	s.controller.SetProvider(s.providerLocked())
+	return target, nil
+}
+
+// SetNetworkProvider is the network failover's provider switch. A fallback
+// replaces the selected target's provider until the failover hands back the
+// primary provider, which restores it.
+func (s *TargetSwitcher) SetNetworkProvider(provider ports.TranscriptionProvider) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fallback = provider
+	if provider == s.primary {
+		s.fallback = nil
+	}
+	s.controller.SetProvider(s.providerLocked())
+	return nil
+}
+
+// Provider returns the provider dictation currently uses.
+func (s *TargetSwitcher) Provider() ports.TranscriptionProvider {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.providerLocked()
+}
+
+// providerLocked resolves the dictation provider: the network fallback while
+// the network is degraded, otherwise the target's profile provider. Callers
+// hold s.mu.
+func (s *TargetSwitcher) providerLocked() ports.TranscriptionProvider {
+	switch {
+	case s.fallback != nil:
+		return s.fallback
+	case s.profile != nil:
+		return s.profile
+	default:
+		return s.primary
+	}
+}
+
+// Active returns the selected target; nil copies transcripts unchanged.
+func (s *TargetSwitcher) Active() ports.OutputTarget {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.target
+}
+
+// List returns the built-in and defined targets, marking the selected one.
+func (s *TargetSwitcher) List() []domain.OutputTargetInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cfg.ListTargets(s.active)
+}
@@ -1,19 +1,100 @@
 package bootstrap
 
 import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"coldmic/internal/a11y"
 	"coldmic/internal/audio"
+	"coldmic/internal/auth"
+	"coldmic/internal/backup"
+	"coldmic/internal/cache"
 	"coldmic/internal/config"
+	"coldmic/internal/domain"
+	"coldmic/internal/forms"
+	"coldmic/internal/history"
+	"coldmic/internal/ingest"
+	"coldmic/internal/input"
+	"coldmic/internal/issues"
+	"coldmic/internal/jobs"
+	"coldmic/internal/media"
+	"coldmic/internal/models"
+	"coldmic/internal/netproxy"
+	"coldmic/internal/network"
+	"coldmic/internal/output"
 	"coldmic/internal/ports"
+	"coldmic/internal/providers/assemblyai"
+	"coldmic/internal/providers/azure"
+	"coldmic/internal/providers/customws"
 	"coldmic/internal/providers/deepgram"
+	"coldmic/internal/providers/gladia"
+	"coldmic/internal/providers/groq"
+	"coldmic/internal/providers/openai"
+	"coldmic/internal/providers/replay"
+	"coldmic/internal/providers/whispercpp"
+	"coldmic/internal/reminders"
 	"coldmic/internal/rules"
+	"coldmic/internal/tasks"
+	"coldmic/internal/translate"
+	"coldmic/internal/usage"
 	"coldmic/internal/usecase"
 )
 
 // Services is the assembled runtime graph.
 type Services struct {
+	// Provider is the primary live transcription provider.
+	Provider ports.TranscriptionProvider
+	// Validator checks the primary provider's credentials; nil when the
+	// provider cannot.
+	Validator ports.ProviderValidator
+	// Models downloads models for local providers.
+	Models     *models.Manager
 	Controller *usecase.SessionController
 	Session    *usecase.SessionService
-	Config     config.Config
+	Corrector  *usecase.Corrector
+	Meeting    *usecase.MeetingController
+	Files      *usecase.FileTranscriber
+	URLs       *usecase.URLTranscriber
+	History    ports.HistoryStore
+	Tokens     ports.AccessTokenStore
+	Forms      ports.FormSchemaStore
+	// Batch transcribes watch-folder and bulk file jobs; callers must Run it.
+	Batch *usecase.BatchPool
+	// Watcher is nil unless COLDMIC_WATCH_DIR is configured.
+	Watcher *usecase.FolderWatcher
+	// Backup is nil unless COLDMIC_BACKUP_TARGET is configured.
+	Backup *usecase.HistoryBackup
+	// Network is nil when COLDMIC_NETWORK_CHECK_MS is 0.
+	Network *usecase.NetworkFailover
+	// Copies announces copied transcripts; callers may Subscribe more listeners.
+	Copies *usecase.CopyBus
+	// Waveforms reads peaks from saved meeting recordings.
+	Waveforms ports.WaveformReader
+	// Playback plays saved meeting recordings.
+	Playback *usecase.AudioPlayback
+	// Revisions edits history entries and compares their pipeline stages.
+	Revisions *usecase.TranscriptHistory
+	// Wipe is the emergency panic wipe.
+	Wipe *usecase.PanicWipe
+	// Targets selects the output target, starting with COLDMIC_TARGET.
+	Targets *TargetSwitcher
+	// Hotkeys runs the hotkeys from the hotkeys file.
+	Hotkeys *usecase.HotkeyDispatcher
+	// Trigger runs push-to-talk from COLDMIC_TRIGGER_DEVICE; callers must Run
+	// it, and it idles until a device is configured or learned.
+	Trigger *usecase.ButtonTrigger
+	// MIDI is nil unless COLDMIC_MIDI_DEVICE is configured.
+	MIDI *usecase.MIDITrigger
+	// Gamepad is nil unless COLDMIC_GAMEPAD_DEVICE is configured.
+	Gamepad *usecase.ButtonTrigger
+	// Resources reports process resource usage and running subsystems.
+	Resources *ResourceMonitor
+	// Usage prices provider audio and keeps the monthly totals.
+	Usage  *usecase.UsageMeter
+	Config config.Config
 }
 
 // Build wires all backend dependencies for the current runtime.
@@ -22,45 +103,974 @@ func Build(eventSink ports.EventSink, clipboard ports.Clipboard) (Services, erro
 	if err != nil {
 		return Services{}, err
 	}
+	return build(cfg, eventSink, clipboard)
+}
+
+// BuildWorkspace wires a fresh service graph scoped to the named workspace.
+func BuildWorkspace(eventSink ports.EventSink, clipboard ports.Clipboard, workspace string) (Services, error) {
+	cfg, err := config.LoadWorkspace(workspace)
+	if err != nil {
+		return Services{}, err
+	}
+	return build(cfg, eventSink, clipboard)
+}
+
+func build(cfg config.Config, eventSink ports.EventSink, clipboard ports.Clipboard) (Services, error) {
+	if err := config.AuditPermissions(cfg); err != nil {
+		return Services{}, err
+	}
+	if cfg.Network.Proxy != "" {
+		if _, err := netproxy.Parse(cfg.Network.Proxy); err != nil {
+			return Services{}, fmt.Errorf("COLDMIC_PROXY: %w", err)
+		}
+	}
+
+	rulesEngine, err := rules.NewLanguageEngine(cfg.Rules.Path, cfg.Rules.IterationLimit)
+	if err != nil {
+		return Services{}, err
+	}
 
-	rulesEngine, err := rules.NewEngine(cfg.Rules.Path, cfg.Rules.IterationLimit)
+	catalog, err := models.LoadCatalog(cfg.Storage.ModelCatalog, models.DefaultCatalog)
+	if err != nil {
+		return Services{}, err
+	}
+	modelManager := models.NewManager(cfg.Storage.ModelsDir, catalog, modelProgressSink(eventSink))
+	cfg.WhisperCpp.ModelPath = localModelPath(modelManager, cfg.WhisperCpp.ModelPath)
+
+	provider, err := newPrimaryProvider(cfg)
+	if err != nil {
+		return Services{}, err
+	}
+	validator, _ := provider.(ports.ProviderValidator)
+	provider, err = withRace(cfg, provider)
+	if err != nil {
+		return Services{}, err
+	}
+	provider, err = withLocalRedaction(cfg, provider)
 	if err != nil {
 		return Services{}, err
 	}
+	if err := checkCapabilities(cfg, Capabilities(cfg, provider)); err != nil {
+		return Services{}, err
+	}
+	sessionCfg := usecase.Config{
+		Audio: ports.AudioConfig{
+			SampleRate:       cfg.Audio.SampleRate,
+			Channels:         cfg.Audio.Channels,
+			InputFormat:      cfg.Audio.InputFormat,
+			InputDevice:      cfg.Audio.InputDevice,
+			DeviceSampleRate: cfg.Audio.DeviceSampleRate,
+			DeviceChannels:   cfg.Audio.DeviceChannels,
+		},
+		Streaming: ports.StreamingConfig{
+			SampleRate:     cfg.Audio.SampleRate,
+			Channels:       cfg.Audio.Channels,
+			Encoding:       "linear16",
+			InterimResults: true,
+			Keywords:       cfg.Session.Keywords,
+			Multichannel:   multichannel(cfg),
+		},
+		Channels: usecase.ChannelConfig{
+			Mode:   usecase.ChannelMode(cfg.Audio.Multichannel),
+			Labels: cfg.Audio.ChannelLabels,
+		},
+		ChunkSize:      cfg.Session.ChunkSize,
+		StreamingGrace: cfg.Session.StreamingGrace,
+		Translation: usecase.TranslationConfig{
+			TargetLanguage: cfg.Translation.TargetLanguage,
+		},
+		Timestamps: usecase.TimestampConfig{
+			Mode:     usecase.TimestampMode(cfg.Timestamps.Mode),
+			Template: cfg.Timestamps.Template,
+			Layout:   cfg.Timestamps.Layout,
+		},
+		Trim: usecase.SilenceTrimConfig{
+			Enabled:   cfg.Trim.Enabled,
+			Threshold: cfg.Trim.Threshold,
+			Padding:   cfg.Trim.Padding,
+		},
+		IdleAbort: usecase.IdleAbortConfig{
+			Timeout:   cfg.Session.IdleAbort,
+			Threshold: cfg.Session.IdleThreshold,
+		},
+		Language: ProviderLanguage(cfg),
+		IncrementalCopy: usecase.IncrementalCopyConfig{
+			Enabled:  cfg.Session.IncrementalCopy,
+			Interval: cfg.Session.CopyInterval,
+		},
+	}
+
+	// Overflow wraps the clipboard itself, so split pieces are limited one by one.
+	overflowDir := output.NewOverflowDir(cfg.Clipboard.OverflowDir)
+	if cfg.Clipboard.MaxChars > 0 {
+		clipboard = usecase.NewOverflowClipboard(clipboard, overflowDir, usecase.ClipboardOverflowConfig{
+			MaxChars: cfg.Clipboard.MaxChars,
+			Mode:     usecase.ClipboardOverflowMode(cfg.Clipboard.Overflow),
+		})
+	}
+	if cfg.Clipboard.Split != string(usecase.ClipboardSplitOff) {
+		var pieces ports.Clipboard
+		if len(cfg.Clipboard.SplitCommand) > 0 {
+			pieces = output.NewCommandSink(cfg.Clipboard.SplitCommand)
+		}
+		clipboard = usecase.NewSplittingClipboard(clipboard, pieces, usecase.ClipboardSplitConfig{
+			Mode:  usecase.ClipboardSplitMode(cfg.Clipboard.Split),
+			Delay: cfg.Clipboard.SplitDelay,
+		})
+	}
 
+	capture := newCapture(cfg.Audio)
+	// Only the recording controllers announce; optional sink interfaces are
+	// still detected on eventSink itself.
+	sessionEvents := eventSink
+	if announcer := buildAnnouncer(cfg.Accessibility); announcer != nil {
+		sessionEvents = usecase.NewAnnouncingEventSink(eventSink, announcer, usecase.AnnounceVerbosity(cfg.Accessibility.Verbosity))
+	}
 	controller := usecase.NewSessionController(
-		audio.NewFFMPEGCapture(cfg.Audio.RecorderCommand),
-		deepgram.NewProvider(deepgram.Config{
-			APIKey:      cfg.Deepgram.APIKey,
-			APIBaseURL:  cfg.Deepgram.APIBaseURL,
-			Model:       cfg.Deepgram.Model,
-			Language:    cfg.Deepgram.Language,
-			SmartFormat: cfg.Deepgram.SmartFormat,
-		}),
+		capture,
+		provider,
 		rulesEngine,
 		clipboard,
-		eventSink,
-		usecase.Config{
-			Audio: ports.AudioConfig{
-				SampleRate:  cfg.Audio.SampleRate,
-				Channels:    cfg.Audio.Channels,
-				InputFormat: cfg.Audio.InputFormat,
-				InputDevice: cfg.Audio.InputDevice,
-			},
-			Streaming: ports.StreamingConfig{
-				SampleRate:     cfg.Audio.SampleRate,
-				Channels:       cfg.Audio.Channels,
-				Encoding:       "linear16",
-				InterimResults: true,
-			},
-			ChunkSize:      cfg.Session.ChunkSize,
-			StreamingGrace: cfg.Session.StreamingGrace,
+		sessionEvents,
+		sessionCfg,
+	)
+	if captions, ok := eventSink.(ports.CaptionSink); ok && cfg.Translation.TargetLanguage != "" {
+		controller.SetTranslator(translate.NewLibreTranslator(translate.Config{
+			APIBaseURL:     cfg.Translation.APIBaseURL,
+			APIKey:         cfg.Translation.APIKey,
+			SourceLanguage: cfg.Translation.SourceLanguage,
+		}), captions)
+	}
+
+	if spans, ok := eventSink.(ports.SpanSink); ok {
+		controller.SetSpanSink(spans)
+	}
+	if cfg.Accurate.Model != "" {
+		accurate, err := accurateProvider(cfg)
+		if err != nil {
+			return Services{}, err
+		}
+		controller.SetAccurateProvider(accurate)
+	}
+	if cfg.Retry.MinConfidence > 0 {
+		retry, err := retryProvider(cfg)
+		if err != nil {
+			return Services{}, err
+		}
+		controller.SetRetry(retry, confidenceSink(eventSink), usecase.RetryConfig{MinConfidence: cfg.Retry.MinConfidence})
+	}
+
+	formStore := forms.NewDirStore(cfg.Forms.Dir)
+	if cfg.Forms.Active != "" {
+		schema, err := formStore.Load(cfg.Forms.Active)
+		if err != nil {
+			return Services{}, err
+		}
+		if err := controller.SetForm(&schema); err != nil {
+			return Services{}, err
+		}
+	}
+
+	var recordingHooks []ports.RecordingHook
+	if cfg.Session.PauseMedia {
+		recordingHooks = append(recordingHooks, media.NewMPRISPauser(""))
+	}
+	switch cfg.Session.DuckStreams {
+	case media.DuckModeDuck, media.DuckModeMute:
+		recordingHooks = append(recordingHooks, media.NewStreamDucker("", cfg.Session.DuckStreams, cfg.Session.DuckLevel))
+	}
+	if len(cfg.Session.RecordingLEDs) > 0 {
+		led, err := media.NewRecordingLED(cfg.Session.RecordingLEDs, cfg.Session.RecordingLEDColor)
+		if err != nil {
+			return Services{}, err
+		}
+		recordingHooks = append(recordingHooks, led)
+	}
+	if len(cfg.Session.PreStartHook) > 0 || len(cfg.Session.PostStopHook) > 0 {
+		recordingHooks = append(recordingHooks, output.NewRecordingCommandHook(cfg.Session.PreStartHook, cfg.Session.PostStopHook, cfg.Session.RecordingHookTimeout))
+	}
+	controller.SetRecordingHooks(recordingHooks...)
+	usageMeter := usecase.NewUsageMeter(usage.NewFileStore(cfg.Usage.Path), cfg.Usage.Rates, nil)
+	controller.SetUsage(usageMeter)
+	controller.SetCasing(casingConfig(cfg.Casing))
+	controller.SetNormalize(normalizeConfig(cfg.Normalize))
+
+	targets := NewTargetSwitcher(cfg, controller, provider, eventSink)
+	if _, err := targets.Select(cfg.Target.Name); err != nil {
+		return Services{}, err
+	}
+
+	desktopAudio := sessionCfg.Audio
+	desktopAudio.InputDevice = cfg.Meeting.DesktopDevice
+	meeting := usecase.NewMeetingController(capture, provider, rulesEngine, clipboard, sessionEvents, usecase.MeetingConfig{
+		Tracks: []usecase.MeetingTrack{
+			{Label: cfg.Meeting.MicLabel, Audio: sessionCfg.Audio},
+			{Label: cfg.Meeting.DesktopLabel, Audio: desktopAudio},
+		},
+		Streaming:      sessionCfg.Streaming,
+		ChunkSize:      sessionCfg.ChunkSize,
+		StreamingGrace: sessionCfg.StreamingGrace,
+		Timestamps:     sessionCfg.Timestamps,
+		DiskGuard: usecase.DiskGuardConfig{
+			MinFreeBytes: uint64(cfg.Storage.MinFreeMB) << 20,
 		},
+		Language: sessionCfg.Language,
+	})
+	recordings := audio.NewWAVStore(cfg.Storage.RecordingsDir)
+	if cfg.Storage.SaveAudio {
+		meeting.SetRecordingStore(recordings)
+	}
+
+	copies := usecase.NewCopyBus(eventSink)
+	if listener, ok := eventSink.(ports.CopyListener); ok {
+		copies.Subscribe(listener)
+	}
+	for _, hook := range cfg.Clipboard.CopyHooks {
+		copies.Subscribe(output.NewCopyHook(hook, cfg.Clipboard.CopyHookTimeout))
+	}
+	controller.SetCopyListener(copies)
+	meeting.SetCopyListener(copies)
+
+	historyStore, err := newHistoryStore(cfg.Storage)
+	if err != nil {
+		return Services{}, err
+	}
+	files := usecase.NewFileTranscriber(
+		audio.NewFFMPEGDecoder(cfg.Audio.RecorderCommand),
+		provider,
+		rulesEngine,
+		sessionCfg,
 	)
+	transcriptCache := cache.NewDirCache(cfg.Storage.CacheDir, int64(cfg.Storage.CacheMaxMB)<<20)
+	if cfg.Storage.CacheMaxMB > 0 {
+		files.SetCache(transcriptCache, cacheFingerprint(cfg, provider, sessionCfg.Trim))
+	}
+
+	batch := usecase.NewBatchPool(files, historyStore, fileJobSink(eventSink), jobs.NewFileStore(cfg.Storage.JobsPath), usecase.BatchConfig{
+		Concurrency: cfg.Batch.Concurrency,
+	})
+	var watcher *usecase.FolderWatcher
+	if cfg.Watch.Dir != "" {
+		watcher = usecase.NewFolderWatcher(batch, usecase.WatchConfig{
+			Dir:       cfg.Watch.Dir,
+			OutputDir: cfg.Watch.OutputDir,
+			Interval:  cfg.Watch.Interval,
+		})
+	}
+
+	var failover *usecase.NetworkFailover
+	if cfg.Network.CheckInterval > 0 {
+		var fallback ports.TranscriptionProvider
+		if cfg.Network.FallbackProvider != "" {
+			if fallback, err = fallbackProvider(cfg); err != nil {
+				return Services{}, err
+			}
+		}
+		failover = usecase.NewNetworkFailover(
+			network.NewNetworkManagerMonitor(""),
+			networkSink(eventSink),
+			usecase.NetworkConfig{
+				Interval:          cfg.Network.CheckInterval,
+				FallbackOnMetered: cfg.Network.FallbackOnMetered,
+			},
+			provider,
+			fallback,
+			targets.SetNetworkProvider,
+			meeting.SetProvider,
+		)
+	}
+
+	historyBackup, err := buildHistoryBackup(cfg.Backup, historyStore)
+	if err != nil {
+		return Services{}, err
+	}
+
+	session := usecase.NewSessionService(controller)
+	hotkeys := usecase.NewHotkeyDispatcher(cfg.Hotkeys, session, targets, clipboard)
+	var midi *usecase.MIDITrigger
+	if cfg.Trigger.MIDIDevice != "" {
+		midi = usecase.NewMIDITrigger(input.NewRawMIDI(cfg.Trigger.MIDIDevice), cfg.MIDI, hotkeys, eventSink)
+	}
+	gamepad, err := buildGamepad(cfg.Trigger, session, eventSink)
+	if err != nil {
+		return Services{}, err
+	}
+	corrector := usecase.NewCorrector(session, clipboard, rulesEngine, history.NewCorrectionLog(cfg.Storage.CorrectionsPath), eventSink)
 
-	return Services{
+	services := Services{
+		Provider:   provider,
+		Validator:  validator,
+		Models:     modelManager,
 		Controller: controller,
-		Session:    usecase.NewSessionService(controller),
+		Session:    session,
+		Corrector:  corrector,
+		Meeting:    meeting,
+		Files:      files,
+		URLs:       usecase.NewURLTranscriber(ingest.NewYTDLPDownloader(cfg.Ingest.DownloaderCommand), files, historyStore, ""),
+		History:    historyStore,
+		Revisions:  usecase.NewTranscriptHistory(historyStore, rulesEngine),
+		Wipe:       panicWipe(cfg, session, meeting, clipboard, historyStore, recordings, overflowDir, transcriptCache),
+		Tokens:     auth.NewFileTokenStore(cfg.Storage.TokensPath),
+		Forms:      formStore,
+		Batch:      batch,
+		Watcher:    watcher,
+		Backup:     historyBackup,
+		Network:    failover,
+		Copies:     copies,
+		Waveforms:  recordings,
+		Playback:   usecase.NewAudioPlayback(recordings, audio.NewFFMPEGPlayer(cfg.Audio.RecorderCommand, cfg.Audio.PlaybackSink), playbackSink(eventSink)),
+		Targets:    targets,
+		Hotkeys:    hotkeys,
+		Trigger:    usecase.NewButtonTrigger(session, eventSink, usecase.ButtonHold, TriggerSource(cfg.Trigger)),
+		MIDI:       midi,
+		Gamepad:    gamepad,
+		Usage:      usageMeter,
 		Config:     cfg,
-	}, nil
+	}
+	services.Resources = &ResourceMonitor{services: services}
+	return services, nil
+}
+
+// newHistoryStore opens the COLDMIC_HISTORY_BACKEND history store.
+func newHistoryStore(cfg config.StorageConfig) (ports.HistoryStore, error) {
+	switch cfg.HistoryBackend {
+	case "sqlite":
+		return history.NewSQLiteStore(cfg.HistoryPath)
+	case "markdown":
+		return history.NewMarkdownStore(cfg.HistoryPath), nil
+	default:
+		return history.NewJSONLStore(cfg.HistoryPath), nil
+	}
+}
+
+// panicWipe purges today's history, recordings, overflow files and cached
+// transcripts only with COLDMIC_PANIC_PURGE.
+func panicWipe(cfg config.Config, session *usecase.SessionService, meeting *usecase.MeetingController, clipboard ports.Clipboard, historyStore ports.HistoryStore, recordings ports.Purger, overflow ports.Purger, transcripts ports.Purger) *usecase.PanicWipe {
+	if !cfg.Storage.PanicPurge {
+		return usecase.NewPanicWipe(session, meeting, clipboard, nil, nil)
+	}
+	purger, _ := historyStore.(ports.Purger)
+	wipe := usecase.NewPanicWipe(session, meeting, clipboard, purger, recordings)
+	wipe.SetFilePurgers(overflow, transcripts)
+	return wipe
+}
+
+// TriggerSource opens the configured trigger button, or nil without a device.
+func TriggerSource(cfg config.TriggerConfig) ports.ButtonSource {
+	if cfg.Device == "" {
+		return nil
+	}
+	return input.NewEvdevButton(cfg.Device, cfg.KeyCode)
+}
+
+// buildGamepad returns a trigger toggling dictation from the configured
+// gamepad button, or nil without a gamepad.
+func buildGamepad(cfg config.TriggerConfig, session usecase.ButtonSession, eventSink ports.EventSink) (*usecase.ButtonTrigger, error) {
+	if cfg.GamepadDevice == "" {
+		return nil, nil
+	}
+	code, err := input.GamepadButtonCode(cfg.GamepadButton)
+	if err != nil {
+		return nil, fmt.Errorf("COLDMIC_GAMEPAD_BUTTON: %w", err)
+	}
+	return usecase.NewButtonTrigger(session, eventSink, usecase.ButtonToggle, input.NewEvdevButton(cfg.GamepadDevice, code)), nil
+}
+
+// Capabilities describes the primary provider configured in cfg.
+func Capabilities(cfg config.Config, provider ports.TranscriptionProvider) domain.Capabilities {
+	var capabilities domain.Capabilities
+	if reporter, ok := provider.(ports.CapabilityReporter); ok {
+		capabilities = reporter.Capabilities()
+	}
+	capabilities.Provider = cfg.Provider
+	if capabilities.Provider == "" {
+		capabilities.Provider = "deepgram"
+	}
+	return capabilities
+}
+
+// multichannel reports whether each captured channel is transcribed
+// separately.
+func multichannel(cfg config.Config) bool {
+	return cfg.Audio.Multichannel != "off" && cfg.Audio.Multichannel != "" && cfg.Audio.Channels > 1
+}
+
+// checkCapabilities rejects options the provider cannot honour, which would
+// otherwise be silently ignored.
+func checkCapabilities(cfg config.Config, capabilities domain.Capabilities) error {
+	name := capabilities.Provider
+	if cfg.Timestamps.Mode != "" && cfg.Timestamps.Mode != string(usecase.TimestampModeOff) && !capabilities.Timestamps {
+		return fmt.Errorf("COLDMIC_TIMESTAMPS=%s needs utterance timing, which provider %s does not report", cfg.Timestamps.Mode, name)
+	}
+	if cfg.Retry.MinConfidence > 0 && !capabilities.Confidence {
+		return fmt.Errorf("COLDMIC_MIN_CONFIDENCE needs transcript confidence, which provider %s does not report", name)
+	}
+	if multichannel(cfg) && !capabilities.Multichannel {
+		return fmt.Errorf("COLDMIC_MULTICHANNEL=%s is not supported by provider %s", cfg.Audio.Multichannel, name)
+	}
+	if len(cfg.Session.Keywords) > 0 && !capabilities.Keywords {
+		return fmt.Errorf("COLDMIC_KEYWORDS is not supported by provider %s", name)
+	}
+	if language := ProviderLanguage(cfg); language != "" && len(capabilities.Languages) > 0 && !supportsLanguage(capabilities.Languages, language) {
+		return fmt.Errorf("language %q is not supported by provider %s (supported: %s)", language, name, strings.Join(capabilities.Languages, ", "))
+	}
+	return nil
+}
+
+// ProviderModel returns the model configured for the primary provider, or
+// "" when it takes none.
+func ProviderModel(cfg config.Config) string {
+	switch cfg.Provider {
+	case "", "deepgram":
+		return cfg.Deepgram.Model
+	case "assemblyai":
+		return cfg.AssemblyAI.Model
+	case "openai":
+		return cfg.OpenAI.Model
+	case "groq":
+		return cfg.Groq.Model
+	case "whispercpp":
+		if cfg.WhisperCpp.ModelPath == "" {
+			return ""
+		}
+		return filepath.Base(cfg.WhisperCpp.ModelPath)
+	default:
+		return ""
+	}
+}
+
+// ProviderLanguage returns the language configured for the primary provider.
+func ProviderLanguage(cfg config.Config) string {
+	switch cfg.Provider {
+	case "", "deepgram":
+		return cfg.Deepgram.Language
+	case "azure":
+		return cfg.Azure.Language
+	case "gladia":
+		return cfg.Gladia.Language
+	case "openai":
+		return cfg.OpenAI.Language
+	case "groq":
+		return cfg.Groq.Language
+	case "whispercpp":
+		return cfg.WhisperCpp.Language
+	default:
+		return ""
+	}
+}
+
+// supportsLanguage matches on the base language, so en-US matches en.
+func supportsLanguage(languages []string, language string) bool {
+	base, _, _ := strings.Cut(strings.ToLower(language), "-")
+	for _, supported := range languages {
+		if supported == base || strings.EqualFold(supported, language) {
+			return true
+		}
+	}
+	return false
+}
+
+// newPrimaryProvider builds the live provider named by cfg.Provider. Retry
+// and accurate-pass providers stay on Deepgram.
+func newPrimaryProvider(cfg config.Config) (ports.TranscriptionProvider, error) {
+	switch cfg.Provider {
+	case "", "deepgram":
+		return NewProvider(cfg.Deepgram), nil
+	case "assemblyai":
+		return assemblyai.NewProvider(assemblyai.Config{
+			APIKey:      cfg.AssemblyAI.APIKey,
+			APIBaseURL:  cfg.AssemblyAI.APIBaseURL,
+			Model:       cfg.AssemblyAI.Model,
+			SmartFormat: cfg.AssemblyAI.SmartFormat,
+			Proxy:       cfg.Network.Proxy,
+		}), nil
+	case "azure":
+		return azure.NewProvider(azure.Config{
+			APIKey:     cfg.Azure.APIKey,
+			Region:     cfg.Azure.Region,
+			APIBaseURL: cfg.Azure.APIBaseURL,
+			Language:   cfg.Azure.Language,
+			Proxy:      cfg.Network.Proxy,
+		}), nil
+	case "customws":
+		return customws.NewProvider(customws.Config{
+			URL:    cfg.CustomWS.URL,
+			Header: cfg.CustomWS.Header,
+			Proxy:  cfg.Network.Proxy,
+		}), nil
+	case "replay":
+		return replay.NewProvider(replay.Config{
+			Path:  cfg.Replay.Path,
+			Speed: cfg.Replay.Speed,
+		}), nil
+	case "gladia":
+		return gladia.NewProvider(gladia.Config{
+			APIKey:     cfg.Gladia.APIKey,
+			APIBaseURL: cfg.Gladia.APIBaseURL,
+			Language:   cfg.Gladia.Language,
+			Proxy:      cfg.Network.Proxy,
+		}), nil
+	case "openai":
+		return openai.NewProvider(openai.Config{
+			APIKey:     cfg.OpenAI.APIKey,
+			APIBaseURL: cfg.OpenAI.APIBaseURL,
+			Model:      cfg.OpenAI.Model,
+			Language:   cfg.OpenAI.Language,
+			Proxy:      cfg.Network.Proxy,
+		}), nil
+	case "groq":
+		return groq.NewProvider(groq.Config{
+			APIKey:     cfg.Groq.APIKey,
+			APIBaseURL: cfg.Groq.APIBaseURL,
+			Model:      cfg.Groq.Model,
+			Language:   cfg.Groq.Language,
+			Proxy:      cfg.Network.Proxy,
+		}), nil
+	case "whispercpp":
+		return whispercpp.NewProvider(whispercpp.Config{
+			Command:      cfg.WhisperCpp.Command,
+			ModelPath:    cfg.WhisperCpp.ModelPath,
+			Language:     cfg.WhisperCpp.Language,
+			Threads:      cfg.WhisperCpp.Threads,
+			Acceleration: cfg.WhisperCpp.Acceleration,
+			Device:       cfg.WhisperCpp.Device,
+			Step:         cfg.WhisperCpp.Step,
+			Window:       cfg.WhisperCpp.Window,
+			Overlap:      cfg.WhisperCpp.Overlap,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown transcription provider %q", cfg.Provider)
+	}
+}
+
+// providerConfig returns the configuration newPrimaryProvider builds the
+// named provider from.
+func providerConfig(cfg config.Config, name string) any {
+	switch name {
+	case "", "deepgram":
+		return cfg.Deepgram
+	case "assemblyai":
+		return cfg.AssemblyAI
+	case "azure":
+		return cfg.Azure
+	case "customws":
+		return cfg.CustomWS
+	case "replay":
+		return cfg.Replay
+	case "gladia":
+		return cfg.Gladia
+	case "openai":
+		return cfg.OpenAI
+	case "groq":
+		return cfg.Groq
+	case "whispercpp":
+		return cfg.WhisperCpp
+	default:
+		return nil
+	}
+}
+
+// cacheFingerprint identifies every setting that shapes a file transcript:
+// the provider with its full configuration, any race partner, local
+// redaction, the audio format and trimming.
+func cacheFingerprint(cfg config.Config, provider ports.TranscriptionProvider, trim usecase.SilenceTrimConfig) string {
+	var race any
+	if cfg.Race.Provider != "" {
+		race = providerConfig(cfg, cfg.Race.Provider)
+	}
+	return fmt.Sprintf(
+		"%s|%+v|%+v|%+v|%+v|%d|%d|%+v",
+		Capabilities(cfg, provider).Provider,
+		providerConfig(cfg, cfg.Provider),
+		cfg.Race,
+		race,
+		cfg.Redaction,
+		cfg.Audio.SampleRate,
+		cfg.Audio.Channels,
+		trim,
+	)
+}
+
+// newCapture records with ffmpeg, with libpulse or PortAudio for the
+// "libpulse" and "portaudio" input formats, or produces silence for the
+// "silence" input format used with the replay provider.
+func newCapture(cfg config.AudioConfig) ports.AudioCapture {
+	switch cfg.InputFormat {
+	case "silence":
+		return audio.NewSilenceCapture()
+	case audio.PulseInputFormat:
+		return audio.NewPulseCapture()
+	case audio.PortAudioInputFormat:
+		return audio.NewPortAudioCapture()
+	}
+	capture := audio.NewFFMPEGCapture(cfg.RecorderCommand)
+	switch cfg.InputFormat {
+	case audio.DShowInputFormat:
+		capture.SetDeviceEnumerator(audio.NewDShowDevices(cfg.RecorderCommand))
+	case audio.AVFoundationInputFormat:
+		capture.SetDeviceEnumerator(audio.NewAVFoundationDevices(cfg.RecorderCommand))
+	default:
+		capture.SetDeviceEnumerator(audio.NewPulseDevices(""))
+	}
+	return capture
+}
+
+// withRace races primary against COLDMIC_RACE_PROVIDER when it is set.
+func withRace(cfg config.Config, primary ports.TranscriptionProvider) (ports.TranscriptionProvider, error) {
+	if cfg.Race.Provider == "" {
+		return primary, nil
+	}
+	pick := usecase.RacePick(cfg.Race.Pick)
+	if pick != usecase.RacePickConfidence && pick != usecase.RacePickFirst {
+		return nil, fmt.Errorf("unsupported COLDMIC_RACE_PICK %q (expected confidence or first)", cfg.Race.Pick)
+	}
+	raceCfg := cfg
+	raceCfg.Provider = cfg.Race.Provider
+	secondary, err := newPrimaryProvider(raceCfg)
+	if err != nil {
+		return nil, err
+	}
+	return usecase.NewRaceProvider(primary, secondary, usecase.RaceConfig{
+		Pick:  pick,
+		Names: [2]string{Capabilities(cfg, primary).Provider, cfg.Race.Provider},
+	}), nil
+}
+
+// withLocalRedaction screens a cloud provider's audio with local whisper.cpp
+// when COLDMIC_REDACT_LOCAL is set. The local provider needs no screening.
+func withLocalRedaction(cfg config.Config, provider ports.TranscriptionProvider) (ports.TranscriptionProvider, error) {
+	mode := usecase.LocalRedactionMode(cfg.Redaction.Mode)
+	switch mode {
+	case "", usecase.LocalRedactionOff:
+		return provider, nil
+	case usecase.LocalRedactionBleep, usecase.LocalRedactionSkip:
+	default:
+		return nil, fmt.Errorf("unsupported COLDMIC_REDACT_LOCAL %q (expected off, bleep or skip)", cfg.Redaction.Mode)
+	}
+	if cfg.Provider == "whispercpp" {
+		return provider, nil
+	}
+	if strings.TrimSpace(cfg.WhisperCpp.ModelPath) == "" {
+		return nil, errors.New("COLDMIC_REDACT_LOCAL requires COLDMIC_WHISPERCPP_MODEL")
+	}
+	screen := whispercpp.NewProvider(whispercpp.Config{
+		Command:      cfg.WhisperCpp.Command,
+		ModelPath:    cfg.WhisperCpp.ModelPath,
+		Language:     cfg.WhisperCpp.Language,
+		Threads:      cfg.WhisperCpp.Threads,
+		Acceleration: cfg.WhisperCpp.Acceleration,
+		Device:       cfg.WhisperCpp.Device,
+	})
+	return usecase.NewLocalRedactingProvider(provider, screen, usecase.LocalRedactionConfig{
+		Mode:      mode,
+		Segment:   cfg.Redaction.Segment,
+		MinDigits: cfg.Redaction.MinDigits,
+	}), nil
+}
+
+// DeepgramProvider builds a Deepgram provider from deepgramCfg, screened with
+// local whisper.cpp when COLDMIC_REDACT_LOCAL is set, whatever the primary
+// provider is. A dry run refuses, so no audio reaches Deepgram.
+func DeepgramProvider(cfg config.Config, deepgramCfg config.DeepgramConfig) (ports.TranscriptionProvider, error) {
+	if cfg.DryRun {
+		return nil, errors.New("COLDMIC_DRY_RUN: Deepgram providers are disabled in a dry run")
+	}
+	cloud := cfg
+	cloud.Provider = "deepgram"
+	return withLocalRedaction(cloud, NewProvider(deepgramCfg))
+}
+
+// accurateProvider builds the Deepgram provider for the accurate pass.
+func accurateProvider(cfg config.Config) (ports.TranscriptionProvider, error) {
+	accurateCfg := cfg.Deepgram
+	accurateCfg.Model = cfg.Accurate.Model
+	if cfg.Accurate.APIBaseURL != "" {
+		accurateCfg.APIBaseURL = cfg.Accurate.APIBaseURL
+	}
+	return DeepgramProvider(cfg, accurateCfg)
+}
+
+// retryProvider builds the Deepgram provider for low-confidence retries.
+func retryProvider(cfg config.Config) (ports.TranscriptionProvider, error) {
+	retryCfg := cfg.Deepgram
+	retryCfg.Model = cfg.Retry.Model
+	if cfg.Retry.APIBaseURL != "" {
+		retryCfg.APIBaseURL = cfg.Retry.APIBaseURL
+	}
+	return DeepgramProvider(cfg, retryCfg)
+}
+
+// fallbackProvider builds the provider used while the network is degraded:
+// the Deepgram endpoint at COLDMIC_FALLBACK_DEEPGRAM_URL, or local
+// whisper.cpp, which needs no network at all.
+func fallbackProvider(cfg config.Config) (ports.TranscriptionProvider, error) {
+	switch cfg.Network.FallbackProvider {
+	case "deepgram":
+		if cfg.Network.FallbackURL == "" {
+			return nil, errors.New("COLDMIC_FALLBACK_PROVIDER=deepgram requires COLDMIC_FALLBACK_DEEPGRAM_URL")
+		}
+		fallbackCfg := cfg.Deepgram
+		fallbackCfg.APIBaseURL = cfg.Network.FallbackURL
+		fallbackCfg.Model = cfg.Network.FallbackModel
+		return DeepgramProvider(cfg, fallbackCfg)
+	case "whispercpp":
+		if strings.TrimSpace(cfg.WhisperCpp.ModelPath) == "" {
+			return nil, errors.New("COLDMIC_FALLBACK_PROVIDER=whispercpp requires COLDMIC_WHISPERCPP_MODEL")
+		}
+		localCfg := cfg
+		localCfg.Provider = "whispercpp"
+		return newPrimaryProvider(localCfg)
+	default:
+		return nil, fmt.Errorf("unsupported COLDMIC_FALLBACK_PROVIDER %q (expected deepgram or whispercpp)", cfg.Network.FallbackProvider)
+	}
+}
+
+// NewProvider builds the streaming transcription provider for cfg.
+func NewProvider(cfg config.DeepgramConfig) ports.TranscriptionProvider {
+	return deepgram.NewProvider(deepgram.Config{
+		APIKey:      cfg.APIKey,
+		APIBaseURL:  cfg.APIBaseURL,
+		Model:       cfg.Model,
+		Language:    cfg.Language,
+		SmartFormat: cfg.SmartFormat,
+		Auth:        cfg.Auth,
+		TLS: deepgram.TLSConfig{
+			CAFile:             cfg.TLS.CAFile,
+			ClientCert:         cfg.TLS.ClientCert,
+			ClientKey:          cfg.TLS.ClientKey,
+			InsecureSkipVerify: cfg.TLS.InsecureSkipVerify,
+		},
+		Tags:            cfg.Tags,
+		Callback:        cfg.Callback,
+		Diarize:         cfg.Diarize,
+		Endpointing:     cfg.Endpointing,
+		UtteranceEndMS:  cfg.UtteranceEndMS,
+		VADEvents:       cfg.VADEvents,
+		Punctuate:       cfg.Punctuate,
+		Numerals:        cfg.Numerals,
+		FillerWords:     cfg.FillerWords,
+		ProfanityFilter: cfg.ProfanityFilter,
+		Redact:          cfg.Redact,
+		DetectLanguage:  cfg.DetectLanguage,
+		Proxy:           cfg.Proxy,
+		DialRetries:     cfg.DialRetries,
+	})
+}
+
+// TargetProvider returns the provider for dictation to an output target:
+// the Deepgram profile listing the target when Deepgram is the provider,
+// otherwise primary.
+func TargetProvider(cfg config.Config, target string, primary ports.TranscriptionProvider) (ports.TranscriptionProvider, error) {
+	if cfg.Provider != "" && cfg.Provider != "deepgram" {
+		return primary, nil
+	}
+	deepgramCfg, ok := cfg.DeepgramForTarget(target)
+	if !ok {
+		return primary, nil
+	}
+	return DeepgramProvider(cfg, deepgramCfg)
+}
+
+func buildHistoryBackup(cfg config.BackupConfig, historyStore ports.HistoryStore) (*usecase.HistoryBackup, error) {
+	var target ports.BackupTarget
+	switch cfg.Target {
+	case "":
+		return nil, nil
+	case "s3":
+		target = backup.NewS3Target(backup.S3Config{
+			BaseURL:   cfg.URL,
+			Region:    cfg.Region,
+			AccessKey: cfg.Username,
+			SecretKey: cfg.Password,
+		})
+	case "webdav":
+		target = backup.NewWebDAVTarget(backup.WebDAVConfig{
+			BaseURL:  cfg.URL,
+			Username: cfg.Username,
+			Password: cfg.Password,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported COLDMIC_BACKUP_TARGET %q (expected s3 or webdav)", cfg.Target)
+	}
+	if cfg.URL == "" {
+		return nil, errors.New("COLDMIC_BACKUP_URL is required when COLDMIC_BACKUP_TARGET is set")
+	}
+	if cfg.Passphrase == "" {
+		return nil, errors.New("COLDMIC_BACKUP_PASSPHRASE is required when COLDMIC_BACKUP_TARGET is set")
+	}
+	return usecase.NewHistoryBackup(historyStore, backup.NewEncryptedTarget(target, cfg.Passphrase), usecase.BackupConfig{
+		Interval:   cfg.Interval,
+		ObjectName: cfg.ObjectName,
+	}), nil
+}
+
+// fileJobSink reuses the event sink for file job updates when it supports them.
+func fileJobSink(eventSink ports.EventSink) ports.FileJobSink {
+	if sink, ok := eventSink.(ports.FileJobSink); ok {
+		return sink
+	}
+	return noopFileJobSink{}
+}
+
+type noopFileJobSink struct{}
+
+func (noopFileJobSink) FileJobChanged(_ domain.FileJob) {}
+
+// networkSink reuses the event sink for connectivity updates when it supports them.
+func networkSink(eventSink ports.EventSink) ports.NetworkSink {
+	if sink, ok := eventSink.(ports.NetworkSink); ok {
+		return sink
+	}
+	return noopNetworkSink{}
+}
+
+type noopNetworkSink struct{}
+
+func (noopNetworkSink) NetworkChanged(_ domain.NetworkState) {}
+
+func casingConfig(cfg config.CasingConfig) usecase.CasingConfig {
+	targets := make(map[string]usecase.CasingPolicy, len(cfg.Targets))
+	for name, policy := range cfg.Targets {
+		targets[name] = usecase.CasingPolicy(policy)
+	}
+	return usecase.CasingConfig{Default: usecase.CasingPolicy(cfg.Policy), Targets: targets, Words: cfg.Words}
+}
+
+func normalizeConfig(cfg config.NormalizeConfig) usecase.NormalizeConfig {
+	targets := make(map[string]usecase.NormalizeOptions, len(cfg.Targets))
+	for name, options := range cfg.Targets {
+		targets[name] = normalizeOptions(options)
+	}
+	return usecase.NormalizeConfig{Default: normalizeOptions(cfg.Options), Targets: targets}
+}
+
+func normalizeOptions(names []string) usecase.NormalizeOptions {
+	return usecase.NormalizeOptions{
+		Trim:              slices.Contains(names, "trim"),
+		CollapseSpaces:    slices.Contains(names, "collapse-spaces"),
+		EnsurePunctuation: slices.Contains(names, "punctuation"),
+		TrailingNewline:   slices.Contains(names, "newline"),
+	}
+}
+
+// localModelPath resolves a catalog model name, such as base.en, to its
+// downloaded file; paths are returned unchanged.
+func localModelPath(manager *models.Manager, model string) string {
+	if model == "" || strings.ContainsRune(model, filepath.Separator) {
+		return model
+	}
+	if path, err := manager.Path(model); err == nil {
+		return path
+	}
+	return model
+}
+
+func modelProgressSink(eventSink ports.EventSink) ports.ModelProgressSink {
+	if sink, ok := eventSink.(ports.ModelProgressSink); ok {
+		return sink
+	}
+	return noopModelProgressSink{}
+}
+
+type noopModelProgressSink struct{}
+
+func (noopModelProgressSink) ModelProgress(_ domain.ModelProgress) {}
+
+func playbackSink(eventSink ports.EventSink) ports.PlaybackSink {
+	if sink, ok := eventSink.(ports.PlaybackSink); ok {
+		return sink
+	}
+	return noopPlaybackSink{}
+}
+
+type noopPlaybackSink struct{}
+
+func (noopPlaybackSink) PlaybackPosition(_ domain.PlaybackPosition) {}
+
+// confidenceSink reuses the event sink for retry offers when it supports them.
+func confidenceSink(eventSink ports.EventSink) ports.ConfidenceSink {
+	if sink, ok := eventSink.(ports.ConfidenceSink); ok {
+		return sink
+	}
+	return noopConfidenceSink{}
+}
+
+type noopConfidenceSink struct{}
+
+func (noopConfidenceSink) LowConfidence(_ domain.LowConfidence) {}
+
+// buildAnnouncer returns nil when announcements are off.
+func buildAnnouncer(cfg config.AccessibilityConfig) ports.Announcer {
+	switch {
+	case cfg.Verbosity == string(usecase.AnnounceOff):
+		return nil
+	case cfg.Backend == "notify":
+		return a11y.NewNotifier(cfg.Command)
+	default:
+		return a11y.NewSpeechDispatcher(cfg.Command)
+	}
+}
+
+// issueSink reuses the event sink for issue drafts when it supports them.
+func issueSink(eventSink ports.EventSink) ports.IssueSink {
+	if sink, ok := eventSink.(ports.IssueSink); ok {
+		return sink
+	}
+	return noopIssueSink{}
+}
+
+type noopIssueSink struct{}
+
+func (noopIssueSink) IssueDraftReady(_ domain.IssueDraft) {}
+func (noopIssueSink) IssueCreated(_ domain.Issue)         {}
+
+// NewTarget builds the named output target, built in or defined in the
+// targets file. "clipboard" and "" return nil, which copies transcripts
+// unchanged.
+func NewTarget(cfg config.Config, name string, eventSink ports.EventSink) (ports.OutputTarget, error) {
+	kind, settings, err := cfg.ResolveTarget(name)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Target = settings
+	switch kind {
+	case "clipboard":
+		return nil, nil
+	case output.GitCommitTargetName:
+		var commitRules ports.RulesEngine
+		if cfg.Target.GitCommitRules != "" {
+			engine, err := rules.NewEngine(cfg.Target.GitCommitRules, cfg.Rules.IterationLimit)
+			if err != nil {
+				return nil, err
+			}
+			commitRules = engine
+		}
+		return output.NewGitCommitTarget(cfg.Target.GitCommitRepo, commitRules), nil
+	case "github-issue":
+		return usecase.NewIssueTarget(issues.NewGitHubTracker(issues.GitHubConfig{
+			APIBaseURL: cfg.Target.GitHubAPIURL,
+			Token:      cfg.Target.GitHubToken,
+			Repo:       cfg.Target.GitHubRepo,
+		}), issueSink(eventSink)), nil
+	case "jira-issue":
+		return usecase.NewIssueTarget(issues.NewJiraTracker(issues.JiraConfig{
+			BaseURL:   cfg.Target.JiraURL,
+			Email:     cfg.Target.JiraEmail,
+			Token:     cfg.Target.JiraToken,
+			Project:   cfg.Target.JiraProject,
+			IssueType: cfg.Target.JiraIssueType,
+		}), issueSink(eventSink)), nil
+	case "reminder":
+		var creator ports.ReminderCreator = reminders.NewICSDir(cfg.Target.ReminderDir)
+		if len(cfg.Target.ReminderCommand) > 0 {
+			creator = reminders.NewCommand(cfg.Target.ReminderCommand)
+		}
+		return usecase.NewReminderTarget(creator), nil
+	case "todo":
+		return usecase.NewTaskTarget("todo", tasks.NewTodoTxtFile(cfg.Target.TodoFile), cfg.Target.TaskProject, cfg.Target.TaskContext), nil
+	case "taskwarrior":
+		return usecase.NewTaskTarget("taskwarrior", tasks.NewTaskwarrior(cfg.Target.TaskCommand), cfg.Target.TaskProject, cfg.Target.TaskContext), nil
+	default:
+		return nil, fmt.Errorf("unknown output target %q", name)
+	}
 }
@@ -2,11 +2,21 @@ package bootstrap
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"coldmic/internal/audio"
+	"coldmic/internal/config"
 	"coldmic/internal/domain"
+	"coldmic/internal/history"
+	"coldmic/internal/models"
+	"coldmic/internal/ports"
+	"coldmic/internal/providers/replay"
+	"coldmic/internal/providers/whispercpp"
+	"coldmic/internal/usecase"
 )
 
 func TestBuildSuccess(t *testing.T) {
@@ -24,6 +34,430 @@ func TestBuildSuccess(t *testing.T) {
 	if services.Session == nil {
 		t.Fatalf("expected session service")
 	}
+	if services.Files == nil || services.URLs == nil || services.History == nil {
+		t.Fatalf("expected file, url, and history services")
+	}
+	if services.Batch == nil {
+		t.Fatalf("expected batch pool")
+	}
+	if services.Watcher != nil {
+		t.Fatalf("expected watcher to be disabled without COLDMIC_WATCH_DIR")
+	}
+	if services.Backup != nil {
+		t.Fatalf("expected backup to be disabled without COLDMIC_BACKUP_TARGET")
+	}
+	if services.Network != nil {
+		t.Fatalf("expected no network monitoring without a fallback")
+	}
+}
+
+func TestBuildLocalNetworkFallback(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DEEPGRAM_API_KEY", "test-key")
+	t.Setenv("COLDMIC_FALLBACK_PROVIDER", "whispercpp")
+	t.Setenv("COLDMIC_WHISPERCPP_MODEL", "")
+
+	if _, err := Build(noopEventSink{}, noopClipboard{}); err == nil || !strings.Contains(err.Error(), "COLDMIC_WHISPERCPP_MODEL") {
+		t.Fatalf("expected a local fallback to need a model, got %v", err)
+	}
+
+	t.Setenv("COLDMIC_WHISPERCPP_MODEL", filepath.Join(t.TempDir(), "ggml-base.en.bin"))
+	services, err := Build(noopEventSink{}, noopClipboard{})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if services.Network == nil {
+		t.Fatalf("expected network monitoring for the local fallback")
+	}
+	fallback, err := fallbackProvider(services.Config)
+	if err != nil {
+		t.Fatalf("fallback provider: %v", err)
+	}
+	if _, ok := fallback.(*whispercpp.Provider); !ok {
+		t.Fatalf("expected the whisper.cpp fallback, got %T", fallback)
+	}
+}
+
+func TestCapabilitiesReportLocalAcceleration(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_PROVIDER", "whispercpp")
+	t.Setenv("COLDMIC_WHISPERCPP_MODEL", "/models/ggml-base.en.bin")
+	t.Setenv("COLDMIC_WHISPERCPP_ACCELERATION", "cpu")
+	t.Setenv("COLDMIC_WHISPERCPP_STEP_MS", "0")
+
+	services, err := Build(noopEventSink{}, noopClipboard{})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	capabilities := Capabilities(services.Config, services.Provider)
+	if capabilities.Provider != "whispercpp" || capabilities.LivePartials {
+		t.Fatalf("unexpected capabilities: %+v", capabilities)
+	}
+	if capabilities.Acceleration == nil || capabilities.Acceleration.Active != "cpu" {
+		t.Fatalf("expected cpu acceleration, got %+v", capabilities.Acceleration)
+	}
+
+	if len(capabilities.Languages) != 1 || capabilities.Languages[0] != "en" {
+		t.Fatalf("expected an English-only model, got %+v", capabilities.Languages)
+	}
+
+	t.Setenv("COLDMIC_WHISPERCPP_STEP_MS", "")
+	services, err = Build(noopEventSink{}, noopClipboard{})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if streaming := Capabilities(services.Config, services.Provider); !streaming.LivePartials || !streaming.Timestamps {
+		t.Fatalf("expected live partials with a whisper.cpp step, got %+v", streaming)
+	}
+
+	services.Config.Provider = "deepgram"
+	if deepgram := Capabilities(services.Config, NewProvider(services.Config.Deepgram)); !deepgram.LivePartials || deepgram.Acceleration != nil {
+		t.Fatalf("expected no acceleration for remote providers, got %+v", deepgram)
+	}
+}
+
+func TestBuildRejectsUnsupportedOptions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_PROVIDER", "groq")
+	t.Setenv("COLDMIC_TIMESTAMPS", "offset")
+
+	if _, err := Build(noopEventSink{}, noopClipboard{}); err == nil || !strings.Contains(err.Error(), "COLDMIC_TIMESTAMPS") {
+		t.Fatalf("expected timestamps to be rejected for a batch provider, got %v", err)
+	}
+
+	t.Setenv("COLDMIC_TIMESTAMPS", "off")
+	t.Setenv("COLDMIC_KEYWORDS", "coldmic, Hyprland")
+	if _, err := Build(noopEventSink{}, noopClipboard{}); err == nil || !strings.Contains(err.Error(), "COLDMIC_KEYWORDS") {
+		t.Fatalf("expected keywords to be rejected, got %v", err)
+	}
+
+	t.Setenv("COLDMIC_PROVIDER", "deepgram")
+	services, err := Build(noopEventSink{}, noopClipboard{})
+	if err != nil {
+		t.Fatalf("expected deepgram to accept keywords: %v", err)
+	}
+	if capabilities := Capabilities(services.Config, services.Provider); !capabilities.Keywords || !capabilities.Streaming {
+		t.Fatalf("unexpected deepgram capabilities: %+v", capabilities)
+	}
+}
+
+func TestTargetProviderUsesDeepgramProfile(t *testing.T) {
+	home := t.TempDir()
+	profiles := filepath.Join(home, "profiles.json")
+	if err := os.WriteFile(profiles, []byte(`{"review": {"url": "https://review.example/v1", "targets": ["git-commit"]}}`), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	t.Setenv("HOME", home)
+	t.Setenv("DEEPGRAM_API_KEY", "test-key")
+	t.Setenv("COLDMIC_DEEPGRAM_PROFILES", profiles)
+
+	services, err := Build(noopEventSink{}, noopClipboard{})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if provider, err := TargetProvider(services.Config, "clipboard", services.Provider); err != nil || provider != services.Provider {
+		t.Fatalf("expected the primary provider for clipboard: %v", err)
+	}
+	if provider, err := TargetProvider(services.Config, "git-commit", services.Provider); err != nil || provider == services.Provider {
+		t.Fatalf("expected a profile provider for git-commit: %v", err)
+	}
+
+	services.Config.Provider = "openai"
+	if provider, _ := TargetProvider(services.Config, "git-commit", services.Provider); provider != services.Provider {
+		t.Fatalf("expected profiles to apply to Deepgram only")
+	}
+}
+
+func TestTargetSwitcherKeepsFallbackAcrossTargetSwitches(t *testing.T) {
+	home := t.TempDir()
+	profiles := filepath.Join(home, "profiles.json")
+	if err := os.WriteFile(profiles, []byte(`{"review": {"url": "https://review.example/v1", "targets": ["git-commit"]}}`), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	t.Setenv("HOME", home)
+	t.Setenv("DEEPGRAM_API_KEY", "test-key")
+	t.Setenv("COLDMIC_DEEPGRAM_PROFILES", profiles)
+
+	services, err := Build(noopEventSink{}, noopClipboard{})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	targets := services.Targets
+	fallback := NewProvider(services.Config.Deepgram)
+
+	if _, err := targets.Select("git-commit"); err != nil {
+		t.Fatalf("select failed: %v", err)
+	}
+	profile := targets.Provider()
+	if profile == services.Provider {
+		t.Fatalf("expected the profile provider for git-commit")
+	}
+	_ = targets.SetNetworkProvider(fallback)
+	if _, err := targets.Select("clipboard"); err != nil {
+		t.Fatalf("select failed: %v", err)
+	}
+	if targets.Provider() != fallback {
+		t.Fatalf("expected a target switch to keep the network fallback")
+	}
+	if _, err := targets.Select("git-commit"); err != nil {
+		t.Fatalf("select failed: %v", err)
+	}
+	_ = targets.SetNetworkProvider(services.Provider)
+	if provider := targets.Provider(); provider == fallback || provider == services.Provider {
+		t.Fatalf("expected recovery to restore the git-commit profile provider")
+	}
+}
+
+func TestCheckCapabilitiesLanguages(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Config{Provider: "whispercpp"}
+	english := domain.Capabilities{Provider: "whispercpp", Languages: []string{"en"}}
+	for _, language := range []string{"", "en", "en-US"} {
+		cfg.WhisperCpp.Language = language
+		if err := checkCapabilities(cfg, english); err != nil {
+			t.Fatalf("expected %q to be accepted: %v", language, err)
+		}
+	}
+	cfg.WhisperCpp.Language = "de"
+	if err := checkCapabilities(cfg, english); err == nil {
+		t.Fatalf("expected German to be rejected by an English-only model")
+	}
+}
+
+func TestCheckCapabilitiesMultichannel(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Config{Audio: config.AudioConfig{Channels: 2, Multichannel: "label"}}
+	if err := checkCapabilities(cfg, domain.Capabilities{Provider: "deepgram", Multichannel: true}); err != nil {
+		t.Fatalf("expected multichannel to be accepted: %v", err)
+	}
+	if err := checkCapabilities(cfg, domain.Capabilities{Provider: "whispercpp"}); err == nil {
+		t.Fatalf("expected multichannel to be rejected by a provider without it")
+	}
+	cfg.Audio.Channels = 1
+	if err := checkCapabilities(cfg, domain.Capabilities{Provider: "whispercpp"}); err != nil {
+		t.Fatalf("expected mono audio to ignore multichannel: %v", err)
+	}
+}
+
+func TestLocalModelPathResolvesCatalogNames(t *testing.T) {
+	t.Parallel()
+
+	manager := models.NewManager("/data/models", models.DefaultCatalog, noopModelProgressSink{})
+	if got := localModelPath(manager, "base.en"); got != filepath.Join("/data/models", "ggml-base.en.bin") {
+		t.Fatalf("unexpected catalog model path %q", got)
+	}
+	for _, model := range []string{"", "/models/custom.bin", "unknown"} {
+		if got := localModelPath(manager, model); got != model {
+			t.Fatalf("expected %q unchanged, got %q", model, got)
+		}
+	}
+}
+
+func TestBuildLocalRedaction(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DEEPGRAM_API_KEY", "test-key")
+	t.Setenv("COLDMIC_REDACT_LOCAL", "bleep")
+	t.Setenv("COLDMIC_WHISPERCPP_MODEL", "")
+
+	if _, err := Build(noopEventSink{}, noopClipboard{}); err == nil || !strings.Contains(err.Error(), "COLDMIC_WHISPERCPP_MODEL") {
+		t.Fatalf("expected missing local model error, got %v", err)
+	}
+
+	t.Setenv("COLDMIC_WHISPERCPP_MODEL", "/models/ggml-tiny.en.bin")
+	services, err := Build(noopEventSink{}, noopClipboard{})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if _, ok := services.Provider.(*usecase.LocalRedactingProvider); !ok {
+		t.Fatalf("expected redacting provider, got %T", services.Provider)
+	}
+
+	t.Setenv("COLDMIC_REDACT_LOCAL", "beep")
+	if _, err := Build(noopEventSink{}, noopClipboard{}); err == nil {
+		t.Fatalf("expected unknown redaction mode to fail")
+	}
+}
+
+func TestDeepgramProvidersAreScreenedLocally(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DEEPGRAM_API_KEY", "test-key")
+	t.Setenv("COLDMIC_REDACT_LOCAL", "skip")
+	t.Setenv("COLDMIC_WHISPERCPP_MODEL", "/models/ggml-tiny.en.bin")
+	t.Setenv("COLDMIC_PROVIDER", "whispercpp")
+	t.Setenv("COLDMIC_FALLBACK_DEEPGRAM_URL", "https://stt.example/v1")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	for name, build := range map[string]func(config.Config) (ports.TranscriptionProvider, error){
+		"accurate": accurateProvider,
+		"retry":    retryProvider,
+		"fallback": fallbackProvider,
+	} {
+		provider, err := build(cfg)
+		if err != nil {
+			t.Fatalf("%s provider: %v", name, err)
+		}
+		if _, ok := provider.(*usecase.LocalRedactingProvider); !ok {
+			t.Fatalf("expected the %s provider to be screened, got %T", name, provider)
+		}
+	}
+}
+
+func TestCacheFingerprintCoversProviderOptions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DEEPGRAM_API_KEY", "test-key")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	base := cacheFingerprint(cfg, nil, usecase.SilenceTrimConfig{})
+	changes := map[string]func(*config.Config){
+		"deepgram option": func(c *config.Config) { c.Deepgram.Diarize = !c.Deepgram.Diarize },
+		"provider":        func(c *config.Config) { c.Provider = "replay" },
+		"redaction":       func(c *config.Config) { c.Redaction.Mode = "skip" },
+		"race":            func(c *config.Config) { c.Race.Provider = "assemblyai" },
+	}
+	for name, change := range changes {
+		changed := cfg
+		change(&changed)
+		if cacheFingerprint(changed, nil, usecase.SilenceTrimConfig{}) == base {
+			t.Fatalf("expected a %s change to miss the cache", name)
+		}
+	}
+}
+
+func TestBuildReplayNeedsNoKeyOrMicrophone(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DEEPGRAM_API_KEY", "")
+	t.Setenv("COLDMIC_PROVIDER", "replay")
+	t.Setenv("COLDMIC_AUDIO_INPUT_FORMAT", "")
+
+	services, err := Build(noopEventSink{}, noopClipboard{})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if services.Config.Audio.InputFormat != "silence" {
+		t.Fatalf("expected silent capture, got %q", services.Config.Audio.InputFormat)
+	}
+	if _, ok := newCapture(services.Config.Audio).(*audio.SilenceCapture); !ok {
+		t.Fatalf("expected silence capture")
+	}
+}
+
+func TestNewCaptureSelectsBackend(t *testing.T) {
+	t.Parallel()
+
+	if _, ok := newCapture(config.AudioConfig{InputFormat: "libpulse"}).(*audio.PulseCapture); !ok {
+		t.Fatalf("expected libpulse capture")
+	}
+	if _, ok := newCapture(config.AudioConfig{InputFormat: "portaudio"}).(*audio.PortAudioCapture); !ok {
+		t.Fatalf("expected PortAudio capture")
+	}
+	if _, ok := newCapture(config.AudioConfig{InputFormat: "pulse"}).(*audio.FFMPEGCapture); !ok {
+		t.Fatalf("expected ffmpeg capture")
+	}
+}
+
+func TestNewHistoryStoreSelectsBackend(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	store, err := newHistoryStore(config.StorageConfig{HistoryBackend: "markdown", HistoryPath: filepath.Join(dir, "history")})
+	if _, ok := store.(*history.MarkdownStore); err != nil || !ok {
+		t.Fatalf("expected markdown history, got %T %v", store, err)
+	}
+	store, err = newHistoryStore(config.StorageConfig{HistoryBackend: "jsonl", HistoryPath: filepath.Join(dir, "history.jsonl")})
+	if _, ok := store.(*history.JSONLStore); err != nil || !ok {
+		t.Fatalf("expected JSON-lines history, got %T %v", store, err)
+	}
+}
+
+func TestBuildDryRunReplacesEveryProvider(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DEEPGRAM_API_KEY", "test-key")
+	t.Setenv("COLDMIC_DRY_RUN", "true")
+	t.Setenv("COLDMIC_RACE_PROVIDER", "assemblyai")
+	t.Setenv("COLDMIC_AUDIO_INPUT_FORMAT", "")
+
+	services, err := Build(noopEventSink{}, noopClipboard{})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if _, ok := services.Provider.(*replay.Provider); !ok {
+		t.Fatalf("expected the replay provider, got %T", services.Provider)
+	}
+	if services.Validator != nil || services.Config.Session.Preflight {
+		t.Fatalf("expected no provider check in a dry run")
+	}
+	if services.Config.Audio.InputFormat == "silence" {
+		t.Fatalf("expected a dry run to keep the real capture")
+	}
+	if _, err := DeepgramProvider(services.Config, services.Config.Deepgram); err == nil {
+		t.Fatalf("expected no Deepgram provider in a dry run")
+	}
+}
+
+func TestBuildRace(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DEEPGRAM_API_KEY", "test-key")
+	t.Setenv("COLDMIC_RACE_PROVIDER", "assemblyai")
+	t.Setenv("COLDMIC_RACE_PICK", "")
+
+	services, err := Build(noopEventSink{}, noopClipboard{})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if _, ok := services.Provider.(*usecase.RaceProvider); !ok {
+		t.Fatalf("expected race provider, got %T", services.Provider)
+	}
+
+	t.Setenv("COLDMIC_RACE_PICK", "fastest")
+	if _, err := Build(noopEventSink{}, noopClipboard{}); err == nil {
+		t.Fatalf("expected unknown race pick to fail")
+	}
+	t.Setenv("COLDMIC_RACE_PICK", "first")
+	t.Setenv("COLDMIC_RACE_PROVIDER", "nope")
+	if _, err := Build(noopEventSink{}, noopClipboard{}); err == nil {
+		t.Fatalf("expected unknown race provider to fail")
+	}
+}
+
+func TestBuildBackupRequiresPassphrase(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_BACKUP_TARGET", "webdav")
+	t.Setenv("COLDMIC_BACKUP_URL", "https://dav.example.com/coldmic")
+
+	if _, err := Build(noopEventSink{}, noopClipboard{}); err == nil {
+		t.Fatalf("expected missing passphrase to fail")
+	}
+
+	t.Setenv("COLDMIC_BACKUP_PASSPHRASE", "secret")
+	services, err := Build(noopEventSink{}, noopClipboard{})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if services.Backup == nil {
+		t.Fatalf("expected backup service")
+	}
+}
+
+func TestBuildRejectsUnknownTarget(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_TARGET", "fax")
+
+	if _, err := Build(noopEventSink{}, noopClipboard{}); err == nil {
+		t.Fatalf("expected unknown target to fail")
+	}
+
+	t.Setenv("COLDMIC_TARGET", "git-commit")
+	if _, err := Build(noopEventSink{}, noopClipboard{}); err != nil {
+		t.Fatalf("build with git-commit target failed: %v", err)
+	}
 }
 
 func TestBuildSkipsInvalidRules(t *testing.T) {
@@ -48,11 +482,61 @@ func TestBuildSkipsInvalidRules(t *testing.T) {
 	}
 }
 
+func TestBuildRefusesWorldWritableRules(t *testing.T) {
+	home := t.TempDir()
+	rules := filepath.Join(home, "shared.rules")
+	if err := os.WriteFile(rules, []byte("a => b\n"), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := os.Chmod(rules, 0o666); err != nil {
+		t.Fatalf("chmod failed: %v", err)
+	}
+
+	t.Setenv("HOME", home)
+	t.Setenv("COLDMIC_RULES_FILE", rules)
+
+	if _, err := Build(noopEventSink{}, noopClipboard{}); err == nil || !strings.Contains(err.Error(), "world-writable") {
+		t.Fatalf("expected world-writable rules error, got %v", err)
+	}
+}
+
+func TestPreflightFailsOnlyOnRejectedCredentials(t *testing.T) {
+	t.Parallel()
+
+	services := Services{Config: config.Config{Session: config.SessionConfig{Preflight: true}}}
+	if err := Preflight(context.Background(), services); err != nil {
+		t.Fatalf("expected providers without validation to pass, got %v", err)
+	}
+
+	services.Validator = fakeValidator{err: errors.New("dial tcp: lookup api.deepgram.com: no such host")}
+	if err := Preflight(context.Background(), services); err != nil {
+		t.Fatalf("expected an unreachable provider to pass, got %v", err)
+	}
+
+	services.Validator = fakeValidator{err: &domain.ProviderError{Provider: "deepgram", Code: domain.ErrorCodeInvalidKey, Message: "Invalid credentials."}}
+	if err := Preflight(context.Background(), services); domain.ErrorCodeFor(err, "") != domain.ErrorCodeInvalidKey {
+		t.Fatalf("expected an invalid key error, got %v", err)
+	}
+
+	services.Config.Session.Preflight = false
+	if err := Preflight(context.Background(), services); err != nil {
+		t.Fatalf("expected COLDMIC_PREFLIGHT=false to skip the check, got %v", err)
+	}
+}
+
+type fakeValidator struct {
+	err error
+}
+
+func (f fakeValidator) Validate(context.Context) error {
+	return f.err
+}
+
 type noopEventSink struct{}
 
 func (noopEventSink) SessionStateChanged(_ domain.SessionState, _ domain.SessionStateReason) {}
 func (noopEventSink) PartialTranscript(_ string)                                             {}
-func (noopEventSink) FinalTranscript(_, _, _ string)                                         {}
+func (noopEventSink) FinalTranscript(_, _, _ string, _ []domain.SessionWarning)              {}
 func (noopEventSink) SessionError(_ domain.ErrorCode, _ string)                              {}
 
 type noopClipboard struct{}
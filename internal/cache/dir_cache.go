@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+// DirCache stores transcripts as one JSON file per key and evicts the least
+// recently used entries once the directory exceeds maxBytes.
+type DirCache struct {
+	dir      string
+	maxBytes int64
+	mu       sync.Mutex
+}
+
+func NewDirCache(dir string, maxBytes int64) *DirCache {
+	return &DirCache{dir: dir, maxBytes: maxBytes}
+}
+
+func (c *DirCache) Get(_ context.Context, key string) (domain.CachedTranscript, bool, error) {
+	path, err := c.path(key)
+	if err != nil {
+		return domain.CachedTranscript{}, false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return domain.CachedTranscript{}, false, nil
+		}
+		return domain.CachedTranscript{}, false, err
+	}
+	var transcript domain.CachedTranscript
+	if err := json.Unmarshal(data, &transcript); err != nil {
+		_ = os.Remove(path)
+		return domain.CachedTranscript{}, false, nil
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return transcript, true, nil
+}
+
+func (c *DirCache) Put(_ context.Context, key string, transcript domain.CachedTranscript) error {
+	path, err := c.path(key)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(transcript)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return c.evict()
+}
+
+// PurgeSince deletes the entries written or read at or after since, which
+// Get records as the modification time, and returns how many it deleted.
+func (c *DirCache) PurgeSince(_ context.Context, since time.Time) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to list cache entries: %w", err)
+	}
+	purged := 0
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().Before(since) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.dir, entry.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, fmt.Errorf("failed to delete cache entry: %w", err))
+			continue
+		}
+		purged++
+	}
+	return purged, errors.Join(errs...)
+}
+
+func (c *DirCache) evict() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+
+	type cached struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []cached
+	var total int64
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, cached{path: filepath.Join(c.dir, entry.Name()), size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, file := range files {
+		if total <= c.maxBytes {
+			break
+		}
+		if err := os.Remove(file.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		total -= file.size
+	}
+	return nil
+}
+
+func (c *DirCache) path(key string) (string, error) {
+	if key == "" || strings.ContainsAny(key, `/\.`) {
+		return "", fmt.Errorf("invalid cache key %q", key)
+	}
+	return filepath.Join(c.dir, key+".json"), nil
+}
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+func TestDirCacheRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	cache := NewDirCache(filepath.Join(t.TempDir(), "cache"), 1<<20)
+	want := domain.CachedTranscript{
+		RawTranscript: "hello",
+		Segments:      []domain.DialogueSegment{{Text: "hello", Offset: 2 * time.Second}},
+	}
+
+	if _, ok, err := cache.Get(context.Background(), "abc"); err != nil || ok {
+		t.Fatalf("expected miss, got ok=%v err=%v", ok, err)
+	}
+	if err := cache.Put(context.Background(), "abc", want); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	got, ok, err := cache.Get(context.Background(), "abc")
+	if err != nil || !ok {
+		t.Fatalf("expected hit, got ok=%v err=%v", ok, err)
+	}
+	if got.RawTranscript != "hello" || len(got.Segments) != 1 || got.Segments[0].Offset != 2*time.Second {
+		t.Fatalf("unexpected cached transcript: %+v", got)
+	}
+}
+
+func TestDirCachePurgeSince(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "cache")
+	cache := NewDirCache(dir, 1<<20)
+	for _, key := range []string{"old", "new"} {
+		if err := cache.Put(context.Background(), key, domain.CachedTranscript{RawTranscript: key}); err != nil {
+			t.Fatalf("put failed: %v", err)
+		}
+	}
+	today := time.Now().Add(-time.Hour)
+	yesterday := today.Add(-24 * time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "old.json"), yesterday, yesterday); err != nil {
+		t.Fatalf("chtimes failed: %v", err)
+	}
+
+	purged, err := cache.PurgeSince(context.Background(), today)
+	if err != nil || purged != 1 {
+		t.Fatalf("expected one purged entry, got %d %v", purged, err)
+	}
+	if _, ok, _ := cache.Get(context.Background(), "new"); ok {
+		t.Fatalf("expected today's entry purged")
+	}
+	if _, ok, _ := cache.Get(context.Background(), "old"); !ok {
+		t.Fatalf("expected yesterday's entry kept")
+	}
+}
+
+func TestDirCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	entry := domain.CachedTranscript{RawTranscript: "0123456789"}
+	cache := NewDirCache(dir, 100)
+
+	for _, key := range []string{"old", "used", "new"} {
+		if err := cache.Put(context.Background(), key, entry); err != nil {
+			t.Fatalf("put failed: %v", err)
+		}
+	}
+	past := time.Now().Add(-time.Hour)
+	_ = os.Chtimes(filepath.Join(dir, "old.json"), past, past)
+	_ = os.Chtimes(filepath.Join(dir, "used.json"), past.Add(time.Minute), past.Add(time.Minute))
+	if _, ok, _ := cache.Get(context.Background(), "used"); !ok {
+		t.Fatalf("expected hit for used entry")
+	}
+
+	if err := cache.Put(context.Background(), "newest", entry); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	if _, ok, _ := cache.Get(context.Background(), "old"); ok {
+		t.Fatalf("expected least recently used entry to be evicted")
+	}
+	if _, ok, _ := cache.Get(context.Background(), "used"); !ok {
+		t.Fatalf("expected recently used entry to survive")
+	}
+}
+
+func TestDirCacheRejectsPathKeys(t *testing.T) {
+	t.Parallel()
+
+	cache := NewDirCache(t.TempDir(), 1<<20)
+	if err := cache.Put(context.Background(), "../escape", domain.CachedTranscript{}); err == nil {
+		t.Fatalf("expected invalid key error")
+	}
+}
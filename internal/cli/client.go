@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"strings"
 	"time"
@@ -15,19 +16,37 @@ import (
 
 type Client struct {
 	baseURL string
+	token   string
 	http    *http.Client
 }
 
+// NewClient accepts an http(s) base URL or unix:///path/to.sock for a
+// daemon listening on a per-user unix socket.
 func NewClient(baseURL string) *Client {
 	trimmed := strings.TrimRight(baseURL, "/")
+	httpClient := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+	if socket, ok := strings.CutPrefix(trimmed, "unix://"); ok {
+		var dialer net.Dialer
+		httpClient.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", socket)
+			},
+		}
+		trimmed = "http://coldmicd"
+	}
 	return &Client{
 		baseURL: trimmed,
-		http: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		http:    httpClient,
 	}
 }
 
+// SetToken sends token as a bearer credential on every request.
+func (c *Client) SetToken(token string) {
+	c.token = strings.TrimSpace(token)
+}
+
 type envelope struct {
 	OK     bool              `json:"ok"`
 	Error  string            `json:"error,omitempty"`
@@ -42,6 +61,24 @@ type transcriptEnvelope struct {
 	Result   domain.StopResult `json:"result"`
 }
 
+type targetsEnvelope struct {
+	OK      bool                      `json:"ok"`
+	Error   string                    `json:"error,omitempty"`
+	Targets []domain.OutputTargetInfo `json:"targets"`
+}
+
+type hotkeysEnvelope struct {
+	OK      bool            `json:"ok"`
+	Error   string          `json:"error,omitempty"`
+	Hotkeys []domain.Hotkey `json:"hotkeys"`
+}
+
+type hotkeyEnvelope struct {
+	OK     bool                `json:"ok"`
+	Error  string              `json:"error,omitempty"`
+	Result domain.HotkeyResult `json:"result"`
+}
+
 func (c *Client) Start(ctx context.Context) (domain.Status, error) {
 	var env envelope
 	if err := c.call(ctx, http.MethodPost, "/v1/session/start", nil, &env); err != nil {
@@ -82,6 +119,43 @@ func (c *Client) Transcript(ctx context.Context) (time.Time, domain.StopResult,
 	return env.Captured, env.Result, nil
 }
 
+// Targets lists the daemon's output targets.
+func (c *Client) Targets(ctx context.Context) ([]domain.OutputTargetInfo, error) {
+	var env targetsEnvelope
+	if err := c.call(ctx, http.MethodGet, "/v1/targets", nil, &env); err != nil {
+		return nil, err
+	}
+	return env.Targets, nil
+}
+
+// SelectTarget switches the daemon to the named output target and returns
+// the updated list.
+func (c *Client) SelectTarget(ctx context.Context, name string) ([]domain.OutputTargetInfo, error) {
+	var env targetsEnvelope
+	if err := c.call(ctx, http.MethodPost, "/v1/targets/select", map[string]string{"name": name}, &env); err != nil {
+		return nil, err
+	}
+	return env.Targets, nil
+}
+
+// Hotkeys lists the daemon's configured hotkeys.
+func (c *Client) Hotkeys(ctx context.Context) ([]domain.Hotkey, error) {
+	var env hotkeysEnvelope
+	if err := c.call(ctx, http.MethodGet, "/v1/hotkeys", nil, &env); err != nil {
+		return nil, err
+	}
+	return env.Hotkeys, nil
+}
+
+// TriggerHotkey runs the named hotkey on the daemon.
+func (c *Client) TriggerHotkey(ctx context.Context, name string) (domain.HotkeyResult, error) {
+	var env hotkeyEnvelope
+	if err := c.call(ctx, http.MethodPost, "/v1/hotkeys/trigger", map[string]string{"name": name}, &env); err != nil {
+		return domain.HotkeyResult{}, err
+	}
+	return env.Result, nil
+}
+
 func (c *Client) call(ctx context.Context, method string, path string, payload any, out any) error {
 	var body io.Reader
 	if payload != nil {
@@ -97,6 +171,9 @@ func (c *Client) call(ctx context.Context, method string, path string, payload a
 		return err
 	}
 	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
 	if payload != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
@@ -119,6 +196,12 @@ func (c *Client) call(ctx context.Context, method string, path string, payload a
 			return newHTTPError(resp.StatusCode, v.Error)
 		case *transcriptEnvelope:
 			return newHTTPError(resp.StatusCode, v.Error)
+		case *targetsEnvelope:
+			return newHTTPError(resp.StatusCode, v.Error)
+		case *hotkeysEnvelope:
+			return newHTTPError(resp.StatusCode, v.Error)
+		case *hotkeyEnvelope:
+			return newHTTPError(resp.StatusCode, v.Error)
 		default:
 			return newHTTPError(resp.StatusCode, "request failed")
 		}
@@ -6,6 +6,7 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"testing"
 )
 
@@ -260,3 +261,49 @@ func TestClientCallPayloadSetsJSONHeader(t *testing.T) {
 		t.Fatalf("unexpected content-type header: %s", gotContentType)
 	}
 }
+
+func TestClientDialsUnixSocket(t *testing.T) {
+	t.Parallel()
+
+	socket := filepath.Join(t.TempDir(), "coldmicd.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	server := &httptest.Server{
+		Listener: listener,
+		Config: &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"ok":true,"status":{"state":"idle","active":false}}`))
+		})},
+	}
+	server.Start()
+	defer server.Close()
+
+	status, err := NewClient("unix://" + socket).Status(context.Background())
+	if err != nil {
+		t.Fatalf("status failed: %v", err)
+	}
+	if status.State != "idle" {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+}
+
+func TestClientSendsBearerToken(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer cm_secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"ok":false,"error":"missing_token"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"status":{"state":"idle","active":false}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.SetToken(" cm_secret ")
+	if _, err := client.Status(context.Background()); err != nil {
+		t.Fatalf("status failed: %v", err)
+	}
+}
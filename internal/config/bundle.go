@@ -0,0 +1,349 @@
+package config
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const bundleVersion = 1
+
+// Bundle file names for the pieces of configuration kept outside the
+// environment. Forms are stored as "forms/<name>".
+const (
+	bundleRulesFile    = "substitutions.rules"
+	bundleProfilesFile = "deepgram-profiles.json"
+	bundleTargetsFile  = "targets.json"
+	bundleHotkeysFile  = "hotkeys.json"
+	bundleMIDIFile     = "midi.json"
+	bundleFormsPrefix  = "forms/"
+)
+
+// settingPrefixes are the environment variables coldmic reads.
+var settingPrefixes = []string{
+	"COLDMIC_", "DEEPGRAM_", "ASSEMBLYAI_", "AZURE_SPEECH_", "GLADIA_", "GROQ_", "OPENAI_", "TODO_FILE",
+}
+
+// ConfigBundle is a portable copy of a coldmic setup. Secrets are never
+// included; Secrets lists the variables that have to be set again.
+type ConfigBundle struct {
+	Version   int               `json:"version"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Workspace string            `json:"workspace"`
+	Settings  map[string]string `json:"settings"`
+	Secrets   []string          `json:"secrets,omitempty"`
+	Files     map[string]string `json:"files"`
+}
+
+// ImportResult reports where an imported bundle was written.
+type ImportResult struct {
+	Files        []string `json:"files"`
+	SettingsPath string   `json:"settingsPath,omitempty"`
+	Secrets      []string `json:"secrets,omitempty"`
+}
+
+// ExportBundle writes the settings, rules (which double as the dictionary),
+// Deepgram profiles, targets, hotkeys, MIDI bindings and forms of cfg to w as a gzipped JSON
+// bundle. Paths under the home directory are written relative to "~".
+func ExportBundle(cfg Config, w io.Writer) error {
+	home, _ := os.UserHomeDir()
+	bundle := ConfigBundle{
+		Version:   bundleVersion,
+		CreatedAt: time.Now().UTC(),
+		Workspace: cfg.Workspace,
+		Settings:  map[string]string{},
+		Files:     map[string]string{},
+	}
+	referenced := credentialVariables(cfg)
+	for _, pair := range os.Environ() {
+		name, value, _ := strings.Cut(pair, "=")
+		if referenced[name] {
+			bundle.Secrets = append(bundle.Secrets, name)
+			continue
+		}
+		if !isSetting(name) {
+			continue
+		}
+		if isSecretSetting(name) {
+			bundle.Secrets = append(bundle.Secrets, name)
+			continue
+		}
+		if stripped, ok := stripURLCredentials(value); ok {
+			// The URL is kept, but its credentials have to be set again.
+			value = stripped
+			bundle.Secrets = append(bundle.Secrets, name)
+		}
+		bundle.Settings[name] = collapseHome(value, home)
+	}
+	slices.Sort(bundle.Secrets)
+
+	if err := addBundleFile(bundle.Files, bundleRulesFile, cfg.Rules.Path); err != nil {
+		return err
+	}
+	if err := addBundleFile(bundle.Files, bundleProfilesFile, cfg.DeepgramProfilesPath); err != nil {
+		return err
+	}
+	if err := addBundleFile(bundle.Files, bundleTargetsFile, cfg.TargetsPath); err != nil {
+		return err
+	}
+	if err := addBundleFile(bundle.Files, bundleHotkeysFile, cfg.HotkeysPath); err != nil {
+		return err
+	}
+	if err := addBundleFile(bundle.Files, bundleMIDIFile, cfg.MIDIPath); err != nil {
+		return err
+	}
+	if cfg.Forms.Dir != "" {
+		forms, err := os.ReadDir(cfg.Forms.Dir)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to read forms directory: %w", err)
+		}
+		for _, form := range forms {
+			if form.IsDir() {
+				continue
+			}
+			if err := addBundleFile(bundle.Files, bundleFormsPrefix+form.Name(), filepath.Join(cfg.Forms.Dir, form.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(bundle); err != nil {
+		return fmt.Errorf("failed to encode config bundle: %w", err)
+	}
+	return zw.Close()
+}
+
+// ImportBundle reads a bundle written by ExportBundle and installs its files
+// at the paths cfg uses, keeping any file it replaces as "<name>.bak".
+// Settings cannot be applied to a running process, so they are written as an
+// env file next to the rules for the user to load.
+func ImportBundle(cfg Config, r io.Reader) (ImportResult, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return ImportResult{}, fmt.Errorf("failed to read config bundle: %w", err)
+	}
+	defer zr.Close()
+	var bundle ConfigBundle
+	if err := json.NewDecoder(zr).Decode(&bundle); err != nil {
+		return ImportResult{}, fmt.Errorf("failed to decode config bundle: %w", err)
+	}
+	if bundle.Version != bundleVersion {
+		return ImportResult{}, fmt.Errorf("unsupported config bundle version %d", bundle.Version)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ImportResult{}, errors.New("could not determine home directory")
+	}
+
+	result := ImportResult{Secrets: bundle.Secrets}
+	names := make([]string, 0, len(bundle.Files))
+	for name := range bundle.Files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		path, err := bundleFilePath(cfg, home, name)
+		if err != nil {
+			return result, err
+		}
+		if err := installFile(path, bundle.Files[name]); err != nil {
+			return result, err
+		}
+		result.Files = append(result.Files, path)
+	}
+
+	if len(bundle.Settings) > 0 {
+		result.SettingsPath = filepath.Join(home, ".config", "coldmic", "imported.env")
+		if err := installFile(result.SettingsPath, formatEnvFile(bundle.Settings, home)); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func addBundleFile(files map[string]string, name string, path string) error {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	files[name] = string(data)
+	return nil
+}
+
+func bundleFilePath(cfg Config, home string, name string) (string, error) {
+	switch {
+	case name == bundleRulesFile:
+		if cfg.Rules.Path != "" {
+			return cfg.Rules.Path, nil
+		}
+		return filepath.Join(home, ".config", "coldmic", "substitutions.rules"), nil
+	case name == bundleProfilesFile && cfg.DeepgramProfilesPath != "":
+		return cfg.DeepgramProfilesPath, nil
+	case name == bundleTargetsFile && cfg.TargetsPath != "":
+		return cfg.TargetsPath, nil
+	case name == bundleHotkeysFile && cfg.HotkeysPath != "":
+		return cfg.HotkeysPath, nil
+	case name == bundleMIDIFile && cfg.MIDIPath != "":
+		return cfg.MIDIPath, nil
+	case strings.HasPrefix(name, bundleFormsPrefix) && cfg.Forms.Dir != "":
+		form := strings.TrimPrefix(name, bundleFormsPrefix)
+		if form == "" || form != filepath.Base(form) || form == "." || form == ".." {
+			return "", fmt.Errorf("invalid form name %q in config bundle", form)
+		}
+		return filepath.Join(cfg.Forms.Dir, form), nil
+	}
+	return "", fmt.Errorf("unexpected file %q in config bundle", name)
+}
+
+// installFile writes data to path, keeping an existing, different file as a
+// backup.
+func installFile(path string, data string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if existing, err := os.ReadFile(path); err == nil {
+		if string(existing) == data {
+			return nil
+		}
+		if err := os.WriteFile(path+".bak", existing, 0o600); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+	}
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func formatEnvFile(settings map[string]string, home string) string {
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var b strings.Builder
+	b.WriteString("# Imported coldmic settings. Secrets are not included.\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s=%s\n", name, shellQuote(expandHome(settings[name], home)))
+	}
+	return b.String()
+}
+
+func isSetting(name string) bool {
+	for _, prefix := range settingPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSecretSetting reports whether name holds a credential, such as
+// DEEPGRAM_API_KEY_WORK or COLDMIC_GITHUB_TOKEN.
+func isSecretSetting(name string) bool {
+	for _, part := range strings.Split(name, "_") {
+		switch part {
+		case "KEY", "TOKEN", "SECRET", "PASSWORD", "PASSPHRASE", "HEADER", "HEADERS":
+			return true
+		}
+	}
+	return false
+}
+
+// credentialVariables returns the variables that Deepgram profiles and
+// targets name as holding their keys, whatever those are called.
+func credentialVariables(cfg Config) map[string]bool {
+	names := map[string]bool{}
+	for _, profile := range cfg.DeepgramProfiles {
+		if profile.KeyEnv != "" {
+			names[profile.KeyEnv] = true
+		}
+	}
+	for _, target := range cfg.Targets {
+		if target.TokenEnv != "" {
+			names[target.TokenEnv] = true
+		}
+	}
+	return names
+}
+
+// stripURLCredentials removes the user info and credential-like query
+// parameters, such as token or sig, from a URL value. It reports whether
+// anything was removed.
+func stripURLCredentials(value string) (string, bool) {
+	if !strings.Contains(value, "://") {
+		return value, false
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return value, false
+	}
+	stripped := u.User != nil
+	u.User = nil
+	query := u.Query()
+	for param := range query {
+		if isCredentialParam(param) {
+			query.Del(param)
+			stripped = true
+		}
+	}
+	if !stripped {
+		return value, false
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), true
+}
+
+func isCredentialParam(param string) bool {
+	name := strings.ToUpper(strings.ReplaceAll(param, "-", "_"))
+	for _, part := range strings.Split(name, "_") {
+		switch part {
+		case "APIKEY", "AUTH", "AUTHORIZATION", "SIG", "SIGNATURE", "CREDENTIAL":
+			return true
+		}
+	}
+	return isSecretSetting(name)
+}
+
+func collapseHome(value string, home string) string {
+	if home == "" {
+		return value
+	}
+	if value == home {
+		return "~"
+	}
+	if rest, ok := strings.CutPrefix(value, home+string(filepath.Separator)); ok {
+		return "~/" + filepath.ToSlash(rest)
+	}
+	return value
+}
+
+func expandHome(value string, home string) string {
+	if value == "~" {
+		return home
+	}
+	if rest, ok := strings.CutPrefix(value, "~/"); ok {
+		return filepath.Join(home, filepath.FromSlash(rest))
+	}
+	return value
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -2,19 +2,87 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+
+	"coldmic/internal/domain"
 )
 
+// DefaultWorkspace uses the top-level data directory and rules file.
+const DefaultWorkspace = "default"
+
 // Config stores runtime configuration for the tracer bullet.
 type Config struct {
-	Deepgram DeepgramConfig
-	Audio    AudioConfig
-	Rules    RulesConfig
-	Session  SessionConfig
+	Workspace string
+	// Provider names the live transcription provider: "deepgram",
+	// "assemblyai", "azure", "gladia", "openai", "groq", "whispercpp",
+	// "customws" or "replay".
+	Provider string
+	// DryRun captures and runs the full event pipeline but replaces every
+	// transcription provider with the replay provider, so nothing is sent
+	// to a paid API.
+	DryRun bool
+	// SimulateEvents lets the frontend emit session, partial, final and
+	// error events itself, for UI development.
+	SimulateEvents bool
+	Deepgram       DeepgramConfig
+	AssemblyAI     AssemblyAIConfig
+	Azure          AzureConfig
+	Gladia         GladiaConfig
+	OpenAI         OpenAIConfig
+	Groq           GroqConfig
+	WhisperCpp     WhisperCppConfig
+	CustomWS       CustomWSConfig
+	Replay         ReplayConfig
+	Audio          AudioConfig
+	Rules          RulesConfig
+	Session        SessionConfig
+	Storage        StorageConfig
+	Watch          WatchConfig
+	Batch          BatchConfig
+	Ingest         IngestConfig
+	Translation    TranslationConfig
+	Meeting        MeetingConfig
+	Timestamps     TimestampConfig
+	Trim           TrimConfig
+	Backup         BackupConfig
+	Network        NetworkConfig
+	Clipboard      ClipboardConfig
+	Forms          FormsConfig
+	Target         TargetConfig
+	Accessibility  AccessibilityConfig
+	Retry          RetryConfig
+	Accurate       AccurateConfig
+	Casing         CasingConfig
+	Normalize      NormalizeConfig
+	Redaction      RedactionConfig
+	Race           RaceConfig
+	Trigger        TriggerConfig
+	Usage          UsageConfig
+	// DeepgramProfiles are named self-hosted endpoints by profile name,
+	// read from DeepgramProfilesPath.
+	DeepgramProfiles     map[string]DeepgramProfile
+	DeepgramProfilesPath string
+	// Targets are named output targets read from TargetsPath.
+	Targets     map[string]TargetDefinition
+	TargetsPath string
+	// Hotkeys are the named hotkeys read from HotkeysPath.
+	Hotkeys     []domain.Hotkey
+	HotkeysPath string
+	// MIDI binds controls of Trigger.MIDIDevice to hotkeys, read from
+	// MIDIPath.
+	MIDI     []domain.MIDIBinding
+	MIDIPath string
+
+	// deepgramDefault is Deepgram before any profile was applied.
+	deepgramDefault DeepgramConfig
 }
 
 type DeepgramConfig struct {
@@ -23,6 +91,122 @@ type DeepgramConfig struct {
 	Model       string
 	Language    string
 	SmartFormat bool
+	// Profile names the endpoint profile applied, if any.
+	Profile string
+	// Auth is token, bearer, none or header:<Name>; empty means token.
+	Auth string
+	TLS  DeepgramTLSConfig
+	// Project names the Deepgram project billed, whose key is read from
+	// DEEPGRAM_PROJECT_KEY_<PROJECT>.
+	Project string
+	// Tags label requests in the Deepgram console, with {user}, {host} and
+	// {workspace} expanded.
+	Tags     []string
+	Callback string
+	// Diarize labels speakers, turning transcripts into "Speaker 1: ..." lines.
+	Diarize bool
+	// Endpointing is the silence in milliseconds that ends an utterance, or
+	// "false" to disable it; empty keeps Deepgram's default.
+	Endpointing string
+	// UtteranceEndMS asks for an utterance end after this long without
+	// words, which finalizes speech even over background noise; 0 disables.
+	UtteranceEndMS int
+	VADEvents      bool
+	// Punctuate adds punctuation and capitals, Numerals writes numbers as
+	// digits and FillerWords keeps "uh" and "um"; each is independent of
+	// SmartFormat.
+	Punctuate   bool
+	Numerals    bool
+	FillerWords bool
+	// ProfanityFilter masks profanity before transcripts are output.
+	ProfanityFilter bool
+	// Redact lists the DeepgramRedactions masked before transcripts are
+	// output.
+	Redact []string
+	// DetectLanguage reports the detected language with each transcript.
+	DetectLanguage bool
+	// Proxy is Network.Proxy, kept here for the providers built from
+	// Deepgram settings alone.
+	Proxy string
+	// DialRetries is how many times a transient failure to open the stream
+	// is retried before the recording fails.
+	DialRetries int
+}
+
+// DeepgramRedactions are the entities Deepgram can redact.
+var DeepgramRedactions = []string{"pci", "ssn", "numbers"}
+
+// historyFileNames are the default history paths in the data directory by
+// COLDMIC_HISTORY_BACKEND.
+var historyFileNames = map[string]string{
+	"jsonl":    "history.jsonl",
+	"sqlite":   "history.db",
+	"markdown": "history",
+}
+
+type AssemblyAIConfig struct {
+	APIKey      string
+	APIBaseURL  string
+	Model       string
+	SmartFormat bool
+}
+
+type AzureConfig struct {
+	APIKey     string
+	Region     string
+	APIBaseURL string
+	Language   string
+}
+
+// CustomWSConfig points at a self-hosted server speaking coldmic's websocket
+// protocol.
+type CustomWSConfig struct {
+	URL string
+	// Header is an optional "Name: value" header sent on connect.
+	Header string
+}
+
+// ReplayConfig plays a scripted transcript for development.
+type ReplayConfig struct {
+	// Path is a JSON fixture of transcript events; empty plays a built-in
+	// script.
+	Path  string
+	Speed float64
+}
+
+type GladiaConfig struct {
+	APIKey     string
+	APIBaseURL string
+	Language   string
+}
+
+type OpenAIConfig struct {
+	APIKey     string
+	APIBaseURL string
+	Model      string
+	Language   string
+}
+
+type GroqConfig struct {
+	APIKey     string
+	APIBaseURL string
+	Model      string
+	Language   string
+}
+
+// WhisperCppConfig runs the whisper.cpp CLI for offline transcription.
+type WhisperCppConfig struct {
+	Command   string
+	ModelPath string
+	Language  string
+	Threads   int
+	// Acceleration is auto, cpu, cuda, vulkan, metal or coreml.
+	Acceleration string
+	Device       int
+	// Step is the new audio that triggers a live partial; 0 disables them.
+	Step    time.Duration
+	Window  time.Duration
+	Overlap time.Duration
 }
 
 type AudioConfig struct {
@@ -31,6 +215,18 @@ type AudioConfig struct {
 	InputDevice     string
 	SampleRate      int
 	Channels        int
+	// DeviceSampleRate and DeviceChannels override the detected native
+	// capture format; zero detects it.
+	DeviceSampleRate int
+	DeviceChannels   int
+	// Multichannel is off, merge or label: with more than one channel, merge
+	// and label transcribe each channel separately and interleave or label
+	// the results. ChannelLabels name the channels in order.
+	Multichannel  string
+	ChannelLabels []string
+	// PlaybackSink is the Pulse/PipeWire sink saved audio plays to; empty
+	// uses the default sink.
+	PlaybackSink string
 }
 
 type RulesConfig struct {
@@ -41,10 +237,264 @@ type RulesConfig struct {
 type SessionConfig struct {
 	ChunkSize      int
 	StreamingGrace time.Duration
+	// PreStartHook runs before push-to-talk capture begins and PostStopHook
+	// once it stops, for example to pause and resume music.
+	PreStartHook         []string
+	PostStopHook         []string
+	RecordingHookTimeout time.Duration
+	// Keywords are names and terms the provider should favour.
+	Keywords []string
+	// PauseMedia pauses playing MPRIS media players while recording.
+	PauseMedia bool
+	// DuckStreams lowers ("duck") or mutes ("mute") other applications'
+	// audio while recording; "off" leaves it alone.
+	DuckStreams string
+	DuckLevel   int
+	// RecordingLEDs lights these kernel LEDs while recording; "auto"
+	// selects the mic-mute LED. RecordingLEDColor is an optional RRGGBB
+	// color for multicolor LEDs.
+	RecordingLEDs     []string
+	RecordingLEDColor string
+	// IdleAbort ends a recording once neither a transcript nor audio above
+	// IdleThreshold RMS has arrived for this long; zero disables.
+	IdleAbort     time.Duration
+	IdleThreshold int
+	// Preflight checks the provider credentials at startup.
+	Preflight bool
+	// IncrementalCopy copies each finished utterance while recording, at
+	// most once per CopyInterval.
+	IncrementalCopy bool
+	CopyInterval    time.Duration
+}
+
+type StorageConfig struct {
+	DataDir       string
+	HistoryPath   string
+	SaveAudio     bool
+	RecordingsDir string
+	MinFreeMB     int
+	TokensPath    string
+	CacheDir      string
+	CacheMaxMB    int
+	JobsPath      string
+	// CorrectionsPath logs words fixed with CorrectWord.
+	CorrectionsPath string
+	// ModelsDir holds downloaded local models, shared by all workspaces.
+	ModelsDir string
+	// ModelCatalog is an optional JSON file adding or overriding models.
+	ModelCatalog string
+	// PanicPurge makes a panic wipe also delete today's history and
+	// recordings.
+	PanicPurge bool
+	// HistoryBackend is "jsonl", "sqlite" or "markdown"; HistoryPath is the
+	// JSON-lines file, the database, or the directory of day files.
+	HistoryBackend string
+}
+
+type WatchConfig struct {
+	Dir       string
+	OutputDir string
+	Interval  time.Duration
+}
+
+type BatchConfig struct {
+	Concurrency int
+}
+
+type IngestConfig struct {
+	DownloaderCommand string
+}
+
+type TranslationConfig struct {
+	TargetLanguage string
+	SourceLanguage string
+	APIBaseURL     string
+	APIKey         string
+}
+
+type MeetingConfig struct {
+	MicLabel      string
+	DesktopLabel  string
+	DesktopDevice string
+}
+
+type TimestampConfig struct {
+	Mode     string
+	Template string
+	Layout   string
+}
+
+type TrimConfig struct {
+	Enabled   bool
+	Threshold int
+	Padding   time.Duration
+}
+
+type ClipboardConfig struct {
+	Split        string
+	SplitDelay   time.Duration
+	SplitCommand []string
+	// CopyHooks run after each copy with the copied text on stdin.
+	CopyHooks       [][]string
+	CopyHookTimeout time.Duration
+	// MaxChars overflows longer transcripts to a file in OverflowDir
+	// (the system temp directory when empty); 0 disables the limit.
+	MaxChars    int
+	Overflow    string
+	OverflowDir string
+}
+
+// FormsConfig locates form schemas. Active names the form applied at startup.
+type FormsConfig struct {
+	Dir    string
+	Active string
+}
+
+// AccessibilityConfig controls status announcements for screen-reader users.
+type AccessibilityConfig struct {
+	// Verbosity is off, minimal, or full.
+	Verbosity string
+	// Backend is speech-dispatcher or notify.
+	Backend string
+	Command string
+}
+
+// TargetConfig selects the output target applied at startup and configures
+// the targets that need it.
+type TargetConfig struct {
+	Name           string
+	GitCommitRepo  string
+	GitCommitRules string
+	GitHubAPIURL   string
+	GitHubToken    string
+	GitHubRepo     string
+	JiraURL        string
+	JiraEmail      string
+	JiraToken      string
+	JiraProject    string
+	JiraIssueType  string
+	ReminderDir    string
+	// ReminderCommand replaces the ICS directory when set.
+	ReminderCommand []string
+	TodoFile        string
+	TaskCommand     string
+	// TaskProject and TaskContext tag captured tasks. The project defaults to
+	// the workspace name outside the default workspace.
+	TaskProject string
+	TaskContext string
+}
+
+// NetworkConfig controls connectivity checks and the fallback provider used
+// while offline or on a metered connection.
+type NetworkConfig struct {
+	// CheckInterval defaults to zero, no checks, unless a fallback is set.
+	CheckInterval time.Duration
+	// FallbackProvider is deepgram, for the endpoint at FallbackURL, or
+	// whispercpp; empty means no fallback.
+	FallbackProvider  string
+	FallbackURL       string
+	FallbackModel     string
+	FallbackOnMetered bool
+	// Proxy routes provider connections through an http:// or socks5://
+	// proxy; empty uses HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+	Proxy string
+}
+
+// TriggerConfig selects an input device button, such as a USB foot pedal,
+// that drives push-to-talk. KeyCode 0 accepts any key of the device.
+// MIDIDevice is a raw MIDI controller whose controls trigger hotkeys.
+// GamepadDevice is a gamepad whose GamepadButton, a name such as "a" or a
+// key code, toggles dictation.
+type TriggerConfig struct {
+	Device        string
+	KeyCode       int
+	MIDIDevice    string
+	GamepadDevice string
+	GamepadButton string
+}
+
+// UsageConfig prices the audio sent to providers. Rates maps a provider
+// name to its cost per audio minute; providers without a rate are tracked
+// at no cost.
+type UsageConfig struct {
+	Path  string
+	Rates map[string]float64
 }
 
-// Load resolves configuration from environment variables and sensible defaults.
+// CasingConfig picks the capitalization of final output. Targets overrides
+// Policy per output target name; Words keeps known spellings.
+type CasingConfig struct {
+	Policy  string
+	Targets map[string]string
+	Words   []string
+}
+
+// NormalizeConfig lists the output normalizations ("trim", "collapse-spaces",
+// "punctuation", "newline") applied by default and per output target.
+type NormalizeConfig struct {
+	Options []string
+	Targets map[string][]string
+}
+
+// RedactionConfig screens audio with local whisper.cpp before it is sent to
+// a cloud provider.
+type RedactionConfig struct {
+	// Mode is off, bleep or skip.
+	Mode      string
+	Segment   time.Duration
+	MinDigits int
+}
+
+// RaceConfig streams every recording to a second provider as well, to
+// compare providers on the same speech.
+type RaceConfig struct {
+	// Provider names the second provider; empty disables the race.
+	Provider string
+	// Pick is confidence or first.
+	Pick string
+}
+
+// RetryConfig controls re-transcribing low-confidence sessions with a more
+// accurate model or a second Deepgram-compatible endpoint.
+type RetryConfig struct {
+	MinConfidence float64
+	Model         string
+	APIBaseURL    string
+}
+
+// AccurateConfig enables a second, slower transcription of every recording
+// on Stop; live partials keep coming from the primary model.
+type AccurateConfig struct {
+	Model      string
+	APIBaseURL string
+}
+
+type BackupConfig struct {
+	Target     string
+	URL        string
+	Region     string
+	Username   string
+	Password   string
+	Passphrase string
+	Interval   time.Duration
+	ObjectName string
+}
+
+// Load resolves configuration from environment variables and sensible defaults,
+// scoped to the workspace named by COLDMIC_WORKSPACE.
 func Load() (Config, error) {
+	return LoadWorkspace(os.Getenv("COLDMIC_WORKSPACE"))
+}
+
+// LoadWorkspace resolves configuration for a named workspace. Non-default
+// workspaces get their own data directory, history, recordings, and rules
+// file, and may use a separate DEEPGRAM_API_KEY_<NAME>.
+func LoadWorkspace(name string) (Config, error) {
+	workspace, err := NormalizeWorkspace(name)
+	if err != nil {
+		return Config{}, err
+	}
+
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return Config{}, errors.New("could not determine home directory")
@@ -57,33 +507,300 @@ func Load() (Config, error) {
 		rulesPath = firstExisting(defaultRules, hyprRules)
 	}
 
+	historyBackend := strings.ToLower(envOrDefault("COLDMIC_HISTORY_BACKEND", "jsonl"))
+	historyName, ok := historyFileNames[historyBackend]
+	if !ok {
+		return Config{}, fmt.Errorf("COLDMIC_HISTORY_BACKEND: unsupported backend %q (expected jsonl, sqlite or markdown)", historyBackend)
+	}
+	dataDir := envOrDefault("COLDMIC_DATA_DIR", filepath.Join(home, ".local", "share", "coldmic"))
+	historyPath := envOrDefault("COLDMIC_HISTORY_FILE", filepath.Join(dataDir, historyName))
+	correctionsPath := envOrDefault("COLDMIC_CORRECTIONS_FILE", filepath.Join(dataDir, "corrections.jsonl"))
+	recordingsDir := envOrDefault("COLDMIC_RECORDINGS_DIR", filepath.Join(dataDir, "recordings"))
+	formsDir := envOrDefault("COLDMIC_FORMS_DIR", filepath.Join(home, ".config", "coldmic", "forms"))
+	modelsDir := envOrDefault("COLDMIC_MODELS_DIR", filepath.Join(dataDir, "models"))
+	fallbackURL := strings.TrimSpace(os.Getenv("COLDMIC_FALLBACK_DEEPGRAM_URL"))
+	fallbackProvider := strings.ToLower(strings.TrimSpace(os.Getenv("COLDMIC_FALLBACK_PROVIDER")))
+	if fallbackProvider == "" && fallbackURL != "" {
+		fallbackProvider = "deepgram"
+	}
+	networkCheckMS := 0
+	if fallbackProvider != "" {
+		networkCheckMS = 10000
+	}
+	// Access tokens guard the one daemon, whichever workspace created them.
+	tokensPath := filepath.Join(dataDir, "tokens.json")
+	backupObject := "coldmic-history.bundle"
+	apiKey := strings.TrimSpace(os.Getenv("DEEPGRAM_API_KEY"))
+	if workspace != DefaultWorkspace {
+		// Path overrides apply to the default workspace only, so workspaces never share state.
+		rulesPath = filepath.Join(home, ".config", "coldmic", "workspaces", workspace, "substitutions.rules")
+		dataDir = filepath.Join(dataDir, "workspaces", workspace)
+		historyPath = filepath.Join(dataDir, historyName)
+		correctionsPath = filepath.Join(dataDir, "corrections.jsonl")
+		recordingsDir = filepath.Join(dataDir, "recordings")
+		formsDir = filepath.Join(home, ".config", "coldmic", "workspaces", workspace, "forms")
+		backupObject = "coldmic-history-" + workspace + ".bundle"
+		apiKey = firstNonEmpty(os.Getenv("DEEPGRAM_API_KEY_"+workspaceEnvSuffix(workspace)), apiKey)
+	}
+
 	cfg := Config{
+		Workspace: workspace,
 		Deepgram: DeepgramConfig{
-			APIKey:      strings.TrimSpace(os.Getenv("DEEPGRAM_API_KEY")),
-			APIBaseURL:  envOrDefault("DEEPGRAM_API_BASE", "https://api.deepgram.com/v1"),
-			Model:       envOrDefault("DEEPGRAM_MODEL", "nova-2"),
-			Language:    strings.TrimSpace(os.Getenv("DEEPGRAM_LANGUAGE")),
-			SmartFormat: envOrDefaultBool("DEEPGRAM_SMART_FORMAT", true),
+			APIKey:          apiKey,
+			APIBaseURL:      envOrDefault("DEEPGRAM_API_BASE", "https://api.deepgram.com/v1"),
+			Model:           envOrDefault("DEEPGRAM_MODEL", "nova-2"),
+			Language:        strings.TrimSpace(os.Getenv("DEEPGRAM_LANGUAGE")),
+			SmartFormat:     envOrDefaultBool("DEEPGRAM_SMART_FORMAT", true),
+			Project:         strings.ToLower(strings.TrimSpace(os.Getenv("COLDMIC_DEEPGRAM_PROJECT"))),
+			Tags:            expandTags(phraseList(os.Getenv("COLDMIC_DEEPGRAM_TAGS")), workspace),
+			Callback:        strings.TrimSpace(os.Getenv("COLDMIC_DEEPGRAM_CALLBACK")),
+			Diarize:         envOrDefaultBool("COLDMIC_DEEPGRAM_DIARIZE", false),
+			Endpointing:     strings.ToLower(strings.TrimSpace(os.Getenv("COLDMIC_DEEPGRAM_ENDPOINTING"))),
+			VADEvents:       envOrDefaultBool("COLDMIC_DEEPGRAM_VAD_EVENTS", false),
+			UtteranceEndMS:  envOrDefaultInt("COLDMIC_DEEPGRAM_UTTERANCE_END_MS", 0),
+			Punctuate:       envOrDefaultBool("COLDMIC_DEEPGRAM_PUNCTUATE", false),
+			Numerals:        envOrDefaultBool("COLDMIC_DEEPGRAM_NUMERALS", false),
+			FillerWords:     envOrDefaultBool("COLDMIC_DEEPGRAM_FILLER_WORDS", false),
+			ProfanityFilter: envOrDefaultBool("COLDMIC_DEEPGRAM_PROFANITY_FILTER", false),
+			Redact:          strings.FieldsFunc(strings.ToLower(os.Getenv("COLDMIC_DEEPGRAM_REDACT")), isListSeparator),
+			DetectLanguage:  envOrDefaultBool("COLDMIC_DEEPGRAM_DETECT_LANGUAGE", false),
+			DialRetries:     envOrDefaultInt("COLDMIC_DEEPGRAM_DIAL_RETRIES", 2),
+			TLS: DeepgramTLSConfig{
+				CAFile:     strings.TrimSpace(os.Getenv("COLDMIC_DEEPGRAM_CA_FILE")),
+				ClientCert: strings.TrimSpace(os.Getenv("COLDMIC_DEEPGRAM_CLIENT_CERT")),
+				ClientKey:  strings.TrimSpace(os.Getenv("COLDMIC_DEEPGRAM_CLIENT_KEY")),
+			},
+		},
+		Provider:       strings.ToLower(envOrDefault("COLDMIC_PROVIDER", "deepgram")),
+		DryRun:         envOrDefaultBool("COLDMIC_DRY_RUN", false),
+		SimulateEvents: envOrDefaultBool("COLDMIC_SIMULATE_EVENTS", false),
+		AssemblyAI: AssemblyAIConfig{
+			APIKey:      strings.TrimSpace(os.Getenv("ASSEMBLYAI_API_KEY")),
+			APIBaseURL:  envOrDefault("ASSEMBLYAI_API_BASE", "wss://streaming.assemblyai.com/v3"),
+			Model:       envOrDefault("COLDMIC_ASSEMBLYAI_MODEL", "universal-streaming-english"),
+			SmartFormat: envOrDefaultBool("COLDMIC_ASSEMBLYAI_FORMAT_TURNS", true),
+		},
+		Azure: AzureConfig{
+			APIKey:     strings.TrimSpace(os.Getenv("AZURE_SPEECH_KEY")),
+			Region:     strings.TrimSpace(os.Getenv("AZURE_SPEECH_REGION")),
+			APIBaseURL: strings.TrimSpace(os.Getenv("AZURE_SPEECH_ENDPOINT")),
+			Language:   envOrDefault("AZURE_SPEECH_LANGUAGE", "en-US"),
+		},
+		Gladia: GladiaConfig{
+			APIKey:     strings.TrimSpace(os.Getenv("GLADIA_API_KEY")),
+			APIBaseURL: envOrDefault("GLADIA_API_BASE", "https://api.gladia.io"),
+			Language:   firstNonEmpty(os.Getenv("COLDMIC_GLADIA_LANGUAGE"), os.Getenv("DEEPGRAM_LANGUAGE")),
+		},
+		Replay: ReplayConfig{
+			Path:  strings.TrimSpace(os.Getenv("COLDMIC_REPLAY_FILE")),
+			Speed: envOrDefaultFloat("COLDMIC_REPLAY_SPEED", 1),
+		},
+		CustomWS: CustomWSConfig{
+			URL:    strings.TrimSpace(os.Getenv("COLDMIC_CUSTOM_WS_URL")),
+			Header: strings.TrimSpace(os.Getenv("COLDMIC_CUSTOM_WS_HEADER")),
+		},
+		OpenAI: OpenAIConfig{
+			APIKey:     strings.TrimSpace(os.Getenv("OPENAI_API_KEY")),
+			APIBaseURL: envOrDefault("OPENAI_API_BASE", "https://api.openai.com/v1"),
+			Model:      envOrDefault("COLDMIC_OPENAI_MODEL", "whisper-1"),
+			Language:   firstNonEmpty(os.Getenv("COLDMIC_OPENAI_LANGUAGE"), os.Getenv("DEEPGRAM_LANGUAGE")),
+		},
+		Groq: GroqConfig{
+			APIKey:     strings.TrimSpace(os.Getenv("GROQ_API_KEY")),
+			APIBaseURL: envOrDefault("GROQ_API_BASE", "https://api.groq.com/openai/v1"),
+			Model:      envOrDefault("COLDMIC_GROQ_MODEL", "whisper-large-v3-turbo"),
+			Language:   firstNonEmpty(os.Getenv("COLDMIC_GROQ_LANGUAGE"), os.Getenv("DEEPGRAM_LANGUAGE")),
+		},
+		WhisperCpp: WhisperCppConfig{
+			Command:      envOrDefault("COLDMIC_WHISPERCPP_COMMAND", "whisper-cli"),
+			ModelPath:    strings.TrimSpace(os.Getenv("COLDMIC_WHISPERCPP_MODEL")),
+			Language:     firstNonEmpty(os.Getenv("COLDMIC_WHISPERCPP_LANGUAGE"), os.Getenv("DEEPGRAM_LANGUAGE")),
+			Threads:      envOrDefaultInt("COLDMIC_WHISPERCPP_THREADS", 0),
+			Acceleration: strings.ToLower(envOrDefault("COLDMIC_WHISPERCPP_ACCELERATION", "auto")),
+			Device:       envOrDefaultInt("COLDMIC_WHISPERCPP_DEVICE", 0),
+			Step:         time.Duration(envOrDefaultInt("COLDMIC_WHISPERCPP_STEP_MS", 3000)) * time.Millisecond,
+			Window:       time.Duration(envOrDefaultInt("COLDMIC_WHISPERCPP_WINDOW_MS", 15000)) * time.Millisecond,
+			Overlap:      time.Duration(envOrDefaultInt("COLDMIC_WHISPERCPP_OVERLAP_MS", 1000)) * time.Millisecond,
 		},
 		Audio: AudioConfig{
 			RecorderCommand: envOrDefault("COLDMIC_FFMPEG_COMMAND", "ffmpeg"),
-			InputFormat:     envOrDefault("COLDMIC_AUDIO_INPUT_FORMAT", "pulse"),
+			InputFormat:     envOrDefault("COLDMIC_AUDIO_INPUT_FORMAT", defaultInputFormat(runtime.GOOS)),
 			InputDevice: firstNonEmpty(
 				os.Getenv("COLDMIC_AUDIO_INPUT_DEVICE"),
 				os.Getenv("DEEPGRAM_PULSE_SOURCE"),
 				os.Getenv("WHISPER_PULSE_SOURCE"),
 				"default",
 			),
-			SampleRate: envOrDefaultInt("COLDMIC_SAMPLE_RATE", 16000),
-			Channels:   envOrDefaultInt("COLDMIC_CHANNELS", 1),
+			SampleRate:       envOrDefaultInt("COLDMIC_SAMPLE_RATE", 16000),
+			Channels:         envOrDefaultInt("COLDMIC_CHANNELS", 1),
+			DeviceSampleRate: envOrDefaultInt("COLDMIC_DEVICE_SAMPLE_RATE", 0),
+			DeviceChannels:   envOrDefaultInt("COLDMIC_DEVICE_CHANNELS", 0),
+			Multichannel:     strings.ToLower(envOrDefault("COLDMIC_MULTICHANNEL", "off")),
+			ChannelLabels:    phraseList(os.Getenv("COLDMIC_CHANNEL_LABELS")),
+			PlaybackSink:     strings.TrimSpace(os.Getenv("COLDMIC_PLAYBACK_SINK")),
 		},
 		Rules: RulesConfig{
 			Path:           rulesPath,
 			IterationLimit: envOrDefaultInt("COLDMIC_RULE_ITERATION_LIMIT", 30),
 		},
 		Session: SessionConfig{
-			ChunkSize:      envOrDefaultInt("COLDMIC_AUDIO_CHUNK_SIZE", 4096),
-			StreamingGrace: time.Duration(firstNonNegativeInt("COLDMIC_STREAMING_GRACE_MS", "DEEPGRAM_STREAMING_GRACE_MS", 1000)) * time.Millisecond,
+			ChunkSize:            envOrDefaultInt("COLDMIC_AUDIO_CHUNK_SIZE", 4096),
+			StreamingGrace:       time.Duration(firstNonNegativeInt("COLDMIC_STREAMING_GRACE_MS", "DEEPGRAM_STREAMING_GRACE_MS", 1000)) * time.Millisecond,
+			PreStartHook:         strings.Fields(os.Getenv("COLDMIC_PRE_START_HOOK")),
+			PostStopHook:         strings.Fields(os.Getenv("COLDMIC_POST_STOP_HOOK")),
+			RecordingHookTimeout: time.Duration(envOrDefaultInt("COLDMIC_RECORDING_HOOK_TIMEOUT_MS", 5000)) * time.Millisecond,
+			PauseMedia:           envOrDefaultBool("COLDMIC_PAUSE_MEDIA", false),
+			Keywords:             phraseList(os.Getenv("COLDMIC_KEYWORDS")),
+			DuckStreams:          strings.ToLower(envOrDefault("COLDMIC_DUCK_STREAMS", "off")),
+			DuckLevel:            envOrDefaultInt("COLDMIC_DUCK_LEVEL", 30),
+			RecordingLEDs:        strings.FieldsFunc(os.Getenv("COLDMIC_RECORDING_LED"), isListSeparator),
+			RecordingLEDColor:    strings.TrimPrefix(envOrDefault("COLDMIC_RECORDING_LED_COLOR", ""), "#"),
+			IdleAbort:            time.Duration(max(envOrDefaultInt("COLDMIC_IDLE_ABORT_SECONDS", 0), 0)) * time.Second,
+			IdleThreshold:        envOrDefaultInt("COLDMIC_IDLE_THRESHOLD", 500),
+			Preflight:            envOrDefaultBool("COLDMIC_PREFLIGHT", true),
+			IncrementalCopy:      envOrDefaultBool("COLDMIC_INCREMENTAL_COPY", false),
+			CopyInterval:         time.Duration(max(envOrDefaultInt("COLDMIC_INCREMENTAL_COPY_INTERVAL_MS", 1000), 0)) * time.Millisecond,
+		},
+		Storage: StorageConfig{
+			DataDir:         dataDir,
+			HistoryBackend:  historyBackend,
+			HistoryPath:     historyPath,
+			CorrectionsPath: correctionsPath,
+			SaveAudio:       envOrDefaultBool("COLDMIC_SAVE_AUDIO", false),
+			RecordingsDir:   recordingsDir,
+			MinFreeMB:       envOrDefaultInt("COLDMIC_MIN_FREE_DISK_MB", 500),
+			TokensPath:      tokensPath,
+			ModelsDir:       modelsDir,
+			ModelCatalog:    strings.TrimSpace(os.Getenv("COLDMIC_MODEL_CATALOG")),
+			CacheDir:        filepath.Join(dataDir, "cache"),
+			CacheMaxMB:      envOrDefaultInt("COLDMIC_CACHE_MAX_MB", 50),
+			JobsPath:        filepath.Join(dataDir, "jobs.json"),
+			PanicPurge:      envOrDefaultBool("COLDMIC_PANIC_PURGE", false),
+		},
+		Watch: WatchConfig{
+			Dir:       strings.TrimSpace(os.Getenv("COLDMIC_WATCH_DIR")),
+			OutputDir: strings.TrimSpace(os.Getenv("COLDMIC_WATCH_OUTPUT_DIR")),
+			Interval:  time.Duration(envOrDefaultInt("COLDMIC_WATCH_INTERVAL_MS", 2000)) * time.Millisecond,
+		},
+		Batch: BatchConfig{
+			Concurrency: firstNonNegativeInt("COLDMIC_BATCH_CONCURRENCY", "COLDMIC_WATCH_CONCURRENCY", 2),
+		},
+		Ingest: IngestConfig{
+			DownloaderCommand: envOrDefault("COLDMIC_YTDLP_COMMAND", "yt-dlp"),
+		},
+		Translation: TranslationConfig{
+			TargetLanguage: strings.TrimSpace(os.Getenv("COLDMIC_TRANSLATE_TARGET")),
+			SourceLanguage: envOrDefault("COLDMIC_TRANSLATE_SOURCE", "auto"),
+			APIBaseURL:     envOrDefault("COLDMIC_TRANSLATE_URL", "http://127.0.0.1:5000"),
+			APIKey:         strings.TrimSpace(os.Getenv("COLDMIC_TRANSLATE_API_KEY")),
+		},
+		Meeting: MeetingConfig{
+			MicLabel:      envOrDefault("COLDMIC_MEETING_MIC_LABEL", "Me"),
+			DesktopLabel:  envOrDefault("COLDMIC_MEETING_DESKTOP_LABEL", "Them"),
+			DesktopDevice: envOrDefault("COLDMIC_MEETING_DESKTOP_DEVICE", "@DEFAULT_MONITOR@"),
+		},
+		Timestamps: TimestampConfig{
+			Mode:     strings.ToLower(envOrDefault("COLDMIC_TIMESTAMPS", "off")),
+			Template: envOrDefaultUntrimmed("COLDMIC_TIMESTAMP_TEMPLATE", "[{ts}] "),
+			Layout:   envOrDefault("COLDMIC_TIMESTAMP_LAYOUT", "15:04:05"),
+		},
+		Backup: BackupConfig{
+			Target:     strings.ToLower(strings.TrimSpace(os.Getenv("COLDMIC_BACKUP_TARGET"))),
+			URL:        strings.TrimSpace(os.Getenv("COLDMIC_BACKUP_URL")),
+			Region:     envOrDefault("COLDMIC_BACKUP_REGION", "us-east-1"),
+			Username:   strings.TrimSpace(os.Getenv("COLDMIC_BACKUP_USERNAME")),
+			Password:   os.Getenv("COLDMIC_BACKUP_PASSWORD"),
+			Passphrase: os.Getenv("COLDMIC_BACKUP_PASSPHRASE"),
+			Interval:   time.Duration(envOrDefaultInt("COLDMIC_BACKUP_INTERVAL_MIN", 60)) * time.Minute,
+			ObjectName: backupObject,
+		},
+		Trim: TrimConfig{
+			Enabled:   envOrDefaultBool("COLDMIC_TRIM_SILENCE", false),
+			Threshold: envOrDefaultInt("COLDMIC_TRIM_THRESHOLD", 500),
+			Padding:   time.Duration(envOrDefaultInt("COLDMIC_TRIM_PADDING_MS", 300)) * time.Millisecond,
+		},
+		Network: NetworkConfig{
+			CheckInterval:     time.Duration(envOrDefaultInt("COLDMIC_NETWORK_CHECK_MS", networkCheckMS)) * time.Millisecond,
+			FallbackProvider:  fallbackProvider,
+			FallbackURL:       fallbackURL,
+			FallbackModel:     strings.TrimSpace(os.Getenv("COLDMIC_FALLBACK_DEEPGRAM_MODEL")),
+			FallbackOnMetered: envOrDefaultBool("COLDMIC_FALLBACK_ON_METERED", true),
+			Proxy:             strings.TrimSpace(os.Getenv("COLDMIC_PROXY")),
+		},
+		Trigger: TriggerConfig{
+			Device:        strings.TrimSpace(os.Getenv("COLDMIC_TRIGGER_DEVICE")),
+			KeyCode:       max(envOrDefaultInt("COLDMIC_TRIGGER_KEYCODE", 0), 0),
+			MIDIDevice:    strings.TrimSpace(os.Getenv("COLDMIC_MIDI_DEVICE")),
+			GamepadDevice: strings.TrimSpace(os.Getenv("COLDMIC_GAMEPAD_DEVICE")),
+			GamepadButton: envOrDefault("COLDMIC_GAMEPAD_BUTTON", "start"),
+		},
+		Usage: UsageConfig{
+			Path: filepath.Join(dataDir, "usage.json"),
+		},
+		Retry: RetryConfig{
+			MinConfidence: envOrDefaultFloat("COLDMIC_MIN_CONFIDENCE", 0),
+			Model:         envOrDefault("COLDMIC_RETRY_MODEL", "nova-3"),
+			APIBaseURL:    strings.TrimSpace(os.Getenv("COLDMIC_RETRY_DEEPGRAM_URL")),
+		},
+		Casing: CasingConfig{
+			Policy:  strings.ToLower(envOrDefault("COLDMIC_CASING", "preserve")),
+			Targets: targetOverrides("COLDMIC_CASING_", "COLDMIC_CASING_WORDS"),
+			Words:   strings.FieldsFunc(os.Getenv("COLDMIC_CASING_WORDS"), isListSeparator),
+		},
+		Normalize: NormalizeConfig{
+			Options: normalizeOptions(os.Getenv("COLDMIC_NORMALIZE")),
+			Targets: map[string][]string{},
+		},
+		Redaction: RedactionConfig{
+			Mode:      strings.ToLower(envOrDefault("COLDMIC_REDACT_LOCAL", "off")),
+			Segment:   time.Duration(envOrDefaultInt("COLDMIC_REDACT_SEGMENT_MS", 3000)) * time.Millisecond,
+			MinDigits: envOrDefaultInt("COLDMIC_REDACT_MIN_DIGITS", 12),
+		},
+		Race: RaceConfig{
+			Provider: strings.ToLower(strings.TrimSpace(os.Getenv("COLDMIC_RACE_PROVIDER"))),
+			Pick:     strings.ToLower(envOrDefault("COLDMIC_RACE_PICK", "confidence")),
+		},
+		Accurate: AccurateConfig{
+			Model:      strings.TrimSpace(os.Getenv("COLDMIC_ACCURATE_MODEL")),
+			APIBaseURL: strings.TrimSpace(os.Getenv("COLDMIC_ACCURATE_DEEPGRAM_URL")),
+		},
+		Clipboard: ClipboardConfig{
+			Split:           strings.ToLower(envOrDefault("COLDMIC_CLIPBOARD_SPLIT", "off")),
+			SplitDelay:      time.Duration(envOrDefaultInt("COLDMIC_CLIPBOARD_SPLIT_DELAY_MS", 150)) * time.Millisecond,
+			SplitCommand:    strings.Fields(os.Getenv("COLDMIC_CLIPBOARD_SPLIT_COMMAND")),
+			CopyHooks:       commandList(os.Getenv("COLDMIC_COPY_HOOK")),
+			CopyHookTimeout: time.Duration(envOrDefaultInt("COLDMIC_COPY_HOOK_TIMEOUT_MS", 30000)) * time.Millisecond,
+			MaxChars:        envOrDefaultInt("COLDMIC_CLIPBOARD_MAX_CHARS", 0),
+			Overflow:        strings.ToLower(envOrDefault("COLDMIC_CLIPBOARD_OVERFLOW", "preview")),
+			OverflowDir:     strings.TrimSpace(os.Getenv("COLDMIC_CLIPBOARD_OVERFLOW_DIR")),
+		},
+		Forms: FormsConfig{
+			Dir:    formsDir,
+			Active: strings.ToLower(strings.TrimSpace(os.Getenv("COLDMIC_FORM"))),
+		},
+		Accessibility: AccessibilityConfig{
+			Verbosity: strings.ToLower(envOrDefault("COLDMIC_ANNOUNCE", "off")),
+			Backend:   strings.ToLower(envOrDefault("COLDMIC_ANNOUNCE_BACKEND", "speech-dispatcher")),
+			Command:   strings.TrimSpace(os.Getenv("COLDMIC_ANNOUNCE_COMMAND")),
+		},
+		Target: TargetConfig{
+			Name:            strings.ToLower(envOrDefault("COLDMIC_TARGET", "clipboard")),
+			GitCommitRepo:   strings.TrimSpace(os.Getenv("COLDMIC_GIT_COMMIT_REPO")),
+			GitCommitRules:  strings.TrimSpace(os.Getenv("COLDMIC_GIT_COMMIT_RULES")),
+			GitHubAPIURL:    envOrDefault("COLDMIC_GITHUB_API_URL", "https://api.github.com"),
+			GitHubToken:     firstNonEmpty(os.Getenv("COLDMIC_GITHUB_TOKEN"), os.Getenv("GITHUB_TOKEN")),
+			GitHubRepo:      strings.TrimSpace(os.Getenv("COLDMIC_GITHUB_REPO")),
+			JiraURL:         strings.TrimSpace(os.Getenv("COLDMIC_JIRA_URL")),
+			JiraEmail:       strings.TrimSpace(os.Getenv("COLDMIC_JIRA_EMAIL")),
+			JiraToken:       strings.TrimSpace(os.Getenv("COLDMIC_JIRA_TOKEN")),
+			JiraProject:     strings.TrimSpace(os.Getenv("COLDMIC_JIRA_PROJECT")),
+			JiraIssueType:   envOrDefault("COLDMIC_JIRA_ISSUE_TYPE", "Task"),
+			ReminderDir:     envOrDefault("COLDMIC_REMINDER_DIR", filepath.Join(dataDir, "reminders")),
+			ReminderCommand: strings.Fields(os.Getenv("COLDMIC_REMINDER_COMMAND")),
+			TodoFile:        firstNonEmpty(os.Getenv("COLDMIC_TODO_FILE"), os.Getenv("TODO_FILE"), filepath.Join(home, "todo.txt")),
+			TaskCommand:     envOrDefault("COLDMIC_TASK_COMMAND", "task"),
+			TaskProject:     strings.TrimSpace(os.Getenv("COLDMIC_TASK_PROJECT")),
+			TaskContext:     strings.TrimSpace(os.Getenv("COLDMIC_TASK_CONTEXT")),
 		},
 	}
 
@@ -93,16 +810,289 @@ func Load() (Config, error) {
 	if cfg.Audio.Channels <= 0 {
 		cfg.Audio.Channels = 1
 	}
+	cfg.Audio.DeviceSampleRate = max(cfg.Audio.DeviceSampleRate, 0)
+	if cfg.Provider == "replay" && !cfg.DryRun && strings.TrimSpace(os.Getenv("COLDMIC_AUDIO_INPUT_FORMAT")) == "" {
+		// Replays need no microphone; dry runs keep the real capture.
+		cfg.Audio.InputFormat = "silence"
+	}
+	cfg.Audio.DeviceChannels = max(cfg.Audio.DeviceChannels, 0)
+	if cfg.Target.TaskProject == "" && workspace != DefaultWorkspace {
+		cfg.Target.TaskProject = workspace
+	}
 	if cfg.Rules.IterationLimit <= 0 {
 		cfg.Rules.IterationLimit = 30
 	}
 	if cfg.Session.ChunkSize < 256 {
 		cfg.Session.ChunkSize = 4096
 	}
+	switch cfg.Audio.Multichannel {
+	case "off", "merge", "label":
+	default:
+		cfg.Audio.Multichannel = "off"
+	}
+	switch cfg.Timestamps.Mode {
+	case "off", "offset", "wallclock":
+	default:
+		cfg.Timestamps.Mode = "off"
+	}
+	switch cfg.Accessibility.Verbosity {
+	case "off", "minimal", "full":
+	default:
+		cfg.Accessibility.Verbosity = "off"
+	}
+	switch cfg.Accessibility.Backend {
+	case "speech-dispatcher", "notify":
+	default:
+		cfg.Accessibility.Backend = "speech-dispatcher"
+	}
+	switch cfg.Clipboard.Split {
+	case "off", "sentences", "paragraphs":
+	default:
+		cfg.Clipboard.Split = "off"
+	}
+	if cfg.Clipboard.MaxChars < 0 {
+		cfg.Clipboard.MaxChars = 0
+	}
+	if cfg.Clipboard.Overflow != "path" {
+		cfg.Clipboard.Overflow = "preview"
+	}
+	if cfg.Clipboard.SplitDelay < 0 {
+		cfg.Clipboard.SplitDelay = 0
+	}
+	if !strings.Contains(cfg.Timestamps.Template, "{ts}") {
+		cfg.Timestamps.Template = "[{ts}] "
+	}
+	if cfg.Storage.MinFreeMB < 0 {
+		cfg.Storage.MinFreeMB = 0
+	}
+	if cfg.Network.CheckInterval < 0 {
+		cfg.Network.CheckInterval = 0
+	}
+	if endpointing := cfg.Deepgram.Endpointing; endpointing != "" && endpointing != "false" {
+		if ms, err := strconv.Atoi(endpointing); err != nil || ms < 0 {
+			cfg.Deepgram.Endpointing = ""
+		}
+	}
+	cfg.Deepgram.UtteranceEndMS = max(cfg.Deepgram.UtteranceEndMS, 0)
+	cfg.Deepgram.DialRetries = max(cfg.Deepgram.DialRetries, 0)
+	// An ignored redaction would leak what it should hide, so unknown
+	// entities are an error rather than dropped.
+	for _, entity := range cfg.Deepgram.Redact {
+		if !slices.Contains(DeepgramRedactions, entity) {
+			return Config{}, fmt.Errorf("COLDMIC_DEEPGRAM_REDACT: unknown entity %q (expected %s)", entity, strings.Join(DeepgramRedactions, ", "))
+		}
+	}
+	if cfg.Usage.Rates, err = usageRates(os.Getenv("COLDMIC_USAGE_RATES")); err != nil {
+		return Config{}, fmt.Errorf("COLDMIC_USAGE_RATES: %w", err)
+	}
+	if color := cfg.Session.RecordingLEDColor; color != "" {
+		if _, err := strconv.ParseUint(color, 16, 32); err != nil || len(color) != 6 {
+			return Config{}, fmt.Errorf("COLDMIC_RECORDING_LED_COLOR: invalid color %q (expected RRGGBB)", color)
+		}
+	}
+	cfg.Deepgram.Proxy = cfg.Network.Proxy
+	if (cfg.Deepgram.TLS.ClientCert == "") != (cfg.Deepgram.TLS.ClientKey == "") {
+		return Config{}, errors.New("COLDMIC_DEEPGRAM_CLIENT_CERT and COLDMIC_DEEPGRAM_CLIENT_KEY must be set together")
+	}
+	cfg.DeepgramProfilesPath = envOrDefault("COLDMIC_DEEPGRAM_PROFILES", filepath.Join(home, ".config", "coldmic", "deepgram-profiles.json"))
+	if cfg.DeepgramProfiles, err = loadDeepgramProfiles(cfg.DeepgramProfilesPath); err != nil {
+		return Config{}, err
+	}
+	if project := cfg.Deepgram.Project; project != "" {
+		// Deepgram bills the project that owns the key, so the project
+		// decides the key.
+		key := "DEEPGRAM_PROJECT_KEY_" + workspaceEnvSuffix(project)
+		if cfg.Deepgram.APIKey = strings.TrimSpace(os.Getenv(key)); cfg.Deepgram.APIKey == "" {
+			return Config{}, fmt.Errorf("%s is required for Deepgram project %q", key, project)
+		}
+	}
+	cfg.deepgramDefault = cfg.Deepgram
+	profile, err := selectDeepgramProfile(cfg.DeepgramProfiles, os.Getenv("COLDMIC_DEEPGRAM_PROFILE"), workspace)
+	if err != nil {
+		return Config{}, err
+	}
+	if profile != "" {
+		cfg.Deepgram = cfg.DeepgramProfiles[profile].apply(profile, cfg.Deepgram)
+	}
+
+	cfg.TargetsPath = envOrDefault("COLDMIC_TARGETS_FILE", filepath.Join(home, ".config", "coldmic", "targets.json"))
+	if cfg.Targets, err = loadTargets(cfg.TargetsPath); err != nil {
+		return Config{}, err
+	}
+	if _, _, err := cfg.ResolveTarget(cfg.Target.Name); err != nil {
+		return Config{}, fmt.Errorf("COLDMIC_TARGET: %w", err)
+	}
+	cfg.HotkeysPath = envOrDefault("COLDMIC_HOTKEYS_FILE", filepath.Join(home, ".config", "coldmic", "hotkeys.json"))
+	if cfg.Hotkeys, err = loadHotkeys(cfg, cfg.HotkeysPath); err != nil {
+		return Config{}, err
+	}
+	cfg.MIDIPath = envOrDefault("COLDMIC_MIDI_FILE", filepath.Join(home, ".config", "coldmic", "midi.json"))
+	if cfg.MIDI, err = loadMIDIBindings(cfg, cfg.MIDIPath); err != nil {
+		return Config{}, err
+	}
+
+	if cfg.Network.FallbackModel == "" {
+		cfg.Network.FallbackModel = cfg.Deepgram.Model
+	}
+	if cfg.Retry.MinConfidence < 0 || cfg.Retry.MinConfidence > 1 {
+		cfg.Retry.MinConfidence = 0
+	}
+	if !validCasing(cfg.Casing.Policy) {
+		cfg.Casing.Policy = "preserve"
+	}
+	for target, policy := range cfg.Casing.Targets {
+		if !validCasing(policy) {
+			delete(cfg.Casing.Targets, target)
+		}
+	}
+	for target, value := range targetOverrides("COLDMIC_NORMALIZE_") {
+		cfg.Normalize.Targets[target] = normalizeOptions(value)
+	}
+	if cfg.Accurate.Model == "" && cfg.Accurate.APIBaseURL != "" {
+		cfg.Accurate.Model = cfg.Deepgram.Model
+	}
+	if cfg.Storage.CacheMaxMB < 0 {
+		cfg.Storage.CacheMaxMB = 0
+	}
+	if cfg.Backup.Interval <= 0 {
+		cfg.Backup.Interval = time.Hour
+	}
+	if cfg.Trim.Threshold <= 0 {
+		cfg.Trim.Threshold = 500
+	}
+	if cfg.Trim.Padding < 0 {
+		cfg.Trim.Padding = 0
+	}
+	if cfg.Watch.OutputDir == "" {
+		cfg.Watch.OutputDir = cfg.Watch.Dir
+	}
+	if cfg.Watch.Interval <= 0 {
+		cfg.Watch.Interval = 2 * time.Second
+	}
+	if cfg.Batch.Concurrency <= 0 {
+		cfg.Batch.Concurrency = 2
+	}
+	if cfg.DryRun {
+		// Every path to a paid provider is closed, not only the primary.
+		cfg.Provider = "replay"
+		cfg.Race.Provider = ""
+		cfg.Accurate.Model = ""
+		cfg.Retry.MinConfidence = 0
+		cfg.Network.FallbackProvider = ""
+		cfg.Network.FallbackURL = ""
+		cfg.Session.Preflight = false
+		// Replayed transcripts must not be cached as real ones.
+		cfg.Storage.CacheMaxMB = 0
+	}
 
 	return cfg, nil
 }
 
+// NormalizeWorkspace lowercases a workspace name and validates it as a
+// directory-safe identifier. An empty name selects DefaultWorkspace.
+func NormalizeWorkspace(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return DefaultWorkspace, nil
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return "", fmt.Errorf("invalid workspace name %q: use letters, digits, '-' or '_'", name)
+		}
+	}
+	return name, nil
+}
+
+// targetOverrides reads per-target settings such as COLDMIC_CASING_GIT_COMMIT
+// into a map keyed by target name ("git-commit"), skipping the excluded keys.
+func targetOverrides(prefix string, exclude ...string) map[string]string {
+	overrides := map[string]string{}
+	for _, entry := range os.Environ() {
+		key, value, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(key, prefix) || slices.Contains(exclude, key) {
+			continue
+		}
+		name := strings.ToLower(strings.ReplaceAll(strings.TrimPrefix(key, prefix), "_", "-"))
+		if value = strings.ToLower(strings.TrimSpace(value)); name != "" && value != "" {
+			overrides[name] = value
+		}
+	}
+	return overrides
+}
+
+func validCasing(policy string) bool {
+	switch policy {
+	case "preserve", "sentence", "lower":
+		return true
+	}
+	return false
+}
+
+// normalizeOptions parses a list such as "trim, newline", dropping unknown
+// names; "none" yields an empty list.
+func normalizeOptions(value string) []string {
+	options := []string{}
+	for _, option := range strings.FieldsFunc(strings.ToLower(value), isListSeparator) {
+		switch option {
+		case "trim", "collapse-spaces", "punctuation", "newline":
+			if !slices.Contains(options, option) {
+				options = append(options, option)
+			}
+		}
+	}
+	return options
+}
+
+// usageRates parses a list such as "deepgram=0.0043, openai=0.006" of
+// provider costs per audio minute.
+func usageRates(value string) (map[string]float64, error) {
+	rates := map[string]float64{}
+	for _, entry := range strings.FieldsFunc(strings.ToLower(value), isListSeparator) {
+		provider, rate, ok := strings.Cut(entry, "=")
+		cost, err := strconv.ParseFloat(rate, 64)
+		if !ok || provider == "" || err != nil || cost < 0 {
+			return nil, fmt.Errorf("invalid rate %q (expected provider=cost per minute)", entry)
+		}
+		rates[provider] = cost
+	}
+	return rates, nil
+}
+
+func isListSeparator(r rune) bool {
+	return r == ',' || unicode.IsSpace(r)
+}
+
+// phraseList splits a comma-separated list whose entries may contain spaces.
+func phraseList(value string) []string {
+	var phrases []string
+	for _, phrase := range strings.Split(value, ",") {
+		if phrase = strings.TrimSpace(phrase); phrase != "" {
+			phrases = append(phrases, phrase)
+		}
+	}
+	return phrases
+}
+
+// expandTags fills in {user}, {host} and {workspace} so one shared config
+// attributes usage per person and machine.
+func expandTags(tags []string, workspace string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	user := firstNonEmpty(os.Getenv("USER"), os.Getenv("USERNAME"))
+	host, _ := os.Hostname()
+	replacer := strings.NewReplacer("{user}", user, "{host}", host, "{workspace}", workspace)
+	expanded := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		expanded = append(expanded, replacer.Replace(tag))
+	}
+	return expanded
+}
+
+func workspaceEnvSuffix(workspace string) string {
+	return strings.ToUpper(strings.ReplaceAll(workspace, "-", "_"))
+}
+
 func firstExisting(paths ...string) string {
 	for _, p := range paths {
 		if p == "" {
@@ -128,6 +1118,20 @@ func firstNonEmpty(values ...string) string {
 	return ""
 }
 
+// defaultInputFormat is the ffmpeg capture input for goos: DirectShow on
+// Windows, AVFoundation on macOS and PulseAudio, which PipeWire also serves,
+// elsewhere.
+func defaultInputFormat(goos string) string {
+	switch goos {
+	case "windows":
+		return "dshow"
+	case "darwin":
+		return "avfoundation"
+	default:
+		return "pulse"
+	}
+}
+
 func envOrDefault(key string, fallback string) string {
 	value := strings.TrimSpace(os.Getenv(key))
 	if value == "" {
@@ -136,6 +1140,15 @@ func envOrDefault(key string, fallback string) string {
 	return value
 }
 
+// envOrDefaultUntrimmed keeps surrounding whitespace, which is significant in templates.
+func envOrDefaultUntrimmed(key string, fallback string) string {
+	value := os.Getenv(key)
+	if strings.TrimSpace(value) == "" {
+		return fallback
+	}
+	return value
+}
+
 func envOrDefaultInt(key string, fallback int) int {
 	value := strings.TrimSpace(os.Getenv(key))
 	if value == "" {
@@ -148,6 +1161,18 @@ func envOrDefaultInt(key string, fallback int) int {
 	return parsed
 }
 
+func envOrDefaultFloat(key string, fallback float64) float64 {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
 func envOrDefaultBool(key string, fallback bool) bool {
 	value := strings.TrimSpace(strings.ToLower(os.Getenv(key)))
 	switch value {
@@ -173,3 +1198,14 @@ func firstNonNegativeInt(primary string, secondary string, fallback int) int {
 	}
 	return fallback
 }
+
+// commandList splits ";"-separated commands into their arguments.
+func commandList(value string) [][]string {
+	var commands [][]string
+	for _, command := range strings.Split(value, ";") {
+		if args := strings.Fields(command); len(args) > 0 {
+			commands = append(commands, args)
+		}
+	}
+	return commands
+}
@@ -1,10 +1,16 @@
 package config
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
+
+	"coldmic/internal/auth"
+	"coldmic/internal/domain"
 )
 
 func TestLoadUsesRulesFallbackOrder(t *testing.T) {
@@ -127,3 +133,1033 @@ func TestLoadInvalidNumericValuesFallback(t *testing.T) {
 		t.Fatalf("expected default smart format true")
 	}
 }
+
+func TestLoadWatchAndStorageConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("COLDMIC_DATA_DIR", "")
+	t.Setenv("COLDMIC_HISTORY_FILE", "")
+	t.Setenv("COLDMIC_WATCH_DIR", "/srv/inbox")
+	t.Setenv("COLDMIC_WATCH_OUTPUT_DIR", "")
+	t.Setenv("COLDMIC_WATCH_INTERVAL_MS", "-5")
+	t.Setenv("COLDMIC_WATCH_CONCURRENCY", "4")
+	t.Setenv("COLDMIC_PANIC_PURGE", "true")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+
+	dataDir := filepath.Join(home, ".local", "share", "coldmic")
+	if cfg.Storage.DataDir != dataDir || cfg.Storage.HistoryPath != filepath.Join(dataDir, "history.jsonl") {
+		t.Fatalf("unexpected storage config: %+v", cfg.Storage)
+	}
+	if cfg.Watch.Dir != "/srv/inbox" || cfg.Watch.OutputDir != "/srv/inbox" {
+		t.Fatalf("expected output dir to default to watch dir: %+v", cfg.Watch)
+	}
+	if cfg.Watch.Interval != 2*time.Second {
+		t.Fatalf("unexpected watch interval: %+v", cfg.Watch)
+	}
+	if cfg.Batch.Concurrency != 4 {
+		t.Fatalf("expected watch concurrency to seed batch concurrency: %+v", cfg.Batch)
+	}
+	if cfg.Storage.JobsPath != filepath.Join(dataDir, "jobs.json") {
+		t.Fatalf("unexpected jobs path: %q", cfg.Storage.JobsPath)
+	}
+	if !cfg.Storage.PanicPurge {
+		t.Fatalf("expected panic purge enabled")
+	}
+	if cfg.Meeting.MicLabel != "Me" || cfg.Meeting.DesktopLabel != "Them" || cfg.Meeting.DesktopDevice != "@DEFAULT_MONITOR@" {
+		t.Fatalf("unexpected meeting defaults: %+v", cfg.Meeting)
+	}
+	if cfg.Ingest.DownloaderCommand != "yt-dlp" {
+		t.Fatalf("unexpected downloader command: %q", cfg.Ingest.DownloaderCommand)
+	}
+}
+
+func TestLoadTimestampConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_TIMESTAMPS", "WallClock")
+	t.Setenv("COLDMIC_TIMESTAMP_TEMPLATE", "<{ts}> ")
+	t.Setenv("COLDMIC_TIMESTAMP_LAYOUT", "15:04")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Timestamps.Mode != "wallclock" || cfg.Timestamps.Template != "<{ts}> " || cfg.Timestamps.Layout != "15:04" {
+		t.Fatalf("unexpected timestamp config: %+v", cfg.Timestamps)
+	}
+
+	t.Setenv("COLDMIC_TIMESTAMPS", "sometimes")
+	t.Setenv("COLDMIC_TIMESTAMP_TEMPLATE", "no placeholder")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Timestamps.Mode != "off" || cfg.Timestamps.Template != "[{ts}] " {
+		t.Fatalf("expected timestamp fallbacks, got %+v", cfg.Timestamps)
+	}
+}
+
+func TestLoadTrimConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_TRIM_SILENCE", "true")
+	t.Setenv("COLDMIC_TRIM_THRESHOLD", "0")
+	t.Setenv("COLDMIC_TRIM_PADDING_MS", "150")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if !cfg.Trim.Enabled || cfg.Trim.Threshold != 500 || cfg.Trim.Padding != 150*time.Millisecond {
+		t.Fatalf("unexpected trim config: %+v", cfg.Trim)
+	}
+}
+
+func TestLoadIncrementalCopyConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_INCREMENTAL_COPY", "true")
+	t.Setenv("COLDMIC_INCREMENTAL_COPY_INTERVAL_MS", "-5")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if !cfg.Session.IncrementalCopy || cfg.Session.CopyInterval != 0 {
+		t.Fatalf("unexpected incremental copy config: %+v", cfg.Session)
+	}
+
+	t.Setenv("COLDMIC_INCREMENTAL_COPY_INTERVAL_MS", "")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Session.CopyInterval != time.Second {
+		t.Fatalf("expected a one second default interval, got %s", cfg.Session.CopyInterval)
+	}
+}
+
+func TestLoadNetworkConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DEEPGRAM_MODEL", "nova-3")
+	t.Setenv("COLDMIC_NETWORK_CHECK_MS", "-1")
+	t.Setenv("COLDMIC_FALLBACK_DEEPGRAM_URL", " http://127.0.0.1:8080/v1 ")
+	t.Setenv("COLDMIC_FALLBACK_DEEPGRAM_MODEL", "")
+	t.Setenv("COLDMIC_FALLBACK_ON_METERED", "false")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Network.CheckInterval != 0 || cfg.Network.FallbackURL != "http://127.0.0.1:8080/v1" {
+		t.Fatalf("unexpected network config: %+v", cfg.Network)
+	}
+	if cfg.Network.FallbackModel != "nova-3" || cfg.Network.FallbackOnMetered {
+		t.Fatalf("expected fallback model to default to the primary model: %+v", cfg.Network)
+	}
+	if cfg.Network.FallbackProvider != "deepgram" {
+		t.Fatalf("expected a fallback URL to select the Deepgram fallback, got %q", cfg.Network.FallbackProvider)
+	}
+}
+
+func TestLoadNetworkCheckDefaultsToFallback(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_NETWORK_CHECK_MS", "")
+	t.Setenv("COLDMIC_FALLBACK_DEEPGRAM_URL", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Network.CheckInterval != 0 || cfg.Network.FallbackProvider != "" {
+		t.Fatalf("expected no network checks without a fallback, got %+v", cfg.Network)
+	}
+
+	t.Setenv("COLDMIC_FALLBACK_PROVIDER", "WhisperCpp")
+	if cfg, err = Load(); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Network.CheckInterval != 10*time.Second || cfg.Network.FallbackProvider != "whispercpp" {
+		t.Fatalf("expected checks for the local fallback, got %+v", cfg.Network)
+	}
+}
+
+func TestLoadRetryAndAccurateConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_MIN_CONFIDENCE", "0.75")
+	t.Setenv("COLDMIC_RETRY_MODEL", "")
+	t.Setenv("COLDMIC_RETRY_DEEPGRAM_URL", " http://127.0.0.1:8080/v1 ")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Retry.MinConfidence != 0.75 || cfg.Retry.Model != "nova-3" || cfg.Retry.APIBaseURL != "http://127.0.0.1:8080/v1" {
+		t.Fatalf("unexpected retry config: %+v", cfg.Retry)
+	}
+
+	if cfg.Accurate.Model != "" {
+		t.Fatalf("expected accurate pass to be disabled by default: %+v", cfg.Accurate)
+	}
+
+	t.Setenv("COLDMIC_ACCURATE_DEEPGRAM_URL", "http://127.0.0.1:9000/v1")
+	t.Setenv("COLDMIC_MIN_CONFIDENCE", "75")
+	if cfg, err = Load(); err != nil || cfg.Retry.MinConfidence != 0 {
+		t.Fatalf("expected out-of-range threshold to disable retries: %+v %v", cfg.Retry, err)
+	}
+	if cfg.Accurate.Model != "nova-2" || cfg.Accurate.APIBaseURL != "http://127.0.0.1:9000/v1" {
+		t.Fatalf("expected accurate model to default to the primary model: %+v", cfg.Accurate)
+	}
+}
+
+func TestLoadCasingConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_CASING", "Sentence")
+	t.Setenv("COLDMIC_CASING_GIT_COMMIT", "lower")
+	t.Setenv("COLDMIC_CASING_TODO", "title")
+	t.Setenv("COLDMIC_CASING_WORDS", "API, GitHub  iOS")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Casing.Policy != "sentence" || len(cfg.Casing.Targets) != 1 || cfg.Casing.Targets["git-commit"] != "lower" {
+		t.Fatalf("unexpected casing config: %+v", cfg.Casing)
+	}
+	if strings.Join(cfg.Casing.Words, "|") != "API|GitHub|iOS" {
+		t.Fatalf("unexpected casing words: %q", cfg.Casing.Words)
+	}
+
+	t.Setenv("COLDMIC_CASING", "shouty")
+	if cfg, _ = Load(); cfg.Casing.Policy != "preserve" {
+		t.Fatalf("expected invalid policy to fall back to preserve, got %q", cfg.Casing.Policy)
+	}
+}
+
+func TestLoadNormalizeConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_NORMALIZE", "Trim, collapse-spaces trim bogus")
+	t.Setenv("COLDMIC_NORMALIZE_GIT_COMMIT", "newline")
+	t.Setenv("COLDMIC_NORMALIZE_CLIPBOARD", "none")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if strings.Join(cfg.Normalize.Options, ",") != "trim,collapse-spaces" {
+		t.Fatalf("unexpected default options: %q", cfg.Normalize.Options)
+	}
+	if strings.Join(cfg.Normalize.Targets["git-commit"], ",") != "newline" {
+		t.Fatalf("unexpected git-commit options: %+v", cfg.Normalize.Targets)
+	}
+	if options, ok := cfg.Normalize.Targets["clipboard"]; !ok || len(options) != 0 {
+		t.Fatalf("expected clipboard override without options: %+v", cfg.Normalize.Targets)
+	}
+}
+
+func TestLoadOpenAIProviderConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_PROVIDER", "OpenAI")
+	t.Setenv("OPENAI_API_KEY", " sk-test ")
+	t.Setenv("COLDMIC_OPENAI_MODEL", "")
+	t.Setenv("COLDMIC_OPENAI_LANGUAGE", "")
+	t.Setenv("DEEPGRAM_LANGUAGE", "de")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Provider != "openai" || cfg.OpenAI.APIKey != "sk-test" || cfg.OpenAI.Model != "whisper-1" || cfg.OpenAI.Language != "de" {
+		t.Fatalf("unexpected openai config: %q %+v", cfg.Provider, cfg.OpenAI)
+	}
+}
+
+func TestLoadAssemblyAIProviderConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_PROVIDER", "AssemblyAI")
+	t.Setenv("ASSEMBLYAI_API_KEY", " aai-key ")
+	t.Setenv("COLDMIC_ASSEMBLYAI_MODEL", "universal-streaming-multilingual")
+	t.Setenv("COLDMIC_ASSEMBLYAI_FORMAT_TURNS", "false")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	want := AssemblyAIConfig{APIKey: "aai-key", APIBaseURL: "wss://streaming.assemblyai.com/v3", Model: "universal-streaming-multilingual"}
+	if cfg.Provider != "assemblyai" || cfg.AssemblyAI != want {
+		t.Fatalf("unexpected assemblyai config: %q %+v", cfg.Provider, cfg.AssemblyAI)
+	}
+}
+
+func TestLoadDeepgramProfiles(t *testing.T) {
+	home := t.TempDir()
+	profiles := filepath.Join(home, "profiles.json")
+	if err := os.WriteFile(profiles, []byte(`{
+		"OnPrem": {"url": "https://stt.corp.example/v1", "auth": "header:X-Api-Key", "key_env": "CORP_STT_KEY", "ca_file": "/etc/corp-ca.pem", "workspaces": ["work"]},
+		"review": {"url": "https://review.example/v1", "auth": "bearer", "model": "nova-3", "targets": ["git-commit"]}
+	}`), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	t.Setenv("HOME", home)
+	t.Setenv("COLDMIC_DEEPGRAM_PROFILES", profiles)
+	t.Setenv("COLDMIC_DEEPGRAM_PROFILE", "")
+	t.Setenv("DEEPGRAM_API_KEY", "cloud-key")
+	t.Setenv("CORP_STT_KEY", "corp-key")
+
+	cfg, err := LoadWorkspace("work")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	want := DeepgramTLSConfig{CAFile: "/etc/corp-ca.pem"}
+	if cfg.Deepgram.Profile != "onprem" || cfg.Deepgram.APIBaseURL != "https://stt.corp.example/v1" || cfg.Deepgram.Auth != "header:X-Api-Key" || cfg.Deepgram.APIKey != "corp-key" || cfg.Deepgram.TLS != want {
+		t.Fatalf("expected workspace profile, got %+v", cfg.Deepgram)
+	}
+
+	review, ok := cfg.DeepgramForTarget("git-commit")
+	if !ok || review.Profile != "review" || review.Auth != "bearer" || review.Model != "nova-3" || review.APIKey != "cloud-key" || review.TLS != (DeepgramTLSConfig{}) {
+		t.Fatalf("expected target profile over the defaults, got %+v", review)
+	}
+	if _, ok := cfg.DeepgramForTarget("clipboard"); ok {
+		t.Fatalf("expected no profile for clipboard")
+	}
+
+	t.Setenv("COLDMIC_DEEPGRAM_PROFILE", "review")
+	if cfg, err := Load(); err != nil || cfg.Deepgram.Profile != "review" {
+		t.Fatalf("expected explicit profile, got %+v: %v", cfg.Deepgram, err)
+	}
+	t.Setenv("COLDMIC_DEEPGRAM_PROFILE", "missing")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected unknown profile to fail")
+	}
+}
+
+func TestLoadDeepgramProjectAndTags(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USER", "ada")
+	t.Setenv("DEEPGRAM_API_KEY", "default-key")
+	t.Setenv("COLDMIC_DEEPGRAM_PROJECT", "Support-Team")
+	t.Setenv("DEEPGRAM_PROJECT_KEY_SUPPORT_TEAM", " support-key ")
+	t.Setenv("COLDMIC_DEEPGRAM_TAGS", "user:{user}, workspace:{workspace},,coldmic")
+	t.Setenv("COLDMIC_DEEPGRAM_CALLBACK", " https://hooks.example/deepgram ")
+
+	cfg, err := LoadWorkspace("work")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Deepgram.Project != "support-team" || cfg.Deepgram.APIKey != "support-key" {
+		t.Fatalf("expected project key, got %+v", cfg.Deepgram)
+	}
+	if strings.Join(cfg.Deepgram.Tags, "|") != "user:ada|workspace:work|coldmic" || cfg.Deepgram.Callback != "https://hooks.example/deepgram" {
+		t.Fatalf("unexpected tags or callback: %+v", cfg.Deepgram)
+	}
+
+	t.Setenv("DEEPGRAM_PROJECT_KEY_SUPPORT_TEAM", "")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "DEEPGRAM_PROJECT_KEY_SUPPORT_TEAM") {
+		t.Fatalf("expected missing project key error, got %v", err)
+	}
+}
+
+func TestLoadDeepgramUtteranceTuning(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DEEPGRAM_API_KEY", "key")
+	t.Setenv("COLDMIC_DEEPGRAM_ENDPOINTING", " 800 ")
+	t.Setenv("COLDMIC_DEEPGRAM_UTTERANCE_END_MS", "1500")
+	t.Setenv("COLDMIC_DEEPGRAM_VAD_EVENTS", "true")
+	t.Setenv("COLDMIC_DEEPGRAM_PUNCTUATE", "true")
+	t.Setenv("COLDMIC_DEEPGRAM_NUMERALS", "true")
+	t.Setenv("COLDMIC_DEEPGRAM_FILLER_WORDS", "true")
+	t.Setenv("COLDMIC_DEEPGRAM_DIAL_RETRIES", "-1")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if !cfg.Deepgram.Punctuate || !cfg.Deepgram.Numerals || !cfg.Deepgram.FillerWords {
+		t.Fatalf("unexpected formatting toggles: %+v", cfg.Deepgram)
+	}
+	if cfg.Deepgram.Endpointing != "800" || cfg.Deepgram.UtteranceEndMS != 1500 || !cfg.Deepgram.VADEvents {
+		t.Fatalf("unexpected utterance tuning: %+v", cfg.Deepgram)
+	}
+	if cfg.Deepgram.DialRetries != 0 {
+		t.Fatalf("expected negative dial retries to disable retrying, got %d", cfg.Deepgram.DialRetries)
+	}
+
+	for value, want := range map[string]string{"FALSE": "false", "soon": "", "-5": ""} {
+		t.Setenv("COLDMIC_DEEPGRAM_ENDPOINTING", value)
+		if cfg, err := Load(); err != nil || cfg.Deepgram.Endpointing != want {
+			t.Fatalf("endpointing %q: expected %q, got %q %v", value, want, cfg.Deepgram.Endpointing, err)
+		}
+	}
+}
+
+func TestLoadDeepgramRedaction(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DEEPGRAM_API_KEY", "key")
+	t.Setenv("COLDMIC_DEEPGRAM_PROFANITY_FILTER", "true")
+	t.Setenv("COLDMIC_DEEPGRAM_REDACT", "PCI, ssn")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if !cfg.Deepgram.ProfanityFilter || !slices.Equal(cfg.Deepgram.Redact, []string{"pci", "ssn"}) {
+		t.Fatalf("unexpected redaction: %+v", cfg.Deepgram)
+	}
+
+	t.Setenv("COLDMIC_DEEPGRAM_REDACT", "pci,phone")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "phone") {
+		t.Fatalf("expected unknown entity to fail, got %v", err)
+	}
+}
+
+func TestLoadDeepgramTLS(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DEEPGRAM_API_KEY", "key")
+	t.Setenv("COLDMIC_DEEPGRAM_CA_FILE", "/etc/ssl/corp-ca.pem")
+	t.Setenv("COLDMIC_DEEPGRAM_CLIENT_CERT", "/etc/coldmic/client.pem")
+	t.Setenv("COLDMIC_DEEPGRAM_CLIENT_KEY", "/etc/coldmic/client.key")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	want := DeepgramTLSConfig{CAFile: "/etc/ssl/corp-ca.pem", ClientCert: "/etc/coldmic/client.pem", ClientKey: "/etc/coldmic/client.key"}
+	if cfg.Deepgram.TLS != want {
+		t.Fatalf("unexpected TLS config: %+v", cfg.Deepgram.TLS)
+	}
+
+	t.Setenv("COLDMIC_DEEPGRAM_CLIENT_KEY", "")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "COLDMIC_DEEPGRAM_CLIENT_KEY") {
+		t.Fatalf("expected a client cert without key to fail, got %v", err)
+	}
+}
+
+func TestLoadProxy(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DEEPGRAM_API_KEY", "key")
+	t.Setenv("COLDMIC_PROXY", " http://proxy.corp:3128 ")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Network.Proxy != "http://proxy.corp:3128" || cfg.Deepgram.Proxy != cfg.Network.Proxy {
+		t.Fatalf("expected the proxy on network and deepgram config, got %q %q", cfg.Network.Proxy, cfg.Deepgram.Proxy)
+	}
+}
+
+func TestLoadUsageRates(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DEEPGRAM_API_KEY", "key")
+	t.Setenv("COLDMIC_USAGE_RATES", "Deepgram=0.0043, openai=0.006")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if len(cfg.Usage.Rates) != 2 || cfg.Usage.Rates["deepgram"] != 0.0043 || cfg.Usage.Rates["openai"] != 0.006 {
+		t.Fatalf("unexpected rates: %+v", cfg.Usage.Rates)
+	}
+	if filepath.Base(cfg.Usage.Path) != "usage.json" {
+		t.Fatalf("unexpected usage path: %q", cfg.Usage.Path)
+	}
+
+	t.Setenv("COLDMIC_USAGE_RATES", "deepgram")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "COLDMIC_USAGE_RATES") {
+		t.Fatalf("expected an invalid rate to fail, got %v", err)
+	}
+}
+
+func TestLoadDryRun(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DEEPGRAM_API_KEY", "key")
+	t.Setenv("COLDMIC_DRY_RUN", "true")
+	t.Setenv("COLDMIC_PROVIDER", "replay")
+	t.Setenv("COLDMIC_ACCURATE_MODEL", "nova-3")
+	t.Setenv("COLDMIC_MIN_CONFIDENCE", "0.8")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Provider != "replay" || cfg.Accurate.Model != "" || cfg.Retry.MinConfidence != 0 || cfg.Session.Preflight || cfg.Storage.CacheMaxMB != 0 {
+		t.Fatalf("expected a dry run to disable every provider, got %+v", cfg)
+	}
+	if cfg.Audio.InputFormat == "silence" {
+		t.Fatalf("expected a dry run to keep the microphone")
+	}
+}
+
+func TestLoadIdleAbort(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DEEPGRAM_API_KEY", "key")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Session.IdleAbort != 0 || cfg.Session.IdleThreshold != 500 {
+		t.Fatalf("unexpected idle abort defaults: %s %d", cfg.Session.IdleAbort, cfg.Session.IdleThreshold)
+	}
+
+	t.Setenv("COLDMIC_IDLE_ABORT_SECONDS", "120")
+	if cfg, err = Load(); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Session.IdleAbort != 2*time.Minute {
+		t.Fatalf("expected a two-minute idle abort, got %s", cfg.Session.IdleAbort)
+	}
+
+	t.Setenv("COLDMIC_IDLE_ABORT_SECONDS", "-5")
+	t.Setenv("COLDMIC_IDLE_THRESHOLD", "800")
+	if cfg, err = Load(); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Session.IdleAbort != 0 || cfg.Session.IdleThreshold != 800 {
+		t.Fatalf("expected idle abort disabled, got %s %d", cfg.Session.IdleAbort, cfg.Session.IdleThreshold)
+	}
+}
+
+func TestLoadRecordingLED(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DEEPGRAM_API_KEY", "key")
+	t.Setenv("COLDMIC_RECORDING_LED", "auto, rgb:kbd_backlight")
+	t.Setenv("COLDMIC_RECORDING_LED_COLOR", "#FF2000")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if len(cfg.Session.RecordingLEDs) != 2 || cfg.Session.RecordingLEDs[1] != "rgb:kbd_backlight" || cfg.Session.RecordingLEDColor != "FF2000" {
+		t.Fatalf("unexpected LED config: %+v %q", cfg.Session.RecordingLEDs, cfg.Session.RecordingLEDColor)
+	}
+
+	t.Setenv("COLDMIC_RECORDING_LED_COLOR", "red")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "COLDMIC_RECORDING_LED_COLOR") {
+		t.Fatalf("expected an invalid color to fail, got %v", err)
+	}
+}
+
+func TestLoadGroqProviderConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_PROVIDER", "groq")
+	t.Setenv("GROQ_API_KEY", " gsk-test ")
+	t.Setenv("GROQ_API_BASE", "")
+	t.Setenv("COLDMIC_GROQ_MODEL", "")
+	t.Setenv("COLDMIC_GROQ_LANGUAGE", "es")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	want := GroqConfig{APIKey: "gsk-test", APIBaseURL: "https://api.groq.com/openai/v1", Model: "whisper-large-v3-turbo", Language: "es"}
+	if cfg.Provider != "groq" || cfg.Groq != want {
+		t.Fatalf("unexpected groq config: %q %+v", cfg.Provider, cfg.Groq)
+	}
+}
+
+func TestLoadAzureProviderConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_PROVIDER", "azure")
+	t.Setenv("AZURE_SPEECH_KEY", " azure-key ")
+	t.Setenv("AZURE_SPEECH_REGION", "westeurope")
+	t.Setenv("AZURE_SPEECH_ENDPOINT", "")
+	t.Setenv("AZURE_SPEECH_LANGUAGE", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	want := AzureConfig{APIKey: "azure-key", Region: "westeurope", Language: "en-US"}
+	if cfg.Provider != "azure" || cfg.Azure != want {
+		t.Fatalf("unexpected azure config: %q %+v", cfg.Provider, cfg.Azure)
+	}
+}
+
+func TestLoadGladiaProviderConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_PROVIDER", "Gladia")
+	t.Setenv("GLADIA_API_KEY", " gladia-key ")
+	t.Setenv("GLADIA_API_BASE", "")
+	t.Setenv("COLDMIC_GLADIA_LANGUAGE", "")
+	t.Setenv("DEEPGRAM_LANGUAGE", "fr")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	want := GladiaConfig{APIKey: "gladia-key", APIBaseURL: "https://api.gladia.io", Language: "fr"}
+	if cfg.Provider != "gladia" || cfg.Gladia != want {
+		t.Fatalf("unexpected gladia config: %q %+v", cfg.Provider, cfg.Gladia)
+	}
+}
+
+func TestLoadRaceConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_RACE_PROVIDER", " AssemblyAI ")
+	t.Setenv("COLDMIC_RACE_PICK", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Race != (RaceConfig{Provider: "assemblyai", Pick: "confidence"}) {
+		t.Fatalf("unexpected race config: %+v", cfg.Race)
+	}
+}
+
+func TestLoadCustomWSProviderConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_PROVIDER", "customws")
+	t.Setenv("COLDMIC_CUSTOM_WS_URL", " ws://localhost:9000/stream ")
+	t.Setenv("COLDMIC_CUSTOM_WS_HEADER", "Authorization: Bearer secret")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	want := CustomWSConfig{URL: "ws://localhost:9000/stream", Header: "Authorization: Bearer secret"}
+	if cfg.Provider != "customws" || cfg.CustomWS != want {
+		t.Fatalf("unexpected custom websocket config: %q %+v", cfg.Provider, cfg.CustomWS)
+	}
+}
+
+func TestLoadWhisperCppProviderConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_PROVIDER", "whispercpp")
+	t.Setenv("COLDMIC_WHISPERCPP_COMMAND", "")
+	t.Setenv("COLDMIC_WHISPERCPP_MODEL", " /models/ggml-base.en.bin ")
+	t.Setenv("COLDMIC_WHISPERCPP_LANGUAGE", "en")
+	t.Setenv("COLDMIC_WHISPERCPP_THREADS", "8")
+	t.Setenv("COLDMIC_WHISPERCPP_ACCELERATION", "Vulkan")
+	t.Setenv("COLDMIC_WHISPERCPP_DEVICE", "")
+	t.Setenv("COLDMIC_WHISPERCPP_STEP_MS", "0")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	want := WhisperCppConfig{Command: "whisper-cli", ModelPath: "/models/ggml-base.en.bin", Language: "en", Threads: 8, Acceleration: "vulkan", Window: 15 * time.Second, Overlap: time.Second}
+	if cfg.Provider != "whispercpp" || cfg.WhisperCpp != want {
+		t.Fatalf("unexpected whisper.cpp config: %q %+v", cfg.Provider, cfg.WhisperCpp)
+	}
+}
+
+func TestLoadClipboardOverflowConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_CLIPBOARD_MAX_CHARS", "20000")
+	t.Setenv("COLDMIC_CLIPBOARD_OVERFLOW", "Path")
+	t.Setenv("COLDMIC_CLIPBOARD_OVERFLOW_DIR", " /tmp/coldmic-overflow ")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Clipboard.MaxChars != 20000 || cfg.Clipboard.Overflow != "path" || cfg.Clipboard.OverflowDir != "/tmp/coldmic-overflow" {
+		t.Fatalf("unexpected overflow config: %+v", cfg.Clipboard)
+	}
+
+	t.Setenv("COLDMIC_CLIPBOARD_MAX_CHARS", "-1")
+	t.Setenv("COLDMIC_CLIPBOARD_OVERFLOW", "truncate")
+	if cfg, _ = Load(); cfg.Clipboard.MaxChars != 0 || cfg.Clipboard.Overflow != "preview" {
+		t.Fatalf("expected overflow defaults, got %+v", cfg.Clipboard)
+	}
+}
+
+func TestLoadClipboardSplitConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_CLIPBOARD_SPLIT", "Sentences")
+	t.Setenv("COLDMIC_CLIPBOARD_SPLIT_DELAY_MS", "-10")
+	t.Setenv("COLDMIC_CLIPBOARD_SPLIT_COMMAND", " cliphist  store ")
+	t.Setenv("COLDMIC_COPY_HOOK", "notify-copied --quiet; ; log-copy")
+	t.Setenv("COLDMIC_COPY_HOOK_TIMEOUT_MS", "5000")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Clipboard.Split != "sentences" || cfg.Clipboard.SplitDelay != 0 {
+		t.Fatalf("unexpected clipboard config: %+v", cfg.Clipboard)
+	}
+	if len(cfg.Clipboard.SplitCommand) != 2 || cfg.Clipboard.SplitCommand[1] != "store" {
+		t.Fatalf("unexpected split command: %q", cfg.Clipboard.SplitCommand)
+	}
+	hooks := cfg.Clipboard.CopyHooks
+	if len(hooks) != 2 || len(hooks[0]) != 2 || hooks[0][0] != "notify-copied" || hooks[1][0] != "log-copy" {
+		t.Fatalf("unexpected copy hooks: %q", hooks)
+	}
+	if cfg.Clipboard.CopyHookTimeout != 5*time.Second {
+		t.Fatalf("unexpected copy hook timeout: %s", cfg.Clipboard.CopyHookTimeout)
+	}
+
+	t.Setenv("COLDMIC_CLIPBOARD_SPLIT", "words")
+	if cfg, _ = Load(); cfg.Clipboard.Split != "off" {
+		t.Fatalf("expected unknown split mode to fall back to off, got %q", cfg.Clipboard.Split)
+	}
+}
+
+func TestLoadFormsConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("COLDMIC_FORMS_DIR", "")
+	t.Setenv("COLDMIC_FORM", " Contact ")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Forms.Dir != filepath.Join(home, ".config", "coldmic", "forms") || cfg.Forms.Active != "contact" {
+		t.Fatalf("unexpected forms config: %+v", cfg.Forms)
+	}
+}
+
+func TestLoadTargetConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_TARGET", "")
+	t.Setenv("COLDMIC_GIT_COMMIT_REPO", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Target.Name != "clipboard" {
+		t.Fatalf("expected clipboard target by default, got %q", cfg.Target.Name)
+	}
+
+	t.Setenv("COLDMIC_TARGET", "Git-Commit")
+	t.Setenv("COLDMIC_GIT_COMMIT_REPO", " /src/app ")
+	if cfg, _ = Load(); cfg.Target.Name != "git-commit" || cfg.Target.GitCommitRepo != "/src/app" {
+		t.Fatalf("unexpected target config: %+v", cfg.Target)
+	}
+}
+
+func TestLoadTaskProjectDefaultsToWorkspace(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("COLDMIC_TASK_PROJECT", "")
+	t.Setenv("COLDMIC_TODO_FILE", "")
+	t.Setenv("TODO_FILE", "")
+
+	cfg, err := LoadWorkspace("clients")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Target.TaskProject != "clients" || cfg.Target.TodoFile != filepath.Join(home, "todo.txt") {
+		t.Fatalf("unexpected task config: %+v", cfg.Target)
+	}
+
+	t.Setenv("COLDMIC_TASK_PROJECT", "inbox")
+	if cfg, _ = LoadWorkspace("clients"); cfg.Target.TaskProject != "inbox" {
+		t.Fatalf("expected explicit project to win, got %q", cfg.Target.TaskProject)
+	}
+	if cfg, _ = LoadWorkspace(""); cfg.Target.TaskProject != "inbox" {
+		t.Fatalf("unexpected default workspace project %q", cfg.Target.TaskProject)
+	}
+}
+
+func TestLoadAccessibilityConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_ANNOUNCE", "")
+	t.Setenv("COLDMIC_ANNOUNCE_BACKEND", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Accessibility.Verbosity != "off" || cfg.Accessibility.Backend != "speech-dispatcher" {
+		t.Fatalf("unexpected default accessibility config: %+v", cfg.Accessibility)
+	}
+
+	t.Setenv("COLDMIC_ANNOUNCE", "Full")
+	t.Setenv("COLDMIC_ANNOUNCE_BACKEND", "braille")
+	if cfg, _ = Load(); cfg.Accessibility.Verbosity != "full" || cfg.Accessibility.Backend != "speech-dispatcher" {
+		t.Fatalf("unexpected accessibility config: %+v", cfg.Accessibility)
+	}
+}
+
+func TestLoadWorkspaceIsolatesState(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("COLDMIC_DATA_DIR", filepath.Join(home, "data"))
+	t.Setenv("COLDMIC_HISTORY_FILE", filepath.Join(home, "shared.jsonl"))
+	t.Setenv("COLDMIC_RULES_FILE", filepath.Join(home, "shared.rules"))
+	t.Setenv("DEEPGRAM_API_KEY", "shared-key")
+	t.Setenv("DEEPGRAM_API_KEY_PERSONAL", "")
+
+	base, err := LoadWorkspace("")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if base.Workspace != DefaultWorkspace || base.Storage.HistoryPath != filepath.Join(home, "shared.jsonl") {
+		t.Fatalf("unexpected default workspace config: %q %q", base.Workspace, base.Storage.HistoryPath)
+	}
+
+	cfg, err := LoadWorkspace(" Personal ")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	wsDir := filepath.Join(home, "data", "workspaces", "personal")
+	if cfg.Workspace != "personal" || cfg.Storage.DataDir != wsDir || cfg.Storage.HistoryPath != filepath.Join(wsDir, "history.jsonl") {
+		t.Fatalf("unexpected workspace storage: %+v", cfg.Storage)
+	}
+	if cfg.Rules.Path != filepath.Join(home, ".config", "coldmic", "workspaces", "personal", "substitutions.rules") {
+		t.Fatalf("unexpected workspace rules path: %q", cfg.Rules.Path)
+	}
+	if cfg.Deepgram.APIKey != "shared-key" || cfg.Backup.ObjectName != "coldmic-history-personal.bundle" {
+		t.Fatalf("unexpected workspace fallbacks: key=%q object=%q", cfg.Deepgram.APIKey, cfg.Backup.ObjectName)
+	}
+
+	t.Setenv("DEEPGRAM_API_KEY_PERSONAL", "personal-key")
+	cfg, err = LoadWorkspace("personal")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Deepgram.APIKey != "personal-key" {
+		t.Fatalf("expected workspace provider key, got %q", cfg.Deepgram.APIKey)
+	}
+
+	if _, err := LoadWorkspace("../etc"); err == nil {
+		t.Fatalf("expected invalid workspace name to fail")
+	}
+}
+
+func TestLoadReadsDefinedTargets(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("COLDMIC_JIRA_PROJECT", "OPS")
+	t.Setenv("WORK_JIRA_TOKEN", "secret")
+	path := filepath.Join(home, "targets.json")
+	t.Setenv("COLDMIC_TARGETS_FILE", path)
+	t.Setenv("COLDMIC_TARGET", "work-jira")
+	targets := `{"Work-Jira": {"type": "jira-issue", "url": "https://jira.example.com", "project": "WORK", "token_env": "WORK_JIRA_TOKEN"}}`
+	if err := os.WriteFile(path, []byte(targets), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	kind, settings, err := cfg.ResolveTarget("work-jira")
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if kind != "jira-issue" || settings.JiraProject != "WORK" || settings.JiraToken != "secret" || settings.JiraURL != "https://jira.example.com" {
+		t.Fatalf("unexpected target settings: %s %+v", kind, settings)
+	}
+	if _, settings, _ := cfg.ResolveTarget("jira-issue"); settings.JiraProject != "OPS" {
+		t.Fatalf("expected built-in target to keep env settings, got %q", settings.JiraProject)
+	}
+	listed := cfg.ListTargets("work-jira")
+	if last := listed[len(listed)-1]; last.Name != "work-jira" || last.BuiltIn || !last.Active {
+		t.Fatalf("expected defined target listed last and active, got %+v", last)
+	}
+}
+
+func TestLoadRejectsInvalidTargets(t *testing.T) {
+	cases := map[string]string{
+		"unknown type":     `{"notes": {"type": "email"}}`,
+		"missing field":    `{"work": {"type": "github-issue"}}`,
+		"foreign field":    `{"work": {"type": "todo", "repo": "a/b"}}`,
+		"unknown field":    `{"work": {"type": "todo", "path": "x"}}`,
+		"builtin redefine": `{"todo": {"type": "todo"}}`,
+		"bad name":         `{"my notes": {"type": "todo"}}`,
+	}
+	for name, targets := range cases {
+		t.Run(name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			path := filepath.Join(home, "targets.json")
+			t.Setenv("COLDMIC_TARGETS_FILE", path)
+			if err := os.WriteFile(path, []byte(targets), 0o600); err != nil {
+				t.Fatalf("write failed: %v", err)
+			}
+			if _, err := Load(); err == nil {
+				t.Fatalf("expected %s to be rejected", name)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsUnknownTarget(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_TARGET", "nowhere")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "nowhere") {
+		t.Fatalf("expected unknown COLDMIC_TARGET to be rejected, got %v", err)
+	}
+}
+
+func TestLoadReadsHotkeys(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	targetsPath := filepath.Join(home, "targets.json")
+	t.Setenv("COLDMIC_TARGETS_FILE", targetsPath)
+	if err := os.WriteFile(targetsPath, []byte(`{"email": {"type": "todo"}}`), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	path := filepath.Join(home, "hotkeys.json")
+	t.Setenv("COLDMIC_HOTKEYS_FILE", path)
+	hotkeys := `{
+		"mail": {"keys": "Super+E", "action": "start", "target": "email"},
+		"code": {"keys": "Super+C", "action": "Toggle", "target": "git-commit"},
+		"cancel": {"action": "abort"}
+	}`
+	if err := os.WriteFile(path, []byte(hotkeys), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if len(cfg.Hotkeys) != 3 || cfg.Hotkeys[0].Name != "cancel" || cfg.Hotkeys[1].Action != domain.HotkeyToggle {
+		t.Fatalf("expected hotkeys sorted by name, got %+v", cfg.Hotkeys)
+	}
+	if mail := cfg.Hotkeys[2]; mail.Target != "email" || mail.Keys != "Super+E" {
+		t.Fatalf("unexpected mail hotkey: %+v", mail)
+	}
+}
+
+func TestLoadRejectsInvalidHotkeys(t *testing.T) {
+	cases := map[string]string{
+		"unknown action":   `{"go": {"action": "pause"}}`,
+		"unknown target":   `{"go": {"action": "start", "target": "nowhere"}}`,
+		"target on abort":  `{"go": {"action": "abort", "target": "todo"}}`,
+		"unknown field":    `{"go": {"action": "start", "mode": "x"}}`,
+		"bad name":         `{"my key": {"action": "stop"}}`,
+		"select no target": `{"go": {"action": "select"}}`,
+	}
+	for name, hotkeys := range cases {
+		t.Run(name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			path := filepath.Join(home, "hotkeys.json")
+			t.Setenv("COLDMIC_HOTKEYS_FILE", path)
+			if err := os.WriteFile(path, []byte(hotkeys), 0o600); err != nil {
+				t.Fatalf("write failed: %v", err)
+			}
+			if _, err := Load(); err == nil {
+				t.Fatalf("expected %s to be rejected", name)
+			}
+		})
+	}
+}
+
+func TestLoadReadsMIDIBindings(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	hotkeysPath := filepath.Join(home, "hotkeys.json")
+	t.Setenv("COLDMIC_HOTKEYS_FILE", hotkeysPath)
+	if err := os.WriteFile(hotkeysPath, []byte(`{"talk": {"action": "start"}, "done": {"action": "stop"}, "code": {"action": "select", "target": "git-commit"}}`), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	path := filepath.Join(home, "midi.json")
+	t.Setenv("COLDMIC_MIDI_FILE", path)
+	t.Setenv("COLDMIC_MIDI_DEVICE", "/dev/snd/midiC1D0")
+	midi := `{
+		"note:37": {"press": "code"},
+		"CC:64": {"channel": 2, "press": "talk", "release": "done"},
+		"note:36": {"press": "Talk", "release": "done"}
+	}`
+	if err := os.WriteFile(path, []byte(midi), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Trigger.MIDIDevice != "/dev/snd/midiC1D0" || len(cfg.MIDI) != 3 {
+		t.Fatalf("unexpected MIDI config: %+v %+v", cfg.Trigger, cfg.MIDI)
+	}
+	want := domain.MIDIBinding{Control: domain.MIDICC, Number: 64, Channel: 2, Press: "talk", Release: "done"}
+	if cfg.MIDI[0] != want || cfg.MIDI[1].Number != 36 || cfg.MIDI[1].Press != "talk" || cfg.MIDI[2].Number != 37 {
+		t.Fatalf("expected bindings sorted by control, got %+v", cfg.MIDI)
+	}
+
+	for name, midi := range map[string]string{
+		"unknown hotkey": `{"note:36": {"press": "missing"}}`,
+		"bad control":    `{"pad:36": {"press": "talk"}}`,
+		"bad number":     `{"note:128": {"press": "talk"}}`,
+		"bad channel":    `{"note:36": {"channel": 17, "press": "talk"}}`,
+		"no hotkey":      `{"note:36": {}}`,
+	} {
+		if err := os.WriteFile(path, []byte(midi), 0o600); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+		if _, err := Load(); err == nil {
+			t.Fatalf("expected %s to be rejected", name)
+		}
+	}
+}
+
+func TestDefaultInputFormat(t *testing.T) {
+	t.Parallel()
+
+	if got := defaultInputFormat("windows"); got != "dshow" {
+		t.Fatalf("expected dshow on windows, got %q", got)
+	}
+	if got := defaultInputFormat("darwin"); got != "avfoundation" {
+		t.Fatalf("expected avfoundation on macOS, got %q", got)
+	}
+	if got := defaultInputFormat("linux"); got != "pulse" {
+		t.Fatalf("expected pulse on linux, got %q", got)
+	}
+}
+
+func TestLoadHistoryBackend(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("COLDMIC_DATA_DIR", "")
+	t.Setenv("COLDMIC_HISTORY_FILE", "")
+	t.Setenv("COLDMIC_HISTORY_BACKEND", "Markdown")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	dataDir := filepath.Join(home, ".local", "share", "coldmic")
+	if cfg.Storage.HistoryBackend != "markdown" || cfg.Storage.HistoryPath != filepath.Join(dataDir, "history") {
+		t.Fatalf("unexpected markdown history config: %+v", cfg.Storage)
+	}
+
+	t.Setenv("COLDMIC_HISTORY_BACKEND", "sqlite")
+	cfg, err = LoadWorkspace("client-a")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Storage.HistoryPath != filepath.Join(dataDir, "workspaces", "client-a", "history.db") {
+		t.Fatalf("unexpected workspace database path: %q", cfg.Storage.HistoryPath)
+	}
+
+	t.Setenv("COLDMIC_HISTORY_BACKEND", "postgres")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "COLDMIC_HISTORY_BACKEND") {
+		t.Fatalf("expected an unsupported backend error, got %v", err)
+	}
+}
+
+func TestAccessTokensAreSharedAcrossWorkspaces(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COLDMIC_DATA_DIR", "")
+
+	work, err := LoadWorkspace("client-a")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	secret, _, err := auth.NewFileTokenStore(work.Storage.TokensPath).Create(context.Background(), "cli", domain.TokenScopeFull)
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+
+	def, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if def.Storage.TokensPath != work.Storage.TokensPath {
+		t.Fatalf("expected one tokens file, got %q and %q", def.Storage.TokensPath, work.Storage.TokensPath)
+	}
+	if _, err := auth.NewFileTokenStore(def.Storage.TokensPath).Verify(context.Background(), secret); err != nil {
+		t.Fatalf("expected the daemon's store to accept a workspace token: %v", err)
+	}
+}
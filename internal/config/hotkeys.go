@@ -0,0 +1,83 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	"coldmic/internal/domain"
+)
+
+// hotkeyDefinition is one entry of the hotkeys file, keyed by hotkey name.
+type hotkeyDefinition struct {
+	Keys   string `json:"keys,omitempty"`
+	Action string `json:"action"`
+	Target string `json:"target,omitempty"`
+}
+
+// loadHotkeys reads and validates the hotkeys file against the targets of
+// cfg; a missing file means no hotkeys. Hotkeys are sorted by name.
+func loadHotkeys(cfg Config, path string) ([]domain.Hotkey, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hotkeys: %w", err)
+	}
+	var raw map[string]hotkeyDefinition
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid hotkeys %s: %w", path, err)
+	}
+	hotkeys := make([]domain.Hotkey, 0, len(raw))
+	for name, definition := range raw {
+		hotkey := domain.Hotkey{
+			Name:   strings.ToLower(strings.TrimSpace(name)),
+			Keys:   strings.TrimSpace(definition.Keys),
+			Action: domain.HotkeyAction(strings.ToLower(strings.TrimSpace(definition.Action))),
+			Target: strings.ToLower(strings.TrimSpace(definition.Target)),
+		}
+		if err := cfg.validateHotkey(hotkey); err != nil {
+			return nil, fmt.Errorf("invalid hotkeys %s: %w", path, err)
+		}
+		hotkeys = append(hotkeys, hotkey)
+	}
+	slices.SortFunc(hotkeys, func(a, b domain.Hotkey) int { return strings.Compare(a.Name, b.Name) })
+	return hotkeys, nil
+}
+
+func (c Config) validateHotkey(hotkey domain.Hotkey) error {
+	if !targetNamePattern.MatchString(hotkey.Name) {
+		return fmt.Errorf("hotkey name %q must be lowercase letters, digits, '-' or '_'", hotkey.Name)
+	}
+	if !slices.Contains(domain.HotkeyActions, hotkey.Action) {
+		actions := make([]string, len(domain.HotkeyActions))
+		for i, action := range domain.HotkeyActions {
+			actions[i] = string(action)
+		}
+		return fmt.Errorf("hotkey %q has unknown action %q (expected one of %s)", hotkey.Name, hotkey.Action, strings.Join(actions, ", "))
+	}
+	if hotkey.Target == "" {
+		if hotkey.Action == domain.HotkeySelect {
+			return fmt.Errorf("hotkey %q: action select needs a target", hotkey.Name)
+		}
+		return nil
+	}
+	if hotkey.Action != domain.HotkeyStart && hotkey.Action != domain.HotkeyToggle && hotkey.Action != domain.HotkeySelect {
+		return fmt.Errorf("hotkey %q: target does not apply to action %s", hotkey.Name, hotkey.Action)
+	}
+	if _, _, err := c.ResolveTarget(hotkey.Target); err != nil {
+		return fmt.Errorf("hotkey %q: %w", hotkey.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"coldmic/internal/domain"
+)
+
+// midiDefinition is one entry of the MIDI file, keyed by control such as
+// "note:36" or "cc:64".
+type midiDefinition struct {
+	Channel int    `json:"channel,omitempty"`
+	Press   string `json:"press,omitempty"`
+	Release string `json:"release,omitempty"`
+}
+
+// loadMIDIBindings reads and validates the MIDI file against the hotkeys of
+// cfg; a missing file means no bindings. Bindings are sorted by control.
+func loadMIDIBindings(cfg Config, path string) ([]domain.MIDIBinding, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read MIDI bindings: %w", err)
+	}
+	var raw map[string]midiDefinition
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid MIDI bindings %s: %w", path, err)
+	}
+	bindings := make([]domain.MIDIBinding, 0, len(raw))
+	for control, definition := range raw {
+		binding, err := cfg.parseMIDIBinding(control, definition)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MIDI bindings %s: %w", path, err)
+		}
+		bindings = append(bindings, binding)
+	}
+	slices.SortFunc(bindings, func(a, b domain.MIDIBinding) int {
+		return cmp.Or(strings.Compare(string(a.Control), string(b.Control)), cmp.Compare(a.Number, b.Number), cmp.Compare(a.Channel, b.Channel))
+	})
+	return bindings, nil
+}
+
+func (c Config) parseMIDIBinding(control string, definition midiDefinition) (domain.MIDIBinding, error) {
+	kind, number, _ := strings.Cut(strings.ToLower(strings.TrimSpace(control)), ":")
+	binding := domain.MIDIBinding{
+		Control: domain.MIDIControl(kind),
+		Channel: definition.Channel,
+		Press:   strings.ToLower(strings.TrimSpace(definition.Press)),
+		Release: strings.ToLower(strings.TrimSpace(definition.Release)),
+	}
+	if binding.Control != domain.MIDINote && binding.Control != domain.MIDICC {
+		return domain.MIDIBinding{}, fmt.Errorf("MIDI control %q must be note:<number> or cc:<number>", control)
+	}
+	var err error
+	if binding.Number, err = strconv.Atoi(number); err != nil || binding.Number < 0 || binding.Number > 127 {
+		return domain.MIDIBinding{}, fmt.Errorf("MIDI control %q needs a number from 0 to 127", control)
+	}
+	if binding.Channel < 0 || binding.Channel > 16 {
+		return domain.MIDIBinding{}, fmt.Errorf("MIDI control %q: channel must be 1 to 16, or 0 for any", control)
+	}
+	if binding.Press == "" && binding.Release == "" {
+		return domain.MIDIBinding{}, fmt.Errorf("MIDI control %q needs a press or release hotkey", control)
+	}
+	for _, name := range []string{binding.Press, binding.Release} {
+		if name != "" && !slices.ContainsFunc(c.Hotkeys, func(hotkey domain.Hotkey) bool { return hotkey.Name == name }) {
+			return domain.MIDIBinding{}, fmt.Errorf("MIDI control %q: unknown hotkey %q", control, name)
+		}
+	}
+	return binding, nil
+}
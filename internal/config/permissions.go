@@ -0,0 +1,79 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+
+	"coldmic/internal/debuglog"
+)
+
+// AuditPermissions checks that configuration and state belong to the current
+// user alone. World-writable rules files, Deepgram profiles or data
+// directories are refused, since anyone could inject substitutions, redirect
+// audio or read transcripts; state that is
+// merely readable by others is tightened to 0600/0700.
+func AuditPermissions(cfg Config) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	if err := refuseWorldWritable("rules file", cfg.Rules.Path); err != nil {
+		return err
+	}
+	if err := refuseWorldWritable("Deepgram profiles", cfg.DeepgramProfilesPath); err != nil {
+		return err
+	}
+	if err := refuseWorldWritable("data directory", cfg.Storage.DataDir); err != nil {
+		return err
+	}
+
+	tighten(cfg.Storage.DataDir, 0o700)
+	tighten(cfg.Storage.RecordingsDir, 0o700)
+	tighten(cfg.Storage.CacheDir, 0o700)
+	if cfg.Storage.HistoryBackend == "markdown" {
+		tighten(cfg.Storage.HistoryPath, 0o700)
+	} else {
+		tighten(cfg.Storage.HistoryPath, 0o600)
+	}
+	tighten(cfg.Storage.TokensPath, 0o600)
+	tighten(cfg.Storage.JobsPath, 0o600)
+	return nil
+}
+
+func refuseWorldWritable(label string, path string) error {
+	if path == "" {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to check %s %q: %w", label, path, err)
+	}
+	if info.Mode().Perm()&0o002 != 0 {
+		return fmt.Errorf("%s %q is world-writable; run: chmod o-w %q", label, path, path)
+	}
+	return nil
+}
+
+func tighten(path string, perm fs.FileMode) {
+	if path == "" {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	if info.Mode().Perm()&^perm == 0 {
+		return
+	}
+	if err := os.Chmod(path, perm); err != nil {
+		debuglog.Printf("permission audit could not restrict %q: %v", path, err)
+		return
+	}
+	debuglog.Printf("permission audit restricted %q from %o to %o", path, info.Mode().Perm(), perm)
+}
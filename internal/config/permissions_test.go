@@ -0,0 +1,42 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuditPermissionsTightensStateAndRefusesWorldWritable(t *testing.T) {
+	t.Parallel()
+
+	dataDir := filepath.Join(t.TempDir(), "data")
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	history := filepath.Join(dataDir, "history.jsonl")
+	if err := os.WriteFile(history, []byte("{}\n"), 0o644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	cfg := Config{Storage: StorageConfig{DataDir: dataDir, HistoryPath: history}}
+
+	if err := AuditPermissions(cfg); err != nil {
+		t.Fatalf("audit failed: %v", err)
+	}
+	for path, want := range map[string]os.FileMode{dataDir: 0o700, history: 0o600} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("stat failed: %v", err)
+		}
+		if info.Mode().Perm() != want {
+			t.Fatalf("expected %q to be %o, got %o", path, want, info.Mode().Perm())
+		}
+	}
+
+	if err := os.Chmod(dataDir, 0o777); err != nil {
+		t.Fatalf("chmod failed: %v", err)
+	}
+	if err := AuditPermissions(cfg); err == nil || !strings.Contains(err.Error(), "world-writable") {
+		t.Fatalf("expected world-writable data directory error, got %v", err)
+	}
+}
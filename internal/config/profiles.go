@@ -0,0 +1,132 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+)
+
+// DeepgramProfile is a named Deepgram-compatible endpoint, such as an
+// on-prem deployment with its own auth scheme and certificate authority.
+type DeepgramProfile struct {
+	URL   string `json:"url"`
+	Model string `json:"model,omitempty"`
+	// Auth is token (default), bearer, none or header:<Name>.
+	Auth string `json:"auth,omitempty"`
+	// KeyEnv names the variable holding the key, so the file holds no
+	// secrets; empty uses DEEPGRAM_API_KEY.
+	KeyEnv             string `json:"key_env,omitempty"`
+	CAFile             string `json:"ca_file,omitempty"`
+	ClientCert         string `json:"client_cert,omitempty"`
+	ClientKey          string `json:"client_key,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+	// Workspaces and Targets select the profile automatically.
+	Workspaces []string `json:"workspaces,omitempty"`
+	Targets    []string `json:"targets,omitempty"`
+}
+
+// DeepgramTLSConfig customizes TLS for self-hosted Deepgram endpoints.
+type DeepgramTLSConfig struct {
+	CAFile             string
+	ClientCert         string
+	ClientKey          string
+	InsecureSkipVerify bool
+}
+
+// loadDeepgramProfiles reads the profiles file; a missing file means no
+// profiles.
+func loadDeepgramProfiles(path string) (map[string]DeepgramProfile, error) {
+	profiles := map[string]DeepgramProfile{}
+	if path == "" {
+		return profiles, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Deepgram profiles: %w", err)
+	}
+	var raw map[string]DeepgramProfile
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid Deepgram profiles %s: %w", path, err)
+	}
+	for name, profile := range raw {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if strings.TrimSpace(profile.URL) == "" {
+			return nil, fmt.Errorf("Deepgram profile %q has no url", name)
+		}
+		if (profile.ClientCert == "") != (profile.ClientKey == "") {
+			return nil, fmt.Errorf("Deepgram profile %q needs both client_cert and client_key", name)
+		}
+		profiles[name] = profile
+	}
+	return profiles, nil
+}
+
+// apply returns base pointed at the profile's endpoint. The language and
+// formatting settings are kept.
+func (p DeepgramProfile) apply(name string, base DeepgramConfig) DeepgramConfig {
+	base.Profile = name
+	base.APIBaseURL = strings.TrimSpace(p.URL)
+	if p.Model != "" {
+		base.Model = p.Model
+	}
+	base.Auth = strings.ToLower(strings.TrimSpace(p.Auth))
+	if strings.HasPrefix(base.Auth, "header:") {
+		// Header names keep their case.
+		base.Auth = "header:" + strings.TrimSpace(p.Auth[len("header:"):])
+	}
+	if p.KeyEnv != "" {
+		base.APIKey = strings.TrimSpace(os.Getenv(p.KeyEnv))
+	}
+	base.TLS = DeepgramTLSConfig{
+		CAFile:             p.CAFile,
+		ClientCert:         p.ClientCert,
+		ClientKey:          p.ClientKey,
+		InsecureSkipVerify: p.InsecureSkipVerify,
+	}
+	return base
+}
+
+// selectDeepgramProfile names the profile for a workspace: the explicit
+// choice if any, otherwise the first profile listing the workspace.
+func selectDeepgramProfile(profiles map[string]DeepgramProfile, explicit string, workspace string) (string, error) {
+	if explicit = strings.ToLower(strings.TrimSpace(explicit)); explicit != "" {
+		if _, ok := profiles[explicit]; !ok {
+			return "", fmt.Errorf("unknown Deepgram profile %q", explicit)
+		}
+		return explicit, nil
+	}
+	for _, name := range sortedProfileNames(profiles) {
+		if slices.Contains(profiles[name].Workspaces, workspace) {
+			return name, nil
+		}
+	}
+	return "", nil
+}
+
+// DeepgramForTarget returns the Deepgram settings for an output target that
+// a profile lists, and false when the target uses the default settings.
+func (c Config) DeepgramForTarget(target string) (DeepgramConfig, bool) {
+	target = strings.ToLower(strings.TrimSpace(target))
+	for _, name := range sortedProfileNames(c.DeepgramProfiles) {
+		if profile := c.DeepgramProfiles[name]; slices.Contains(profile.Targets, target) {
+			return profile.apply(name, c.deepgramDefault), true
+		}
+	}
+	return c.Deepgram, false
+}
+
+func sortedProfileNames(profiles map[string]DeepgramProfile) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,214 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"coldmic/internal/domain"
+)
+
+// BuiltinTargets are the output target types. Each can be used by name with
+// the COLDMIC_* settings, or configured under another name in the targets
+// file.
+var BuiltinTargets = []string{"clipboard", "git-commit", "github-issue", "jira-issue", "reminder", "todo", "taskwarrior"}
+
+// targetFields lists the settings each target type accepts, and which of
+// them are required.
+var targetFields = map[string]map[string]bool{
+	"clipboard":    {},
+	"git-commit":   {"repo": false, "commit_rules": false},
+	"github-issue": {"repo": true, "url": false, "token_env": false},
+	"jira-issue":   {"url": true, "project": true, "email": false, "issue_type": false, "token_env": false},
+	"reminder":     {"dir": false, "command": false},
+	"todo":         {"file": false, "project": false, "context": false},
+	"taskwarrior":  {"command": false, "project": false, "context": false},
+}
+
+var targetNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// TargetDefinition is a named output target in the targets file, such as a
+// second Jira project. Type is a built-in target; the other fields replace
+// its COLDMIC_* settings and must apply to that type.
+type TargetDefinition struct {
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	// Repo is the git repository for git-commit and owner/name for
+	// github-issue.
+	Repo        string `json:"repo,omitempty"`
+	CommitRules string `json:"commit_rules,omitempty"`
+	// URL is the GitHub API or Jira base URL.
+	URL       string `json:"url,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Project   string `json:"project,omitempty"`
+	IssueType string `json:"issue_type,omitempty"`
+	// TokenEnv names the variable holding the tracker token, so the file
+	// holds no secrets.
+	TokenEnv string `json:"token_env,omitempty"`
+	Dir      string `json:"dir,omitempty"`
+	File     string `json:"file,omitempty"`
+	Command  string `json:"command,omitempty"`
+	Context  string `json:"context,omitempty"`
+}
+
+// loadTargets reads and validates the targets file; a missing file means no
+// defined targets.
+func loadTargets(path string) (map[string]TargetDefinition, error) {
+	targets := map[string]TargetDefinition{}
+	if path == "" {
+		return targets, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return targets, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read targets: %w", err)
+	}
+	var raw map[string]TargetDefinition
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid targets %s: %w", path, err)
+	}
+	for name, target := range raw {
+		name = strings.ToLower(strings.TrimSpace(name))
+		target.Type = strings.ToLower(strings.TrimSpace(target.Type))
+		if err := target.validate(name); err != nil {
+			return nil, fmt.Errorf("invalid targets %s: %w", path, err)
+		}
+		targets[name] = target
+	}
+	return targets, nil
+}
+
+func (t TargetDefinition) validate(name string) error {
+	if !targetNamePattern.MatchString(name) {
+		return fmt.Errorf("target name %q must be lowercase letters, digits, '-' or '_'", name)
+	}
+	if slices.Contains(BuiltinTargets, name) {
+		return fmt.Errorf("target %q is built in and cannot be redefined", name)
+	}
+	fields, ok := targetFields[t.Type]
+	if !ok {
+		return fmt.Errorf("target %q has unknown type %q (expected one of %s)", name, t.Type, strings.Join(BuiltinTargets, ", "))
+	}
+	for field, value := range t.settings() {
+		required, applies := fields[field]
+		if value != "" && !applies {
+			return fmt.Errorf("target %q: %s does not apply to type %s", name, field, t.Type)
+		}
+		if value == "" && required {
+			return fmt.Errorf("target %q: %s is required for type %s", name, field, t.Type)
+		}
+	}
+	return nil
+}
+
+func (t TargetDefinition) settings() map[string]string {
+	return map[string]string{
+		"repo":         t.Repo,
+		"commit_rules": t.CommitRules,
+		"url":          t.URL,
+		"email":        t.Email,
+		"project":      t.Project,
+		"issue_type":   t.IssueType,
+		"token_env":    t.TokenEnv,
+		"dir":          t.Dir,
+		"file":         t.File,
+		"command":      t.Command,
+		"context":      t.Context,
+	}
+}
+
+// ResolveTarget returns the type of the named target and the target
+// settings to build it with. "" names the clipboard.
+func (c Config) ResolveTarget(name string) (string, TargetConfig, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return "clipboard", c.Target, nil
+	}
+	if slices.Contains(BuiltinTargets, name) {
+		return name, c.Target, nil
+	}
+	target, ok := c.Targets[name]
+	if !ok {
+		return "", TargetConfig{}, fmt.Errorf("unknown output target %q", name)
+	}
+	return target.Type, target.apply(c.Target), nil
+}
+
+func (t TargetDefinition) apply(base TargetConfig) TargetConfig {
+	override := func(field *string, value string) {
+		if value != "" {
+			*field = value
+		}
+	}
+	switch t.Type {
+	case "git-commit":
+		override(&base.GitCommitRepo, t.Repo)
+		override(&base.GitCommitRules, t.CommitRules)
+	case "github-issue":
+		override(&base.GitHubRepo, t.Repo)
+		override(&base.GitHubAPIURL, t.URL)
+		if t.TokenEnv != "" {
+			base.GitHubToken = strings.TrimSpace(os.Getenv(t.TokenEnv))
+		}
+	case "jira-issue":
+		override(&base.JiraURL, t.URL)
+		override(&base.JiraProject, t.Project)
+		override(&base.JiraEmail, t.Email)
+		override(&base.JiraIssueType, t.IssueType)
+		if t.TokenEnv != "" {
+			base.JiraToken = strings.TrimSpace(os.Getenv(t.TokenEnv))
+		}
+	case "reminder":
+		override(&base.ReminderDir, t.Dir)
+		if t.Command != "" {
+			base.ReminderCommand = strings.Fields(t.Command)
+		}
+	case "todo":
+		override(&base.TodoFile, t.File)
+		override(&base.TaskProject, t.Project)
+		override(&base.TaskContext, t.Context)
+	case "taskwarrior":
+		override(&base.TaskCommand, t.Command)
+		override(&base.TaskProject, t.Project)
+		override(&base.TaskContext, t.Context)
+	}
+	return base
+}
+
+// ListTargets returns the built-in targets followed by the defined ones,
+// marking active as the one in use.
+func (c Config) ListTargets(active string) []domain.OutputTargetInfo {
+	active = strings.ToLower(strings.TrimSpace(active))
+	if active == "" {
+		active = "clipboard"
+	}
+	targets := make([]domain.OutputTargetInfo, 0, len(BuiltinTargets)+len(c.Targets))
+	for _, name := range BuiltinTargets {
+		targets = append(targets, domain.OutputTargetInfo{Name: name, Type: name, BuiltIn: true, Active: name == active})
+	}
+	names := make([]string, 0, len(c.Targets))
+	for name := range c.Targets {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		target := c.Targets[name]
+		targets = append(targets, domain.OutputTargetInfo{
+			Name:        name,
+			Type:        target.Type,
+			Description: target.Description,
+			Active:      name == active,
+		})
+	}
+	return targets
+}
@@ -0,0 +1,88 @@
+package daemon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"coldmic/internal/auth"
+	"coldmic/internal/domain"
+)
+
+func TestAPIOpenUntilFirstTokenExists(t *testing.T) {
+	t.Parallel()
+
+	api := NewAPI(&fakeService{})
+	api.SetTokens(auth.NewFileTokenStore(filepath.Join(t.TempDir(), "tokens.json")))
+
+	rec := httptest.NewRecorder()
+	api.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/session/status", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected open API without tokens, got %d", rec.Code)
+	}
+}
+
+func TestAPIEnforcesTokenScopes(t *testing.T) {
+	t.Parallel()
+
+	store := auth.NewFileTokenStore(filepath.Join(t.TempDir(), "tokens.json"))
+	statusToken, _, err := store.Create(context.Background(), "status bar", domain.TokenScopeStatus)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	fullToken, _, err := store.Create(context.Background(), "hotkey", domain.TokenScopeFull)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+
+	svc := &fakeService{status: domain.Status{State: domain.SessionStateIdle}}
+	api := NewAPI(svc)
+	api.SetTokens(store)
+	api.SetHistory(&fakeHistory{entries: []domain.HistoryEntry{{ID: "session-1"}}})
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{"missing token", http.MethodGet, "/v1/session/status", "", http.StatusUnauthorized},
+		{"unknown token", http.MethodGet, "/v1/session/status", "cm_nope", http.StatusUnauthorized},
+		{"status reads status", http.MethodGet, "/v1/session/status", statusToken, http.StatusOK},
+		{"status cannot read history", http.MethodGet, "/v1/history", statusToken, http.StatusForbidden},
+		{"status cannot read transcript", http.MethodGet, "/v1/session/transcript/latest", statusToken, http.StatusForbidden},
+		{"status cannot start", http.MethodPost, "/v1/session/start", statusToken, http.StatusForbidden},
+		{"full reads history", http.MethodGet, "/v1/history", fullToken, http.StatusOK},
+		{"full starts", http.MethodPost, "/v1/session/start", fullToken, http.StatusAccepted},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		if tc.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+		rec := httptest.NewRecorder()
+		api.Handler().ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Fatalf("%s: expected %d, got %d", tc.name, tc.want, rec.Code)
+		}
+	}
+	if svc.startCalls != 1 {
+		t.Fatalf("expected only the full token to start a session, got %d starts", svc.startCalls)
+	}
+}
+
+type fakeHistory struct {
+	entries []domain.HistoryEntry
+}
+
+func (f *fakeHistory) Append(_ context.Context, entry domain.HistoryEntry) error {
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+func (f *fakeHistory) List(_ context.Context) ([]domain.HistoryEntry, error) {
+	return f.entries, nil
+}
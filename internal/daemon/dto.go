@@ -27,3 +27,56 @@ type LatestTranscriptResponse struct {
 	Captured time.Time         `json:"captured"`
 	Result   domain.StopResult `json:"result"`
 }
+
+type HistoryResponse struct {
+	OK      bool                  `json:"ok"`
+	Entries []domain.HistoryEntry `json:"entries"`
+}
+
+type TargetsResponse struct {
+	OK      bool                      `json:"ok"`
+	Targets []domain.OutputTargetInfo `json:"targets"`
+}
+
+type SelectTargetRequest struct {
+	Name string `json:"name"`
+}
+
+type HotkeysResponse struct {
+	OK      bool            `json:"ok"`
+	Hotkeys []domain.Hotkey `json:"hotkeys"`
+}
+
+type TriggerHotkeyRequest struct {
+	Name string `json:"name"`
+}
+
+type TriggerHotkeyResponse struct {
+	OK     bool                `json:"ok"`
+	Result domain.HotkeyResult `json:"result"`
+}
+
+// StreamDeckState is the key state of a Stream Deck action. State indexes
+// the states of a multi-state action in the plugin manifest.
+type StreamDeckState struct {
+	Event  string        `json:"event"`
+	State  int           `json:"state"`
+	Title  string        `json:"title"`
+	Target string        `json:"target,omitempty"`
+	Status domain.Status `json:"status"`
+}
+
+// StreamDeckAction is a key press sent by a Stream Deck plugin. Target
+// applies to start, toggle and target; Hotkey names the hotkey to trigger.
+type StreamDeckAction struct {
+	Action string `json:"action"`
+	Target string `json:"target,omitempty"`
+	Hotkey string `json:"hotkey,omitempty"`
+}
+
+// StreamDeckError reports a failed action on the Stream Deck socket.
+type StreamDeckError struct {
+	Event  string `json:"event"`
+	Action string `json:"action"`
+	Error  string `json:"error"`
+}
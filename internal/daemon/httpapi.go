@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	"coldmic/internal/domain"
+	"coldmic/internal/eventschema"
+	"coldmic/internal/ports"
 )
 
 const (
@@ -16,22 +19,93 @@ const (
 
 type API struct {
 	service SessionService
+	tokens  ports.AccessTokenStore
+	history ports.HistoryStore
+	targets TargetSelector
+	hotkeys HotkeyTrigger
 }
 
 func NewAPI(service SessionService) *API {
 	return &API{service: service}
 }
 
+// SetTokens enables scoped bearer-token checks once the store holds any token.
+func (a *API) SetTokens(tokens ports.AccessTokenStore) {
+	a.tokens = tokens
+}
+
+// SetHistory exposes transcript history under the history scope.
+func (a *API) SetHistory(history ports.HistoryStore) {
+	a.history = history
+}
+
+// SetTargets exposes output targets: listing under the status scope and
+// switching under the full scope.
+func (a *API) SetTargets(targets TargetSelector) {
+	a.targets = targets
+}
+
+// SetHotkeys exposes configured hotkeys: listing under the status scope and
+// triggering under the full scope.
+func (a *API) SetHotkeys(hotkeys HotkeyTrigger) {
+	a.hotkeys = hotkeys
+}
+
 func (a *API) Handler() http.Handler {
 	mux := http.NewServeMux()
-	mux.HandleFunc("/v1/session/start", a.handleStart)
-	mux.HandleFunc("/v1/session/stop", a.handleStop)
-	mux.HandleFunc("/v1/session/abort", a.handleAbort)
-	mux.HandleFunc("/v1/session/status", a.handleStatus)
-	mux.HandleFunc("/v1/session/transcript/latest", a.handleLatestTranscript)
+	mux.HandleFunc("/v1/session/start", a.require(domain.TokenScopeFull, a.handleStart))
+	mux.HandleFunc("/v1/session/stop", a.require(domain.TokenScopeFull, a.handleStop))
+	mux.HandleFunc("/v1/session/abort", a.require(domain.TokenScopeFull, a.handleAbort))
+	mux.HandleFunc("/v1/session/status", a.require(domain.TokenScopeStatus, a.handleStatus))
+	mux.HandleFunc("/v1/session/transcript/latest", a.require(domain.TokenScopeHistory, a.handleLatestTranscript))
+	mux.HandleFunc("/v1/history", a.require(domain.TokenScopeHistory, a.handleHistory))
+	mux.HandleFunc("/v1/targets", a.require(domain.TokenScopeStatus, a.handleTargets))
+	mux.HandleFunc("/v1/targets/select", a.require(domain.TokenScopeFull, a.handleSelectTarget))
+	mux.HandleFunc("/v1/hotkeys", a.require(domain.TokenScopeStatus, a.handleHotkeys))
+	mux.HandleFunc("/v1/hotkeys/trigger", a.require(domain.TokenScopeFull, a.handleTriggerHotkey))
+	mux.HandleFunc("/v1/streamdeck/state", a.require(domain.TokenScopeStatus, a.handleStreamDeckState))
+	mux.HandleFunc("/v1/streamdeck/action", a.require(domain.TokenScopeFull, a.handleStreamDeckAction))
+	mux.HandleFunc("/v1/streamdeck/socket", a.require(domain.TokenScopeFull, a.handleStreamDeckSocket))
+	mux.HandleFunc("/v1/schema", a.require(domain.TokenScopeStatus, a.handleSchema))
 	return mux
 }
 
+// require rejects requests whose bearer token lacks scope. Without a token
+// store, or before any token has been created, the API stays open.
+func (a *API) require(scope domain.TokenScope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if a.tokens == nil {
+			next(w, r)
+			return
+		}
+		enforced, err := a.tokens.Enforced(r.Context())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if !enforced {
+			next(w, r)
+			return
+		}
+
+		secret, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || secret == "" {
+			writeError(w, http.StatusUnauthorized, "missing_token")
+			return
+		}
+		token, err := a.tokens.Verify(r.Context(), strings.TrimSpace(secret))
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, "invalid_token")
+			return
+		}
+		if !token.Scope.Allows(scope) {
+			writeError(w, http.StatusForbidden, "insufficient_scope")
+			return
+		}
+		next(w, r)
+	}
+}
+
 func (a *API) handleStart(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed")
@@ -119,6 +193,118 @@ func (a *API) handleLatestTranscript(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (a *API) handleHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+	if a.history == nil {
+		writeError(w, http.StatusNotFound, "history_unavailable")
+		return
+	}
+
+	entries, err := a.history.List(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if entries == nil {
+		entries = []domain.HistoryEntry{}
+	}
+	writeJSON(w, http.StatusOK, HistoryResponse{OK: true, Entries: entries})
+}
+
+func (a *API) handleTargets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+	if a.targets == nil {
+		writeError(w, http.StatusNotFound, "targets_unavailable")
+		return
+	}
+	writeJSON(w, http.StatusOK, TargetsResponse{OK: true, Targets: a.targets.List()})
+}
+
+// handleSchema serves the JSON schema of the event payloads and results.
+func (a *API) handleSchema(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(eventschema.Document())
+}
+
+func (a *API) handleSelectTarget(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+	if a.targets == nil {
+		writeError(w, http.StatusNotFound, "targets_unavailable")
+		return
+	}
+	var req SelectTargetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+	if _, err := a.targets.Select(req.Name); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, TargetsResponse{OK: true, Targets: a.targets.List()})
+}
+
+func (a *API) handleHotkeys(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+	if a.hotkeys == nil {
+		writeError(w, http.StatusNotFound, "hotkeys_unavailable")
+		return
+	}
+	hotkeys := a.hotkeys.List()
+	if hotkeys == nil {
+		hotkeys = []domain.Hotkey{}
+	}
+	writeJSON(w, http.StatusOK, HotkeysResponse{OK: true, Hotkeys: hotkeys})
+}
+
+func (a *API) handleTriggerHotkey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+	if a.hotkeys == nil {
+		writeError(w, http.StatusNotFound, "hotkeys_unavailable")
+		return
+	}
+	var req TriggerHotkeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	// A session started by the hotkey must outlive this request.
+	result, err := a.hotkeys.Trigger(context.WithoutCancel(r.Context()), req.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrHotkeyNotFound), errors.Is(err, domain.ErrNoTranscriptAvailable):
+			writeError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, domain.ErrNoActiveSession):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, TriggerHotkeyResponse{OK: true, Result: result})
+}
+
 func writeError(w http.ResponseWriter, status int, message string) {
 	writeJSON(w, status, ErrorResponse{OK: false, Error: message})
 }
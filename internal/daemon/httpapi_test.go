@@ -4,12 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"coldmic/internal/domain"
+	"coldmic/internal/ports"
 )
 
 func TestAPIStart(t *testing.T) {
@@ -315,6 +318,129 @@ func TestAPILatestTranscriptSuccess(t *testing.T) {
 	}
 }
 
+func TestAPISelectTarget(t *testing.T) {
+	t.Parallel()
+	targets := &fakeTargets{names: []string{"clipboard", "work-jira"}, active: "clipboard"}
+	api := NewAPI(&fakeService{})
+	api.SetTargets(targets)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/targets/select", strings.NewReader(`{"name":"work-jira"}`))
+	rec := httptest.NewRecorder()
+	api.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected code: %d", rec.Code)
+	}
+	var resp TargetsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if len(resp.Targets) != 2 || !resp.Targets[1].Active {
+		t.Fatalf("expected work-jira to be active, got %+v", resp.Targets)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/targets/select", strings.NewReader(`{"name":"nope"}`))
+	rec = httptest.NewRecorder()
+	api.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest || targets.active != "work-jira" {
+		t.Fatalf("expected unknown target to be refused, got %d active=%s", rec.Code, targets.active)
+	}
+}
+
+func TestAPITargetsUnavailable(t *testing.T) {
+	t.Parallel()
+	api := NewAPI(&fakeService{})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/targets", nil)
+	rec := httptest.NewRecorder()
+	api.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("unexpected code: %d", rec.Code)
+	}
+}
+
+func TestAPITriggerHotkey(t *testing.T) {
+	t.Parallel()
+	hotkeys := &fakeHotkeys{hotkeys: []domain.Hotkey{{Name: "email", Keys: "Super+E", Action: domain.HotkeyStart, Target: "email"}}}
+	api := NewAPI(&fakeService{})
+	api.SetHotkeys(hotkeys)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/hotkeys", nil)
+	rec := httptest.NewRecorder()
+	api.Handler().ServeHTTP(rec, req)
+	var list HotkeysResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if rec.Code != http.StatusOK || len(list.Hotkeys) != 1 || list.Hotkeys[0].Target != "email" {
+		t.Fatalf("unexpected hotkeys: %d %+v", rec.Code, list)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/hotkeys/trigger", strings.NewReader(`{"name":"email"}`))
+	rec = httptest.NewRecorder()
+	api.Handler().ServeHTTP(rec, req)
+	var resp TriggerHotkeyResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if rec.Code != http.StatusOK || resp.Result.Action != domain.HotkeyStart || hotkeys.triggered != "email" {
+		t.Fatalf("unexpected trigger response: %d %+v", rec.Code, resp)
+	}
+	if hotkeys.ctx.Done() != nil {
+		t.Fatalf("expected hotkey context to outlive the request")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/hotkeys/trigger", strings.NewReader(`{"name":"nope"}`))
+	rec = httptest.NewRecorder()
+	api.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected unknown hotkey to be 404, got %d", rec.Code)
+	}
+}
+
+type fakeHotkeys struct {
+	hotkeys   []domain.Hotkey
+	triggered string
+	ctx       context.Context
+}
+
+func (f *fakeHotkeys) List() []domain.Hotkey {
+	return f.hotkeys
+}
+
+func (f *fakeHotkeys) Trigger(ctx context.Context, name string) (domain.HotkeyResult, error) {
+	for _, hotkey := range f.hotkeys {
+		if hotkey.Name == name {
+			f.triggered = name
+			f.ctx = ctx
+			return domain.HotkeyResult{Hotkey: name, Action: hotkey.Action, Status: domain.Status{State: domain.SessionStateRecording, Active: true}}, nil
+		}
+	}
+	return domain.HotkeyResult{}, fmt.Errorf("%w: %q", domain.ErrHotkeyNotFound, name)
+}
+
+type fakeTargets struct {
+	names  []string
+	active string
+}
+
+func (f *fakeTargets) List() []domain.OutputTargetInfo {
+	var targets []domain.OutputTargetInfo
+	for _, name := range f.names {
+		targets = append(targets, domain.OutputTargetInfo{Name: name, Type: name, Active: name == f.active})
+	}
+	return targets
+}
+
+func (f *fakeTargets) Select(name string) (ports.OutputTarget, error) {
+	for _, known := range f.names {
+		if known == name {
+			f.active = name
+			return nil, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown output target %q", name)
+}
+
 type fakeService struct {
 	startCalls int
 	abortCalls int
@@ -359,3 +485,21 @@ func (f *fakeService) LastTranscript() (domain.LatestTranscript, error) {
 	}
 	return f.latest, nil
 }
+
+func TestAPISchema(t *testing.T) {
+	t.Parallel()
+	api := NewAPI(&fakeService{})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/schema", nil)
+	rec := httptest.NewRecorder()
+	api.Handler().ServeHTTP(rec, req)
+	var schema struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &schema); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if rec.Code != http.StatusOK || schema.Version != domain.EventSchemaVersion {
+		t.Fatalf("unexpected schema response: %d %s", rec.Code, rec.Body.String())
+	}
+}
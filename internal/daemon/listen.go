@@ -0,0 +1,69 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const unixAddrPrefix = "unix:"
+
+// DefaultSocketPath is the per-user control socket the daemon listens on and
+// the CLI dials by default: coldmic/coldmicd.sock under $XDG_RUNTIME_DIR, or
+// under a directory named for the user in the temp dir when that is unset.
+func DefaultSocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "coldmic", "coldmicd.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("coldmic-%d", os.Getuid()), "coldmicd.sock")
+}
+
+// Listen opens the daemon control listener. "unix:/path/to.sock" creates a
+// socket only the current user can connect to; TCP addresses must be loopback
+// so the control API is never exposed to other machines.
+func Listen(addr string) (net.Listener, error) {
+	if path, ok := strings.CutPrefix(addr, unixAddrPrefix); ok {
+		return listenUnix(strings.TrimPrefix(path, "//"))
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid daemon address %q: %w", addr, err)
+	}
+	if !isLoopbackHost(host) {
+		return nil, fmt.Errorf("refusing to bind daemon to non-loopback address %q; use 127.0.0.1 or a unix: socket", addr)
+	}
+	return net.Listen("tcp", addr)
+}
+
+func listenUnix(path string) (net.Listener, error) {
+	if path == "" {
+		return nil, errors.New("unix socket path is empty")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("failed to restrict socket permissions: %w", err)
+	}
+	return listener, nil
+}
+
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,61 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestListenRejectsNonLoopbackTCP(t *testing.T) {
+	t.Parallel()
+
+	if _, err := Listen("0.0.0.0:0"); err == nil {
+		t.Fatalf("expected non-loopback bind to be refused")
+	}
+	listener, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("loopback listen failed: %v", err)
+	}
+	_ = listener.Close()
+}
+
+func TestListenUnixSocketIsPrivate(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "run", "coldmicd.sock")
+	listener, err := Listen("unix:" + path)
+	if err != nil {
+		t.Fatalf("unix listen failed: %v", err)
+	}
+	defer listener.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat failed: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected socket mode 0600, got %o", info.Mode().Perm())
+	}
+	dirInfo, err := os.Stat(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("stat failed: %v", err)
+	}
+	if dirInfo.Mode().Perm() != 0o700 {
+		t.Fatalf("expected socket directory mode 0700, got %o", dirInfo.Mode().Perm())
+	}
+}
+
+func TestDefaultSocketPathUsesRuntimeDir(t *testing.T) {
+	runtime := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtime)
+
+	path := DefaultSocketPath()
+	if path != filepath.Join(runtime, "coldmic", "coldmicd.sock") {
+		t.Fatalf("unexpected default socket %q", path)
+	}
+	listener, err := Listen("unix:" + path)
+	if err != nil {
+		t.Fatalf("listen on default socket failed: %v", err)
+	}
+	_ = listener.Close()
+}
@@ -1,6 +1,7 @@
 package daemon
 
 import (
+	"context"
 	"log"
 
 	"coldmic/internal/domain"
@@ -17,10 +18,42 @@ func (LoggingEventSink) PartialTranscript(text string) {
 	log.Printf("partial transcript=%q", text)
 }
 
-func (LoggingEventSink) FinalTranscript(raw string, transformed string, sessionID string) {
+func (LoggingEventSink) FinalTranscript(raw string, transformed string, sessionID string, warnings []domain.SessionWarning) {
 	log.Printf("final transcript session_id=%s raw=%q transformed=%q", sessionID, raw, transformed)
+	for _, warning := range warnings {
+		log.Printf("session warning session_id=%s code=%s count=%d message=%q", sessionID, warning.Code, warning.Count, warning.Message)
+	}
 }
 
 func (LoggingEventSink) SessionError(code domain.ErrorCode, detail string) {
 	log.Printf("session error code=%s detail=%q", code, detail)
 }
+
+func (LoggingEventSink) FileJobChanged(job domain.FileJob) {
+	log.Printf("file job id=%s state=%s source=%q output=%q error=%q", job.ID, job.State, job.SourcePath, job.OutputPath, job.Error)
+}
+
+func (LoggingEventSink) Caption(caption domain.Caption) {
+	log.Printf("caption session_id=%s kind=%s language=%s text=%q", caption.SessionID, caption.Kind, caption.Language, caption.Text)
+}
+
+func (LoggingEventSink) NetworkChanged(state domain.NetworkState) {
+	log.Printf("network online=%t metered=%t using_fallback=%t", state.Online, state.Metered, state.UsingFallback)
+}
+
+func (LoggingEventSink) IssueDraftReady(draft domain.IssueDraft) {
+	log.Printf("issue draft tracker=%s project=%s title=%q", draft.Tracker, draft.Project, draft.Title)
+}
+
+func (LoggingEventSink) IssueCreated(issue domain.Issue) {
+	log.Printf("issue created tracker=%s key=%s url=%s", issue.Tracker, issue.Key, issue.URL)
+}
+
+func (LoggingEventSink) TranscriptCopied(_ context.Context, event domain.CopyEvent) error {
+	log.Printf("transcript copied session_id=%s target=%s form=%s len=%d", event.SessionID, event.Target, event.Form, len(event.Text))
+	return nil
+}
+
+func (LoggingEventSink) LowConfidence(event domain.LowConfidence) {
+	log.Printf("low confidence session_id=%s confidence=%.2f threshold=%.2f", event.SessionID, event.Confidence, event.Threshold)
+}
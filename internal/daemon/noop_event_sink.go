@@ -7,5 +7,6 @@ type NoopEventSink struct{}
 
 func (NoopEventSink) SessionStateChanged(_ domain.SessionState, _ domain.SessionStateReason) {}
 func (NoopEventSink) PartialTranscript(_ string)                                             {}
-func (NoopEventSink) FinalTranscript(_, _, _ string)                                         {}
+func (NoopEventSink) FinalTranscript(_, _, _ string, _ []domain.SessionWarning)              {}
 func (NoopEventSink) SessionError(_ domain.ErrorCode, _ string)                              {}
+func (NoopEventSink) FileJobChanged(_ domain.FileJob)                                        {}
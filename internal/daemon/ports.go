@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"coldmic/internal/domain"
+	"coldmic/internal/ports"
 )
 
 // SessionService is the control surface required by daemon transports.
@@ -14,3 +15,15 @@ type SessionService interface {
 	Status() domain.Status
 	LastTranscript() (domain.LatestTranscript, error)
 }
+
+// TargetSelector lists output targets and switches between them by name.
+type TargetSelector interface {
+	List() []domain.OutputTargetInfo
+	Select(name string) (ports.OutputTarget, error)
+}
+
+// HotkeyTrigger lists configured hotkeys and runs them by name.
+type HotkeyTrigger interface {
+	List() []domain.Hotkey
+	Trigger(ctx context.Context, name string) (domain.HotkeyResult, error)
+}
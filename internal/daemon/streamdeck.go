@@ -0,0 +1,194 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"coldmic/internal/domain"
+)
+
+// Stream Deck key states, in the order of the states of the plugin's
+// multi-state action.
+const (
+	deckStateIdle      = 0
+	deckStateRecording = 1
+	deckStateBusy      = 2
+)
+
+// deckPollInterval is how often the Stream Deck socket checks for a state
+// change to push.
+const deckPollInterval = 200 * time.Millisecond
+
+const deckWriteTimeout = 5 * time.Second
+
+// deckUpgrader keeps gorilla's same-origin check, so web pages cannot drive
+// the daemon; Stream Deck plugins send no Origin header.
+var deckUpgrader = websocket.Upgrader{}
+
+func (a *API) deckState() StreamDeckState {
+	status := a.service.Status()
+	state := StreamDeckState{Event: "state", State: deckStateIdle, Title: "Ready", Status: status}
+	switch status.State {
+	case domain.SessionStateRecording:
+		state.State, state.Title = deckStateRecording, "Recording"
+	case domain.SessionStateStopping:
+		state.State, state.Title = deckStateBusy, "Transcribing"
+	}
+	if a.targets != nil {
+		for _, target := range a.targets.List() {
+			if target.Active {
+				state.Target = target.Name
+			}
+		}
+	}
+	return state
+}
+
+// runDeckAction performs a Stream Deck key press. Sessions it starts outlive
+// ctx.
+func (a *API) runDeckAction(ctx context.Context, action StreamDeckAction) error {
+	switch action.Action {
+	case "toggle":
+		if a.service.Status().Active {
+			return a.deckStop(ctx)
+		}
+		return a.deckStart(ctx, action.Target)
+	case "start":
+		return a.deckStart(ctx, action.Target)
+	case "stop":
+		return a.deckStop(ctx)
+	case "abort":
+		return a.service.Abort()
+	case "target":
+		return a.deckSelect(action.Target)
+	case "hotkey":
+		if a.hotkeys == nil {
+			return errors.New("hotkeys_unavailable")
+		}
+		_, err := a.hotkeys.Trigger(context.WithoutCancel(ctx), action.Hotkey)
+		return err
+	}
+	return fmt.Errorf("unknown Stream Deck action %q", action.Action)
+}
+
+func (a *API) deckStart(ctx context.Context, target string) error {
+	if target != "" {
+		if err := a.deckSelect(target); err != nil {
+			return err
+		}
+	}
+	return a.service.Start(context.WithoutCancel(ctx))
+}
+
+func (a *API) deckStop(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 15*time.Second)
+	defer cancel()
+	_, err := a.service.Stop(ctx)
+	return err
+}
+
+func (a *API) deckSelect(target string) error {
+	if a.targets == nil {
+		return errors.New("targets_unavailable")
+	}
+	_, err := a.targets.Select(target)
+	return err
+}
+
+func (a *API) handleStreamDeckState(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+	writeJSON(w, http.StatusOK, a.deckState())
+}
+
+func (a *API) handleStreamDeckAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+	var action StreamDeckAction
+	if err := json.NewDecoder(r.Body).Decode(&action); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+	if err := a.runDeckAction(r.Context(), action); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNoActiveSession):
+			writeError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, domain.ErrHotkeyNotFound):
+			writeError(w, http.StatusNotFound, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, a.deckState())
+}
+
+// handleStreamDeckSocket pushes the key state whenever it changes and runs
+// the actions the plugin sends, reporting failures as error events.
+func (a *API) handleStreamDeckSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := deckUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	var (
+		writeMu sync.Mutex
+		last    *StreamDeckState
+	)
+	write := func(payload any) error {
+		_ = conn.SetWriteDeadline(time.Now().Add(deckWriteTimeout))
+		return conn.WriteJSON(payload)
+	}
+	publish := func() error {
+		state := a.deckState()
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		if last != nil && *last == state {
+			return nil
+		}
+		last = &state
+		return write(state)
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			var action StreamDeckAction
+			if err := conn.ReadJSON(&action); err != nil {
+				return
+			}
+			if err := a.runDeckAction(r.Context(), action); err != nil {
+				writeMu.Lock()
+				_ = write(StreamDeckError{Event: "error", Action: action.Action, Error: err.Error()})
+				writeMu.Unlock()
+			}
+			_ = publish()
+		}
+	}()
+
+	ticker := time.NewTicker(deckPollInterval)
+	defer ticker.Stop()
+	for {
+		if err := publish(); err != nil {
+			return
+		}
+		select {
+		case <-closed:
+			return
+		case <-ticker.C:
+		}
+	}
+}
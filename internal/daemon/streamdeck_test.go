@@ -0,0 +1,140 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"coldmic/internal/domain"
+)
+
+func TestAPIStreamDeckAction(t *testing.T) {
+	t.Parallel()
+	svc := &fakeService{}
+	targets := &fakeTargets{names: []string{"clipboard", "email"}, active: "clipboard"}
+	api := NewAPI(svc)
+	api.SetTargets(targets)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/streamdeck/action", strings.NewReader(`{"action":"toggle","target":"email"}`))
+	rec := httptest.NewRecorder()
+	api.Handler().ServeHTTP(rec, req)
+	var state StreamDeckState
+	if err := json.Unmarshal(rec.Body.Bytes(), &state); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if rec.Code != http.StatusOK || svc.startCalls != 1 || state.Target != "email" {
+		t.Fatalf("expected toggle to start with the email target, got %d %+v", rec.Code, state)
+	}
+	if svc.startCtx.Done() != nil {
+		t.Fatalf("expected the session to outlive the request")
+	}
+
+	svc.status = domain.Status{State: domain.SessionStateRecording, Active: true}
+	req = httptest.NewRequest(http.MethodGet, "/v1/streamdeck/state", nil)
+	rec = httptest.NewRecorder()
+	api.Handler().ServeHTTP(rec, req)
+	if err := json.Unmarshal(rec.Body.Bytes(), &state); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if state.State != deckStateRecording || state.Title != "Recording" {
+		t.Fatalf("unexpected state: %+v", state)
+	}
+
+	svc.status = domain.Status{State: domain.SessionStateIdle}
+	svc.stopErr = domain.ErrNoActiveSession
+	req = httptest.NewRequest(http.MethodPost, "/v1/streamdeck/action", strings.NewReader(`{"action":"stop"}`))
+	rec = httptest.NewRecorder()
+	api.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected stop without a session to conflict, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/streamdeck/action", strings.NewReader(`{"action":"dance"}`))
+	rec = httptest.NewRecorder()
+	api.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected unknown action to fail, got %d", rec.Code)
+	}
+}
+
+func TestAPIStreamDeckSocketPushesStateChanges(t *testing.T) {
+	t.Parallel()
+	svc := &deckService{}
+	server := httptest.NewServer(NewAPI(svc).Handler())
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/v1/streamdeck/socket", nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	var state StreamDeckState
+	if err := conn.ReadJSON(&state); err != nil || state.State != deckStateIdle {
+		t.Fatalf("expected the idle state on connect, got %+v %v", state, err)
+	}
+	if err := conn.WriteJSON(StreamDeckAction{Action: "toggle"}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := conn.ReadJSON(&state); err != nil || state.State != deckStateRecording {
+		t.Fatalf("expected the recording state after toggle, got %+v %v", state, err)
+	}
+
+	if err := conn.WriteJSON(StreamDeckAction{Action: "hotkey", Hotkey: "email"}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	var failure StreamDeckError
+	if err := conn.ReadJSON(&failure); err != nil || failure.Event != "error" || failure.Action != "hotkey" {
+		t.Fatalf("expected an error event without hotkeys, got %+v %v", failure, err)
+	}
+
+	svc.set(domain.Status{State: domain.SessionStateStopping, Active: true})
+	if err := conn.ReadJSON(&state); err != nil || state.State != deckStateBusy {
+		t.Fatalf("expected the busy state to be pushed, got %+v %v", state, err)
+	}
+}
+
+// deckService is a SessionService safe for the socket's concurrent reads.
+type deckService struct {
+	mu     sync.Mutex
+	status domain.Status
+}
+
+func (s *deckService) set(status domain.Status) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+func (s *deckService) Start(context.Context) error {
+	s.set(domain.Status{State: domain.SessionStateRecording, Active: true})
+	return nil
+}
+
+func (s *deckService) Stop(context.Context) (domain.StopResult, error) {
+	s.set(domain.Status{State: domain.SessionStateIdle})
+	return domain.StopResult{}, nil
+}
+
+func (s *deckService) Abort() error {
+	s.set(domain.Status{State: domain.SessionStateIdle})
+	return nil
+}
+
+func (s *deckService) Status() domain.Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+func (s *deckService) LastTranscript() (domain.LatestTranscript, error) {
+	return domain.LatestTranscript{}, domain.ErrNoTranscriptAvailable
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// TokenScope limits what a control API token may do.
+type TokenScope string
+
+const (
+	// TokenScopeStatus can only read the recording state.
+	TokenScopeStatus TokenScope = "status"
+	// TokenScopeHistory can read status, the latest transcript, and history.
+	TokenScopeHistory TokenScope = "history"
+	// TokenScopeFull can additionally start, stop, and abort recordings.
+	TokenScopeFull TokenScope = "full"
+)
+
+var ErrInvalidToken = errors.New("invalid access token")
+
+// Valid reports whether s is a known scope.
+func (s TokenScope) Valid() bool {
+	switch s {
+	case TokenScopeStatus, TokenScopeHistory, TokenScopeFull:
+		return true
+	default:
+		return false
+	}
+}
+
+// Allows reports whether a token with scope s satisfies required.
+func (s TokenScope) Allows(required TokenScope) bool {
+	switch s {
+	case TokenScopeFull:
+		return true
+	case TokenScopeHistory:
+		return required == TokenScopeHistory || required == TokenScopeStatus
+	case TokenScopeStatus:
+		return required == TokenScopeStatus
+	default:
+		return false
+	}
+}
+
+// AccessToken describes an issued token; the secret itself is never stored.
+type AccessToken struct {
+	ID        string     `json:"id"`
+	Label     string     `json:"label,omitempty"`
+	Scope     TokenScope `json:"scope"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
@@ -5,4 +5,12 @@ import "errors"
 var (
 	ErrNoActiveSession       = errors.New("no active recording session")
 	ErrNoTranscriptAvailable = errors.New("no transcript available")
+	ErrFileJobNotFound       = errors.New("file job not found")
+	ErrNoIssueDraft          = errors.New("no issue draft is pending")
+	ErrNoRetryAudio          = errors.New("no low-confidence session to retry")
+	ErrSessionInProgress     = errors.New("a recording session is in progress")
+	ErrCorrectionNotFound    = errors.New("text to correct not found in the last transcript")
+	ErrModelNotFound         = errors.New("model not found in the catalog")
+	ErrHotkeyNotFound        = errors.New("hotkey not found")
+	ErrNoSessionLog          = errors.New("no session has been recorded")
 )
@@ -0,0 +1,40 @@
+package domain
+
+// EventSchemaVersion is the version of the published JSON schema for the
+// session, partial, final and error event payloads and StopResult. Fields
+// may be added within a version; removing, renaming or retyping a field
+// requires a new version.
+const EventSchemaVersion = 1
+
+// SessionEvent is the payload of the coldmic:session event.
+type SessionEvent struct {
+	SchemaVersion int                `json:"schemaVersion"`
+	State         SessionState       `json:"state"`
+	Reason        SessionStateReason `json:"reason"`
+	Message       string             `json:"message"`
+}
+
+// PartialEvent is the payload of the coldmic:partial event.
+type PartialEvent struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Text          string `json:"text"`
+}
+
+// FinalEvent is the payload of the coldmic:final event. Raw is the provider
+// transcript and Transformed the text after formatting for the target.
+type FinalEvent struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Raw           string `json:"raw"`
+	Transformed   string `json:"transformed"`
+	SessionID     string `json:"sessionId"`
+	// Warnings are the non-fatal issues of the session, as in StopResult.
+	Warnings []SessionWarning `json:"warnings,omitempty"`
+}
+
+// ErrorEvent is the payload of the coldmic:error event.
+type ErrorEvent struct {
+	SchemaVersion int       `json:"schemaVersion"`
+	Code          ErrorCode `json:"code"`
+	Message       string    `json:"message"`
+	Detail        string    `json:"detail"`
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// FileJobState models the lifecycle of a queued file transcription.
+type FileJobState string
+
+const (
+	FileJobStateQueued     FileJobState = "queued"
+	FileJobStateProcessing FileJobState = "processing"
+	FileJobStateDone       FileJobState = "done"
+	FileJobStateFailed     FileJobState = "failed"
+	FileJobStateCanceled   FileJobState = "canceled"
+)
+
+// Finished reports whether the job has reached a terminal state.
+func (s FileJobState) Finished() bool {
+	return s == FileJobStateDone || s == FileJobStateFailed || s == FileJobStateCanceled
+}
+
+// FileJob reports the progress of a single file transcription.
+type FileJob struct {
+	ID         string       `json:"id"`
+	SourcePath string       `json:"sourcePath"`
+	OutputPath string       `json:"outputPath,omitempty"`
+	State      FileJobState `json:"state"`
+	Error      string       `json:"error,omitempty"`
+	UpdatedAt  time.Time    `json:"updatedAt"`
+}
+
+// FileTranscript is the processed output of a file transcription.
+type FileTranscript struct {
+	SourcePath            string `json:"sourcePath"`
+	RawTranscript         string `json:"rawTranscript"`
+	FinalTranscript       string `json:"finalTranscript"`
+	TimestampedTranscript string `json:"timestampedTranscript,omitempty"`
+}
+
+// CachedTranscript is the provider output for a file before rules are applied.
+type CachedTranscript struct {
+	RawTranscript string            `json:"rawTranscript"`
+	Segments      []DialogueSegment `json:"segments,omitempty"`
+}
@@ -0,0 +1,36 @@
+package domain
+
+// FormOutput selects how a filled form is handed to the clipboard.
+type FormOutput string
+
+const (
+	// FormOutputJSON copies a JSON object keyed by field key.
+	FormOutputJSON FormOutput = "json"
+	// FormOutputKeyValue copies "Label: value" lines.
+	FormOutputKeyValue FormOutput = "kv"
+	// FormOutputEntries copies each value as its own clipboard entry.
+	FormOutputEntries FormOutput = "entries"
+)
+
+// FormSchema describes the labeled fields of a form-filling target.
+type FormSchema struct {
+	Name   string      `json:"name"`
+	Output FormOutput  `json:"output,omitempty"`
+	Fields []FormField `json:"fields"`
+}
+
+// FormField is one dictated field. It is recognized by its label or any
+// alias followed by a spoken or typed colon.
+type FormField struct {
+	Key      string   `json:"key"`
+	Label    string   `json:"label,omitempty"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Required bool     `json:"required,omitempty"`
+}
+
+// FormValue is a filled field in schema order.
+type FormValue struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// HistorySource identifies which pipeline produced a history entry.
+type HistorySource string
+
+const (
+	HistorySourceSession HistorySource = "session"
+	HistorySourceFile    HistorySource = "file"
+	HistorySourceURL     HistorySource = "url"
+)
+
+// HistoryEntry is one persisted transcript. RawTranscript is the provider's
+// text, FinalTranscript the text after rules, and EditedTranscript the text
+// after a later edit by the user, if any. When rules are re-applied,
+// OriginalTranscript keeps the rules output the entry was created with.
+type HistoryEntry struct {
+	ID                 string        `json:"id"`
+	Source             HistorySource `json:"source"`
+	SourcePath         string        `json:"sourcePath,omitempty"`
+	RawTranscript      string        `json:"rawTranscript"`
+	FinalTranscript    string        `json:"finalTranscript"`
+	OriginalTranscript string        `json:"originalTranscript,omitempty"`
+	EditedTranscript   string        `json:"editedTranscript,omitempty"`
+	CreatedAt          time.Time     `json:"createdAt"`
+	EditedAt           time.Time     `json:"editedAt,omitempty"`
+	ReappliedAt        time.Time     `json:"reappliedAt,omitempty"`
+}
+
+// HistoryFilter selects history entries; empty fields match everything.
+type HistoryFilter struct {
+	IDs    []string      `json:"ids,omitempty"`
+	Source HistorySource `json:"source,omitempty"`
+	Since  time.Time     `json:"since,omitempty"`
+	Until  time.Time     `json:"until,omitempty"`
+	// Contains matches raw or final transcripts case-insensitively.
+	Contains string `json:"contains,omitempty"`
+}
+
+// Matches reports whether entry satisfies every set field of the filter.
+func (f HistoryFilter) Matches(entry HistoryEntry) bool {
+	if len(f.IDs) > 0 && !slices.Contains(f.IDs, entry.ID) {
+		return false
+	}
+	if f.Source != "" && entry.Source != f.Source {
+		return false
+	}
+	if !f.Since.IsZero() && entry.CreatedAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !entry.CreatedAt.Before(f.Until) {
+		return false
+	}
+	if needle := strings.ToLower(strings.TrimSpace(f.Contains)); needle != "" {
+		return strings.Contains(strings.ToLower(entry.RawTranscript), needle) ||
+			strings.Contains(strings.ToLower(entry.FinalTranscript), needle)
+	}
+	return true
+}
+
+// TranscriptStage names one version of a transcript in the pipeline.
+type TranscriptStage string
+
+const (
+	TranscriptStageRaw    TranscriptStage = "raw"
+	TranscriptStageRules  TranscriptStage = "rules"
+	TranscriptStageEdited TranscriptStage = "edited"
+)
+
+// DiffOpKind says whether a run of words was kept, added or removed.
+type DiffOpKind string
+
+const (
+	DiffOpEqual  DiffOpKind = "equal"
+	DiffOpInsert DiffOpKind = "insert"
+	DiffOpDelete DiffOpKind = "delete"
+)
+
+// DiffOp is a run of consecutive words with the same fate.
+type DiffOp struct {
+	Kind DiffOpKind `json:"kind"`
+	Text string     `json:"text"`
+}
+
+// StageDiff is what one pipeline stage changed, word by word.
+type StageDiff struct {
+	From TranscriptStage `json:"from"`
+	To   TranscriptStage `json:"to"`
+	Ops  []DiffOp        `json:"ops"`
+}
+
+// TranscriptComparison holds every stored version of a history transcript
+// and the diff between consecutive stages.
+type TranscriptComparison struct {
+	Entry  HistoryEntry `json:"entry"`
+	Stages []StageDiff  `json:"stages"`
+}
@@ -0,0 +1,37 @@
+package domain
+
+// HotkeyAction is what a hotkey does when triggered.
+type HotkeyAction string
+
+const (
+	HotkeyStart    HotkeyAction = "start"
+	HotkeyStop     HotkeyAction = "stop"
+	HotkeyToggle   HotkeyAction = "toggle"
+	HotkeyAbort    HotkeyAction = "abort"
+	HotkeyCopyLast HotkeyAction = "copy-last"
+	// HotkeySelect switches the output target without starting a session.
+	HotkeySelect HotkeyAction = "select"
+)
+
+// HotkeyActions lists the valid actions.
+var HotkeyActions = []HotkeyAction{HotkeyStart, HotkeyStop, HotkeyToggle, HotkeyAbort, HotkeyCopyLast, HotkeySelect}
+
+// Hotkey binds a key combination to an action. Keys documents the binding
+// for the compositor or window manager, which triggers the hotkey by Name.
+// Target, for start and toggle, is the output target of the session the
+// hotkey starts; select requires one.
+type Hotkey struct {
+	Name   string       `json:"name"`
+	Keys   string       `json:"keys,omitempty"`
+	Action HotkeyAction `json:"action"`
+	Target string       `json:"target,omitempty"`
+}
+
+// HotkeyResult reports what a triggered hotkey did. Result is set when it
+// stopped a session or copied the last transcript.
+type HotkeyResult struct {
+	Hotkey string       `json:"hotkey"`
+	Action HotkeyAction `json:"action"`
+	Status Status       `json:"status"`
+	Result *StopResult  `json:"result,omitempty"`
+}
@@ -0,0 +1,18 @@
+package domain
+
+// IssueDraft is a dictated issue waiting for confirmation before it is created.
+type IssueDraft struct {
+	Tracker string `json:"tracker"`
+	// Project is the GitHub owner/repo or Jira project key.
+	Project string `json:"project"`
+	Title   string `json:"title"`
+	Body    string `json:"body,omitempty"`
+}
+
+// Issue is an issue created in a tracker.
+type Issue struct {
+	Tracker string `json:"tracker"`
+	Key     string `json:"key"`
+	Title   string `json:"title"`
+	URL     string `json:"url"`
+}
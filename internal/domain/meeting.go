@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// DialogueSegment is one utterance with its offset from the start of the recording.
+// Label identifies the source in multi-track meetings and is empty otherwise.
+// Words carries the provider's words with offsets from the start of the
+// track when it reports word timings.
+type DialogueSegment struct {
+	Label  string           `json:"label"`
+	Text   string           `json:"text"`
+	Offset time.Duration    `json:"offset"`
+	Words  []TranscriptWord `json:"words,omitempty"`
+}
@@ -0,0 +1,9 @@
+package domain
+
+// NetworkState is the host connectivity as reported by the network manager.
+type NetworkState struct {
+	Online  bool `json:"online"`
+	Metered bool `json:"metered"`
+	// UsingFallback is set while transcription runs on the fallback provider.
+	UsingFallback bool `json:"usingFallback"`
+}
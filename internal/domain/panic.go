@@ -0,0 +1,16 @@
+package domain
+
+// PanicWipeResult reports what a panic wipe discarded.
+type PanicWipeResult struct {
+	SessionsAborted  int  `json:"sessionsAborted"`
+	MeetingAborted   bool `json:"meetingAborted"`
+	ClipboardCleared bool `json:"clipboardCleared"`
+	// HistoryPurged and RecordingsPurged count what was deleted from today's
+	// history and recordings; both stay zero unless purging is enabled.
+	HistoryPurged    int `json:"historyPurged"`
+	RecordingsPurged int `json:"recordingsPurged"`
+	// OverflowPurged and CachePurged count today's clipboard overflow files
+	// and cached file transcripts deleted, likewise only when purging.
+	OverflowPurged int `json:"overflowPurged"`
+	CachePurged    int `json:"cachePurged"`
+}
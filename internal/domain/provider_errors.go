@@ -0,0 +1,36 @@
+package domain
+
+import "errors"
+
+// ProviderError is a provider failure with a known cause, so the UI can show
+// targeted guidance instead of the raw transport error.
+type ProviderError struct {
+	Provider string
+	// Code is ErrorCodeInvalidKey, ErrorCodeInsufficientCredits or
+	// ErrorCodeUnsupportedConfig.
+	Code    ErrorCode
+	Message string
+	Err     error
+}
+
+func (e *ProviderError) Error() string {
+	message := e.Message
+	if message == "" && e.Err != nil {
+		message = e.Err.Error()
+	}
+	return e.Provider + " " + string(e.Code) + ": " + message
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCodeFor returns the code of a classified provider error in err's
+// chain, otherwise fallback.
+func ErrorCodeFor(err error, fallback ErrorCode) ErrorCode {
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) && providerErr.Code != "" {
+		return providerErr.Code
+	}
+	return fallback
+}
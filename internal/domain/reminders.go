@@ -0,0 +1,9 @@
+package domain
+
+import "time"
+
+// Reminder is a dictated reminder with the time it is due.
+type Reminder struct {
+	Text string    `json:"text"`
+	At   time.Time `json:"at"`
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// ResourceUsage reports what the running process costs, so an idle
+// background process can be checked to stay near zero CPU and memory.
+type ResourceUsage struct {
+	// HeapBytes is memory held by live Go objects.
+	HeapBytes uint64 `json:"heapBytes"`
+	// SysBytes is memory obtained from the operating system.
+	SysBytes   uint64 `json:"sysBytes"`
+	Goroutines int    `json:"goroutines"`
+	// CPUTime is user plus system CPU time since start; zero where the
+	// platform does not report it.
+	CPUTime    time.Duration    `json:"cpuTime"`
+	Uptime     time.Duration    `json:"uptime"`
+	Subsystems []SubsystemUsage `json:"subsystems"`
+}
+
+// SubsystemUsage is one optional background subsystem. Running is true only
+// while its loop runs: batch workers while files are queued, a session while
+// recording, triggers while reading a device and the others once started.
+type SubsystemUsage struct {
+	Name       string `json:"name"`
+	Configured bool   `json:"configured"`
+	Running    bool   `json:"running"`
+	Detail     string `json:"detail,omitempty"`
+}
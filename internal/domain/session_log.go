@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// SessionLogKind is what a session log entry records.
+type SessionLogKind string
+
+const (
+	SessionLogState      SessionLogKind = "state"
+	SessionLogAudio      SessionLogKind = "audio"
+	SessionLogTranscript SessionLogKind = "transcript"
+	SessionLogError      SessionLogKind = "error"
+)
+
+// SessionLog is the ordered internal event log of one session, kept for
+// debugging. Transcript text is reduced to word counts and errors to their
+// codes, so a log can be attached to a bug report and replayed in tests.
+type SessionLog struct {
+	SessionID string            `json:"sessionId"`
+	Started   time.Time         `json:"started"`
+	Entries   []SessionLogEntry `json:"entries"`
+	// Truncated is set once the log stopped growing at its entry limit.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// SessionLogEntry is one event of a SessionLog.
+type SessionLogEntry struct {
+	// Seq numbers entries from 1 in the order they were recorded.
+	Seq int `json:"seq"`
+	// At is the time since the session started.
+	At     time.Duration      `json:"at"`
+	Kind   SessionLogKind     `json:"kind"`
+	State  SessionState       `json:"state,omitempty"`
+	Reason SessionStateReason `json:"reason,omitempty"`
+	// Chunk is the number of audio chunks sent to the provider so far.
+	Chunk int `json:"chunk,omitempty"`
+	// Bytes is the size of an audio chunk.
+	Bytes       int            `json:"bytes,omitempty"`
+	Transcript  TranscriptKind `json:"transcript,omitempty"`
+	SpeechFinal bool           `json:"speechFinal,omitempty"`
+	Words       int            `json:"words,omitempty"`
+	Channel     int            `json:"channel,omitempty"`
+	Code        ErrorCode      `json:"code,omitempty"`
+}
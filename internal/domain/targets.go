@@ -0,0 +1,12 @@
+package domain
+
+// OutputTargetInfo describes an output target that dictation can be sent to.
+// Built-in targets use the COLDMIC_* settings; defined targets come from the
+// targets file and name the built-in Type they configure.
+type OutputTargetInfo struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	BuiltIn     bool   `json:"builtIn"`
+	Active      bool   `json:"active"`
+}
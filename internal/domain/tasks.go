@@ -0,0 +1,8 @@
+package domain
+
+// Task is a dictated to-do item with its capture tags.
+type Task struct {
+	Text    string `json:"text"`
+	Project string `json:"project,omitempty"`
+	Context string `json:"context,omitempty"`
+}
@@ -0,0 +1,44 @@
+package domain
+
+// TriggerDevice is an input device button that drives push-to-talk, such as
+// a USB foot pedal. KeyCode is the Linux input key code; 0 accepts any key
+// of the device.
+type TriggerDevice struct {
+	Path    string `json:"path"`
+	Name    string `json:"name,omitempty"`
+	KeyCode int    `json:"keyCode"`
+}
+
+// MIDIControl is the kind of MIDI control a binding listens to.
+type MIDIControl string
+
+const (
+	MIDINote MIDIControl = "note"
+	MIDICC   MIDIControl = "cc"
+)
+
+// MIDIMessage is a note or control change from a MIDI controller. On is set
+// for a note on or a control value of at least 64, such as a pad hit or a
+// sustain pedal pressed down. Channel counts from 1.
+type MIDIMessage struct {
+	Control MIDIControl
+	Channel int
+	Number  int
+	On      bool
+}
+
+// MIDIBinding triggers the hotkey named Press when a control turns on and the
+// one named Release when it turns off; either may be empty. Channel 0
+// matches every channel.
+type MIDIBinding struct {
+	Control MIDIControl `json:"control"`
+	Number  int         `json:"number"`
+	Channel int         `json:"channel,omitempty"`
+	Press   string      `json:"press,omitempty"`
+	Release string      `json:"release,omitempty"`
+}
+
+// Matches reports whether message comes from the bound control.
+func (b MIDIBinding) Matches(message MIDIMessage) bool {
+	return b.Control == message.Control && b.Number == message.Number && (b.Channel == 0 || b.Channel == message.Channel)
+}
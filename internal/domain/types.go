@@ -26,6 +26,9 @@ const (
 	SessionReasonNoTranscript                   SessionStateReason = "no_transcript"
 	SessionReasonTranscriptionFailed            SessionStateReason = "transcription_failed"
 	SessionReasonRulesFailed                    SessionStateReason = "rules_failed"
+	SessionReasonMeetingStarted                 SessionStateReason = "meeting_started"
+	SessionReasonProviderSwitched               SessionStateReason = "provider_switched"
+	SessionReasonIdleTimeout                    SessionStateReason = "idle_timeout"
 )
 
 // ErrorCode identifies non-fatal and fatal backend errors.
@@ -38,6 +41,17 @@ const (
 	ErrorCodeTranscription ErrorCode = "transcription"
 	ErrorCodeRules         ErrorCode = "rules"
 	ErrorCodeClipboard     ErrorCode = "clipboard"
+	ErrorCodeDiskSpace     ErrorCode = "disk_space"
+	ErrorCodeForm          ErrorCode = "form"
+	ErrorCodeTarget        ErrorCode = "target"
+	ErrorCodeCopyHook      ErrorCode = "copy_hook"
+	ErrorCodeRecordingHook ErrorCode = "recording_hook"
+	ErrorCodeReconnecting  ErrorCode = "reconnecting"
+	ErrorCodeTrigger       ErrorCode = "trigger"
+	// Provider errors the user can fix in their account or configuration.
+	ErrorCodeInvalidKey          ErrorCode = "invalid_key"
+	ErrorCodeInsufficientCredits ErrorCode = "insufficient_credits"
+	ErrorCodeUnsupportedConfig   ErrorCode = "unsupported_config"
 )
 
 // TranscriptKind identifies whether a stream event is partial or final text.
@@ -53,6 +67,66 @@ type TranscriptEvent struct {
 	Kind          TranscriptKind `json:"kind"`
 	Text          string         `json:"text"`
 	IsSpeechFinal bool           `json:"isSpeechFinal"`
+	// Confidence is the provider's 0-1 confidence in Text; 0 means unreported.
+	Confidence float64 `json:"confidence,omitempty"`
+	// Words carries per-word confidence when the provider reports it.
+	Words []TranscriptWord `json:"words,omitempty"`
+	// Speaker is the speaker of the first word when the provider diarizes.
+	Speaker int `json:"speaker,omitempty"`
+	// Channel numbers the audio channel from 1 when the provider transcribes
+	// channels separately; 0 is unknown.
+	Channel int `json:"channel,omitempty"`
+	// Language is the language code the provider detected, when it detects
+	// languages.
+	Language string `json:"language,omitempty"`
+	// Start and Duration locate the utterance in the audio stream when the provider reports timing.
+	Start    time.Duration `json:"start,omitempty"`
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+// TranscriptWord is one recognized word with the provider's confidence in it.
+// Start and End locate the word in the audio stream when the provider
+// reports word timings.
+type TranscriptWord struct {
+	Text       string        `json:"text"`
+	Confidence float64       `json:"confidence"`
+	Start      time.Duration `json:"start,omitempty"`
+	End        time.Duration `json:"end,omitempty"`
+	// Speaker numbers speakers from 1 when the provider diarizes; 0 is unknown.
+	Speaker int `json:"speaker,omitempty"`
+}
+
+// ConfidenceBucket groups word confidences for display.
+type ConfidenceBucket string
+
+const (
+	ConfidenceHigh   ConfidenceBucket = "high"
+	ConfidenceMedium ConfidenceBucket = "medium"
+	ConfidenceLow    ConfidenceBucket = "low"
+)
+
+// TranscriptSpan is a run of consecutive raw transcript words in the same
+// confidence bucket; Confidence is the lowest word confidence in the run.
+type TranscriptSpan struct {
+	Text       string           `json:"text"`
+	Confidence float64          `json:"confidence"`
+	Bucket     ConfidenceBucket `json:"bucket"`
+}
+
+// ConfidenceTranscript is the structured final transcript used to highlight
+// uncertain words while proofreading.
+type ConfidenceTranscript struct {
+	SessionID string           `json:"sessionId"`
+	Spans     []TranscriptSpan `json:"spans"`
+}
+
+// Caption is a translated rendition of a transcript event.
+type Caption struct {
+	Kind       TranscriptKind `json:"kind"`
+	SourceText string         `json:"sourceText"`
+	Text       string         `json:"text"`
+	Language   string         `json:"language"`
+	SessionID  string         `json:"sessionId,omitempty"`
 }
 
 // StopResult is returned once recording is stopped and transcription is processed.
@@ -61,6 +135,61 @@ type StopResult struct {
 	FinalTranscript string `json:"finalTranscript"`
 	Copied          bool   `json:"copied"`
 	SessionID       string `json:"sessionId,omitempty"`
+	// TranslatedTranscript is set when translation captions are enabled.
+	TranslatedTranscript string `json:"translatedTranscript,omitempty"`
+	// Segments carries the labeled utterances of a meeting recording.
+	Segments []DialogueSegment `json:"segments,omitempty"`
+	// TimestampedTranscript is the transcript with per-utterance timestamps when enabled.
+	TimestampedTranscript string `json:"timestampedTranscript,omitempty"`
+	// Form and Fields are set when a form-filling schema is active.
+	Form   string      `json:"form,omitempty"`
+	Fields []FormValue `json:"fields,omitempty"`
+	// Target names the output target the transcript was formatted for.
+	Target string `json:"target,omitempty"`
+	// Confidence is the provider's average confidence, when reported.
+	Confidence float64 `json:"confidence,omitempty"`
+	// RetryAvailable is set when Confidence fell below the retry threshold
+	// and the session audio was kept for RetryLastSession.
+	RetryAvailable bool `json:"retryAvailable,omitempty"`
+	// Spans splits RawTranscript by word confidence when the provider reports it.
+	Spans []TranscriptSpan `json:"spans,omitempty"`
+	// Words lists the recognized words with confidence, and with start and
+	// end offsets when the provider reports word timings.
+	Words []TranscriptWord `json:"words,omitempty"`
+	// Language is the detected language spoken in most of the transcript,
+	// when the provider detects languages.
+	Language string `json:"language,omitempty"`
+	// Usage is the audio sent to each provider and its estimated cost, when
+	// usage tracking is enabled: one entry per provider streamed to,
+	// including race partners and an accurate pass.
+	Usage []SessionUsage `json:"usage,omitempty"`
+	// Warnings lists the non-fatal issues of the session that may make the
+	// transcript less trustworthy.
+	Warnings []SessionWarning `json:"warnings,omitempty"`
+}
+
+// SessionWarning is a non-fatal issue during a session, such as dropped
+// audio or a provider reconnect. Count is how often it occurred.
+type SessionWarning struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+	Count   int       `json:"count"`
+}
+
+// LowConfidence offers to re-run a session whose transcript fell below the
+// minimum confidence.
+type LowConfidence struct {
+	SessionID  string  `json:"sessionId"`
+	Confidence float64 `json:"confidence"`
+	Threshold  float64 `json:"threshold"`
+}
+
+// Correction records a word or phrase the user fixed in a transcript.
+type Correction struct {
+	SessionID   string    `json:"sessionId,omitempty"`
+	Original    string    `json:"original"`
+	Corrected   string    `json:"corrected"`
+	CorrectedAt time.Time `json:"correctedAt"`
 }
 
 // LatestTranscript captures the most recent successful stop output.
@@ -69,9 +198,73 @@ type LatestTranscript struct {
 	CapturedAt time.Time  `json:"capturedAt"`
 }
 
+// Acceleration describes the inference backends of a local provider.
+type Acceleration struct {
+	Requested string   `json:"requested"`
+	Available []string `json:"available"`
+	// Active is the backend in use, "cpu" after falling back.
+	Active string `json:"active"`
+}
+
+// LocalModel is a model file local providers can download and run.
+type LocalModel struct {
+	Name string `json:"name"`
+	// Kind is "whisper" or "wake-word".
+	Kind      string `json:"kind"`
+	URL       string `json:"url"`
+	SHA256    string `json:"sha256,omitempty"`
+	SizeBytes int64  `json:"sizeBytes,omitempty"`
+	Installed bool   `json:"installed"`
+	Path      string `json:"path,omitempty"`
+}
+
+// ModelProgress reports a model download in flight. Total is 0 when the
+// server does not announce the size.
+type ModelProgress struct {
+	Name       string `json:"name"`
+	Downloaded int64  `json:"downloaded"`
+	Total      int64  `json:"total"`
+	Done       bool   `json:"done"`
+}
+
+// Capabilities describes what the configured transcription provider offers.
+type Capabilities struct {
+	Provider string `json:"provider"`
+	// Streaming is false for providers that receive the whole recording at once.
+	Streaming bool `json:"streaming"`
+	// LivePartials is false for providers that transcribe after recording stops.
+	LivePartials bool `json:"livePartials"`
+	// Timestamps reports utterance start and duration in the audio.
+	Timestamps bool `json:"timestamps"`
+	// WordTimings reports where each word starts and ends in the audio.
+	WordTimings bool `json:"wordTimings"`
+	// Confidence reports how sure the provider is of each transcript.
+	Confidence  bool `json:"confidence"`
+	Diarization bool `json:"diarization"`
+	// Keywords boosts recognition of ports.StreamingConfig.Keywords.
+	Keywords bool `json:"keywords"`
+	// Multichannel transcribes each audio channel separately.
+	Multichannel bool `json:"multichannel"`
+	// Languages lists the supported language codes; empty means any.
+	Languages []string `json:"languages,omitempty"`
+	// Acceleration is set for local providers only.
+	Acceleration *Acceleration `json:"acceleration,omitempty"`
+}
+
 // Status summarizes the current runtime status.
 type Status struct {
 	State   SessionState `json:"state"`
 	Active  bool         `json:"active"`
 	Message string       `json:"message,omitempty"`
 }
+
+// CopyEvent describes a transcript written to the clipboard, for clipboard
+// managers and user scripts.
+type CopyEvent struct {
+	SessionID     string    `json:"sessionId"`
+	Text          string    `json:"text"`
+	RawTranscript string    `json:"rawTranscript"`
+	Target        string    `json:"target,omitempty"`
+	Form          string    `json:"form,omitempty"`
+	CopiedAt      time.Time `json:"copiedAt"`
+}
@@ -0,0 +1,28 @@
+package domain
+
+// SessionUsage is the audio a session sent to its provider and what it is
+// estimated to cost.
+type SessionUsage struct {
+	Provider     string  `json:"provider"`
+	AudioSeconds float64 `json:"audioSeconds"`
+	// Cost is AudioSeconds priced at the configured rate for Provider; 0
+	// when no rate is configured.
+	Cost float64 `json:"cost"`
+}
+
+// ProviderUsage totals the sessions of one provider.
+type ProviderUsage struct {
+	Provider     string  `json:"provider"`
+	Sessions     int     `json:"sessions"`
+	AudioSeconds float64 `json:"audioSeconds"`
+	Cost         float64 `json:"cost"`
+}
+
+// UsageTotal is the running usage of one calendar month, such as "2026-10".
+type UsageTotal struct {
+	Month        string          `json:"month"`
+	Sessions     int             `json:"sessions"`
+	AudioSeconds float64         `json:"audioSeconds"`
+	Cost         float64         `json:"cost"`
+	Providers    []ProviderUsage `json:"providers"`
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// Waveform holds downsampled peaks for the saved recordings of one session,
+// one track per captured source.
+type Waveform struct {
+	SessionID string          `json:"sessionId"`
+	Tracks    []WaveformTrack `json:"tracks"`
+}
+
+// WaveformTrack covers Duration of audio in evenly sized buckets, so bucket i
+// starts at i*Duration/len(Peaks) and lines up with utterance offsets.
+// Peaks are absolute sample maxima scaled to 0..1.
+type WaveformTrack struct {
+	Label    string        `json:"label"`
+	Duration time.Duration `json:"duration"`
+	Peaks    []float64     `json:"peaks"`
+}
+
+// PlaybackPosition reports how far playback of a session's audio has got.
+// Done is set once, when playback ends or is stopped.
+type PlaybackPosition struct {
+	SessionID string        `json:"sessionId"`
+	Position  time.Duration `json:"position"`
+	Done      bool          `json:"done"`
+}
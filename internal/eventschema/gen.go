@@ -0,0 +1,21 @@
+//go:build ignore
+
+// gen writes the schema document generated from the domain types.
+package main
+
+import (
+	"log"
+	"os"
+
+	"coldmic/internal/eventschema"
+)
+
+func main() {
+	data, err := eventschema.Generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(eventschema.File, data, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
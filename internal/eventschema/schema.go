@@ -0,0 +1,141 @@
+// Package eventschema publishes the versioned JSON schema of the payloads
+// the app emits to frontends and plugins. The schema is generated from the
+// domain types and checked in, so a change to the contract shows up in
+// review.
+package eventschema
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"coldmic/internal/domain"
+)
+
+//go:generate go run gen.go
+
+// File is the name of the checked-in schema document.
+var File = fmt.Sprintf("coldmic-events.v%d.schema.json", domain.EventSchemaVersion)
+
+//go:embed coldmic-events.v1.schema.json
+var document []byte
+
+// Events maps the emitted event names to their payload types.
+var Events = map[string]reflect.Type{
+	"coldmic:session": reflect.TypeFor[domain.SessionEvent](),
+	"coldmic:partial": reflect.TypeFor[domain.PartialEvent](),
+	"coldmic:final":   reflect.TypeFor[domain.FinalEvent](),
+	"coldmic:error":   reflect.TypeFor[domain.ErrorEvent](),
+}
+
+// results lists the non-event types covered by the schema.
+var results = []reflect.Type{reflect.TypeFor[domain.StopResult]()}
+
+// enums closes string types whose values are part of the contract. Other
+// string types, such as error codes, may gain values within a version.
+var enums = map[reflect.Type][]string{
+	reflect.TypeFor[domain.SessionState](): {
+		string(domain.SessionStateIdle),
+		string(domain.SessionStateRecording),
+		string(domain.SessionStateStopping),
+		string(domain.SessionStateError),
+	},
+}
+
+// Document returns the checked-in schema.
+func Document() []byte {
+	return bytes.Clone(document)
+}
+
+// Generate builds the schema from the domain types.
+func Generate() ([]byte, error) {
+	g := generator{defs: map[string]any{}}
+	events := map[string]any{}
+	for name, typ := range Events {
+		events[name] = g.schema(typ)
+	}
+	for _, typ := range results {
+		g.schema(typ)
+	}
+	doc := map[string]any{
+		"$schema":  "https://json-schema.org/draft/2020-12/schema",
+		"$id":      fmt.Sprintf("urn:coldmic:events:v%d", domain.EventSchemaVersion),
+		"title":    "coldmic event payloads and results",
+		"version":  domain.EventSchemaVersion,
+		"x-events": events,
+		"$defs":    g.defs,
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+type generator struct {
+	defs map[string]any
+}
+
+// schema returns the schema of typ, adding structs to defs and referring
+// to them by name.
+func (g *generator) schema(typ reflect.Type) map[string]any {
+	if values, ok := enums[typ]; ok {
+		return map[string]any{"type": "string", "enum": values}
+	}
+	switch typ.Kind() {
+	case reflect.Pointer:
+		return g.schema(typ.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.schema(typ.Elem())}
+	case reflect.Struct:
+		ref := map[string]any{"$ref": "#/$defs/" + typ.Name()}
+		if _, ok := g.defs[typ.Name()]; ok {
+			return ref
+		}
+		// Reserve the name first so recursive types terminate.
+		g.defs[typ.Name()] = nil
+		g.defs[typ.Name()] = g.object(typ)
+		return ref
+	default:
+		panic(fmt.Sprintf("eventschema: unsupported type %s", typ))
+	}
+}
+
+func (g *generator) object(typ reflect.Type) map[string]any {
+	properties := map[string]any{}
+	required := []string{}
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = g.schema(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			required = append(required, name)
+		}
+	}
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
@@ -0,0 +1,143 @@
+package eventschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+func TestGeneratedSchemaIsCheckedIn(t *testing.T) {
+	t.Parallel()
+
+	generated, err := Generate()
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if !bytes.Equal(generated, Document()) {
+		t.Fatalf("%s is out of date with the domain types; run go generate ./internal/eventschema", File)
+	}
+}
+
+func TestPayloadsValidateAgainstSchema(t *testing.T) {
+	t.Parallel()
+
+	var doc struct {
+		Events map[string]map[string]any `json:"x-events"`
+		Defs   map[string]map[string]any `json:"$defs"`
+	}
+	if err := json.Unmarshal(Document(), &doc); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+
+	payloads := map[string]any{
+		"coldmic:session": domain.SessionEvent{SchemaVersion: domain.EventSchemaVersion, State: domain.SessionStateIdle, Reason: domain.SessionReasonMicCold},
+		"coldmic:partial": domain.PartialEvent{SchemaVersion: domain.EventSchemaVersion, Text: "hello"},
+		"coldmic:final":   domain.FinalEvent{SchemaVersion: domain.EventSchemaVersion, Raw: "hello", Transformed: "Hello.", SessionID: "session-1"},
+		"coldmic:error":   domain.ErrorEvent{SchemaVersion: domain.EventSchemaVersion, Code: domain.ErrorCodeTranscription},
+	}
+	for name, payload := range payloads {
+		if err := validate(doc.Defs, doc.Events[name], encode(t, payload)); err != nil {
+			t.Fatalf("%s payload does not match the schema: %v", name, err)
+		}
+	}
+
+	result := domain.StopResult{
+		RawTranscript:   "hello",
+		FinalTranscript: "Hello.",
+		Segments:        []domain.DialogueSegment{{Label: "Speaker 1", Text: "hello", Offset: time.Second}},
+		Words:           []domain.TranscriptWord{{Text: "hello", Confidence: 0.9, End: time.Second}},
+	}
+	if err := validate(doc.Defs, map[string]any{"$ref": "#/$defs/StopResult"}, encode(t, result)); err != nil {
+		t.Fatalf("StopResult does not match the schema: %v", err)
+	}
+
+	bad := map[string]any{"schemaVersion": 1, "state": "paused", "reason": "", "message": ""}
+	if err := validate(doc.Defs, doc.Events["coldmic:session"], bad); err == nil {
+		t.Fatalf("expected an unknown session state to be rejected")
+	}
+}
+
+func encode(t *testing.T, value any) any {
+	t.Helper()
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	return decoded
+}
+
+// validate checks value against the subset of JSON schema the generator
+// emits.
+func validate(defs map[string]map[string]any, schema map[string]any, value any) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		return validate(defs, defs[strings.TrimPrefix(ref, "#/$defs/")], value)
+	}
+	if enum, ok := schema["enum"].([]any); ok && !slices.Contains(enum, value) {
+		return fmt.Errorf("%v is not one of %v", value, enum)
+	}
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("expected an object, got %T", value)
+		}
+		for _, name := range schema["required"].([]any) {
+			if _, ok := object[name.(string)]; !ok {
+				return fmt.Errorf("missing required %q", name)
+			}
+		}
+		properties := schema["properties"].(map[string]any)
+		for name, field := range object {
+			property, ok := properties[name].(map[string]any)
+			if !ok {
+				return fmt.Errorf("unexpected property %q", name)
+			}
+			if err := validate(defs, property, field); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("expected an array, got %T", value)
+		}
+		for i, item := range items {
+			if err := validate(defs, schema["items"].(map[string]any), item); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+	case "string", "boolean", "number":
+		if !matchesScalar(schema["type"].(string), value) {
+			return fmt.Errorf("expected %s, got %T", schema["type"], value)
+		}
+	case "integer":
+		number, ok := value.(float64)
+		if !ok || number != float64(int64(number)) {
+			return fmt.Errorf("expected an integer, got %v", value)
+		}
+	}
+	return nil
+}
+
+func matchesScalar(kind string, value any) bool {
+	switch value.(type) {
+	case string:
+		return kind == "string"
+	case bool:
+		return kind == "boolean"
+	case float64:
+		return kind == "number"
+	}
+	return false
+}
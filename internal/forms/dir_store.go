@@ -0,0 +1,68 @@
+package forms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"coldmic/internal/domain"
+)
+
+var validName = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// DirStore loads form schemas from <dir>/<name>.json.
+type DirStore struct {
+	dir string
+}
+
+func NewDirStore(dir string) *DirStore {
+	return &DirStore{dir: dir}
+}
+
+func (s *DirStore) Load(name string) (domain.FormSchema, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !validName.MatchString(name) {
+		return domain.FormSchema{}, fmt.Errorf("invalid form name %q", name)
+	}
+	data, err := os.ReadFile(filepath.Join(s.dir, name+".json"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return domain.FormSchema{}, fmt.Errorf("form %q not found in %s", name, s.dir)
+		}
+		return domain.FormSchema{}, fmt.Errorf("failed to read form %q: %w", name, err)
+	}
+
+	var schema domain.FormSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return domain.FormSchema{}, fmt.Errorf("invalid form %q: %w", name, err)
+	}
+	if schema.Name == "" {
+		schema.Name = name
+	}
+	return schema, nil
+}
+
+// List returns the names of available forms in sorted order.
+func (s *DirStore) List() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list forms: %w", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !entry.IsDir() && ok && validName.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
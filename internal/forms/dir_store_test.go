@@ -0,0 +1,59 @@
+package forms
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDirStoreLoadsAndListsSchemas(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "contact.json"), `{"output":"kv","fields":[{"key":"name","required":true},{"key":"email","aliases":["mail"]}]}`)
+	writeFile(t, filepath.Join(dir, "bug-report.json"), `{"name":"Bug report","fields":[{"key":"title"}]}`)
+	writeFile(t, filepath.Join(dir, "notes.txt"), "ignored")
+
+	store := NewDirStore(dir)
+	names, err := store.List()
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"bug-report", "contact"}) {
+		t.Fatalf("unexpected form names: %q", names)
+	}
+
+	schema, err := store.Load("Contact")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if schema.Name != "contact" || schema.Output != "kv" || len(schema.Fields) != 2 || !schema.Fields[0].Required {
+		t.Fatalf("unexpected schema: %+v", schema)
+	}
+	if named, _ := store.Load("bug-report"); named.Name != "Bug report" {
+		t.Fatalf("expected explicit schema name to be kept, got %q", named.Name)
+	}
+}
+
+func TestDirStoreRejectsBadNames(t *testing.T) {
+	t.Parallel()
+
+	store := NewDirStore(t.TempDir())
+	if _, err := store.Load("../secrets"); err == nil {
+		t.Fatalf("expected path traversal to be rejected")
+	}
+	if _, err := store.Load("missing"); err == nil {
+		t.Fatalf("expected missing form error")
+	}
+	if names, err := NewDirStore(filepath.Join(t.TempDir(), "absent")).List(); err != nil || len(names) != 0 {
+		t.Fatalf("expected empty list for missing dir, got %q %v", names, err)
+	}
+}
+
+func writeFile(t *testing.T, path string, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"coldmic/internal/domain"
+)
+
+// CorrectionLog appends user corrections to a JSON-lines file.
+type CorrectionLog struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewCorrectionLog(path string) *CorrectionLog {
+	return &CorrectionLog{path: path}
+}
+
+func (l *CorrectionLog) RecordCorrection(_ context.Context, correction domain.Correction) error {
+	payload, err := json.Marshal(correction)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create corrections directory: %w", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open corrections file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(payload, '\n')); err != nil {
+		return fmt.Errorf("failed to write correction: %w", err)
+	}
+	return nil
+}
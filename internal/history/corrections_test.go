@@ -0,0 +1,39 @@
+package history
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+func TestCorrectionLogAppendsJSONLines(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "nested", "corrections.jsonl")
+	log := NewCorrectionLog(path)
+	for _, correction := range []domain.Correction{
+		{SessionID: "session-1", Original: "cold mick", Corrected: "ColdMic", CorrectedAt: time.Unix(1, 0).UTC()},
+		{Original: "k8", Corrected: "k8s", CorrectedAt: time.Unix(2, 0).UTC()},
+	} {
+		if err := log.RecordCorrection(context.Background(), correction); err != nil {
+			t.Fatalf("record failed: %v", err)
+		}
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read corrections: %v", err)
+	}
+	want := `{"sessionId":"session-1","original":"cold mick","corrected":"ColdMic","correctedAt":"1970-01-01T00:00:01Z"}` + "\n" +
+		`{"original":"k8","corrected":"k8s","correctedAt":"1970-01-01T00:00:02Z"}` + "\n"
+	if string(contents) != want {
+		t.Fatalf("unexpected corrections file:\n%s", contents)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected private corrections file, got %v %v", info.Mode(), err)
+	}
+}
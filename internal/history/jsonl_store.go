@@ -0,0 +1,151 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+// JSONLStore appends history entries to a JSON-lines file. Appending an entry
+// with the ID and creation time of an earlier one records a revision, which
+// List returns in place of the earlier line.
+type JSONLStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewJSONLStore(path string) *JSONLStore {
+	return &JSONLStore{path: path}
+}
+
+func (s *JSONLStore) Append(_ context.Context, entry domain.HistoryEntry) error {
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(payload, '\n')); err != nil {
+		return fmt.Errorf("failed to write history entry: %w", err)
+	}
+	return nil
+}
+
+func (s *JSONLStore) List(_ context.Context) ([]domain.HistoryEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	var entries []domain.HistoryEntry
+	positions := make(map[string]int)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry domain.HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		key := historyKey(entry)
+		if i, ok := positions[key]; ok {
+			entries[i] = entry
+			continue
+		}
+		positions[key] = len(entries)
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+	return entries, nil
+}
+
+// PurgeSince deletes the entries created at or after since, with all their
+// revisions, and returns how many entries it deleted.
+func (s *JSONLStore) PurgeSince(_ context.Context, since time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	contents, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	var kept []byte
+	purged := make(map[string]bool)
+	for _, line := range bytes.Split(contents, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var entry domain.HistoryEntry
+		if err := json.Unmarshal(line, &entry); err == nil && !entry.CreatedAt.Before(since) {
+			purged[historyKey(entry)] = true
+			continue
+		}
+		kept = append(append(kept, line...), '\n')
+	}
+	if len(purged) == 0 {
+		return 0, nil
+	}
+
+	if err := replaceFile(s.path, kept); err != nil {
+		return 0, fmt.Errorf("failed to purge history: %w", err)
+	}
+	return len(purged), nil
+}
+
+// historyKey identifies an entry across its revisions.
+func historyKey(entry domain.HistoryEntry) string {
+	return entry.ID + "@" + entry.CreatedAt.UTC().Format(time.RFC3339Nano)
+}
+
+// replaceFile atomically replaces path with data through a private temporary
+// file in the same directory.
+func replaceFile(path string, data []byte) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), ".history-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(data); err != nil {
+		_ = temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), path)
+}
@@ -0,0 +1,127 @@
+package history
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+func TestJSONLStoreAppendAndList(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "nested", "history.jsonl")
+	store := NewJSONLStore(path)
+
+	entries, err := store.List(context.Background())
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("expected empty history, got %v %v", entries, err)
+	}
+
+	first := domain.HistoryEntry{ID: "file-1", Source: domain.HistorySourceFile, FinalTranscript: "one", CreatedAt: time.Unix(1, 0).UTC()}
+	second := domain.HistoryEntry{ID: "session-1", Source: domain.HistorySourceSession, FinalTranscript: "two", CreatedAt: time.Unix(2, 0).UTC()}
+	for _, entry := range []domain.HistoryEntry{first, second} {
+		if err := store.Append(context.Background(), entry); err != nil {
+			t.Fatalf("append failed: %v", err)
+		}
+	}
+
+	entries, err = store.List(context.Background())
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != "file-1" || entries[1].FinalTranscript != "two" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat failed: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected private history file, got %v", info.Mode().Perm())
+	}
+}
+
+func TestJSONLStoreSkipsCorruptLines(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	if err := os.WriteFile(path, []byte("not json\n\n{\"id\":\"ok\"}\n"), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	entries, err := NewJSONLStore(path).List(context.Background())
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ID != "ok" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestJSONLStoreRevisionsReplaceEarlierLines(t *testing.T) {
+	t.Parallel()
+
+	store := NewJSONLStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	original := domain.HistoryEntry{ID: "file-1", FinalTranscript: "one", CreatedAt: time.Unix(1, 0).UTC()}
+	rerun := domain.HistoryEntry{ID: "file-1", FinalTranscript: "other run", CreatedAt: time.Unix(5, 0).UTC()}
+	edited := original
+	edited.EditedTranscript = "one, edited"
+	for _, entry := range []domain.HistoryEntry{original, rerun, edited} {
+		if err := store.Append(context.Background(), entry); err != nil {
+			t.Fatalf("append failed: %v", err)
+		}
+	}
+
+	entries, err := store.List(context.Background())
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].EditedTranscript != "one, edited" || entries[1].FinalTranscript != "other run" {
+		t.Fatalf("expected the revision in place of the original, got %+v", entries)
+	}
+}
+
+func TestJSONLStorePurgeSinceDeletesRecentEntries(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	store := NewJSONLStore(path)
+	old := domain.HistoryEntry{ID: "session-1", FinalTranscript: "yesterday", CreatedAt: time.Unix(100, 0).UTC()}
+	recent := domain.HistoryEntry{ID: "session-2", FinalTranscript: "secret", CreatedAt: time.Unix(200, 0).UTC()}
+	edited := recent
+	edited.FinalTranscript = "still secret"
+	for _, entry := range []domain.HistoryEntry{old, recent, edited} {
+		if err := store.Append(context.Background(), entry); err != nil {
+			t.Fatalf("append failed: %v", err)
+		}
+	}
+
+	purged, err := store.PurgeSince(context.Background(), time.Unix(150, 0))
+	if err != nil || purged != 1 {
+		t.Fatalf("expected one purged entry, got %d %v", purged, err)
+	}
+	entries, err := store.List(context.Background())
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ID != "session-1" {
+		t.Fatalf("unexpected entries after purge: %+v", entries)
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if strings.Contains(string(contents), "secret") {
+		t.Fatalf("expected purged text gone from the file, got %s", contents)
+	}
+
+	if purged, err := store.PurgeSince(context.Background(), time.Unix(150, 0)); err != nil || purged != 0 {
+		t.Fatalf("expected nothing left to purge, got %d %v", purged, err)
+	}
+}
@@ -0,0 +1,253 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+const (
+	markdownDayLayout    = "2006-01-02"
+	markdownMarkerPrefix = "<!-- coldmic "
+	markdownMarkerSuffix = " -->"
+)
+
+// MarkdownStore keeps history as one readable markdown file per day in a
+// directory. Each entry is a section headed by its time and source, with the
+// transcript as the section text and the remaining fields in an HTML
+// comment. Like JSONLStore, appending an entry with the ID and creation time
+// of an earlier one records a revision that List returns in its place.
+type MarkdownStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func NewMarkdownStore(dir string) *MarkdownStore {
+	return &MarkdownStore{dir: dir}
+}
+
+// markdownMeta is the comment of a section. Body says which transcript the
+// section text holds: the edited one when there is one, else the final one.
+type markdownMeta struct {
+	domain.HistoryEntry
+	Body string `json:"body"`
+}
+
+type markdownSection struct {
+	entry domain.HistoryEntry
+	lines []string
+}
+
+func (s *MarkdownStore) Append(_ context.Context, entry domain.HistoryEntry) error {
+	meta := markdownMeta{HistoryEntry: entry, Body: "final"}
+	body := entry.FinalTranscript
+	meta.FinalTranscript = ""
+	if entry.EditedTranscript != "" {
+		meta.Body = "edited"
+		body = entry.EditedTranscript
+		meta.FinalTranscript = entry.FinalTranscript
+		meta.EditedTranscript = ""
+	}
+	// json.Marshal escapes '>', so a transcript cannot close the comment.
+	payload, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	created := entry.CreatedAt.Local()
+	section := fmt.Sprintf("\n## %s · %s\n\n%s%s%s\n\n%s\n", created.Format("15:04:05"), entry.Source, markdownMarkerPrefix, payload, markdownMarkerSuffix, body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	path := filepath.Join(s.dir, created.Format(markdownDayLayout)+".md")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	if info, err := file.Stat(); err == nil && info.Size() == 0 {
+		section = "# " + created.Format(markdownDayLayout) + "\n" + section
+	}
+	if _, err := file.WriteString(section); err != nil {
+		return fmt.Errorf("failed to write history entry: %w", err)
+	}
+	return nil
+}
+
+func (s *MarkdownStore) List(_ context.Context) ([]domain.HistoryEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths, err := s.days()
+	if err != nil {
+		return nil, err
+	}
+	var entries []domain.HistoryEntry
+	positions := make(map[string]int)
+	for _, path := range paths {
+		_, sections, err := readMarkdownDay(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, section := range sections {
+			key := historyKey(section.entry)
+			if i, ok := positions[key]; ok {
+				entries[i] = section.entry
+				continue
+			}
+			positions[key] = len(entries)
+			entries = append(entries, section.entry)
+		}
+	}
+	return entries, nil
+}
+
+// PurgeSince deletes the entries created at or after since, with all their
+// revisions, and returns how many entries it deleted. Day files left without
+// entries are removed.
+func (s *MarkdownStore) PurgeSince(_ context.Context, since time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths, err := s.days()
+	if err != nil {
+		return 0, err
+	}
+	local := since.Local()
+	firstDay := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local)
+	purged := make(map[string]bool)
+	for _, path := range paths {
+		day, err := time.ParseInLocation(markdownDayLayout, strings.TrimSuffix(filepath.Base(path), ".md"), time.Local)
+		if err != nil || day.Before(firstDay) {
+			continue
+		}
+		header, sections, err := readMarkdownDay(path)
+		if err != nil {
+			return len(purged), err
+		}
+		kept := header
+		remaining := 0
+		for _, section := range sections {
+			if !section.entry.CreatedAt.Before(since) {
+				purged[historyKey(section.entry)] = true
+				continue
+			}
+			kept = append(kept, section.lines...)
+			remaining++
+		}
+		if remaining == len(sections) {
+			continue
+		}
+		if remaining == 0 {
+			if err := os.Remove(path); err != nil {
+				return len(purged), fmt.Errorf("failed to purge history: %w", err)
+			}
+			continue
+		}
+		if err := replaceFile(path, []byte(strings.Join(kept, "\n")+"\n")); err != nil {
+			return len(purged), fmt.Errorf("failed to purge history: %w", err)
+		}
+	}
+	return len(purged), nil
+}
+
+// days returns the day files in date order.
+func (s *MarkdownStore) days() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list history directory: %w", err)
+	}
+	var paths []string
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".md")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if _, err := time.Parse(markdownDayLayout, name); err != nil {
+			continue
+		}
+		paths = append(paths, filepath.Join(s.dir, entry.Name()))
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// readMarkdownDay splits a day file into the lines before its first section
+// and its sections. A section runs from its heading to the next section's
+// heading, so transcripts may contain headings of their own; sections with
+// an unreadable comment are skipped.
+func readMarkdownDay(path string) ([]string, []markdownSection, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+	lines := strings.Split(strings.TrimRight(string(contents), "\n"), "\n")
+
+	var markers []int
+	for i, line := range lines {
+		if strings.HasPrefix(line, markdownMarkerPrefix) && strings.HasSuffix(line, markdownMarkerSuffix) {
+			markers = append(markers, i)
+		}
+	}
+	// starts[k] is where section k begins: its heading, when the marker has
+	// one, and the blank lines before it.
+	starts := make([]int, len(markers))
+	for k, marker := range markers {
+		start := marker
+		i := marker - 1
+		for i >= 0 && lines[i] == "" {
+			i--
+		}
+		if i >= 0 && strings.HasPrefix(lines[i], "## ") {
+			start = i
+			for start > 0 && lines[start-1] == "" {
+				start--
+			}
+		}
+		starts[k] = start
+	}
+
+	var header []string
+	if len(markers) == 0 {
+		header = lines
+	} else {
+		header = lines[:starts[0]]
+	}
+	var sections []markdownSection
+	for k, marker := range markers {
+		end := len(lines)
+		if k+1 < len(markers) {
+			end = starts[k+1]
+		}
+		var meta markdownMeta
+		payload := strings.TrimSuffix(strings.TrimPrefix(lines[marker], markdownMarkerPrefix), markdownMarkerSuffix)
+		if err := json.Unmarshal([]byte(payload), &meta); err != nil {
+			continue
+		}
+		body := strings.Trim(strings.Join(lines[marker+1:end], "\n"), "\n")
+		entry := meta.HistoryEntry
+		if meta.Body == "edited" {
+			entry.EditedTranscript = body
+		} else {
+			entry.FinalTranscript = body
+		}
+		sections = append(sections, markdownSection{entry: entry, lines: lines[starts[k]:end]})
+	}
+	return header, sections, nil
+}
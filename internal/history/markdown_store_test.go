@@ -0,0 +1,85 @@
+package history
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+func TestMarkdownStoreRoundTripsReadableEntries(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "history")
+	store := NewMarkdownStore(dir)
+	day := time.Date(2026, 3, 4, 9, 15, 0, 0, time.Local)
+	first := domain.HistoryEntry{ID: "session-1", Source: domain.HistorySourceSession, RawTranscript: "raw --> one", FinalTranscript: "First line.\n\n## Not a heading\nLast line.", CreatedAt: day}
+	second := domain.HistoryEntry{ID: "file-1", Source: domain.HistorySourceFile, SourcePath: "/tmp/a.wav", FinalTranscript: "two", CreatedAt: day.Add(time.Hour)}
+	edited := first
+	edited.EditedTranscript = "First line, edited."
+	edited.EditedAt = day.Add(2 * time.Hour)
+	nextDay := domain.HistoryEntry{ID: "session-2", Source: domain.HistorySourceSession, FinalTranscript: "three", CreatedAt: day.AddDate(0, 0, 1)}
+	for _, entry := range []domain.HistoryEntry{first, second, edited, nextDay} {
+		if err := store.Append(context.Background(), entry); err != nil {
+			t.Fatalf("append failed: %v", err)
+		}
+	}
+
+	entries, err := store.List(context.Background())
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected three entries, got %+v", entries)
+	}
+	if entries[0].FinalTranscript != first.FinalTranscript || entries[0].EditedTranscript != "First line, edited." || entries[0].RawTranscript != "raw --> one" {
+		t.Fatalf("expected the revision in place of the first entry, got %+v", entries[0])
+	}
+	if entries[1].SourcePath != "/tmp/a.wav" || entries[1].FinalTranscript != "two" || !entries[1].CreatedAt.Equal(second.CreatedAt) {
+		t.Fatalf("unexpected second entry: %+v", entries[1])
+	}
+	if entries[2].ID != "session-2" {
+		t.Fatalf("expected the next day's entry last, got %+v", entries[2])
+	}
+
+	contents, err := os.ReadFile(filepath.Join(dir, "2026-03-04.md"))
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if !strings.HasPrefix(string(contents), "# 2026-03-04\n\n## 09:15:00 · session\n") || !strings.Contains(string(contents), "\ntwo\n") {
+		t.Fatalf("expected readable markdown, got:\n%s", contents)
+	}
+}
+
+func TestMarkdownStorePurgeSinceDeletesRecentEntries(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	store := NewMarkdownStore(dir)
+	day := time.Date(2026, 3, 4, 0, 0, 0, 0, time.Local)
+	for i, offset := range []time.Duration{-time.Hour, 9 * time.Hour, 10 * time.Hour, 34 * time.Hour} {
+		entry := domain.HistoryEntry{ID: "session-" + string(rune('a'+i)), Source: domain.HistorySourceSession, FinalTranscript: "text", CreatedAt: day.Add(offset)}
+		if err := store.Append(context.Background(), entry); err != nil {
+			t.Fatalf("append failed: %v", err)
+		}
+	}
+
+	purged, err := store.PurgeSince(context.Background(), day.Add(10*time.Hour))
+	if err != nil || purged != 2 {
+		t.Fatalf("expected two purged entries, got %d %v", purged, err)
+	}
+	entries, err := store.List(context.Background())
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != "session-a" || entries[1].ID != "session-b" {
+		t.Fatalf("unexpected entries after purge: %+v", entries)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2026-03-05.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected the emptied day file removed, got %v", err)
+	}
+}
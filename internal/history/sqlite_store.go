@@ -0,0 +1,110 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+// SQLiteStore keeps history in a SQLite database, for large histories that
+// are listed and searched often. Each entry is a JSON row keyed by its ID and
+// creation time, so appending a revision replaces the earlier row in place,
+// as JSONLStore does. It needs a build with the sqlite tag; other builds fail
+// in NewSQLiteStore.
+type SQLiteStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+var sqliteSchema = []string{
+	`CREATE TABLE IF NOT EXISTS history (
+		seq INTEGER PRIMARY KEY AUTOINCREMENT,
+		id TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		source TEXT NOT NULL,
+		entry TEXT NOT NULL,
+		UNIQUE (id, created_at)
+	)`,
+	`CREATE INDEX IF NOT EXISTS history_created_at ON history (created_at)`,
+}
+
+// NewSQLiteStore opens the database at path, creating it when missing.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+	s := &SQLiteStore{path: path}
+	for _, statement := range sqliteSchema {
+		if err := s.run(statement, nil, nil); err != nil {
+			return nil, fmt.Errorf("failed to open history database: %w", err)
+		}
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to restrict history database: %w", err)
+	}
+	return s, nil
+}
+
+func (s *SQLiteStore) Append(_ context.Context, entry domain.HistoryEntry) error {
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err = s.run(
+		`INSERT INTO history (id, created_at, source, entry) VALUES (?, ?, ?, ?)
+		ON CONFLICT (id, created_at) DO UPDATE SET source = excluded.source, entry = excluded.entry`,
+		[]any{entry.ID, entry.CreatedAt.UnixNano(), string(entry.Source), string(payload)},
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write history entry: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) List(_ context.Context) ([]domain.HistoryEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var entries []domain.HistoryEntry
+	err := s.run(`SELECT entry FROM history ORDER BY seq`, nil, func(columns []string) error {
+		var entry domain.HistoryEntry
+		if err := json.Unmarshal([]byte(columns[0]), &entry); err == nil {
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return entries, nil
+}
+
+// PurgeSince deletes the entries created at or after since and returns how
+// many it deleted.
+func (s *SQLiteStore) PurgeSince(_ context.Context, since time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purged := 0
+	err := s.run(`SELECT COUNT(*) FROM history WHERE created_at >= ?`, []any{since.UnixNano()}, func(columns []string) error {
+		_, err := fmt.Sscan(columns[0], &purged)
+		return err
+	})
+	if err == nil && purged > 0 {
+		err = s.run(`DELETE FROM history WHERE created_at >= ?`, []any{since.UnixNano()}, nil)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge history: %w", err)
+	}
+	return purged, nil
+}
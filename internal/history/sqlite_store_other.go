@@ -0,0 +1,9 @@
+//go:build !sqlite
+
+package history
+
+import "errors"
+
+func (s *SQLiteStore) run(string, []any, func([]string) error) error {
+	return errors.New("COLDMIC_HISTORY_BACKEND=sqlite needs a build with -tags sqlite")
+}
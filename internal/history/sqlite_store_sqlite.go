@@ -0,0 +1,97 @@
+//go:build sqlite
+
+package history
+
+/*
+#cgo pkg-config: sqlite3
+#include <stdlib.h>
+#include <sqlite3.h>
+
+// SQLITE_TRANSIENT is a function pointer cast cgo cannot express.
+static int coldmic_bind_text(sqlite3_stmt *stmt, int index, const char *text, int length) {
+	return sqlite3_bind_text(stmt, index, text, length, SQLITE_TRANSIENT);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// sqliteBusyTimeoutMS is how long a statement waits for another process, such
+// as a second workspace window, to release the database.
+const sqliteBusyTimeoutMS = 5000
+
+// run executes one statement with args bound in order, calling row with the
+// text of each result row's columns. The database is opened for the call
+// only, so the store needs no Close and a rebuilt service graph leaks no
+// handle. s.mu serializes calls within the process.
+func (s *SQLiteStore) run(sql string, args []any, row func([]string) error) error {
+	cpath := C.CString(s.path)
+	defer C.free(unsafe.Pointer(cpath))
+	var db *C.sqlite3
+	if rc := C.sqlite3_open_v2(cpath, &db, C.SQLITE_OPEN_READWRITE|C.SQLITE_OPEN_CREATE, nil); rc != C.SQLITE_OK {
+		err := sqliteError(db, rc)
+		C.sqlite3_close(db)
+		return err
+	}
+	defer C.sqlite3_close(db)
+	C.sqlite3_busy_timeout(db, sqliteBusyTimeoutMS)
+
+	csql := C.CString(sql)
+	defer C.free(unsafe.Pointer(csql))
+	var stmt *C.sqlite3_stmt
+	if rc := C.sqlite3_prepare_v2(db, csql, -1, &stmt, nil); rc != C.SQLITE_OK {
+		return sqliteError(db, rc)
+	}
+	defer C.sqlite3_finalize(stmt)
+
+	for i, arg := range args {
+		index := C.int(i + 1)
+		var rc C.int
+		switch value := arg.(type) {
+		case string:
+			text := C.CString(value)
+			rc = C.coldmic_bind_text(stmt, index, text, C.int(len(value)))
+			C.free(unsafe.Pointer(text))
+		case int64:
+			rc = C.sqlite3_bind_int64(stmt, index, C.sqlite3_int64(value))
+		default:
+			return fmt.Errorf("unsupported sqlite argument %T", arg)
+		}
+		if rc != C.SQLITE_OK {
+			return sqliteError(db, rc)
+		}
+	}
+
+	for {
+		switch rc := C.sqlite3_step(stmt); rc {
+		case C.SQLITE_DONE:
+			return nil
+		case C.SQLITE_ROW:
+			if row == nil {
+				continue
+			}
+			columns := make([]string, int(C.sqlite3_column_count(stmt)))
+			for i := range columns {
+				// column_text must come before column_bytes, which then
+				// reports the length of the text conversion.
+				text := C.sqlite3_column_text(stmt, C.int(i))
+				columns[i] = C.GoStringN((*C.char)(unsafe.Pointer(text)), C.sqlite3_column_bytes(stmt, C.int(i)))
+			}
+			if err := row(columns); err != nil {
+				return err
+			}
+		default:
+			return sqliteError(db, rc)
+		}
+	}
+}
+
+func sqliteError(db *C.sqlite3, rc C.int) error {
+	if db != nil {
+		return fmt.Errorf("sqlite: %s", C.GoString(C.sqlite3_errmsg(db)))
+	}
+	return fmt.Errorf("sqlite: %s", C.GoString(C.sqlite3_errstr(rc)))
+}
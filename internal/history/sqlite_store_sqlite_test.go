@@ -0,0 +1,52 @@
+//go:build sqlite
+
+package history
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+func TestSQLiteStoreAppendListAndPurge(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "nested", "history.db")
+	store, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	first := domain.HistoryEntry{ID: "session-1", Source: domain.HistorySourceSession, FinalTranscript: "one", CreatedAt: time.Unix(100, 5).UTC()}
+	second := domain.HistoryEntry{ID: "file-1", Source: domain.HistorySourceFile, FinalTranscript: "two", CreatedAt: time.Unix(200, 0).UTC()}
+	edited := first
+	edited.EditedTranscript = "one, edited"
+	for _, entry := range []domain.HistoryEntry{first, second, edited} {
+		if err := store.Append(context.Background(), entry); err != nil {
+			t.Fatalf("append failed: %v", err)
+		}
+	}
+
+	entries, err := store.List(context.Background())
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].EditedTranscript != "one, edited" || entries[1].ID != "file-1" {
+		t.Fatalf("expected the revision in place of the first entry, got %+v", entries)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected a private database, got %v %v", info, err)
+	}
+
+	purged, err := store.PurgeSince(context.Background(), time.Unix(150, 0))
+	if err != nil || purged != 1 {
+		t.Fatalf("expected one purged entry, got %d %v", purged, err)
+	}
+	if entries, err := store.List(context.Background()); err != nil || len(entries) != 1 || entries[0].ID != "session-1" {
+		t.Fatalf("unexpected entries after purge: %+v %v", entries, err)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !sqlite
+
+package history
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSQLiteStoreNeedsSQLiteBuild(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewSQLiteStore(filepath.Join(t.TempDir(), "history.db"))
+	if err == nil || !strings.Contains(err.Error(), "-tags sqlite") {
+		t.Fatalf("expected a build tag error, got %v", err)
+	}
+}
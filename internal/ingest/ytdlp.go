@@ -0,0 +1,57 @@
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"coldmic/internal/debuglog"
+)
+
+// YTDLPDownloader fetches the audio track of a URL using yt-dlp.
+type YTDLPDownloader struct {
+	command string
+}
+
+func NewYTDLPDownloader(command string) *YTDLPDownloader {
+	if command == "" {
+		command = "yt-dlp"
+	}
+	return &YTDLPDownloader{command: command}
+}
+
+// Download stores the audio for sourceURL in destDir and returns the file path.
+func (d *YTDLPDownloader) Download(ctx context.Context, sourceURL string, destDir string) (string, error) {
+	args := []string{
+		"--no-playlist",
+		"--no-progress",
+		"--extract-audio",
+		"--audio-format", "wav",
+		"--output", destDir + "/%(id)s.%(ext)s",
+		"--print", "after_move:filepath",
+		sourceURL,
+	}
+	debuglog.Printf("yt-dlp download command=%s url=%q dest=%q", d.command, sourceURL, destDir)
+
+	cmd := exec.CommandContext(ctx, d.command, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("yt-dlp failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	path := lastLine(stdout.String())
+	if path == "" {
+		return "", errors.New("yt-dlp did not report a downloaded file")
+	}
+	return path, nil
+}
+
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
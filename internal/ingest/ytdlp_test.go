@@ -0,0 +1,48 @@
+package ingest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestYTDLPDownloaderReturnsPrintedPath(t *testing.T) {
+	script := writeScript(t, "yt-dlp.sh", "#!/usr/bin/env bash\necho '[info] downloading'\necho \"${7%/*}/abc.wav\"\n")
+	dest := t.TempDir()
+
+	path, err := NewYTDLPDownloader(script).Download(context.Background(), "https://example.com/talk", dest)
+	if err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	if path != filepath.Join(dest, "abc.wav") {
+		t.Fatalf("unexpected path: %q", path)
+	}
+}
+
+func TestYTDLPDownloaderReportsFailure(t *testing.T) {
+	script := writeScript(t, "yt-dlp-fail.sh", "#!/usr/bin/env bash\necho 'ERROR: unsupported URL' 1>&2\nexit 1\n")
+
+	_, err := NewYTDLPDownloader(script).Download(context.Background(), "https://example.com/nope", t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "unsupported URL") {
+		t.Fatalf("expected yt-dlp error with stderr, got %v", err)
+	}
+}
+
+func TestYTDLPDownloaderRequiresOutputPath(t *testing.T) {
+	script := writeScript(t, "yt-dlp-empty.sh", "#!/usr/bin/env bash\nexit 0\n")
+
+	if _, err := NewYTDLPDownloader(script).Download(context.Background(), "https://example.com/x", t.TempDir()); err == nil {
+		t.Fatalf("expected missing path error")
+	}
+}
+
+func writeScript(t *testing.T, name string, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o700); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+	return path
+}
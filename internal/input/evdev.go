@@ -0,0 +1,185 @@
+package input
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"coldmic/internal/domain"
+)
+
+// Linux input event types and key values, see linux/input-event-codes.h.
+const (
+	evKey        = 0x01
+	keyReleased  = 0
+	keyPressed   = 1
+	defaultInput = "/dev/input"
+	defaultSysfs = "/sys/class/input"
+)
+
+// eventSize is sizeof(struct input_event): a timeval of two longs followed
+// by type, code and value.
+var eventSize = 2*strconv.IntSize/8 + 8
+
+type keyEvent struct {
+	code  int
+	value int32
+}
+
+// readKeyEvents decodes input events from r and calls fn for each key
+// event until r fails.
+func readKeyEvents(r io.Reader, fn func(keyEvent) bool) error {
+	buf := make([]byte, eventSize)
+	offset := eventSize - 8
+	for {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return err
+		}
+		if binary.LittleEndian.Uint16(buf[offset:]) != evKey {
+			continue
+		}
+		event := keyEvent{
+			code:  int(binary.LittleEndian.Uint16(buf[offset+2:])),
+			value: int32(binary.LittleEndian.Uint32(buf[offset+4:])),
+		}
+		if !fn(event) {
+			return nil
+		}
+	}
+}
+
+// EvdevButton reads one key of an evdev device, such as a USB foot pedal or
+// macro pad. Reading /dev/input usually requires membership of the input
+// group.
+type EvdevButton struct {
+	path    string
+	keyCode int
+}
+
+// NewEvdevButton watches keyCode on the device at path; keyCode 0 accepts
+// any key of the device.
+func NewEvdevButton(path string, keyCode int) *EvdevButton {
+	return &EvdevButton{path: path, keyCode: keyCode}
+}
+
+// Run reports presses and releases of the key until ctx ends or the device
+// goes away. Auto-repeat is ignored.
+func (b *EvdevButton) Run(ctx context.Context, pressed func(down bool)) error {
+	device, err := os.Open(b.path)
+	if err != nil {
+		return fmt.Errorf("failed to open trigger device: %w", err)
+	}
+	stop := context.AfterFunc(ctx, func() { _ = device.Close() })
+	defer stop()
+	defer device.Close()
+
+	err = readKeyEvents(device, func(event keyEvent) bool {
+		if b.keyCode != 0 && event.code != b.keyCode {
+			return true
+		}
+		switch event.value {
+		case keyPressed:
+			pressed(true)
+		case keyReleased:
+			pressed(false)
+		}
+		return true
+	})
+	if ctx.Err() != nil {
+		return nil
+	}
+	return fmt.Errorf("trigger device %s stopped: %w", b.path, err)
+}
+
+// EvdevLearner finds the device and key of the next button press.
+type EvdevLearner struct {
+	dir   string
+	sysfs string
+}
+
+func NewEvdevLearner() *EvdevLearner {
+	return &EvdevLearner{dir: defaultInput, sysfs: defaultSysfs}
+}
+
+// Learn watches every readable event device until a key is pressed. The
+// device is reported by its stable /dev/input/by-id path when it has one.
+func (l *EvdevLearner) Learn(ctx context.Context) (domain.TriggerDevice, error) {
+	paths, err := filepath.Glob(filepath.Join(l.dir, "event*"))
+	if err != nil {
+		return domain.TriggerDevice{}, err
+	}
+	sort.Strings(paths)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	found := make(chan domain.TriggerDevice, 1)
+	var wg sync.WaitGroup
+	opened := 0
+	for _, path := range paths {
+		device, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		opened++
+		wg.Add(1)
+		context.AfterFunc(ctx, func() { _ = device.Close() })
+		go func() {
+			defer wg.Done()
+			_ = readKeyEvents(device, func(event keyEvent) bool {
+				if event.value != keyPressed {
+					return true
+				}
+				select {
+				case found <- domain.TriggerDevice{Path: path, KeyCode: event.code}:
+				default:
+				}
+				return false
+			})
+		}()
+	}
+	if opened == 0 {
+		return domain.TriggerDevice{}, fmt.Errorf("no readable input devices in %s; add yourself to the input group", l.dir)
+	}
+
+	select {
+	case device := <-found:
+		cancel()
+		wg.Wait()
+		device.Name = l.deviceName(device.Path)
+		device.Path = l.stablePath(device.Path)
+		return device, nil
+	case <-ctx.Done():
+		wg.Wait()
+		return domain.TriggerDevice{}, errors.New("no button was pressed")
+	}
+}
+
+func (l *EvdevLearner) deviceName(path string) string {
+	name, err := os.ReadFile(filepath.Join(l.sysfs, filepath.Base(path), "device", "name"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(name))
+}
+
+// stablePath returns the by-id link to path, which survives replugging,
+// or path itself.
+func (l *EvdevLearner) stablePath(path string) string {
+	links, _ := filepath.Glob(filepath.Join(l.dir, "by-id", "*"))
+	sort.Strings(links)
+	for _, link := range links {
+		target, err := filepath.EvalSymlinks(link)
+		if err == nil && target == path {
+			return link
+		}
+	}
+	return path
+}
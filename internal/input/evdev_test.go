@@ -0,0 +1,101 @@
+package input
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestEvdevButtonReportsPressAndRelease(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "event0")
+	writeEvents(t, path, []rawEvent{
+		{kind: 0x04, code: 4, value: 30}, // EV_MSC scan code
+		{kind: evKey, code: 30, value: keyPressed},
+		{kind: evKey, code: 183, value: keyPressed},
+		{kind: evKey, code: 183, value: 2},
+		{kind: 0x00, code: 0, value: 0}, // EV_SYN
+		{kind: evKey, code: 183, value: keyReleased},
+	})
+
+	var got []bool
+	err := NewEvdevButton(path, 183).Run(context.Background(), func(down bool) { got = append(got, down) })
+	if err == nil {
+		t.Fatalf("expected end of device to be reported")
+	}
+	if !slices.Equal(got, []bool{true, false}) {
+		t.Fatalf("expected press then release of key 183, got %v", got)
+	}
+}
+
+func TestEvdevLearnerFindsPressedButton(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	sysfs := t.TempDir()
+	writeEvents(t, filepath.Join(dir, "event3"), nil)
+	writeEvents(t, filepath.Join(dir, "event7"), []rawEvent{
+		{kind: evKey, code: 183, value: keyReleased},
+		{kind: evKey, code: 184, value: keyPressed},
+	})
+	if err := os.MkdirAll(filepath.Join(dir, "by-id"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(dir, "event7"), filepath.Join(dir, "by-id", "usb-Pedal-event-kbd")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(sysfs, "event7", "device"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sysfs, "event7", "device", "name"), []byte("Foot Pedal\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	learner := &EvdevLearner{dir: dir, sysfs: sysfs}
+	device, err := learner.Learn(context.Background())
+	if err != nil {
+		t.Fatalf("learn failed: %v", err)
+	}
+	if device.Path != filepath.Join(dir, "by-id", "usb-Pedal-event-kbd") || device.KeyCode != 184 || device.Name != "Foot Pedal" {
+		t.Fatalf("unexpected device %+v", device)
+	}
+}
+
+type rawEvent struct {
+	kind  uint16
+	code  uint16
+	value int32
+}
+
+func writeEvents(t *testing.T, path string, events []rawEvent) {
+	t.Helper()
+	var data []byte
+	for _, event := range events {
+		buf := make([]byte, eventSize)
+		offset := eventSize - 8
+		binary.LittleEndian.PutUint16(buf[offset:], event.kind)
+		binary.LittleEndian.PutUint16(buf[offset+2:], event.code)
+		binary.LittleEndian.PutUint32(buf[offset+4:], uint32(event.value))
+		data = append(data, buf...)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGamepadButtonCode(t *testing.T) {
+	t.Parallel()
+
+	for name, want := range map[string]int{"A": 0x130, " start ": 0x13b, "south": 0x130, "304": 304} {
+		if code, err := GamepadButtonCode(name); err != nil || code != want {
+			t.Fatalf("%q: expected %d, got %d %v", name, want, code, err)
+		}
+	}
+	if _, err := GamepadButtonCode("turbo"); err == nil {
+		t.Fatalf("expected unknown button to fail")
+	}
+}
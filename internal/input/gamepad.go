@@ -0,0 +1,49 @@
+package input
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// gamepadButtons maps button names to Linux gamepad key codes. Names follow
+// the Xbox layout, with the positional names the kernel uses as aliases.
+var gamepadButtons = map[string]int{
+	"a":      0x130,
+	"south":  0x130,
+	"b":      0x131,
+	"east":   0x131,
+	"x":      0x133,
+	"north":  0x133,
+	"y":      0x134,
+	"west":   0x134,
+	"lb":     0x136,
+	"rb":     0x137,
+	"lt":     0x138,
+	"rt":     0x139,
+	"select": 0x13a,
+	"back":   0x13a,
+	"start":  0x13b,
+	"guide":  0x13c,
+	"ls":     0x13d,
+	"rs":     0x13e,
+}
+
+// GamepadButtonCode resolves a gamepad button name such as "a" or "start",
+// or a numeric key code, to a Linux key code.
+func GamepadButtonCode(name string) (int, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if code, ok := gamepadButtons[name]; ok {
+		return code, nil
+	}
+	if code, err := strconv.Atoi(name); err == nil && code > 0 {
+		return code, nil
+	}
+	names := make([]string, 0, len(gamepadButtons))
+	for name := range gamepadButtons {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return 0, fmt.Errorf("unknown gamepad button %q (expected a key code or one of %s)", name, strings.Join(names, ", "))
+}
@@ -0,0 +1,114 @@
+package input
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"coldmic/internal/domain"
+)
+
+// MIDI status bytes, see the MIDI 1.0 specification.
+const (
+	midiNoteOff       = 0x80
+	midiNoteOn        = 0x90
+	midiControlChange = 0xB0
+	midiSysExStart    = 0xF0
+	midiRealtime      = 0xF8
+)
+
+// RawMIDI reads a MIDI controller through its ALSA raw MIDI node, such as
+// /dev/snd/midiC1D0. Reading /dev/snd usually requires membership of the
+// audio group.
+type RawMIDI struct {
+	path string
+}
+
+func NewRawMIDI(path string) *RawMIDI {
+	return &RawMIDI{path: path}
+}
+
+// Run reports notes and control changes until ctx ends or the device goes
+// away.
+func (m *RawMIDI) Run(ctx context.Context, received func(domain.MIDIMessage)) error {
+	device, err := os.Open(m.path)
+	if err != nil {
+		return fmt.Errorf("failed to open MIDI device: %w", err)
+	}
+	stop := context.AfterFunc(ctx, func() { _ = device.Close() })
+	defer stop()
+	defer device.Close()
+
+	err = readMIDI(device, received)
+	if ctx.Err() != nil {
+		return nil
+	}
+	return fmt.Errorf("MIDI device %s stopped: %w", m.path, err)
+}
+
+// readMIDI decodes a MIDI byte stream, including running status, and calls
+// fn for each note and control change until r fails. Other messages are
+// skipped.
+func readMIDI(r io.Reader, fn func(domain.MIDIMessage)) error {
+	reader := bufio.NewReader(r)
+	var status byte
+	var data []byte
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return err
+		}
+		switch {
+		case b >= midiRealtime:
+			// Clock and transport bytes may appear anywhere, even inside
+			// another message.
+			continue
+		case b >= midiSysExStart:
+			// System messages cancel running status.
+			status = 0
+			continue
+		case b >= midiNoteOff:
+			status = b
+			data = data[:0]
+			continue
+		case status == 0:
+			continue
+		}
+		data = append(data, b)
+		if len(data) < midiDataLength(status) {
+			continue
+		}
+		if message, ok := decodeMIDI(status, data); ok {
+			fn(message)
+		}
+		data = data[:0]
+	}
+}
+
+func midiDataLength(status byte) int {
+	switch status & 0xF0 {
+	case 0xC0, 0xD0:
+		return 1
+	}
+	return 2
+}
+
+func decodeMIDI(status byte, data []byte) (domain.MIDIMessage, bool) {
+	message := domain.MIDIMessage{Channel: int(status&0x0F) + 1, Number: int(data[0])}
+	switch status & 0xF0 {
+	case midiNoteOn:
+		// A note on with velocity 0 is a note off.
+		message.Control = domain.MIDINote
+		message.On = data[1] > 0
+	case midiNoteOff:
+		message.Control = domain.MIDINote
+	case midiControlChange:
+		message.Control = domain.MIDICC
+		message.On = data[1] >= 64
+	default:
+		return domain.MIDIMessage{}, false
+	}
+	return message, true
+}
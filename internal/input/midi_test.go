@@ -0,0 +1,39 @@
+package input
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"testing"
+
+	"coldmic/internal/domain"
+)
+
+func TestReadMIDIDecodesNotesAndControls(t *testing.T) {
+	t.Parallel()
+
+	stream := []byte{
+		0xF8,             // clock
+		0x90, 0x24, 0x64, // note on 36, channel 1
+		0x24, 0x00, // running status: note on velocity 0
+		0xF0, 0x7E, 0x01, 0xF7, // sysex
+		0x25, 0x10, // data without status after sysex is ignored
+		0xB1, 0x40, 0xF8, 0x7F, // sustain down on channel 2, with a clock inside
+		0xC0, 0x05, // program change
+		0x81, 0x26, 0x40, // note off 38, channel 2
+	}
+	var got []domain.MIDIMessage
+	err := readMIDI(bytes.NewReader(stream), func(message domain.MIDIMessage) { got = append(got, message) })
+	if err != io.EOF {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+	want := []domain.MIDIMessage{
+		{Control: domain.MIDINote, Channel: 1, Number: 36, On: true},
+		{Control: domain.MIDINote, Channel: 1, Number: 36},
+		{Control: domain.MIDICC, Channel: 2, Number: 64, On: true},
+		{Control: domain.MIDINote, Channel: 2, Number: 38},
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("unexpected messages:\n got %+v\nwant %+v", got, want)
+	}
+}
@@ -0,0 +1,96 @@
+package issues
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+// GitHubConfig configures issue creation in one GitHub repository.
+type GitHubConfig struct {
+	APIBaseURL string
+	Token      string
+	// Repo is "owner/name".
+	Repo string
+}
+
+// GitHubTracker implements ports.IssueTracker with the GitHub REST API.
+type GitHubTracker struct {
+	cfg  GitHubConfig
+	http *http.Client
+}
+
+func NewGitHubTracker(cfg GitHubConfig) *GitHubTracker {
+	if cfg.APIBaseURL == "" {
+		cfg.APIBaseURL = "https://api.github.com"
+	}
+	return &GitHubTracker{cfg: cfg, http: &http.Client{Timeout: 15 * time.Second}}
+}
+
+func (g *GitHubTracker) Name() string {
+	return "github"
+}
+
+func (g *GitHubTracker) Project() string {
+	return g.cfg.Repo
+}
+
+type githubIssueRequest struct {
+	Title string `json:"title"`
+	Body  string `json:"body,omitempty"`
+}
+
+type githubIssueResponse struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	Message string `json:"message"`
+}
+
+func (g *GitHubTracker) CreateIssue(ctx context.Context, draft domain.IssueDraft) (domain.Issue, error) {
+	if g.cfg.Token == "" || !strings.Contains(g.cfg.Repo, "/") {
+		return domain.Issue{}, fmt.Errorf("github issues need a token and an owner/repo, got repo %q", g.cfg.Repo)
+	}
+	payload, err := json.Marshal(githubIssueRequest{Title: draft.Title, Body: draft.Body})
+	if err != nil {
+		return domain.Issue{}, err
+	}
+
+	endpoint := strings.TrimRight(g.cfg.APIBaseURL, "/") + "/repos/" + g.cfg.Repo + "/issues"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return domain.Issue{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+g.cfg.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.http.Do(req)
+	if err != nil {
+		return domain.Issue{}, fmt.Errorf("github issue request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var decoded githubIssueResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return domain.Issue{}, fmt.Errorf("invalid github issue response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		if decoded.Message == "" {
+			decoded.Message = fmt.Sprintf("status %d", resp.StatusCode)
+		}
+		return domain.Issue{}, fmt.Errorf("github issue creation failed: %s", decoded.Message)
+	}
+	return domain.Issue{
+		Tracker: g.Name(),
+		Key:     g.cfg.Repo + "#" + strconv.Itoa(decoded.Number),
+		Title:   draft.Title,
+		URL:     decoded.HTMLURL,
+	}, nil
+}
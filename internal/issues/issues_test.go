@@ -0,0 +1,100 @@
+package issues
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"coldmic/internal/domain"
+)
+
+func TestGitHubTrackerCreatesIssue(t *testing.T) {
+	t.Parallel()
+
+	var got githubIssueRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repos/acme/app/issues" || r.Header.Get("Authorization") != "Bearer tok" {
+			t.Errorf("unexpected request %s %s auth=%q", r.Method, r.URL.Path, r.Header.Get("Authorization"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"number":42,"html_url":"https://github.com/acme/app/issues/42"}`))
+	}))
+	defer server.Close()
+
+	tracker := NewGitHubTracker(GitHubConfig{APIBaseURL: server.URL, Token: "tok", Repo: "acme/app"})
+	issue, err := tracker.CreateIssue(context.Background(), domain.IssueDraft{Title: "Crash on save", Body: "Steps follow."})
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if got.Title != "Crash on save" || got.Body != "Steps follow." {
+		t.Fatalf("unexpected payload: %+v", got)
+	}
+	if issue.Key != "acme/app#42" || issue.URL != "https://github.com/acme/app/issues/42" {
+		t.Fatalf("unexpected issue: %+v", issue)
+	}
+}
+
+func TestGitHubTrackerReportsErrors(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"Bad credentials"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewGitHubTracker(GitHubConfig{APIBaseURL: server.URL, Token: "x", Repo: "acme/app"}).CreateIssue(context.Background(), domain.IssueDraft{Title: "t"})
+	if err == nil || !strings.Contains(err.Error(), "Bad credentials") {
+		t.Fatalf("expected github error, got %v", err)
+	}
+	if _, err := NewGitHubTracker(GitHubConfig{Token: "x", Repo: "app"}).CreateIssue(context.Background(), domain.IssueDraft{}); err == nil {
+		t.Fatalf("expected repo without owner to be rejected")
+	}
+}
+
+func TestJiraTrackerCreatesIssue(t *testing.T) {
+	t.Parallel()
+
+	var got jiraIssueRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if r.URL.Path != "/rest/api/2/issue" || !ok || user != "me@example.com" || pass != "tok" {
+			t.Errorf("unexpected request %s auth=%v", r.URL.Path, ok)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"key":"OPS-12"}`))
+	}))
+	defer server.Close()
+
+	tracker := NewJiraTracker(JiraConfig{BaseURL: server.URL + "/", Email: "me@example.com", Token: "tok", Project: "OPS"})
+	issue, err := tracker.CreateIssue(context.Background(), domain.IssueDraft{Title: "Rotate certs", Body: "Before Friday."})
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if got.Fields.Project.Key != "OPS" || got.Fields.Summary != "Rotate certs" || got.Fields.IssueType.Name != "Task" {
+		t.Fatalf("unexpected payload: %+v", got)
+	}
+	if issue.Key != "OPS-12" || issue.URL != server.URL+"/browse/OPS-12" {
+		t.Fatalf("unexpected issue: %+v", issue)
+	}
+}
+
+func TestJiraTrackerReportsFieldErrors(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"errorMessages":[],"errors":{"summary":"required","issuetype":"invalid"}}`))
+	}))
+	defer server.Close()
+
+	_, err := NewJiraTracker(JiraConfig{BaseURL: server.URL, Token: "t", Project: "OPS"}).CreateIssue(context.Background(), domain.IssueDraft{})
+	if err == nil || !strings.Contains(err.Error(), "issuetype: invalid; summary: required") {
+		t.Fatalf("expected jira field errors, got %v", err)
+	}
+}
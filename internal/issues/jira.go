@@ -0,0 +1,134 @@
+package issues
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+// JiraConfig configures issue creation in one Jira project.
+type JiraConfig struct {
+	BaseURL string
+	Email   string
+	Token   string
+	Project string
+	// IssueType defaults to "Task".
+	IssueType string
+}
+
+// JiraTracker implements ports.IssueTracker with the Jira REST API v2.
+type JiraTracker struct {
+	cfg  JiraConfig
+	http *http.Client
+}
+
+func NewJiraTracker(cfg JiraConfig) *JiraTracker {
+	if cfg.IssueType == "" {
+		cfg.IssueType = "Task"
+	}
+	return &JiraTracker{cfg: cfg, http: &http.Client{Timeout: 15 * time.Second}}
+}
+
+func (j *JiraTracker) Name() string {
+	return "jira"
+}
+
+func (j *JiraTracker) Project() string {
+	return j.cfg.Project
+}
+
+type jiraIssueRequest struct {
+	Fields jiraIssueFields `json:"fields"`
+}
+
+type jiraIssueFields struct {
+	Project     jiraKey  `json:"project"`
+	Summary     string   `json:"summary"`
+	Description string   `json:"description,omitempty"`
+	IssueType   jiraName `json:"issuetype"`
+}
+
+type jiraKey struct {
+	Key string `json:"key"`
+}
+
+type jiraName struct {
+	Name string `json:"name"`
+}
+
+type jiraIssueResponse struct {
+	Key           string            `json:"key"`
+	ErrorMessages []string          `json:"errorMessages"`
+	Errors        map[string]string `json:"errors"`
+}
+
+func (j *JiraTracker) CreateIssue(ctx context.Context, draft domain.IssueDraft) (domain.Issue, error) {
+	if j.cfg.BaseURL == "" || j.cfg.Project == "" || j.cfg.Token == "" {
+		return domain.Issue{}, fmt.Errorf("jira issues need a base URL, project, and token")
+	}
+	payload, err := json.Marshal(jiraIssueRequest{Fields: jiraIssueFields{
+		Project:     jiraKey{Key: j.cfg.Project},
+		Summary:     draft.Title,
+		Description: draft.Body,
+		IssueType:   jiraName{Name: j.cfg.IssueType},
+	}})
+	if err != nil {
+		return domain.Issue{}, err
+	}
+
+	base := strings.TrimRight(j.cfg.BaseURL, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/rest/api/2/issue", bytes.NewReader(payload))
+	if err != nil {
+		return domain.Issue{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if j.cfg.Email != "" {
+		req.SetBasicAuth(j.cfg.Email, j.cfg.Token)
+	} else {
+		// Jira Data Center personal access tokens use bearer auth.
+		req.Header.Set("Authorization", "Bearer "+j.cfg.Token)
+	}
+
+	resp, err := j.http.Do(req)
+	if err != nil {
+		return domain.Issue{}, fmt.Errorf("jira issue request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var decoded jiraIssueResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return domain.Issue{}, fmt.Errorf("invalid jira issue response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return domain.Issue{}, fmt.Errorf("jira issue creation failed: %s", jiraError(decoded, resp.StatusCode))
+	}
+	return domain.Issue{
+		Tracker: j.Name(),
+		Key:     decoded.Key,
+		Title:   draft.Title,
+		URL:     base + "/browse/" + decoded.Key,
+	}, nil
+}
+
+func jiraError(resp jiraIssueResponse, status int) string {
+	messages := append([]string(nil), resp.ErrorMessages...)
+	fields := make([]string, 0, len(resp.Errors))
+	for field := range resp.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		messages = append(messages, field+": "+resp.Errors[field])
+	}
+	if len(messages) == 0 {
+		return fmt.Sprintf("status %d", status)
+	}
+	return strings.Join(messages, "; ")
+}
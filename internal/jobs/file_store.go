@@ -0,0 +1,125 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"coldmic/internal/domain"
+)
+
+// DefaultFinishedLimit is how many finished jobs are kept for display.
+const DefaultFinishedLimit = 200
+
+// FileStore keeps file job state in a private JSON file, rewritten atomically
+// on every change. Unfinished jobs are always kept; finished ones are trimmed
+// to the most recent DefaultFinishedLimit.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+type jobFile struct {
+	Jobs []domain.FileJob `json:"jobs"`
+}
+
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Save inserts or replaces the job with the same ID.
+func (s *FileStore) Save(_ context.Context, job domain.FileJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i := range file.Jobs {
+		if file.Jobs[i].ID == job.ID {
+			file.Jobs[i] = job
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		file.Jobs = append(file.Jobs, job)
+	}
+	file.Jobs = trimFinished(file.Jobs, DefaultFinishedLimit)
+	return s.save(file)
+}
+
+// List returns saved jobs in submission order.
+func (s *FileStore) List(_ context.Context) ([]domain.FileJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return file.Jobs, nil
+}
+
+func (s *FileStore) load() (jobFile, error) {
+	var file jobFile
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return file, nil
+		}
+		return file, fmt.Errorf("failed to read job file: %w", err)
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return file, fmt.Errorf("invalid job file: %w", err)
+	}
+	return file, nil
+}
+
+func (s *FileStore) save(file jobFile) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create job directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write job file: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// trimFinished drops the oldest finished jobs beyond limit, preserving order.
+func trimFinished(jobs []domain.FileJob, limit int) []domain.FileJob {
+	var finished []int
+	for i, job := range jobs {
+		if job.State.Finished() {
+			finished = append(finished, i)
+		}
+	}
+	if len(finished) <= limit {
+		return jobs
+	}
+	sort.SliceStable(finished, func(a, b int) bool {
+		return jobs[finished[a]].UpdatedAt.Before(jobs[finished[b]].UpdatedAt)
+	})
+	drop := make(map[int]struct{}, len(finished)-limit)
+	for _, i := range finished[:len(finished)-limit] {
+		drop[i] = struct{}{}
+	}
+	kept := make([]domain.FileJob, 0, len(jobs)-len(drop))
+	for i, job := range jobs {
+		if _, ok := drop[i]; !ok {
+			kept = append(kept, job)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,77 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+func TestFileStoreSaveReplacesByID(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state", "jobs.json")
+	store := NewFileStore(path)
+	ctx := context.Background()
+
+	job := domain.FileJob{ID: "a", SourcePath: "/in/a.wav", State: domain.FileJobStateQueued}
+	if err := store.Save(ctx, job); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	job.State = domain.FileJobStateProcessing
+	if err := store.Save(ctx, job); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	if err := store.Save(ctx, domain.FileJob{ID: "b", State: domain.FileJobStateQueued}); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	// A second store instance sees jobs written by the first.
+	got, err := NewFileStore(path).List(ctx)
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "a" || got[0].State != domain.FileJobStateProcessing || got[1].ID != "b" {
+		t.Fatalf("unexpected jobs: %+v", got)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected private job file, got %v %v", info, err)
+	}
+}
+
+func TestFileStoreTrimsOldestFinishedJobs(t *testing.T) {
+	t.Parallel()
+
+	store := NewFileStore(filepath.Join(t.TempDir(), "jobs.json"))
+	ctx := context.Background()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if err := store.Save(ctx, domain.FileJob{ID: "pending", State: domain.FileJobStateQueued, UpdatedAt: base}); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	for i := 0; i < DefaultFinishedLimit+2; i++ {
+		job := domain.FileJob{
+			ID:        fmt.Sprintf("done-%d", i),
+			State:     domain.FileJobStateDone,
+			UpdatedAt: base.Add(time.Duration(i+1) * time.Minute),
+		}
+		if err := store.Save(ctx, job); err != nil {
+			t.Fatalf("save failed: %v", err)
+		}
+	}
+
+	got, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(got) != DefaultFinishedLimit+1 {
+		t.Fatalf("expected %d jobs, got %d", DefaultFinishedLimit+1, len(got))
+	}
+	if got[0].ID != "pending" || got[1].ID != "done-2" {
+		t.Fatalf("expected unfinished job kept and oldest finished dropped, got %s %s", got[0].ID, got[1].ID)
+	}
+}
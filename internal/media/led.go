@@ -0,0 +1,206 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// LEDAuto selects every mic-mute LED the kernel exposes.
+const LEDAuto = "auto"
+
+const defaultLEDClass = "/sys/class/leds"
+
+// RecordingLED implements ports.RecordingHook for LEDs exposed through the
+// kernel's LED class, such as a laptop's mic-mute LED or a keyboard
+// backlight, giving a physical "mic hot" indicator while recording. Writing
+// brightness usually needs a udev rule granting the user access.
+type RecordingLED struct {
+	class string
+	names []string
+	color []int
+
+	mu       sync.Mutex
+	restores []ledState
+}
+
+type ledState struct {
+	dir        string
+	brightness string
+	intensity  string
+}
+
+// NewRecordingLED lights the named LEDs while recording; names are entries
+// of /sys/class/leds or paths to them, and "auto" selects every
+// "*::micmute" LED. color is an optional RRGGBB value applied to
+// multicolor LEDs, such as RGB keyboard backlights.
+func NewRecordingLED(names []string, color string) (*RecordingLED, error) {
+	led := &RecordingLED{class: defaultLEDClass, names: names}
+	if color != "" {
+		rgb, err := parseLEDColor(color)
+		if err != nil {
+			return nil, err
+		}
+		led.color = rgb
+	}
+	return led, nil
+}
+
+// parseLEDColor parses an RRGGBB color, with or without a leading '#'.
+func parseLEDColor(color string) ([]int, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(color), "#")
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 6 || err != nil {
+		return nil, fmt.Errorf("invalid LED color %q (expected RRGGBB)", color)
+	}
+	return []int{int(value >> 16 & 0xff), int(value >> 8 & 0xff), int(value & 0xff)}, nil
+}
+
+func (l *RecordingLED) RecordingStarting(context.Context) error {
+	dirs, err := l.dirs()
+	var errs []error
+	if err != nil {
+		errs = append(errs, err)
+	}
+	var restores []ledState
+	for _, dir := range dirs {
+		state, err := l.light(dir)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		restores = append(restores, state)
+	}
+
+	l.mu.Lock()
+	l.restores = restores
+	l.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+func (l *RecordingLED) RecordingStopped(context.Context) error {
+	l.mu.Lock()
+	restores := l.restores
+	l.restores = nil
+	l.mu.Unlock()
+
+	var errs []error
+	for _, state := range restores {
+		if state.intensity != "" {
+			if err := writeLED(state.dir, "multi_intensity", state.intensity); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if err := writeLED(state.dir, "brightness", state.brightness); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// dirs resolves the configured names to LED directories.
+func (l *RecordingLED) dirs() ([]string, error) {
+	var dirs []string
+	for _, name := range l.names {
+		switch {
+		case name == LEDAuto:
+			matches, err := filepath.Glob(filepath.Join(l.class, "*::micmute"))
+			if err != nil {
+				return nil, err
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no mic-mute LED found in %s", l.class)
+			}
+			sort.Strings(matches)
+			dirs = append(dirs, matches...)
+		case filepath.IsAbs(name):
+			dirs = append(dirs, name)
+		default:
+			dirs = append(dirs, filepath.Join(l.class, name))
+		}
+	}
+	return dirs, nil
+}
+
+// light turns the LED at dir fully on, in the configured color when it is a
+// multicolor LED, and returns its previous state.
+func (l *RecordingLED) light(dir string) (ledState, error) {
+	state := ledState{dir: dir}
+	brightness, err := readLED(dir, "brightness")
+	if err != nil {
+		return ledState{}, err
+	}
+	state.brightness = brightness
+	level, err := readLED(dir, "max_brightness")
+	if err != nil {
+		level = "1"
+	}
+
+	if l.color != nil {
+		if intensity, err := readLED(dir, "multi_intensity"); err == nil {
+			colored, err := l.intensity(dir, strings.Fields(intensity))
+			if err != nil {
+				return ledState{}, err
+			}
+			if err := writeLED(dir, "multi_intensity", colored); err != nil {
+				return ledState{}, err
+			}
+			state.intensity = intensity
+		}
+	}
+	if err := writeLED(dir, "brightness", level); err != nil {
+		if state.intensity != "" {
+			_ = writeLED(dir, "multi_intensity", state.intensity)
+		}
+		return ledState{}, err
+	}
+	return state, nil
+}
+
+// intensity orders the configured color by the LED's multi_index, such as
+// "red green blue"; channels other than those three keep their intensity.
+func (l *RecordingLED) intensity(dir string, current []string) (string, error) {
+	index, err := readLED(dir, "multi_index")
+	if err != nil {
+		return "", err
+	}
+	channels := strings.Fields(index)
+	if len(channels) != len(current) {
+		return "", fmt.Errorf("LED %s has %d channels but %d intensities", filepath.Base(dir), len(channels), len(current))
+	}
+	values := make([]string, len(channels))
+	for i, channel := range channels {
+		switch channel {
+		case "red":
+			values[i] = strconv.Itoa(l.color[0])
+		case "green":
+			values[i] = strconv.Itoa(l.color[1])
+		case "blue":
+			values[i] = strconv.Itoa(l.color[2])
+		default:
+			values[i] = current[i]
+		}
+	}
+	return strings.Join(values, " "), nil
+}
+
+func readLED(dir, attribute string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, attribute))
+	if err != nil {
+		return "", fmt.Errorf("failed to read LED %s: %w", filepath.Base(dir), err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func writeLED(dir, attribute, value string) error {
+	if err := os.WriteFile(filepath.Join(dir, attribute), []byte(value), 0o644); err != nil {
+		return fmt.Errorf("failed to set LED %s: %w", filepath.Base(dir), err)
+	}
+	return nil
+}
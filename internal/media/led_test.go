@@ -0,0 +1,92 @@
+package media
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestLED(t *testing.T, dir string, attributes map[string]string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range attributes {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(value+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readTestLED(t *testing.T, dir, attribute string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, attribute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func TestRecordingLEDLightsAndRestores(t *testing.T) {
+	t.Parallel()
+
+	class := t.TempDir()
+	micmute := filepath.Join(class, "platform::micmute")
+	keyboard := filepath.Join(class, "rgb:kbd_backlight")
+	writeTestLED(t, micmute, map[string]string{"brightness": "0", "max_brightness": "1"})
+	writeTestLED(t, keyboard, map[string]string{
+		"brightness":      "40",
+		"max_brightness":  "255",
+		"multi_index":     "green red blue",
+		"multi_intensity": "255 255 255",
+	})
+
+	led, err := NewRecordingLED([]string{LEDAuto, "rgb:kbd_backlight"}, "#ff2000")
+	if err != nil {
+		t.Fatalf("new LED failed: %v", err)
+	}
+	led.class = class
+	if err := led.RecordingStarting(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if got := readTestLED(t, micmute, "brightness"); got != "1" {
+		t.Fatalf("expected the mic-mute LED on, got %q", got)
+	}
+	if got := readTestLED(t, keyboard, "brightness"); got != "255" {
+		t.Fatalf("expected the backlight at full brightness, got %q", got)
+	}
+	if got := readTestLED(t, keyboard, "multi_intensity"); got != "32 255 0" {
+		t.Fatalf("expected the color in multi_index order, got %q", got)
+	}
+
+	if err := led.RecordingStopped(context.Background()); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if got := readTestLED(t, micmute, "brightness"); got != "0" {
+		t.Fatalf("expected the mic-mute LED restored, got %q", got)
+	}
+	if readTestLED(t, keyboard, "brightness") != "40" || readTestLED(t, keyboard, "multi_intensity") != "255 255 255" {
+		t.Fatalf("expected the backlight restored")
+	}
+}
+
+func TestRecordingLEDReportsMissingLEDs(t *testing.T) {
+	t.Parallel()
+
+	led, err := NewRecordingLED([]string{LEDAuto, "missing"}, "")
+	if err != nil {
+		t.Fatalf("new LED failed: %v", err)
+	}
+	led.class = t.TempDir()
+	if err := led.RecordingStarting(context.Background()); err == nil {
+		t.Fatalf("expected missing LEDs to fail")
+	}
+	if err := led.RecordingStopped(context.Background()); err != nil {
+		t.Fatalf("expected nothing to restore, got %v", err)
+	}
+	if _, err := NewRecordingLED([]string{LEDAuto}, "red"); err == nil {
+		t.Fatalf("expected an invalid color to fail")
+	}
+}
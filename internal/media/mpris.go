@@ -0,0 +1,135 @@
+// Package media pauses desktop media players while dictating.
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	mprisPrefix    = "org.mpris.MediaPlayer2."
+	mprisPath      = "/org/mpris/MediaPlayer2"
+	mprisInterface = "org.mpris.MediaPlayer2.Player"
+)
+
+// defaultMPRISTimeout bounds each D-Bus call so a hung player never holds up
+// a recording.
+const defaultMPRISTimeout = 2 * time.Second
+
+var dbusString = regexp.MustCompile(`string "([^"]*)"`)
+
+// runFunc runs dbus-send with args and returns its output.
+type runFunc func(ctx context.Context, args ...string) (string, error)
+
+// MPRISPauser implements ports.RecordingHook over the session D-Bus. It
+// pauses every MPRIS player that is playing when recording starts and resumes
+// only those players once recording stops.
+type MPRISPauser struct {
+	run     runFunc
+	timeout time.Duration
+
+	mu     sync.Mutex
+	paused []string
+}
+
+func NewMPRISPauser(command string) *MPRISPauser {
+	if command == "" {
+		command = "dbus-send"
+	}
+	return &MPRISPauser{
+		run: func(ctx context.Context, args ...string) (string, error) {
+			output, err := exec.CommandContext(ctx, command, args...).CombinedOutput()
+			if err != nil {
+				return "", fmt.Errorf("%s failed: %w: %s", command, err, strings.TrimSpace(string(output)))
+			}
+			return string(output), nil
+		},
+		timeout: defaultMPRISTimeout,
+	}
+}
+
+func (p *MPRISPauser) RecordingStarting(ctx context.Context) error {
+	players, err := p.players(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list media players: %w", err)
+	}
+
+	var paused []string
+	var errs []error
+	for _, player := range players {
+		if status, err := p.status(ctx, player); err != nil || status != "Playing" {
+			continue
+		}
+		if err := p.call(ctx, player, "Pause"); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		paused = append(paused, player)
+	}
+
+	p.mu.Lock()
+	p.paused = paused
+	p.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+func (p *MPRISPauser) RecordingStopped(ctx context.Context) error {
+	p.mu.Lock()
+	paused := p.paused
+	p.paused = nil
+	p.mu.Unlock()
+
+	var errs []error
+	for _, player := range paused {
+		if err := p.call(ctx, player, "Play"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p *MPRISPauser) players(ctx context.Context) ([]string, error) {
+	output, err := p.dbus(ctx, "--dest=org.freedesktop.DBus", "/org/freedesktop/DBus", "org.freedesktop.DBus.ListNames")
+	if err != nil {
+		return nil, err
+	}
+	var players []string
+	for _, match := range dbusString.FindAllStringSubmatch(output, -1) {
+		if strings.HasPrefix(match[1], mprisPrefix) {
+			players = append(players, match[1])
+		}
+	}
+	return players, nil
+}
+
+func (p *MPRISPauser) status(ctx context.Context, player string) (string, error) {
+	output, err := p.dbus(ctx, "--dest="+player, mprisPath, "org.freedesktop.DBus.Properties.Get",
+		"string:"+mprisInterface, "string:PlaybackStatus")
+	if err != nil {
+		return "", err
+	}
+	match := dbusString.FindStringSubmatch(output)
+	if match == nil {
+		return "", fmt.Errorf("unexpected playback status from %s", player)
+	}
+	return match[1], nil
+}
+
+func (p *MPRISPauser) call(ctx context.Context, player, method string) error {
+	if _, err := p.dbus(ctx, "--dest="+player, mprisPath, mprisInterface+"."+method); err != nil {
+		return fmt.Errorf("failed to %s %s: %w", strings.ToLower(method), strings.TrimPrefix(player, mprisPrefix), err)
+	}
+	return nil
+}
+
+func (p *MPRISPauser) dbus(ctx context.Context, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	return p.run(ctx, append([]string{"--session", "--print-reply"}, args...)...)
+}
@@ -0,0 +1,91 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeBus struct {
+	status map[string]string
+	fail   map[string]bool
+	calls  []string
+}
+
+func (b *fakeBus) run(_ context.Context, args ...string) (string, error) {
+	dest := strings.TrimPrefix(args[2], "--dest=")
+	method := args[4]
+	switch {
+	case strings.HasSuffix(method, "ListNames"):
+		return `method return
+   array [
+      string "org.freedesktop.DBus"
+      string "org.mpris.MediaPlayer2.spotify"
+      string "org.mpris.MediaPlayer2.firefox.instance_1_42"
+      string "org.mpris.MediaPlayer2.vlc"
+   ]`, nil
+	case strings.HasSuffix(method, "Properties.Get"):
+		return `method return
+   variant       string "` + b.status[dest] + `"`, nil
+	default:
+		b.calls = append(b.calls, strings.TrimPrefix(dest, mprisPrefix)+"."+strings.TrimPrefix(method, mprisInterface+"."))
+		if b.fail[dest] {
+			return "", errors.New("no reply")
+		}
+		return "method return", nil
+	}
+}
+
+func newTestPauser(bus *fakeBus) *MPRISPauser {
+	return &MPRISPauser{run: bus.run, timeout: time.Second}
+}
+
+func TestMPRISPauserResumesOnlyPausedPlayers(t *testing.T) {
+	t.Parallel()
+
+	bus := &fakeBus{status: map[string]string{
+		"org.mpris.MediaPlayer2.spotify":               "Playing",
+		"org.mpris.MediaPlayer2.firefox.instance_1_42": "Paused",
+		"org.mpris.MediaPlayer2.vlc":                   "Playing",
+	}}
+	pauser := newTestPauser(bus)
+
+	if err := pauser.RecordingStarting(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if err := pauser.RecordingStopped(context.Background()); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if err := pauser.RecordingStopped(context.Background()); err != nil {
+		t.Fatalf("second stop failed: %v", err)
+	}
+
+	want := "spotify.Pause vlc.Pause spotify.Play vlc.Play"
+	if got := strings.Join(bus.calls, " "); got != want {
+		t.Fatalf("unexpected calls %q, want %q", got, want)
+	}
+}
+
+func TestMPRISPauserReportsFailedPause(t *testing.T) {
+	t.Parallel()
+
+	bus := &fakeBus{
+		status: map[string]string{"org.mpris.MediaPlayer2.spotify": "Playing", "org.mpris.MediaPlayer2.vlc": "Playing"},
+		fail:   map[string]bool{"org.mpris.MediaPlayer2.vlc": true},
+	}
+	pauser := newTestPauser(bus)
+
+	err := pauser.RecordingStarting(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "failed to pause vlc") {
+		t.Fatalf("expected pause failure, got %v", err)
+	}
+	bus.calls = nil
+	if err := pauser.RecordingStopped(context.Background()); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if strings.Join(bus.calls, " ") != "spotify.Play" {
+		t.Fatalf("expected only the paused player to resume, got %v", bus.calls)
+	}
+}
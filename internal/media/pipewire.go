@@ -0,0 +1,201 @@
+package media
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Stream duck modes for other applications' playback.
+const (
+	DuckModeDuck = "duck"
+	DuckModeMute = "mute"
+)
+
+// defaultDuckTimeout bounds each pactl call.
+const defaultDuckTimeout = 2 * time.Second
+
+// StreamDucker implements ports.RecordingHook for PipeWire (through its
+// PulseAudio interface). While recording it lowers or mutes other
+// applications' playback streams and mutes their capture streams, then
+// restores exactly the streams it changed.
+type StreamDucker struct {
+	run     runFunc
+	mode    string
+	level   int
+	timeout time.Duration
+
+	mu      sync.Mutex
+	changed []streamChange
+}
+
+type streamChange struct {
+	kind    string
+	index   int
+	muted   bool
+	volumes []string
+}
+
+// NewStreamDucker ducks playback to level percent of its volume, or mutes
+// it when mode is "mute".
+func NewStreamDucker(command, mode string, level int) *StreamDucker {
+	if command == "" {
+		command = "pactl"
+	}
+	if level < 0 || level > 100 {
+		level = 30
+	}
+	return &StreamDucker{
+		run: func(ctx context.Context, args ...string) (string, error) {
+			output, err := exec.CommandContext(ctx, command, args...).CombinedOutput()
+			if err != nil {
+				return "", fmt.Errorf("%s %s failed: %w: %s", command, args[0], err, strings.TrimSpace(string(output)))
+			}
+			return string(output), nil
+		},
+		mode:    mode,
+		level:   level,
+		timeout: defaultDuckTimeout,
+	}
+}
+
+type pulseStream struct {
+	Index      int                    `json:"index"`
+	Mute       bool                   `json:"mute"`
+	ChannelMap string                 `json:"channel_map"`
+	Volume     map[string]pulseVolume `json:"volume"`
+	Properties map[string]string      `json:"properties"`
+}
+
+type pulseVolume struct {
+	Value int `json:"value"`
+}
+
+// volumes returns the raw channel volumes in channel-map order.
+func (s pulseStream) volumes() []int {
+	var out []int
+	for _, channel := range strings.Split(s.ChannelMap, ",") {
+		if volume, ok := s.Volume[strings.TrimSpace(channel)]; ok {
+			out = append(out, volume.Value)
+		}
+	}
+	if len(out) != len(s.Volume) {
+		out = out[:0]
+		for _, volume := range s.Volume {
+			out = append(out, volume.Value)
+		}
+	}
+	return out
+}
+
+func (d *StreamDucker) RecordingStarting(ctx context.Context) error {
+	var changed []streamChange
+	var errs []error
+
+	playback, err := d.streams(ctx, "sink-inputs")
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, stream := range playback {
+		if stream.Mute {
+			continue
+		}
+		change := streamChange{kind: "sink-input", index: stream.Index}
+		var err error
+		if d.mode == DuckModeMute {
+			err = d.pactl(ctx, "set-sink-input-mute", strconv.Itoa(stream.Index), "1")
+		} else {
+			var ducked []string
+			for _, volume := range stream.volumes() {
+				change.volumes = append(change.volumes, strconv.Itoa(volume))
+				ducked = append(ducked, strconv.Itoa(volume*d.level/100))
+			}
+			if len(ducked) == 0 {
+				continue
+			}
+			err = d.pactl(ctx, append([]string{"set-sink-input-volume", strconv.Itoa(stream.Index)}, ducked...)...)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		changed = append(changed, change)
+	}
+
+	capture, err := d.streams(ctx, "source-outputs")
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, stream := range capture {
+		if stream.Mute || isMonitor(stream) {
+			continue
+		}
+		if err := d.pactl(ctx, "set-source-output-mute", strconv.Itoa(stream.Index), "1"); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		changed = append(changed, streamChange{kind: "source-output", index: stream.Index})
+	}
+
+	d.mu.Lock()
+	d.changed = changed
+	d.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+func (d *StreamDucker) RecordingStopped(ctx context.Context) error {
+	d.mu.Lock()
+	changed := d.changed
+	d.changed = nil
+	d.mu.Unlock()
+
+	var errs []error
+	for _, change := range changed {
+		index := strconv.Itoa(change.index)
+		var err error
+		if len(change.volumes) > 0 {
+			err = d.pactl(ctx, append([]string{"set-" + change.kind + "-volume", index}, change.volumes...)...)
+		} else {
+			err = d.pactl(ctx, "set-"+change.kind+"-mute", index, "0")
+		}
+		// Streams that ended while recording have nothing left to restore.
+		if err != nil && !strings.Contains(err.Error(), "No such entity") {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (d *StreamDucker) streams(ctx context.Context, kind string) ([]pulseStream, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+	output, err := d.run(ctx, "-f", "json", "list", kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", kind, err)
+	}
+	var streams []pulseStream
+	if err := json.Unmarshal([]byte(output), &streams); err != nil {
+		return nil, fmt.Errorf("invalid pactl %s output: %w", kind, err)
+	}
+	return streams, nil
+}
+
+func (d *StreamDucker) pactl(ctx context.Context, args ...string) error {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+	_, err := d.run(ctx, args...)
+	return err
+}
+
+// isMonitor reports capture streams that record playback, such as level
+// meters, which muting would not help.
+func isMonitor(stream pulseStream) bool {
+	return strings.HasSuffix(stream.Properties["target.object"], ".monitor") ||
+		stream.Properties["stream.capture.sink"] == "true"
+}
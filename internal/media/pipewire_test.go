@@ -0,0 +1,85 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakePactl struct {
+	sinkInputs    string
+	sourceOutputs string
+	missing       map[string]bool
+	calls         []string
+}
+
+func (p *fakePactl) run(_ context.Context, args ...string) (string, error) {
+	if args[0] == "-f" {
+		if args[3] == "sink-inputs" {
+			return p.sinkInputs, nil
+		}
+		return p.sourceOutputs, nil
+	}
+	call := strings.Join(args, " ")
+	p.calls = append(p.calls, call)
+	if p.missing[args[1]] {
+		return "", errors.New("Failure: No such entity")
+	}
+	return "", nil
+}
+
+const testSinkInputs = `[
+	{"index":12,"mute":false,"channel_map":"front-left,front-right","volume":{"front-left":{"value":65536},"front-right":{"value":32768}},"properties":{"application.name":"Spotify"}},
+	{"index":13,"mute":true,"channel_map":"mono","volume":{"mono":{"value":65536}},"properties":{}}
+]`
+
+const testSourceOutputs = `[
+	{"index":40,"mute":false,"channel_map":"mono","volume":{"mono":{"value":65536}},"properties":{"application.name":"Firefox"}},
+	{"index":41,"mute":false,"channel_map":"mono","volume":{"mono":{"value":65536}},"properties":{"target.object":"alsa_output.pci.analog-stereo.monitor"}}
+]`
+
+func newTestDucker(pactl *fakePactl, mode string) *StreamDucker {
+	return &StreamDucker{run: pactl.run, mode: mode, level: 25, timeout: time.Second}
+}
+
+func TestStreamDuckerDucksAndRestores(t *testing.T) {
+	t.Parallel()
+
+	pactl := &fakePactl{sinkInputs: testSinkInputs, sourceOutputs: testSourceOutputs}
+	ducker := newTestDucker(pactl, DuckModeDuck)
+	if err := ducker.RecordingStarting(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if err := ducker.RecordingStopped(context.Background()); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+
+	want := []string{
+		"set-sink-input-volume 12 16384 8192",
+		"set-source-output-mute 40 1",
+		"set-sink-input-volume 12 65536 32768",
+		"set-source-output-mute 40 0",
+	}
+	if strings.Join(pactl.calls, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected pactl calls:\n%s", strings.Join(pactl.calls, "\n"))
+	}
+}
+
+func TestStreamDuckerMutesAndIgnoresEndedStreams(t *testing.T) {
+	t.Parallel()
+
+	pactl := &fakePactl{sinkInputs: testSinkInputs, sourceOutputs: `[]`}
+	ducker := newTestDucker(pactl, DuckModeMute)
+	if err := ducker.RecordingStarting(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	pactl.missing = map[string]bool{"12": true}
+	if err := ducker.RecordingStopped(context.Background()); err != nil {
+		t.Fatalf("expected ended stream to be skipped, got %v", err)
+	}
+	if strings.Join(pactl.calls, "|") != "set-sink-input-mute 12 1|set-sink-input-mute 12 0" {
+		t.Fatalf("unexpected pactl calls: %v", pactl.calls)
+	}
+}
@@ -0,0 +1,249 @@
+// Package models downloads and stores model files for local providers.
+package models
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+const whisperBaseURL = "https://huggingface.co/ggerganov/whisper.cpp/resolve/main/"
+
+// DefaultCatalog lists the whisper.cpp models offered without configuration,
+// each pinned to the SHA-256 of the published file.
+var DefaultCatalog = []domain.LocalModel{
+	{Name: "tiny.en", Kind: "whisper", URL: whisperBaseURL + "ggml-tiny.en.bin", SHA256: "921e4cf8686fdd993dcd081a5da5b6c365bfde1162e72b08d75ac75289920b1f"},
+	{Name: "base.en", Kind: "whisper", URL: whisperBaseURL + "ggml-base.en.bin", SHA256: "a03779c86df3323075f5e796cb2ce5029f00ec8869eee3fdfb897afe36c6d002"},
+	{Name: "small.en", Kind: "whisper", URL: whisperBaseURL + "ggml-small.en.bin", SHA256: "c6138d6d58ecc8322097e0f987c32f1be8bb0a18532a3f88f734d1bbf9c41e5d"},
+	{Name: "medium.en", Kind: "whisper", URL: whisperBaseURL + "ggml-medium.en.bin", SHA256: "cc37e93478338ec7700281a7ac30a10128929eb8f427dda2e865faa8f6da4356"},
+	{Name: "large-v3-turbo", Kind: "whisper", URL: whisperBaseURL + "ggml-large-v3-turbo.bin", SHA256: "1fc70f774d38eb169993ac391eea357ef47c88757ef72ee5943879b7e8e2bc69"},
+}
+
+// progressInterval throttles progress events for fast downloads.
+const progressInterval = 250 * time.Millisecond
+
+var sha256Hex = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Manager lists, downloads, and deletes models in one directory. Every
+// download is verified against a SHA-256 checksum before it is installed.
+type Manager struct {
+	dir      string
+	catalog  []domain.LocalModel
+	client   *http.Client
+	progress ports.ModelProgressSink
+
+	mu          sync.Mutex
+	downloading map[string]bool
+}
+
+func NewManager(dir string, catalog []domain.LocalModel, progress ports.ModelProgressSink) *Manager {
+	return &Manager{
+		dir:         dir,
+		catalog:     catalog,
+		client:      &http.Client{},
+		progress:    progress,
+		downloading: make(map[string]bool),
+	}
+}
+
+// LoadCatalog reads extra or overriding catalog entries, such as wake-word
+// models, from a JSON array at path and merges them over base by name. Every
+// entry must pin its sha256.
+func LoadCatalog(path string, base []domain.LocalModel) ([]domain.LocalModel, error) {
+	catalog := append([]domain.LocalModel(nil), base...)
+	if path == "" {
+		return catalog, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model catalog: %w", err)
+	}
+	var extra []domain.LocalModel
+	if err := json.Unmarshal(data, &extra); err != nil {
+		return nil, fmt.Errorf("invalid model catalog %s: %w", path, err)
+	}
+	for _, model := range extra {
+		if model.Name == "" || model.URL == "" {
+			return nil, fmt.Errorf("invalid model catalog %s: every model needs a name and url", path)
+		}
+		model.SHA256 = strings.ToLower(model.SHA256)
+		if model.SHA256 == "" {
+			return nil, fmt.Errorf("invalid model catalog %s: %s has no sha256", path, model.Name)
+		}
+		if !sha256Hex.MatchString(model.SHA256) {
+			return nil, fmt.Errorf("invalid model catalog %s: %s has a malformed sha256", path, model.Name)
+		}
+		replaced := false
+		for i := range catalog {
+			if catalog[i].Name == model.Name {
+				catalog[i], replaced = model, true
+			}
+		}
+		if !replaced {
+			catalog = append(catalog, model)
+		}
+	}
+	return catalog, nil
+}
+
+// List returns the catalog with each model's installed state.
+func (m *Manager) List() []domain.LocalModel {
+	models := make([]domain.LocalModel, 0, len(m.catalog))
+	for _, model := range m.catalog {
+		model.Path = m.path(model)
+		if _, err := os.Stat(model.Path); err == nil {
+			model.Installed = true
+		} else {
+			model.Path = ""
+		}
+		models = append(models, model)
+	}
+	return models
+}
+
+// Path returns where the named model is, or would be, installed.
+func (m *Manager) Path(name string) (string, error) {
+	model, err := m.find(name)
+	if err != nil {
+		return "", err
+	}
+	return m.path(model), nil
+}
+
+// Download fetches the named model, reporting progress, and installs it once
+// it matches the catalog's SHA-256. Checksums announced by the server are
+// not trusted, since a compromised mirror would announce its own.
+func (m *Manager) Download(ctx context.Context, name string) (domain.LocalModel, error) {
+	model, err := m.find(name)
+	if err != nil {
+		return domain.LocalModel{}, err
+	}
+	if model.SHA256 == "" {
+		return domain.LocalModel{}, fmt.Errorf("model %s has no checksum to verify against; add sha256 to the model catalog", name)
+	}
+
+	m.mu.Lock()
+	if m.downloading[name] {
+		m.mu.Unlock()
+		return domain.LocalModel{}, fmt.Errorf("model %s is already downloading", name)
+	}
+	m.downloading[name] = true
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		delete(m.downloading, name)
+		m.mu.Unlock()
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, model.URL, nil)
+	if err != nil {
+		return domain.LocalModel{}, err
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return domain.LocalModel{}, fmt.Errorf("failed to download model %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return domain.LocalModel{}, fmt.Errorf("failed to download model %s: %s", name, resp.Status)
+	}
+
+	if err := os.MkdirAll(m.dir, 0o700); err != nil {
+		return domain.LocalModel{}, fmt.Errorf("failed to create model directory: %w", err)
+	}
+	partial, err := os.CreateTemp(m.dir, ".download-*")
+	if err != nil {
+		return domain.LocalModel{}, fmt.Errorf("failed to create model file: %w", err)
+	}
+	defer os.Remove(partial.Name())
+
+	hash := sha256.New()
+	counter := &progressWriter{name: name, total: resp.ContentLength, sink: m.progress}
+	_, err = io.Copy(io.MultiWriter(partial, hash, counter), resp.Body)
+	if closeErr := partial.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return domain.LocalModel{}, fmt.Errorf("failed to download model %s: %w", name, err)
+	}
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != model.SHA256 {
+		return domain.LocalModel{}, fmt.Errorf("model %s failed verification: sha256 %s, expected %s", name, actual, model.SHA256)
+	}
+
+	model.Path = m.path(model)
+	if err := os.Rename(partial.Name(), model.Path); err != nil {
+		return domain.LocalModel{}, fmt.Errorf("failed to install model %s: %w", name, err)
+	}
+	model.Installed = true
+	counter.finish()
+	debuglog.Printf("model installed name=%s path=%s bytes=%d", name, model.Path, counter.written)
+	return model, nil
+}
+
+// Delete removes the named model from disk.
+func (m *Manager) Delete(name string) error {
+	model, err := m.find(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(m.path(model)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete model %s: %w", name, err)
+	}
+	return nil
+}
+
+func (m *Manager) find(name string) (domain.LocalModel, error) {
+	for _, model := range m.catalog {
+		if model.Name == name {
+			return model, nil
+		}
+	}
+	return domain.LocalModel{}, domain.ErrModelNotFound
+}
+
+// path keeps the download's file name, such as ggml-base.en.bin, so models
+// are easy to find and use from other tools.
+func (m *Manager) path(model domain.LocalModel) string {
+	file := path.Base(strings.SplitN(model.URL, "?", 2)[0])
+	if file == "" || file == "." || file == "/" {
+		file = model.Name
+	}
+	return filepath.Join(m.dir, file)
+}
+
+type progressWriter struct {
+	name    string
+	total   int64
+	written int64
+	sink    ports.ModelProgressSink
+	last    time.Time
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.written += int64(len(p))
+	if now := time.Now(); now.Sub(w.last) >= progressInterval {
+		w.last = now
+		w.sink.ModelProgress(domain.ModelProgress{Name: w.name, Downloaded: w.written, Total: w.total})
+	}
+	return len(p), nil
+}
+
+func (w *progressWriter) finish() {
+	w.sink.ModelProgress(domain.ModelProgress{Name: w.name, Downloaded: w.written, Total: w.total, Done: true})
+}
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"coldmic/internal/domain"
+)
+
+type recordingProgress struct {
+	mu     sync.Mutex
+	events []domain.ModelProgress
+}
+
+func (r *recordingProgress) ModelProgress(progress domain.ModelProgress) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, progress)
+}
+
+// newModelServer serves body, announcing announced as its checksum the way
+// Hugging Face does on the redirect to its CDN.
+func newModelServer(t *testing.T, body string, announced string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/resolve/ggml-test.bin":
+			w.Header().Set("X-Linked-Etag", `"`+announced+`"`)
+			http.Redirect(w, r, "/cdn/ggml-test.bin", http.StatusFound)
+		case "/cdn/ggml-test.bin", "/plain/wake.onnx":
+			_, _ = w.Write([]byte(body))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestManagerDownloadsVerifiesAndDeletes(t *testing.T) {
+	t.Parallel()
+
+	sum := checksum("model weights")
+	server := newModelServer(t, "model weights", sum)
+	dir := filepath.Join(t.TempDir(), "models")
+	progress := &recordingProgress{}
+	manager := NewManager(dir, []domain.LocalModel{{Name: "test", Kind: "whisper", URL: server.URL + "/resolve/ggml-test.bin", SHA256: sum}}, progress)
+
+	if models := manager.List(); len(models) != 1 || models[0].Installed {
+		t.Fatalf("expected uninstalled model, got %+v", models)
+	}
+	model, err := manager.Download(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	if !model.Installed || model.Path != filepath.Join(dir, "ggml-test.bin") || model.SHA256 != sum {
+		t.Fatalf("unexpected model: %+v", model)
+	}
+	if data, _ := os.ReadFile(model.Path); string(data) != "model weights" {
+		t.Fatalf("unexpected model contents %q", data)
+	}
+	last := progress.events[len(progress.events)-1]
+	if !last.Done || last.Downloaded != int64(len("model weights")) {
+		t.Fatalf("unexpected final progress: %+v", last)
+	}
+	if models := manager.List(); !models[0].Installed {
+		t.Fatalf("expected installed model, got %+v", models)
+	}
+
+	if err := manager.Delete("test"); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if _, err := os.Stat(model.Path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected model file removed, got %v", err)
+	}
+	if err := manager.Delete("missing"); !errors.Is(err, domain.ErrModelNotFound) {
+		t.Fatalf("expected ErrModelNotFound, got %v", err)
+	}
+}
+
+func TestManagerRejectsUnverifiedDownloads(t *testing.T) {
+	t.Parallel()
+
+	// The mirror announces the checksum of its tampered file.
+	server := newModelServer(t, "tampered", checksum("tampered"))
+	dir := t.TempDir()
+	manager := NewManager(dir, []domain.LocalModel{
+		{Name: "pinned", URL: server.URL + "/plain/wake.onnx", SHA256: strings.Repeat("0", 64)},
+		{Name: "mirrored", URL: server.URL + "/resolve/ggml-test.bin", SHA256: checksum("model weights")},
+		{Name: "unpinned", URL: server.URL + "/resolve/ggml-test.bin"},
+	}, &recordingProgress{})
+
+	if _, err := manager.Download(context.Background(), "pinned"); err == nil || !strings.Contains(err.Error(), "failed verification") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+	if _, err := manager.Download(context.Background(), "mirrored"); err == nil || !strings.Contains(err.Error(), "failed verification") {
+		t.Fatalf("expected the announced checksum to be ignored, got %v", err)
+	}
+	if _, err := manager.Download(context.Background(), "unpinned"); err == nil || !strings.Contains(err.Error(), "no checksum") {
+		t.Fatalf("expected missing checksum error, got %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf("expected no files left behind, got %v", entries)
+	}
+}
+
+func TestLoadCatalogMergesEntries(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	catalog := `[
+		{"name":"base.en","kind":"whisper","url":"https://mirror.example.com/ggml-base.en.bin","sha256":"` + strings.Repeat("cd", 32) + `"},
+		{"name":"hey-coldmic","kind":"wake-word","url":"https://example.com/hey.onnx","sha256":"` + strings.Repeat("AB", 32) + `"}
+	]`
+	if err := os.WriteFile(path, []byte(catalog), 0o600); err != nil {
+		t.Fatalf("write catalog: %v", err)
+	}
+
+	models, err := LoadCatalog(path, DefaultCatalog)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if len(models) != len(DefaultCatalog)+1 {
+		t.Fatalf("expected one added model, got %d", len(models))
+	}
+	for _, model := range models {
+		if model.Name == "base.en" && !strings.HasPrefix(model.URL, "https://mirror.example.com/") {
+			t.Fatalf("expected base.en to be overridden, got %+v", model)
+		}
+	}
+	if last := models[len(models)-1]; last.Kind != "wake-word" || last.SHA256 != strings.Repeat("ab", 32) {
+		t.Fatalf("unexpected added model: %+v", last)
+	}
+
+	if err := os.WriteFile(path, []byte(`[{"name":"x","url":"u","sha256":"nope"}]`), 0o600); err != nil {
+		t.Fatalf("write catalog: %v", err)
+	}
+	if _, err := LoadCatalog(path, nil); err == nil {
+		t.Fatalf("expected malformed checksum error")
+	}
+	if err := os.WriteFile(path, []byte(`[{"name":"x","url":"u"}]`), 0o600); err != nil {
+		t.Fatalf("write catalog: %v", err)
+	}
+	if _, err := LoadCatalog(path, nil); err == nil || !strings.Contains(err.Error(), "no sha256") {
+		t.Fatalf("expected a missing checksum error, got %v", err)
+	}
+}
+
+func TestDefaultCatalogPinsChecksums(t *testing.T) {
+	t.Parallel()
+
+	for _, model := range DefaultCatalog {
+		if !sha256Hex.MatchString(model.SHA256) {
+			t.Fatalf("expected %s to pin a sha256, got %q", model.Name, model.SHA256)
+		}
+	}
+}
+
+func checksum(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,59 @@
+// Package netproxy routes connections to transcription providers through
+// an HTTP or SOCKS5 proxy.
+package netproxy
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/gorilla/websocket"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Parse checks an explicit proxy URL such as http://proxy.corp:3128. Only
+// http and socks5 proxies work for both websocket and HTTP connections.
+func Parse(raw string) (*url.URL, error) {
+	proxyURL, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	if proxyURL.Host == "" || (proxyURL.Scheme != "http" && proxyURL.Scheme != "socks5") {
+		return nil, fmt.Errorf("invalid proxy URL %q: expected http://host:port or socks5://host:port", raw)
+	}
+	return proxyURL, nil
+}
+
+// Func returns the proxy for a request. An explicit proxyURL is used for
+// every host outside NO_PROXY; without one HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY apply. Loopback hosts are never proxied.
+func Func(proxyURL string) func(*http.Request) (*url.URL, error) {
+	if proxyURL == "" {
+		return http.ProxyFromEnvironment
+	}
+	noProxy := os.Getenv("NO_PROXY")
+	if noProxy == "" {
+		noProxy = os.Getenv("no_proxy")
+	}
+	proxy := (&httpproxy.Config{HTTPProxy: proxyURL, HTTPSProxy: proxyURL, NoProxy: noProxy}).ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}
+}
+
+// Dialer returns a websocket dialer that connects through the proxy Func
+// picks.
+func Dialer(proxyURL string) *websocket.Dialer {
+	dialer := *websocket.DefaultDialer
+	dialer.Proxy = Func(proxyURL)
+	return &dialer
+}
+
+// Transport returns an HTTP transport that connects through the proxy Func
+// picks.
+func Transport(proxyURL string) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = Func(proxyURL)
+	return transport
+}
@@ -0,0 +1,51 @@
+package netproxy
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestFuncUsesExplicitProxyOutsideNoProxy(t *testing.T) {
+	t.Setenv("NO_PROXY", "internal.corp")
+
+	proxy := Func("http://proxy.corp:3128")
+	for host, want := range map[string]string{
+		"wss://api.deepgram.com/v1/listen": "http://proxy.corp:3128",
+		"https://stt.internal.corp/v1":     "",
+		"ws://127.0.0.1:8080/stream":       "",
+	} {
+		req, err := http.NewRequest(http.MethodGet, host, nil)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		// The websocket dialer looks proxies up under the http(s) scheme.
+		switch req.URL.Scheme {
+		case "wss":
+			req.URL.Scheme = "https"
+		case "ws":
+			req.URL.Scheme = "http"
+		}
+		got, err := proxy(req)
+		if err != nil {
+			t.Fatalf("proxy failed for %s: %v", host, err)
+		}
+		if (got == nil && want != "") || (got != nil && got.String() != want) {
+			t.Fatalf("expected proxy %q for %s, got %v", want, host, got)
+		}
+	}
+}
+
+func TestParseRejectsUnsupportedProxies(t *testing.T) {
+	t.Parallel()
+
+	for _, raw := range []string{"http://proxy.corp:3128", "socks5://127.0.0.1:1080"} {
+		if _, err := Parse(raw); err != nil {
+			t.Fatalf("expected %s to parse, got %v", raw, err)
+		}
+	}
+	for _, raw := range []string{"proxy.corp:3128", "https://proxy.corp", "ftp://proxy.corp"} {
+		if _, err := Parse(raw); err == nil {
+			t.Fatalf("expected %s to be rejected", raw)
+		}
+	}
+}
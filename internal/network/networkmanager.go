@@ -0,0 +1,78 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"coldmic/internal/domain"
+)
+
+// NetworkManager connectivity and metered values, see NMConnectivityState
+// and NMMetered in the NetworkManager D-Bus API.
+const (
+	nmConnectivityFull = 4
+	nmMeteredYes       = 1
+	nmMeteredGuessYes  = 3
+)
+
+// NetworkManagerMonitor reads connectivity and metered state from
+// NetworkManager over the system D-Bus using busctl.
+type NetworkManagerMonitor struct {
+	command string
+}
+
+func NewNetworkManagerMonitor(command string) *NetworkManagerMonitor {
+	if command == "" {
+		command = "busctl"
+	}
+	return &NetworkManagerMonitor{command: command}
+}
+
+// State treats anything short of full connectivity (none, captive portal,
+// limited) as offline, since providers would be unreachable.
+func (m *NetworkManagerMonitor) State(ctx context.Context) (domain.NetworkState, error) {
+	connectivity, err := m.property(ctx, "Connectivity")
+	if err != nil {
+		return domain.NetworkState{}, err
+	}
+	metered, err := m.property(ctx, "Metered")
+	if err != nil {
+		return domain.NetworkState{}, err
+	}
+	return domain.NetworkState{
+		Online:  connectivity == nmConnectivityFull,
+		Metered: metered == nmMeteredYes || metered == nmMeteredGuessYes,
+	}, nil
+}
+
+func (m *NetworkManagerMonitor) property(ctx context.Context, name string) (int, error) {
+	cmd := exec.CommandContext(ctx, m.command,
+		"--system",
+		"get-property",
+		"org.freedesktop.NetworkManager",
+		"/org/freedesktop/NetworkManager",
+		"org.freedesktop.NetworkManager",
+		name,
+	)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return 0, fmt.Errorf("failed to read NetworkManager %s: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	// busctl prints the D-Bus signature followed by the value, e.g. "u 4".
+	fields := strings.Fields(stdout.String())
+	if len(fields) != 2 || fields[0] != "u" {
+		return 0, fmt.Errorf("unexpected NetworkManager %s value %q", name, strings.TrimSpace(stdout.String()))
+	}
+	value, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, fmt.Errorf("unexpected NetworkManager %s value %q", name, fields[1])
+	}
+	return value, nil
+}
@@ -0,0 +1,59 @@
+package network
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNetworkManagerMonitorReadsConnectivityAndMetered(t *testing.T) {
+	script := writeScript(t, "busctl.sh", `#!/usr/bin/env bash
+case "$6" in
+  Connectivity) echo "u 4" ;;
+  Metered) echo "u 3" ;;
+esac
+`)
+
+	state, err := NewNetworkManagerMonitor(script).State(context.Background())
+	if err != nil {
+		t.Fatalf("state failed: %v", err)
+	}
+	if !state.Online || !state.Metered {
+		t.Fatalf("expected online metered state, got %+v", state)
+	}
+}
+
+func TestNetworkManagerMonitorTreatsPortalAsOffline(t *testing.T) {
+	script := writeScript(t, "busctl-portal.sh", `#!/usr/bin/env bash
+case "$6" in
+  Connectivity) echo "u 2" ;;
+  Metered) echo "u 4" ;;
+esac
+`)
+
+	state, err := NewNetworkManagerMonitor(script).State(context.Background())
+	if err != nil {
+		t.Fatalf("state failed: %v", err)
+	}
+	if state.Online || state.Metered {
+		t.Fatalf("expected offline unmetered state, got %+v", state)
+	}
+}
+
+func TestNetworkManagerMonitorReportsFailure(t *testing.T) {
+	script := writeScript(t, "busctl-fail.sh", "#!/usr/bin/env bash\necho 'Failed to connect to bus' 1>&2\nexit 1\n")
+
+	if _, err := NewNetworkManagerMonitor(script).State(context.Background()); err == nil {
+		t.Fatalf("expected busctl failure")
+	}
+}
+
+func writeScript(t *testing.T, name string, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o700); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+	return path
+}
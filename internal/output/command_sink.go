@@ -0,0 +1,34 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// CommandSink pipes text to a command's stdin, such as `cliphist store`. It
+// satisfies ports.Clipboard so it can stand in wherever text is copied.
+type CommandSink struct {
+	args []string
+}
+
+func NewCommandSink(args []string) *CommandSink {
+	return &CommandSink{args: args}
+}
+
+func (s *CommandSink) SetText(ctx context.Context, text string) error {
+	if len(s.args) == 0 {
+		return errors.New("no output command configured")
+	}
+	cmd := exec.CommandContext(ctx, s.args[0], s.args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", s.args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
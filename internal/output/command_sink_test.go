@@ -0,0 +1,50 @@
+package output
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommandSinkPipesTextToStdin(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out.txt")
+	script := writeScript(t, "store.sh", "#!/usr/bin/env bash\ncat >> \"$1\"\necho >> \"$1\"\n")
+
+	sink := NewCommandSink([]string{script, out})
+	for _, text := range []string{"first", "second"} {
+		if err := sink.SetText(context.Background(), text); err != nil {
+			t.Fatalf("set text failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if string(data) != "first\nsecond\n" {
+		t.Fatalf("unexpected command input: %q", string(data))
+	}
+}
+
+func TestCommandSinkReportsFailure(t *testing.T) {
+	script := writeScript(t, "fail.sh", "#!/usr/bin/env bash\necho 'database locked' 1>&2\nexit 1\n")
+
+	err := NewCommandSink([]string{script}).SetText(context.Background(), "x")
+	if err == nil || !strings.Contains(err.Error(), "database locked") {
+		t.Fatalf("expected command error with stderr, got %v", err)
+	}
+	if err := NewCommandSink(nil).SetText(context.Background(), "x"); err == nil {
+		t.Fatalf("expected missing command error")
+	}
+}
+
+func writeScript(t *testing.T, name string, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o700); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+	return path
+}
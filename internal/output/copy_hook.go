@@ -0,0 +1,68 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+// defaultCopyHookTimeout stops a hung post-copy command.
+const defaultCopyHookTimeout = 30 * time.Second
+
+// CopyHook runs a command after each copy with the copied text on stdin. The
+// event metadata is in COLDMIC_COPY_EVENT as JSON, with the common fields
+// also in COLDMIC_TEXT, COLDMIC_RAW, COLDMIC_SESSION_ID, COLDMIC_TARGET, and
+// COLDMIC_FORM.
+type CopyHook struct {
+	args    []string
+	timeout time.Duration
+}
+
+// NewCopyHook runs args after each copy, stopping it after timeout; a
+// non-positive timeout uses the 30 second default.
+func NewCopyHook(args []string, timeout time.Duration) *CopyHook {
+	if timeout <= 0 {
+		timeout = defaultCopyHookTimeout
+	}
+	return &CopyHook{args: args, timeout: timeout}
+}
+
+func (h *CopyHook) TranscriptCopied(ctx context.Context, event domain.CopyEvent) error {
+	if len(h.args) == 0 {
+		return errors.New("no copy hook command configured")
+	}
+	metadata, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, h.args[0], h.args[1:]...)
+	cmd.Env = append(os.Environ(),
+		"COLDMIC_COPY_EVENT="+string(metadata),
+		"COLDMIC_TEXT="+event.Text,
+		"COLDMIC_RAW="+event.RawTranscript,
+		"COLDMIC_SESSION_ID="+event.SessionID,
+		"COLDMIC_TARGET="+event.Target,
+		"COLDMIC_FORM="+event.Form,
+	)
+	cmd.Stdin = strings.NewReader(event.Text)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("copy hook %s timed out after %s", h.args[0], h.timeout)
+		}
+		return fmt.Errorf("copy hook %s failed: %w: %s", h.args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+func TestCopyHookPassesTextAndMetadata(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	script := writeScript(t, "hook.sh", "#!/usr/bin/env bash\ncat > \"$1/text\"\nprintf '%s' \"$COLDMIC_COPY_EVENT\" > \"$1/event\"\nprintf '%s' \"$COLDMIC_SESSION_ID\" > \"$1/session\"\nprintf '%s|%s' \"$COLDMIC_TEXT\" \"$COLDMIC_RAW\" > \"$1/env\"\n")
+
+	event := domain.CopyEvent{SessionID: "session-3", Text: "Hello world.", RawTranscript: "hello world", Target: "git-commit", CopiedAt: time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)}
+	if err := NewCopyHook([]string{script, dir}, 0).TranscriptCopied(context.Background(), event); err != nil {
+		t.Fatalf("hook failed: %v", err)
+	}
+
+	text, _ := os.ReadFile(filepath.Join(dir, "text"))
+	session, _ := os.ReadFile(filepath.Join(dir, "session"))
+	env, _ := os.ReadFile(filepath.Join(dir, "env"))
+	if string(text) != "Hello world." || string(session) != "session-3" || string(env) != "Hello world.|hello world" {
+		t.Fatalf("unexpected hook input text=%q session=%q env=%q", text, session, env)
+	}
+	var got domain.CopyEvent
+	raw, _ := os.ReadFile(filepath.Join(dir, "event"))
+	if err := json.Unmarshal(raw, &got); err != nil || got != event {
+		t.Fatalf("unexpected event metadata %s: %v", raw, err)
+	}
+}
+
+func TestCopyHookReportsFailureAndTimeout(t *testing.T) {
+	t.Parallel()
+
+	failing := writeScript(t, "fail.sh", "#!/usr/bin/env bash\necho 'no daemon' 1>&2\nexit 3\n")
+	err := NewCopyHook([]string{failing}, 0).TranscriptCopied(context.Background(), domain.CopyEvent{})
+	if err == nil || !strings.Contains(err.Error(), "no daemon") {
+		t.Fatalf("expected hook error with stderr, got %v", err)
+	}
+
+	hook := NewCopyHook([]string{writeScript(t, "slow.sh", "#!/usr/bin/env bash\nexec sleep 5\n")}, 50*time.Millisecond)
+	if err := hook.TranscriptCopied(context.Background(), domain.CopyEvent{}); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected slow hook to time out, got %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package output
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"coldmic/internal/ports"
+)
+
+const (
+	GitCommitTargetName = "git-commit"
+
+	gitSubjectWidth = 50
+	gitBodyWidth    = 72
+)
+
+// imperativeVerbs maps common non-imperative opening verbs to the imperative
+// form git convention expects ("Fixed the bug" -> "Fix the bug").
+var imperativeVerbs = map[string]string{
+	"added": "add", "adds": "add", "adding": "add",
+	"fixed": "fix", "fixes": "fix", "fixing": "fix",
+	"updated": "update", "updates": "update", "updating": "update",
+	"removed": "remove", "removes": "remove", "removing": "remove",
+	"renamed": "rename", "renames": "rename", "renaming": "rename",
+	"changed": "change", "changes": "change", "changing": "change",
+	"moved": "move", "moves": "move", "moving": "move",
+	"improved": "improve", "improves": "improve", "improving": "improve",
+	"refactored": "refactor", "refactors": "refactor", "refactoring": "refactor",
+	"implemented": "implement", "implements": "implement", "implementing": "implement",
+	"replaced": "replace", "replaces": "replace", "replacing": "replace",
+	"reverted": "revert", "reverts": "revert", "reverting": "revert",
+	"bumped": "bump", "bumps": "bump", "bumping": "bump",
+	"dropped": "drop", "drops": "drop", "dropping": "drop",
+	"made": "make", "makes": "make", "making": "make",
+}
+
+// GitCommitTarget formats dictation as a git commit message: a capitalized,
+// imperative subject of at most 50 characters, a blank line, and a body
+// wrapped at 72 columns. When a repository is configured, the message is also
+// written to its COMMIT_EDITMSG.
+type GitCommitTarget struct {
+	repo  string
+	rules ports.RulesEngine
+}
+
+// NewGitCommitTarget creates the target. repo may be empty to only copy the
+// message; rules may be nil or carry commit-specific rewrites applied first.
+func NewGitCommitTarget(repo string, rules ports.RulesEngine) *GitCommitTarget {
+	return &GitCommitTarget{repo: repo, rules: rules}
+}
+
+func (t *GitCommitTarget) Name() string {
+	return GitCommitTargetName
+}
+
+func (t *GitCommitTarget) Format(text string) (string, error) {
+	if t.rules != nil {
+		rewritten, err := t.rules.Apply(text)
+		if err != nil {
+			return "", fmt.Errorf("commit message rules failed: %w", err)
+		}
+		text = rewritten
+	}
+	return formatCommitMessage(text), nil
+}
+
+func (t *GitCommitTarget) Deliver(_ context.Context, text string) error {
+	if t.repo == "" {
+		return nil
+	}
+	gitDir, err := resolveGitDir(t.repo)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(gitDir, "COMMIT_EDITMSG"), []byte(text+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to write commit message: %w", err)
+	}
+	return nil
+}
+
+// resolveGitDir finds the git directory of repo, following the "gitdir:"
+// file that worktrees and submodules use in place of a .git directory.
+func resolveGitDir(repo string) (string, error) {
+	dotGit := filepath.Join(repo, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return "", fmt.Errorf("%s is not a git repository: %w", repo, err)
+	}
+	if info.IsDir() {
+		return dotGit, nil
+	}
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", dotGit, err)
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", errors.New(dotGit + " does not point to a git directory")
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(repo, gitDir)
+	}
+	return gitDir, nil
+}
+
+func formatCommitMessage(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return ""
+	}
+	subject, body := cutFirstSentence(text)
+	subject = imperativeSubject(strings.TrimRight(subject, ".!? "))
+
+	if utf8.RuneCountInString(subject) > gitSubjectWidth {
+		head, tail := wrapFirstLine(subject, gitSubjectWidth)
+		subject = head
+		// The overflow keeps its sentence and opens the body.
+		body = strings.TrimSpace(tail + ". " + body)
+	}
+	if body == "" {
+		return subject
+	}
+	return subject + "\n\n" + strings.Join(wrapWords(body, gitBodyWidth), "\n")
+}
+
+// cutFirstSentence splits text after its first sentence-ending punctuation.
+func cutFirstSentence(text string) (string, string) {
+	for i, r := range text {
+		if r != '.' && r != '!' && r != '?' {
+			continue
+		}
+		next := i + 1
+		if next == len(text) {
+			return text, ""
+		}
+		if text[next] == ' ' {
+			return text[:next], strings.TrimSpace(text[next:])
+		}
+	}
+	return text, ""
+}
+
+func imperativeSubject(subject string) string {
+	word, rest, _ := strings.Cut(subject, " ")
+	if verb, ok := imperativeVerbs[strings.ToLower(word)]; ok {
+		word = verb
+	}
+	if rest != "" {
+		word += " " + rest
+	}
+	r, size := utf8.DecodeRuneInString(word)
+	return string(unicode.ToUpper(r)) + word[size:]
+}
+
+// wrapFirstLine returns the longest word-aligned prefix of text within width
+// and the remainder. A single overlong word is cut at width.
+func wrapFirstLine(text string, width int) (string, string) {
+	lines := wrapWords(text, width)
+	head := lines[0]
+	if utf8.RuneCountInString(head) > width {
+		runes := []rune(head)
+		return string(runes[:width]), strings.TrimSpace(string(runes[width:]) + " " + strings.Join(lines[1:], " "))
+	}
+	return head, strings.Join(lines[1:], " ")
+}
+
+func wrapWords(text string, width int) []string {
+	var lines []string
+	var line strings.Builder
+	for _, word := range strings.Fields(text) {
+		if line.Len() > 0 && utf8.RuneCountInString(line.String())+1+utf8.RuneCountInString(word) > width {
+			lines = append(lines, line.String())
+			line.Reset()
+		}
+		if line.Len() > 0 {
+			line.WriteByte(' ')
+		}
+		line.WriteString(word)
+	}
+	return append(lines, line.String())
+}
@@ -0,0 +1,91 @@
+package output
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type replaceRules struct {
+	from, to string
+	err      error
+}
+
+func (r replaceRules) Apply(text string) (string, error) {
+	return strings.ReplaceAll(text, r.from, r.to), r.err
+}
+
+func TestGitCommitTargetFormatsConventionalMessage(t *testing.T) {
+	t.Parallel()
+
+	target := NewGitCommitTarget("", nil)
+	got, err := target.Format("fixed the login redirect. The session cookie was dropped whenever the user signed in from a second tab, so the redirect looped forever.")
+	if err != nil {
+		t.Fatalf("format failed: %v", err)
+	}
+	want := "Fix the login redirect\n\n" +
+		"The session cookie was dropped whenever the user signed in from a second\n" +
+		"tab, so the redirect looped forever."
+	if got != want {
+		t.Fatalf("unexpected message:\n%s", got)
+	}
+}
+
+func TestGitCommitTargetWrapsLongSubject(t *testing.T) {
+	t.Parallel()
+
+	got, _ := NewGitCommitTarget("", nil).Format("adding retry support for uploads that time out on slow connections")
+	lines := strings.Split(got, "\n")
+	if lines[0] != "Add retry support for uploads that time out on" || len(lines[0]) > gitSubjectWidth {
+		t.Fatalf("unexpected subject %q", lines[0])
+	}
+	if len(lines) != 3 || lines[1] != "" || lines[2] != "slow connections." {
+		t.Fatalf("unexpected body %q", lines)
+	}
+}
+
+func TestGitCommitTargetAppliesRules(t *testing.T) {
+	t.Parallel()
+
+	got, err := NewGitCommitTarget("", replaceRules{from: "bug ", to: "issue "}).Format("fix bug with parser")
+	if err != nil || got != "Fix issue with parser" {
+		t.Fatalf("unexpected rules output %q: %v", got, err)
+	}
+	if _, err := NewGitCommitTarget("", replaceRules{err: errors.New("bad rule")}).Format("x"); err == nil {
+		t.Fatalf("expected rules error")
+	}
+}
+
+func TestGitCommitTargetWritesCommitEditMsg(t *testing.T) {
+	t.Parallel()
+
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewGitCommitTarget(repo, nil).Deliver(context.Background(), "Fix typo"); err != nil {
+		t.Fatalf("deliver failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(repo, ".git", "COMMIT_EDITMSG"))
+	if err != nil || string(data) != "Fix typo\n" {
+		t.Fatalf("unexpected COMMIT_EDITMSG %q: %v", data, err)
+	}
+
+	worktree := t.TempDir()
+	if err := os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: "+filepath.Join(repo, ".git")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewGitCommitTarget(worktree, nil).Deliver(context.Background(), "Add docs"); err != nil {
+		t.Fatalf("deliver through gitdir file failed: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(repo, ".git", "COMMIT_EDITMSG")); string(data) != "Add docs\n" {
+		t.Fatalf("unexpected worktree COMMIT_EDITMSG %q", data)
+	}
+
+	if err := NewGitCommitTarget(t.TempDir(), nil).Deliver(context.Background(), "x"); err == nil {
+		t.Fatalf("expected error outside a git repository")
+	}
+}
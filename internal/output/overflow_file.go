@@ -0,0 +1,81 @@
+package output
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// overflowPattern names overflow files; the * is replaced by os.CreateTemp.
+const overflowPattern = "coldmic-transcript-*.txt"
+
+// OverflowDir saves overlong transcripts as private text files in dir, or in
+// the system temp directory when dir is empty.
+type OverflowDir struct {
+	dir string
+}
+
+func NewOverflowDir(dir string) *OverflowDir {
+	return &OverflowDir{dir: dir}
+}
+
+func (d *OverflowDir) SaveText(_ context.Context, text string) (string, error) {
+	if d.dir != "" {
+		if err := os.MkdirAll(d.dir, 0o700); err != nil {
+			return "", fmt.Errorf("failed to create overflow directory: %w", err)
+		}
+	}
+	file, err := os.CreateTemp(d.dir, overflowPattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to create overflow file: %w", err)
+	}
+	if _, err := file.WriteString(text); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return "", fmt.Errorf("failed to write overflow file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to write overflow file: %w", err)
+	}
+	return file.Name(), nil
+}
+
+// PurgeSince deletes the overflow files written at or after since, judged by
+// their modification time, and returns how many it deleted.
+func (d *OverflowDir) PurgeSince(_ context.Context, since time.Time) (int, error) {
+	dir := d.dir
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to list overflow files: %w", err)
+	}
+
+	prefix, suffix, _ := strings.Cut(overflowPattern, "*")
+	purged := 0
+	var errs []error
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, suffix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().Before(since) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, fmt.Errorf("failed to delete overflow file: %w", err))
+			continue
+		}
+		purged++
+	}
+	return purged, errors.Join(errs...)
+}
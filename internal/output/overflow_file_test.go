@@ -0,0 +1,58 @@
+package output
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOverflowDirSavesPrivateFile(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "overflow")
+	path, err := NewOverflowDir(dir).SaveText(context.Background(), "a very long transcript")
+	if err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	if filepath.Dir(path) != dir || !strings.HasPrefix(filepath.Base(path), "coldmic-transcript-") {
+		t.Fatalf("unexpected overflow path: %s", path)
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil || string(contents) != "a very long transcript" {
+		t.Fatalf("unexpected overflow contents: %q %v", contents, err)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected private overflow file, got %v", info.Mode())
+	}
+}
+
+func TestOverflowDirPurgeSince(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	overflow := NewOverflowDir(dir)
+	oldPath, _ := overflow.SaveText(context.Background(), "yesterday")
+	newPath, _ := overflow.SaveText(context.Background(), "today")
+	other := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(other, []byte("keep"), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	since := time.Now().Add(-time.Hour)
+	yesterday := since.Add(-24 * time.Hour)
+	if err := os.Chtimes(oldPath, yesterday, yesterday); err != nil {
+		t.Fatalf("chtimes failed: %v", err)
+	}
+
+	purged, err := overflow.PurgeSince(context.Background(), since)
+	if err != nil || purged != 1 {
+		t.Fatalf("expected one purged file, got %d %v", purged, err)
+	}
+	for path, kept := range map[string]bool{oldPath: true, newPath: false, other: true} {
+		if _, err := os.Stat(path); (err == nil) != kept {
+			t.Fatalf("unexpected state for %s: %v", filepath.Base(path), err)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// defaultRecordingHookTimeout keeps a hung hook from holding up a recording.
+const defaultRecordingHookTimeout = 5 * time.Second
+
+// RecordingCommandHook runs a command before push-to-talk capture begins and
+// another once it stops, such as `playerctl pause` and `playerctl play`.
+// Either command may be empty.
+type RecordingCommandHook struct {
+	start   []string
+	stop    []string
+	timeout time.Duration
+}
+
+// NewRecordingCommandHook stops each command after timeout; a non-positive
+// timeout uses the 5 second default.
+func NewRecordingCommandHook(start, stop []string, timeout time.Duration) *RecordingCommandHook {
+	if timeout <= 0 {
+		timeout = defaultRecordingHookTimeout
+	}
+	return &RecordingCommandHook{start: start, stop: stop, timeout: timeout}
+}
+
+func (h *RecordingCommandHook) RecordingStarting(ctx context.Context) error {
+	return h.run(ctx, "pre-start", h.start)
+}
+
+func (h *RecordingCommandHook) RecordingStopped(ctx context.Context) error {
+	return h.run(ctx, "post-stop", h.stop)
+}
+
+func (h *RecordingCommandHook) run(ctx context.Context, stage string, args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("%s hook %s timed out after %s", stage, args[0], h.timeout)
+		}
+		return fmt.Errorf("%s hook %s failed: %w: %s", stage, args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
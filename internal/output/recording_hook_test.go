@@ -0,0 +1,48 @@
+package output
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordingCommandHookRunsStartAndStop(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	script := writeScript(t, "media.sh", "#!/usr/bin/env bash\necho \"$2\" >> \"$1/calls\"\n")
+	hook := NewRecordingCommandHook([]string{script, dir, "pause"}, []string{script, dir, "play"}, 0)
+	if err := hook.RecordingStarting(context.Background()); err != nil {
+		t.Fatalf("start hook failed: %v", err)
+	}
+	if err := hook.RecordingStopped(context.Background()); err != nil {
+		t.Fatalf("stop hook failed: %v", err)
+	}
+	calls, _ := os.ReadFile(filepath.Join(dir, "calls"))
+	if string(calls) != "pause\nplay\n" {
+		t.Fatalf("unexpected hook calls %q", calls)
+	}
+
+	if err := NewRecordingCommandHook(nil, nil, 0).RecordingStopped(context.Background()); err != nil {
+		t.Fatalf("expected missing command to be skipped, got %v", err)
+	}
+}
+
+func TestRecordingCommandHookReportsFailureAndTimeout(t *testing.T) {
+	t.Parallel()
+
+	failing := writeScript(t, "fail.sh", "#!/usr/bin/env bash\necho 'No players found' 1>&2\nexit 1\n")
+	err := NewRecordingCommandHook([]string{failing}, nil, 0).RecordingStarting(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "pre-start") || !strings.Contains(err.Error(), "No players found") {
+		t.Fatalf("expected start hook error with stderr, got %v", err)
+	}
+
+	slow := writeScript(t, "slow.sh", "#!/usr/bin/env bash\nexec sleep 5\n")
+	err = NewRecordingCommandHook(nil, []string{slow}, 50*time.Millisecond).RecordingStopped(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected stop hook timeout, got %v", err)
+	}
+}
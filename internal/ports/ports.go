@@ -3,6 +3,7 @@ package ports
 import (
 	"context"
 	"io"
+	"time"
 
 	"coldmic/internal/domain"
 )
@@ -13,6 +14,28 @@ type AudioConfig struct {
 	Channels    int
 	InputFormat string
 	InputDevice string
+	// DeviceSampleRate and DeviceChannels are the device's native format,
+	// captured as-is and resampled to SampleRate and Channels; zero asks the
+	// device enumerator.
+	DeviceSampleRate int
+	DeviceChannels   int
+}
+
+// AudioDeviceFormat is the format a capture device delivers natively.
+type AudioDeviceFormat struct {
+	SampleRate int
+	Channels   int
+}
+
+// AudioDeviceEnumerator reports the native format of capture devices.
+type AudioDeviceEnumerator interface {
+	DeviceFormat(ctx context.Context, inputFormat string, device string) (AudioDeviceFormat, error)
+}
+
+// AudioDeviceLister is implemented by enumerators that can list the capture
+// devices of an input format.
+type AudioDeviceLister interface {
+	InputDevices(ctx context.Context, inputFormat string) ([]string, error)
 }
 
 // AudioSession is a live capture session.
@@ -26,12 +49,23 @@ type AudioCapture interface {
 	Start(ctx context.Context, cfg AudioConfig) (AudioSession, error)
 }
 
+// AudioDecoder converts an audio file into raw PCM matching cfg.
+type AudioDecoder interface {
+	Decode(ctx context.Context, path string, cfg AudioConfig) (io.ReadCloser, error)
+}
+
 // StreamingConfig describes provider-agnostic streaming settings.
 type StreamingConfig struct {
 	SampleRate     int
 	Channels       int
 	Encoding       string
 	InterimResults bool
+	// Keywords are names and terms the provider should favour, for providers
+	// whose capabilities include keywords.
+	Keywords []string
+	// Multichannel transcribes each of Channels separately, for providers
+	// whose capabilities include multichannel.
+	Multichannel bool
 }
 
 // StreamingSession is an active provider websocket session.
@@ -43,25 +77,313 @@ type StreamingSession interface {
 	Close() error
 }
 
+// StreamFinalizer is implemented by streaming sessions that can transcribe
+// the audio sent so far on request. Finalize returns once the provider has
+// acknowledged the flush, so stopping need not wait a fixed grace period.
+type StreamFinalizer interface {
+	Finalize(ctx context.Context) error
+}
+
+// ReconnectNotifier is implemented by streaming sessions that reconnect
+// when their connection drops, buffering audio meanwhile. OnReconnect
+// registers fn to hear about each drop.
+type ReconnectNotifier interface {
+	OnReconnect(fn func(err error))
+}
+
 // TranscriptionProvider starts streaming transcription sessions.
 type TranscriptionProvider interface {
 	StartStreaming(ctx context.Context, cfg StreamingConfig) (StreamingSession, error)
 }
 
+// ClipTranscriber transcribes a short clip of raw PCM on the machine, for
+// screening audio before it reaches a cloud provider.
+type ClipTranscriber interface {
+	TranscribeClip(ctx context.Context, cfg StreamingConfig, pcm []byte) (string, error)
+}
+
+// CapabilityReporter is implemented by providers that describe which
+// options they support.
+type CapabilityReporter interface {
+	Capabilities() domain.Capabilities
+}
+
+// ProviderNamer is implemented by providers that name the services their
+// audio is sent to, so usage is priced per service. Wrappers report every
+// provider they stream to.
+type ProviderNamer interface {
+	ProviderNames() []string
+}
+
+// ProviderValidator is implemented by providers that can check their
+// credentials without starting a session. Validate returns a
+// *domain.ProviderError when the provider rejects them.
+type ProviderValidator interface {
+	Validate(ctx context.Context) error
+}
+
+// ModelProgressSink is implemented by event sinks that show model downloads.
+type ModelProgressSink interface {
+	ModelProgress(progress domain.ModelProgress)
+}
+
 // RulesEngine transforms transcripts using deterministic rules.
 type RulesEngine interface {
 	Apply(text string) (string, error)
 }
 
+// WordSource is implemented by rules engines that know correctly cased
+// words, such as acronyms from the substitution dictionary.
+type WordSource interface {
+	KnownWords() []string
+}
+
+// LanguageRules is implemented by rules engines with per-language rules,
+// returning the rules to apply to a transcript in language.
+type LanguageRules interface {
+	ForLanguage(language string) (RulesEngine, error)
+}
+
+// RuleWarner is implemented by rules engines that skipped rules they could
+// not parse, describing each skipped rule.
+type RuleWarner interface {
+	RuleWarnings() []string
+}
+
+// RuleCompiler is implemented by rules engines that compile rules outside
+// the rules file, such as one-off rules for a single session.
+type RuleCompiler interface {
+	CompileRules(lines []string) (RulesEngine, error)
+}
+
+// TextFileStore saves text too long to paste and returns the file path.
+type TextFileStore interface {
+	SaveText(ctx context.Context, text string) (string, error)
+}
+
+// RuleWriter persists new literal substitution rules.
+type RuleWriter interface {
+	AddLiteral(from string, to string) error
+}
+
+// CorrectionStore records user corrections for later suggestions.
+type CorrectionStore interface {
+	RecordCorrection(ctx context.Context, correction domain.Correction) error
+}
+
+// Translator converts transcript text into another language.
+type Translator interface {
+	Translate(ctx context.Context, text string, targetLanguage string) (string, error)
+}
+
 // Clipboard writes text into the system clipboard.
 type Clipboard interface {
 	SetText(ctx context.Context, text string) error
 }
 
+// OutputTarget shapes a finalized transcript for a destination, such as a
+// git commit message, and delivers it there once it has been copied.
+type OutputTarget interface {
+	Name() string
+	Format(text string) (string, error)
+	Deliver(ctx context.Context, text string) error
+}
+
+// IssueTracker creates issues in a tracker such as GitHub or Jira.
+type IssueTracker interface {
+	Name() string
+	Project() string
+	CreateIssue(ctx context.Context, draft domain.IssueDraft) (domain.Issue, error)
+}
+
+// IssueSink is implemented by event sinks that surface issue drafts awaiting
+// confirmation and the issues created from them.
+type IssueSink interface {
+	IssueDraftReady(draft domain.IssueDraft)
+	IssueCreated(issue domain.Issue)
+}
+
+// ReminderCreator schedules reminders in a calendar or reminder tool.
+type ReminderCreator interface {
+	CreateReminder(ctx context.Context, reminder domain.Reminder) error
+}
+
+// TaskSink adds tasks to a task list such as todo.txt or Taskwarrior.
+type TaskSink interface {
+	AddTask(ctx context.Context, task domain.Task) error
+}
+
+// CopyListener is notified after each transcript reaches the clipboard.
+type CopyListener interface {
+	TranscriptCopied(ctx context.Context, event domain.CopyEvent) error
+}
+
+// RecordingHook prepares the environment before push-to-talk capture begins,
+// such as pausing music, and restores it once capture stops.
+type RecordingHook interface {
+	RecordingStarting(ctx context.Context) error
+	RecordingStopped(ctx context.Context) error
+}
+
+// Announcer speaks or displays short status messages for screen-reader users.
+type Announcer interface {
+	Announce(ctx context.Context, message string, urgent bool) error
+}
+
+// ConfidenceSink is implemented by event sinks that offer to retry
+// low-confidence sessions.
+type ConfidenceSink interface {
+	LowConfidence(event domain.LowConfidence)
+}
+
+// SpanSink is implemented by event sinks that render word confidence.
+type SpanSink interface {
+	ConfidenceSpans(transcript domain.ConfidenceTranscript)
+}
+
 // EventSink emits backend state/events to the UI.
 type EventSink interface {
 	SessionStateChanged(state domain.SessionState, reason domain.SessionStateReason)
 	PartialTranscript(text string)
-	FinalTranscript(raw string, transformed string, sessionID string)
+	FinalTranscript(raw string, transformed string, sessionID string, warnings []domain.SessionWarning)
 	SessionError(code domain.ErrorCode, detail string)
 }
+
+// MediaDownloader fetches remote media audio into a local directory.
+type MediaDownloader interface {
+	Download(ctx context.Context, sourceURL string, destDir string) (string, error)
+}
+
+// FileJobSink receives file transcription job updates.
+type FileJobSink interface {
+	FileJobChanged(job domain.FileJob)
+}
+
+// NetworkMonitor reports the current host connectivity.
+type NetworkMonitor interface {
+	State(ctx context.Context) (domain.NetworkState, error)
+}
+
+// NetworkSink receives connectivity changes and provider failover updates.
+type NetworkSink interface {
+	NetworkChanged(state domain.NetworkState)
+}
+
+// ButtonSource reports presses and releases of a physical button, such as a
+// foot pedal, until ctx ends or the device goes away.
+type ButtonSource interface {
+	Run(ctx context.Context, pressed func(down bool)) error
+}
+
+// ButtonLearner waits for the next button press on any input device and
+// reports the device and key.
+type ButtonLearner interface {
+	Learn(ctx context.Context) (domain.TriggerDevice, error)
+}
+
+// MIDISource reads notes and control changes from a MIDI controller until
+// ctx is cancelled or the device fails.
+type MIDISource interface {
+	Run(ctx context.Context, received func(domain.MIDIMessage)) error
+}
+
+// FormSchemaStore loads form-filling schemas by name.
+type FormSchemaStore interface {
+	Load(name string) (domain.FormSchema, error)
+	List() ([]string, error)
+}
+
+// UsageStore keeps running monthly provider usage totals.
+type UsageStore interface {
+	Add(ctx context.Context, month string, usage domain.SessionUsage) error
+	Month(ctx context.Context, month string) (domain.UsageTotal, error)
+}
+
+// FileJobStore persists file job state so queued work survives a restart.
+type FileJobStore interface {
+	Save(ctx context.Context, job domain.FileJob) error
+	List(ctx context.Context) ([]domain.FileJob, error)
+}
+
+// HistoryStore persists processed transcripts.
+type HistoryStore interface {
+	Append(ctx context.Context, entry domain.HistoryEntry) error
+	List(ctx context.Context) ([]domain.HistoryEntry, error)
+}
+
+// Purger is implemented by stores that can delete everything they saved at
+// or after a time, for emergency wipes. It returns how much was deleted.
+type Purger interface {
+	PurgeSince(ctx context.Context, since time.Time) (int, error)
+}
+
+// CaptionSink receives translated captions parallel to the transcript stream.
+type CaptionSink interface {
+	Caption(caption domain.Caption)
+}
+
+// RecordingStore persists raw capture audio and reports remaining disk space.
+type RecordingStore interface {
+	Create(name string, cfg AudioConfig) (io.WriteCloser, error)
+	FreeBytes() (uint64, error)
+}
+
+// WaveformReader summarizes saved session recordings for display.
+type WaveformReader interface {
+	Waveform(sessionID string, buckets int) (domain.Waveform, error)
+}
+
+// SessionAudioStore locates the saved recordings of a session.
+type SessionAudioStore interface {
+	SessionAudio(sessionID string) ([]string, error)
+}
+
+// AudioPlayer plays recordings, mixed together, from an offset.
+type AudioPlayer interface {
+	Play(ctx context.Context, paths []string, from time.Duration) (Playback, error)
+}
+
+// Playback is audio that is playing; Wait returns once it ends or stops.
+type Playback interface {
+	Wait() error
+	Stop() error
+}
+
+// PlaybackSink is implemented by event sinks that follow audio playback.
+type PlaybackSink interface {
+	PlaybackPosition(position domain.PlaybackPosition)
+}
+
+// BackupTarget stores opaque backup objects in remote storage.
+type BackupTarget interface {
+	Upload(ctx context.Context, name string, data []byte) error
+	Download(ctx context.Context, name string) ([]byte, error)
+}
+
+// AccessTokenStore issues and verifies scoped control API tokens.
+type AccessTokenStore interface {
+	Create(ctx context.Context, label string, scope domain.TokenScope) (string, domain.AccessToken, error)
+	Verify(ctx context.Context, secret string) (domain.AccessToken, error)
+	// Enforced reports whether any token exists; the API stays open until then.
+	Enforced(ctx context.Context) (bool, error)
+}
+
+// TranscriptCache stores provider output for previously transcribed audio.
+type TranscriptCache interface {
+	Get(ctx context.Context, key string) (domain.CachedTranscript, bool, error)
+	Put(ctx context.Context, key string, transcript domain.CachedTranscript) error
+}
+
+// Clock tells the time and schedules timers, so timing in the session
+// controllers can run on a virtual clock in tests.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer fires once on C unless stopped first.
+type Timer interface {
+	C() <-chan time.Time
+	// Stop reports whether it stopped the timer before it fired.
+	Stop() bool
+}
@@ -0,0 +1,422 @@
+package assemblyai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/netproxy"
+	"coldmic/internal/ports"
+)
+
+// Config controls AssemblyAI realtime websocket settings.
+type Config struct {
+	APIKey     string
+	APIBaseURL string
+	// Model selects the streaming speech model, such as
+	// universal-streaming-english or universal-streaming-multilingual.
+	Model string
+	// SmartFormat waits for AssemblyAI's punctuated and cased turn before
+	// reporting it final.
+	SmartFormat bool
+	// Proxy is an explicit proxy URL; empty uses the proxy environment.
+	Proxy string
+}
+
+// Provider implements ports.TranscriptionProvider for AssemblyAI's
+// Universal Streaming API.
+type Provider struct {
+	cfg Config
+}
+
+func NewProvider(cfg Config) *Provider {
+	if cfg.APIBaseURL == "" {
+		cfg.APIBaseURL = "wss://streaming.assemblyai.com/v3"
+	}
+	if cfg.Model == "" {
+		cfg.Model = "universal-streaming-english"
+	}
+	return &Provider{cfg: cfg}
+}
+
+// Capabilities describes AssemblyAI Universal Streaming. The English model
+// only transcribes English; the multilingual one covers six languages.
+func (p *Provider) Capabilities() domain.Capabilities {
+	capabilities := domain.Capabilities{
+		Streaming:    true,
+		LivePartials: true,
+		Timestamps:   true,
+		WordTimings:  true,
+		Confidence:   true,
+		Keywords:     true,
+		Languages:    []string{"en"},
+	}
+	if p.cfg.Model == "universal-streaming-multilingual" {
+		capabilities.Languages = []string{"en", "es", "fr", "de", "it", "pt"}
+	}
+	return capabilities
+}
+
+// ProviderNames names the service usage is priced under.
+func (p *Provider) ProviderNames() []string {
+	return []string{"assemblyai"}
+}
+
+func (p *Provider) StartStreaming(ctx context.Context, cfg ports.StreamingConfig) (ports.StreamingSession, error) {
+	if strings.TrimSpace(p.cfg.APIKey) == "" {
+		return nil, errors.New("ASSEMBLYAI_API_KEY is not configured")
+	}
+
+	wsURL, err := buildStreamURL(p.cfg, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := http.Header{}
+	headers.Set("Authorization", p.cfg.APIKey)
+
+	conn, _, err := netproxy.Dialer(p.cfg.Proxy).DialContext(ctx, wsURL, headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to AssemblyAI websocket: %w", err)
+	}
+	debuglog.Printf("assemblyai connected url=%s", wsURL)
+
+	session := &streamingSession{
+		conn:        conn,
+		smartFormat: p.cfg.SmartFormat,
+		interim:     cfg.InterimResults,
+		events:      make(chan domain.TranscriptEvent, 64),
+		audio:       make(chan []byte, 32),
+		readDone:    make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+
+	session.wg.Add(2)
+	go session.readLoop()
+	go session.writeLoop()
+	go func() {
+		session.wg.Wait()
+		close(session.events)
+		close(session.done)
+		_ = conn.Close()
+	}()
+
+	go func() {
+		<-ctx.Done()
+		_ = session.Close()
+	}()
+
+	return session, nil
+}
+
+type streamingSession struct {
+	conn        *websocket.Conn
+	smartFormat bool
+	interim     bool
+
+	events chan domain.TranscriptEvent
+	audio  chan []byte
+	// readDone stops the writer once AssemblyAI ends the stream, which it
+	// does on its own when it rejects a session.
+	readDone chan struct{}
+	done     chan struct{}
+
+	wg sync.WaitGroup
+
+	errMu sync.Mutex
+	err   error
+
+	closeSendOnce sync.Once
+	closeOnce     sync.Once
+	sendMu        sync.RWMutex
+	sendClosed    bool
+}
+
+func (s *streamingSession) SendAudio(chunk []byte) error {
+	if len(chunk) == 0 {
+		return nil
+	}
+
+	s.sendMu.RLock()
+	closed := s.sendClosed
+	s.sendMu.RUnlock()
+	if closed {
+		return errors.New("audio stream is already closed")
+	}
+
+	copied := append([]byte(nil), chunk...)
+	select {
+	case s.audio <- copied:
+		return nil
+	case <-s.done:
+		if err := s.waitErr(); err != nil {
+			return err
+		}
+		return errors.New("session closed")
+	}
+}
+
+func (s *streamingSession) CloseSend() error {
+	s.closeSendOnce.Do(func() {
+		s.sendMu.Lock()
+		s.sendClosed = true
+		close(s.audio)
+		s.sendMu.Unlock()
+	})
+	return nil
+}
+
+func (s *streamingSession) Events() <-chan domain.TranscriptEvent {
+	return s.events
+}
+
+func (s *streamingSession) Wait() error {
+	<-s.done
+	return s.waitErr()
+}
+
+func (s *streamingSession) Close() error {
+	s.closeOnce.Do(func() {
+		_ = s.CloseSend()
+		_ = s.conn.Close()
+	})
+	<-s.done
+	return s.waitErr()
+}
+
+func (s *streamingSession) waitErr() error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	return s.err
+}
+
+func (s *streamingSession) setErr(err error) {
+	if err == nil || isExpectedShutdownErr(err) {
+		return
+	}
+
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+func isExpectedShutdownErr(err error) bool {
+	if errors.Is(err, net.ErrClosed) || errors.Is(err, websocket.ErrCloseSent) {
+		return true
+	}
+
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) {
+		return false
+	}
+
+	switch closeErr.Code {
+	case websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseNoStatusReceived:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *streamingSession) writeLoop() {
+	defer s.wg.Done()
+
+	for {
+		var chunk []byte
+		var ok bool
+		select {
+		case chunk, ok = <-s.audio:
+		case <-s.readDone:
+			return
+		}
+		if !ok {
+			break
+		}
+		if err := s.conn.WriteMessage(websocket.BinaryMessage, chunk); err != nil {
+			debuglog.Printf("assemblyai audio send failed: %v", err)
+			s.setErr(fmt.Errorf("failed to send audio: %w", err))
+			return
+		}
+	}
+
+	if err := s.conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"Terminate"}`)); err != nil {
+		debuglog.Printf("assemblyai terminate failed: %v", err)
+		s.setErr(fmt.Errorf("failed to close stream: %w", err))
+		return
+	}
+	debuglog.Printf("assemblyai sent Terminate")
+}
+
+func (s *streamingSession) readLoop() {
+	defer s.wg.Done()
+	defer close(s.readDone)
+
+	for {
+		_, payload, err := s.conn.ReadMessage()
+		if err != nil {
+			debuglog.Printf("assemblyai read failed: %v", err)
+			s.setErr(fmt.Errorf("failed to read provider event: %w", closeReason(err)))
+			return
+		}
+
+		var message streamMessage
+		if err := json.Unmarshal(payload, &message); err != nil {
+			debuglog.Printf("assemblyai ignored non-json payload bytes=%d", len(payload))
+			continue
+		}
+
+		switch {
+		case message.Error != "":
+			debuglog.Printf("assemblyai error message=%q", message.Error)
+			s.setErr(errors.New(strings.TrimSpace(message.Error)))
+			return
+		case message.Type == "Termination":
+			debuglog.Printf("assemblyai session terminated audio_seconds=%.1f", message.AudioDurationSeconds)
+			return
+		case message.Type == "Turn":
+			event, ok := s.turnEvent(message)
+			if !ok {
+				continue
+			}
+			debuglog.Printf("assemblyai transcript kind=%s turn=%d text=%q", event.Kind, message.TurnOrder, truncateForLog(event.Text, 160))
+			s.emit(event)
+		}
+	}
+}
+
+// turnEvent maps a Turn message onto a transcript event. A turn is final once
+// it has ended, and with SmartFormat only once its formatted copy arrives.
+func (s *streamingSession) turnEvent(message streamMessage) (domain.TranscriptEvent, bool) {
+	text := strings.TrimSpace(message.Transcript)
+	if text == "" {
+		return domain.TranscriptEvent{}, false
+	}
+
+	final := message.EndOfTurn && (message.TurnIsFormatted || !s.smartFormat)
+	if !final && !s.interim {
+		return domain.TranscriptEvent{}, false
+	}
+
+	event := domain.TranscriptEvent{
+		Kind:          domain.TranscriptKindPartial,
+		Text:          text,
+		IsSpeechFinal: final,
+	}
+	if final {
+		event.Kind = domain.TranscriptKindFinal
+	}
+	if len(message.Words) > 0 {
+		first, last := message.Words[0], message.Words[len(message.Words)-1]
+		event.Start = time.Duration(first.Start) * time.Millisecond
+		event.Duration = time.Duration(last.End-first.Start) * time.Millisecond
+		event.Words = make([]domain.TranscriptWord, 0, len(message.Words))
+		var total float64
+		for _, word := range message.Words {
+			event.Words = append(event.Words, domain.TranscriptWord{
+				Text:       word.Text,
+				Confidence: word.Confidence,
+				Start:      time.Duration(word.Start) * time.Millisecond,
+				End:        time.Duration(word.End) * time.Millisecond,
+			})
+			total += word.Confidence
+		}
+		event.Confidence = total / float64(len(message.Words))
+	}
+	return event, true
+}
+
+func (s *streamingSession) emit(event domain.TranscriptEvent) {
+	select {
+	case s.events <- event:
+	case <-s.done:
+	default:
+	}
+}
+
+// closeReason surfaces the reason AssemblyAI gives when it rejects a stream,
+// such as an invalid key or insufficient balance.
+func closeReason(err error) error {
+	var closeErr *websocket.CloseError
+	if errors.As(err, &closeErr) && !isExpectedShutdownErr(err) && closeErr.Text != "" {
+		return fmt.Errorf("%s (code %d): %w", closeErr.Text, closeErr.Code, err)
+	}
+	return err
+}
+
+type streamMessage struct {
+	Type                 string       `json:"type"`
+	Error                string       `json:"error"`
+	Transcript           string       `json:"transcript"`
+	TurnOrder            int          `json:"turn_order"`
+	EndOfTurn            bool         `json:"end_of_turn"`
+	TurnIsFormatted      bool         `json:"turn_is_formatted"`
+	AudioDurationSeconds float64      `json:"audio_duration_seconds"`
+	Words                []streamWord `json:"words"`
+}
+
+type streamWord struct {
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence"`
+	// Start and End are milliseconds from the start of the stream.
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+func truncateForLog(input string, max int) string {
+	if max <= 0 || len(input) <= max {
+		return input
+	}
+	return input[:max] + "..."
+}
+
+func buildStreamURL(providerCfg Config, streamCfg ports.StreamingConfig) (string, error) {
+	base := strings.TrimSpace(providerCfg.APIBaseURL)
+	if base == "" {
+		base = "wss://streaming.assemblyai.com/v3"
+	}
+
+	if strings.HasPrefix(base, "https://") {
+		base = "wss://" + strings.TrimPrefix(base, "https://")
+	} else if strings.HasPrefix(base, "http://") {
+		base = "ws://" + strings.TrimPrefix(base, "http://")
+	}
+	base = strings.TrimRight(base, "/")
+
+	streamURL, err := url.Parse(base + "/ws")
+	if err != nil {
+		return "", fmt.Errorf("invalid AssemblyAI API base URL: %w", err)
+	}
+
+	if streamCfg.SampleRate <= 0 {
+		streamCfg.SampleRate = 16000
+	}
+	query := streamURL.Query()
+	query.Set("sample_rate", fmt.Sprintf("%d", streamCfg.SampleRate))
+	query.Set("encoding", "pcm_s16le")
+	query.Set("format_turns", fmt.Sprintf("%t", providerCfg.SmartFormat))
+	if providerCfg.Model != "" {
+		query.Set("speech_model", providerCfg.Model)
+	}
+	if len(streamCfg.Keywords) > 0 {
+		keyterms, err := json.Marshal(streamCfg.Keywords)
+		if err != nil {
+			return "", err
+		}
+		query.Set("keyterms_prompt", string(keyterms))
+	}
+	streamURL.RawQuery = query.Encode()
+	return streamURL.String(), nil
+}
@@ -0,0 +1,136 @@
+package assemblyai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestNewProviderDefaults(t *testing.T) {
+	t.Parallel()
+
+	p := NewProvider(Config{})
+	if p.cfg.APIBaseURL != "wss://streaming.assemblyai.com/v3" || p.cfg.Model != "universal-streaming-english" {
+		t.Fatalf("unexpected defaults: %+v", p.cfg)
+	}
+	if _, err := p.StartStreaming(context.Background(), ports.StreamingConfig{}); err == nil {
+		t.Fatalf("expected missing key error")
+	}
+}
+
+func TestBuildStreamURL(t *testing.T) {
+	t.Parallel()
+
+	url, err := buildStreamURL(Config{APIBaseURL: "http://localhost:8080/v3/", Model: "m", SmartFormat: true}, ports.StreamingConfig{SampleRate: 8000})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"ws://localhost:8080/v3/ws?", "sample_rate=8000", "encoding=pcm_s16le", "format_turns=true", "speech_model=m"} {
+		if !strings.Contains(url, want) {
+			t.Fatalf("expected %q in url: %s", want, url)
+		}
+	}
+	url, err = buildStreamURL(Config{APIBaseURL: "wss://streaming.assemblyai.com/v3"}, ports.StreamingConfig{Keywords: []string{"coldmic"}})
+	if err != nil || !strings.Contains(url, "keyterms_prompt=%5B%22coldmic%22%5D") {
+		t.Fatalf("expected key terms in url %s: %v", url, err)
+	}
+	if _, err := buildStreamURL(Config{APIBaseURL: ":// bad"}, ports.StreamingConfig{}); err == nil {
+		t.Fatalf("expected invalid base url error")
+	}
+}
+
+func TestStreamingSessionMapsTurns(t *testing.T) {
+	t.Parallel()
+
+	var auth string
+	server := newTestServer(t, &auth, func(conn *websocket.Conn) {
+		for _, message := range []string{
+			`{"type":"Begin","id":"abc"}`,
+			`{"type":"Turn","turn_order":0,"transcript":"hello wor","end_of_turn":false,"words":[{"text":"hello","confidence":0.9,"start":500,"end":800}]}`,
+			`{"type":"Turn","turn_order":0,"transcript":"hello world","end_of_turn":true,"turn_is_formatted":false}`,
+			`{"type":"Turn","turn_order":0,"transcript":"Hello world.","end_of_turn":true,"turn_is_formatted":true,"words":[{"text":"Hello","confidence":0.9,"start":500,"end":800},{"text":"world.","confidence":0.7,"start":800,"end":1500}]}`,
+		} {
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(message))
+		}
+		_, payload, _ := conn.ReadMessage()
+		if string(payload) != `{"type":"Terminate"}` {
+			t.Errorf("expected Terminate, got %s", payload)
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"Termination","audio_duration_seconds":1.5}`))
+	})
+
+	provider := NewProvider(Config{APIKey: "test-key", APIBaseURL: server.URL, SmartFormat: true})
+	session, err := provider.StartStreaming(context.Background(), ports.StreamingConfig{InterimResults: true})
+	if err != nil {
+		t.Fatalf("start streaming failed: %v", err)
+	}
+	_ = session.CloseSend()
+
+	var events []domain.TranscriptEvent
+	for event := range session.Events() {
+		events = append(events, event)
+	}
+	if err := session.Wait(); err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	if auth != "test-key" {
+		t.Fatalf("unexpected authorization header %q", auth)
+	}
+	if len(events) != 3 || events[0].Text != "hello wor" || events[1].Kind != domain.TranscriptKindPartial || events[1].Text != "hello world" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+	final := events[2]
+	if final.Kind != domain.TranscriptKindFinal || final.Text != "Hello world." || !final.IsSpeechFinal {
+		t.Fatalf("unexpected final event: %+v", final)
+	}
+	if final.Start != 500*time.Millisecond || final.Duration != time.Second || len(final.Words) != 2 || final.Confidence < 0.79 || final.Confidence > 0.81 {
+		t.Fatalf("unexpected final timing or confidence: %+v", final)
+	}
+	if word := final.Words[1]; word.Start != 800*time.Millisecond || word.End != 1500*time.Millisecond {
+		t.Fatalf("unexpected word timing: %+v", word)
+	}
+}
+
+func TestStreamingSessionReportsCloseReason(t *testing.T) {
+	t.Parallel()
+
+	var auth string
+	server := newTestServer(t, &auth, func(conn *websocket.Conn) {
+		_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(3005, "Invalid API key"))
+	})
+
+	session, err := NewProvider(Config{APIKey: "bad", APIBaseURL: server.URL}).StartStreaming(context.Background(), ports.StreamingConfig{})
+	if err != nil {
+		t.Fatalf("start streaming failed: %v", err)
+	}
+	for range session.Events() {
+	}
+	if err := session.Wait(); err == nil || !strings.Contains(err.Error(), "Invalid API key") {
+		t.Fatalf("expected close reason in error, got %v", err)
+	}
+}
+
+func newTestServer(t *testing.T, auth *string, handler func(conn *websocket.Conn)) *httptest.Server {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*auth = r.Header.Get("Authorization")
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		handler(conn)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
@@ -0,0 +1,488 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/netproxy"
+	"coldmic/internal/ports"
+)
+
+// Config controls Azure Speech websocket settings.
+type Config struct {
+	APIKey string
+	// Region builds the default endpoint, such as westeurope.
+	Region string
+	// APIBaseURL overrides the regional endpoint, for example for a
+	// container or private endpoint.
+	APIBaseURL string
+	Language   string
+	// Proxy is an explicit proxy URL; empty uses the proxy environment.
+	Proxy string
+}
+
+// Provider implements ports.TranscriptionProvider for Azure AI Speech
+// speech-to-text over its websocket protocol.
+type Provider struct {
+	cfg Config
+}
+
+func NewProvider(cfg Config) *Provider {
+	if cfg.Language == "" {
+		cfg.Language = "en-US"
+	}
+	return &Provider{cfg: cfg}
+}
+
+// Capabilities describes Azure AI Speech.
+func (p *Provider) Capabilities() domain.Capabilities {
+	return domain.Capabilities{
+		Streaming:    true,
+		LivePartials: true,
+		Timestamps:   true,
+		Confidence:   true,
+	}
+}
+
+// ProviderNames names the service usage is priced under.
+func (p *Provider) ProviderNames() []string {
+	return []string{"azure"}
+}
+
+func (p *Provider) StartStreaming(ctx context.Context, cfg ports.StreamingConfig) (ports.StreamingSession, error) {
+	if strings.TrimSpace(p.cfg.APIKey) == "" {
+		return nil, errors.New("AZURE_SPEECH_KEY is not configured")
+	}
+	if strings.TrimSpace(p.cfg.Region) == "" && strings.TrimSpace(p.cfg.APIBaseURL) == "" {
+		return nil, errors.New("AZURE_SPEECH_REGION is not configured")
+	}
+
+	wsURL, err := buildRecognitionURL(p.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	connectionID := newID()
+	headers := http.Header{}
+	headers.Set("Ocp-Apim-Subscription-Key", p.cfg.APIKey)
+	headers.Set("X-ConnectionId", connectionID)
+
+	conn, _, err := netproxy.Dialer(p.cfg.Proxy).DialContext(ctx, wsURL, headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Azure Speech websocket: %w", err)
+	}
+	debuglog.Printf("azure connected url=%s", wsURL)
+
+	if cfg.SampleRate <= 0 {
+		cfg.SampleRate = 16000
+	}
+	if cfg.Channels <= 0 {
+		cfg.Channels = 1
+	}
+	session := &streamingSession{
+		conn:      conn,
+		requestID: newID(),
+		stream:    cfg,
+		interim:   cfg.InterimResults,
+		events:    make(chan domain.TranscriptEvent, 64),
+		audio:     make(chan []byte, 32),
+		readDone:  make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	if err := session.sendConfig(); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	session.wg.Add(2)
+	go session.readLoop()
+	go session.writeLoop()
+	go func() {
+		session.wg.Wait()
+		close(session.events)
+		close(session.done)
+		_ = conn.Close()
+	}()
+
+	go func() {
+		<-ctx.Done()
+		_ = session.Close()
+	}()
+
+	return session, nil
+}
+
+type streamingSession struct {
+	conn      *websocket.Conn
+	requestID string
+	stream    ports.StreamingConfig
+	interim   bool
+
+	events chan domain.TranscriptEvent
+	audio  chan []byte
+	// readDone stops the writer once Azure ends the turn or the connection.
+	readDone chan struct{}
+	done     chan struct{}
+
+	wg sync.WaitGroup
+
+	errMu sync.Mutex
+	err   error
+
+	closeSendOnce sync.Once
+	closeOnce     sync.Once
+	sendMu        sync.RWMutex
+	sendClosed    bool
+}
+
+func (s *streamingSession) SendAudio(chunk []byte) error {
+	if len(chunk) == 0 {
+		return nil
+	}
+
+	s.sendMu.RLock()
+	closed := s.sendClosed
+	s.sendMu.RUnlock()
+	if closed {
+		return errors.New("audio stream is already closed")
+	}
+
+	copied := append([]byte(nil), chunk...)
+	select {
+	case s.audio <- copied:
+		return nil
+	case <-s.done:
+		if err := s.waitErr(); err != nil {
+			return err
+		}
+		return errors.New("session closed")
+	}
+}
+
+func (s *streamingSession) CloseSend() error {
+	s.closeSendOnce.Do(func() {
+		s.sendMu.Lock()
+		s.sendClosed = true
+		close(s.audio)
+		s.sendMu.Unlock()
+	})
+	return nil
+}
+
+func (s *streamingSession) Events() <-chan domain.TranscriptEvent {
+	return s.events
+}
+
+func (s *streamingSession) Wait() error {
+	<-s.done
+	return s.waitErr()
+}
+
+func (s *streamingSession) Close() error {
+	s.closeOnce.Do(func() {
+		_ = s.CloseSend()
+		_ = s.conn.Close()
+	})
+	<-s.done
+	return s.waitErr()
+}
+
+func (s *streamingSession) waitErr() error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	return s.err
+}
+
+func (s *streamingSession) setErr(err error) {
+	if err == nil || isExpectedShutdownErr(err) {
+		return
+	}
+
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+func isExpectedShutdownErr(err error) bool {
+	if errors.Is(err, net.ErrClosed) || errors.Is(err, websocket.ErrCloseSent) {
+		return true
+	}
+
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) {
+		return false
+	}
+
+	switch closeErr.Code {
+	case websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseNoStatusReceived:
+		return true
+	default:
+		return false
+	}
+}
+
+// sendConfig announces the client before any audio, as the protocol requires.
+func (s *streamingSession) sendConfig() error {
+	body := `{"context":{"system":{"name":"coldmic","version":"1.0.0","build":"go","lang":"Go"}}}`
+	message := s.headers("speech.config", "application/json") + "\r\n" + body
+	if err := s.conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+		return fmt.Errorf("failed to send speech config: %w", err)
+	}
+	return nil
+}
+
+func (s *streamingSession) writeLoop() {
+	defer s.wg.Done()
+
+	// The first audio message carries a WAV header describing the stream.
+	if err := s.writeAudio(wavHeader(s.stream)); err != nil {
+		s.setErr(fmt.Errorf("failed to send audio: %w", err))
+		return
+	}
+	for {
+		var chunk []byte
+		var ok bool
+		select {
+		case chunk, ok = <-s.audio:
+		case <-s.readDone:
+			return
+		}
+		if !ok {
+			break
+		}
+		if err := s.writeAudio(chunk); err != nil {
+			debuglog.Printf("azure audio send failed: %v", err)
+			s.setErr(fmt.Errorf("failed to send audio: %w", err))
+			return
+		}
+	}
+
+	// An empty audio message marks the end of the stream.
+	if err := s.writeAudio(nil); err != nil {
+		debuglog.Printf("azure end of audio failed: %v", err)
+		s.setErr(fmt.Errorf("failed to close stream: %w", err))
+		return
+	}
+	debuglog.Printf("azure sent end of audio")
+}
+
+func (s *streamingSession) writeAudio(audio []byte) error {
+	header := s.headers("audio", "audio/x-wav")
+	message := make([]byte, 2, 2+len(header)+len(audio))
+	binary.BigEndian.PutUint16(message, uint16(len(header)))
+	message = append(message, header...)
+	message = append(message, audio...)
+	return s.conn.WriteMessage(websocket.BinaryMessage, message)
+}
+
+func (s *streamingSession) headers(path, contentType string) string {
+	return "Path: " + path + "\r\n" +
+		"X-RequestId: " + s.requestID + "\r\n" +
+		"X-Timestamp: " + time.Now().UTC().Format("2006-01-02T15:04:05.000Z") + "\r\n" +
+		"Content-Type: " + contentType + "\r\n"
+}
+
+func (s *streamingSession) readLoop() {
+	defer s.wg.Done()
+	defer close(s.readDone)
+
+	for {
+		_, payload, err := s.conn.ReadMessage()
+		if err != nil {
+			debuglog.Printf("azure read failed: %v", err)
+			s.setErr(fmt.Errorf("failed to read provider event: %w", closeReason(err)))
+			return
+		}
+
+		path, body := parseMessage(payload)
+		switch path {
+		case "speech.hypothesis":
+			if !s.interim {
+				continue
+			}
+			var hypothesis speechHypothesis
+			if err := json.Unmarshal(body, &hypothesis); err != nil || strings.TrimSpace(hypothesis.Text) == "" {
+				continue
+			}
+			s.emit(domain.TranscriptEvent{
+				Kind:     domain.TranscriptKindPartial,
+				Text:     strings.TrimSpace(hypothesis.Text),
+				Start:    ticksToDuration(hypothesis.Offset),
+				Duration: ticksToDuration(hypothesis.Duration),
+			})
+		case "speech.phrase":
+			var phrase speechPhrase
+			if err := json.Unmarshal(body, &phrase); err != nil {
+				debuglog.Printf("azure ignored invalid phrase bytes=%d", len(body))
+				continue
+			}
+			if phrase.RecognitionStatus == "Error" || phrase.RecognitionStatus == "BadRequest" || phrase.RecognitionStatus == "TooManyRequests" {
+				s.setErr(fmt.Errorf("azure recognition failed: %s", phrase.RecognitionStatus))
+				return
+			}
+			event, ok := phrase.event()
+			if !ok {
+				continue
+			}
+			debuglog.Printf("azure transcript kind=%s text=%q", event.Kind, truncateForLog(event.Text, 160))
+			s.emit(event)
+		case "turn.end":
+			debuglog.Printf("azure turn ended")
+			return
+		}
+	}
+}
+
+func (s *streamingSession) emit(event domain.TranscriptEvent) {
+	select {
+	case s.events <- event:
+	case <-s.done:
+	default:
+	}
+}
+
+// parseMessage splits a protocol text message into its Path header and body.
+func parseMessage(payload []byte) (string, []byte) {
+	head, body, found := bytes.Cut(payload, []byte("\r\n\r\n"))
+	if !found {
+		return "", nil
+	}
+	for _, line := range strings.Split(string(head), "\r\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "Path") {
+			return strings.ToLower(strings.TrimSpace(value)), body
+		}
+	}
+	return "", body
+}
+
+// closeReason surfaces the reason Azure gives when it closes a stream, such
+// as an invalid key or exceeded quota.
+func closeReason(err error) error {
+	var closeErr *websocket.CloseError
+	if errors.As(err, &closeErr) && !isExpectedShutdownErr(err) && closeErr.Text != "" {
+		return fmt.Errorf("%s (code %d): %w", closeErr.Text, closeErr.Code, err)
+	}
+	return err
+}
+
+type speechHypothesis struct {
+	Text     string `json:"Text"`
+	Offset   int64  `json:"Offset"`
+	Duration int64  `json:"Duration"`
+}
+
+type speechPhrase struct {
+	RecognitionStatus string `json:"RecognitionStatus"`
+	DisplayText       string `json:"DisplayText"`
+	Offset            int64  `json:"Offset"`
+	Duration          int64  `json:"Duration"`
+	NBest             []struct {
+		Confidence float64 `json:"Confidence"`
+		Display    string  `json:"Display"`
+	} `json:"NBest"`
+}
+
+// event maps a recognized phrase onto a final transcript event. Phrases
+// without speech, such as NoMatch or silence timeouts, are skipped.
+func (p speechPhrase) event() (domain.TranscriptEvent, bool) {
+	if p.RecognitionStatus != "Success" {
+		return domain.TranscriptEvent{}, false
+	}
+	event := domain.TranscriptEvent{
+		Kind:          domain.TranscriptKindFinal,
+		Text:          strings.TrimSpace(p.DisplayText),
+		IsSpeechFinal: true,
+		Start:         ticksToDuration(p.Offset),
+		Duration:      ticksToDuration(p.Duration),
+	}
+	if len(p.NBest) > 0 {
+		event.Confidence = p.NBest[0].Confidence
+		if event.Text == "" {
+			event.Text = strings.TrimSpace(p.NBest[0].Display)
+		}
+	}
+	return event, event.Text != ""
+}
+
+// ticksToDuration converts Azure's 100-nanosecond offsets.
+func ticksToDuration(ticks int64) time.Duration {
+	if ticks <= 0 {
+		return 0
+	}
+	return time.Duration(ticks) * 100 * time.Nanosecond
+}
+
+func truncateForLog(input string, max int) string {
+	if max <= 0 || len(input) <= max {
+		return input
+	}
+	return input[:max] + "..."
+}
+
+func newID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// wavHeader describes an open-ended 16-bit PCM stream.
+func wavHeader(cfg ports.StreamingConfig) []byte {
+	blockAlign := cfg.Channels * 2
+	header := make([]byte, 44)
+	copy(header[0:], "RIFF")
+	copy(header[8:], "WAVE")
+	copy(header[12:], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:], 16)
+	binary.LittleEndian.PutUint16(header[20:], 1)
+	binary.LittleEndian.PutUint16(header[22:], uint16(cfg.Channels))
+	binary.LittleEndian.PutUint32(header[24:], uint32(cfg.SampleRate))
+	binary.LittleEndian.PutUint32(header[28:], uint32(cfg.SampleRate*blockAlign))
+	binary.LittleEndian.PutUint16(header[32:], uint16(blockAlign))
+	binary.LittleEndian.PutUint16(header[34:], 16)
+	copy(header[36:], "data")
+	return header
+}
+
+func buildRecognitionURL(cfg Config) (string, error) {
+	base := strings.TrimSpace(cfg.APIBaseURL)
+	if base == "" {
+		base = "wss://" + strings.TrimSpace(cfg.Region) + ".stt.speech.microsoft.com"
+	}
+
+	if strings.HasPrefix(base, "https://") {
+		base = "wss://" + strings.TrimPrefix(base, "https://")
+	} else if strings.HasPrefix(base, "http://") {
+		base = "ws://" + strings.TrimPrefix(base, "http://")
+	}
+	base = strings.TrimRight(base, "/")
+
+	recognitionURL, err := url.Parse(base + "/speech/recognition/conversation/cognitiveservices/v1")
+	if err != nil {
+		return "", fmt.Errorf("invalid Azure Speech endpoint: %w", err)
+	}
+	query := recognitionURL.Query()
+	query.Set("language", cfg.Language)
+	query.Set("format", "detailed")
+	recognitionURL.RawQuery = query.Encode()
+	return recognitionURL.String(), nil
+}
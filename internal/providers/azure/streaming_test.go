@@ -0,0 +1,143 @@
+package azure
+
+import (
+	"context"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestProviderRequiresKeyAndRegion(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewProvider(Config{Region: "westeurope"}).StartStreaming(context.Background(), ports.StreamingConfig{}); err == nil {
+		t.Fatalf("expected missing key error")
+	}
+	if _, err := NewProvider(Config{APIKey: "key"}).StartStreaming(context.Background(), ports.StreamingConfig{}); err == nil {
+		t.Fatalf("expected missing region error")
+	}
+}
+
+func TestBuildRecognitionURL(t *testing.T) {
+	t.Parallel()
+
+	url, err := buildRecognitionURL(NewProvider(Config{Region: "westeurope"}).cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if url != "wss://westeurope.stt.speech.microsoft.com/speech/recognition/conversation/cognitiveservices/v1?format=detailed&language=en-US" {
+		t.Fatalf("unexpected url: %s", url)
+	}
+
+	url, err = buildRecognitionURL(Config{APIBaseURL: "http://localhost:5000/", Language: "de-DE"})
+	if err != nil || !strings.HasPrefix(url, "ws://localhost:5000/speech/") || !strings.Contains(url, "language=de-DE") {
+		t.Fatalf("unexpected override url %s: %v", url, err)
+	}
+}
+
+func TestParseMessage(t *testing.T) {
+	t.Parallel()
+
+	path, body := parseMessage([]byte("X-RequestId: abc\r\nPath: Speech.Phrase\r\nContent-Type: application/json\r\n\r\n{}"))
+	if path != "speech.phrase" || string(body) != "{}" {
+		t.Fatalf("unexpected message %q %q", path, body)
+	}
+	if path, _ := parseMessage([]byte("no headers")); path != "" {
+		t.Fatalf("expected no path, got %q", path)
+	}
+}
+
+func TestStreamingSessionMapsHypothesesAndPhrases(t *testing.T) {
+	t.Parallel()
+
+	var key string
+	var paths []string
+	var lastAudio int
+	server := newTestServer(t, &key, func(conn *websocket.Conn) {
+		send := func(path, body string) {
+			_ = conn.WriteMessage(websocket.TextMessage, []byte("Path: "+path+"\r\nContent-Type: application/json\r\n\r\n"+body))
+		}
+		for {
+			kind, payload, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if kind == websocket.TextMessage {
+				path, _ := parseMessage(payload)
+				paths = append(paths, path)
+				continue
+			}
+			headerLen := int(binary.BigEndian.Uint16(payload))
+			path, _ := parseMessage(append(payload[2:2+headerLen:2+headerLen], '\r', '\n'))
+			paths = append(paths, path)
+			lastAudio = len(payload) - 2 - headerLen
+			if lastAudio == 0 {
+				break
+			}
+		}
+		send("turn.start", `{}`)
+		send("speech.hypothesis", `{"Text":"hello wor","Offset":5000000,"Duration":3000000}`)
+		send("speech.phrase", `{"RecognitionStatus":"Success","DisplayText":"Hello world.","Offset":5000000,"Duration":10000000,"NBest":[{"Confidence":0.87,"Display":"Hello world."}]}`)
+		send("speech.phrase", `{"RecognitionStatus":"InitialSilenceTimeout","Offset":0,"Duration":0}`)
+		send("turn.end", `{}`)
+	})
+
+	provider := NewProvider(Config{APIKey: "azure-key", APIBaseURL: server.URL})
+	session, err := provider.StartStreaming(context.Background(), ports.StreamingConfig{InterimResults: true})
+	if err != nil {
+		t.Fatalf("start streaming failed: %v", err)
+	}
+	if err := session.SendAudio(make([]byte, 320)); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	_ = session.CloseSend()
+
+	var events []domain.TranscriptEvent
+	for event := range session.Events() {
+		events = append(events, event)
+	}
+	if err := session.Wait(); err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	if key != "azure-key" {
+		t.Fatalf("unexpected subscription key %q", key)
+	}
+	if strings.Join(paths, ",") != "speech.config,audio,audio,audio" || lastAudio != 0 {
+		t.Fatalf("unexpected client messages %v", paths)
+	}
+	if len(events) != 2 || events[0].Kind != domain.TranscriptKindPartial || events[0].Text != "hello wor" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+	final := events[1]
+	if final.Kind != domain.TranscriptKindFinal || final.Text != "Hello world." || final.Confidence != 0.87 {
+		t.Fatalf("unexpected final event: %+v", final)
+	}
+	if final.Start != 500*time.Millisecond || final.Duration != time.Second {
+		t.Fatalf("unexpected timing: start=%s duration=%s", final.Start, final.Duration)
+	}
+}
+
+func newTestServer(t *testing.T, key *string, handler func(conn *websocket.Conn)) *httptest.Server {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*key = r.Header.Get("Ocp-Apim-Subscription-Key")
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		handler(conn)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
@@ -0,0 +1,361 @@
+// Package customws streams audio to any websocket speech-to-text server that
+// speaks coldmic's JSON protocol, so self-hosted servers need no Go code of
+// their own.
+//
+// The client connects to the configured URL with sample_rate, channels,
+// encoding (linear16) and interim (true or false) query parameters, sends
+// audio as binary frames of raw PCM, and sends the text frame
+// {"type":"close"} once the recording ends.
+//
+// The server answers with JSON text frames:
+//
+//	{"kind":"partial","text":"hello wor"}
+//	{"kind":"final","text":"Hello world.","confidence":0.93,"start":0.5,"duration":1.0}
+//	{"kind":"error","message":"model not loaded"}
+//	{"kind":"done"}
+//
+// confidence, start and duration (in seconds) are optional. The server sends
+// done, or closes the websocket, after the last final transcript.
+package customws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/netproxy"
+	"coldmic/internal/ports"
+)
+
+// Config controls the custom websocket provider.
+type Config struct {
+	URL string
+	// Header is an optional "Name: value" header sent on connect, such as an
+	// API key for the server.
+	Header string
+	// Proxy is an explicit proxy URL; empty uses the proxy environment.
+	Proxy string
+}
+
+// Provider implements ports.TranscriptionProvider for servers speaking the
+// protocol described in the package documentation.
+type Provider struct {
+	cfg Config
+}
+
+func NewProvider(cfg Config) *Provider {
+	return &Provider{cfg: cfg}
+}
+
+// Capabilities describes what the protocol can carry; whether partials and
+// timing arrive depends on the server.
+func (p *Provider) Capabilities() domain.Capabilities {
+	return domain.Capabilities{
+		Streaming:    true,
+		LivePartials: true,
+		Timestamps:   true,
+		Confidence:   true,
+	}
+}
+
+// ProviderNames names the service usage is priced under.
+func (p *Provider) ProviderNames() []string {
+	return []string{"customws"}
+}
+
+func (p *Provider) StartStreaming(ctx context.Context, cfg ports.StreamingConfig) (ports.StreamingSession, error) {
+	if strings.TrimSpace(p.cfg.URL) == "" {
+		return nil, errors.New("COLDMIC_CUSTOM_WS_URL is not configured")
+	}
+	wsURL, err := buildStreamURL(p.cfg, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := http.Header{}
+	if p.cfg.Header != "" {
+		name, value, ok := strings.Cut(p.cfg.Header, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid COLDMIC_CUSTOM_WS_HEADER %q (expected \"Name: value\")", name)
+		}
+		headers.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	conn, _, err := netproxy.Dialer(p.cfg.Proxy).DialContext(ctx, wsURL, headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to custom websocket: %w", err)
+	}
+	debuglog.Printf("custom websocket connected url=%s", wsURL)
+
+	session := &streamingSession{
+		conn:     conn,
+		events:   make(chan domain.TranscriptEvent, 64),
+		audio:    make(chan []byte, 32),
+		readDone: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	session.wg.Add(2)
+	go session.readLoop()
+	go session.writeLoop()
+	go func() {
+		session.wg.Wait()
+		close(session.events)
+		close(session.done)
+		_ = conn.Close()
+	}()
+
+	go func() {
+		<-ctx.Done()
+		_ = session.Close()
+	}()
+
+	return session, nil
+}
+
+type streamingSession struct {
+	conn *websocket.Conn
+
+	events chan domain.TranscriptEvent
+	audio  chan []byte
+	// readDone stops the writer once the server ends the stream.
+	readDone chan struct{}
+	done     chan struct{}
+
+	wg sync.WaitGroup
+
+	errMu sync.Mutex
+	err   error
+
+	closeSendOnce sync.Once
+	closeOnce     sync.Once
+	sendMu        sync.RWMutex
+	sendClosed    bool
+}
+
+func (s *streamingSession) SendAudio(chunk []byte) error {
+	if len(chunk) == 0 {
+		return nil
+	}
+
+	s.sendMu.RLock()
+	closed := s.sendClosed
+	s.sendMu.RUnlock()
+	if closed {
+		return errors.New("audio stream is already closed")
+	}
+
+	copied := append([]byte(nil), chunk...)
+	select {
+	case s.audio <- copied:
+		return nil
+	case <-s.done:
+		if err := s.waitErr(); err != nil {
+			return err
+		}
+		return errors.New("session closed")
+	}
+}
+
+func (s *streamingSession) CloseSend() error {
+	s.closeSendOnce.Do(func() {
+		s.sendMu.Lock()
+		s.sendClosed = true
+		close(s.audio)
+		s.sendMu.Unlock()
+	})
+	return nil
+}
+
+func (s *streamingSession) Events() <-chan domain.TranscriptEvent {
+	return s.events
+}
+
+func (s *streamingSession) Wait() error {
+	<-s.done
+	return s.waitErr()
+}
+
+func (s *streamingSession) Close() error {
+	s.closeOnce.Do(func() {
+		_ = s.CloseSend()
+		_ = s.conn.Close()
+	})
+	<-s.done
+	return s.waitErr()
+}
+
+func (s *streamingSession) waitErr() error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	return s.err
+}
+
+func (s *streamingSession) setErr(err error) {
+	if err == nil || isExpectedShutdownErr(err) {
+		return
+	}
+
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+func isExpectedShutdownErr(err error) bool {
+	if errors.Is(err, net.ErrClosed) || errors.Is(err, websocket.ErrCloseSent) {
+		return true
+	}
+
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) {
+		return false
+	}
+
+	switch closeErr.Code {
+	case websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseNoStatusReceived:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *streamingSession) writeLoop() {
+	defer s.wg.Done()
+
+	for {
+		var chunk []byte
+		var ok bool
+		select {
+		case chunk, ok = <-s.audio:
+		case <-s.readDone:
+			return
+		}
+		if !ok {
+			break
+		}
+		if err := s.conn.WriteMessage(websocket.BinaryMessage, chunk); err != nil {
+			debuglog.Printf("custom websocket audio send failed: %v", err)
+			s.setErr(fmt.Errorf("failed to send audio: %w", err))
+			return
+		}
+	}
+
+	if err := s.conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"close"}`)); err != nil {
+		debuglog.Printf("custom websocket close failed: %v", err)
+		s.setErr(fmt.Errorf("failed to close stream: %w", err))
+		return
+	}
+	debuglog.Printf("custom websocket sent close")
+}
+
+func (s *streamingSession) readLoop() {
+	defer s.wg.Done()
+	defer close(s.readDone)
+
+	for {
+		_, payload, err := s.conn.ReadMessage()
+		if err != nil {
+			debuglog.Printf("custom websocket read failed: %v", err)
+			s.setErr(fmt.Errorf("failed to read provider event: %w", err))
+			return
+		}
+
+		var message streamMessage
+		if err := json.Unmarshal(payload, &message); err != nil {
+			debuglog.Printf("custom websocket ignored non-json payload bytes=%d", len(payload))
+			continue
+		}
+
+		switch message.Kind {
+		case "error":
+			s.setErr(fmt.Errorf("custom websocket error: %s", strings.TrimSpace(message.Message)))
+			return
+		case "done":
+			return
+		case "partial", "final":
+			text := strings.TrimSpace(message.Text)
+			if text == "" {
+				continue
+			}
+			event := domain.TranscriptEvent{
+				Kind:       domain.TranscriptKind(message.Kind),
+				Text:       text,
+				Confidence: message.Confidence,
+				Start:      secondsToDuration(message.Start),
+				Duration:   secondsToDuration(message.Duration),
+			}
+			event.IsSpeechFinal = event.Kind == domain.TranscriptKindFinal
+			s.emit(event)
+		default:
+			debuglog.Printf("custom websocket ignored message kind=%q", message.Kind)
+		}
+	}
+}
+
+func (s *streamingSession) emit(event domain.TranscriptEvent) {
+	select {
+	case s.events <- event:
+	case <-s.done:
+	default:
+	}
+}
+
+type streamMessage struct {
+	Kind       string  `json:"kind"`
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence"`
+	Start      float64 `json:"start"`
+	Duration   float64 `json:"duration"`
+	Message    string  `json:"message"`
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func buildStreamURL(providerCfg Config, streamCfg ports.StreamingConfig) (string, error) {
+	streamURL, err := url.Parse(strings.TrimSpace(providerCfg.URL))
+	if err != nil {
+		return "", fmt.Errorf("invalid custom websocket URL: %w", err)
+	}
+	switch streamURL.Scheme {
+	case "ws", "wss":
+	case "http":
+		streamURL.Scheme = "ws"
+	case "https":
+		streamURL.Scheme = "wss"
+	default:
+		return "", fmt.Errorf("invalid custom websocket URL scheme %q", streamURL.Scheme)
+	}
+
+	if streamCfg.SampleRate <= 0 {
+		streamCfg.SampleRate = 16000
+	}
+	if streamCfg.Channels <= 0 {
+		streamCfg.Channels = 1
+	}
+	query := streamURL.Query()
+	query.Set("sample_rate", strconv.Itoa(streamCfg.SampleRate))
+	query.Set("channels", strconv.Itoa(streamCfg.Channels))
+	query.Set("encoding", "linear16")
+	query.Set("interim", strconv.FormatBool(streamCfg.InterimResults))
+	streamURL.RawQuery = query.Encode()
+	return streamURL.String(), nil
+}
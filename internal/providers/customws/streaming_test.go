@@ -0,0 +1,139 @@
+package customws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestProviderRequiresURL(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewProvider(Config{}).StartStreaming(context.Background(), ports.StreamingConfig{}); err == nil {
+		t.Fatalf("expected missing url error")
+	}
+}
+
+func TestBuildStreamURL(t *testing.T) {
+	t.Parallel()
+
+	url, err := buildStreamURL(Config{URL: "http://localhost:9000/stream?model=small"}, ports.StreamingConfig{InterimResults: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if url != "ws://localhost:9000/stream?channels=1&encoding=linear16&interim=true&model=small&sample_rate=16000" {
+		t.Fatalf("unexpected url: %s", url)
+	}
+	if _, err := buildStreamURL(Config{URL: "ftp://localhost"}, ports.StreamingConfig{}); err == nil {
+		t.Fatalf("expected unsupported scheme to be rejected")
+	}
+}
+
+func TestProviderRejectsMalformedHeader(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewProvider(Config{URL: "ws://localhost:1", Header: "no-colon"}).StartStreaming(context.Background(), ports.StreamingConfig{})
+	if err == nil || !strings.Contains(err.Error(), "COLDMIC_CUSTOM_WS_HEADER") {
+		t.Fatalf("expected malformed header error, got %v", err)
+	}
+}
+
+func TestStreamingSessionSpeaksProtocol(t *testing.T) {
+	t.Parallel()
+
+	var header string
+	var messages []string
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("Authorization")
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			kind, payload, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if kind == websocket.BinaryMessage {
+				messages = append(messages, "audio")
+				continue
+			}
+			messages = append(messages, string(payload))
+			break
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"kind":"partial","text":" hello wor"}`))
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"kind":"final","text":"Hello world.","confidence":0.93,"start":0.5,"duration":1}`))
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"kind":"done"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	provider := NewProvider(Config{URL: server.URL, Header: "Authorization: Bearer secret"})
+	session, err := provider.StartStreaming(context.Background(), ports.StreamingConfig{InterimResults: true})
+	if err != nil {
+		t.Fatalf("start streaming failed: %v", err)
+	}
+	if err := session.SendAudio(make([]byte, 320)); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	_ = session.CloseSend()
+
+	var events []domain.TranscriptEvent
+	for event := range session.Events() {
+		events = append(events, event)
+	}
+	if err := session.Wait(); err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	if header != "Bearer secret" {
+		t.Fatalf("unexpected auth header %q", header)
+	}
+	if strings.Join(messages, ",") != `audio,{"type":"close"}` {
+		t.Fatalf("unexpected client messages %v", messages)
+	}
+	if len(events) != 2 || events[0].Kind != domain.TranscriptKindPartial || events[0].Text != "hello wor" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+	final := events[1]
+	if final.Kind != domain.TranscriptKindFinal || final.Text != "Hello world." || final.Confidence != 0.93 {
+		t.Fatalf("unexpected final event: %+v", final)
+	}
+	if final.Start != 500*time.Millisecond || final.Duration != time.Second {
+		t.Fatalf("unexpected timing: start=%s duration=%s", final.Start, final.Duration)
+	}
+}
+
+func TestStreamingSessionReportsServerError(t *testing.T) {
+	t.Parallel()
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"kind":"error","message":"model not loaded"}`))
+		_, _, _ = conn.ReadMessage()
+	}))
+	t.Cleanup(server.Close)
+
+	session, err := NewProvider(Config{URL: server.URL}).StartStreaming(context.Background(), ports.StreamingConfig{})
+	if err != nil {
+		t.Fatalf("start streaming failed: %v", err)
+	}
+	for range session.Events() {
+	}
+	if err := session.Wait(); err == nil || !strings.Contains(err.Error(), "model not loaded") {
+		t.Fatalf("expected server error, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package deepgram
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+
+	"github.com/gorilla/websocket"
+
+	"coldmic/internal/domain"
+)
+
+// deepgramError is the JSON body Deepgram returns with a rejected request.
+type deepgramError struct {
+	ErrCode  string `json:"err_code"`
+	ErrMsg   string `json:"err_msg"`
+	Category string `json:"category"`
+	Message  string `json:"message"`
+}
+
+// handshakeError classifies a rejected websocket handshake by its HTTP
+// status and error body, returning err unchanged when the cause is unknown.
+func handshakeError(resp *http.Response, err error) error {
+	if resp == nil {
+		return err
+	}
+	var body deepgramError
+	if resp.Body != nil {
+		payload, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		_ = json.Unmarshal(payload, &body)
+	}
+	code := classify(resp.StatusCode, firstNonEmpty(body.ErrCode, body.Category))
+	if code == "" {
+		return err
+	}
+	message := firstNonEmpty(body.ErrMsg, body.Message, resp.Header.Get("dg-error"), resp.Status)
+	return &domain.ProviderError{Provider: "deepgram", Code: code, Message: message, Err: err}
+}
+
+// transientDialError reports whether a failed dial is worth retrying: name
+// resolution failures, resets, refusals and timeouts, which a network blip
+// causes, rather than a rejected handshake or a bad URL.
+func transientDialError(err error) bool {
+	var providerErr *domain.ProviderError
+	if errors.As(err, &providerErr) {
+		return false
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// classify maps Deepgram error codes, falling back to the HTTP status.
+func classify(status int, errCode string) domain.ErrorCode {
+	switch strings.ToUpper(errCode) {
+	case "INVALID_AUTH", "INSUFFICIENT_PERMISSIONS":
+		return domain.ErrorCodeInvalidKey
+	case "ASR_PAYMENT_REQUIRED", "INSUFFICIENT_CREDITS", "PAYMENT_REQUIRED":
+		return domain.ErrorCodeInsufficientCredits
+	case "BAD_REQUEST", "INVALID_QUERY_PARAMETER", "UNSUPPORTED_MODEL", "DATA-0000":
+		return domain.ErrorCodeUnsupportedConfig
+	}
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return domain.ErrorCodeInvalidKey
+	case http.StatusPaymentRequired:
+		return domain.ErrorCodeInsufficientCredits
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity:
+		return domain.ErrorCodeUnsupportedConfig
+	default:
+		return ""
+	}
+}
+
+// closeError classifies a close frame: Deepgram closes with 1008 when the
+// audio does not match the configured encoding, and may name an error code
+// in the reason.
+func closeError(err error) error {
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) {
+		return err
+	}
+	reasonCode, _, _ := strings.Cut(strings.TrimSpace(closeErr.Text), " ")
+	code := classify(0, strings.TrimRight(reasonCode, ":,"))
+	if code == "" && closeErr.Code == websocket.ClosePolicyViolation {
+		code = domain.ErrorCodeUnsupportedConfig
+	}
+	if code == "" {
+		return err
+	}
+	return &domain.ProviderError{Provider: "deepgram", Code: code, Message: firstNonEmpty(closeErr.Text, "connection closed by Deepgram"), Err: err}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,79 @@
+package deepgram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/websocket"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestClassify(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		status  int
+		errCode string
+		want    domain.ErrorCode
+	}{
+		{http.StatusUnauthorized, "", domain.ErrorCodeInvalidKey},
+		{http.StatusBadRequest, "INVALID_AUTH", domain.ErrorCodeInvalidKey},
+		{http.StatusPaymentRequired, "", domain.ErrorCodeInsufficientCredits},
+		{0, "ASR_PAYMENT_REQUIRED", domain.ErrorCodeInsufficientCredits},
+		{http.StatusBadRequest, "", domain.ErrorCodeUnsupportedConfig},
+		{0, "DATA-0000", domain.ErrorCodeUnsupportedConfig},
+		{http.StatusInternalServerError, "", ""},
+	}
+	for _, tc := range cases {
+		if got := classify(tc.status, tc.errCode); got != tc.want {
+			t.Fatalf("classify(%d, %q) = %q, want %q", tc.status, tc.errCode, got, tc.want)
+		}
+	}
+}
+
+func TestStartStreamingClassifiesRejectedHandshake(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"err_code":"INVALID_AUTH","err_msg":"Invalid credentials.","request_id":"r1"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	_, err := NewProvider(Config{APIKey: "bad", APIBaseURL: server.URL}).StartStreaming(context.Background(), ports.StreamingConfig{})
+	var providerErr *domain.ProviderError
+	if !errors.As(err, &providerErr) || providerErr.Code != domain.ErrorCodeInvalidKey || providerErr.Message != "Invalid credentials." {
+		t.Fatalf("expected invalid key error, got %v", err)
+	}
+	if !errors.Is(err, websocket.ErrBadHandshake) {
+		t.Fatalf("expected the handshake error to stay in the chain: %v", err)
+	}
+}
+
+func TestStreamingSessionClassifiesPolicyClose(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, func(conn *websocket.Conn) {
+		_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "DATA-0000: payload could not be decoded as audio"))
+		_, _, _ = conn.ReadMessage()
+	})
+
+	session := startTestSession(t, server)
+	_ = session.CloseSend()
+	for range session.Events() {
+	}
+	err := session.Wait()
+	if domain.ErrorCodeFor(err, domain.ErrorCodeTranscription) != domain.ErrorCodeUnsupportedConfig {
+		t.Fatalf("expected unsupported config error, got %v", err)
+	}
+	var providerErr *domain.ProviderError
+	if errors.As(closeError(&websocket.CloseError{Code: websocket.CloseInternalServerErr, Text: "NET-0001"}), &providerErr) {
+		t.Fatalf("expected unknown close errors to pass through, got %v", providerErr)
+	}
+}
@@ -2,22 +2,47 @@ package deepgram
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/rand/v2"
 	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gorilla/websocket"
 
 	"coldmic/internal/debuglog"
 	"coldmic/internal/domain"
+	"coldmic/internal/netproxy"
 	"coldmic/internal/ports"
 )
 
+// keepAliveInterval is how long the stream may go without audio before a
+// KeepAlive message is sent; Deepgram closes streams idle for about 10s.
+const keepAliveInterval = 5 * time.Second
+
+// A connection that drops mid-recording is redialled up to
+// reconnectAttempts times, waiting reconnectBackoff before the first attempt
+// and doubling it after each failure. Audio is buffered meanwhile, up to
+// reconnectBufferLimit bytes (about five minutes of 16 kHz mono).
+const (
+	reconnectAttempts    = 5
+	reconnectBackoff     = 250 * time.Millisecond
+	reconnectBufferLimit = 10 << 20
+)
+
+// dialBackoff is the wait before the first retry of a transient failure to
+// open the stream; it doubles after each retry, with jitter.
+const dialBackoff = 200 * time.Millisecond
+
 // Config controls Deepgram websocket settings.
 type Config struct {
 	APIKey      string
@@ -25,11 +50,105 @@ type Config struct {
 	Model       string
 	Language    string
 	SmartFormat bool
+	// Auth selects how APIKey is sent: "token" (Deepgram's default),
+	// "bearer", "none", or "header:<Name>" for the bare key in a custom
+	// header, as self-hosted gateways often expect.
+	Auth string
+	TLS  TLSConfig
+	// Tags are sent as Deepgram request tags for usage attribution.
+	Tags []string
+	// Callback is passed as Deepgram's callback URL.
+	Callback string
+	// Diarize asks Deepgram to label each word with its speaker.
+	Diarize bool
+	// Endpointing is the endpointing parameter: milliseconds of silence or
+	// "false"; empty leaves Deepgram's default.
+	Endpointing string
+	// UtteranceEndMS sets utterance_end_ms when positive.
+	UtteranceEndMS int
+	VADEvents      bool
+	// Punctuate, Numerals and FillerWords fine-tune formatting beyond
+	// SmartFormat; false leaves Deepgram's default.
+	Punctuate   bool
+	Numerals    bool
+	FillerWords bool
+	// ProfanityFilter masks profanity in transcripts.
+	ProfanityFilter bool
+	// Redact lists the entities Deepgram masks in transcripts: pci, ssn or
+	// numbers.
+	Redact []string
+	// DetectLanguage asks Deepgram to detect the spoken language, which is
+	// reported on each transcript event.
+	DetectLanguage bool
+	// Proxy is an explicit proxy URL; empty uses the proxy environment.
+	Proxy string
+	// DialRetries is how many times StartStreaming retries a transient dial
+	// failure, such as a DNS error or reset, before giving up.
+	DialRetries int
+}
+
+// TLSConfig customizes TLS for self-hosted deployments.
+type TLSConfig struct {
+	// CAFile adds a PEM bundle of trusted certificate authorities.
+	CAFile string
+	// ClientCert and ClientKey enable mutual TLS.
+	ClientCert         string
+	ClientKey          string
+	InsecureSkipVerify bool
+}
+
+func (c TLSConfig) build() (*tls.Config, error) {
+	if c == (TLSConfig{}) {
+		return nil, nil
+	}
+	config := &tls.Config{InsecureSkipVerify: c.InsecureSkipVerify}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Deepgram CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", c.CAFile)
+		}
+		config.RootCAs = pool
+	}
+	if c.ClientCert != "" || c.ClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(c.ClientCert, c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load Deepgram client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// authHeader returns the header carrying key for the auth style, or an
+// empty name when no credentials are sent.
+func authHeader(auth string, key string) (string, string, error) {
+	switch {
+	case auth == "" || auth == "token":
+		return "Authorization", "Token " + key, nil
+	case auth == "bearer":
+		return "Authorization", "Bearer " + key, nil
+	case auth == "none":
+		return "", "", nil
+	case strings.HasPrefix(auth, "header:") && strings.TrimSpace(strings.TrimPrefix(auth, "header:")) != "":
+		return strings.TrimSpace(strings.TrimPrefix(auth, "header:")), key, nil
+	default:
+		return "", "", fmt.Errorf("unsupported Deepgram auth %q (expected token, bearer, none or header:<Name>)", auth)
+	}
 }
 
 // Provider implements ports.TranscriptionProvider for Deepgram.
 type Provider struct {
-	cfg Config
+	cfg              Config
+	keepAlive        time.Duration
+	reconnectBackoff time.Duration
+	dialBackoff      time.Duration
 }
 
 func NewProvider(cfg Config) *Provider {
@@ -39,11 +158,30 @@ func NewProvider(cfg Config) *Provider {
 	if cfg.Model == "" {
 		cfg.Model = "nova-2"
 	}
-	return &Provider{cfg: cfg}
+	return &Provider{cfg: cfg, keepAlive: keepAliveInterval, reconnectBackoff: reconnectBackoff, dialBackoff: dialBackoff}
+}
+
+// Capabilities describes Deepgram live transcription.
+func (p *Provider) Capabilities() domain.Capabilities {
+	return domain.Capabilities{
+		Streaming:    true,
+		LivePartials: true,
+		Timestamps:   true,
+		WordTimings:  true,
+		Confidence:   true,
+		Diarization:  true,
+		Keywords:     true,
+		Multichannel: true,
+	}
+}
+
+// ProviderNames names the service usage is priced under.
+func (p *Provider) ProviderNames() []string {
+	return []string{"deepgram"}
 }
 
 func (p *Provider) StartStreaming(ctx context.Context, cfg ports.StreamingConfig) (ports.StreamingSession, error) {
-	if strings.TrimSpace(p.cfg.APIKey) == "" {
+	if strings.TrimSpace(p.cfg.APIKey) == "" && p.cfg.Auth != "none" {
 		return nil, errors.New("DEEPGRAM_API_KEY is not configured")
 	}
 
@@ -53,30 +191,54 @@ func (p *Provider) StartStreaming(ctx context.Context, cfg ports.StreamingConfig
 	}
 
 	headers := http.Header{}
-	headers.Set("Authorization", "Token "+p.cfg.APIKey)
+	name, value, err := authHeader(p.cfg.Auth, p.cfg.APIKey)
+	if err != nil {
+		return nil, err
+	}
+	if name != "" {
+		headers.Set(name, value)
+	}
+	tlsConfig, err := p.cfg.TLS.build()
+	if err != nil {
+		return nil, err
+	}
+	dialer := netproxy.Dialer(p.cfg.Proxy)
+	dialer.TLSClientConfig = tlsConfig
+	dial := func(ctx context.Context) (*websocket.Conn, error) {
+		conn, resp, err := dialer.DialContext(ctx, wsURL, headers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to Deepgram websocket: %w", handshakeError(resp, err))
+		}
+		return conn, nil
+	}
 
-	conn, _, err := websocket.DefaultDialer.DialContext(ctx, wsURL, headers)
+	conn, err := p.dialWithRetry(ctx, dial)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to Deepgram websocket: %w", err)
+		return nil, err
 	}
 	debuglog.Printf("deepgram connected url=%s", wsURL)
 
 	session := &streamingSession{
-		conn:   conn,
-		events: make(chan domain.TranscriptEvent, 64),
-		audio:  make(chan []byte, 32),
-		done:   make(chan struct{}),
+		ctx:            ctx,
+		dial:           dial,
+		conn:           conn,
+		events:         make(chan domain.TranscriptEvent, 64),
+		audio:          make(chan []byte, 32),
+		done:           make(chan struct{}),
+		finalize:       make(chan struct{}, 1),
+		finalized:      make(chan struct{}, 1),
+		conns:          make(chan *websocket.Conn),
+		closing:        make(chan struct{}),
+		writeStop:      make(chan struct{}),
+		writeDone:      make(chan struct{}),
+		keepAlive:      p.keepAlive,
+		backoff:        p.reconnectBackoff,
+		bytesPerSecond: bytesPerSecond(cfg),
+		channels:       transcribedChannels(cfg),
 	}
 
-	session.wg.Add(2)
-	go session.readLoop()
-	go session.writeLoop()
-	go func() {
-		session.wg.Wait()
-		close(session.events)
-		close(session.done)
-		_ = conn.Close()
-	}()
+	go session.writeLoop(conn)
+	go session.run(conn)
 
 	go func() {
 		<-ctx.Done()
@@ -86,14 +248,95 @@ func (p *Provider) StartStreaming(ctx context.Context, cfg ports.StreamingConfig
 	return session, nil
 }
 
+// dialWithRetry opens the stream, retrying transient failures up to
+// DialRetries times so a network blip does not fail the whole recording.
+func (p *Provider) dialWithRetry(ctx context.Context, dial func(context.Context) (*websocket.Conn, error)) (*websocket.Conn, error) {
+	delay := p.dialBackoff
+	for retry := 0; ; retry++ {
+		conn, err := dial(ctx)
+		if err == nil {
+			return conn, nil
+		}
+		if retry >= p.cfg.DialRetries || !transientDialError(err) || ctx.Err() != nil {
+			return nil, err
+		}
+		wait := jitter(delay)
+		debuglog.Printf("deepgram dial retry=%d in %s: %v", retry+1, wait, err)
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		}
+		delay *= 2
+	}
+}
+
+// jitter spreads a wait over its upper half so clients that failed
+// together do not retry together.
+func jitter(delay time.Duration) time.Duration {
+	if delay <= 1 {
+		return delay
+	}
+	return delay/2 + rand.N(delay/2)
+}
+
+// bytesPerSecond is the linear16 data rate of cfg, with the defaults
+// buildListenURL applies.
+func bytesPerSecond(cfg ports.StreamingConfig) int64 {
+	sampleRate := cfg.SampleRate
+	if sampleRate <= 0 {
+		sampleRate = 16000
+	}
+	return int64(sampleRate) * int64(max(cfg.Channels, 1)) * 2
+}
+
+// transcribedChannels is how many channels Deepgram transcribes separately.
+func transcribedChannels(cfg ports.StreamingConfig) int32 {
+	if cfg.Multichannel && cfg.Channels > 1 {
+		return int32(cfg.Channels)
+	}
+	return 1
+}
+
 type streamingSession struct {
-	conn *websocket.Conn
+	ctx  context.Context
+	dial func(ctx context.Context) (*websocket.Conn, error)
 
 	events chan domain.TranscriptEvent
 	audio  chan []byte
 	done   chan struct{}
-
-	wg sync.WaitGroup
+	// finalize asks the write loop to send Finalize after the queued audio;
+	// finalized is signalled once every channel has sent its from_finalize
+	// response, counted down in pendingAcks.
+	finalize    chan struct{}
+	finalized   chan struct{}
+	channels    int32
+	pendingAcks atomic.Int32
+	// conns hands the write loop each new connection, or nil once the
+	// current one has dropped.
+	conns chan *websocket.Conn
+	// closing is closed by Close and stops reconnecting.
+	closing   chan struct{}
+	writeStop chan struct{}
+	writeDone chan struct{}
+	// keepAlive is the idle time after which a KeepAlive message is sent.
+	keepAlive time.Duration
+	// backoff is the wait before the first reconnect attempt.
+	backoff        time.Duration
+	bytesPerSecond int64
+	// sentBytes counts the audio written to all connections, so results of
+	// a new connection can be shifted to the stream's timeline.
+	sentBytes atomic.Int64
+	// streamEnded is set once CloseStream has been written.
+	streamEnded atomic.Bool
+
+	connMu sync.Mutex
+	conn   *websocket.Conn
+
+	notifyMu sync.Mutex
+	notify   func(err error)
 
 	errMu sync.Mutex
 	err   error
@@ -138,6 +381,50 @@ func (s *streamingSession) CloseSend() error {
 	return nil
 }
 
+// Finalize asks Deepgram to transcribe the audio sent so far and waits for
+// the response marked from_finalize.
+func (s *streamingSession) Finalize(ctx context.Context) error {
+	s.sendMu.RLock()
+	closed := s.sendClosed
+	s.sendMu.RUnlock()
+	if closed {
+		return errors.New("audio stream is already closed")
+	}
+
+	select {
+	case <-s.finalized:
+	default:
+	}
+	s.pendingAcks.Store(s.channels)
+	select {
+	case s.finalize <- struct{}{}:
+	case <-s.done:
+		return errors.New("session closed")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-s.finalized:
+		return nil
+	case <-s.done:
+		if err := s.waitErr(); err != nil {
+			return err
+		}
+		return errors.New("session closed")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// OnReconnect reports each dropped connection to fn. The session buffers
+// audio and reconnects, so the drop is not fatal.
+func (s *streamingSession) OnReconnect(fn func(err error)) {
+	s.notifyMu.Lock()
+	defer s.notifyMu.Unlock()
+	s.notify = fn
+}
+
 func (s *streamingSession) Events() <-chan domain.TranscriptEvent {
 	return s.events
 }
@@ -149,8 +436,11 @@ func (s *streamingSession) Wait() error {
 
 func (s *streamingSession) Close() error {
 	s.closeOnce.Do(func() {
+		close(s.closing)
 		_ = s.CloseSend()
+		s.connMu.Lock()
 		_ = s.conn.Close()
+		s.connMu.Unlock()
 	})
 	<-s.done
 	return s.waitErr()
@@ -195,34 +485,232 @@ func isExpectedShutdownErr(err error) bool {
 	}
 }
 
-func (s *streamingSession) writeLoop() {
-	defer s.wg.Done()
+// run reads each connection in turn, reconnecting when one drops before the
+// stream has ended, and shuts the session down afterwards.
+func (s *streamingSession) run(conn *websocket.Conn) {
+	defer func() {
+		close(s.writeStop)
+		<-s.writeDone
+		s.connMu.Lock()
+		_ = s.conn.Close()
+		s.connMu.Unlock()
+		close(s.events)
+		close(s.done)
+	}()
 
-	for chunk := range s.audio {
-		if err := s.conn.WriteMessage(websocket.BinaryMessage, chunk); err != nil {
-			debuglog.Printf("deepgram audio send failed: %v", err)
-			s.setErr(fmt.Errorf("failed to send audio: %w", err))
+	var base time.Duration
+	for {
+		dropped, err := s.readLoop(conn, base)
+		if !dropped || !s.recoverable(err) {
+			s.setErr(err)
+			return
+		}
+		_ = conn.Close()
+		s.notifyDrop(err)
+		// Once the write loop has let go of the connection, sentBytes
+		// holds the audio the new connection starts after.
+		s.conns <- nil
+		base = s.offset()
+		if conn = s.redial(err); conn == nil {
 			return
 		}
+		s.conns <- conn
 	}
+}
 
-	if err := s.conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"CloseStream"}`)); err != nil {
-		debuglog.Printf("deepgram close stream failed: %v", err)
-		s.setErr(fmt.Errorf("failed to close stream: %w", err))
-		return
+// recoverable reports whether a dropped connection should be replaced:
+// audio is still to be delivered and Deepgram did not reject the stream.
+func (s *streamingSession) recoverable(err error) bool {
+	select {
+	case <-s.closing:
+		return false
+	default:
 	}
-	debuglog.Printf("deepgram sent CloseStream")
+	if s.streamEnded.Load() || s.ctx.Err() != nil {
+		return false
+	}
+	var providerErr *domain.ProviderError
+	return !errors.As(err, &providerErr)
+}
+
+func (s *streamingSession) notifyDrop(err error) {
+	debuglog.Printf("deepgram connection dropped, reconnecting: %v", err)
+	s.notifyMu.Lock()
+	notify := s.notify
+	s.notifyMu.Unlock()
+	if notify != nil {
+		notify(fmt.Errorf("deepgram connection dropped; buffering audio and reconnecting: %w", err))
+	}
+}
+
+// redial connects again, doubling the wait after each failed attempt. It
+// returns nil when the attempts are exhausted or the session is closed.
+func (s *streamingSession) redial(cause error) *websocket.Conn {
+	delay := s.backoff
+	lastErr := cause
+	for attempt := 1; attempt <= reconnectAttempts; attempt++ {
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-s.closing:
+			timer.Stop()
+			return nil
+		}
+		conn, err := s.dial(s.ctx)
+		if err != nil {
+			debuglog.Printf("deepgram reconnect attempt=%d failed: %v", attempt, err)
+			lastErr = err
+			var providerErr *domain.ProviderError
+			if errors.As(err, &providerErr) {
+				break
+			}
+			delay *= 2
+			continue
+		}
+
+		s.connMu.Lock()
+		s.conn = conn
+		s.connMu.Unlock()
+		select {
+		case <-s.closing:
+			// Close ran while dialling and missed this connection.
+			_ = conn.Close()
+			return nil
+		default:
+		}
+		debuglog.Printf("deepgram reconnected attempt=%d offset=%s", attempt, s.offset())
+		return conn
+	}
+	s.setErr(fmt.Errorf("failed to reconnect to Deepgram: %w", lastErr))
+	return nil
 }
 
-func (s *streamingSession) readLoop() {
-	defer s.wg.Done()
+func (s *streamingSession) offset() time.Duration {
+	if s.bytesPerSecond <= 0 {
+		return 0
+	}
+	return time.Duration(s.sentBytes.Load() * int64(time.Second) / s.bytesPerSecond)
+}
+
+// writeLoop owns all writes. Audio that cannot be sent while a connection
+// is being replaced is kept, up to reconnectBufferLimit bytes, and sent to
+// the next connection in order.
+func (s *streamingSession) writeLoop(conn *websocket.Conn) {
+	defer close(s.writeDone)
+
+	// Push-to-talk pauses leave the audio channel idle; keep the stream open
+	// until audio resumes or the session ends.
+	keepAlive := time.NewTicker(s.keepAlive)
+	defer keepAlive.Stop()
+
+	audio := s.audio
+	var backlog [][]byte
+	backlogBytes := 0
+	finalizing := false
+
+	// drop gives up on conn after a failed write; run sees the closed
+	// connection and reconnects.
+	drop := func(err error) {
+		debuglog.Printf("deepgram write failed: %v", err)
+		_ = conn.Close()
+		conn = nil
+	}
+	queue := func(chunk []byte) {
+		backlog = append(backlog, chunk)
+		backlogBytes += len(chunk)
+		for backlogBytes > reconnectBufferLimit {
+			debuglog.Printf("deepgram reconnect buffer full, dropping %d bytes", len(backlog[0]))
+			backlogBytes -= len(backlog[0])
+			backlog = backlog[1:]
+		}
+	}
+	// flush sends the queued audio, then a pending Finalize and, once the
+	// audio has ended, CloseStream.
+	flush := func() {
+		for conn != nil && len(backlog) > 0 {
+			if err := conn.WriteMessage(websocket.BinaryMessage, backlog[0]); err != nil {
+				drop(err)
+				return
+			}
+			s.sentBytes.Add(int64(len(backlog[0])))
+			backlogBytes -= len(backlog[0])
+			backlog = backlog[1:]
+		}
+		if conn == nil {
+			return
+		}
+		if finalizing {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"Finalize"}`)); err != nil {
+				drop(err)
+				return
+			}
+			debuglog.Printf("deepgram sent Finalize")
+			finalizing = false
+		}
+		if audio == nil && !s.streamEnded.Load() {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"CloseStream"}`)); err != nil {
+				drop(err)
+				return
+			}
+			debuglog.Printf("deepgram sent CloseStream")
+			s.streamEnded.Store(true)
+		}
+	}
 
 	for {
-		_, payload, err := s.conn.ReadMessage()
+		select {
+		case <-s.writeStop:
+			return
+		case conn = <-s.conns:
+			flush()
+			keepAlive.Reset(s.keepAlive)
+		case chunk, ok := <-audio:
+			if ok {
+				queue(chunk)
+			} else {
+				audio = nil
+			}
+			flush()
+			keepAlive.Reset(s.keepAlive)
+		case <-s.finalize:
+			// Finalize only covers audio Deepgram has already received.
+		drain:
+			for {
+				select {
+				case chunk, ok := <-audio:
+					if !ok {
+						audio = nil
+						break drain
+					}
+					queue(chunk)
+				default:
+					break drain
+				}
+			}
+			finalizing = true
+			flush()
+			keepAlive.Reset(s.keepAlive)
+		case <-keepAlive.C:
+			if conn == nil || s.streamEnded.Load() {
+				continue
+			}
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"KeepAlive"}`)); err != nil {
+				drop(err)
+				continue
+			}
+			debuglog.Printf("deepgram sent KeepAlive")
+		}
+	}
+}
+
+// readLoop emits the results of conn, shifted by base, until the connection
+// ends. dropped reports a failed read rather than an error event.
+func (s *streamingSession) readLoop(conn *websocket.Conn, base time.Duration) (bool, error) {
+	for {
+		_, payload, err := conn.ReadMessage()
 		if err != nil {
 			debuglog.Printf("deepgram read failed: %v", err)
-			s.setErr(fmt.Errorf("failed to read provider event: %w", err))
-			return
+			return true, fmt.Errorf("failed to read provider event: %w", closeError(err))
 		}
 
 		var response deepgramResponse
@@ -241,16 +729,41 @@ func (s *streamingSession) readLoop() {
 			}
 			debuglog.Printf("deepgram error event message=%q", message)
 			s.emit(domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "", IsSpeechFinal: true})
-			s.setErr(errors.New(message))
-			return
+			if code := classify(0, firstNonEmpty(response.ErrCode, response.Variant)); code != "" {
+				return false, &domain.ProviderError{Provider: "deepgram", Code: code, Message: message}
+			}
+			return false, errors.New(message)
+		}
+
+		if strings.EqualFold(response.Type, "UtteranceEnd") {
+			// Sent with utterance_end_ms when noise keeps speech_final from
+			// arriving; an empty final marks the pause.
+			s.emit(domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, IsSpeechFinal: true})
+			continue
 		}
 
-		transcript := extractTranscript(response)
+		alternative := extractTranscript(response)
+		transcript := alternative.Transcript
 		if transcript == "" {
+			s.ackFinalize(response)
 			continue
 		}
 
-		event := domain.TranscriptEvent{Text: transcript, IsSpeechFinal: response.SpeechFinal}
+		event := domain.TranscriptEvent{
+			Text:          transcript,
+			Confidence:    alternative.Confidence,
+			Words:         transcriptWords(alternative.Words, base),
+			IsSpeechFinal: response.SpeechFinal,
+			Start:         base + secondsToDuration(response.Start),
+			Duration:      secondsToDuration(response.Duration),
+		}
+		if len(event.Words) > 0 {
+			event.Speaker = event.Words[0].Speaker
+		}
+		if s.channels > 1 && len(response.ChannelIndex) > 0 {
+			event.Channel = response.ChannelIndex[0] + 1
+		}
+		event.Language = detectedLanguage(response, alternative)
 		if response.IsFinal || response.SpeechFinal {
 			event.Kind = domain.TranscriptKindFinal
 		} else {
@@ -258,6 +771,18 @@ func (s *streamingSession) readLoop() {
 		}
 		debuglog.Printf("deepgram transcript kind=%s speech_final=%t text=%q", event.Kind, event.IsSpeechFinal, truncateForLog(transcript, 160))
 		s.emit(event)
+		s.ackFinalize(response)
+	}
+}
+
+// ackFinalize wakes a pending Finalize once its response has been emitted.
+func (s *streamingSession) ackFinalize(response deepgramResponse) {
+	if !response.FromFinalize || s.pendingAcks.Add(-1) > 0 {
+		return
+	}
+	select {
+	case s.finalized <- struct{}{}:
+	default:
 	}
 }
 
@@ -272,36 +797,114 @@ func (s *streamingSession) emit(event domain.TranscriptEvent) {
 type deepgramResponse struct {
 	Type        string `json:"type"`
 	Message     string `json:"message"`
+	ErrCode     string `json:"err_code"`
+	Variant     string `json:"variant"`
 	IsFinal     bool   `json:"is_final"`
 	SpeechFinal bool   `json:"speech_final"`
+	// FromFinalize marks the result that answers a Finalize message.
+	FromFinalize bool    `json:"from_finalize"`
+	Start        float64 `json:"start"`
+	Duration     float64 `json:"duration"`
+	// ChannelIndex is [channel, channels] with multichannel=true.
+	ChannelIndex []int `json:"channel_index"`
 
-	Channel struct {
-		Alternatives []struct {
-			Transcript string `json:"transcript"`
-		} `json:"alternatives"`
-	} `json:"channel"`
+	Channel deepgramChannel `json:"channel"`
 
 	Results struct {
-		Channels []struct {
-			Alternatives []struct {
-				Transcript string `json:"transcript"`
-			} `json:"alternatives"`
-		} `json:"channels"`
+		Channels []deepgramChannel `json:"channels"`
 	} `json:"results"`
 }
 
-func extractTranscript(response deepgramResponse) string {
+type deepgramChannel struct {
+	Alternatives []deepgramAlternative `json:"alternatives"`
+	// DetectedLanguage is sent with detect_language=true.
+	DetectedLanguage string `json:"detected_language"`
+}
+
+type deepgramAlternative struct {
+	Transcript string         `json:"transcript"`
+	Confidence float64        `json:"confidence"`
+	Words      []deepgramWord `json:"words"`
+	// Languages lists the languages of the transcript, most used first, when
+	// the model transcribes several languages.
+	Languages []string `json:"languages"`
+}
+
+type deepgramWord struct {
+	Word           string  `json:"word"`
+	PunctuatedWord string  `json:"punctuated_word"`
+	Confidence     float64 `json:"confidence"`
+	// Start and End are seconds from the start of the stream.
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	// Speaker is the zero-based speaker index, sent with diarize=true.
+	Speaker *int `json:"speaker"`
+}
+
+// transcriptWords prefers punctuated words so spans match the smart-formatted
+// transcript, and shifts their timings by base.
+func transcriptWords(words []deepgramWord, base time.Duration) []domain.TranscriptWord {
+	if len(words) == 0 {
+		return nil
+	}
+	out := make([]domain.TranscriptWord, 0, len(words))
+	for _, word := range words {
+		text := word.PunctuatedWord
+		if text == "" {
+			text = word.Word
+		}
+		converted := domain.TranscriptWord{
+			Text:       text,
+			Confidence: word.Confidence,
+			Start:      base + secondsToDuration(word.Start),
+			End:        base + secondsToDuration(word.End),
+		}
+		if word.Speaker != nil {
+			converted.Speaker = *word.Speaker + 1
+		}
+		out = append(out, converted)
+	}
+	return out
+}
+
+// extractTranscript returns the top alternative with its transcript trimmed.
+func extractTranscript(response deepgramResponse) deepgramAlternative {
 	if len(response.Channel.Alternatives) > 0 {
-		if text := strings.TrimSpace(response.Channel.Alternatives[0].Transcript); text != "" {
-			return text
+		alternative := response.Channel.Alternatives[0]
+		if alternative.Transcript = strings.TrimSpace(alternative.Transcript); alternative.Transcript != "" {
+			return alternative
 		}
 	}
 	if len(response.Results.Channels) > 0 && len(response.Results.Channels[0].Alternatives) > 0 {
-		return strings.TrimSpace(response.Results.Channels[0].Alternatives[0].Transcript)
+		alternative := response.Results.Channels[0].Alternatives[0]
+		alternative.Transcript = strings.TrimSpace(alternative.Transcript)
+		return alternative
+	}
+	return deepgramAlternative{}
+}
+
+// detectedLanguage returns the language Deepgram detected for response, or
+// "" when detection is off.
+func detectedLanguage(response deepgramResponse, alternative deepgramAlternative) string {
+	if response.Channel.DetectedLanguage != "" {
+		return response.Channel.DetectedLanguage
+	}
+	if len(response.Results.Channels) > 0 && response.Results.Channels[0].DetectedLanguage != "" {
+		return response.Results.Channels[0].DetectedLanguage
+	}
+	if len(alternative.Languages) > 0 {
+		return alternative.Languages[0]
 	}
 	return ""
 }
 
+func secondsToDuration(seconds float64) time.Duration {
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
 func truncateForLog(input string, max int) string {
 	if max <= 0 || len(input) <= max {
 		return input
@@ -342,11 +945,58 @@ func buildListenURL(providerCfg Config, streamCfg ports.StreamingConfig) (string
 	query.Set("encoding", streamCfg.Encoding)
 	query.Set("sample_rate", fmt.Sprintf("%d", streamCfg.SampleRate))
 	query.Set("channels", fmt.Sprintf("%d", streamCfg.Channels))
+	if streamCfg.Multichannel && streamCfg.Channels > 1 {
+		query.Set("multichannel", "true")
+	}
 	query.Set("interim_results", fmt.Sprintf("%t", streamCfg.InterimResults))
 	query.Set("smart_format", fmt.Sprintf("%t", providerCfg.SmartFormat))
 	if providerCfg.Language != "" {
 		query.Set("language", providerCfg.Language)
 	}
+	// Nova-3 takes key terms; earlier models boost keywords instead.
+	keywordParam := "keywords"
+	if strings.HasPrefix(providerCfg.Model, "nova-3") {
+		keywordParam = "keyterm"
+	}
+	for _, keyword := range streamCfg.Keywords {
+		query.Add(keywordParam, keyword)
+	}
+	for _, tag := range providerCfg.Tags {
+		query.Add("tag", tag)
+	}
+	if providerCfg.Callback != "" {
+		query.Set("callback", providerCfg.Callback)
+	}
+	if providerCfg.Diarize {
+		query.Set("diarize", "true")
+	}
+	if providerCfg.Endpointing != "" {
+		query.Set("endpointing", providerCfg.Endpointing)
+	}
+	if providerCfg.UtteranceEndMS > 0 {
+		query.Set("utterance_end_ms", fmt.Sprintf("%d", providerCfg.UtteranceEndMS))
+	}
+	if providerCfg.VADEvents {
+		query.Set("vad_events", "true")
+	}
+	if providerCfg.Punctuate {
+		query.Set("punctuate", "true")
+	}
+	if providerCfg.Numerals {
+		query.Set("numerals", "true")
+	}
+	if providerCfg.FillerWords {
+		query.Set("filler_words", "true")
+	}
+	if providerCfg.ProfanityFilter {
+		query.Set("profanity_filter", "true")
+	}
+	for _, entity := range providerCfg.Redact {
+		query.Add("redact", entity)
+	}
+	if providerCfg.DetectLanguage {
+		query.Set("detect_language", "true")
+	}
 	listenURL.RawQuery = query.Encode()
 	return listenURL.String(), nil
 }
@@ -2,14 +2,21 @@ package deepgram
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/gorilla/websocket"
 
+	"coldmic/internal/domain"
 	"coldmic/internal/ports"
 )
 
@@ -35,6 +42,61 @@ func TestProviderStartStreamingRequiresAPIKey(t *testing.T) {
 	}
 }
 
+func TestAuthHeaderStyles(t *testing.T) {
+	t.Parallel()
+
+	for auth, want := range map[string][2]string{
+		"":                 {"Authorization", "Token k"},
+		"token":            {"Authorization", "Token k"},
+		"bearer":           {"Authorization", "Bearer k"},
+		"header:X-Api-Key": {"X-Api-Key", "k"},
+		"none":             {"", ""},
+	} {
+		name, value, err := authHeader(auth, "k")
+		if err != nil || name != want[0] || value != want[1] {
+			t.Fatalf("authHeader(%q) = %q %q %v", auth, name, value, err)
+		}
+	}
+	if _, _, err := authHeader("basic", "k"); err == nil {
+		t.Fatalf("expected unknown auth style to fail")
+	}
+}
+
+func TestProviderUsesProfileAuthAndTLS(t *testing.T) {
+	t.Parallel()
+
+	var key string
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key = r.Header.Get("X-Api-Key")
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		_ = conn.Close()
+	}))
+	defer server.Close()
+
+	provider := NewProvider(Config{APIKey: "onprem", APIBaseURL: server.URL, Auth: "header:X-Api-Key"})
+	if _, err := provider.StartStreaming(context.Background(), ports.StreamingConfig{}); err == nil {
+		t.Fatalf("expected the self-signed certificate to be rejected")
+	}
+
+	provider = NewProvider(Config{APIKey: "onprem", APIBaseURL: server.URL, Auth: "header:X-Api-Key", TLS: TLSConfig{InsecureSkipVerify: true}})
+	session, err := provider.StartStreaming(context.Background(), ports.StreamingConfig{})
+	if err != nil {
+		t.Fatalf("start streaming failed: %v", err)
+	}
+	_ = session.Close()
+	if key != "onprem" {
+		t.Fatalf("expected key in custom header, got %q", key)
+	}
+
+	if _, err := NewProvider(Config{APIBaseURL: server.URL, TLS: TLSConfig{CAFile: "/missing/ca.pem"}, Auth: "none"}).StartStreaming(context.Background(), ports.StreamingConfig{}); err == nil {
+		t.Fatalf("expected missing CA file to fail")
+	}
+}
+
 func TestBuildListenURLDefaults(t *testing.T) {
 	t.Parallel()
 
@@ -79,6 +141,148 @@ func TestBuildListenURLWithLanguageAndSmartFormat(t *testing.T) {
 	}
 }
 
+func TestBuildListenURLWithKeywords(t *testing.T) {
+	t.Parallel()
+
+	keywords := ports.StreamingConfig{Keywords: []string{"coldmic", "Hypr land"}}
+	url, err := buildListenURL(Config{APIBaseURL: "https://api.deepgram.com/v1", Model: "nova-2"}, keywords)
+	if err != nil || !strings.Contains(url, "keywords=coldmic&keywords=Hypr+land") {
+		t.Fatalf("expected keywords in url %s: %v", url, err)
+	}
+	url, err = buildListenURL(Config{APIBaseURL: "https://api.deepgram.com/v1", Model: "nova-3"}, keywords)
+	if err != nil || !strings.Contains(url, "keyterm=coldmic&keyterm=Hypr+land") || strings.Contains(url, "keywords=") {
+		t.Fatalf("expected key terms for nova-3 in url %s: %v", url, err)
+	}
+}
+
+func TestBuildListenURLWithTagsAndCallback(t *testing.T) {
+	t.Parallel()
+
+	url, err := buildListenURL(Config{
+		APIBaseURL: "https://api.deepgram.com/v1",
+		Model:      "nova-2",
+		Tags:       []string{"user:ada", "host:desk"},
+		Callback:   "https://hooks.example/deepgram",
+	}, ports.StreamingConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(url, "tag=user%3Aada&tag=host%3Adesk") || !strings.Contains(url, "callback=https%3A%2F%2Fhooks.example%2Fdeepgram") {
+		t.Fatalf("expected tags and callback in url: %s", url)
+	}
+}
+
+func TestBuildListenURLWithDiarize(t *testing.T) {
+	t.Parallel()
+
+	url, err := buildListenURL(Config{APIBaseURL: "https://api.deepgram.com/v1", Model: "nova-2", Diarize: true}, ports.StreamingConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(url, "diarize=true") {
+		t.Fatalf("expected diarize in url: %s", url)
+	}
+}
+
+func TestBuildListenURLWithUtteranceTuning(t *testing.T) {
+	t.Parallel()
+
+	url, err := buildListenURL(Config{
+		APIBaseURL:     "https://api.deepgram.com/v1",
+		Model:          "nova-2",
+		Endpointing:    "500",
+		UtteranceEndMS: 1500,
+		VADEvents:      true,
+	}, ports.StreamingConfig{InterimResults: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, param := range []string{"endpointing=500", "utterance_end_ms=1500", "vad_events=true"} {
+		if !strings.Contains(url, param) {
+			t.Fatalf("expected %s in url: %s", param, url)
+		}
+	}
+	url, _ = buildListenURL(Config{APIBaseURL: "https://api.deepgram.com/v1", Model: "nova-2"}, ports.StreamingConfig{})
+	if strings.Contains(url, "endpointing") || strings.Contains(url, "utterance_end_ms") || strings.Contains(url, "vad_events") {
+		t.Fatalf("expected Deepgram defaults without tuning: %s", url)
+	}
+}
+
+func TestBuildListenURLWithFormattingToggles(t *testing.T) {
+	t.Parallel()
+
+	url, err := buildListenURL(Config{
+		APIBaseURL:  "https://api.deepgram.com/v1",
+		Model:       "nova-2",
+		Punctuate:   true,
+		Numerals:    true,
+		FillerWords: true,
+	}, ports.StreamingConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, param := range []string{"punctuate=true", "numerals=true", "filler_words=true"} {
+		if !strings.Contains(url, param) {
+			t.Fatalf("expected %s in url: %s", param, url)
+		}
+	}
+	url, _ = buildListenURL(Config{APIBaseURL: "https://api.deepgram.com/v1", Model: "nova-2"}, ports.StreamingConfig{})
+	if strings.Contains(url, "punctuate") || strings.Contains(url, "numerals") || strings.Contains(url, "filler_words") {
+		t.Fatalf("expected Deepgram defaults without toggles: %s", url)
+	}
+}
+
+func TestBuildListenURLWithRedaction(t *testing.T) {
+	t.Parallel()
+
+	url, err := buildListenURL(Config{
+		APIBaseURL:      "https://api.deepgram.com/v1",
+		Model:           "nova-2",
+		ProfanityFilter: true,
+		Redact:          []string{"pci", "ssn"},
+	}, ports.StreamingConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, param := range []string{"profanity_filter=true", "redact=pci", "redact=ssn"} {
+		if !strings.Contains(url, param) {
+			t.Fatalf("expected %s in url: %s", param, url)
+		}
+	}
+	url, _ = buildListenURL(Config{APIBaseURL: "https://api.deepgram.com/v1", Model: "nova-2"}, ports.StreamingConfig{})
+	if strings.Contains(url, "profanity_filter") || strings.Contains(url, "redact") {
+		t.Fatalf("expected no redaction by default: %s", url)
+	}
+}
+
+func TestBuildListenURLWithLanguageDetection(t *testing.T) {
+	t.Parallel()
+
+	url, err := buildListenURL(Config{APIBaseURL: "https://api.deepgram.com/v1", Model: "nova-2", DetectLanguage: true}, ports.StreamingConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(url, "detect_language=true") {
+		t.Fatalf("expected detect_language in url: %s", url)
+	}
+}
+
+func TestBuildListenURLWithMultichannel(t *testing.T) {
+	t.Parallel()
+
+	url, err := buildListenURL(Config{APIBaseURL: "https://api.deepgram.com/v1", Model: "nova-2"}, ports.StreamingConfig{Channels: 2, Multichannel: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(url, "channels=2") || !strings.Contains(url, "multichannel=true") {
+		t.Fatalf("expected multichannel in url: %s", url)
+	}
+	url, _ = buildListenURL(Config{APIBaseURL: "https://api.deepgram.com/v1", Model: "nova-2"}, ports.StreamingConfig{Channels: 1, Multichannel: true})
+	if strings.Contains(url, "multichannel") {
+		t.Fatalf("expected no multichannel for mono audio: %s", url)
+	}
+}
+
 func TestBuildListenURLInvalidBase(t *testing.T) {
 	t.Parallel()
 
@@ -92,29 +296,40 @@ func TestExtractTranscript(t *testing.T) {
 	t.Parallel()
 
 	r1 := deepgramResponse{}
-	r1.Channel.Alternatives = append(r1.Channel.Alternatives, struct {
-		Transcript string "json:\"transcript\""
-	}{Transcript: " channel "})
-	if got := extractTranscript(r1); got != "channel" {
-		t.Fatalf("unexpected transcript from channel: %q", got)
+	r1.Channel.Alternatives = append(r1.Channel.Alternatives, deepgramAlternative{Transcript: " channel ", Confidence: 0.91})
+	if got := extractTranscript(r1); got.Transcript != "channel" || got.Confidence != 0.91 {
+		t.Fatalf("unexpected alternative from channel: %+v", got)
 	}
 
 	r2 := deepgramResponse{}
-	r2.Results.Channels = append(r2.Results.Channels, struct {
-		Alternatives []struct {
-			Transcript string "json:\"transcript\""
-		} "json:\"alternatives\""
-	}{
-		Alternatives: []struct {
-			Transcript string "json:\"transcript\""
-		}{{Transcript: "results"}},
+	r2.Results.Channels = append(r2.Results.Channels, deepgramChannel{
+		Alternatives: []deepgramAlternative{{Transcript: "results"}},
 	})
-	if got := extractTranscript(r2); got != "results" {
-		t.Fatalf("unexpected transcript from results: %q", got)
+	if got := extractTranscript(r2); got.Transcript != "results" {
+		t.Fatalf("unexpected transcript from results: %q", got.Transcript)
 	}
 
-	if got := extractTranscript(deepgramResponse{}); got != "" {
-		t.Fatalf("expected empty transcript, got %q", got)
+	if got := extractTranscript(deepgramResponse{}); got.Transcript != "" {
+		t.Fatalf("expected empty transcript, got %q", got.Transcript)
+	}
+}
+
+func TestTranscriptWordsPreferPunctuated(t *testing.T) {
+	t.Parallel()
+
+	var response deepgramResponse
+	payload := `{"channel":{"alternatives":[{"transcript":"hello there","confidence":0.8,"words":[{"word":"hello","punctuated_word":"Hello","confidence":0.95,"start":1.5,"end":1.75,"speaker":0},{"word":"there","confidence":0.6,"speaker":1}]}]}}`
+	if err := json.Unmarshal([]byte(payload), &response); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	words := transcriptWords(extractTranscript(response).Words, 0)
+	if len(words) != 2 || words[0].Text != "Hello" || words[0].Confidence != 0.95 || words[1].Text != "there" ||
+		words[0].Start != 1500*time.Millisecond || words[0].End != 1750*time.Millisecond ||
+		words[0].Speaker != 1 || words[1].Speaker != 2 {
+		t.Fatalf("unexpected words: %+v", words)
+	}
+	if transcriptWords(nil, 0) != nil {
+		t.Fatalf("expected nil words without provider word data")
 	}
 }
 
@@ -194,3 +409,359 @@ func TestStreamingSessionSetErrFirstWins(t *testing.T) {
 		t.Fatalf("expected first error to win")
 	}
 }
+
+func TestStreamingSessionParsesUtteranceTiming(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, func(conn *websocket.Conn) {
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"Results","is_final":true,"start":1.5,"duration":0.25,"channel":{"alternatives":[{"transcript":"hello"}]}}`))
+		_, _, _ = conn.ReadMessage()
+	})
+
+	session := startTestSession(t, server)
+	event := <-session.Events()
+	if event.Text != "hello" || event.Kind != domain.TranscriptKindFinal {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+	if event.Start != 1500*time.Millisecond || event.Duration != 250*time.Millisecond {
+		t.Fatalf("unexpected timing: start=%s duration=%s", event.Start, event.Duration)
+	}
+	_ = session.Close()
+}
+
+func TestStreamingSessionParsesDetectedLanguage(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, func(conn *websocket.Conn) {
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"Results","is_final":true,"channel":{"detected_language":"de","alternatives":[{"transcript":"hallo"}]}}`))
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"Results","is_final":true,"channel":{"alternatives":[{"transcript":"hola amigo","languages":["es","en"]}]}}`))
+		_, _, _ = conn.ReadMessage()
+	})
+
+	session := startTestSession(t, server)
+	if event := <-session.Events(); event.Language != "de" {
+		t.Fatalf("expected detected language, got %+v", event)
+	}
+	if event := <-session.Events(); event.Language != "es" {
+		t.Fatalf("expected the main language of the words, got %+v", event)
+	}
+	_ = session.Close()
+}
+
+func TestStreamingSessionMarksUtteranceEnd(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, func(conn *websocket.Conn) {
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"SpeechStarted","timestamp":0.5}`))
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"UtteranceEnd","last_word_end":2.1}`))
+		_, _, _ = conn.ReadMessage()
+	})
+
+	session := startTestSession(t, server)
+	event := <-session.Events()
+	if event.Kind != domain.TranscriptKindFinal || !event.IsSpeechFinal || event.Text != "" {
+		t.Fatalf("expected an empty speech-final marker, got %+v", event)
+	}
+	_ = session.Close()
+}
+
+func TestStreamingSessionSendsKeepAliveWhileIdle(t *testing.T) {
+	t.Parallel()
+
+	messages := make(chan string, 8)
+	server := newTestServer(t, func(conn *websocket.Conn) {
+		for {
+			kind, data, err := conn.ReadMessage()
+			if err != nil {
+				close(messages)
+				return
+			}
+			if kind == websocket.BinaryMessage {
+				messages <- "audio"
+				continue
+			}
+			messages <- string(data)
+		}
+	})
+
+	provider := NewProvider(Config{APIKey: "test-key", APIBaseURL: server.URL})
+	provider.keepAlive = 20 * time.Millisecond
+	session, err := provider.StartStreaming(context.Background(), ports.StreamingConfig{})
+	if err != nil {
+		t.Fatalf("start streaming failed: %v", err)
+	}
+	if err := session.SendAudio([]byte{1, 2}); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	for _, want := range []string{"audio", `{"type":"KeepAlive"}`} {
+		select {
+		case got := <-messages:
+			if got != want {
+				t.Fatalf("expected %s, got %s", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s", want)
+		}
+	}
+	_ = session.CloseSend()
+	for got := range messages {
+		if got == `{"type":"CloseStream"}` {
+			_ = session.Close()
+			return
+		}
+	}
+	t.Fatalf("expected CloseStream after the keepalives")
+}
+
+func TestStreamingSessionFinalizeFlushesQueuedAudio(t *testing.T) {
+	t.Parallel()
+
+	messages := make(chan string, 8)
+	server := newTestServer(t, func(conn *websocket.Conn) {
+		for {
+			kind, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if kind == websocket.BinaryMessage {
+				messages <- "audio"
+				continue
+			}
+			messages <- string(data)
+			if string(data) == `{"type":"Finalize"}` {
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"Results","is_final":true,"from_finalize":true,"channel":{"alternatives":[{"transcript":"tail"}]}}`))
+			}
+		}
+	})
+
+	session := startTestSession(t, server)
+	defer session.Close()
+	for range 3 {
+		if err := session.SendAudio([]byte{1, 2}); err != nil {
+			t.Fatalf("send failed: %v", err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := session.(ports.StreamFinalizer).Finalize(ctx); err != nil {
+		t.Fatalf("finalize failed: %v", err)
+	}
+	for _, want := range []string{"audio", "audio", "audio", `{"type":"Finalize"}`} {
+		if got := <-messages; got != want {
+			t.Fatalf("expected %s, got %s", want, got)
+		}
+	}
+	if event := <-session.Events(); event.Text != "tail" || event.Kind != domain.TranscriptKindFinal {
+		t.Fatalf("expected finalized tail before the acknowledgment, got %+v", event)
+	}
+
+	_ = session.CloseSend()
+	if err := session.(ports.StreamFinalizer).Finalize(ctx); err == nil {
+		t.Fatalf("expected finalize after CloseSend to fail")
+	}
+}
+
+func TestStreamingSessionMultichannelFinalizeWaitsForEveryChannel(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, func(conn *websocket.Conn) {
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if string(data) == `{"type":"Finalize"}` {
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"Results","is_final":true,"from_finalize":true,"channel_index":[1,2],"channel":{"alternatives":[{"transcript":"them"}]}}`))
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"Results","is_final":true,"from_finalize":true,"channel_index":[0,2],"channel":{"alternatives":[{"transcript":"me"}]}}`))
+			}
+		}
+	})
+
+	provider := NewProvider(Config{APIKey: "test-key", APIBaseURL: server.URL})
+	session, err := provider.StartStreaming(context.Background(), ports.StreamingConfig{Channels: 2, Multichannel: true})
+	if err != nil {
+		t.Fatalf("start streaming failed: %v", err)
+	}
+	defer session.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := session.(ports.StreamFinalizer).Finalize(ctx); err != nil {
+		t.Fatalf("finalize failed: %v", err)
+	}
+	for _, want := range []domain.TranscriptEvent{{Text: "them", Channel: 2}, {Text: "me", Channel: 1}} {
+		select {
+		case event := <-session.Events():
+			if event.Text != want.Text || event.Channel != want.Channel {
+				t.Fatalf("expected %+v, got %+v", want, event)
+			}
+		default:
+			t.Fatalf("expected both channels to be final before finalize returned")
+		}
+	}
+}
+
+func TestStreamingSessionReconnectsAndResendsBufferedAudio(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	connections := 0
+	var resumed []string
+	server := newTestServer(t, func(conn *websocket.Conn) {
+		mu.Lock()
+		connections++
+		first := connections == 1
+		mu.Unlock()
+		if first {
+			// Drop the connection without a close frame after the first chunk.
+			_, _, _ = conn.ReadMessage()
+			_ = conn.UnderlyingConn().Close()
+			return
+		}
+		for {
+			kind, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			if kind == websocket.BinaryMessage {
+				resumed = append(resumed, string(data))
+			}
+			mu.Unlock()
+			if string(data) == `{"type":"CloseStream"}` {
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"is_final":true,"start":0.5,"duration":1,"channel":{"alternatives":[{"transcript":"after the drop"}]}}`))
+				_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				return
+			}
+		}
+	})
+
+	provider := NewProvider(Config{APIKey: "test-key", APIBaseURL: server.URL})
+	provider.reconnectBackoff = time.Millisecond
+	session, err := provider.StartStreaming(context.Background(), ports.StreamingConfig{SampleRate: 16000, Channels: 1})
+	if err != nil {
+		t.Fatalf("start streaming failed: %v", err)
+	}
+	drops := make(chan error, 1)
+	session.(ports.ReconnectNotifier).OnReconnect(func(err error) { drops <- err })
+
+	// One second of audio goes to the first connection before it drops.
+	if err := session.SendAudio(make([]byte, 32000)); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	select {
+	case <-drops:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected the drop to be reported")
+	}
+	for _, chunk := range []string{"b", "c"} {
+		if err := session.SendAudio([]byte(chunk)); err != nil {
+			t.Fatalf("send after drop failed: %v", err)
+		}
+	}
+	_ = session.CloseSend()
+
+	var events []domain.TranscriptEvent
+	for event := range session.Events() {
+		events = append(events, event)
+	}
+	if err := session.Wait(); err != nil {
+		t.Fatalf("expected the reconnected session to end cleanly, got %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if connections != 2 || strings.Join(resumed, "") != "bc" {
+		t.Fatalf("expected buffered audio on a second connection, got connections=%d audio=%q", connections, resumed)
+	}
+	if len(events) != 1 || events[0].Text != "after the drop" || events[0].Start != 1500*time.Millisecond {
+		t.Fatalf("expected the result shifted past the first connection's audio, got %+v", events)
+	}
+}
+
+func TestStartStreamingRetriesTransientDialFailures(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch attempts.Add(1) {
+		case 1, 2:
+			// Reset the connection before the handshake completes.
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				_ = conn.Close()
+			}
+		default:
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			_, _, _ = conn.ReadMessage()
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	provider := NewProvider(Config{APIKey: "test-key", APIBaseURL: server.URL, DialRetries: 1})
+	provider.dialBackoff = time.Millisecond
+	if _, err := provider.StartStreaming(context.Background(), ports.StreamingConfig{}); err == nil {
+		t.Fatalf("expected a failure once the retries ran out")
+	}
+
+	attempts.Store(0)
+	provider.cfg.DialRetries = 3
+	session, err := provider.StartStreaming(context.Background(), ports.StreamingConfig{})
+	if err != nil {
+		t.Fatalf("expected the dial to be retried, got %v", err)
+	}
+	_ = session.Close()
+	if got := attempts.Load(); got != 3 {
+		t.Fatalf("expected 3 dial attempts, got %d", got)
+	}
+}
+
+func TestStartStreamingDoesNotRetryRejectedHandshake(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+
+	provider := NewProvider(Config{APIKey: "test-key", APIBaseURL: server.URL, DialRetries: 3})
+	provider.dialBackoff = time.Millisecond
+	if _, err := provider.StartStreaming(context.Background(), ports.StreamingConfig{}); err == nil {
+		t.Fatalf("expected the rejected handshake to fail")
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Fatalf("expected one dial attempt, got %d", got)
+	}
+}
+
+func newTestServer(t *testing.T, handler func(conn *websocket.Conn)) *httptest.Server {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		handler(conn)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func startTestSession(t *testing.T, server *httptest.Server) ports.StreamingSession {
+	t.Helper()
+
+	provider := NewProvider(Config{APIKey: "test-key", APIBaseURL: server.URL})
+	session, err := provider.StartStreaming(context.Background(), ports.StreamingConfig{})
+	if err != nil {
+		t.Fatalf("start streaming failed: %v", err)
+	}
+	return session
+}
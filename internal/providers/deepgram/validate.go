@@ -0,0 +1,58 @@
+package deepgram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"coldmic/internal/netproxy"
+)
+
+// Validate checks the API key by listing the key's projects, which costs
+// nothing. Endpoints without the projects API, such as self-hosted
+// deployments, and keyless auth pass without a check.
+func (p *Provider) Validate(ctx context.Context) error {
+	if p.cfg.Auth == "none" {
+		return nil
+	}
+	if strings.TrimSpace(p.cfg.APIKey) == "" {
+		return errors.New("DEEPGRAM_API_KEY is not configured")
+	}
+	name, value, err := authHeader(p.cfg.Auth, p.cfg.APIKey)
+	if err != nil {
+		return err
+	}
+	tlsConfig, err := p.cfg.TLS.build()
+	if err != nil {
+		return err
+	}
+	transport := netproxy.Transport(p.cfg.Proxy)
+	transport.TLSClientConfig = tlsConfig
+	client := &http.Client{Transport: transport}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(p.cfg.APIBaseURL, "/")+"/projects", nil)
+	if err != nil {
+		return err
+	}
+	if name != "" {
+		req.Header.Set(name, value)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach Deepgram: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusPaymentRequired, http.StatusForbidden:
+		return handshakeError(resp, fmt.Errorf("deepgram rejected the API key: %s", resp.Status))
+	case http.StatusNotFound, http.StatusMethodNotAllowed:
+		return nil
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("deepgram key check failed: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package deepgram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"coldmic/internal/domain"
+)
+
+func TestValidateClassifiesRejectedKey(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/projects" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") == "Token good" {
+			_, _ = w.Write([]byte(`{"projects":[]}`))
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"err_code":"INVALID_AUTH","err_msg":"Invalid credentials."}`))
+	}))
+	t.Cleanup(server.Close)
+
+	if err := NewProvider(Config{APIKey: "good", APIBaseURL: server.URL + "/v1"}).Validate(context.Background()); err != nil {
+		t.Fatalf("expected a valid key to pass, got %v", err)
+	}
+	err := NewProvider(Config{APIKey: "bad", APIBaseURL: server.URL + "/v1"}).Validate(context.Background())
+	var providerErr *domain.ProviderError
+	if !errors.As(err, &providerErr) || providerErr.Code != domain.ErrorCodeInvalidKey || providerErr.Message != "Invalid credentials." {
+		t.Fatalf("expected invalid key error, got %v", err)
+	}
+}
+
+func TestValidatePassesEndpointsWithoutProjects(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+
+	if err := NewProvider(Config{APIKey: "key", APIBaseURL: server.URL}).Validate(context.Background()); err != nil {
+		t.Fatalf("expected a self-hosted endpoint to pass, got %v", err)
+	}
+	if err := NewProvider(Config{Auth: "none", APIBaseURL: "http://127.0.0.1:1"}).Validate(context.Background()); err != nil {
+		t.Fatalf("expected keyless auth to skip the check, got %v", err)
+	}
+}
@@ -0,0 +1,456 @@
+// Package gladia streams live transcription to Gladia's real-time API.
+package gladia
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/netproxy"
+	"coldmic/internal/ports"
+)
+
+// Config controls Gladia live session settings.
+type Config struct {
+	APIKey     string
+	APIBaseURL string
+	// Language is an ISO-639-1 code; empty lets Gladia detect it.
+	Language string
+	// Proxy is an explicit proxy URL; empty uses the proxy environment.
+	Proxy string
+}
+
+// Provider implements ports.TranscriptionProvider for Gladia live
+// transcription. Each stream first creates a session over HTTPS and then
+// sends audio to the websocket URL Gladia returns.
+type Provider struct {
+	cfg    Config
+	client *http.Client
+}
+
+func NewProvider(cfg Config) *Provider {
+	if cfg.APIBaseURL == "" {
+		cfg.APIBaseURL = "https://api.gladia.io"
+	}
+	return &Provider{cfg: cfg, client: &http.Client{Timeout: 15 * time.Second, Transport: netproxy.Transport(cfg.Proxy)}}
+}
+
+// Capabilities describes Gladia live transcription.
+func (p *Provider) Capabilities() domain.Capabilities {
+	return domain.Capabilities{
+		Streaming:    true,
+		LivePartials: true,
+		Timestamps:   true,
+		Confidence:   true,
+	}
+}
+
+// ProviderNames names the service usage is priced under.
+func (p *Provider) ProviderNames() []string {
+	return []string{"gladia"}
+}
+
+func (p *Provider) StartStreaming(ctx context.Context, cfg ports.StreamingConfig) (ports.StreamingSession, error) {
+	if strings.TrimSpace(p.cfg.APIKey) == "" {
+		return nil, errors.New("GLADIA_API_KEY is not configured")
+	}
+	if cfg.SampleRate <= 0 {
+		cfg.SampleRate = 16000
+	}
+	if cfg.Channels <= 0 {
+		cfg.Channels = 1
+	}
+
+	wsURL, err := p.initSession(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, _, err := netproxy.Dialer(p.cfg.Proxy).DialContext(ctx, wsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Gladia websocket: %w", err)
+	}
+	debuglog.Printf("gladia connected")
+
+	session := &streamingSession{
+		conn:     conn,
+		interim:  cfg.InterimResults,
+		events:   make(chan domain.TranscriptEvent, 64),
+		audio:    make(chan []byte, 32),
+		readDone: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	session.wg.Add(2)
+	go session.readLoop()
+	go session.writeLoop()
+	go func() {
+		session.wg.Wait()
+		close(session.events)
+		close(session.done)
+		_ = conn.Close()
+	}()
+
+	go func() {
+		<-ctx.Done()
+		_ = session.Close()
+	}()
+
+	return session, nil
+}
+
+// initSession creates a live session and returns its websocket URL, which
+// carries a short-lived token in place of the API key.
+func (p *Provider) initSession(ctx context.Context, cfg ports.StreamingConfig) (string, error) {
+	initURL, err := buildInitURL(p.cfg)
+	if err != nil {
+		return "", err
+	}
+	body, err := json.Marshal(newInitRequest(p.cfg, cfg))
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, initURL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gladia-Key", p.cfg.APIKey)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to create Gladia session: %w", err)
+	}
+	defer resp.Body.Close()
+
+	payload, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var failure struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(payload, &failure) == nil && failure.Message != "" {
+			return "", fmt.Errorf("failed to create Gladia session: %s (status %d)", failure.Message, resp.StatusCode)
+		}
+		return "", fmt.Errorf("failed to create Gladia session: status %d", resp.StatusCode)
+	}
+
+	var created struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal(payload, &created); err != nil || created.URL == "" {
+		return "", errors.New("failed to create Gladia session: response has no websocket url")
+	}
+	debuglog.Printf("gladia session created id=%s", created.ID)
+	return created.URL, nil
+}
+
+type initRequest struct {
+	Encoding       string          `json:"encoding"`
+	BitDepth       int             `json:"bit_depth"`
+	SampleRate     int             `json:"sample_rate"`
+	Channels       int             `json:"channels"`
+	LanguageConfig *languageConfig `json:"language_config,omitempty"`
+	MessagesConfig messagesConfig  `json:"messages_config"`
+}
+
+type languageConfig struct {
+	Languages []string `json:"languages"`
+}
+
+type messagesConfig struct {
+	ReceivePartialTranscripts bool `json:"receive_partial_transcripts"`
+	ReceiveFinalTranscripts   bool `json:"receive_final_transcripts"`
+}
+
+func newInitRequest(cfg Config, stream ports.StreamingConfig) initRequest {
+	request := initRequest{
+		Encoding:   "wav/pcm",
+		BitDepth:   16,
+		SampleRate: stream.SampleRate,
+		Channels:   stream.Channels,
+		MessagesConfig: messagesConfig{
+			ReceivePartialTranscripts: stream.InterimResults,
+			ReceiveFinalTranscripts:   true,
+		},
+	}
+	if language := strings.TrimSpace(cfg.Language); language != "" {
+		request.LanguageConfig = &languageConfig{Languages: []string{language}}
+	}
+	return request
+}
+
+type streamingSession struct {
+	conn    *websocket.Conn
+	interim bool
+
+	events chan domain.TranscriptEvent
+	audio  chan []byte
+	// readDone stops the writer once Gladia closes the session.
+	readDone chan struct{}
+	done     chan struct{}
+
+	wg sync.WaitGroup
+
+	errMu sync.Mutex
+	err   error
+
+	closeSendOnce sync.Once
+	closeOnce     sync.Once
+	sendMu        sync.RWMutex
+	sendClosed    bool
+}
+
+func (s *streamingSession) SendAudio(chunk []byte) error {
+	if len(chunk) == 0 {
+		return nil
+	}
+
+	s.sendMu.RLock()
+	closed := s.sendClosed
+	s.sendMu.RUnlock()
+	if closed {
+		return errors.New("audio stream is already closed")
+	}
+
+	copied := append([]byte(nil), chunk...)
+	select {
+	case s.audio <- copied:
+		return nil
+	case <-s.done:
+		if err := s.waitErr(); err != nil {
+			return err
+		}
+		return errors.New("session closed")
+	}
+}
+
+func (s *streamingSession) CloseSend() error {
+	s.closeSendOnce.Do(func() {
+		s.sendMu.Lock()
+		s.sendClosed = true
+		close(s.audio)
+		s.sendMu.Unlock()
+	})
+	return nil
+}
+
+func (s *streamingSession) Events() <-chan domain.TranscriptEvent {
+	return s.events
+}
+
+func (s *streamingSession) Wait() error {
+	<-s.done
+	return s.waitErr()
+}
+
+func (s *streamingSession) Close() error {
+	s.closeOnce.Do(func() {
+		_ = s.CloseSend()
+		_ = s.conn.Close()
+	})
+	<-s.done
+	return s.waitErr()
+}
+
+func (s *streamingSession) waitErr() error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	return s.err
+}
+
+func (s *streamingSession) setErr(err error) {
+	if err == nil || isExpectedShutdownErr(err) {
+		return
+	}
+
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+func isExpectedShutdownErr(err error) bool {
+	if errors.Is(err, net.ErrClosed) || errors.Is(err, websocket.ErrCloseSent) {
+		return true
+	}
+
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) {
+		return false
+	}
+
+	switch closeErr.Code {
+	case websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseNoStatusReceived:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *streamingSession) writeLoop() {
+	defer s.wg.Done()
+
+	for {
+		var chunk []byte
+		var ok bool
+		select {
+		case chunk, ok = <-s.audio:
+		case <-s.readDone:
+			return
+		}
+		if !ok {
+			break
+		}
+		if err := s.conn.WriteMessage(websocket.BinaryMessage, chunk); err != nil {
+			debuglog.Printf("gladia audio send failed: %v", err)
+			s.setErr(fmt.Errorf("failed to send audio: %w", err))
+			return
+		}
+	}
+
+	// Gladia sends the remaining transcripts and then closes the websocket.
+	if err := s.conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"stop_recording"}`)); err != nil {
+		debuglog.Printf("gladia stop_recording failed: %v", err)
+		s.setErr(fmt.Errorf("failed to close stream: %w", err))
+		return
+	}
+	debuglog.Printf("gladia sent stop_recording")
+}
+
+func (s *streamingSession) readLoop() {
+	defer s.wg.Done()
+	defer close(s.readDone)
+
+	for {
+		_, payload, err := s.conn.ReadMessage()
+		if err != nil {
+			debuglog.Printf("gladia read failed: %v", err)
+			s.setErr(fmt.Errorf("failed to read provider event: %w", closeReason(err)))
+			return
+		}
+
+		var message streamMessage
+		if err := json.Unmarshal(payload, &message); err != nil {
+			debuglog.Printf("gladia ignored non-json payload bytes=%d", len(payload))
+			continue
+		}
+
+		switch message.Type {
+		case "error":
+			debuglog.Printf("gladia error message=%q", message.Error.Message)
+			s.setErr(fmt.Errorf("gladia error: %s", strings.TrimSpace(message.Error.Message)))
+			return
+		case "end_session":
+			debuglog.Printf("gladia session ended")
+			return
+		case "transcript":
+			event, ok := s.transcriptEvent(message)
+			if !ok {
+				continue
+			}
+			debuglog.Printf("gladia transcript kind=%s text=%q", event.Kind, truncateForLog(event.Text, 160))
+			s.emit(event)
+		}
+	}
+}
+
+// transcriptEvent maps a Gladia utterance onto a transcript event. Partial
+// utterances are dropped unless interim results were requested.
+func (s *streamingSession) transcriptEvent(message streamMessage) (domain.TranscriptEvent, bool) {
+	utterance := message.Data.Utterance
+	text := strings.TrimSpace(utterance.Text)
+	if text == "" {
+		return domain.TranscriptEvent{}, false
+	}
+
+	event := domain.TranscriptEvent{
+		Kind:       domain.TranscriptKindPartial,
+		Text:       text,
+		Confidence: utterance.Confidence,
+		Start:      secondsToDuration(utterance.Start),
+		Duration:   secondsToDuration(utterance.End - utterance.Start),
+	}
+	if message.Data.IsFinal {
+		event.Kind = domain.TranscriptKindFinal
+		event.IsSpeechFinal = true
+	} else if !s.interim {
+		return domain.TranscriptEvent{}, false
+	}
+	return event, true
+}
+
+func (s *streamingSession) emit(event domain.TranscriptEvent) {
+	select {
+	case s.events <- event:
+	case <-s.done:
+	default:
+	}
+}
+
+// closeReason surfaces the reason Gladia gives when it closes a stream, such
+// as an expired session token.
+func closeReason(err error) error {
+	var closeErr *websocket.CloseError
+	if errors.As(err, &closeErr) && !isExpectedShutdownErr(err) && closeErr.Text != "" {
+		return fmt.Errorf("%s (code %d): %w", closeErr.Text, closeErr.Code, err)
+	}
+	return err
+}
+
+type streamMessage struct {
+	Type string `json:"type"`
+	Data struct {
+		IsFinal   bool `json:"is_final"`
+		Utterance struct {
+			Text       string  `json:"text"`
+			Start      float64 `json:"start"`
+			End        float64 `json:"end"`
+			Confidence float64 `json:"confidence"`
+		} `json:"utterance"`
+	} `json:"data"`
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func truncateForLog(input string, max int) string {
+	if max <= 0 || len(input) <= max {
+		return input
+	}
+	return input[:max] + "..."
+}
+
+func buildInitURL(cfg Config) (string, error) {
+	base := strings.TrimRight(strings.TrimSpace(cfg.APIBaseURL), "/")
+	initURL, err := url.Parse(base + "/v2/live")
+	if err != nil {
+		return "", fmt.Errorf("invalid Gladia API base: %w", err)
+	}
+	if initURL.Scheme != "https" && initURL.Scheme != "http" {
+		return "", fmt.Errorf("invalid Gladia API base scheme %q", initURL.Scheme)
+	}
+	return initURL.String(), nil
+}
@@ -0,0 +1,145 @@
+package gladia
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestProviderRequiresAPIKey(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewProvider(Config{}).StartStreaming(context.Background(), ports.StreamingConfig{}); err == nil {
+		t.Fatalf("expected missing key error")
+	}
+}
+
+func TestBuildInitURL(t *testing.T) {
+	t.Parallel()
+
+	url, err := buildInitURL(NewProvider(Config{}).cfg)
+	if err != nil || url != "https://api.gladia.io/v2/live" {
+		t.Fatalf("unexpected url %s: %v", url, err)
+	}
+	url, err = buildInitURL(Config{APIBaseURL: "http://localhost:8080/"})
+	if err != nil || url != "http://localhost:8080/v2/live" {
+		t.Fatalf("unexpected override url %s: %v", url, err)
+	}
+	if _, err := buildInitURL(Config{APIBaseURL: "wss://api.gladia.io"}); err == nil {
+		t.Fatalf("expected websocket base to be rejected")
+	}
+}
+
+func TestNewInitRequest(t *testing.T) {
+	t.Parallel()
+
+	request := newInitRequest(Config{}, ports.StreamingConfig{SampleRate: 16000, Channels: 1, InterimResults: true})
+	if request.Encoding != "wav/pcm" || request.BitDepth != 16 || request.SampleRate != 16000 || request.Channels != 1 {
+		t.Fatalf("unexpected audio settings: %+v", request)
+	}
+	if request.LanguageConfig != nil || !request.MessagesConfig.ReceivePartialTranscripts {
+		t.Fatalf("expected detected language with partials: %+v", request)
+	}
+	if request := newInitRequest(Config{Language: "de"}, ports.StreamingConfig{}); request.LanguageConfig == nil || request.LanguageConfig.Languages[0] != "de" {
+		t.Fatalf("expected language hint: %+v", request)
+	}
+}
+
+func TestStreamingSessionMapsTranscripts(t *testing.T) {
+	t.Parallel()
+
+	var key string
+	var init initRequest
+	var messages []string
+	upgrader := websocket.Upgrader{}
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	mux.HandleFunc("/v2/live", func(w http.ResponseWriter, r *http.Request) {
+		key = r.Header.Get("X-Gladia-Key")
+		_ = json.NewDecoder(r.Body).Decode(&init)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"abc","url":"ws` + strings.TrimPrefix(server.URL, "http") + `/ws?token=t"}`))
+	})
+	mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			kind, payload, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if kind == websocket.BinaryMessage {
+				messages = append(messages, "audio")
+				continue
+			}
+			messages = append(messages, string(payload))
+			break
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"transcript","data":{"is_final":false,"utterance":{"text":" hello wor","start":0.5,"end":0.8}}}`))
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"transcript","data":{"is_final":true,"utterance":{"text":" Hello world.","start":0.5,"end":1.5,"confidence":0.93}}}`))
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"speech_end"}`))
+		_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	})
+
+	provider := NewProvider(Config{APIKey: "gladia-key", APIBaseURL: server.URL, Language: "en"})
+	session, err := provider.StartStreaming(context.Background(), ports.StreamingConfig{InterimResults: true})
+	if err != nil {
+		t.Fatalf("start streaming failed: %v", err)
+	}
+	if err := session.SendAudio(make([]byte, 320)); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	_ = session.CloseSend()
+
+	var events []domain.TranscriptEvent
+	for event := range session.Events() {
+		events = append(events, event)
+	}
+	if err := session.Wait(); err != nil {
+		t.Fatalf("unexpected session error: %v", err)
+	}
+	if key != "gladia-key" || init.SampleRate != 16000 || init.LanguageConfig == nil {
+		t.Fatalf("unexpected session init key=%q %+v", key, init)
+	}
+	if strings.Join(messages, ",") != `audio,{"type":"stop_recording"}` {
+		t.Fatalf("unexpected client messages %v", messages)
+	}
+	if len(events) != 2 || events[0].Kind != domain.TranscriptKindPartial || events[0].Text != "hello wor" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+	final := events[1]
+	if final.Kind != domain.TranscriptKindFinal || final.Text != "Hello world." || final.Confidence != 0.93 {
+		t.Fatalf("unexpected final event: %+v", final)
+	}
+	if final.Start != 500*time.Millisecond || final.Duration != time.Second {
+		t.Fatalf("unexpected timing: start=%s duration=%s", final.Start, final.Duration)
+	}
+}
+
+func TestStartStreamingReportsRejectedSession(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"Invalid API key"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	_, err := NewProvider(Config{APIKey: "bad", APIBaseURL: server.URL}).StartStreaming(context.Background(), ports.StreamingConfig{})
+	if err == nil || !strings.Contains(err.Error(), "Invalid API key") {
+		t.Fatalf("expected rejected session error, got %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+// Package groq transcribes recordings with Groq's hosted Whisper models.
+package groq
+
+import (
+	"time"
+
+	"coldmic/internal/providers/openai"
+)
+
+// Config controls Groq audio transcription requests.
+type Config struct {
+	APIKey     string
+	APIBaseURL string
+	Model      string
+	Language   string
+	// Timeout bounds the upload and transcription of one recording.
+	Timeout time.Duration
+	// Proxy is an explicit proxy URL; empty uses the proxy environment.
+	Proxy string
+}
+
+// NewProvider returns a batch provider for Groq. Groq serves an
+// OpenAI-compatible transcription endpoint, so each recording is buffered
+// and submitted once the audio is closed, like the OpenAI provider.
+func NewProvider(cfg Config) *openai.Provider {
+	if cfg.APIBaseURL == "" {
+		cfg.APIBaseURL = "https://api.groq.com/openai/v1"
+	}
+	if cfg.Model == "" {
+		cfg.Model = "whisper-large-v3-turbo"
+	}
+	return openai.NewProvider(openai.Config{
+		APIKey:     cfg.APIKey,
+		APIBaseURL: cfg.APIBaseURL,
+		Model:      cfg.Model,
+		Language:   cfg.Language,
+		Timeout:    cfg.Timeout,
+		Service:    "groq",
+		KeyEnv:     "GROQ_API_KEY",
+		Proxy:      cfg.Proxy,
+	})
+}
@@ -0,0 +1,57 @@
+package groq
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestProviderSubmitsRecordingToGroq(t *testing.T) {
+	t.Parallel()
+
+	var model string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/v1/audio/transcriptions" || r.Header.Get("Authorization") != "Bearer gsk-test" {
+			t.Errorf("unexpected request %s %s", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		model = r.FormValue("model")
+		_, _ = w.Write([]byte(`{"text":" Fast and accurate. "}`))
+	}))
+	defer server.Close()
+
+	provider := NewProvider(Config{APIKey: "gsk-test", APIBaseURL: server.URL + "/openai/v1"})
+	stream, err := provider.StartStreaming(context.Background(), ports.StreamingConfig{SampleRate: 16000, Channels: 1})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	_ = stream.SendAudio(make([]byte, 3200))
+	_ = stream.CloseSend()
+
+	var events []domain.TranscriptEvent
+	for event := range stream.Events() {
+		events = append(events, event)
+	}
+	if err := stream.Wait(); err != nil {
+		t.Fatalf("wait failed: %v", err)
+	}
+	if model != "whisper-large-v3-turbo" {
+		t.Fatalf("unexpected model %q", model)
+	}
+	if len(events) != 1 || events[0].Kind != domain.TranscriptKindFinal || events[0].Text != "Fast and accurate." {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+}
+
+func TestProviderRequiresGroqKey(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewProvider(Config{}).StartStreaming(context.Background(), ports.StreamingConfig{})
+	if err == nil || !strings.Contains(err.Error(), "GROQ_API_KEY") {
+		t.Fatalf("expected missing key error, got %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/netproxy"
+	"coldmic/internal/ports"
+	"coldmic/internal/providers/wavbuffer"
+)
+
+// Config controls OpenAI audio transcription requests.
+type Config struct {
+	APIKey     string
+	APIBaseURL string
+	Model      string
+	Language   string
+	// Timeout bounds the upload and transcription of one recording.
+	Timeout time.Duration
+	// Service names an OpenAI-compatible API in logs and errors, and KeyEnv
+	// the variable its key comes from; they default to openai and
+	// OPENAI_API_KEY.
+	Service string
+	KeyEnv  string
+	// Proxy is an explicit proxy URL; empty uses the proxy environment.
+	Proxy string
+}
+
+// Provider implements ports.TranscriptionProvider for OpenAI's audio
+// transcription API. The API is not streaming, so each session buffers PCM
+// to a temporary WAV file and submits it once the audio is closed.
+type Provider struct {
+	cfg    Config
+	client *http.Client
+}
+
+func NewProvider(cfg Config) *Provider {
+	if cfg.APIBaseURL == "" {
+		cfg.APIBaseURL = "https://api.openai.com/v1"
+	}
+	if cfg.Model == "" {
+		cfg.Model = "whisper-1"
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 2 * time.Minute
+	}
+	if cfg.Service == "" {
+		cfg.Service = "openai"
+	}
+	if cfg.KeyEnv == "" {
+		cfg.KeyEnv = "OPENAI_API_KEY"
+	}
+	return &Provider{cfg: cfg, client: &http.Client{Timeout: cfg.Timeout, Transport: netproxy.Transport(cfg.Proxy)}}
+}
+
+// Capabilities describes batch transcription: one final transcript without
+// timing or confidence once the recording is submitted.
+func (p *Provider) Capabilities() domain.Capabilities {
+	return domain.Capabilities{}
+}
+
+// ProviderNames names the service usage is priced under, such as openai
+// or groq.
+func (p *Provider) ProviderNames() []string {
+	return []string{p.cfg.Service}
+}
+
+func (p *Provider) StartStreaming(ctx context.Context, cfg ports.StreamingConfig) (ports.StreamingSession, error) {
+	if strings.TrimSpace(p.cfg.APIKey) == "" {
+		return nil, fmt.Errorf("%s is not configured", p.cfg.KeyEnv)
+	}
+	return wavbuffer.NewSession(ctx, cfg, p.transcribe)
+}
+
+func (p *Provider) transcribe(ctx context.Context, path string) (string, error) {
+	audio, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read audio buffer: %w", err)
+	}
+
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	part, err := form.CreateFormFile("file", "audio.wav")
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(audio); err != nil {
+		return "", err
+	}
+	fields := map[string]string{"model": p.cfg.Model, "response_format": "json"}
+	if p.cfg.Language != "" {
+		fields["language"] = p.cfg.Language
+	}
+	for name, value := range fields {
+		if err := form.WriteField(name, value); err != nil {
+			return "", err
+		}
+	}
+	if err := form.Close(); err != nil {
+		return "", err
+	}
+
+	endpoint := strings.TrimRight(p.cfg.APIBaseURL, "/") + "/audio/transcriptions"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.cfg.APIKey)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	debuglog.Printf("%s transcription submit model=%s bytes=%d", p.cfg.Service, p.cfg.Model, len(audio))
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%s transcription request failed: %w", p.cfg.Service, err)
+	}
+	defer resp.Body.Close()
+
+	payload, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s response: %w", p.cfg.Service, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s transcription failed: %s: %s", p.cfg.Service, resp.Status, apiError(payload))
+	}
+	var result struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(payload, &result); err != nil {
+		return "", fmt.Errorf("invalid %s response: %w", p.cfg.Service, err)
+	}
+	return result.Text, nil
+}
+
+// apiError extracts the message from an OpenAI error body.
+func apiError(payload []byte) string {
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(payload, &body); err == nil && body.Error.Message != "" {
+		return body.Error.Message
+	}
+	return strings.TrimSpace(string(payload))
+}
@@ -0,0 +1,125 @@
+package openai
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestProviderSubmitsBufferedAudioOnCloseSend(t *testing.T) {
+	t.Parallel()
+
+	var fields map[string]string
+	var wav []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/audio/transcriptions" || r.Header.Get("Authorization") != "Bearer sk-test" {
+			t.Errorf("unexpected request %s %s", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("parse form: %v", err)
+		}
+		fields = map[string]string{"model": r.FormValue("model"), "language": r.FormValue("language")}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("missing file: %v", err)
+		} else {
+			wav, _ = io.ReadAll(file)
+		}
+		_, _ = w.Write([]byte(`{"text":" Hello there. "}`))
+	}))
+	defer server.Close()
+
+	provider := NewProvider(Config{APIKey: "sk-test", APIBaseURL: server.URL + "/v1/", Language: "en"})
+	stream, err := provider.StartStreaming(context.Background(), ports.StreamingConfig{SampleRate: 16000, Channels: 1})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	pcm := make([]byte, 32000)
+	if err := stream.SendAudio(pcm[:16000]); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	if err := stream.SendAudio(pcm[16000:]); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("close send failed: %v", err)
+	}
+	if err := stream.SendAudio(pcm); err == nil {
+		t.Fatalf("expected audio after CloseSend to be rejected")
+	}
+
+	var events []domain.TranscriptEvent
+	for event := range stream.Events() {
+		events = append(events, event)
+	}
+	if err := stream.Wait(); err != nil {
+		t.Fatalf("wait failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Kind != domain.TranscriptKindFinal || events[0].Text != "Hello there." || events[0].Duration != time.Second {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+	if fields["model"] != "whisper-1" || fields["language"] != "en" {
+		t.Fatalf("unexpected form fields: %+v", fields)
+	}
+	if len(wav) != 44+len(pcm) || string(wav[:4]) != "RIFF" || binary.LittleEndian.Uint32(wav[40:]) != uint32(len(pcm)) {
+		t.Fatalf("unexpected wav upload: %d bytes", len(wav))
+	}
+}
+
+func TestProviderReportsAPIError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":{"message":"Incorrect API key provided"}}`))
+	}))
+	defer server.Close()
+
+	stream, err := NewProvider(Config{APIKey: "bad", APIBaseURL: server.URL}).StartStreaming(context.Background(), ports.StreamingConfig{})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	_ = stream.SendAudio([]byte{1, 2})
+	_ = stream.CloseSend()
+	for range stream.Events() {
+		t.Fatalf("expected no transcript events")
+	}
+	if err := stream.Wait(); err == nil || !strings.Contains(err.Error(), "Incorrect API key provided") {
+		t.Fatalf("expected API error, got %v", err)
+	}
+}
+
+func TestProviderCloseDiscardsUnsubmittedAudio(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		t.Errorf("expected no request for discarded audio")
+	}))
+	defer server.Close()
+
+	if _, err := NewProvider(Config{}).StartStreaming(context.Background(), ports.StreamingConfig{}); err == nil {
+		t.Fatalf("expected missing API key error")
+	}
+	stream, err := NewProvider(Config{APIKey: "k", APIBaseURL: server.URL}).StartStreaming(context.Background(), ports.StreamingConfig{})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	_ = stream.SendAudio([]byte{1, 2})
+	if err := stream.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	if err := stream.Wait(); err != nil {
+		t.Fatalf("expected clean discard, got %v", err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("close send after close failed: %v", err)
+	}
+}
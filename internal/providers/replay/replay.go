@@ -0,0 +1,202 @@
+// Package replay plays scripted transcript events instead of transcribing,
+// so the app and its UI can be exercised without an API key or microphone.
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// Step is one scripted event in a fixture file. Kind is partial, final or
+// error; an error step ends the session with Text as its message.
+type Step struct {
+	// DelayMS waits before the step, counted from the previous one.
+	DelayMS    int     `json:"delay_ms"`
+	Kind       string  `json:"kind"`
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence,omitempty"`
+	// Start and Duration are utterance timing in seconds.
+	Start    float64 `json:"start,omitempty"`
+	Duration float64 `json:"duration,omitempty"`
+}
+
+// DefaultScript is replayed when no fixture file is configured.
+var DefaultScript = []Step{
+	{DelayMS: 400, Kind: "partial", Text: "this is"},
+	{DelayMS: 300, Kind: "partial", Text: "this is a replayed"},
+	{DelayMS: 300, Kind: "final", Text: "This is a replayed transcript.", Confidence: 0.97, Start: 0.2, Duration: 1.6},
+	{DelayMS: 500, Kind: "partial", Text: "no microphone"},
+	{DelayMS: 400, Kind: "final", Text: "No microphone or API key needed.", Confidence: 0.88, Start: 2.1, Duration: 1.8},
+}
+
+// Config controls the replay provider.
+type Config struct {
+	// Path is a JSON array of steps; empty replays DefaultScript.
+	Path string
+	// Speed scales delays, so 2 replays twice as fast; zero means 1.
+	Speed float64
+}
+
+// Provider implements ports.TranscriptionProvider by replaying a script.
+type Provider struct {
+	cfg Config
+}
+
+func NewProvider(cfg Config) *Provider {
+	if cfg.Speed <= 0 {
+		cfg.Speed = 1
+	}
+	return &Provider{cfg: cfg}
+}
+
+// Capabilities reports everything a script can express.
+func (p *Provider) Capabilities() domain.Capabilities {
+	return domain.Capabilities{
+		Streaming:    true,
+		LivePartials: true,
+		Timestamps:   true,
+		Confidence:   true,
+	}
+}
+
+// LoadScript reads and validates a fixture file.
+func LoadScript(path string) ([]Step, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay fixture: %w", err)
+	}
+	var steps []Step
+	if err := json.Unmarshal(data, &steps); err != nil {
+		return nil, fmt.Errorf("invalid replay fixture %s: %w", path, err)
+	}
+	for i, step := range steps {
+		switch step.Kind {
+		case "partial", "final", "error":
+		default:
+			return nil, fmt.Errorf("replay fixture %s step %d: unknown kind %q", path, i+1, step.Kind)
+		}
+	}
+	return steps, nil
+}
+
+// ProviderNames names the service usage is priced under.
+func (p *Provider) ProviderNames() []string {
+	return []string{"replay"}
+}
+
+// StartStreaming replays the script (re-read for every session, so fixtures
+// can be edited while the app runs). Audio is accepted and discarded;
+// CloseSend plays the remaining steps without delay.
+func (p *Provider) StartStreaming(ctx context.Context, _ ports.StreamingConfig) (ports.StreamingSession, error) {
+	steps := DefaultScript
+	if p.cfg.Path != "" {
+		var err error
+		if steps, err = LoadScript(p.cfg.Path); err != nil {
+			return nil, err
+		}
+	}
+	debuglog.Printf("replay started steps=%d speed=%.2f", len(steps), p.cfg.Speed)
+
+	s := &session{
+		events:    make(chan domain.TranscriptEvent, len(steps)+1),
+		closeSend: make(chan struct{}),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go s.play(ctx, steps, p.cfg.Speed)
+	return s, nil
+}
+
+type session struct {
+	events    chan domain.TranscriptEvent
+	closeSend chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+
+	closeSendOnce sync.Once
+	stopOnce      sync.Once
+	err           error
+}
+
+func (s *session) play(ctx context.Context, steps []Step, speed float64) {
+	defer close(s.done)
+	defer close(s.events)
+
+	hurry := false
+	for _, step := range steps {
+		if delay := time.Duration(float64(step.DelayMS) / speed * float64(time.Millisecond)); delay > 0 && !hurry {
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-s.closeSend:
+				timer.Stop()
+				hurry = true
+			case <-s.stop:
+				timer.Stop()
+				return
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+		if step.Kind == "error" {
+			s.err = errors.New(step.Text)
+			return
+		}
+		event := domain.TranscriptEvent{
+			Kind:       domain.TranscriptKind(step.Kind),
+			Text:       strings.TrimSpace(step.Text),
+			Confidence: step.Confidence,
+			Start:      time.Duration(step.Start * float64(time.Second)),
+			Duration:   time.Duration(step.Duration * float64(time.Second)),
+		}
+		event.IsSpeechFinal = event.Kind == domain.TranscriptKindFinal
+		s.events <- event
+	}
+
+	// A finished script behaves like a provider waiting for more audio.
+	select {
+	case <-s.closeSend:
+	case <-s.stop:
+	case <-ctx.Done():
+	}
+}
+
+func (s *session) SendAudio(_ []byte) error {
+	select {
+	case <-s.closeSend:
+		return errors.New("audio stream is already closed")
+	default:
+		return nil
+	}
+}
+
+func (s *session) CloseSend() error {
+	s.closeSendOnce.Do(func() { close(s.closeSend) })
+	return nil
+}
+
+func (s *session) Events() <-chan domain.TranscriptEvent {
+	return s.events
+}
+
+func (s *session) Wait() error {
+	<-s.done
+	return s.err
+}
+
+func (s *session) Close() error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	<-s.done
+	return s.err
+}
@@ -0,0 +1,107 @@
+package replay
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestProviderReplaysFixture(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "script.json")
+	fixture := `[
+		{"delay_ms": 5, "kind": "partial", "text": "ship"},
+		{"delay_ms": 5, "kind": "final", "text": " Ship it. ", "confidence": 0.9, "start": 0.5, "duration": 1},
+		{"delay_ms": 60000, "kind": "final", "text": "after stop"}
+	]`
+	if err := os.WriteFile(path, []byte(fixture), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	session, err := NewProvider(Config{Path: path}).StartStreaming(context.Background(), ports.StreamingConfig{})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if err := session.SendAudio([]byte("pcm")); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	<-session.Events()
+	final := <-session.Events()
+	if final.Kind != domain.TranscriptKindFinal || final.Text != "Ship it." || !final.IsSpeechFinal || final.Confidence != 0.9 {
+		t.Fatalf("unexpected final: %+v", final)
+	}
+	if final.Start != 500*time.Millisecond || final.Duration != time.Second {
+		t.Fatalf("unexpected timing: %+v", final)
+	}
+
+	// CloseSend skips the remaining delays.
+	_ = session.CloseSend()
+	var rest []string
+	for event := range session.Events() {
+		rest = append(rest, event.Text)
+	}
+	if len(rest) != 1 || rest[0] != "after stop" {
+		t.Fatalf("expected the remaining step without delay, got %q", rest)
+	}
+	if err := session.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestProviderReplaysErrors(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "error.json")
+	if err := os.WriteFile(path, []byte(`[{"kind": "error", "text": "simulated outage"}]`), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	session, err := NewProvider(Config{Path: path}).StartStreaming(context.Background(), ports.StreamingConfig{})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if err := session.Wait(); err == nil || err.Error() != "simulated outage" {
+		t.Fatalf("expected scripted error, got %v", err)
+	}
+}
+
+func TestLoadScriptRejectsUnknownKinds(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "bad.json")
+	if err := os.WriteFile(path, []byte(`[{"kind": "interim", "text": "x"}]`), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := NewProvider(Config{Path: path}).StartStreaming(context.Background(), ports.StreamingConfig{}); err == nil {
+		t.Fatalf("expected unknown kind to be rejected")
+	}
+	if _, err := LoadScript(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatalf("expected missing fixture to fail")
+	}
+}
+
+func TestProviderDefaultScriptAtSpeed(t *testing.T) {
+	t.Parallel()
+
+	session, err := NewProvider(Config{Speed: 1000}).StartStreaming(context.Background(), ports.StreamingConfig{})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	var finals int
+	for i := 0; i < len(DefaultScript); i++ {
+		if event := <-session.Events(); event.Kind == domain.TranscriptKindFinal {
+			finals++
+		}
+	}
+	if finals != 2 {
+		t.Fatalf("expected two finals in the default script, got %d", finals)
+	}
+	if err := session.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+}
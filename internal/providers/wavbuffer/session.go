@@ -0,0 +1,198 @@
+// Package wavbuffer adapts file-based transcription engines to
+// ports.StreamingSession by buffering PCM to a temporary WAV file.
+package wavbuffer
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+const headerSize = 44
+
+// Transcribe turns the finished WAV file at path into text.
+type Transcribe func(ctx context.Context, path string) (string, error)
+
+// NewSession starts buffering audio. Once CloseSend is called the file is
+// handed to transcribe, and its text arrives as a single final event.
+func NewSession(ctx context.Context, cfg ports.StreamingConfig, transcribe Transcribe) (ports.StreamingSession, error) {
+	if cfg.SampleRate <= 0 {
+		cfg.SampleRate = 16000
+	}
+	if cfg.Channels <= 0 {
+		cfg.Channels = 1
+	}
+
+	file, err := os.CreateTemp("", "coldmic-*.wav")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audio buffer: %w", err)
+	}
+	if _, err := file.Write(make([]byte, headerSize)); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return nil, fmt.Errorf("failed to write audio buffer: %w", err)
+	}
+
+	return &session{
+		ctx:        ctx,
+		cfg:        cfg,
+		transcribe: transcribe,
+		file:       file,
+		events:     make(chan domain.TranscriptEvent, 1),
+		done:       make(chan struct{}),
+	}, nil
+}
+
+type session struct {
+	ctx        context.Context
+	cfg        ports.StreamingConfig
+	transcribe Transcribe
+
+	mu      sync.Mutex
+	file    *os.File
+	written uint32
+	closed  bool
+
+	events     chan domain.TranscriptEvent
+	done       chan struct{}
+	finishOnce sync.Once
+	err        error
+}
+
+func (s *session) SendAudio(chunk []byte) error {
+	if len(chunk) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errors.New("audio stream is already closed")
+	}
+	n, err := s.file.Write(chunk)
+	s.written += uint32(n)
+	if err != nil {
+		return fmt.Errorf("failed to buffer audio: %w", err)
+	}
+	return nil
+}
+
+// CloseSend finishes the WAV file and starts transcribing it.
+func (s *session) CloseSend() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	go func() {
+		text, err := s.run()
+		if text = strings.TrimSpace(text); err == nil && text != "" {
+			s.events <- domain.TranscriptEvent{
+				Kind:          domain.TranscriptKindFinal,
+				Text:          text,
+				IsSpeechFinal: true,
+				Duration:      s.duration(),
+			}
+		}
+		s.finish(err)
+	}()
+	return nil
+}
+
+func (s *session) Events() <-chan domain.TranscriptEvent {
+	return s.events
+}
+
+func (s *session) Wait() error {
+	<-s.done
+	return s.err
+}
+
+// Close discards audio that was never submitted. A transcription already in
+// progress still completes so its transcript is not lost.
+func (s *session) Close() error {
+	s.mu.Lock()
+	submitting := s.closed
+	s.closed = true
+	s.mu.Unlock()
+	if !submitting {
+		s.finish(nil)
+	}
+	return nil
+}
+
+func (s *session) run() (string, error) {
+	if s.written == 0 {
+		return "", nil
+	}
+	if err := s.writeHeader(); err != nil {
+		return "", err
+	}
+	if err := s.file.Sync(); err != nil {
+		return "", fmt.Errorf("failed to flush audio buffer: %w", err)
+	}
+	// Transcriptions run to completion even if the session context ends.
+	return s.transcribe(context.WithoutCancel(s.ctx), s.file.Name())
+}
+
+func (s *session) finish(err error) {
+	s.finishOnce.Do(func() {
+		s.err = err
+		_ = s.file.Close()
+		_ = os.Remove(s.file.Name())
+		close(s.events)
+		close(s.done)
+	})
+}
+
+func (s *session) duration() time.Duration {
+	bytesPerSecond := s.cfg.SampleRate * s.cfg.Channels * 2
+	return time.Duration(s.written) * time.Second / time.Duration(bytesPerSecond)
+}
+
+func (s *session) writeHeader() error {
+	if _, err := s.file.WriteAt(header(s.cfg, s.written), 0); err != nil {
+		return fmt.Errorf("failed to write wav header: %w", err)
+	}
+	return nil
+}
+
+// Encode wraps 16-bit PCM in a WAV container described by cfg.
+func Encode(cfg ports.StreamingConfig, pcm []byte) []byte {
+	if cfg.SampleRate <= 0 {
+		cfg.SampleRate = 16000
+	}
+	if cfg.Channels <= 0 {
+		cfg.Channels = 1
+	}
+	return append(header(cfg, uint32(len(pcm))), pcm...)
+}
+
+func header(cfg ports.StreamingConfig, dataSize uint32) []byte {
+	blockAlign := cfg.Channels * 2
+	header := make([]byte, headerSize)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], 36+dataSize)
+	copy(header[8:], "WAVE")
+	copy(header[12:], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:], 16)
+	binary.LittleEndian.PutUint16(header[20:], 1)
+	binary.LittleEndian.PutUint16(header[22:], uint16(cfg.Channels))
+	binary.LittleEndian.PutUint32(header[24:], uint32(cfg.SampleRate))
+	binary.LittleEndian.PutUint32(header[28:], uint32(cfg.SampleRate*blockAlign))
+	binary.LittleEndian.PutUint16(header[32:], uint16(blockAlign))
+	binary.LittleEndian.PutUint16(header[34:], 16)
+	copy(header[36:], "data")
+	binary.LittleEndian.PutUint32(header[40:], dataSize)
+	return header
+}
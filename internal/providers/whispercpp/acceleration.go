@@ -0,0 +1,83 @@
+package whispercpp
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Acceleration backends whisper.cpp can offload inference to.
+const (
+	AccelerationAuto   = "auto"
+	AccelerationCPU    = "cpu"
+	AccelerationCUDA   = "cuda"
+	AccelerationVulkan = "vulkan"
+	AccelerationMetal  = "metal"
+	AccelerationCoreML = "coreml"
+)
+
+// autoPreference orders the backends tried by AccelerationAuto.
+var autoPreference = []string{AccelerationCUDA, AccelerationMetal, AccelerationCoreML, AccelerationVulkan}
+
+// probe looks for accelerators on this machine; tests replace its functions.
+type probe struct {
+	goos     string
+	lookPath func(string) (string, error)
+	exists   func(string) bool
+}
+
+func systemProbe() probe {
+	return probe{
+		goos:     runtime.GOOS,
+		lookPath: exec.LookPath,
+		exists: func(path string) bool {
+			_, err := os.Stat(path)
+			return err == nil
+		},
+	}
+}
+
+// detect lists the accelerators available for modelPath, always ending with
+// the CPU. CoreML additionally needs the model's converted encoder next to it.
+func (p probe) detect(modelPath string) []string {
+	var available []string
+	if _, err := p.lookPath("nvidia-smi"); err == nil || p.exists("/dev/nvidia0") {
+		available = append(available, AccelerationCUDA)
+	}
+	if p.goos == "darwin" {
+		available = append(available, AccelerationMetal)
+		if p.exists(coreMLEncoder(modelPath)) {
+			available = append(available, AccelerationCoreML)
+		}
+	}
+	if p.exists("/usr/share/vulkan/icd.d") || p.exists("/etc/vulkan/icd.d") {
+		available = append(available, AccelerationVulkan)
+	}
+	return append(available, AccelerationCPU)
+}
+
+// coreMLEncoder is where whisper.cpp looks for a model's CoreML encoder, such
+// as ggml-base.en-encoder.mlmodelc for ggml-base.en.bin.
+func coreMLEncoder(modelPath string) string {
+	return strings.TrimSuffix(modelPath, filepath.Ext(modelPath)) + "-encoder.mlmodelc"
+}
+
+// resolveAcceleration picks the backend to use for requested, falling back
+// to the CPU when it is not available.
+func resolveAcceleration(requested string, available []string) string {
+	if requested == "" || requested == AccelerationAuto {
+		for _, backend := range autoPreference {
+			if slices.Contains(available, backend) {
+				return backend
+			}
+		}
+		return AccelerationCPU
+	}
+	if slices.Contains(available, requested) {
+		return requested
+	}
+	return AccelerationCPU
+}
@@ -0,0 +1,83 @@
+package whispercpp
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"coldmic/internal/ports"
+)
+
+func fakeProbe(goos string, tools []string, paths ...string) probe {
+	return probe{
+		goos: goos,
+		lookPath: func(name string) (string, error) {
+			if slices.Contains(tools, name) {
+				return "/usr/bin/" + name, nil
+			}
+			return "", errors.New("not found")
+		},
+		exists: func(path string) bool { return slices.Contains(paths, path) },
+	}
+}
+
+func TestProbeDetectsAccelerators(t *testing.T) {
+	t.Parallel()
+
+	linux := fakeProbe("linux", []string{"nvidia-smi"}, "/usr/share/vulkan/icd.d").detect("ggml-base.en.bin")
+	if !slices.Equal(linux, []string{"cuda", "vulkan", "cpu"}) {
+		t.Fatalf("unexpected linux accelerators: %v", linux)
+	}
+	mac := fakeProbe("darwin", nil, "/models/ggml-base.en-encoder.mlmodelc").detect("/models/ggml-base.en.bin")
+	if !slices.Equal(mac, []string{"metal", "coreml", "cpu"}) {
+		t.Fatalf("unexpected darwin accelerators: %v", mac)
+	}
+	if none := fakeProbe("linux", nil).detect("m.bin"); !slices.Equal(none, []string{"cpu"}) {
+		t.Fatalf("expected cpu only, got %v", none)
+	}
+}
+
+func TestResolveAccelerationFallsBackToCPU(t *testing.T) {
+	t.Parallel()
+
+	available := []string{"vulkan", "cpu"}
+	cases := map[string]string{"": "vulkan", "auto": "vulkan", "vulkan": "vulkan", "cuda": "cpu", "cpu": "cpu"}
+	for requested, want := range cases {
+		if got := resolveAcceleration(requested, available); got != want {
+			t.Fatalf("resolve %q: got %q, want %q", requested, got, want)
+		}
+	}
+}
+
+func TestProviderRetriesFailedGPURunOnCPU(t *testing.T) {
+	t.Parallel()
+
+	command := writeScript(t, `for arg in "$@"; do
+  if [ "$arg" = "--no-gpu" ]; then echo ' from the cpu'; exit 0; fi
+done
+echo 'ggml_cuda_init: failed to initialize CUDA' >&2
+exit 1
+`)
+	provider := newProvider(Config{Command: command, ModelPath: "m.bin", Acceleration: "cuda"}, fakeProbe("linux", []string{"nvidia-smi"}))
+	if got := provider.Acceleration(); got.Active != "cuda" || got.Requested != "cuda" || !slices.Equal(got.Available, []string{"cuda", "cpu"}) {
+		t.Fatalf("unexpected acceleration before run: %+v", got)
+	}
+
+	stream, err := provider.StartStreaming(context.Background(), ports.StreamingConfig{})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	_ = stream.SendAudio(make([]byte, 3200))
+	_ = stream.CloseSend()
+	event, ok := <-stream.Events()
+	if !ok || event.Text != "from the cpu" {
+		t.Fatalf("expected cpu transcript, got %+v", event)
+	}
+	if err := stream.Wait(); err != nil {
+		t.Fatalf("wait failed: %v", err)
+	}
+	if got := provider.Acceleration().Active; got != "cpu" {
+		t.Fatalf("expected provider to stay on cpu, got %q", got)
+	}
+}
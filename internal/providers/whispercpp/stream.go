@@ -0,0 +1,257 @@
+package whispercpp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+	"coldmic/internal/providers/wavbuffer"
+)
+
+// windowSession transcribes a sliding window of the recording while it is
+// captured. Every Step of new audio the uncommitted audio, plus Overlap of
+// already committed audio for context, is transcribed and shown as a
+// partial. Once the uncommitted audio reaches Window it is committed as a
+// final transcript, with words repeated from the overlap removed.
+type windowSession struct {
+	provider *Provider
+	ctx      context.Context
+	cfg      ports.StreamingConfig
+
+	step, window, overlap int
+
+	mu        sync.Mutex
+	pcm       []byte
+	committed int
+	inferred  int
+	lastWords []string
+	closed    bool
+	discarded bool
+
+	kick   chan struct{}
+	events chan domain.TranscriptEvent
+	done   chan struct{}
+	err    error
+}
+
+func newWindowSession(ctx context.Context, provider *Provider, cfg ports.StreamingConfig) *windowSession {
+	if cfg.SampleRate <= 0 {
+		cfg.SampleRate = 16000
+	}
+	if cfg.Channels <= 0 {
+		cfg.Channels = 1
+	}
+	s := &windowSession{
+		provider: provider,
+		// Inference runs to completion even if the session context ends.
+		ctx:     context.WithoutCancel(ctx),
+		cfg:     cfg,
+		step:    provider.bytesFor(cfg, provider.cfg.Step),
+		window:  provider.bytesFor(cfg, provider.cfg.Window),
+		overlap: provider.bytesFor(cfg, provider.cfg.Overlap),
+		kick:    make(chan struct{}, 1),
+		events:  make(chan domain.TranscriptEvent, 64),
+		done:    make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// bytesFor converts d to a whole number of PCM frames for cfg.
+func (p *Provider) bytesFor(cfg ports.StreamingConfig, d time.Duration) int {
+	frame := cfg.Channels * 2
+	return int(d*time.Duration(cfg.SampleRate)/time.Second) * frame
+}
+
+func (s *windowSession) SendAudio(chunk []byte) error {
+	if len(chunk) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return errors.New("audio stream is already closed")
+	}
+	s.pcm = append(s.pcm, chunk...)
+	s.mu.Unlock()
+	s.signal()
+	return nil
+}
+
+// CloseSend transcribes the remaining audio as the last final transcript.
+func (s *windowSession) CloseSend() error {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.signal()
+	return nil
+}
+
+func (s *windowSession) Events() <-chan domain.TranscriptEvent {
+	return s.events
+}
+
+func (s *windowSession) Wait() error {
+	<-s.done
+	return s.err
+}
+
+// Close discards audio that was never submitted. After CloseSend the last
+// window still completes so its transcript is not lost.
+func (s *windowSession) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		s.discarded = true
+	}
+	s.mu.Unlock()
+	s.signal()
+	return nil
+}
+
+func (s *windowSession) signal() {
+	select {
+	case s.kick <- struct{}{}:
+	default:
+	}
+}
+
+func (s *windowSession) run() {
+	defer close(s.done)
+	defer close(s.events)
+
+	for range s.kick {
+		s.mu.Lock()
+		closed, discarded := s.closed, s.discarded
+		total, committed, inferred := len(s.pcm), s.committed, s.inferred
+		s.mu.Unlock()
+
+		if discarded {
+			return
+		}
+		if closed {
+			if total > committed {
+				_, s.err = s.infer(committed, total, domain.TranscriptKindFinal)
+			}
+			return
+		}
+		if total-committed >= s.window {
+			if _, err := s.infer(committed, committed+s.window, domain.TranscriptKindFinal); err != nil {
+				s.err = err
+				return
+			}
+			// Audio may have piled up during inference; look again.
+			s.signal()
+			continue
+		}
+		if total-inferred >= s.step {
+			if _, err := s.infer(committed, total, domain.TranscriptKindPartial); err != nil {
+				s.err = err
+				return
+			}
+		}
+	}
+}
+
+// infer transcribes pcm[from:to] with the preceding overlap and emits it.
+func (s *windowSession) infer(from, to int, kind domain.TranscriptKind) (string, error) {
+	start := max(from-s.overlap, 0)
+	s.mu.Lock()
+	pcm := append([]byte(nil), s.pcm[start:to]...)
+	previous := s.lastWords
+	s.mu.Unlock()
+
+	text, err := s.provider.TranscribeClip(s.ctx, s.cfg, pcm)
+	if err != nil {
+		return "", err
+	}
+	text = dedupOverlap(previous, text)
+
+	s.mu.Lock()
+	s.inferred = to
+	if kind == domain.TranscriptKindFinal {
+		s.committed = to
+		s.lastWords = tailWords(append(previous, strings.Fields(text)...), overlapWords)
+	}
+	s.mu.Unlock()
+
+	if text != "" {
+		debuglog.Printf("whisper.cpp window kind=%s from=%d to=%d text_len=%d", kind, from, to, len(text))
+		s.events <- domain.TranscriptEvent{
+			Kind:          kind,
+			Text:          text,
+			IsSpeechFinal: kind == domain.TranscriptKindFinal,
+			Start:         s.duration(from),
+			Duration:      s.duration(to - from),
+		}
+	}
+	return text, nil
+}
+
+func (s *windowSession) duration(bytes int) time.Duration {
+	return time.Duration(bytes) * time.Second / time.Duration(s.cfg.SampleRate*s.cfg.Channels*2)
+}
+
+// overlapWords bounds how many committed words are compared with the start
+// of the next window.
+const overlapWords = 12
+
+// dedupOverlap drops the leading words of text that repeat the end of the
+// previously committed words, since each window re-hears the overlap.
+func dedupOverlap(previous []string, text string) string {
+	words := strings.Fields(text)
+	for n := min(len(previous), len(words), overlapWords); n > 0; n-- {
+		if sameWords(previous[len(previous)-n:], words[:n]) {
+			return strings.Join(words[n:], " ")
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func sameWords(a, b []string) bool {
+	for i := range a {
+		if normalizeWord(a[i]) != normalizeWord(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeWord(word string) string {
+	return strings.ToLower(strings.TrimFunc(word, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}))
+}
+
+func tailWords(words []string, n int) []string {
+	if len(words) > n {
+		words = words[len(words)-n:]
+	}
+	return append([]string(nil), words...)
+}
+
+// TranscribeClip transcribes raw PCM described by cfg through a temporary
+// WAV file.
+func (p *Provider) TranscribeClip(ctx context.Context, cfg ports.StreamingConfig, pcm []byte) (string, error) {
+	file, err := os.CreateTemp("", "coldmic-clip-*.wav")
+	if err != nil {
+		return "", fmt.Errorf("failed to create audio clip: %w", err)
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(wavbuffer.Encode(cfg, pcm))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write audio clip: %w", err)
+	}
+	return p.transcribe(ctx, file.Name())
+}
@@ -0,0 +1,188 @@
+// Package whispercpp transcribes audio offline with the whisper.cpp CLI.
+package whispercpp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+	"coldmic/internal/providers/wavbuffer"
+)
+
+// Config controls the whisper.cpp command.
+type Config struct {
+	// Command is the whisper.cpp CLI binary, such as whisper-cli.
+	Command string
+	// ModelPath is the ggml model file passed with -m.
+	ModelPath string
+	Language  string
+	Threads   int
+	// Acceleration selects the inference backend: auto, cpu, cuda, vulkan,
+	// metal or coreml. The whisper.cpp build must include the backend.
+	Acceleration string
+	// Device selects the GPU when several are present.
+	Device int
+	// Step is how much new audio triggers a partial transcript while
+	// recording; 0 transcribes only once recording stops. Window caps the
+	// audio transcribed at once before it is committed, and Overlap is the
+	// committed audio re-transcribed for context at the start of a window.
+	Step    time.Duration
+	Window  time.Duration
+	Overlap time.Duration
+	// Timeout bounds the transcription of one recording.
+	Timeout time.Duration
+}
+
+// Provider implements ports.TranscriptionProvider by running whisper.cpp on
+// each recording. With a Step, a sliding window of the recording is
+// transcribed while it is captured; otherwise audio is buffered to a
+// temporary WAV file and transcribed once it is closed, producing a single
+// final transcript.
+type Provider struct {
+	cfg       Config
+	available []string
+
+	mu     sync.Mutex
+	active string
+}
+
+func NewProvider(cfg Config) *Provider {
+	return newProvider(cfg, systemProbe())
+}
+
+func newProvider(cfg Config, probe probe) *Provider {
+	if cfg.Command == "" {
+		cfg.Command = "whisper-cli"
+	}
+	if cfg.Acceleration == "" {
+		cfg.Acceleration = AccelerationAuto
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Minute
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = 15 * time.Second
+	}
+	if cfg.Overlap < 0 || cfg.Overlap >= cfg.Window {
+		cfg.Overlap = 0
+	}
+	available := probe.detect(cfg.ModelPath)
+	active := resolveAcceleration(cfg.Acceleration, available)
+	if active != cfg.Acceleration && cfg.Acceleration != AccelerationAuto {
+		debuglog.Printf("whisper.cpp acceleration %s unavailable; using %s", cfg.Acceleration, active)
+	}
+	return &Provider{cfg: cfg, available: available, active: active}
+}
+
+// Acceleration reports the requested, detected and active inference backends.
+func (p *Provider) Acceleration() domain.Acceleration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return domain.Acceleration{
+		Requested: p.cfg.Acceleration,
+		Available: append([]string(nil), p.available...),
+		Active:    p.active,
+	}
+}
+
+// Capabilities describes local transcription. Live partials and their
+// timing need a Step, and English-only models (*.en) transcribe only English.
+func (p *Provider) Capabilities() domain.Capabilities {
+	acceleration := p.Acceleration()
+	capabilities := domain.Capabilities{
+		Streaming:    p.cfg.Step > 0,
+		LivePartials: p.cfg.Step > 0,
+		Timestamps:   p.cfg.Step > 0,
+		Acceleration: &acceleration,
+	}
+	if strings.HasSuffix(strings.TrimSuffix(filepath.Base(p.cfg.ModelPath), ".bin"), ".en") {
+		capabilities.Languages = []string{"en"}
+	}
+	return capabilities
+}
+
+// ProviderNames names the service usage is priced under.
+func (p *Provider) ProviderNames() []string {
+	return []string{"whispercpp"}
+}
+
+func (p *Provider) StartStreaming(ctx context.Context, cfg ports.StreamingConfig) (ports.StreamingSession, error) {
+	if strings.TrimSpace(p.cfg.ModelPath) == "" {
+		return nil, errors.New("COLDMIC_WHISPERCPP_MODEL is not configured")
+	}
+	if _, err := exec.LookPath(p.cfg.Command); err != nil {
+		return nil, fmt.Errorf("whisper.cpp command %q not found: %w", p.cfg.Command, err)
+	}
+	if cfg.InterimResults && p.cfg.Step > 0 {
+		return newWindowSession(ctx, p, cfg), nil
+	}
+	return wavbuffer.NewSession(ctx, cfg, p.transcribe)
+}
+
+func (p *Provider) transcribe(ctx context.Context, path string) (string, error) {
+	p.mu.Lock()
+	backend := p.active
+	p.mu.Unlock()
+
+	text, err := p.run(ctx, path, backend)
+	if err == nil || backend == AccelerationCPU || ctx.Err() != nil {
+		return text, err
+	}
+	// A GPU build or driver problem should not cost the transcript: retry on
+	// the CPU and stay there for later recordings.
+	debuglog.Printf("whisper.cpp %s run failed, falling back to cpu: %v", backend, err)
+	p.mu.Lock()
+	p.active = AccelerationCPU
+	p.mu.Unlock()
+	return p.run(ctx, path, AccelerationCPU)
+}
+
+func (p *Provider) run(ctx context.Context, path string, backend string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+	defer cancel()
+
+	args := []string{"-m", p.cfg.ModelPath, "-f", path, "--no-timestamps", "--no-prints"}
+	if p.cfg.Language != "" {
+		args = append(args, "-l", p.cfg.Language)
+	}
+	if p.cfg.Threads > 0 {
+		args = append(args, "-t", strconv.Itoa(p.cfg.Threads))
+	}
+	if backend == AccelerationCPU {
+		args = append(args, "--no-gpu")
+	} else if p.cfg.Device > 0 {
+		args = append(args, "-dev", strconv.Itoa(p.cfg.Device))
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.cfg.Command, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	debuglog.Printf("whisper.cpp transcription start command=%s model=%s backend=%s", p.cfg.Command, p.cfg.ModelPath, backend)
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("whisper.cpp timed out after %s", p.cfg.Timeout)
+		}
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return "", fmt.Errorf("whisper.cpp failed: %w: %s", err, detail)
+		}
+		return "", fmt.Errorf("whisper.cpp failed: %w", err)
+	}
+	return joinLines(stdout.String()), nil
+}
+
+// joinLines flattens whisper.cpp's one-segment-per-line output.
+func joinLines(output string) string {
+	return strings.Join(strings.Fields(output), " ")
+}
@@ -0,0 +1,158 @@
+package whispercpp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func writeScript(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "whisper-cli")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0o755); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+	return path
+}
+
+func TestProviderTranscribesBufferedAudio(t *testing.T) {
+	t.Parallel()
+
+	argsFile := filepath.Join(t.TempDir(), "args")
+	command := writeScript(t, `echo "$@" > `+argsFile+`
+printf ' Hello there.\n How are you?\n'
+`)
+	provider := NewProvider(Config{Command: command, ModelPath: "ggml-base.en.bin", Language: "en", Threads: 4})
+	stream, err := provider.StartStreaming(context.Background(), ports.StreamingConfig{SampleRate: 16000, Channels: 1})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if err := stream.SendAudio(make([]byte, 32000)); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("close send failed: %v", err)
+	}
+
+	var events []domain.TranscriptEvent
+	for event := range stream.Events() {
+		events = append(events, event)
+	}
+	if err := stream.Wait(); err != nil {
+		t.Fatalf("wait failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Kind != domain.TranscriptKindFinal || events[0].Text != "Hello there. How are you?" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+	if events[0].Duration.Seconds() != 1 {
+		t.Fatalf("expected 1s duration, got %s", events[0].Duration)
+	}
+	args, _ := os.ReadFile(argsFile)
+	for _, want := range []string{"-m ggml-base.en.bin", "-l en", "-t 4", "--no-timestamps"} {
+		if !strings.Contains(string(args), want) {
+			t.Fatalf("expected %q in args %q", want, args)
+		}
+	}
+}
+
+func TestProviderReportsCommandFailure(t *testing.T) {
+	t.Parallel()
+
+	command := writeScript(t, "echo 'failed to load model' >&2\nexit 1\n")
+	provider := NewProvider(Config{Command: command, ModelPath: "missing.bin"})
+	stream, err := provider.StartStreaming(context.Background(), ports.StreamingConfig{})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	_ = stream.SendAudio(make([]byte, 3200))
+	_ = stream.CloseSend()
+	for range stream.Events() {
+		t.Fatalf("expected no transcript events")
+	}
+	if err := stream.Wait(); err == nil || !strings.Contains(err.Error(), "failed to load model") {
+		t.Fatalf("expected command error, got %v", err)
+	}
+}
+
+func TestProviderRequiresModel(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewProvider(Config{Command: "sh"}).StartStreaming(context.Background(), ports.StreamingConfig{}); err == nil {
+		t.Fatalf("expected missing model error")
+	}
+}
+
+func TestProviderStreamsWindowedPartials(t *testing.T) {
+	t.Parallel()
+
+	counter := filepath.Join(t.TempDir(), "count")
+	command := writeScript(t, `n=$(cat `+counter+` 2>/dev/null || echo 0); n=$((n+1)); echo $n > `+counter+`
+case $n in
+1) echo ' Hello there' ;;
+2) echo ' Hello there, how are you' ;;
+*) echo ' are you doing today?' ;;
+esac
+`)
+	provider := NewProvider(Config{
+		Command:   command,
+		ModelPath: "ggml-base.en.bin",
+		Step:      time.Second,
+		Window:    2 * time.Second,
+		Overlap:   500 * time.Millisecond,
+	})
+	stream, err := provider.StartStreaming(context.Background(), ports.StreamingConfig{SampleRate: 16000, Channels: 1, InterimResults: true})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+
+	next := func() domain.TranscriptEvent {
+		t.Helper()
+		select {
+		case event := <-stream.Events():
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for transcript")
+			return domain.TranscriptEvent{}
+		}
+	}
+
+	_ = stream.SendAudio(make([]byte, 32000))
+	if event := next(); event.Kind != domain.TranscriptKindPartial || event.Text != "Hello there" {
+		t.Fatalf("unexpected partial: %+v", event)
+	}
+	_ = stream.SendAudio(make([]byte, 32000))
+	if event := next(); event.Kind != domain.TranscriptKindFinal || event.Text != "Hello there, how are you" || event.Duration != 2*time.Second {
+		t.Fatalf("unexpected window final: %+v", event)
+	}
+	_ = stream.SendAudio(make([]byte, 16000))
+	_ = stream.CloseSend()
+	if event := next(); event.Kind != domain.TranscriptKindFinal || event.Text != "doing today?" || event.Start != 2*time.Second {
+		t.Fatalf("expected overlap to be removed, got %+v", event)
+	}
+	if err := stream.Wait(); err != nil {
+		t.Fatalf("wait failed: %v", err)
+	}
+}
+
+func TestDedupOverlap(t *testing.T) {
+	t.Parallel()
+
+	previous := []string{"so", "how", "are", "you?"}
+	for text, want := range map[string]string{
+		"are you doing":    "doing",
+		"How are you, Sam": "Sam",
+		"fine thanks":      "fine thanks",
+		"  you  ":          "",
+		"are we done":      "are we done",
+	} {
+		if got := dedupOverlap(previous, text); got != want {
+			t.Fatalf("dedupOverlap(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
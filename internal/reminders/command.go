@@ -0,0 +1,44 @@
+package reminders
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+// Command hands reminders to a user command. The reminder text is passed on
+// stdin and in COLDMIC_REMINDER_TEXT, and its time in COLDMIC_REMINDER_AT
+// (RFC 3339) and COLDMIC_REMINDER_UNIX.
+type Command struct {
+	args []string
+}
+
+func NewCommand(args []string) *Command {
+	return &Command{args: args}
+}
+
+func (c *Command) CreateReminder(ctx context.Context, reminder domain.Reminder) error {
+	if len(c.args) == 0 {
+		return errors.New("no reminder command configured")
+	}
+	cmd := exec.CommandContext(ctx, c.args[0], c.args[1:]...)
+	cmd.Env = append(os.Environ(),
+		"COLDMIC_REMINDER_TEXT="+reminder.Text,
+		"COLDMIC_REMINDER_AT="+reminder.At.Format(time.RFC3339),
+		fmt.Sprintf("COLDMIC_REMINDER_UNIX=%d", reminder.At.Unix()),
+	)
+	cmd.Stdin = strings.NewReader(reminder.Text)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", c.args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package reminders
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+const (
+	icsTimeFormat    = "20060102T150405Z"
+	icsEventDuration = 15 * time.Minute
+	icsLineLimit     = 75
+)
+
+// ICSDir writes each reminder as a one-file calendar event with an alarm
+// into a vdir, the layout khal and vdirsyncer read.
+type ICSDir struct {
+	dir string
+	now func() time.Time
+}
+
+func NewICSDir(dir string) *ICSDir {
+	return &ICSDir{dir: dir, now: time.Now}
+}
+
+func (d *ICSDir) CreateReminder(_ context.Context, reminder domain.Reminder) error {
+	if err := os.MkdirAll(d.dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create reminder directory: %w", err)
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	uid := hex.EncodeToString(id) + "@coldmic"
+
+	start := reminder.At.UTC()
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//coldmic//reminders//EN",
+		"BEGIN:VEVENT",
+		"UID:" + uid,
+		"DTSTAMP:" + d.now().UTC().Format(icsTimeFormat),
+		"DTSTART:" + start.Format(icsTimeFormat),
+		"DTEND:" + start.Add(icsEventDuration).Format(icsTimeFormat),
+		"SUMMARY:" + escapeICSText(reminder.Text),
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"DESCRIPTION:" + escapeICSText(reminder.Text),
+		"TRIGGER:PT0M",
+		"END:VALARM",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(foldICSLine(line))
+		b.WriteString("\r\n")
+	}
+
+	path := filepath.Join(d.dir, strings.TrimSuffix(uid, "@coldmic")+".ics")
+	if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+		return fmt.Errorf("failed to write reminder: %w", err)
+	}
+	return nil
+}
+
+func escapeICSText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(text)
+}
+
+// foldICSLine splits lines longer than 75 octets as RFC 5545 requires,
+// without breaking UTF-8 sequences.
+func foldICSLine(line string) string {
+	if len(line) <= icsLineLimit {
+		return line
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > icsLineLimit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
@@ -0,0 +1,83 @@
+package reminders
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+func TestICSDirWritesEventWithAlarm(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "reminders")
+	store := NewICSDir(dir)
+	store.now = func() time.Time { return time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC) }
+	at := time.Date(2026, 10, 15, 19, 30, 0, 0, time.FixedZone("CEST", 2*3600))
+
+	if err := store.CreateReminder(context.Background(), domain.Reminder{Text: "call Sam, re: lease; bring keys", At: at}); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 || !strings.HasSuffix(entries[0].Name(), ".ics") {
+		t.Fatalf("expected one ics file, got %v: %v", entries, err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	ics := string(data)
+	for _, want := range []string{
+		"BEGIN:VEVENT\r\n",
+		"DTSTART:20261015T173000Z\r\n",
+		"DTEND:20261015T174500Z\r\n",
+		`SUMMARY:call Sam\, re: lease\; bring keys` + "\r\n",
+		"TRIGGER:PT0M\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Fatalf("expected %q in:\n%s", want, ics)
+		}
+	}
+}
+
+func TestFoldICSLine(t *testing.T) {
+	t.Parallel()
+
+	line := "SUMMARY:" + strings.Repeat("é", 50)
+	folded := foldICSLine(line)
+	for _, part := range strings.Split(folded, "\r\n") {
+		if len(part) > icsLineLimit {
+			t.Fatalf("folded line too long: %d", len(part))
+		}
+	}
+	if strings.ReplaceAll(folded, "\r\n ", "") != line {
+		t.Fatalf("unfolding did not restore the line")
+	}
+}
+
+func TestCommandPassesReminderToScript(t *testing.T) {
+	t.Parallel()
+
+	out := filepath.Join(t.TempDir(), "out.txt")
+	script := filepath.Join(t.TempDir(), "remind.sh")
+	contents := "#!/usr/bin/env bash\nprintf '%s|%s|%s' \"$(cat)\" \"$COLDMIC_REMINDER_AT\" \"$COLDMIC_REMINDER_UNIX\" > \"$1\"\n"
+	if err := os.WriteFile(script, []byte(contents), 0o700); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+
+	at := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	if err := NewCommand([]string{script, out}).CreateReminder(context.Background(), domain.Reminder{Text: "stretch", At: at}); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	data, _ := os.ReadFile(out)
+	if string(data) != "stretch|2026-10-16T09:00:00Z|1792141200" {
+		t.Fatalf("unexpected command input: %q", data)
+	}
+	if err := NewCommand(nil).CreateReminder(context.Background(), domain.Reminder{}); err == nil {
+		t.Fatalf("expected missing command error")
+	}
+}
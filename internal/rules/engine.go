@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"unicode/utf8"
+
+	"coldmic/internal/ports"
 )
 
 type compiledRule interface {
@@ -22,8 +26,13 @@ type RuleParser interface {
 
 // Engine applies deterministic substitutions loaded from a rules file.
 type Engine struct {
-	rules     []compiledRule
+	path      string
 	loopLimit int
+
+	mu    sync.RWMutex
+	rules []compiledRule
+	// skipped describes the lines of the rules file that did not parse.
+	skipped []string
 }
 
 // NewEngine loads and compiles rules from a file using built-in parsers.
@@ -40,25 +49,31 @@ func NewEngineWithParsers(path string, loopLimit int, parsers []RuleParser) (*En
 		parsers = defaultRuleParsers()
 	}
 
-	if strings.TrimSpace(path) == "" {
+	path = strings.TrimSpace(path)
+	if path == "" {
 		return &Engine{loopLimit: loopLimit}, nil
 	}
 
 	contents, err := os.ReadFile(path)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			return &Engine{loopLimit: loopLimit}, nil
+			return &Engine{path: path, loopLimit: loopLimit}, nil
 		}
 		return nil, fmt.Errorf("failed to read rules file %q: %w", path, err)
 	}
 
-	rules := parseRules(string(contents), parsers)
+	rules, skipped := parseRules(string(contents), parsers)
+	for i, warning := range skipped {
+		skipped[i] = filepath.Base(path) + " " + warning
+	}
 
-	return &Engine{rules: rules, loopLimit: loopLimit}, nil
+	return &Engine{path: path, rules: rules, skipped: skipped, loopLimit: loopLimit}, nil
 }
 
 // Apply transforms text deterministically.
 func (e *Engine) Apply(text string) (string, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	if len(e.rules) == 0 {
 		return text, nil
 	}
@@ -81,9 +96,109 @@ func (e *Engine) Apply(text string) (string, error) {
 	return result, nil
 }
 
-func parseRules(contents string, parsers []RuleParser) []compiledRule {
+// AddLiteral appends a "from => to" rule to the rules file and applies it
+// from now on. Without a rules file the rule only lasts for this process.
+func (e *Engine) AddLiteral(from string, to string) error {
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	if strings.Contains(from, "\n") || strings.Contains(to, "\n") || strings.Contains(from, "=>") {
+		return errors.New("literal rule cannot span lines or contain \"=>\"")
+	}
+	line := from + " => " + to
+	rule, err := parseLiteralRule(line)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.path != "" {
+		if err := appendRuleLine(e.path, line); err != nil {
+			return err
+		}
+	}
+	e.rules = append(e.rules, rule)
+	return nil
+}
+
+// KnownWords returns the capitalized words produced by literal rules, such
+// as "PR" from "pull request => PR", so casing policies can keep them.
+func (e *Engine) KnownWords() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var words []string
+	for _, rule := range e.rules {
+		literal, ok := rule.(literalRule)
+		if !ok {
+			continue
+		}
+		for _, word := range strings.Fields(literal.replacement) {
+			if strings.ToLower(word) != word {
+				words = append(words, word)
+			}
+		}
+	}
+	return words
+}
+
+// RuleWarnings describes the lines of the rules file skipped because they
+// did not parse.
+func (e *Engine) RuleWarnings() []string {
+	return append([]string(nil), e.skipped...)
+}
+
+// CompileRules compiles lines in rules-file syntax into rules that are not
+// persisted, such as rules for a single session. Unlike a rules file, an
+// invalid or unsupported line is an error rather than skipped.
+func (e *Engine) CompileRules(lines []string) (ports.RulesEngine, error) {
+	compiled := make([]compiledRule, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule, err := parseRule(line, defaultRuleParsers())
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule %q: %w", line, err)
+		}
+		compiled = append(compiled, rule)
+	}
+	return &Engine{loopLimit: e.loopLimit, rules: compiled}, nil
+}
+
+func appendRuleLine(path string, line string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create rules directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open rules file %q: %w", path, err)
+	}
+	defer file.Close()
+
+	// Start on a fresh line when the file does not end with a newline.
+	prefix := ""
+	if info, err := file.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			prefix = "\n"
+		}
+	}
+	if _, err := file.WriteString(prefix + line + "\n"); err != nil {
+		return fmt.Errorf("failed to write rules file %q: %w", path, err)
+	}
+	return nil
+}
+
+// errUnsupportedRule reports a line no parser accepts.
+var errUnsupportedRule = errors.New("unsupported rule format")
+
+// parseRules compiles the rules in contents, skipping the lines that do not
+// parse and describing each skipped line.
+func parseRules(contents string, parsers []RuleParser) ([]compiledRule, []string) {
 	lines := strings.Split(contents, "\n")
 	rules := make([]compiledRule, 0, len(lines))
+	var skipped []string
 
 	for index, raw := range lines {
 		line := strings.TrimSpace(raw)
@@ -91,28 +206,30 @@ func parseRules(contents string, parsers []RuleParser) []compiledRule {
 			continue
 		}
 
-		parsed := false
-		for _, parser := range parsers {
-			if !parser.CanParse(line) {
-				continue
-			}
-			rule, err := parser.Parse(line)
-			if err != nil {
-				log.Printf("warning: skipping invalid rule at line %d: %v", index+1, err)
-				parsed = true
-				break
-			}
+		rule, err := parseRule(line, parsers)
+		switch {
+		case errors.Is(err, errUnsupportedRule):
+			log.Printf("warning: skipping unsupported rule format at line %d", index+1)
+			skipped = append(skipped, fmt.Sprintf("line %d: unsupported rule format", index+1))
+		case err != nil:
+			log.Printf("warning: skipping invalid rule at line %d: %v", index+1, err)
+			skipped = append(skipped, fmt.Sprintf("line %d: %v", index+1, err))
+		default:
 			rules = append(rules, rule)
-			parsed = true
-			break
 		}
+	}
 
-		if !parsed {
-			log.Printf("warning: skipping unsupported rule format at line %d", index+1)
+	return rules, skipped
+}
+
+// parseRule compiles line with the first parser that accepts it.
+func parseRule(line string, parsers []RuleParser) (compiledRule, error) {
+	for _, parser := range parsers {
+		if parser.CanParse(line) {
+			return parser.Parse(line)
 		}
 	}
-
-	return rules
+	return nil, errUnsupportedRule
 }
 
 func defaultRuleParsers() []RuleParser {
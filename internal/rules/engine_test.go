@@ -196,10 +196,13 @@ func TestParseRegexRuleUnsupportedFlag(t *testing.T) {
 func TestParseRulesUnsupportedLine(t *testing.T) {
 	t.Parallel()
 
-	rules := parseRules("not-a-rule", defaultRuleParsers())
+	rules, skipped := parseRules("not-a-rule", defaultRuleParsers())
 	if len(rules) != 0 {
 		t.Fatalf("expected 0 rules, got %d", len(rules))
 	}
+	if len(skipped) != 1 || skipped[0] != "line 1: unsupported rule format" {
+		t.Fatalf("unexpected skipped rules: %q", skipped)
+	}
 }
 
 func TestEngineSkipsInvalidRules(t *testing.T) {
@@ -234,6 +237,10 @@ world => earth
 	if output != "hi earth" {
 		t.Fatalf("unexpected output: %q", output)
 	}
+	warnings := engine.RuleWarnings()
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0], "substitutions.rules line 5: invalid regex") {
+		t.Fatalf("unexpected rule warnings: %q", warnings)
+	}
 }
 
 type prefixRuleParser struct{}
@@ -250,3 +257,118 @@ func (prefixRuleParser) Parse(line string) (compiledRule, error) {
 	}
 	return parseLiteralRule(parts[0] + " => " + parts[1])
 }
+
+func TestEngineAddLiteralPersistsRule(t *testing.T) {
+	t.Parallel()
+
+	rulesPath := filepath.Join(t.TempDir(), "coldmic", "substitutions.rules")
+	engine, err := NewEngine(rulesPath, 30)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := engine.AddLiteral(" cold mick ", "ColdMic"); err != nil {
+		t.Fatalf("add literal failed: %v", err)
+	}
+	if err := engine.AddLiteral("a => b", "c"); err == nil {
+		t.Fatalf("expected rule separator in source to be rejected")
+	}
+
+	output, err := engine.Apply("launch cold mick now")
+	if err != nil || output != "launch ColdMic now" {
+		t.Fatalf("unexpected output: %q %v", output, err)
+	}
+
+	contents, err := os.ReadFile(rulesPath)
+	if err != nil {
+		t.Fatalf("read rules file: %v", err)
+	}
+	if string(contents) != "cold mick => ColdMic\n" {
+		t.Fatalf("unexpected rules file: %q", contents)
+	}
+	reloaded, err := NewEngine(rulesPath, 30)
+	if err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if output, _ := reloaded.Apply("cold mick"); output != "ColdMic" {
+		t.Fatalf("expected persisted rule to apply, got %q", output)
+	}
+}
+
+func TestEngineAddLiteralStartsOnNewLine(t *testing.T) {
+	t.Parallel()
+
+	rulesPath := filepath.Join(t.TempDir(), "substitutions.rules")
+	if err := os.WriteFile(rulesPath, []byte("pull request => PR"), 0o600); err != nil {
+		t.Fatalf("failed to write rules file: %v", err)
+	}
+	engine, err := NewEngine(rulesPath, 30)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if err := engine.AddLiteral("k8s", "Kubernetes"); err != nil {
+		t.Fatalf("add literal failed: %v", err)
+	}
+	contents, _ := os.ReadFile(rulesPath)
+	if string(contents) != "pull request => PR\nk8s => Kubernetes\n" {
+		t.Fatalf("unexpected rules file: %q", contents)
+	}
+}
+
+func TestEngineKnownWordsFromLiteralRules(t *testing.T) {
+	t.Parallel()
+
+	rulesPath := filepath.Join(t.TempDir(), "substitutions.rules")
+	rules := "pull request => PR\nkube => kubernetes\ncold mick => ColdMic app\ns/gh/GitHub/g\n"
+	if err := os.WriteFile(rulesPath, []byte(rules), 0o600); err != nil {
+		t.Fatalf("failed to write rules file: %v", err)
+	}
+	engine, err := NewEngine(rulesPath, 30)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	if got := strings.Join(engine.KnownWords(), ","); got != "PR,ColdMic" {
+		t.Fatalf("unexpected known words: %q", got)
+	}
+}
+
+func TestEngineCompileRulesLeavesFileUntouched(t *testing.T) {
+	t.Parallel()
+
+	rulesPath := filepath.Join(t.TempDir(), "substitutions.rules")
+	if err := os.WriteFile(rulesPath, []byte("pull request => PR\n"), 0o600); err != nil {
+		t.Fatalf("failed to write rules file: %v", err)
+	}
+	engine, err := NewEngine(rulesPath, 30)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+
+	compiled, err := engine.CompileRules([]string{"# proper nouns", "", "sarah kovack => Sarah Kovač", "s/\\bkovac\\b/Kovač/g"})
+	if err != nil {
+		t.Fatalf("compile failed: %v", err)
+	}
+	output, err := compiled.Apply("sarah kovack and kovac opened a pull request")
+	if err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+	if output != "Sarah Kovač and Kovač opened a pull request" {
+		t.Fatalf("unexpected output: %q", output)
+	}
+	if words := strings.Join(compiled.(*Engine).KnownWords(), ","); words != "Sarah,Kovač" {
+		t.Fatalf("unexpected known words: %q", words)
+	}
+
+	if _, err := engine.CompileRules([]string{"sarah kovack"}); err == nil {
+		t.Fatalf("expected unsupported line to fail")
+	}
+	if _, err := engine.CompileRules([]string{"s/x/y/q"}); err == nil {
+		t.Fatalf("expected invalid regex rule to fail")
+	}
+	contents, err := os.ReadFile(rulesPath)
+	if err != nil {
+		t.Fatalf("read rules file: %v", err)
+	}
+	if string(contents) != "pull request => PR\n" {
+		t.Fatalf("rules file changed: %q", contents)
+	}
+}
@@ -0,0 +1,99 @@
+package rules
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"coldmic/internal/ports"
+)
+
+// LanguageEngine adds per-language rules files next to a shared base file:
+// with substitutions.rules as the base, German transcripts also apply
+// substitutions.de.rules. The base engine is embedded, so corrections and
+// callers without a language keep using the shared file.
+type LanguageEngine struct {
+	*Engine
+	parsers []RuleParser
+
+	mu        sync.Mutex
+	languages map[string]ports.RulesEngine
+}
+
+// NewLanguageEngine loads the base rules file; language files are loaded on
+// first use.
+func NewLanguageEngine(path string, loopLimit int) (*LanguageEngine, error) {
+	base, err := NewEngine(path, loopLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &LanguageEngine{Engine: base, parsers: defaultRuleParsers(), languages: map[string]ports.RulesEngine{}}, nil
+}
+
+// ForLanguage returns the rules for language: its own file, tried as the
+// full tag (substitutions.en-us.rules) and then the base language
+// (substitutions.en.rules), applied before the shared base rules. Without a
+// language file, or without a language, it returns the base rules.
+func (l *LanguageEngine) ForLanguage(language string) (ports.RulesEngine, error) {
+	language = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(language), "_", "-"))
+	if language == "" || l.path == "" {
+		return l.Engine, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if engine, ok := l.languages[language]; ok {
+		return engine, nil
+	}
+
+	var engine ports.RulesEngine = l.Engine
+	for _, candidate := range languageCandidates(language) {
+		own, err := NewEngineWithParsers(languagePath(l.path, candidate), l.loopLimit, l.parsers)
+		if err != nil {
+			return nil, err
+		}
+		if len(own.rules) > 0 || len(own.skipped) > 0 {
+			engine = &layeredEngine{language: own, base: l.Engine}
+			break
+		}
+	}
+	l.languages[language] = engine
+	return engine, nil
+}
+
+// languageCandidates lists the tags tried for language, most specific first.
+func languageCandidates(language string) []string {
+	candidates := []string{language}
+	if base, _, ok := strings.Cut(language, "-"); ok && base != "" {
+		candidates = append(candidates, base)
+	}
+	return candidates
+}
+
+// languagePath inserts language before the extension of path.
+func languagePath(path string, language string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + language + ext
+}
+
+// layeredEngine applies language rules before the shared base rules.
+type layeredEngine struct {
+	language *Engine
+	base     *Engine
+}
+
+func (e *layeredEngine) Apply(text string) (string, error) {
+	text, err := e.language.Apply(text)
+	if err != nil {
+		return "", err
+	}
+	return e.base.Apply(text)
+}
+
+func (e *layeredEngine) KnownWords() []string {
+	return append(e.language.KnownWords(), e.base.KnownWords()...)
+}
+
+func (e *layeredEngine) RuleWarnings() []string {
+	return append(e.language.RuleWarnings(), e.base.RuleWarnings()...)
+}
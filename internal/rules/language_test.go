@@ -0,0 +1,96 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"coldmic/internal/ports"
+)
+
+func TestLanguageEngineLayersLanguageRulesOverBase(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	basePath := filepath.Join(tmpDir, "substitutions.rules")
+	files := map[string]string{
+		"substitutions.rules":    "deep gram => Deepgram\n",
+		"substitutions.de.rules": "komma => ,\nPR Anfrage => Pull Request\n",
+		"substitutions.en.rules": "comma => ,\n",
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(contents), 0o600); err != nil {
+			t.Fatalf("failed to write rules file: %v", err)
+		}
+	}
+
+	engine, err := NewLanguageEngine(basePath, 30)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+
+	cases := []struct {
+		language string
+		input    string
+		want     string
+	}{
+		{language: "de", input: "deep gram komma comma", want: "Deepgram , comma"},
+		{language: "en-US", input: "deep gram komma comma", want: "Deepgram komma ,"},
+		{language: "fr", input: "deep gram komma comma", want: "Deepgram komma comma"},
+		{language: "", input: "deep gram komma comma", want: "Deepgram komma comma"},
+	}
+	for _, tc := range cases {
+		rules, err := engine.ForLanguage(tc.language)
+		if err != nil {
+			t.Fatalf("ForLanguage(%q) failed: %v", tc.language, err)
+		}
+		output, err := rules.Apply(tc.input)
+		if err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+		if output != tc.want {
+			t.Fatalf("language %q: expected %q, got %q", tc.language, tc.want, output)
+		}
+	}
+
+	rules, err := engine.ForLanguage("de")
+	if err != nil {
+		t.Fatalf("ForLanguage failed: %v", err)
+	}
+	words := rules.(ports.WordSource).KnownWords()
+	if !slices.Contains(words, "Pull") || !slices.Contains(words, "Deepgram") {
+		t.Fatalf("expected language and base words, got %v", words)
+	}
+}
+
+func TestLanguageEnginePrefersFullLanguageTag(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	basePath := filepath.Join(tmpDir, "substitutions.rules")
+	if err := os.WriteFile(filepath.Join(tmpDir, "substitutions.pt-br.rules"), []byte("ônibus => busão\n"), 0o600); err != nil {
+		t.Fatalf("failed to write rules file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "substitutions.pt.rules"), []byte("ônibus => autocarro\n"), 0o600); err != nil {
+		t.Fatalf("failed to write rules file: %v", err)
+	}
+
+	engine, err := NewLanguageEngine(basePath, 30)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	for language, want := range map[string]string{"pt_BR": "busão", "pt-PT": "autocarro"} {
+		rules, err := engine.ForLanguage(language)
+		if err != nil {
+			t.Fatalf("ForLanguage(%q) failed: %v", language, err)
+		}
+		output, err := rules.Apply("ônibus")
+		if err != nil {
+			t.Fatalf("apply failed: %v", err)
+		}
+		if output != want {
+			t.Fatalf("language %q: expected %q, got %q", language, want, output)
+		}
+	}
+}
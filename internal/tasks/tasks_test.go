@@ -0,0 +1,70 @@
+package tasks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+func TestTodoTxtFileAppendsTaggedTasks(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "todo", "todo.txt")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("(A) existing task"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	todo := NewTodoTxtFile(path)
+	todo.now = func() time.Time { return time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC) }
+	ctx := context.Background()
+	if err := todo.AddTask(ctx, domain.Task{Text: "call  the plumber", Project: "home repairs", Context: "phone"}); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	if err := todo.AddTask(ctx, domain.Task{Text: "buy milk"}); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	want := "(A) existing task\n2026-10-15 call the plumber +home-repairs @phone\n2026-10-15 buy milk\n"
+	if string(data) != want {
+		t.Fatalf("unexpected todo.txt:\n%q", data)
+	}
+}
+
+func TestTaskwarriorRunsTaskAdd(t *testing.T) {
+	t.Parallel()
+
+	out := filepath.Join(t.TempDir(), "args.txt")
+	script := filepath.Join(t.TempDir(), "task")
+	if err := os.WriteFile(script, []byte("#!/usr/bin/env bash\nprintf '%s\\n' \"$@\" > "+out+"\n"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	err := NewTaskwarrior(script).AddTask(context.Background(), domain.Task{Text: "renew due:passport", Project: "admin", Context: "errand"})
+	if err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	data, _ := os.ReadFile(out)
+	want := "rc.confirmation=off\nrc.verbose=nothing\nadd\nproject:admin\n+errand\n--\nrenew due:passport\n"
+	if string(data) != want {
+		t.Fatalf("unexpected task args:\n%s", data)
+	}
+
+	failing := filepath.Join(t.TempDir(), "task-fail")
+	if err := os.WriteFile(failing, []byte("#!/usr/bin/env bash\necho 'no data' 1>&2\nexit 2\n"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewTaskwarrior(failing).AddTask(context.Background(), domain.Task{Text: "x"}); err == nil {
+		t.Fatalf("expected task failure")
+	}
+}
@@ -0,0 +1,43 @@
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"coldmic/internal/domain"
+)
+
+// Taskwarrior adds tasks with `task add`.
+type Taskwarrior struct {
+	command string
+}
+
+func NewTaskwarrior(command string) *Taskwarrior {
+	if command == "" {
+		command = "task"
+	}
+	return &Taskwarrior{command: command}
+}
+
+func (t *Taskwarrior) AddTask(ctx context.Context, task domain.Task) error {
+	args := []string{"rc.confirmation=off", "rc.verbose=nothing", "add"}
+	if task.Project != "" {
+		args = append(args, "project:"+tag(task.Project))
+	}
+	if task.Context != "" {
+		args = append(args, "+"+tag(task.Context))
+	}
+	// "--" stops Taskwarrior from reading dictated words as attributes.
+	args = append(args, "--", strings.Join(strings.Fields(task.Text), " "))
+
+	cmd := exec.CommandContext(ctx, t.command, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s add failed: %w: %s", t.command, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
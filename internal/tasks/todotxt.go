@@ -0,0 +1,69 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+// TodoTxtFile appends tasks to a todo.txt file.
+type TodoTxtFile struct {
+	path string
+	now  func() time.Time
+	mu   sync.Mutex
+}
+
+func NewTodoTxtFile(path string) *TodoTxtFile {
+	return &TodoTxtFile{path: path, now: time.Now}
+}
+
+func (f *TodoTxtFile) AddTask(_ context.Context, task domain.Task) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create todo directory: %w", err)
+	}
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open todo file: %w", err)
+	}
+	defer file.Close()
+
+	// Keep the file line-oriented even if it was saved without a final newline.
+	if info, err := file.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			if _, err := file.WriteString("\n"); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := file.WriteString(todoLine(task, f.now()) + "\n"); err != nil {
+		return fmt.Errorf("failed to append task: %w", err)
+	}
+	return nil
+}
+
+// todoLine formats task as "YYYY-MM-DD text +project @context".
+func todoLine(task domain.Task, created time.Time) string {
+	parts := []string{created.Format("2006-01-02"), strings.Join(strings.Fields(task.Text), " ")}
+	if task.Project != "" {
+		parts = append(parts, "+"+tag(task.Project))
+	}
+	if task.Context != "" {
+		parts = append(parts, "@"+tag(task.Context))
+	}
+	return strings.Join(parts, " ")
+}
+
+// tag makes value usable as a single todo.txt or Taskwarrior tag.
+func tag(value string) string {
+	return strings.Join(strings.Fields(value), "-")
+}
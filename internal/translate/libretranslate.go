@@ -0,0 +1,85 @@
+package translate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Config controls the LibreTranslate-compatible HTTP translator.
+type Config struct {
+	APIBaseURL     string
+	APIKey         string
+	SourceLanguage string
+}
+
+// LibreTranslator implements ports.Translator against a LibreTranslate server.
+type LibreTranslator struct {
+	cfg  Config
+	http *http.Client
+}
+
+func NewLibreTranslator(cfg Config) *LibreTranslator {
+	if cfg.APIBaseURL == "" {
+		cfg.APIBaseURL = "http://127.0.0.1:5000"
+	}
+	if cfg.SourceLanguage == "" {
+		cfg.SourceLanguage = "auto"
+	}
+	return &LibreTranslator{cfg: cfg, http: &http.Client{Timeout: 10 * time.Second}}
+}
+
+type translateRequest struct {
+	Q      string `json:"q"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Format string `json:"format"`
+	APIKey string `json:"api_key,omitempty"`
+}
+
+type translateResponse struct {
+	TranslatedText string `json:"translatedText"`
+	Error          string `json:"error"`
+}
+
+func (t *LibreTranslator) Translate(ctx context.Context, text string, targetLanguage string) (string, error) {
+	payload, err := json.Marshal(translateRequest{
+		Q:      text,
+		Source: t.cfg.SourceLanguage,
+		Target: targetLanguage,
+		Format: "text",
+		APIKey: t.cfg.APIKey,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := strings.TrimRight(t.cfg.APIBaseURL, "/") + "/translate"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("translation request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var decoded translateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return "", fmt.Errorf("invalid translation response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		if decoded.Error == "" {
+			decoded.Error = fmt.Sprintf("status %d", resp.StatusCode)
+		}
+		return "", fmt.Errorf("translation failed: %s", decoded.Error)
+	}
+	return strings.TrimSpace(decoded.TranslatedText), nil
+}
@@ -0,0 +1,51 @@
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLibreTranslatorTranslate(t *testing.T) {
+	t.Parallel()
+
+	var got translateRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/translate" || r.Method != http.MethodPost {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"translatedText":" hello "}`))
+	}))
+	defer server.Close()
+
+	translator := NewLibreTranslator(Config{APIBaseURL: server.URL + "/", APIKey: "k"})
+	text, err := translator.Translate(context.Background(), "hallo", "en")
+	if err != nil {
+		t.Fatalf("translate failed: %v", err)
+	}
+	if text != "hello" {
+		t.Fatalf("unexpected translation: %q", text)
+	}
+	if got.Q != "hallo" || got.Source != "auto" || got.Target != "en" || got.APIKey != "k" {
+		t.Fatalf("unexpected request payload: %+v", got)
+	}
+}
+
+func TestLibreTranslatorError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"unsupported target"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewLibreTranslator(Config{APIBaseURL: server.URL}).Translate(context.Background(), "x", "zz")
+	if err == nil || !strings.Contains(err.Error(), "unsupported target") {
+		t.Fatalf("expected translation error, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"coldmic/internal/domain"
+)
+
+// FileStore keeps monthly usage totals in a private JSON file, rewritten
+// atomically on every session.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+type usageFile struct {
+	// Months holds the per-provider totals by month, such as "2026-10".
+	Months map[string]map[string]domain.ProviderUsage `json:"months"`
+}
+
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Add counts one session towards the provider's total for month.
+func (s *FileStore) Add(_ context.Context, month string, session domain.SessionUsage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.load()
+	if err != nil {
+		return err
+	}
+	providers := file.Months[month]
+	if providers == nil {
+		providers = map[string]domain.ProviderUsage{}
+		file.Months[month] = providers
+	}
+	total := providers[session.Provider]
+	total.Provider = session.Provider
+	total.Sessions++
+	total.AudioSeconds += session.AudioSeconds
+	total.Cost += session.Cost
+	providers[session.Provider] = total
+	return s.save(file)
+}
+
+// Month returns the totals of month, with providers sorted by name.
+func (s *FileStore) Month(_ context.Context, month string) (domain.UsageTotal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.load()
+	if err != nil {
+		return domain.UsageTotal{}, err
+	}
+	total := domain.UsageTotal{Month: month, Providers: []domain.ProviderUsage{}}
+	for _, provider := range file.Months[month] {
+		total.Sessions += provider.Sessions
+		total.AudioSeconds += provider.AudioSeconds
+		total.Cost += provider.Cost
+		total.Providers = append(total.Providers, provider)
+	}
+	sort.Slice(total.Providers, func(i, j int) bool {
+		return total.Providers[i].Provider < total.Providers[j].Provider
+	})
+	return total, nil
+}
+
+func (s *FileStore) load() (usageFile, error) {
+	file := usageFile{Months: map[string]map[string]domain.ProviderUsage{}}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return file, nil
+		}
+		return file, fmt.Errorf("failed to read usage file: %w", err)
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return file, fmt.Errorf("invalid usage file: %w", err)
+	}
+	if file.Months == nil {
+		file.Months = map[string]map[string]domain.ProviderUsage{}
+	}
+	return file, nil
+}
+
+func (s *FileStore) save(file usageFile) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create usage directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write usage file: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,46 @@
+package usage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"coldmic/internal/domain"
+)
+
+func TestFileStoreTotalsByMonthAndProvider(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "usage.json")
+	store := NewFileStore(path)
+	ctx := context.Background()
+	for _, add := range []struct {
+		month string
+		usage domain.SessionUsage
+	}{
+		{"2026-10", domain.SessionUsage{Provider: "deepgram", AudioSeconds: 30, Cost: 0.5}},
+		{"2026-10", domain.SessionUsage{Provider: "openai", AudioSeconds: 60, Cost: 1}},
+		{"2026-10", domain.SessionUsage{Provider: "deepgram", AudioSeconds: 90, Cost: 1.5}},
+		{"2026-09", domain.SessionUsage{Provider: "deepgram", AudioSeconds: 600, Cost: 10}},
+	} {
+		if err := store.Add(ctx, add.month, add.usage); err != nil {
+			t.Fatalf("add failed: %v", err)
+		}
+	}
+
+	total, err := NewFileStore(path).Month(ctx, "2026-10")
+	if err != nil {
+		t.Fatalf("month failed: %v", err)
+	}
+	if total.Sessions != 3 || total.AudioSeconds != 180 || total.Cost != 3 || len(total.Providers) != 2 {
+		t.Fatalf("unexpected month total: %+v", total)
+	}
+	if deepgram := total.Providers[0]; deepgram.Provider != "deepgram" || deepgram.Sessions != 2 || deepgram.AudioSeconds != 120 {
+		t.Fatalf("unexpected deepgram total: %+v", deepgram)
+	}
+
+	empty, err := store.Month(ctx, "2026-11")
+	if err != nil || empty.Sessions != 0 || empty.Providers == nil {
+		t.Fatalf("expected an empty month, got %+v %v", empty, err)
+	}
+}
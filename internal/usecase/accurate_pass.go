@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// SetAccurateProvider runs every recording through provider again on Stop
+// and copies that transcript instead of the live one. The live provider
+// still supplies partials while recording; nil disables the second pass.
+func (c *SessionController) SetAccurateProvider(provider ports.TranscriptionProvider) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.accurate = provider
+}
+
+// accuratePass returns the aggregator holding the transcript to copy: the
+// accurate provider's when it succeeds, otherwise the live one. It also
+// returns the usage of the accurate pass.
+func (c *SessionController) accuratePass(ctx context.Context, active *activeSession) (*transcriptAggregator, []domain.SessionUsage) {
+	c.mu.Lock()
+	provider := c.accurate
+	c.mu.Unlock()
+	if provider == nil || active.buffer == nil {
+		return active.aggregator, nil
+	}
+
+	pcm, ok := active.buffer.Audio()
+	if !ok {
+		debuglog.Printf("session accurate pass skipped: no buffered audio")
+		return active.aggregator, nil
+	}
+	aggregator, usage, err := c.transcribeRetained(ctx, provider, pcm)
+	if err != nil {
+		debuglog.Printf("session accurate pass failed: %v", err)
+		active.log.warn(domain.ErrorCodeTranscription, "accurate transcription failed; the live transcript was used")
+		c.events.SessionError(domain.ErrorCodeTranscription, "accurate transcription failed; using live transcript: "+err.Error())
+		return active.aggregator, usage
+	}
+	return aggregator, usage
+}
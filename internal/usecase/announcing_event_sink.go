@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// AnnounceVerbosity selects what is announced to screen-reader users.
+type AnnounceVerbosity string
+
+const (
+	AnnounceOff AnnounceVerbosity = "off"
+	// AnnounceMinimal announces recording, copy, and error transitions.
+	AnnounceMinimal AnnounceVerbosity = "minimal"
+	// AnnounceFull also reads each final transcript aloud.
+	AnnounceFull AnnounceVerbosity = "full"
+)
+
+// announceBacklog bounds the messages waiting for a slow announcer.
+const announceBacklog = 16
+
+// AnnouncingEventSink forwards events to the wrapped sink and announces
+// session transitions through an Announcer, so coldmic can be used without
+// watching the window. Announcements are spoken in order on a background
+// worker; when the announcer falls behind, new messages are dropped.
+type AnnouncingEventSink struct {
+	ports.EventSink
+	announcer ports.Announcer
+	verbosity AnnounceVerbosity
+	queue     chan announcement
+	start     sync.Once
+}
+
+type announcement struct {
+	message string
+	urgent  bool
+}
+
+func NewAnnouncingEventSink(sink ports.EventSink, announcer ports.Announcer, verbosity AnnounceVerbosity) *AnnouncingEventSink {
+	return &AnnouncingEventSink{
+		EventSink: sink,
+		announcer: announcer,
+		verbosity: verbosity,
+		queue:     make(chan announcement, announceBacklog),
+	}
+}
+
+func (s *AnnouncingEventSink) SessionStateChanged(state domain.SessionState, reason domain.SessionStateReason) {
+	s.EventSink.SessionStateChanged(state, reason)
+	if message := announceReason(reason); message != "" {
+		s.announce(message, state == domain.SessionStateError)
+	}
+}
+
+func (s *AnnouncingEventSink) FinalTranscript(raw string, transformed string, sessionID string, warnings []domain.SessionWarning) {
+	s.EventSink.FinalTranscript(raw, transformed, sessionID, warnings)
+	if s.verbosity == AnnounceFull && transformed != "" {
+		s.announce(transformed, false)
+	}
+}
+
+func (s *AnnouncingEventSink) SessionError(code domain.ErrorCode, detail string) {
+	s.EventSink.SessionError(code, detail)
+	message := "Error: " + string(code)
+	if s.verbosity == AnnounceFull && detail != "" {
+		message += ". " + detail
+	}
+	s.announce(message, true)
+}
+
+func (s *AnnouncingEventSink) announce(message string, urgent bool) {
+	if s.verbosity == AnnounceOff || s.verbosity == "" {
+		return
+	}
+	s.start.Do(func() { go s.run() })
+	select {
+	case s.queue <- announcement{message: message, urgent: urgent}:
+	default:
+		debuglog.Printf("announcement dropped: %q", message)
+	}
+}
+
+func (s *AnnouncingEventSink) run() {
+	for item := range s.queue {
+		if err := s.announcer.Announce(context.Background(), item.message, item.urgent); err != nil {
+			debuglog.Printf("announcement failed: %v", err)
+		}
+	}
+}
+
+// announceReason returns the spoken message for a transition, or "" for
+// transitions not worth interrupting the user for.
+func announceReason(reason domain.SessionStateReason) string {
+	switch reason {
+	case domain.SessionReasonRecordingStarted, domain.SessionReasonMeetingStarted:
+		return "Recording"
+	case domain.SessionReasonRecordingRestarted:
+		return "Recording restarted"
+	case domain.SessionReasonTranscribing:
+		return "Transcribing"
+	case domain.SessionReasonTranscriptCopied:
+		return "Copied"
+	case domain.SessionReasonTranscriptReadyClipboardFailed:
+		return "Transcript ready, but the clipboard write failed"
+	case domain.SessionReasonRecordingDiscarded:
+		return "Recording discarded"
+	case domain.SessionReasonNoTranscript:
+		return "Nothing was transcribed"
+	case domain.SessionReasonTranscriptionFailed:
+		return "Transcription failed"
+	case domain.SessionReasonRulesFailed:
+		return "Rules failed"
+	case domain.SessionReasonProviderSwitched:
+		return "Provider switched"
+	case domain.SessionReasonIdleTimeout:
+		return "Recording discarded, no speech detected"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"coldmic/internal/domain"
+)
+
+type fakeAnnouncer struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (f *fakeAnnouncer) Announce(_ context.Context, message string, urgent bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if urgent {
+		message = "!" + message
+	}
+	f.messages = append(f.messages, message)
+	return nil
+}
+
+func (f *fakeAnnouncer) snapshot() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.messages...)
+}
+
+func announceSession(sink *AnnouncingEventSink) {
+	sink.SessionStateChanged(domain.SessionStateRecording, domain.SessionReasonRecordingStarted)
+	sink.PartialTranscript("hello")
+	sink.SessionStateChanged(domain.SessionStateStopping, domain.SessionReasonTranscribing)
+	sink.FinalTranscript("hello world", "Hello world.", "session-1", nil)
+	sink.SessionError(domain.ErrorCodeClipboard, "no display")
+	sink.SessionStateChanged(domain.SessionStateIdle, domain.SessionReasonTranscriptCopied)
+}
+
+func TestAnnouncingEventSinkVerbosity(t *testing.T) {
+	t.Parallel()
+
+	cases := map[AnnounceVerbosity][]string{
+		AnnounceMinimal: {"Recording", "Transcribing", "!Error: clipboard", "Copied"},
+		AnnounceFull:    {"Recording", "Transcribing", "Hello world.", "!Error: clipboard. no display", "Copied"},
+	}
+	for verbosity, want := range cases {
+		announcer := &fakeAnnouncer{}
+		inner := &fakeEventSink{}
+		announceSession(NewAnnouncingEventSink(inner, announcer, verbosity))
+
+		waitFor(t, func() bool { return len(announcer.snapshot()) == len(want) })
+		if got := announcer.snapshot(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: unexpected announcements %q", verbosity, got)
+		}
+		if len(inner.snapshotStates()) != 3 || len(inner.snapshotErrors()) != 1 {
+			t.Fatalf("%s: expected events to reach the wrapped sink", verbosity)
+		}
+	}
+}
+
+func TestAnnouncingEventSinkOffStaysSilent(t *testing.T) {
+	t.Parallel()
+
+	announcer := &fakeAnnouncer{}
+	announceSession(NewAnnouncingEventSink(&fakeEventSink{}, announcer, AnnounceOff))
+	if got := announcer.snapshot(); len(got) != 0 {
+		t.Fatalf("expected no announcements, got %q", got)
+	}
+}
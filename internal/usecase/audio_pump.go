@@ -16,6 +16,7 @@ func pumpAudioChunks(
 	stream ports.StreamingSession,
 	chunkSize int,
 	events ports.EventSink,
+	log *sessionLog,
 	done chan struct{},
 ) {
 	defer close(done)
@@ -41,13 +42,17 @@ func pumpAudioChunks(
 			}
 			if sendErr := stream.SendAudio(buf[:n]); sendErr != nil {
 				debuglog.Printf("audio pump send error after chunks=%d bytes=%d: %v", chunkCount, totalBytes, sendErr)
+				log.warn(domain.ErrorCodeAudioStream, "audio stopped reaching the provider; later speech is missing")
 				events.SessionError(domain.ErrorCodeAudioStream, fmt.Sprintf("failed to stream audio: %v", sendErr))
 				return
 			}
+			// Only audio the provider accepted is logged and billed.
+			log.chunk(n)
 		}
 		if err != nil {
 			if !errors.Is(err, io.EOF) {
 				debuglog.Printf("audio pump read error after chunks=%d bytes=%d: %v", chunkCount, totalBytes, err)
+				log.warn(domain.ErrorCodeAudioStream, "audio capture failed; later speech is missing")
 				events.SessionError(domain.ErrorCodeAudioStream, fmt.Sprintf("audio capture error: %v", err))
 			}
 			return
@@ -55,16 +60,19 @@ func pumpAudioChunks(
 	}
 }
 
-func waitForStream(session ports.StreamingSession, timeout time.Duration) error {
+// waitForStream waits for session to end, closing it after timeout on clock.
+func waitForStream(session ports.StreamingSession, clock ports.Clock, timeout time.Duration) error {
 	done := make(chan error, 1)
 	go func() {
 		done <- session.Wait()
 	}()
 
+	timer := clock.NewTimer(timeout)
 	select {
 	case err := <-done:
+		timer.Stop()
 		return err
-	case <-time.After(timeout):
+	case <-timer.C():
 		_ = session.Close()
 		return <-done
 	}
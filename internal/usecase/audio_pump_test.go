@@ -15,15 +15,19 @@ func TestPumpAudioChunksReportsSendError(t *testing.T) {
 	audio := &fakeAudioSession{chunks: [][]byte{[]byte("abc")}}
 	stream := &sendErrStream{err: errors.New("send failed")}
 	events := &fakeEventSink{}
+	log := newSessionLog("session-1", newFakeClock())
 	done := make(chan struct{})
 
-	go pumpAudioChunks(audio, stream, 256, events, done)
+	go pumpAudioChunks(audio, stream, 256, events, log, done)
 	<-done
 
 	errs := events.snapshotErrors()
 	if len(errs) == 0 || errs[0].code != domain.ErrorCodeAudioStream {
 		t.Fatalf("expected audio stream error")
 	}
+	if sent := log.sentBytes(); sent != 0 {
+		t.Fatalf("expected rejected audio not to count as sent, got %d bytes", sent)
+	}
 }
 
 func TestPumpAudioChunksReportsReadError(t *testing.T) {
@@ -34,7 +38,7 @@ func TestPumpAudioChunksReportsReadError(t *testing.T) {
 	events := &fakeEventSink{}
 	done := make(chan struct{})
 
-	go pumpAudioChunks(audio, stream, 256, events, done)
+	go pumpAudioChunks(audio, stream, 256, events, nil, done)
 	<-done
 
 	errs := events.snapshotErrors()
@@ -47,7 +51,13 @@ func TestWaitForStreamTimeoutClosesSession(t *testing.T) {
 	t.Parallel()
 
 	stream := &blockingWaitStream{done: make(chan struct{}), waitErr: errors.New("closed")}
-	err := waitForStream(stream, 10*time.Millisecond)
+	clock := newFakeClock()
+	result := make(chan error, 1)
+	go func() { result <- waitForStream(stream, clock, 4*time.Second) }()
+
+	clock.waitForTimers(t, 1)
+	clock.Advance(4 * time.Second)
+	err := <-result
 	if err == nil || err.Error() != "closed" {
 		t.Fatalf("expected closed error, got %v", err)
 	}
@@ -0,0 +1,372 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// BatchConfig controls the batch transcription worker pool.
+type BatchConfig struct {
+	Concurrency int
+}
+
+type fileTranscriber interface {
+	TranscribeFile(ctx context.Context, path string) (domain.FileTranscript, error)
+}
+
+// BatchPool transcribes queued files on up to Concurrency workers. Workers
+// start when jobs are queued and exit once the queue is empty, so an idle
+// pool costs nothing but the Run loop. Every state change is saved to the job
+// store, so jobs still queued or processing when the process exits are
+// picked up again by the next Run.
+type BatchPool struct {
+	transcriber fileTranscriber
+	history     ports.HistoryStore
+	jobs        ports.FileJobSink
+	store       ports.FileJobStore
+	cfg         BatchConfig
+
+	restoreOnce sync.Once
+	wake        chan struct{}
+
+	mu       sync.Mutex
+	queue    []domain.FileJob
+	running  map[string]runningJob
+	workers  int
+	idPrefix string
+	nextID   uint64
+}
+
+type runningJob struct {
+	job    domain.FileJob
+	cancel context.CancelFunc
+}
+
+// NewBatchPool builds a pool. store may be nil, in which case jobs are not
+// resumed across restarts.
+func NewBatchPool(transcriber fileTranscriber, history ports.HistoryStore, jobs ports.FileJobSink, store ports.FileJobStore, cfg BatchConfig) *BatchPool {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	return &BatchPool{
+		transcriber: transcriber,
+		history:     history,
+		jobs:        jobs,
+		store:       store,
+		cfg:         cfg,
+		wake:        make(chan struct{}, 1),
+		running:     make(map[string]runningJob),
+		idPrefix:    strconv.FormatInt(time.Now().UnixNano(), 36),
+	}
+}
+
+// Run starts workers for queued jobs until ctx is cancelled. Jobs
+// interrupted by shutdown are left queued for the next Run.
+func (p *BatchPool) Run(ctx context.Context) error {
+	p.restoreOnce.Do(p.restore)
+	debuglog.Printf("batch pool started concurrency=%d", p.cfg.Concurrency)
+
+	var workers sync.WaitGroup
+	defer workers.Wait()
+	for {
+		p.spawn(ctx, &workers)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-p.wake:
+		}
+	}
+}
+
+// Workers returns the number of running workers; it is 0 while idle.
+func (p *BatchPool) Workers() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.workers
+}
+
+// spawn starts a worker for each queued job, up to Concurrency.
+func (p *BatchPool) spawn(ctx context.Context, workers *sync.WaitGroup) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for p.workers < p.cfg.Concurrency && p.workers < len(p.queue) {
+		p.workers++
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for {
+				job, jobCtx, ok := p.next(ctx)
+				if !ok {
+					return
+				}
+				p.process(ctx, jobCtx, job)
+			}
+		}()
+	}
+}
+
+// Submit queues sourcePath for transcription. The transcript is exported to
+// outputPath when it is non-empty and always appended to history. A source
+// that already has an unfinished job is not queued twice.
+func (p *BatchPool) Submit(sourcePath string, outputPath string) domain.FileJob {
+	p.restoreOnce.Do(p.restore)
+
+	p.mu.Lock()
+	for _, job := range p.queue {
+		if job.SourcePath == sourcePath && job.OutputPath == outputPath {
+			p.mu.Unlock()
+			return job
+		}
+	}
+	for _, running := range p.running {
+		if running.job.SourcePath == sourcePath && running.job.OutputPath == outputPath {
+			p.mu.Unlock()
+			return running.job
+		}
+	}
+	p.nextID++
+	job := domain.FileJob{
+		ID:         fmt.Sprintf("file-%s-%d", p.idPrefix, p.nextID),
+		SourcePath: sourcePath,
+		OutputPath: outputPath,
+		State:      domain.FileJobStateQueued,
+		UpdatedAt:  time.Now().UTC(),
+	}
+	p.queue = append(p.queue, job)
+	p.mu.Unlock()
+
+	p.publish(job)
+	p.signal()
+	return job
+}
+
+// Cancel stops a queued or processing job.
+func (p *BatchPool) Cancel(id string) error {
+	p.mu.Lock()
+	if running, ok := p.running[id]; ok {
+		p.mu.Unlock()
+		running.cancel()
+		return nil
+	}
+	for i, job := range p.queue {
+		if job.ID != id {
+			continue
+		}
+		p.queue = append(p.queue[:i], p.queue[i+1:]...)
+		p.mu.Unlock()
+		p.update(&job, domain.FileJobStateCanceled, nil)
+		return nil
+	}
+	p.mu.Unlock()
+	return domain.ErrFileJobNotFound
+}
+
+// Retry queues a failed or canceled job again under the same ID.
+func (p *BatchPool) Retry(ctx context.Context, id string) (domain.FileJob, error) {
+	p.restoreOnce.Do(p.restore)
+	if p.store == nil {
+		return domain.FileJob{}, domain.ErrFileJobNotFound
+	}
+	saved, err := p.store.List(ctx)
+	if err != nil {
+		return domain.FileJob{}, err
+	}
+
+	for _, job := range saved {
+		if job.ID != id {
+			continue
+		}
+		if job.State != domain.FileJobStateFailed && job.State != domain.FileJobStateCanceled {
+			return domain.FileJob{}, fmt.Errorf("job %s is %s; only failed or canceled jobs can be retried", id, job.State)
+		}
+		job.State = domain.FileJobStateQueued
+		job.Error = ""
+		job.UpdatedAt = time.Now().UTC()
+		p.mu.Lock()
+		if p.tracked(id) {
+			p.mu.Unlock()
+			return domain.FileJob{}, fmt.Errorf("job %s is already queued", id)
+		}
+		p.queue = append(p.queue, job)
+		p.mu.Unlock()
+
+		p.publish(job)
+		p.signal()
+		return job, nil
+	}
+	return domain.FileJob{}, domain.ErrFileJobNotFound
+}
+
+// Jobs lists known jobs, including recently finished ones when a store is
+// configured.
+func (p *BatchPool) Jobs(ctx context.Context) ([]domain.FileJob, error) {
+	p.restoreOnce.Do(p.restore)
+	if p.store != nil {
+		return p.store.List(ctx)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]domain.FileJob, 0, len(p.running)+len(p.queue))
+	for _, running := range p.running {
+		job := running.job
+		job.State = domain.FileJobStateProcessing
+		out = append(out, job)
+	}
+	return append(out, p.queue...), nil
+}
+
+// tracked reports whether id is queued or running. Callers must hold p.mu.
+func (p *BatchPool) tracked(id string) bool {
+	if _, ok := p.running[id]; ok {
+		return true
+	}
+	for _, job := range p.queue {
+		if job.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// restore re-queues jobs that were unfinished when the store was last saved.
+func (p *BatchPool) restore() {
+	if p.store == nil {
+		return
+	}
+	saved, err := p.store.List(context.Background())
+	if err != nil {
+		debuglog.Printf("batch pool restore failed: %v", err)
+		return
+	}
+
+	var resumed []domain.FileJob
+	p.mu.Lock()
+	for _, job := range saved {
+		if job.State.Finished() {
+			continue
+		}
+		job.State = domain.FileJobStateQueued
+		job.Error = ""
+		job.UpdatedAt = time.Now().UTC()
+		p.queue = append(p.queue, job)
+		resumed = append(resumed, job)
+	}
+	p.mu.Unlock()
+
+	for _, job := range resumed {
+		debuglog.Printf("batch pool resumed job id=%s path=%q", job.ID, job.SourcePath)
+		p.publish(job)
+	}
+	if len(resumed) > 0 {
+		p.signal()
+	}
+}
+
+// next takes the first queued job. When the queue is empty or ctx is done
+// the calling worker is retired and next returns false.
+func (p *BatchPool) next(ctx context.Context) (domain.FileJob, context.Context, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if ctx.Err() != nil || len(p.queue) == 0 {
+		p.workers--
+		return domain.FileJob{}, nil, false
+	}
+	job := p.queue[0]
+	p.queue = p.queue[1:]
+	jobCtx, cancel := context.WithCancel(ctx)
+	p.running[job.ID] = runningJob{job: job, cancel: cancel}
+	return job, jobCtx, true
+}
+
+func (p *BatchPool) process(runCtx context.Context, ctx context.Context, job domain.FileJob) {
+	defer func() {
+		p.mu.Lock()
+		if running, ok := p.running[job.ID]; ok {
+			running.cancel()
+			delete(p.running, job.ID)
+		}
+		p.mu.Unlock()
+	}()
+
+	p.update(&job, domain.FileJobStateProcessing, nil)
+
+	transcript, err := p.transcriber.TranscribeFile(ctx, job.SourcePath)
+	if err != nil {
+		switch {
+		case runCtx.Err() != nil:
+			p.update(&job, domain.FileJobStateQueued, nil)
+		case ctx.Err() != nil:
+			p.update(&job, domain.FileJobStateCanceled, nil)
+		default:
+			p.update(&job, domain.FileJobStateFailed, err)
+		}
+		return
+	}
+
+	if job.OutputPath != "" {
+		export := transcript.FinalTranscript
+		if transcript.TimestampedTranscript != "" {
+			export = transcript.TimestampedTranscript
+		}
+		if err := os.MkdirAll(filepath.Dir(job.OutputPath), 0o700); err != nil {
+			p.update(&job, domain.FileJobStateFailed, fmt.Errorf("failed to create export directory: %w", err))
+			return
+		}
+		if err := os.WriteFile(job.OutputPath, []byte(export+"\n"), 0o600); err != nil {
+			p.update(&job, domain.FileJobStateFailed, fmt.Errorf("failed to write export file: %w", err))
+			return
+		}
+	}
+
+	entry := domain.HistoryEntry{
+		ID:              job.ID,
+		Source:          domain.HistorySourceFile,
+		SourcePath:      job.SourcePath,
+		RawTranscript:   transcript.RawTranscript,
+		FinalTranscript: transcript.FinalTranscript,
+		CreatedAt:       time.Now().UTC(),
+	}
+	if err := p.history.Append(context.WithoutCancel(ctx), entry); err != nil {
+		debuglog.Printf("batch pool history append failed path=%q: %v", job.SourcePath, err)
+	}
+
+	p.update(&job, domain.FileJobStateDone, nil)
+}
+
+func (p *BatchPool) update(job *domain.FileJob, state domain.FileJobState, err error) {
+	job.State = state
+	job.UpdatedAt = time.Now().UTC()
+	job.Error = ""
+	if err != nil {
+		job.Error = err.Error()
+	}
+	debuglog.Printf("batch pool job id=%s state=%s path=%q err=%v", job.ID, state, job.SourcePath, err)
+	p.publish(*job)
+}
+
+// publish persists job and forwards it to the job sink.
+func (p *BatchPool) publish(job domain.FileJob) {
+	if p.store != nil {
+		if err := p.store.Save(context.Background(), job); err != nil {
+			debuglog.Printf("batch pool job save failed id=%s: %v", job.ID, err)
+		}
+	}
+	p.jobs.FileJobChanged(job)
+}
+
+func (p *BatchPool) signal() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"coldmic/internal/domain"
+)
+
+func TestBatchPoolCancelsQueuedAndRunningJobs(t *testing.T) {
+	t.Parallel()
+
+	transcriber := newBlockingFileTranscriber()
+	jobs := &fakeFileJobSink{}
+	store := &fakeFileJobStore{}
+	pool := NewBatchPool(transcriber, &fakeHistoryStore{}, jobs, store, BatchConfig{Concurrency: 1})
+
+	first := pool.Submit("/in/a.wav", "")
+	second := pool.Submit("/in/b.wav", "")
+	if again := pool.Submit("/in/a.wav", ""); again.ID != first.ID {
+		t.Fatalf("expected duplicate submit to return the existing job, got %+v", again)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- pool.Run(ctx) }()
+
+	<-transcriber.started
+	if err := pool.Cancel(second.ID); err != nil {
+		t.Fatalf("cancel queued failed: %v", err)
+	}
+	if err := pool.Cancel(first.ID); err != nil {
+		t.Fatalf("cancel running failed: %v", err)
+	}
+	waitFor(t, func() bool { return jobs.count(domain.FileJobStateCanceled) == 2 })
+
+	if err := pool.Cancel(first.ID); !errors.Is(err, domain.ErrFileJobNotFound) {
+		t.Fatalf("expected finished job to be unknown, got %v", err)
+	}
+	if got := store.get(second.ID); got.State != domain.FileJobStateCanceled {
+		t.Fatalf("expected canceled state to be persisted, got %+v", got)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+}
+
+func TestBatchPoolResumesUnfinishedJobs(t *testing.T) {
+	t.Parallel()
+
+	out := filepath.Join(t.TempDir(), "exports", "a.txt")
+	store := &fakeFileJobStore{jobs: []domain.FileJob{
+		{ID: "file-old-1", SourcePath: "/in/a.wav", OutputPath: out, State: domain.FileJobStateProcessing},
+		{ID: "file-old-2", SourcePath: "/in/b.wav", State: domain.FileJobStateDone},
+	}}
+	history := &fakeHistoryStore{}
+	jobs := &fakeFileJobSink{}
+	pool := NewBatchPool(&fakeFileTranscriber{text: "resumed"}, history, jobs, store, BatchConfig{Concurrency: 2})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pool.Run(ctx) }()
+
+	waitFor(t, func() bool { return jobs.count(domain.FileJobStateDone) == 1 })
+	cancel()
+	<-done
+
+	entries := history.snapshot()
+	if len(entries) != 1 || entries[0].ID != "file-old-1" || entries[0].FinalTranscript != "resumed" {
+		t.Fatalf("expected only the unfinished job to be resumed, got %+v", entries)
+	}
+	if got := store.get("file-old-1"); got.State != domain.FileJobStateDone {
+		t.Fatalf("expected resumed job to finish, got %+v", got)
+	}
+}
+
+func TestBatchPoolShutdownLeavesJobsQueued(t *testing.T) {
+	t.Parallel()
+
+	transcriber := newBlockingFileTranscriber()
+	store := &fakeFileJobStore{}
+	pool := NewBatchPool(transcriber, &fakeHistoryStore{}, &fakeFileJobSink{}, store, BatchConfig{Concurrency: 1})
+	job := pool.Submit("/in/a.wav", "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pool.Run(ctx) }()
+
+	<-transcriber.started
+	cancel()
+	<-done
+
+	if got := store.get(job.ID); got.State != domain.FileJobStateQueued {
+		t.Fatalf("expected interrupted job to stay queued, got %+v", got)
+	}
+}
+
+func TestBatchPoolStartsWorkersOnDemand(t *testing.T) {
+	t.Parallel()
+
+	jobs := &fakeFileJobSink{}
+	pool := NewBatchPool(&fakeFileTranscriber{text: "hello"}, &fakeHistoryStore{}, jobs, &fakeFileJobStore{}, BatchConfig{Concurrency: 4})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pool.Run(ctx) }()
+
+	if got := pool.Workers(); got != 0 {
+		t.Fatalf("expected no workers while idle, got %d", got)
+	}
+	pool.Submit("/in/a.wav", "")
+	pool.Submit("/in/b.wav", "")
+	waitFor(t, func() bool { return jobs.count(domain.FileJobStateDone) == 2 })
+	waitFor(t, func() bool { return pool.Workers() == 0 })
+
+	pool.Submit("/in/c.wav", "")
+	waitFor(t, func() bool { return jobs.count(domain.FileJobStateDone) == 3 })
+	cancel()
+	<-done
+}
+
+func TestBatchPoolRetriesFailedJobs(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeFileJobStore{jobs: []domain.FileJob{
+		{ID: "file-old-1", SourcePath: "/in/a.wav", State: domain.FileJobStateFailed, Error: "network down"},
+		{ID: "file-old-2", SourcePath: "/in/b.wav", State: domain.FileJobStateDone},
+	}}
+	history := &fakeHistoryStore{}
+	jobs := &fakeFileJobSink{}
+	pool := NewBatchPool(&fakeFileTranscriber{text: "retried"}, history, jobs, store, BatchConfig{})
+
+	if _, err := pool.Retry(context.Background(), "file-old-2"); err == nil {
+		t.Fatalf("expected finished job retry to be rejected")
+	}
+	if _, err := pool.Retry(context.Background(), "missing"); !errors.Is(err, domain.ErrFileJobNotFound) {
+		t.Fatalf("expected unknown job error, got %v", err)
+	}
+	job, err := pool.Retry(context.Background(), "file-old-1")
+	if err != nil {
+		t.Fatalf("retry failed: %v", err)
+	}
+	if job.State != domain.FileJobStateQueued || job.Error != "" {
+		t.Fatalf("expected retried job to be queued without error, got %+v", job)
+	}
+	if _, err := pool.Retry(context.Background(), "file-old-1"); err == nil {
+		t.Fatalf("expected queued job retry to be rejected")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pool.Run(ctx) }()
+	waitFor(t, func() bool { return jobs.count(domain.FileJobStateDone) == 1 })
+	cancel()
+	<-done
+
+	listed, err := pool.Jobs(context.Background())
+	if err != nil {
+		t.Fatalf("jobs failed: %v", err)
+	}
+	if len(listed) != 2 || listed[0].State != domain.FileJobStateDone {
+		t.Fatalf("expected retried job to finish, got %+v", listed)
+	}
+}
+
+type blockingFileTranscriber struct {
+	started chan struct{}
+}
+
+func newBlockingFileTranscriber() *blockingFileTranscriber {
+	return &blockingFileTranscriber{started: make(chan struct{}, 16)}
+}
+
+func (f *blockingFileTranscriber) TranscribeFile(ctx context.Context, _ string) (domain.FileTranscript, error) {
+	f.started <- struct{}{}
+	<-ctx.Done()
+	return domain.FileTranscript{}, ctx.Err()
+}
+
+type fakeFileJobStore struct {
+	mu   sync.Mutex
+	jobs []domain.FileJob
+}
+
+func (f *fakeFileJobStore) Save(_ context.Context, job domain.FileJob) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := range f.jobs {
+		if f.jobs[i].ID == job.ID {
+			f.jobs[i] = job
+			return nil
+		}
+	}
+	f.jobs = append(f.jobs, job)
+	return nil
+}
+
+func (f *fakeFileJobStore) List(_ context.Context) ([]domain.FileJob, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]domain.FileJob, len(f.jobs))
+	copy(out, f.jobs)
+	return out, nil
+}
+
+func (f *fakeFileJobStore) get(id string) domain.FileJob {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, job := range f.jobs {
+		if job.ID == id {
+			return job
+		}
+	}
+	return domain.FileJob{}
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// buttonRetryDelay is how long ButtonTrigger waits before reopening a
+// device that failed, such as a foot pedal being replugged.
+const buttonRetryDelay = 2 * time.Second
+
+// ButtonSession is the session control a push-to-talk button drives.
+type ButtonSession interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) (domain.StopResult, error)
+	Status() domain.Status
+}
+
+// ButtonMode is how a button drives the session.
+type ButtonMode string
+
+const (
+	// ButtonHold is push-to-talk: pressing starts a session and releasing
+	// stops it.
+	ButtonHold ButtonMode = "hold"
+	// ButtonToggle starts a session on one press and stops it on the next.
+	ButtonToggle ButtonMode = "toggle"
+)
+
+// ButtonTrigger turns a hardware button into a dictation control. Without a
+// source it idles until one is set, and is running only while it reads one.
+type ButtonTrigger struct {
+	session ButtonSession
+	events  ports.EventSink
+	mode    ButtonMode
+	retry   time.Duration
+	runState
+
+	mu      sync.Mutex
+	source  ports.ButtonSource
+	changed chan struct{}
+}
+
+func NewButtonTrigger(session ButtonSession, events ports.EventSink, mode ButtonMode, source ports.ButtonSource) *ButtonTrigger {
+	return &ButtonTrigger{
+		session: session,
+		events:  events,
+		mode:    mode,
+		retry:   buttonRetryDelay,
+		source:  source,
+		changed: make(chan struct{}, 1),
+	}
+}
+
+// SetSource replaces the button source; nil disables the trigger.
+func (t *ButtonTrigger) SetSource(source ports.ButtonSource) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.source = source
+	select {
+	case t.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Armed reports whether the trigger has a source to read.
+func (t *ButtonTrigger) Armed() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.source != nil
+}
+
+// Run reads the current source until ctx is cancelled, restarting it when
+// the source changes or fails.
+func (t *ButtonTrigger) Run(ctx context.Context) error {
+	for ctx.Err() == nil {
+		t.runSource(ctx, t.current())
+	}
+	return nil
+}
+
+// current returns the source to run, consuming any pending change.
+func (t *ButtonTrigger) current() ports.ButtonSource {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	select {
+	case <-t.changed:
+	default:
+	}
+	return t.source
+}
+
+// runSource reads source until ctx ends or the source changes. A failed
+// source is reopened after the retry delay.
+func (t *ButtonTrigger) runSource(ctx context.Context, source ports.ButtonSource) {
+	if source == nil {
+		select {
+		case <-ctx.Done():
+		case <-t.changed:
+		}
+		return
+	}
+	defer t.begin()()
+	sourceCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	failed := make(chan error, 1)
+	go func() {
+		failed <- source.Run(sourceCtx, func(down bool) { t.pressed(ctx, down) })
+	}()
+
+	select {
+	case <-ctx.Done():
+	case <-t.changed:
+	case err := <-failed:
+		if err != nil {
+			debuglog.Printf("button trigger failed: %v", err)
+			t.events.SessionError(domain.ErrorCodeTrigger, err.Error())
+		}
+		select {
+		case <-ctx.Done():
+		case <-t.changed:
+		case <-time.After(t.retry):
+		}
+		return
+	}
+	cancel()
+	<-failed
+}
+
+func (t *ButtonTrigger) pressed(ctx context.Context, down bool) {
+	active := t.session.Status().Active
+	var err error
+	switch {
+	case down && !active:
+		err = t.session.Start(ctx)
+	case down && active && t.mode == ButtonToggle:
+		_, err = t.session.Stop(ctx)
+	case !down && active && t.mode != ButtonToggle:
+		_, err = t.session.Stop(ctx)
+	}
+	if err != nil {
+		t.events.SessionError(domain.ErrorCodeFor(err, domain.ErrorCodeTranscription), err.Error())
+	}
+}
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+func TestButtonTriggerPushToTalk(t *testing.T) {
+	t.Parallel()
+
+	session := &fakeHotkeySession{}
+	events := &fakeEventSink{}
+	trigger := NewButtonTrigger(session, events, ButtonHold, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		_ = trigger.Run(ctx)
+		close(done)
+	}()
+
+	first := newFakeButton()
+	trigger.SetSource(first)
+	first.press(true)
+	if !session.active {
+		t.Fatalf("expected press to start a session")
+	}
+	first.press(true)
+	first.press(false)
+	if session.active {
+		t.Fatalf("expected release to stop the session")
+	}
+
+	second := newFakeButton()
+	trigger.SetSource(second)
+	select {
+	case <-first.stopped:
+	case <-time.After(time.Second):
+		t.Fatalf("expected the old source to stop")
+	}
+	second.press(true)
+	if !session.active {
+		t.Fatalf("expected the new source to start a session")
+	}
+
+	cancel()
+	<-done
+}
+
+func TestButtonTriggerToggle(t *testing.T) {
+	t.Parallel()
+
+	session := &fakeHotkeySession{}
+	button := newFakeButton()
+	trigger := NewButtonTrigger(session, &fakeEventSink{}, ButtonToggle, button)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = trigger.Run(ctx)
+		close(done)
+	}()
+
+	button.press(true)
+	button.press(false)
+	if !session.active {
+		t.Fatalf("expected the session to keep recording after release")
+	}
+	button.press(true)
+	if session.active {
+		t.Fatalf("expected the second press to stop the session")
+	}
+
+	cancel()
+	<-done
+}
+
+func TestButtonTriggerReportsFailedSource(t *testing.T) {
+	t.Parallel()
+
+	events := &fakeEventSink{}
+	trigger := NewButtonTrigger(&fakeHotkeySession{}, events, ButtonHold, failingButton{})
+	trigger.retry = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = trigger.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for len(events.snapshotErrors()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+	errs := events.snapshotErrors()
+	if len(errs) != 1 || errs[0].code != domain.ErrorCodeTrigger {
+		t.Fatalf("expected one trigger error, got %+v", errs)
+	}
+}
+
+// fakeButton delivers presses from the test and waits until each is handled.
+type fakeButton struct {
+	presses chan bool
+	handled chan struct{}
+	stopped chan struct{}
+}
+
+func newFakeButton() *fakeButton {
+	return &fakeButton{presses: make(chan bool), handled: make(chan struct{}), stopped: make(chan struct{})}
+}
+
+func (f *fakeButton) Run(ctx context.Context, pressed func(down bool)) error {
+	defer close(f.stopped)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case down := <-f.presses:
+			pressed(down)
+			f.handled <- struct{}{}
+		}
+	}
+}
+
+func (f *fakeButton) press(down bool) {
+	f.presses <- down
+	<-f.handled
+}
+
+type failingButton struct{}
+
+func (failingButton) Run(context.Context, func(bool)) error {
+	return errors.New("device unplugged")
+}
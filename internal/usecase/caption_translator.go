@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// TranslationConfig enables the live translation caption stream.
+type TranslationConfig struct {
+	TargetLanguage string
+}
+
+// captionTranslator translates transcript events off the hot path. Finals are
+// translated strictly in order; partials are coalesced so only the most recent
+// one is translated when the translator falls behind.
+type captionTranslator struct {
+	translator ports.Translator
+	sink       ports.CaptionSink
+	target     string
+	sessionID  string
+
+	mu      sync.Mutex
+	finals  []domain.TranscriptEvent
+	partial *domain.TranscriptEvent
+	closed  bool
+	wake    chan struct{}
+	done    chan struct{}
+	cancel  context.CancelFunc
+
+	translated []string
+}
+
+func newCaptionTranslator(translator ports.Translator, sink ports.CaptionSink, target string, sessionID string) *captionTranslator {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &captionTranslator{
+		translator: translator,
+		sink:       sink,
+		target:     target,
+		sessionID:  sessionID,
+		wake:       make(chan struct{}, 1),
+		done:       make(chan struct{}),
+		cancel:     cancel,
+	}
+	go c.run(ctx)
+	return c
+}
+
+func (c *captionTranslator) Submit(event domain.TranscriptEvent) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return
+	}
+	if event.Kind == domain.TranscriptKindFinal {
+		c.finals = append(c.finals, event)
+		c.partial = nil
+	} else {
+		c.partial = &event
+	}
+	c.mu.Unlock()
+
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Flush stops accepting events and waits for queued finals to be translated.
+func (c *captionTranslator) Flush(ctx context.Context) {
+	c.mu.Lock()
+	c.closed = true
+	c.partial = nil
+	c.mu.Unlock()
+
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		c.cancel()
+		<-c.done
+	}
+}
+
+// Discard stops translation immediately, dropping queued events.
+func (c *captionTranslator) Discard() {
+	c.mu.Lock()
+	c.closed = true
+	c.finals = nil
+	c.partial = nil
+	c.mu.Unlock()
+	c.cancel()
+
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+	<-c.done
+}
+
+// Translated returns the joined translation of all final segments.
+func (c *captionTranslator) Translated() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return strings.TrimSpace(strings.Join(c.translated, " "))
+}
+
+func (c *captionTranslator) run(ctx context.Context) {
+	defer close(c.done)
+	defer c.cancel()
+
+	for {
+		event, ok, closed := c.next()
+		if !ok {
+			if closed {
+				return
+			}
+			select {
+			case <-c.wake:
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+
+		text, err := c.translator.Translate(ctx, event.Text, c.target)
+		if err != nil {
+			debuglog.Printf("caption translation failed kind=%s: %v", event.Kind, err)
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		if event.Kind == domain.TranscriptKindFinal {
+			c.mu.Lock()
+			c.translated = append(c.translated, text)
+			c.mu.Unlock()
+		}
+		c.sink.Caption(domain.Caption{
+			Kind:       event.Kind,
+			SourceText: event.Text,
+			Text:       text,
+			Language:   c.target,
+			SessionID:  c.sessionID,
+		})
+	}
+}
+
+func (c *captionTranslator) next() (domain.TranscriptEvent, bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.finals) > 0 {
+		event := c.finals[0]
+		c.finals = c.finals[1:]
+		return event, true, false
+	}
+	if c.partial != nil {
+		event := *c.partial
+		c.partial = nil
+		return event, true, false
+	}
+	return domain.TranscriptEvent{}, false, c.closed
+}
+
+// captioningStream tees provider events into a caption translator.
+type captioningStream struct {
+	ports.StreamingSession
+	events chan domain.TranscriptEvent
+}
+
+func newCaptioningStream(inner ports.StreamingSession, captions *captionTranslator) *captioningStream {
+	s := &captioningStream{StreamingSession: inner, events: make(chan domain.TranscriptEvent, 64)}
+	go func() {
+		defer close(s.events)
+		for event := range inner.Events() {
+			if strings.TrimSpace(event.Text) != "" {
+				captions.Submit(event)
+			}
+			s.events <- event
+		}
+	}()
+	return s
+}
+
+func (s *captioningStream) Events() <-chan domain.TranscriptEvent {
+	return s.events
+}
+
+// Finalize flushes the provider stream when it supports finalizing.
+func (s *captioningStream) Finalize(ctx context.Context) error {
+	if finalizer, ok := s.StreamingSession.(ports.StreamFinalizer); ok {
+		return finalizer.Finalize(ctx)
+	}
+	return errors.ErrUnsupported
+}
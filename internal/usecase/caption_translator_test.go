@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestCaptionTranslatorTranslatesFinalsInOrder(t *testing.T) {
+	t.Parallel()
+
+	sink := &fakeCaptionSink{}
+	captions := newCaptionTranslator(&fakeTranslator{}, sink, "en", "session-1")
+	captions.Submit(domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "eins"})
+	captions.Submit(domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "zwei"})
+	captions.Flush(context.Background())
+
+	if got := captions.Translated(); got != "EINS ZWEI" {
+		t.Fatalf("unexpected translated transcript: %q", got)
+	}
+	got := sink.snapshot()
+	if len(got) != 2 || got[0].SourceText != "eins" || got[1].Text != "ZWEI" {
+		t.Fatalf("unexpected captions: %+v", got)
+	}
+	if got[0].Language != "en" || got[0].SessionID != "session-1" {
+		t.Fatalf("expected caption metadata, got %+v", got[0])
+	}
+}
+
+func TestCaptionTranslatorSkipsFailedTranslations(t *testing.T) {
+	t.Parallel()
+
+	sink := &fakeCaptionSink{}
+	captions := newCaptionTranslator(&fakeTranslator{fail: "bad"}, sink, "en", "s")
+	captions.Submit(domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "bad"})
+	captions.Submit(domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "good"})
+	captions.Flush(context.Background())
+
+	if got := captions.Translated(); got != "GOOD" {
+		t.Fatalf("unexpected translated transcript: %q", got)
+	}
+}
+
+func TestCaptionTranslatorDiscardDropsQueue(t *testing.T) {
+	t.Parallel()
+
+	block := make(chan struct{})
+	sink := &fakeCaptionSink{}
+	captions := newCaptionTranslator(&fakeTranslator{block: block}, sink, "en", "s")
+	captions.Submit(domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "one"})
+	captions.Submit(domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "two"})
+	captions.Discard()
+	close(block)
+
+	if len(sink.snapshot()) != 0 {
+		t.Fatalf("expected no captions after discard, got %+v", sink.snapshot())
+	}
+}
+
+func TestSessionControllerEmitsCaptionsAndTranslatedTranscript(t *testing.T) {
+	t.Parallel()
+
+	streamSession := newFakeStreamingSession()
+	streamSession.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindPartial, Text: "hallo"}
+	streamSession.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "hallo welt"}
+	sink := &fakeCaptionSink{}
+	events := &fakeEventSink{}
+
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{}}},
+		&fakeProvider{sessions: []ports.StreamingSession{streamSession}},
+		&fakeRules{},
+		&fakeClipboard{},
+		events,
+		Config{Translation: TranslationConfig{TargetLanguage: "en"}},
+	)
+	controller.SetTranslator(&fakeTranslator{}, sink)
+
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	result, err := controller.Stop(context.Background())
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+
+	if result.FinalTranscript != "hallo welt" {
+		t.Fatalf("expected untranslated clipboard transcript, got %q", result.FinalTranscript)
+	}
+	if result.TranslatedTranscript != "HALLO WELT" {
+		t.Fatalf("unexpected translated transcript: %q", result.TranslatedTranscript)
+	}
+	if len(events.partials) != 1 || events.partials[0] != "hallo" {
+		t.Fatalf("expected partial transcript to still flow, got %+v", events.partials)
+	}
+	finals := 0
+	for _, caption := range sink.snapshot() {
+		if caption.Kind == domain.TranscriptKindFinal {
+			finals++
+		}
+	}
+	if finals != 1 {
+		t.Fatalf("expected one final caption, got %+v", sink.snapshot())
+	}
+}
+
+type fakeTranslator struct {
+	fail  string
+	block chan struct{}
+}
+
+func (f *fakeTranslator) Translate(ctx context.Context, text string, _ string) (string, error) {
+	if f.block != nil {
+		select {
+		case <-f.block:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	if text == f.fail {
+		return "", errors.New("translation failed")
+	}
+	return strings.ToUpper(text), nil
+}
+
+type fakeCaptionSink struct {
+	mu       sync.Mutex
+	captions []domain.Caption
+}
+
+func (f *fakeCaptionSink) Caption(caption domain.Caption) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.captions = append(f.captions, caption)
+}
+
+func (f *fakeCaptionSink) snapshot() []domain.Caption {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]domain.Caption, len(f.captions))
+	copy(out, f.captions)
+	return out
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// CasingPolicy controls the capitalization of final output.
+type CasingPolicy string
+
+const (
+	CasingPreserve CasingPolicy = "preserve"
+	CasingSentence CasingPolicy = "sentence"
+	CasingLower    CasingPolicy = "lower"
+)
+
+// clipboardTargetName names plain clipboard output in per-target settings.
+const clipboardTargetName = "clipboard"
+
+// CasingConfig picks a casing policy per output target. Words keeps known
+// spellings, such as acronyms and names, in any policy.
+type CasingConfig struct {
+	Default CasingPolicy
+	Targets map[string]CasingPolicy
+	Words   []string
+}
+
+func (c CasingConfig) policyFor(target string) CasingPolicy {
+	if policy, ok := c.Targets[target]; ok {
+		return policy
+	}
+	if c.Default == "" {
+		return CasingPreserve
+	}
+	return c.Default
+}
+
+var casingWord = regexp.MustCompile(`[\p{L}\p{N}]+(?:['’][\p{L}]+)*`)
+
+// applyCasing recases text under policy. Sentence case capitalizes the first
+// word of each sentence and keeps the provider's casing of the rest, so
+// names and acronyms survive. Known words keep their spelling; in lower
+// case only all-caps acronyms among them are kept.
+func applyCasing(policy CasingPolicy, text string, known []string) string {
+	if policy != CasingSentence && policy != CasingLower {
+		return text
+	}
+	keep := map[string]string{}
+	for _, word := range known {
+		if policy == CasingLower && !isAcronym(word) {
+			continue
+		}
+		keep[strings.ToLower(word)] = word
+	}
+
+	var b strings.Builder
+	sentenceStart := true
+	last := 0
+	for _, span := range casingWord.FindAllStringIndex(text, -1) {
+		gap := text[last:span[0]]
+		b.WriteString(gap)
+		if strings.ContainsAny(gap, ".!?\n") {
+			sentenceStart = true
+		}
+		word := text[span[0]:span[1]]
+		lower := strings.ToLower(word)
+		switch {
+		case keep[lower] != "":
+			word = keep[lower]
+		case policy == CasingLower:
+			word = lower
+		case sentenceStart:
+			word = capitalize(word)
+		}
+		b.WriteString(word)
+		sentenceStart = false
+		last = span[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// isAcronym reports whether word is at least two characters with letters all
+// upper case, like "API" or "K8S".
+func isAcronym(word string) bool {
+	if utf8.RuneCountInString(word) < 2 {
+		return false
+	}
+	letters := 0
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			if !unicode.IsUpper(r) {
+				return false
+			}
+			letters++
+		}
+	}
+	return letters > 0
+}
+
+func capitalize(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+	return string(unicode.ToUpper(r)) + word[size:]
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+)
+
+func TestApplyCasing(t *testing.T) {
+	t.Parallel()
+
+	known := []string{"API", "Siobhan", "K8S"}
+	cases := []struct {
+		name   string
+		policy CasingPolicy
+		input  string
+		want   string
+	}{
+		{"preserve", CasingPreserve, "Ship The API NOW", "Ship The API NOW"},
+		{"sentence", CasingSentence, "ship the api now. then tell siobhan I'm done!\nok", "Ship the API now. Then tell Siobhan I'm done!\nOk"},
+		{"sentence keeps provider casing", CasingSentence, "deploy to AWS. ask Maria about iOS", "Deploy to AWS. Ask Maria about iOS"},
+		{"sentence keeps known word at start", CasingSentence, "k8s Upgrade", "K8S Upgrade"},
+		{"lower keeps acronyms only", CasingLower, "Ask Siobhan About The API", "ask siobhan about the API"},
+		{"unknown policy", CasingPolicy("title"), "Mixed Case", "Mixed Case"},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := applyCasing(tc.policy, tc.input, known); got != tc.want {
+				t.Fatalf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+type fakeWordRules struct {
+	fakeRules
+	words []string
+}
+
+func (f *fakeWordRules) KnownWords() []string { return f.words }
+
+func TestFinalizerAppliesCasingForTarget(t *testing.T) {
+	t.Parallel()
+
+	clipboard := &fakeClipboard{}
+	finalizer := newTranscriptFinalizer(&fakeWordRules{words: []string{"PR"}}, clipboard, &fakeEventSink{})
+	finalizer.setCasing(CasingConfig{
+		Default: CasingSentence,
+		Targets: map[string]CasingPolicy{"fake": CasingLower},
+		Words:   []string{"GitHub"},
+	})
+
+	result, _, err := finalizer.Finalize(context.Background(), "session-1", "open a pr on github", "", nil)
+	if err != nil {
+		t.Fatalf("finalize failed: %v", err)
+	}
+	if result.FinalTranscript != "Open a PR on GitHub" || clipboard.lastText != result.FinalTranscript {
+		t.Fatalf("unexpected clipboard casing: %q", result.FinalTranscript)
+	}
+
+	finalizer.setTarget(&fakeOutputTarget{})
+	result, _, err = finalizer.Finalize(context.Background(), "session-2", "OPEN a pr ON github", "", nil)
+	if err != nil {
+		t.Fatalf("finalize failed: %v", err)
+	}
+	if result.FinalTranscript != "formatted: open a PR on github" {
+		t.Fatalf("unexpected target casing: %q", result.FinalTranscript)
+	}
+}
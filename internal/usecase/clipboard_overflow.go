@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"coldmic/internal/ports"
+)
+
+// ClipboardOverflowMode selects what is copied in place of an overlong transcript.
+type ClipboardOverflowMode string
+
+const (
+	ClipboardOverflowPath    ClipboardOverflowMode = "path"
+	ClipboardOverflowPreview ClipboardOverflowMode = "preview"
+)
+
+// overflowPreviewChars caps the preview copied ahead of the file path.
+const overflowPreviewChars = 200
+
+// ClipboardOverflowConfig controls overflow-to-file clipboard output.
+type ClipboardOverflowConfig struct {
+	// MaxChars is the longest text copied as is; 0 disables overflow.
+	MaxChars int
+	Mode     ClipboardOverflowMode
+}
+
+// OverflowClipboard saves text longer than MaxChars to a file and copies the
+// file path, optionally after a short preview, so huge pastes cannot freeze
+// the target application.
+type OverflowClipboard struct {
+	clipboard ports.Clipboard
+	files     ports.TextFileStore
+	cfg       ClipboardOverflowConfig
+}
+
+func NewOverflowClipboard(clipboard ports.Clipboard, files ports.TextFileStore, cfg ClipboardOverflowConfig) *OverflowClipboard {
+	return &OverflowClipboard{clipboard: clipboard, files: files, cfg: cfg}
+}
+
+func (o *OverflowClipboard) SetText(ctx context.Context, text string) error {
+	if o.cfg.MaxChars <= 0 || utf8.RuneCountInString(text) <= o.cfg.MaxChars {
+		return o.clipboard.SetText(ctx, text)
+	}
+	path, err := o.files.SaveText(ctx, text)
+	if err != nil {
+		return err
+	}
+	if o.cfg.Mode == ClipboardOverflowPath {
+		return o.clipboard.SetText(ctx, path)
+	}
+	return o.clipboard.SetText(ctx, overflowPreview(text, min(overflowPreviewChars, o.cfg.MaxChars))+"\n[full transcript: "+path+"]")
+}
+
+// overflowPreview cuts text to at most limit runes, preferring a word boundary.
+func overflowPreview(text string, limit int) string {
+	runes := []rune(strings.TrimSpace(text))
+	if len(runes) <= limit {
+		return string(runes)
+	}
+	preview := string(runes[:limit])
+	if cut := strings.LastIndexAny(preview, " \n\t"); cut > len(preview)/2 {
+		preview = preview[:cut]
+	}
+	return strings.TrimSpace(preview) + "…"
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type fakeTextFileStore struct {
+	saved []string
+	err   error
+}
+
+func (f *fakeTextFileStore) SaveText(_ context.Context, text string) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	f.saved = append(f.saved, text)
+	return "/tmp/coldmic-transcript-1.txt", nil
+}
+
+func TestOverflowClipboardCopiesShortTextUnchanged(t *testing.T) {
+	t.Parallel()
+
+	clipboard := &recordingClipboard{}
+	files := &fakeTextFileStore{}
+	overflow := NewOverflowClipboard(clipboard, files, ClipboardOverflowConfig{MaxChars: 5, Mode: ClipboardOverflowPath})
+
+	if err := overflow.SetText(context.Background(), "héllo"); err != nil {
+		t.Fatalf("set text failed: %v", err)
+	}
+	if len(clipboard.texts) != 1 || clipboard.texts[0] != "héllo" || len(files.saved) != 0 {
+		t.Fatalf("expected text within the limit to be copied, got %q %q", clipboard.texts, files.saved)
+	}
+}
+
+func TestOverflowClipboardCopiesPathOrPreview(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("word ", 60)
+	clipboard := &recordingClipboard{}
+	files := &fakeTextFileStore{}
+	for _, mode := range []ClipboardOverflowMode{ClipboardOverflowPath, ClipboardOverflowPreview} {
+		overflow := NewOverflowClipboard(clipboard, files, ClipboardOverflowConfig{MaxChars: 100, Mode: mode})
+		if err := overflow.SetText(context.Background(), long); err != nil {
+			t.Fatalf("set text failed: %v", err)
+		}
+	}
+
+	if len(files.saved) != 2 || files.saved[0] != long {
+		t.Fatalf("expected full text to be saved, got %d files", len(files.saved))
+	}
+	if clipboard.texts[0] != "/tmp/coldmic-transcript-1.txt" {
+		t.Fatalf("unexpected path copy: %q", clipboard.texts[0])
+	}
+	want := strings.TrimSpace(strings.Repeat("word ", 20)) + "…\n[full transcript: /tmp/coldmic-transcript-1.txt]"
+	if clipboard.texts[1] != want {
+		t.Fatalf("unexpected preview copy: %q", clipboard.texts[1])
+	}
+}
+
+func TestOverflowClipboardReportsSaveFailure(t *testing.T) {
+	t.Parallel()
+
+	clipboard := &recordingClipboard{}
+	overflow := NewOverflowClipboard(clipboard, &fakeTextFileStore{err: errors.New("disk full")}, ClipboardOverflowConfig{MaxChars: 1})
+	if err := overflow.SetText(context.Background(), "too long"); err == nil {
+		t.Fatalf("expected save failure")
+	}
+	if len(clipboard.texts) != 0 {
+		t.Fatalf("expected nothing copied on failure, got %q", clipboard.texts)
+	}
+}
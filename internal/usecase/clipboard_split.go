@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"coldmic/internal/ports"
+)
+
+// ClipboardSplitMode selects how a final transcript is broken into clipboard entries.
+type ClipboardSplitMode string
+
+const (
+	ClipboardSplitOff        ClipboardSplitMode = "off"
+	ClipboardSplitSentences  ClipboardSplitMode = "sentences"
+	ClipboardSplitParagraphs ClipboardSplitMode = "paragraphs"
+)
+
+// ClipboardSplitConfig controls split clipboard output.
+type ClipboardSplitConfig struct {
+	Mode ClipboardSplitMode
+	// Delay separates consecutive clipboard writes so clipboard managers
+	// record each piece as its own entry.
+	Delay time.Duration
+	// Clock times Delay; nil uses the wall clock.
+	Clock ports.Clock
+}
+
+// SplittingClipboard writes a transcript as one clipboard entry per sentence
+// or paragraph. Pieces are written last to first, so the first piece ends up
+// on the clipboard and on top of the clipboard manager history.
+//
+// When a separate pieces sink is given (e.g. `cliphist store`), the pieces go
+// there and the clipboard itself still receives the whole transcript.
+type SplittingClipboard struct {
+	clipboard ports.Clipboard
+	pieces    ports.Clipboard
+	cfg       ClipboardSplitConfig
+}
+
+// NewSplittingClipboard wraps clipboard. pieces may be nil to write pieces to
+// clipboard directly.
+func NewSplittingClipboard(clipboard ports.Clipboard, pieces ports.Clipboard, cfg ClipboardSplitConfig) *SplittingClipboard {
+	if cfg.Delay < 0 {
+		cfg.Delay = 0
+	}
+	return &SplittingClipboard{clipboard: clipboard, pieces: pieces, cfg: cfg}
+}
+
+func (s *SplittingClipboard) SetText(ctx context.Context, text string) error {
+	parts := splitTranscript(text, s.cfg.Mode)
+	if len(parts) <= 1 {
+		return s.clipboard.SetText(ctx, text)
+	}
+
+	sink := s.clipboard
+	if s.pieces != nil {
+		sink = s.pieces
+	}
+	if err := writePieces(ctx, sink, parts, clockOrSystem(s.cfg.Clock), s.cfg.Delay); err != nil {
+		return err
+	}
+	if s.pieces != nil {
+		return s.clipboard.SetText(ctx, text)
+	}
+	return nil
+}
+
+// writePieces writes parts last to first, delay apart, so the first part ends
+// up on the clipboard and on top of clipboard manager history.
+func writePieces(ctx context.Context, sink ports.Clipboard, parts []string, clock ports.Clock, delay time.Duration) error {
+	for i := len(parts) - 1; i >= 0; i-- {
+		if err := sink.SetText(ctx, parts[i]); err != nil {
+			return fmt.Errorf("failed to write clipboard piece %d of %d: %w", i+1, len(parts), err)
+		}
+		if i > 0 && delay > 0 {
+			if err := sleepContext(ctx, clock, delay); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+var (
+	paragraphBreak = regexp.MustCompile(`\n\s*\n`)
+	// sentenceEnd matches terminal punctuation followed by whitespace and the
+	// start of a new sentence, so "e.g. this" and "3.5" are not split.
+	sentenceEnd = regexp.MustCompile(`[.!?…]["')\]]*\s+["'(\[]?[\p{Lu}\p{N}]`)
+)
+
+// splitTranscript breaks text into trimmed, non-empty pieces.
+func splitTranscript(text string, mode ClipboardSplitMode) []string {
+	var parts []string
+	switch mode {
+	case ClipboardSplitParagraphs:
+		blocks := paragraphBreak.Split(text, -1)
+		if len(blocks) == 1 {
+			// Single-spaced output such as meeting dialogue: one line per paragraph.
+			blocks = strings.Split(text, "\n")
+		}
+		parts = blocks
+	case ClipboardSplitSentences:
+		for _, line := range strings.Split(text, "\n") {
+			parts = append(parts, splitSentences(line)...)
+		}
+	default:
+		return []string{text}
+	}
+
+	out := parts[:0]
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func splitSentences(line string) []string {
+	var sentences []string
+	start := 0
+	for _, loc := range sentenceEnd.FindAllStringIndex(line, -1) {
+		// Cut after the whitespace run; the final matched rune opens the next sentence.
+		end := loc[1]
+		for end > loc[0] && !isSpace(line[end-1]) {
+			end--
+		}
+		sentences = append(sentences, line[start:end])
+		start = end
+	}
+	return append(sentences, line[start:])
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r'
+}
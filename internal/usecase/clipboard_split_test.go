@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestSplitTranscriptSentences(t *testing.T) {
+	t.Parallel()
+
+	got := splitTranscript("Send it to Ana. She owns v2.5, e.g. the API! Done?\nMe: next line.", ClipboardSplitSentences)
+	want := []string{"Send it to Ana.", "She owns v2.5, e.g. the API!", "Done?", "Me: next line."}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected sentences:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestSplitTranscriptParagraphs(t *testing.T) {
+	t.Parallel()
+
+	got := splitTranscript("First part. Still first.\n\n  Second part.\n", ClipboardSplitParagraphs)
+	want := []string{"First part. Still first.", "Second part."}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected paragraphs: %q", got)
+	}
+
+	dialogue := splitTranscript("Me: hi\nThem: hello", ClipboardSplitParagraphs)
+	if !reflect.DeepEqual(dialogue, []string{"Me: hi", "Them: hello"}) {
+		t.Fatalf("expected single-spaced lines as paragraphs, got %q", dialogue)
+	}
+}
+
+func TestSplittingClipboardWritesPiecesLastToFirst(t *testing.T) {
+	t.Parallel()
+
+	clipboard := &recordingClipboard{}
+	split := NewSplittingClipboard(clipboard, nil, ClipboardSplitConfig{Mode: ClipboardSplitSentences})
+	if err := split.SetText(context.Background(), "One. Two. Three."); err != nil {
+		t.Fatalf("set text failed: %v", err)
+	}
+	if want := []string{"Three.", "Two.", "One."}; !reflect.DeepEqual(clipboard.texts, want) {
+		t.Fatalf("unexpected clipboard writes: %q", clipboard.texts)
+	}
+}
+
+func TestSplittingClipboardUsesPiecesSink(t *testing.T) {
+	t.Parallel()
+
+	clipboard := &recordingClipboard{}
+	pieces := &recordingClipboard{}
+	split := NewSplittingClipboard(clipboard, pieces, ClipboardSplitConfig{Mode: ClipboardSplitSentences})
+	if err := split.SetText(context.Background(), "One. Two."); err != nil {
+		t.Fatalf("set text failed: %v", err)
+	}
+	if !reflect.DeepEqual(pieces.texts, []string{"Two.", "One."}) {
+		t.Fatalf("unexpected pieces: %q", pieces.texts)
+	}
+	if !reflect.DeepEqual(clipboard.texts, []string{"One. Two."}) {
+		t.Fatalf("expected whole transcript on the clipboard, got %q", clipboard.texts)
+	}
+
+	// A single sentence is copied as-is.
+	if err := split.SetText(context.Background(), "Only one."); err != nil {
+		t.Fatalf("set text failed: %v", err)
+	}
+	if len(pieces.texts) != 2 || clipboard.texts[1] != "Only one." {
+		t.Fatalf("expected single sentence to bypass splitting")
+	}
+}
+
+type recordingClipboard struct {
+	mu    sync.Mutex
+	texts []string
+	// failures is the number of writes to fail before recording any.
+	failures int
+}
+
+func (r *recordingClipboard) SetText(_ context.Context, text string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failures > 0 {
+		r.failures--
+		return errors.New("clipboard unavailable")
+	}
+	r.texts = append(r.texts, text)
+	return nil
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"coldmic/internal/ports"
+)
+
+// systemClock is the wall clock, used unless a config sets another.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) NewTimer(d time.Duration) ports.Timer {
+	return systemTimer{timer: time.NewTimer(d)}
+}
+
+type systemTimer struct {
+	timer *time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time { return t.timer.C }
+
+func (t systemTimer) Stop() bool { return t.timer.Stop() }
+
+// clockOrSystem returns clock, or the wall clock when it is nil.
+func clockOrSystem(clock ports.Clock) ports.Clock {
+	if clock == nil {
+		return systemClock{}
+	}
+	return clock
+}
+
+// sleepContext waits d on clock, returning early with ctx's error once ctx
+// is done.
+func sleepContext(ctx context.Context, clock ports.Clock, d time.Duration) error {
+	timer := clock.NewTimer(d)
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		timer.Stop()
+		return ctx.Err()
+	}
+}
+
+// withClockTimeout is context.WithTimeout on clock. The context reports
+// context.DeadlineExceeded as its cause once the timeout passes.
+func withClockTimeout(ctx context.Context, clock ports.Clock, d time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	timer := clock.NewTimer(d)
+	go func() {
+		select {
+		case <-timer.C():
+			cancel(context.DeadlineExceeded)
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}()
+	return ctx, func() { cancel(context.Canceled) }
+}
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestSessionControllerWaitsStreamingGraceOnClock(t *testing.T) {
+	t.Parallel()
+
+	stream := newFakeStreamingSession()
+	stream.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "tail"}
+	clock := newFakeClock()
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{}}},
+		&fakeProvider{sessions: []ports.StreamingSession{stream}},
+		&fakeRules{},
+		&fakeClipboard{},
+		&fakeEventSink{},
+		Config{StreamingGrace: time.Hour, Clock: clock},
+	)
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+
+	stopped := make(chan error, 1)
+	go func() {
+		_, err := controller.Stop(context.Background())
+		stopped <- err
+	}()
+	clock.waitForTimers(t, 1)
+	select {
+	case err := <-stopped:
+		t.Fatalf("expected stop to wait out the grace period, got %v", err)
+	default:
+	}
+	clock.Advance(time.Hour)
+	if err := <-stopped; err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+}
+
+func TestWithClockTimeoutExpiresOnClock(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	ctx, cancel := withClockTimeout(context.Background(), clock, finalizeTimeout)
+	defer cancel()
+
+	clock.waitForTimers(t, 1)
+	clock.Advance(finalizeTimeout - time.Millisecond)
+	if ctx.Err() != nil {
+		t.Fatalf("expected the context to live until the timeout")
+	}
+	clock.Advance(time.Millisecond)
+	<-ctx.Done()
+	if !errors.Is(context.Cause(ctx), context.DeadlineExceeded) {
+		t.Fatalf("expected deadline cause, got %v", context.Cause(ctx))
+	}
+}
+
+// fakeClock is a virtual clock whose timers fire only when Advance moves
+// time past them.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) ports.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	timer := &fakeTimer{clock: c, when: c.now.Add(d), c: make(chan time.Time, 1)}
+	c.timers = append(c.timers, timer)
+	return timer
+}
+
+// Advance moves the clock forward by d and fires the timers that are due.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, timer := range c.timers {
+		if timer.when.After(c.now) {
+			pending = append(pending, timer)
+			continue
+		}
+		timer.c <- c.now
+	}
+	c.timers = pending
+}
+
+// waitForTimers blocks until n timers are pending, so a test advances the
+// clock only once the code under test is waiting on it.
+func (c *fakeClock) waitForTimers(t *testing.T, n int) {
+	t.Helper()
+	waitFor(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return len(c.timers) >= n
+	})
+}
+
+type fakeTimer struct {
+	clock *fakeClock
+	when  time.Time
+	c     chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.c }
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	for i, timer := range t.clock.timers {
+		if timer == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// defaultRetryMaxAudio bounds the audio kept in memory for a retry; longer
+// sessions are not retried.
+const defaultRetryMaxAudio = 10 * time.Minute
+
+// RetryConfig controls low-confidence retries of dictation sessions.
+type RetryConfig struct {
+	// MinConfidence is the average provider confidence below which a retry is
+	// offered; 0 disables retries.
+	MinConfidence float64
+	MaxAudio      time.Duration
+}
+
+// retainedAudio is the captured audio of the last low-confidence session.
+type retainedAudio struct {
+	sessionID string
+	pcm       []byte
+	// temporary are the session's temporary rules, reapplied on retry.
+	temporary ports.RulesEngine
+}
+
+// bufferingAudioSession keeps a copy of everything read from the capture, up
+// to limit bytes, so the session can be transcribed again.
+type bufferingAudioSession struct {
+	ports.AudioSession
+	limit int
+
+	mu       sync.Mutex
+	buf      bytes.Buffer
+	overflow bool
+}
+
+func newBufferingAudioSession(session ports.AudioSession, limit int) *bufferingAudioSession {
+	return &bufferingAudioSession{AudioSession: session, limit: limit}
+}
+
+func (b *bufferingAudioSession) Read(p []byte) (int, error) {
+	n, err := b.AudioSession.Read(p)
+	if n > 0 {
+		b.mu.Lock()
+		if b.buf.Len()+n > b.limit {
+			b.overflow = true
+			b.buf.Reset()
+		} else if !b.overflow {
+			b.buf.Write(p[:n])
+		}
+		b.mu.Unlock()
+	}
+	return n, err
+}
+
+// Audio returns the buffered audio, or false if the session outgrew the limit.
+func (b *bufferingAudioSession) Audio() ([]byte, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.overflow || b.buf.Len() == 0 {
+		return nil, false
+	}
+	return bytes.Clone(b.buf.Bytes()), true
+}
+
+// SetRetry offers a retry with provider whenever a session's average
+// confidence falls below cfg.MinConfidence. sink receives the offer; a nil
+// provider retries with the current one.
+func (c *SessionController) SetRetry(provider ports.TranscriptionProvider, sink ports.ConfidenceSink, cfg RetryConfig) {
+	if cfg.MaxAudio <= 0 {
+		cfg.MaxAudio = defaultRetryMaxAudio
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.retry = cfg
+	c.retryProvider = provider
+	c.confidence = sink
+}
+
+// audioBufferLimit returns the capture bytes to keep for a retry or an
+// accurate pass, or 0 when neither is enabled.
+func (c *SessionController) audioBufferLimit() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.retry.MinConfidence <= 0 && c.accurate == nil {
+		return 0
+	}
+	maxAudio := c.retry.MaxAudio
+	if maxAudio <= 0 {
+		maxAudio = defaultRetryMaxAudio
+	}
+	sampleRate := c.cfg.Audio.SampleRate
+	if sampleRate <= 0 {
+		sampleRate = 16000
+	}
+	bytesPerSecond := sampleRate * max(c.cfg.Audio.Channels, 1) * 2
+	return int(maxAudio/time.Second) * bytesPerSecond
+}
+
+// checkConfidence records the confidence of the transcript in aggregator on
+// result and, when it is below the threshold, keeps the audio and offers a
+// retry.
+func (c *SessionController) checkConfidence(active *activeSession, aggregator *transcriptAggregator, result *domain.StopResult) {
+	confidence, ok := aggregator.Confidence()
+	if !ok {
+		return
+	}
+	result.Confidence = confidence
+
+	c.mu.Lock()
+	threshold, sink := c.retry.MinConfidence, c.confidence
+	c.mu.Unlock()
+	if active.buffer == nil || threshold <= 0 || confidence >= threshold {
+		return
+	}
+	pcm, ok := active.buffer.Audio()
+	if !ok {
+		debuglog.Printf("low confidence session %s not retryable: audio exceeded the retry buffer", active.id)
+		return
+	}
+
+	c.mu.Lock()
+	c.lastAudio = &retainedAudio{sessionID: active.id, pcm: pcm, temporary: active.temporary.engine}
+	c.mu.Unlock()
+	result.RetryAvailable = true
+	if sink != nil {
+		sink.LowConfidence(domain.LowConfidence{SessionID: active.id, Confidence: confidence, Threshold: threshold})
+	}
+}
+
+// RetryLastSession transcribes the audio of the last low-confidence session
+// again with the retry provider and copies the new transcript.
+func (c *SessionController) RetryLastSession(ctx context.Context) (domain.StopResult, error) {
+	c.mu.Lock()
+	if len(c.sessions) > 0 {
+		c.mu.Unlock()
+		return domain.StopResult{}, domain.ErrSessionInProgress
+	}
+	audio := c.lastAudio
+	provider := c.retryProvider
+	if provider == nil {
+		provider = c.provider
+	}
+	c.mu.Unlock()
+	if audio == nil {
+		return domain.StopResult{}, domain.ErrNoRetryAudio
+	}
+
+	c.events.SessionStateChanged(domain.SessionStateStopping, domain.SessionReasonTranscribing)
+	aggregator, usage, err := c.transcribeRetained(ctx, provider, audio.pcm)
+	if err != nil {
+		c.events.SessionError(domain.ErrorCodeFor(err, domain.ErrorCodeTranscription), err.Error())
+		c.events.SessionStateChanged(domain.SessionStateError, domain.SessionReasonTranscriptionFailed)
+		return domain.StopResult{}, err
+	}
+
+	result, reason, err := c.finalizer.Finalize(ctx, audio.sessionID, aggregator.Raw(), transcriptLanguage(aggregator.Language(), c.cfg.Language), audio.temporary)
+	if err != nil {
+		c.events.SessionStateChanged(domain.SessionStateError, reason)
+		return domain.StopResult{}, err
+	}
+	result.Confidence, _ = aggregator.Confidence()
+	result.Language = aggregator.Language()
+	result.Usage = usage
+
+	c.mu.Lock()
+	if c.lastAudio == audio {
+		c.lastAudio = nil
+	}
+	c.mu.Unlock()
+	c.events.FinalTranscript(result.RawTranscript, result.FinalTranscript, result.SessionID, result.Warnings)
+	c.reportSpans(aggregator, &result)
+	c.events.SessionStateChanged(domain.SessionStateIdle, reason)
+	return result, nil
+}
+
+// transcribeRetained streams captured PCM through provider and returns the
+// aggregated transcript, which is never empty on success, and the usage of
+// the audio sent, which is billed even when transcription fails.
+func (c *SessionController) transcribeRetained(ctx context.Context, provider ports.TranscriptionProvider, pcm []byte) (*transcriptAggregator, []domain.SessionUsage, error) {
+	stream, err := provider.StartStreaming(ctx, c.cfg.Streaming)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer stream.Close()
+
+	errs := &errorCollector{}
+	aggregator := newChannelAggregator(c.cfg.Channels)
+	eventsDone := make(chan struct{})
+	audioDone := make(chan struct{})
+	go consumeTranscriptionEvents(stream, aggregator, errs, nil, eventsDone)
+	go pumpAudioChunks(decodedAudio{bytes.NewReader(pcm)}, stream, c.cfg.ChunkSize, errs, nil, audioDone)
+
+	<-audioDone
+	_ = stream.CloseSend()
+	streamErr := waitForStream(stream, clockOrSystem(c.cfg.Clock), 30*time.Second)
+	<-eventsDone
+	usage := c.recordUsage(ctx, provider, int64(len(pcm)))
+
+	if err := errs.Err(); err != nil {
+		return nil, usage, err
+	}
+	if aggregator.Raw() == "" {
+		if streamErr != nil {
+			return nil, usage, streamErr
+		}
+		return nil, usage, errors.New("no transcript captured")
+	}
+	return aggregator, usage, nil
+}
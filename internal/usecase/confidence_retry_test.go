@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+type fakeConfidenceSink struct {
+	offers []domain.LowConfidence
+}
+
+func (f *fakeConfidenceSink) LowConfidence(event domain.LowConfidence) {
+	f.offers = append(f.offers, event)
+}
+
+func TestSessionControllerRetriesLowConfidenceSession(t *testing.T) {
+	t.Parallel()
+
+	first := newFakeStreamingSession()
+	first.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "wreck a nice beach", Confidence: 0.4}
+	retried := newFakeStreamingSession()
+	retried.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "recognize speech", Confidence: 0.95}
+
+	clipboard := &recordingClipboard{}
+	sink := &fakeConfidenceSink{}
+	retryProvider := &fakeProvider{sessions: []ports.StreamingSession{retried}}
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{chunks: [][]byte{[]byte("pcm-1"), []byte("pcm-2")}}}},
+		&fakeProvider{sessions: []ports.StreamingSession{first}},
+		&fakeRules{},
+		clipboard,
+		&fakeEventSink{},
+		Config{ChunkSize: 512},
+	)
+	controller.SetRetry(retryProvider, sink, RetryConfig{MinConfidence: 0.8})
+
+	if _, err := controller.RetryLastSession(context.Background()); !errors.Is(err, domain.ErrNoRetryAudio) {
+		t.Fatalf("expected no retry audio, got %v", err)
+	}
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	result, err := controller.Stop(context.Background())
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if !result.RetryAvailable || result.Confidence != 0.4 {
+		t.Fatalf("expected retry offer, got %+v", result)
+	}
+	if len(sink.offers) != 1 || sink.offers[0].SessionID != result.SessionID || sink.offers[0].Threshold != 0.8 {
+		t.Fatalf("unexpected offers: %+v", sink.offers)
+	}
+
+	retry, err := controller.RetryLastSession(context.Background())
+	if err != nil {
+		t.Fatalf("retry failed: %v", err)
+	}
+	if retry.RawTranscript != "recognize speech" || retry.SessionID != result.SessionID || retry.Confidence != 0.95 {
+		t.Fatalf("unexpected retry result: %+v", retry)
+	}
+	if retryProvider.calls != 1 {
+		t.Fatalf("expected retry provider to be used once, got %d", retryProvider.calls)
+	}
+	if len(clipboard.texts) != 2 || clipboard.texts[1] != "recognize speech" {
+		t.Fatalf("expected retried transcript on the clipboard, got %q", clipboard.texts)
+	}
+	if _, err := controller.RetryLastSession(context.Background()); !errors.Is(err, domain.ErrNoRetryAudio) {
+		t.Fatalf("expected audio to be dropped after retry, got %v", err)
+	}
+}
+
+func TestSessionControllerSkipsRetryAboveThreshold(t *testing.T) {
+	t.Parallel()
+
+	stream := newFakeStreamingSession()
+	stream.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "clear speech", Confidence: 0.9}
+	sink := &fakeConfidenceSink{}
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{chunks: [][]byte{[]byte("pcm")}}}},
+		&fakeProvider{sessions: []ports.StreamingSession{stream}},
+		&fakeRules{},
+		&fakeClipboard{},
+		&fakeEventSink{},
+		Config{ChunkSize: 512},
+	)
+	controller.SetRetry(nil, sink, RetryConfig{MinConfidence: 0.8})
+
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	result, err := controller.Stop(context.Background())
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if result.RetryAvailable || len(sink.offers) != 0 {
+		t.Fatalf("expected no retry offer, got %+v %+v", result, sink.offers)
+	}
+}
+
+func TestBufferingAudioSessionDropsOversizedAudio(t *testing.T) {
+	t.Parallel()
+
+	buffer := newBufferingAudioSession(&fakeAudioSession{chunks: [][]byte{[]byte("abcd"), []byte("efgh")}}, 6)
+	p := make([]byte, 16)
+	if _, err := buffer.Read(p); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if audio, ok := buffer.Audio(); !ok || string(audio) != "abcd" {
+		t.Fatalf("unexpected buffered audio: %q %v", audio, ok)
+	}
+	if _, err := buffer.Read(p); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, ok := buffer.Audio(); ok {
+		t.Fatalf("expected oversized audio to be dropped")
+	}
+}
+
+func TestSessionControllerCopiesAccurateTranscript(t *testing.T) {
+	t.Parallel()
+
+	live := newFakeStreamingSession()
+	live.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindPartial, Text: "ship it"}
+	live.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "ship it friday"}
+	accurate := newFakeStreamingSession()
+	accurate.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "Ship it Friday."}
+
+	clipboard := &fakeClipboard{}
+	events := &fakeEventSink{}
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{chunks: [][]byte{[]byte("pcm")}}}},
+		&fakeProvider{sessions: []ports.StreamingSession{live}},
+		&fakeRules{},
+		clipboard,
+		events,
+		Config{ChunkSize: 512},
+	)
+	controller.SetAccurateProvider(&fakeProvider{sessions: []ports.StreamingSession{accurate}})
+
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	result, err := controller.Stop(context.Background())
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if result.RawTranscript != "Ship it Friday." || clipboard.lastText != "Ship it Friday." {
+		t.Fatalf("expected accurate transcript, got %+v", result)
+	}
+	if len(events.partials) == 0 || events.partials[0] != "ship it" {
+		t.Fatalf("expected live partials, got %q", events.partials)
+	}
+}
+
+func TestSessionControllerFallsBackToLiveTranscript(t *testing.T) {
+	t.Parallel()
+
+	live := newFakeStreamingSession()
+	live.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "ship it friday"}
+
+	events := &fakeEventSink{}
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{chunks: [][]byte{[]byte("pcm")}}}},
+		&fakeProvider{sessions: []ports.StreamingSession{live}},
+		&fakeRules{},
+		&fakeClipboard{},
+		events,
+		Config{ChunkSize: 512},
+	)
+	controller.SetAccurateProvider(&fakeProvider{err: errors.New("model unavailable")})
+
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	result, err := controller.Stop(context.Background())
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if result.RawTranscript != "ship it friday" {
+		t.Fatalf("expected live transcript, got %q", result.RawTranscript)
+	}
+	errs := events.snapshotErrors()
+	if len(errs) != 1 || errs[0].code != domain.ErrorCodeTranscription {
+		t.Fatalf("expected accurate pass error, got %+v", errs)
+	}
+}
+
+type fakeSpanSink struct {
+	transcripts []domain.ConfidenceTranscript
+}
+
+func (f *fakeSpanSink) ConfidenceSpans(transcript domain.ConfidenceTranscript) {
+	f.transcripts = append(f.transcripts, transcript)
+}
+
+func TestSessionControllerReportsConfidenceSpans(t *testing.T) {
+	t.Parallel()
+
+	stream := newFakeStreamingSession()
+	stream.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "call Aoife", Words: []domain.TranscriptWord{
+		{Text: "call", Confidence: 0.97},
+		{Text: "Aoife", Confidence: 0.31, Start: 400 * time.Millisecond, End: 900 * time.Millisecond},
+	}}
+	sink := &fakeSpanSink{}
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{chunks: [][]byte{[]byte("pcm")}}}},
+		&fakeProvider{sessions: []ports.StreamingSession{stream}},
+		&fakeRules{},
+		&fakeClipboard{},
+		&fakeEventSink{},
+		Config{ChunkSize: 512},
+	)
+	controller.SetSpanSink(sink)
+
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	result, err := controller.Stop(context.Background())
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if len(result.Spans) != 2 || result.Spans[1].Bucket != domain.ConfidenceLow {
+		t.Fatalf("unexpected spans: %+v", result.Spans)
+	}
+	if len(result.Words) != 2 || result.Words[1].Start != 400*time.Millisecond || result.Words[1].End != 900*time.Millisecond {
+		t.Fatalf("expected timed words in the result, got %+v", result.Words)
+	}
+	if len(sink.transcripts) != 1 || sink.transcripts[0].SessionID != result.SessionID || len(sink.transcripts[0].Spans) != 2 {
+		t.Fatalf("unexpected span events: %+v", sink.transcripts)
+	}
+}
@@ -1,9 +1,12 @@
 package usecase
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
@@ -12,12 +15,34 @@ import (
 	"coldmic/internal/ports"
 )
 
+// finalizeTimeout bounds the wait for a provider to acknowledge Finalize.
+const finalizeTimeout = 3 * time.Second
+
 // Config controls tracer-bullet recording behavior.
 type Config struct {
-	Audio          ports.AudioConfig
-	Streaming      ports.StreamingConfig
-	ChunkSize      int
+	Audio     ports.AudioConfig
+	Streaming ports.StreamingConfig
+	ChunkSize int
+	// StreamingGrace is how long Stop waits for the tail of the audio to be
+	// transcribed when the provider stream cannot finalize.
 	StreamingGrace time.Duration
+	Translation    TranslationConfig
+	Timestamps     TimestampConfig
+	// Channels combines the channels of a multichannel stream; it applies
+	// when Streaming.Multichannel is set.
+	Channels ChannelConfig
+	// Trim applies to batch file transcription only; live capture is never trimmed.
+	Trim SilenceTrimConfig
+	// IdleAbort ends live sessions that stay idle.
+	IdleAbort IdleAbortConfig
+	// Language is the configured transcript language; it selects
+	// per-language rules when the provider detects none.
+	Language string
+	// Clock times grace periods and stream timeouts; nil uses the wall clock.
+	Clock ports.Clock
+	// IncrementalCopy copies each utterance while recording when transcripts
+	// are copied as plain text.
+	IncrementalCopy IncrementalCopyConfig
 }
 
 // SessionController orchestrates push-to-talk recording and transcription.
@@ -25,12 +50,36 @@ type SessionController struct {
 	audio     ports.AudioCapture
 	provider  ports.TranscriptionProvider
 	events    ports.EventSink
-	finalizer transcriptFinalizer
+	finalizer *transcriptFinalizer
 	cfg       Config
 
-	mu      sync.Mutex
-	current *activeSession
-	nextID  uint64
+	translator ports.Translator
+	captions   ports.CaptionSink
+	spans      ports.SpanSink
+	hooks      []ports.RecordingHook
+
+	mu sync.Mutex
+	// sessions are the running sessions by ID. Only one runs at a time
+	// today, but each is tracked on its own so meeting and push-to-talk
+	// sessions can later run side by side.
+	sessions map[string]*activeSession
+	// latest is the most recently started running session, which calls
+	// without a session ID act on.
+	latest *activeSession
+	nextID uint64
+	// lastLog is the event log of the current or most recent session.
+	lastLog *sessionLog
+
+	retry         RetryConfig
+	retryProvider ports.TranscriptionProvider
+	confidence    ports.ConfidenceSink
+	lastAudio     *retainedAudio
+	accurate      ports.TranscriptionProvider
+
+	usage *UsageMeter
+	// pendingRules are temporary rules added while no session ran, taken by
+	// the next session.
+	pendingRules temporaryRules
 }
 
 func NewSessionController(
@@ -44,30 +93,116 @@ func NewSessionController(
 	if cfg.ChunkSize < 256 {
 		cfg.ChunkSize = 4096
 	}
+	finalizer := newTranscriptFinalizer(rules, clipboard, events)
+	finalizer.clock = cfg.Clock
 	return &SessionController{
 		audio:     audio,
 		provider:  provider,
 		events:    events,
-		finalizer: newTranscriptFinalizer(rules, clipboard, events),
+		finalizer: finalizer,
 		cfg:       cfg,
+		sessions:  map[string]*activeSession{},
 	}
 }
 
-// Start begins a new capture/transcription session.
-func (c *SessionController) Start(ctx context.Context) error {
-	var previous *activeSession
+// SetTranslator enables the parallel caption stream for sessions started afterwards.
+// Captions are only produced when Config.Translation.TargetLanguage is set.
+func (c *SessionController) SetTranslator(translator ports.Translator, captions ports.CaptionSink) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.translator = translator
+	c.captions = captions
+}
+
+// SetSpanSink reports the word-confidence spans of every final transcript to
+// sink. A nil sink only returns them on StopResult.
+func (c *SessionController) SetSpanSink(sink ports.SpanSink) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.spans = sink
+}
+
+// SetUsage prices the audio each session sends to its providers, including
+// accurate passes and retries, and adds it to the monthly total. A nil meter
+// disables tracking.
+func (c *SessionController) SetUsage(meter *UsageMeter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.usage = meter
+}
 
+// SetProvider switches the transcription provider for sessions started afterwards.
+func (c *SessionController) SetProvider(provider ports.TranscriptionProvider) {
 	c.mu.Lock()
-	if c.current != nil {
-		previous = c.current
-		c.current = nil
+	defer c.mu.Unlock()
+	c.provider = provider
+}
+
+// SetForm switches sessions finished afterwards to form-filling output,
+// copying the dictated fields instead of the transcript. A nil schema
+// restores plain transcripts.
+func (c *SessionController) SetForm(schema *domain.FormSchema) error {
+	if schema == nil {
+		c.finalizer.setForm(nil)
+		return nil
+	}
+	form, err := newFormFiller(*schema)
+	if err != nil {
+		return err
+	}
+	c.finalizer.setForm(form)
+	return nil
+}
+
+// SetCopyListener announces every copied transcript to listener, such as a
+// CopyBus. A nil listener disables announcements.
+func (c *SessionController) SetCopyListener(listener ports.CopyListener) {
+	c.finalizer.setCopyListener(listener)
+}
+
+// SetTarget formats transcripts finished afterwards for target and delivers
+// them there after copying. A nil target copies transcripts unchanged. Form
+// output takes precedence over the target while a form is active.
+func (c *SessionController) SetTarget(target ports.OutputTarget) {
+	c.finalizer.setTarget(target)
+}
+
+// SetCasing recases transcripts finished afterwards according to the
+// policy for the active output target.
+func (c *SessionController) SetCasing(casing CasingConfig) {
+	c.finalizer.setCasing(casing)
+}
+
+// SetNormalize tidies whitespace and punctuation of transcripts finished
+// afterwards according to the options for the active output target.
+func (c *SessionController) SetNormalize(normalize NormalizeConfig) {
+	c.finalizer.setNormalize(normalize)
+}
+
+// Start begins a new capture/transcription session, discarding the latest
+// running session first.
+func (c *SessionController) Start(ctx context.Context) error {
+	c.mu.Lock()
+	previous := c.latest
+	if previous != nil {
+		c.forget(previous)
 	}
 	c.mu.Unlock()
 
 	if previous != nil {
 		c.stopSession(previous)
 	}
+	_, err := c.start(ctx, previous != nil)
+	return err
+}
+
+// StartSession begins a session alongside any running ones and returns its
+// ID. The new session becomes the latest.
+func (c *SessionController) StartSession(ctx context.Context) (string, error) {
+	return c.start(ctx, false)
+}
 
+func (c *SessionController) start(ctx context.Context, restarted bool) (string, error) {
 	debuglog.Printf(
 		"session start requested audio_format=%s audio_device=%s sample_rate=%d channels=%d chunk_size=%d streaming_grace_ms=%d",
 		c.cfg.Audio.InputFormat,
@@ -78,54 +213,123 @@ func (c *SessionController) Start(ctx context.Context) error {
 		c.cfg.StreamingGrace/time.Millisecond,
 	)
 
+	c.mu.Lock()
+	provider := c.provider
+	c.mu.Unlock()
+
 	sessionCtx, cancel := context.WithCancel(ctx)
-	stream, err := c.provider.StartStreaming(sessionCtx, c.cfg.Streaming)
+	stream, err := provider.StartStreaming(sessionCtx, c.cfg.Streaming)
 	if err != nil {
 		cancel()
 		debuglog.Printf("session start failed during provider startup: %v", err)
-		return err
+		return "", err
 	}
 	debuglog.Printf("session provider stream started")
+	// Reconnects are reported once the session log exists; the notifier is
+	// taken from the provider stream before it is wrapped.
+	notifier, _ := stream.(ports.ReconnectNotifier)
 
+	restore := c.prepareRecording(ctx)
 	audioSession, err := c.audio.Start(sessionCtx, c.cfg.Audio)
 	if err != nil {
+		if restore != nil {
+			restore()
+		}
 		_ = stream.Close()
 		cancel()
 		debuglog.Printf("session start failed during audio startup: %v", err)
-		return err
+		return "", err
 	}
 	debuglog.Printf("session audio capture started")
 
+	var buffer *bufferingAudioSession
+	if limit := c.audioBufferLimit(); limit > 0 {
+		buffer = newBufferingAudioSession(audioSession, limit)
+		audioSession = buffer
+	}
+	var watchdog *idleWatchdog
+	if c.cfg.IdleAbort.Timeout > 0 {
+		watchdog = newIdleWatchdog(c.cfg.IdleAbort, clockOrSystem(c.cfg.Clock))
+		audioSession = watchdog.audio(audioSession)
+		stream = watchdog.stream(stream)
+	}
+
 	active := &activeSession{
 		cancel:     cancel,
+		provider:   provider,
+		buffer:     buffer,
+		restore:    restore,
 		audio:      audioSession,
 		stream:     stream,
 		state:      domain.SessionStateRecording,
-		aggregator: newTranscriptAggregator(),
+		aggregator: newChannelAggregator(c.cfg.Channels),
 		eventsDone: make(chan struct{}),
 		audioDone:  make(chan struct{}),
 	}
 
 	c.mu.Lock()
 	c.nextID++
+	active.seq = c.nextID
 	active.id = fmt.Sprintf("session-%d", c.nextID)
-	c.current = active
+	active.log = newSessionLog(active.id, clockOrSystem(c.cfg.Clock))
+	c.lastLog = active.log
+	translator, captions := c.translator, c.captions
 	c.mu.Unlock()
 
-	go consumeTranscriptionEvents(active.stream, active.aggregator, c.events, active.eventsDone)
-	go pumpAudioChunks(active.audio, active.stream, c.cfg.ChunkSize, c.events, active.audioDone)
+	if notifier != nil {
+		notifier.OnReconnect(func(err error) {
+			active.log.warn(domain.ErrorCodeReconnecting, "the provider stream reconnected; speech during the gap may be missing")
+			c.events.SessionError(domain.ErrorCodeReconnecting, err.Error())
+		})
+	}
+
+	if translator != nil && captions != nil && c.cfg.Translation.TargetLanguage != "" {
+		active.captions = newCaptionTranslator(translator, captions, c.cfg.Translation.TargetLanguage, active.id)
+		active.stream = newCaptioningStream(active.stream, active.captions)
+	}
+	if c.cfg.IncrementalCopy.Enabled && c.finalizer.plainOutput() {
+		active.copier = newIncrementalCopier(c.cfg.IncrementalCopy, clockOrSystem(c.cfg.Clock), func(ctx context.Context, text string, first bool) error {
+			language := transcriptLanguage(active.aggregator.Language(), c.cfg.Language)
+			err := c.finalizer.copySegment(ctx, active.id, text, language, c.temporaryRulesOf(active), first)
+			if err != nil {
+				active.log.warn(domain.ErrorCodeClipboard, "an utterance was not copied while recording")
+			}
+			return err
+		})
+		active.stream = active.copier.stream(active.stream)
+	}
+
+	c.mu.Lock()
+	c.sessions[active.id] = active
+	c.latest = active
+	active.temporary = c.pendingRules
+	c.pendingRules = temporaryRules{}
+	c.mu.Unlock()
 
 	reason := domain.SessionReasonRecordingStarted
-	if previous != nil {
+	if restarted {
 		reason = domain.SessionReasonRecordingRestarted
 	}
+	active.log.state(domain.SessionStateRecording, reason)
+	go consumeTranscriptionEvents(active.stream, active.aggregator, c.events, active.log, active.eventsDone)
+	go pumpAudioChunks(active.audio, active.stream, c.cfg.ChunkSize, c.events, active.log, active.audioDone)
+	if watchdog != nil {
+		go watchdog.run(sessionCtx, func(heard bool) { c.abortIdle(active, heard) })
+	}
+
 	c.events.SessionStateChanged(domain.SessionStateRecording, reason)
-	return nil
+	return active.id, nil
 }
 
-// Stop ends an active session and returns the final transcript.
+// Stop ends the latest session and returns the final transcript.
 func (c *SessionController) Stop(ctx context.Context) (domain.StopResult, error) {
-	active, err := c.getCurrent()
+	return c.StopSession(ctx, "")
+}
+
+// StopSession ends the session with id, or the latest session when id is
+// empty, and returns the final transcript.
+func (c *SessionController) StopSession(ctx context.Context, id string) (domain.StopResult, error) {
+	active, err := c.lookup(id)
 	if err != nil {
 		return domain.StopResult{}, err
 	}
@@ -133,31 +337,43 @@ func (c *SessionController) Stop(ctx context.Context) (domain.StopResult, error)
 	debuglog.Printf("session stop requested")
 
 	active.setState(domain.SessionStateStopping)
+	active.log.state(domain.SessionStateStopping, domain.SessionReasonTranscribing)
 	c.events.SessionStateChanged(domain.SessionStateStopping, domain.SessionReasonTranscribing)
 
 	if err := active.audio.Stop(); err != nil {
 		debuglog.Printf("session audio stop returned error: %v", err)
+		active.log.warn(domain.ErrorCodeAudioStop, "audio capture did not stop cleanly")
 		c.events.SessionError(domain.ErrorCodeAudioStop, "failed to stop audio capture cleanly")
 	}
-
-	if c.cfg.StreamingGrace > 0 {
-		timer := time.NewTimer(c.cfg.StreamingGrace)
-		select {
-		case <-timer.C:
-		case <-ctx.Done():
-			timer.Stop()
-		}
-	}
+	active.restoreEnvironment()
+	c.flushStream(ctx, active)
 
 	_ = active.stream.CloseSend()
-	streamErr := waitForStream(active.stream, 4*time.Second)
+	streamErr := waitForStream(active.stream, clockOrSystem(c.cfg.Clock), 4*time.Second)
 	<-active.eventsDone
 	<-active.audioDone
+	usage := c.recordUsage(ctx, active.provider, active.log.sentBytes())
+	if active.captions != nil {
+		flushCtx, cancelFlush := withClockTimeout(ctx, clockOrSystem(c.cfg.Clock), 5*time.Second)
+		active.captions.Flush(flushCtx)
+		cancelFlush()
+	}
+	// Text copied while recording is not copied again.
+	delivered := false
+	if active.copier != nil {
+		flushCtx, cancelFlush := withClockTimeout(ctx, clockOrSystem(c.cfg.Clock), 5*time.Second)
+		delivered = active.copier.Close(flushCtx)
+		cancelFlush()
+	}
 
-	raw := active.aggregator.Raw()
+	aggregator, accurateUsage := c.accuratePass(ctx, active)
+	usage = append(usage, accurateUsage...)
+	raw := aggregator.Raw()
 	debuglog.Printf("session stop stream_err=%v raw_len=%d raw=%q", streamErr, len(raw), raw)
 	if raw == "" && streamErr != nil {
-		c.events.SessionError(domain.ErrorCodeTranscription, streamErr.Error())
+		code := domain.ErrorCodeFor(streamErr, domain.ErrorCodeTranscription)
+		active.log.error(code)
+		c.events.SessionError(code, streamErr.Error())
 		c.finishSession(active, domain.SessionStateError, domain.SessionReasonTranscriptionFailed)
 		return domain.StopResult{}, streamErr
 	}
@@ -166,22 +382,85 @@ func (c *SessionController) Stop(ctx context.Context) (domain.StopResult, error)
 		c.finishSession(active, domain.SessionStateIdle, domain.SessionReasonNoTranscript)
 		return domain.StopResult{}, errors.New("no transcript captured")
 	}
+	if streamErr != nil {
+		active.log.warn(domain.ErrorCodeFor(streamErr, domain.ErrorCodeTranscription), "transcription ended with an error; the transcript may be incomplete")
+	}
 
-	result, reason, err := c.finalizer.Finalize(ctx, raw)
+	// An accurate transcript replaces the live utterances already copied,
+	// so it is copied whole.
+	finalize := c.finalizer.Finalize
+	if delivered && aggregator == active.aggregator {
+		finalize = c.finalizer.FinalizeDelivered
+	}
+	result, reason, err := finalize(ctx, active.id, raw, transcriptLanguage(aggregator.Language(), c.cfg.Language), c.temporaryRulesOf(active))
 	if err != nil {
 		c.finishSession(active, domain.SessionStateError, reason)
 		return domain.StopResult{}, err
 	}
 
-	result.SessionID = active.id
-	c.events.FinalTranscript(result.RawTranscript, result.FinalTranscript, result.SessionID)
+	if active.captions != nil {
+		result.TranslatedTranscript = active.captions.Translated()
+	}
+	result.Language = aggregator.Language()
+	result.Usage = usage
+	result.Warnings = append(active.log.warnings(), result.Warnings...)
+	c.checkConfidence(active, aggregator, &result)
+	c.events.FinalTranscript(result.RawTranscript, result.FinalTranscript, result.SessionID, result.Warnings)
+	c.reportSpans(aggregator, &result)
 	c.finishSession(active, domain.SessionStateIdle, reason)
 	return result, nil
 }
 
-// Abort cancels and discards an active session without transcription.
+// flushStream lets the provider transcribe the tail of the audio before the
+// stream is closed. Streams that finalize are flushed once the last chunk
+// has been sent; others wait the fixed StreamingGrace.
+func (c *SessionController) flushStream(ctx context.Context, active *activeSession) {
+	if finalizer, ok := active.stream.(ports.StreamFinalizer); ok {
+		select {
+		case <-active.audioDone:
+		case <-ctx.Done():
+			return
+		}
+		finalizeCtx, cancel := withClockTimeout(ctx, clockOrSystem(c.cfg.Clock), finalizeTimeout)
+		err := finalizer.Finalize(finalizeCtx)
+		cancel()
+		if err == nil {
+			debuglog.Printf("session stream finalized")
+			return
+		}
+		if !errors.Is(err, errors.ErrUnsupported) {
+			debuglog.Printf("session stream finalize failed: %v", err)
+			return
+		}
+	}
+
+	if c.cfg.StreamingGrace > 0 {
+		_ = sleepContext(ctx, clockOrSystem(c.cfg.Clock), c.cfg.StreamingGrace)
+	}
+}
+
+// reportSpans attaches the words and their confidence spans to result and
+// emits the spans.
+func (c *SessionController) reportSpans(aggregator *transcriptAggregator, result *domain.StopResult) {
+	result.Spans = aggregator.Spans()
+	result.Words = aggregator.Words()
+	c.mu.Lock()
+	sink := c.spans
+	c.mu.Unlock()
+	if sink != nil && len(result.Spans) > 0 {
+		sink.ConfidenceSpans(domain.ConfidenceTranscript{SessionID: result.SessionID, Spans: result.Spans})
+	}
+}
+
+// Abort cancels and discards the latest session without transcription.
 func (c *SessionController) Abort() error {
-	active, err := c.getCurrent()
+	return c.AbortSession("")
+}
+
+// AbortSession discards the session with id, or the latest session when id
+// is empty, without transcription.
+func (c *SessionController) AbortSession(id string) error {
+	active, err := c.lookup(id)
 	if err != nil {
 		return err
 	}
@@ -191,43 +470,139 @@ func (c *SessionController) Abort() error {
 	return nil
 }
 
-// Status returns the current backend status.
-func (c *SessionController) Status() domain.Status {
+// SessionLog returns the event log of the current or most recent session.
+func (c *SessionController) SessionLog() (domain.SessionLog, error) {
+	c.mu.Lock()
+	log := c.lastLog
+	c.mu.Unlock()
+	if log == nil {
+		return domain.SessionLog{}, domain.ErrNoSessionLog
+	}
+	return log.snapshot(), nil
+}
+
+// forgetLast drops the retained audio and event log of the last session and
+// any temporary rules waiting for the next one.
+func (c *SessionController) forgetLast() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	if c.current == nil {
+	c.lastAudio = nil
+	c.lastLog = nil
+	c.pendingRules = temporaryRules{}
+}
+
+// Status returns the status of the latest session.
+func (c *SessionController) Status() domain.Status {
+	return c.SessionStatus("")
+}
+
+// SessionStatus returns the status of the session with id, or of the latest
+// session when id is empty. A session that is not running is idle.
+func (c *SessionController) SessionStatus(id string) domain.Status {
+	active, err := c.lookup(id)
+	if err != nil {
 		return domain.Status{State: domain.SessionStateIdle, Active: false}
 	}
-	state := c.current.getState()
+	state := active.getState()
 	return domain.Status{State: state, Active: state != domain.SessionStateIdle}
 }
 
-func (c *SessionController) getCurrent() (*activeSession, error) {
+// Sessions returns the IDs of the running sessions in the order they
+// started.
+func (c *SessionController) Sessions() []string {
+	c.mu.Lock()
+	running := make([]*activeSession, 0, len(c.sessions))
+	for _, active := range c.sessions {
+		running = append(running, active)
+	}
+	c.mu.Unlock()
+	slices.SortFunc(running, func(a, b *activeSession) int { return cmp.Compare(a.seq, b.seq) })
+	ids := make([]string, len(running))
+	for i, active := range running {
+		ids[i] = active.id
+	}
+	return ids
+}
+
+// lookup finds the running session with id, or the latest when id is empty.
+func (c *SessionController) lookup(id string) (*activeSession, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	if c.current == nil {
+	active := c.latest
+	if id != "" {
+		active = c.sessions[id]
+	}
+	if active == nil {
 		return nil, domain.ErrNoActiveSession
 	}
-	return c.current, nil
+	return active, nil
+}
+
+// forget stops tracking active; when it was the latest, the most recently
+// started of the remaining sessions takes its place. c.mu must be held.
+func (c *SessionController) forget(active *activeSession) {
+	if c.sessions[active.id] != active {
+		return
+	}
+	delete(c.sessions, active.id)
+	if c.latest != active {
+		return
+	}
+	c.latest = nil
+	for _, other := range c.sessions {
+		if c.latest == nil || other.seq > c.latest.seq {
+			c.latest = other
+		}
+	}
 }
 
 func (c *SessionController) stopSession(active *activeSession) {
 	debuglog.Printf("session teardown requested")
 	active.cancel()
 	_ = active.audio.Stop()
+	active.restoreEnvironment()
 	_ = active.stream.Close()
 	<-active.eventsDone
 	<-active.audioDone
+	if active.captions != nil {
+		active.captions.Discard()
+	}
+	if active.copier != nil {
+		active.copier.Discard()
+	}
+	// Discarded audio was still sent, and billed.
+	c.recordUsage(context.Background(), active.provider, active.log.sentBytes())
+}
+
+// recordUsage adds n bytes of audio sent to provider to the usage total of
+// every service the provider streams to, returning nil when usage is not
+// tracked.
+func (c *SessionController) recordUsage(ctx context.Context, provider ports.TranscriptionProvider, n int64) []domain.SessionUsage {
+	c.mu.Lock()
+	meter := c.usage
+	c.mu.Unlock()
+	if meter == nil {
+		return nil
+	}
+	var usage []domain.SessionUsage
+	for _, name := range providerNames(provider) {
+		usage = append(usage, meter.Record(ctx, name, audioSeconds(n, c.cfg.Audio)))
+	}
+	return usage
 }
 
 func (c *SessionController) finishSession(active *activeSession, state domain.SessionState, reason domain.SessionStateReason) {
 	active.cancel()
 	active.setState(state)
+	active.log.state(state, reason)
+	if state == domain.SessionStateError {
+		if dump, err := json.Marshal(active.log.snapshot()); err == nil {
+			debuglog.Printf("session log %s", dump)
+		}
+	}
 
 	c.mu.Lock()
-	if c.current == active {
-		c.current = nil
-	}
+	c.forget(active)
 	c.mu.Unlock()
 
 	c.events.SessionStateChanged(state, reason)
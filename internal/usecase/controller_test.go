@@ -3,7 +3,9 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"slices"
 	"sync"
 	"testing"
 	"time"
@@ -233,6 +235,38 @@ func TestSessionControllerStopNoTranscriptWithStreamError(t *testing.T) {
 	}
 }
 
+func TestSessionControllerReportsClassifiedProviderError(t *testing.T) {
+	t.Parallel()
+
+	streamSession := newFakeStreamingSession()
+	streamSession.waitErr = fmt.Errorf("failed to read provider event: %w", &domain.ProviderError{
+		Provider: "deepgram",
+		Code:     domain.ErrorCodeInsufficientCredits,
+		Message:  "Project does not have enough credits",
+	})
+	events := &fakeEventSink{}
+
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{chunks: [][]byte{[]byte("abc")}}}},
+		&fakeProvider{sessions: []ports.StreamingSession{streamSession}},
+		&fakeRules{},
+		&fakeClipboard{},
+		events,
+		Config{},
+	)
+
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if _, err := controller.Stop(context.Background()); err == nil {
+		t.Fatalf("expected stream failure")
+	}
+	errs := events.snapshotErrors()
+	if len(errs) != 1 || errs[0].code != domain.ErrorCodeInsufficientCredits {
+		t.Fatalf("expected insufficient credits error, got %+v", errs)
+	}
+}
+
 func TestSessionControllerStartRestartStopsPreviousSession(t *testing.T) {
 	t.Parallel()
 
@@ -325,6 +359,58 @@ func TestSessionControllerStopAssignsUniqueSessionIDs(t *testing.T) {
 	}
 }
 
+func TestSessionControllerTracksSessionsByID(t *testing.T) {
+	t.Parallel()
+
+	firstStream := newFakeStreamingSession()
+	firstStream.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "first"}
+	secondStream := newFakeStreamingSession()
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{}, &fakeAudioSession{}}},
+		&fakeProvider{sessions: []ports.StreamingSession{firstStream, secondStream}},
+		&fakeRules{},
+		&fakeClipboard{},
+		&fakeEventSink{},
+		Config{},
+	)
+
+	first, err := controller.StartSession(context.Background())
+	if err != nil {
+		t.Fatalf("first start failed: %v", err)
+	}
+	second, err := controller.StartSession(context.Background())
+	if err != nil {
+		t.Fatalf("second start failed: %v", err)
+	}
+	if ids := controller.Sessions(); len(ids) != 2 || ids[0] != first || ids[1] != second {
+		t.Fatalf("expected both sessions running in start order, got %v", ids)
+	}
+	if firstStream.closeCalls != 0 || !controller.SessionStatus(first).Active {
+		t.Fatalf("expected the first session to keep running")
+	}
+
+	result, err := controller.StopSession(context.Background(), first)
+	if err != nil {
+		t.Fatalf("stop first failed: %v", err)
+	}
+	if result.SessionID != first || result.RawTranscript != "first" {
+		t.Fatalf("unexpected result for the first session: %+v", result)
+	}
+	if controller.SessionStatus(first).Active || !controller.Status().Active {
+		t.Fatalf("expected only the second session to remain")
+	}
+	if _, err := controller.StopSession(context.Background(), first); !errors.Is(err, domain.ErrNoActiveSession) {
+		t.Fatalf("expected a stopped session to be unknown, got %v", err)
+	}
+
+	if err := controller.Abort(); err != nil {
+		t.Fatalf("abort latest failed: %v", err)
+	}
+	if len(controller.Sessions()) != 0 || controller.Status().Active {
+		t.Fatalf("expected no running sessions, got %v", controller.Sessions())
+	}
+}
+
 func TestSessionControllerStatusActive(t *testing.T) {
 	t.Parallel()
 
@@ -366,6 +452,136 @@ func (f *fakeAudioCapture) Start(_ context.Context, _ ports.AudioConfig) (ports.
 	return session, nil
 }
 
+func TestSessionControllerStopFinalizesInsteadOfGrace(t *testing.T) {
+	t.Parallel()
+
+	inner := newFakeStreamingSession()
+	inner.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "tail"}
+	stream := &finalizingStreamingSession{fakeStreamingSession: inner}
+	audioSession := &fakeAudioSession{chunks: [][]byte{[]byte("abc")}}
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{audioSession}},
+		&fakeProvider{sessions: []ports.StreamingSession{stream}},
+		&fakeRules{},
+		&fakeClipboard{},
+		&fakeEventSink{},
+		Config{StreamingGrace: time.Minute},
+	)
+
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	started := time.Now()
+	result, err := controller.Stop(context.Background())
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 10*time.Second {
+		t.Fatalf("expected finalize to replace the grace period, stop took %s", elapsed)
+	}
+	if stream.finalizeCalls != 1 || result.RawTranscript != "tail" {
+		t.Fatalf("expected one finalize before close, got calls=%d raw=%q", stream.finalizeCalls, result.RawTranscript)
+	}
+}
+
+func TestSessionControllerReportsReconnects(t *testing.T) {
+	t.Parallel()
+
+	stream := &reconnectingStreamingSession{fakeStreamingSession: newFakeStreamingSession()}
+	events := &fakeEventSink{}
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{}}},
+		&fakeProvider{sessions: []ports.StreamingSession{stream}},
+		&fakeRules{},
+		&fakeClipboard{},
+		events,
+		Config{},
+	)
+
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	stream.notify(errors.New("connection reset"))
+	_ = controller.Abort()
+
+	events.mu.Lock()
+	defer events.mu.Unlock()
+	if len(events.errors) != 1 || events.errors[0].code != domain.ErrorCodeReconnecting {
+		t.Fatalf("expected a reconnecting error, got %+v", events.errors)
+	}
+}
+
+type warningRules struct {
+	fakeRules
+	warnings []string
+}
+
+func (f *warningRules) RuleWarnings() []string { return f.warnings }
+
+func TestSessionControllerReportsWarnings(t *testing.T) {
+	t.Parallel()
+
+	stream := &reconnectingStreamingSession{fakeStreamingSession: newFakeStreamingSession()}
+	stream.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "text"}
+	events := &fakeEventSink{}
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{}}},
+		&fakeProvider{sessions: []ports.StreamingSession{stream}},
+		&warningRules{warnings: []string{"substitutions.rules line 3: unsupported rule format"}},
+		&fakeClipboard{err: errors.New("clipboard down")},
+		events,
+		Config{},
+	)
+
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	stream.notify(errors.New("connection reset"))
+	stream.notify(errors.New("connection reset"))
+	result, err := controller.Stop(context.Background())
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+
+	want := []domain.SessionWarning{
+		{Code: domain.ErrorCodeReconnecting, Message: "the provider stream reconnected; speech during the gap may be missing", Count: 2},
+		{Code: domain.ErrorCodeRules, Message: "rules were skipped because they did not parse: substitutions.rules line 3: unsupported rule format", Count: 1},
+		{Code: domain.ErrorCodeClipboard, Message: "the transcript was not copied to the clipboard", Count: 1},
+	}
+	if !slices.Equal(result.Warnings, want) {
+		t.Fatalf("expected warnings %+v, got %+v", want, result.Warnings)
+	}
+	events.mu.Lock()
+	defer events.mu.Unlock()
+	if len(events.finals) != 1 || !slices.Equal(events.finals[0].warnings, want) {
+		t.Fatalf("expected the final event to carry the warnings, got %+v", events.finals)
+	}
+}
+
+type reconnectingStreamingSession struct {
+	*fakeStreamingSession
+	notify func(err error)
+}
+
+func (f *reconnectingStreamingSession) OnReconnect(fn func(err error)) {
+	f.notify = fn
+}
+
+type finalizingStreamingSession struct {
+	*fakeStreamingSession
+	finalizeCalls int
+}
+
+func (f *finalizingStreamingSession) Finalize(context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return errors.New("finalize after close")
+	}
+	f.finalizeCalls++
+	return nil
+}
+
 type fakeAudioSession struct {
 	mu        sync.Mutex
 	chunks    [][]byte
@@ -499,6 +715,7 @@ type finalEvent struct {
 	raw         string
 	transformed string
 	sessionID   string
+	warnings    []domain.SessionWarning
 }
 
 type errEvent struct {
@@ -518,10 +735,10 @@ func (f *fakeEventSink) PartialTranscript(text string) {
 	f.partials = append(f.partials, text)
 }
 
-func (f *fakeEventSink) FinalTranscript(raw string, transformed string, sessionID string) {
+func (f *fakeEventSink) FinalTranscript(raw string, transformed string, sessionID string, warnings []domain.SessionWarning) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
-	f.finals = append(f.finals, finalEvent{raw: raw, transformed: transformed, sessionID: sessionID})
+	f.finals = append(f.finals, finalEvent{raw: raw, transformed: transformed, sessionID: sessionID, warnings: warnings})
 }
 
 func (f *fakeEventSink) SessionError(code domain.ErrorCode, detail string) {
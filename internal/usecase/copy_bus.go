@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// copyBusBacklog bounds the copies waiting for slow subscribers.
+const copyBusBacklog = 16
+
+// CopyBus fans transcript-copied events out to subscribers such as the
+// desktop UI and post-copy command hooks. Subscribers run on one background
+// worker, in copy order, so a slow hook never delays the end of a session.
+type CopyBus struct {
+	events ports.EventSink
+	queue  chan copyDelivery
+	start  sync.Once
+
+	mu        sync.Mutex
+	listeners []ports.CopyListener
+}
+
+type copyDelivery struct {
+	ctx       context.Context
+	event     domain.CopyEvent
+	listeners []ports.CopyListener
+}
+
+func NewCopyBus(events ports.EventSink) *CopyBus {
+	return &CopyBus{events: events, queue: make(chan copyDelivery, copyBusBacklog)}
+}
+
+// Subscribe adds listener for copies made afterwards.
+func (b *CopyBus) Subscribe(listener ports.CopyListener) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.listeners = append(b.listeners, listener)
+}
+
+// TranscriptCopied queues event for every subscriber and returns at once.
+// Subscriber errors are reported as copy hook errors.
+func (b *CopyBus) TranscriptCopied(ctx context.Context, event domain.CopyEvent) error {
+	b.mu.Lock()
+	listeners := append([]ports.CopyListener(nil), b.listeners...)
+	b.mu.Unlock()
+	if len(listeners) == 0 {
+		return nil
+	}
+
+	b.start.Do(func() { go b.run() })
+	// Hooks outlive the stop request that triggered them.
+	select {
+	case b.queue <- copyDelivery{ctx: context.WithoutCancel(ctx), event: event, listeners: listeners}:
+		return nil
+	default:
+		return errors.New("copy hooks are falling behind; transcript-copied event dropped")
+	}
+}
+
+func (b *CopyBus) run() {
+	for delivery := range b.queue {
+		for _, listener := range delivery.listeners {
+			if err := listener.TranscriptCopied(delivery.ctx, delivery.event); err != nil {
+				debuglog.Printf("copy listener failed session_id=%s: %v", delivery.event.SessionID, err)
+				b.events.SessionError(domain.ErrorCodeCopyHook, err.Error())
+			}
+		}
+	}
+}
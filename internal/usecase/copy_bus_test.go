@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+type recordingCopyListener struct {
+	mu     sync.Mutex
+	events []domain.CopyEvent
+	err    error
+}
+
+func (r *recordingCopyListener) TranscriptCopied(_ context.Context, event domain.CopyEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return r.err
+}
+
+func (r *recordingCopyListener) snapshot() []domain.CopyEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.CopyEvent(nil), r.events...)
+}
+
+func TestCopyBusDeliversToSubscribersInOrder(t *testing.T) {
+	t.Parallel()
+
+	events := &fakeEventSink{}
+	bus := NewCopyBus(events)
+	failing := &recordingCopyListener{err: errors.New("hook exited 1")}
+	ok := &recordingCopyListener{}
+	bus.Subscribe(failing)
+	bus.Subscribe(ok)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	for _, id := range []string{"session-1", "session-2"} {
+		if err := bus.TranscriptCopied(ctx, domain.CopyEvent{SessionID: id, Text: "hi"}); err != nil {
+			t.Fatalf("publish failed: %v", err)
+		}
+	}
+	// Cancelling the stop request must not cut subscribers short.
+	cancel()
+
+	waitFor(t, func() bool { return len(ok.snapshot()) == 2 })
+	if got := ok.snapshot(); got[0].SessionID != "session-1" || got[1].SessionID != "session-2" {
+		t.Fatalf("unexpected delivery order: %+v", got)
+	}
+	waitFor(t, func() bool { return len(events.snapshotErrors()) == 2 })
+	if errs := events.snapshotErrors(); errs[0].code != domain.ErrorCodeCopyHook {
+		t.Fatalf("expected copy hook error, got %+v", errs)
+	}
+}
+
+func TestCopyBusWithoutSubscribersIsANoop(t *testing.T) {
+	t.Parallel()
+
+	bus := NewCopyBus(&fakeEventSink{})
+	for i := 0; i < copyBusBacklog*2; i++ {
+		if err := bus.TranscriptCopied(context.Background(), domain.CopyEvent{}); err != nil {
+			t.Fatalf("unexpected error without subscribers: %v", err)
+		}
+	}
+}
+
+func TestTranscriptFinalizerAnnouncesCopies(t *testing.T) {
+	t.Parallel()
+
+	listener := &recordingCopyListener{}
+	f := newTranscriptFinalizer(&fakeRules{transform: "final"}, &fakeClipboard{}, &fakeEventSink{})
+	f.setCopyListener(listener)
+
+	before := time.Now()
+	if _, _, err := f.Finalize(context.Background(), "session-9", "raw", "", nil); err != nil {
+		t.Fatalf("finalize failed: %v", err)
+	}
+	got := listener.snapshot()
+	if len(got) != 1 || got[0].SessionID != "session-9" || got[0].Text != "final" || got[0].RawTranscript != "raw" || got[0].CopiedAt.Before(before) {
+		t.Fatalf("unexpected copy event: %+v", got)
+	}
+
+	failing := newTranscriptFinalizer(&fakeRules{transform: "final"}, &fakeClipboard{err: errors.New("no display")}, &fakeEventSink{})
+	failing.setCopyListener(listener)
+	if _, _, err := failing.Finalize(context.Background(), "session-10", "raw", "", nil); err != nil {
+		t.Fatalf("finalize failed: %v", err)
+	}
+	if len(listener.snapshot()) != 1 {
+		t.Fatalf("expected no copy event when the clipboard write fails")
+	}
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// Corrector fixes a misheard word in the last transcript, copies the fixed
+// text again and teaches the rules so later transcripts come out right.
+type Corrector struct {
+	session   *SessionService
+	clipboard ports.Clipboard
+	rules     ports.RuleWriter
+	store     ports.CorrectionStore
+	events    ports.EventSink
+	now       func() time.Time
+}
+
+func NewCorrector(
+	session *SessionService,
+	clipboard ports.Clipboard,
+	rules ports.RuleWriter,
+	store ports.CorrectionStore,
+	events ports.EventSink,
+) *Corrector {
+	return &Corrector{session: session, clipboard: clipboard, rules: rules, store: store, events: events, now: time.Now}
+}
+
+// Correct replaces every whole-word occurrence of original in the last
+// transcript with corrected. The patched transcript becomes the latest one
+// even if saving the rule or the correction record fails.
+func (c *Corrector) Correct(ctx context.Context, original string, corrected string) (domain.StopResult, error) {
+	original, corrected = strings.TrimSpace(original), strings.TrimSpace(corrected)
+	if original == "" {
+		return domain.StopResult{}, errors.New("text to correct is required")
+	}
+	latest, err := c.session.LastTranscript()
+	if err != nil {
+		return domain.StopResult{}, err
+	}
+
+	pattern, err := correctionPattern(original)
+	if err != nil {
+		return domain.StopResult{}, err
+	}
+	result := latest.Result
+	if !pattern.MatchString(result.FinalTranscript) {
+		return domain.StopResult{}, domain.ErrCorrectionNotFound
+	}
+	replacement := strings.ReplaceAll(corrected, "$", "$$")
+	result.FinalTranscript = pattern.ReplaceAllString(result.FinalTranscript, replacement)
+	result.RawTranscript = pattern.ReplaceAllString(result.RawTranscript, replacement)
+	// Spans no longer line up with the patched text.
+	result.Spans = nil
+
+	result.Copied = true
+	if err := c.clipboard.SetText(ctx, result.FinalTranscript); err != nil {
+		result.Copied = false
+		c.events.SessionError(domain.ErrorCodeClipboard, err.Error())
+	}
+	c.session.replaceLatest(result)
+
+	if err := c.rules.AddLiteral(original, corrected); err != nil {
+		return result, fmt.Errorf("failed to save correction rule: %w", err)
+	}
+	correction := domain.Correction{
+		SessionID:   result.SessionID,
+		Original:    original,
+		Corrected:   corrected,
+		CorrectedAt: c.now().UTC(),
+	}
+	if err := c.store.RecordCorrection(ctx, correction); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// correctionPattern matches original case-insensitively on word boundaries,
+// as literal rules do.
+func correctionPattern(original string) (*regexp.Regexp, error) {
+	pattern := regexp.QuoteMeta(original)
+	if first := []rune(original)[0]; isWordRune(first) {
+		pattern = `\b` + pattern
+	}
+	if runes := []rune(original); isWordRune(runes[len(runes)-1]) {
+		pattern += `\b`
+	}
+	return regexp.Compile("(?i)" + pattern)
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+type fakeRuleWriter struct {
+	rules [][2]string
+	err   error
+}
+
+func (f *fakeRuleWriter) AddLiteral(from string, to string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.rules = append(f.rules, [2]string{from, to})
+	return nil
+}
+
+type fakeCorrectionStore struct {
+	corrections []domain.Correction
+}
+
+func (f *fakeCorrectionStore) RecordCorrection(_ context.Context, correction domain.Correction) error {
+	f.corrections = append(f.corrections, correction)
+	return nil
+}
+
+func newCorrectorSession(t *testing.T, text string) *SessionService {
+	t.Helper()
+
+	stream := newFakeStreamingSession()
+	stream.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: text}
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{chunks: [][]byte{[]byte("pcm")}}}},
+		&fakeProvider{sessions: []ports.StreamingSession{stream}},
+		&fakeRules{},
+		&fakeClipboard{},
+		&fakeEventSink{},
+		Config{},
+	)
+	service := NewSessionService(controller)
+	if err := service.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if _, err := service.Stop(context.Background()); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	return service
+}
+
+func TestCorrectorPatchesCopiesAndLearns(t *testing.T) {
+	t.Parallel()
+
+	session := newCorrectorSession(t, "Ask Shavon about Shavon's review")
+	clipboard := &fakeClipboard{}
+	rules := &fakeRuleWriter{}
+	store := &fakeCorrectionStore{}
+	corrector := NewCorrector(session, clipboard, rules, store, &fakeEventSink{})
+	corrector.now = func() time.Time { return time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC) }
+
+	result, err := corrector.Correct(context.Background(), "shavon", "Siobhan")
+	if err != nil {
+		t.Fatalf("correct failed: %v", err)
+	}
+	if result.FinalTranscript != "Ask Siobhan about Siobhan's review" || !result.Copied {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if clipboard.lastText != result.FinalTranscript {
+		t.Fatalf("expected corrected transcript on the clipboard, got %q", clipboard.lastText)
+	}
+	latest, err := session.LastTranscript()
+	if err != nil || latest.Result.FinalTranscript != result.FinalTranscript {
+		t.Fatalf("expected latest transcript to be patched: %+v %v", latest, err)
+	}
+	if len(rules.rules) != 1 || rules.rules[0] != [2]string{"shavon", "Siobhan"} {
+		t.Fatalf("unexpected rules: %+v", rules.rules)
+	}
+	if len(store.corrections) != 1 || store.corrections[0].SessionID != result.SessionID || store.corrections[0].CorrectedAt.Hour() != 12 {
+		t.Fatalf("unexpected corrections: %+v", store.corrections)
+	}
+}
+
+func TestCorrectorRejectsUnknownText(t *testing.T) {
+	t.Parallel()
+
+	session := newCorrectorSession(t, "Shipping the scholarship form")
+	rules := &fakeRuleWriter{}
+	corrector := NewCorrector(session, &fakeClipboard{}, rules, &fakeCorrectionStore{}, &fakeEventSink{})
+
+	if _, err := corrector.Correct(context.Background(), "ship", "sheep"); !errors.Is(err, domain.ErrCorrectionNotFound) {
+		t.Fatalf("expected whole-word mismatch to fail, got %v", err)
+	}
+	if len(rules.rules) != 0 {
+		t.Fatalf("expected no rule for a failed correction")
+	}
+
+	empty := NewCorrector(NewSessionService(nil), &fakeClipboard{}, rules, &fakeCorrectionStore{}, &fakeEventSink{})
+	if _, err := empty.Correct(context.Background(), "x", "y"); !errors.Is(err, domain.ErrNoTranscriptAvailable) {
+		t.Fatalf("expected no transcript error, got %v", err)
+	}
+}
+
+func TestCorrectorKeepsPatchWhenRuleFails(t *testing.T) {
+	t.Parallel()
+
+	session := newCorrectorSession(t, "deploy to prod")
+	corrector := NewCorrector(session, &fakeClipboard{}, &fakeRuleWriter{err: errors.New("read-only")}, &fakeCorrectionStore{}, &fakeEventSink{})
+
+	if _, err := corrector.Correct(context.Background(), "prod", "production"); err == nil {
+		t.Fatalf("expected rule failure")
+	}
+	latest, _ := session.LastTranscript()
+	if latest.Result.FinalTranscript != "deploy to production" {
+		t.Fatalf("expected patched transcript despite rule failure, got %q", latest.Result.FinalTranscript)
+	}
+}
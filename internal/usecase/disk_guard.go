@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// DiskGuardConfig controls when audio saving is refused or stopped for low disk space.
+type DiskGuardConfig struct {
+	MinFreeBytes  uint64
+	CheckInterval time.Duration
+}
+
+// checkFreeSpace returns an error when the store reports less than the configured minimum.
+func checkFreeSpace(store ports.RecordingStore, cfg DiskGuardConfig) error {
+	free, err := store.FreeBytes()
+	if err != nil {
+		return err
+	}
+	if free < cfg.MinFreeBytes {
+		return fmt.Errorf("only %d MB free, below the %d MB minimum", free>>20, cfg.MinFreeBytes>>20)
+	}
+	return nil
+}
+
+// savingAudioSession tees capture audio into a recording while periodically
+// re-checking free space. Saving stops with a warning event once space runs
+// low or a write fails; capture and transcription continue unaffected.
+type savingAudioSession struct {
+	ports.AudioSession
+	store  ports.RecordingStore
+	events ports.EventSink
+	cfg    DiskGuardConfig
+
+	mu        sync.Mutex
+	writer    io.WriteCloser
+	lastCheck time.Time
+}
+
+func newSavingAudioSession(session ports.AudioSession, writer io.WriteCloser, store ports.RecordingStore, events ports.EventSink, cfg DiskGuardConfig) *savingAudioSession {
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = 5 * time.Second
+	}
+	return &savingAudioSession{
+		AudioSession: session,
+		store:        store,
+		events:       events,
+		cfg:          cfg,
+		writer:       writer,
+		lastCheck:    time.Now(),
+	}
+}
+
+func (s *savingAudioSession) Read(p []byte) (int, error) {
+	n, err := s.AudioSession.Read(p)
+	if n > 0 {
+		s.save(p[:n])
+	}
+	return n, err
+}
+
+func (s *savingAudioSession) save(chunk []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.writer == nil {
+		return
+	}
+
+	if time.Since(s.lastCheck) >= s.cfg.CheckInterval {
+		s.lastCheck = time.Now()
+		if err := checkFreeSpace(s.store, s.cfg); err != nil {
+			s.abandonLocked(err)
+			return
+		}
+	}
+	if _, err := s.writer.Write(chunk); err != nil {
+		s.abandonLocked(err)
+	}
+}
+
+func (s *savingAudioSession) abandonLocked(err error) {
+	debuglog.Printf("audio saving stopped: %v", err)
+	_ = s.writer.Close()
+	s.writer = nil
+	s.events.SessionError(domain.ErrorCodeDiskSpace, err.Error())
+}
+
+// Finish closes the recording if it is still being written.
+func (s *savingAudioSession) Finish() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.writer == nil {
+		return nil
+	}
+	err := s.writer.Close()
+	s.writer = nil
+	return err
+}
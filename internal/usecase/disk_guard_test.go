@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestSavingAudioSessionStopsWhenDiskRunsLow(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeRecordingStore{free: 1 << 30}
+	writer := &fakeRecordingWriter{}
+	events := &fakeEventSink{}
+	session := newSavingAudioSession(
+		&fakeAudioSession{chunks: [][]byte{[]byte("one"), []byte("two"), []byte("three")}},
+		writer,
+		store,
+		events,
+		DiskGuardConfig{MinFreeBytes: 1 << 20, CheckInterval: time.Nanosecond},
+	)
+
+	buf := make([]byte, 8)
+	if _, err := session.Read(buf); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	store.setFree(1 << 10)
+	if n, err := session.Read(buf); err != nil || string(buf[:n]) != "two" {
+		t.Fatalf("expected capture to continue after saving stops, got %q %v", buf[:n], err)
+	}
+	_, _ = session.Read(buf)
+
+	if writer.String() != "one" || !writer.closed {
+		t.Fatalf("expected recording to stop after first chunk, got %q closed=%v", writer.String(), writer.closed)
+	}
+	errs := events.snapshotErrors()
+	if len(errs) != 1 || errs[0].code != domain.ErrorCodeDiskSpace {
+		t.Fatalf("expected one disk space warning, got %+v", errs)
+	}
+	if err := session.Finish(); err != nil {
+		t.Fatalf("finish after abandon should be a no-op: %v", err)
+	}
+}
+
+func TestMeetingControllerSkipsSavingWhenPreflightFails(t *testing.T) {
+	t.Parallel()
+
+	mic := newFakeStreamingSession()
+	mic.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "hello"}
+	store := &fakeRecordingStore{free: 1 << 10}
+	events := &fakeEventSink{}
+	controller := NewMeetingController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{}}},
+		&fakeProvider{sessions: []ports.StreamingSession{mic}},
+		&fakeRules{},
+		&fakeClipboard{},
+		events,
+		MeetingConfig{Tracks: []MeetingTrack{{Label: "Me"}}, DiskGuard: DiskGuardConfig{MinFreeBytes: 1 << 20}},
+	)
+	controller.SetRecordingStore(store)
+
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if _, err := controller.Stop(context.Background()); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if store.created != 0 {
+		t.Fatalf("expected no recording to be created, got %d", store.created)
+	}
+	errs := events.snapshotErrors()
+	if len(errs) != 1 || errs[0].code != domain.ErrorCodeDiskSpace {
+		t.Fatalf("expected disk space warning, got %+v", errs)
+	}
+}
+
+func TestMeetingControllerSavesTrackAudio(t *testing.T) {
+	t.Parallel()
+
+	mic := newFakeStreamingSession()
+	mic.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "hello"}
+	store := &fakeRecordingStore{free: 1 << 30}
+	controller := NewMeetingController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{chunks: [][]byte{[]byte("pcm")}}}},
+		&fakeProvider{sessions: []ports.StreamingSession{mic}},
+		&fakeRules{},
+		&fakeClipboard{},
+		&fakeEventSink{},
+		MeetingConfig{Tracks: []MeetingTrack{{Label: "Me"}}},
+	)
+	controller.SetRecordingStore(store)
+
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if _, err := controller.Stop(context.Background()); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if store.created != 1 || store.writer.String() != "pcm" || !store.writer.closed {
+		t.Fatalf("expected saved and closed recording, got created=%d writer=%+v", store.created, store.writer)
+	}
+}
+
+type fakeRecordingStore struct {
+	mu      sync.Mutex
+	free    uint64
+	created int
+	writer  *fakeRecordingWriter
+}
+
+func (f *fakeRecordingStore) Create(_ string, _ ports.AudioConfig) (io.WriteCloser, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.created++
+	f.writer = &fakeRecordingWriter{}
+	return f.writer, nil
+}
+
+func (f *fakeRecordingStore) FreeBytes() (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.free, nil
+}
+
+func (f *fakeRecordingStore) setFree(free uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.free = free
+}
+
+type fakeRecordingWriter struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (f *fakeRecordingWriter) Close() error {
+	f.closed = true
+	return nil
+}
@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// FileTranscriber runs stored audio through the streaming provider pipeline.
+type FileTranscriber struct {
+	decoder  ports.AudioDecoder
+	provider ports.TranscriptionProvider
+	rules    ports.RulesEngine
+	cfg      Config
+
+	cache            ports.TranscriptCache
+	cacheFingerprint string
+}
+
+func NewFileTranscriber(
+	decoder ports.AudioDecoder,
+	provider ports.TranscriptionProvider,
+	rules ports.RulesEngine,
+	cfg Config,
+) *FileTranscriber {
+	if cfg.ChunkSize < 256 {
+		cfg.ChunkSize = 4096
+	}
+	return &FileTranscriber{decoder: decoder, provider: provider, rules: rules, cfg: cfg}
+}
+
+// TranscribeFile decodes path, streams it to the provider, and applies rules.
+// With a cache configured, unchanged audio is served without re-streaming.
+func (t *FileTranscriber) TranscribeFile(ctx context.Context, path string) (domain.FileTranscript, error) {
+	debuglog.Printf("file transcription requested path=%q", path)
+
+	startedAt := time.Now()
+	key := ""
+	if t.cache != nil {
+		hash, err := hashFile(path)
+		if err != nil {
+			return domain.FileTranscript{}, err
+		}
+		key = hash + "-" + t.cacheFingerprint
+		cached, ok, err := t.cache.Get(ctx, key)
+		if err != nil {
+			debuglog.Printf("transcript cache read failed path=%q: %v", path, err)
+		} else if ok {
+			debuglog.Printf("transcript cache hit path=%q", path)
+			return t.finish(path, cached, startedAt)
+		}
+	}
+
+	transcript, err := t.stream(ctx, path)
+	if err != nil {
+		return domain.FileTranscript{}, err
+	}
+	if t.cache != nil {
+		if err := t.cache.Put(ctx, key, transcript); err != nil {
+			debuglog.Printf("transcript cache write failed path=%q: %v", path, err)
+		}
+	}
+	return t.finish(path, transcript, startedAt)
+}
+
+// SetCache enables result caching. fingerprint identifies everything besides
+// the audio that affects provider output (provider, model, language, trimming),
+// so changing any of them invalidates earlier entries.
+func (t *FileTranscriber) SetCache(cache ports.TranscriptCache, fingerprint string) {
+	t.cache = cache
+	sum := sha256.Sum256([]byte(fingerprint))
+	t.cacheFingerprint = hex.EncodeToString(sum[:8])
+}
+
+func (t *FileTranscriber) stream(ctx context.Context, path string) (domain.CachedTranscript, error) {
+	pcm, err := t.decoder.Decode(ctx, path, t.cfg.Audio)
+	if err != nil {
+		return domain.CachedTranscript{}, err
+	}
+	stream, err := t.provider.StartStreaming(ctx, t.cfg.Streaming)
+	if err != nil {
+		_ = pcm.Close()
+		return domain.CachedTranscript{}, err
+	}
+
+	var source io.Reader = pcm
+	var offsets *offsetMap
+	if t.cfg.Trim.Enabled {
+		trimmer := newSilenceTrimmer(pcm, t.cfg.Audio.SampleRate, t.cfg.Audio.Channels, t.cfg.Trim)
+		source = trimmer
+		offsets = trimmer.Offsets()
+	}
+
+	errs := &errorCollector{}
+	aggregator := newTranscriptAggregator()
+	eventsDone := make(chan struct{})
+	audioDone := make(chan struct{})
+	go consumeTranscriptionEvents(stream, aggregator, errs, nil, eventsDone)
+	go pumpAudioChunks(decodedAudio{source}, stream, t.cfg.ChunkSize, errs, nil, audioDone)
+
+	<-audioDone
+	decodeErr := pcm.Close()
+	_ = stream.CloseSend()
+	streamErr := waitForStream(stream, clockOrSystem(t.cfg.Clock), 30*time.Second)
+	<-eventsDone
+
+	if decodeErr != nil {
+		return domain.CachedTranscript{}, decodeErr
+	}
+	if err := errs.Err(); err != nil {
+		return domain.CachedTranscript{}, err
+	}
+
+	raw := aggregator.Raw()
+	if raw == "" {
+		if streamErr != nil {
+			return domain.CachedTranscript{}, streamErr
+		}
+		return domain.CachedTranscript{}, errors.New("no transcript captured")
+	}
+
+	// Provider offsets are relative to the trimmed audio; map them back to the recording.
+	segments := aggregator.Segments()
+	for i := range segments {
+		segments[i].Offset = offsets.Original(segments[i].Offset)
+	}
+	return domain.CachedTranscript{RawTranscript: raw, Segments: segments}, nil
+}
+
+// finish applies rules and timestamps; these are never cached so rule edits take effect.
+func (t *FileTranscriber) finish(path string, transcript domain.CachedTranscript, startedAt time.Time) (domain.FileTranscript, error) {
+	transformed, err := t.rules.Apply(transcript.RawTranscript)
+	if err != nil {
+		return domain.FileTranscript{}, err
+	}
+	timestamped, err := formatTimestamped(t.cfg.Timestamps, transcript.Segments, startedAt, t.rules)
+	if err != nil {
+		return domain.FileTranscript{}, err
+	}
+
+	return domain.FileTranscript{
+		SourcePath:            path,
+		RawTranscript:         transcript.RawTranscript,
+		FinalTranscript:       transformed,
+		TimestampedTranscript: timestamped,
+	}, nil
+}
+
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open audio file: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to hash audio file: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// decodedAudio adapts a decoder stream to the capture session shape used by the pump.
+// The decoder is closed by the transcriber once the pump has drained it.
+type decodedAudio struct {
+	io.Reader
+}
+
+func (decodedAudio) Close() error { return nil }
+func (decodedAudio) Stop() error  { return nil }
+
+// errorCollector captures pipeline errors reported through the event sink contract.
+type errorCollector struct {
+	mu  sync.Mutex
+	err error
+}
+
+func (c *errorCollector) SessionStateChanged(_ domain.SessionState, _ domain.SessionStateReason) {}
+func (c *errorCollector) PartialTranscript(_ string)                                             {}
+func (c *errorCollector) FinalTranscript(_, _, _ string, _ []domain.SessionWarning)              {}
+
+func (c *errorCollector) SessionError(code domain.ErrorCode, detail string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err == nil {
+		c.err = fmt.Errorf("%s: %s", code, detail)
+	}
+}
+
+func (c *errorCollector) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
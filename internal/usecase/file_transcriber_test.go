@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestFileTranscriberAppliesRules(t *testing.T) {
+	t.Parallel()
+
+	stream := newFakeStreamingSession()
+	stream.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "hello file"}
+	transcriber := NewFileTranscriber(
+		&fakeDecoder{data: "pcm"},
+		&fakeProvider{sessions: []ports.StreamingSession{stream}},
+		&fakeRules{transform: "HELLO FILE"},
+		Config{},
+	)
+
+	result, err := transcriber.TranscribeFile(context.Background(), "/tmp/talk.wav")
+	if err != nil {
+		t.Fatalf("transcribe failed: %v", err)
+	}
+	if result.SourcePath != "/tmp/talk.wav" || result.RawTranscript != "hello file" || result.FinalTranscript != "HELLO FILE" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
+
+func TestFileTranscriberDecodeFailure(t *testing.T) {
+	t.Parallel()
+
+	stream := newFakeStreamingSession()
+	stream.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "partial audio"}
+	transcriber := NewFileTranscriber(
+		&fakeDecoder{data: "pcm", closeErr: errors.New("corrupt file")},
+		&fakeProvider{sessions: []ports.StreamingSession{stream}},
+		&fakeRules{},
+		Config{},
+	)
+
+	if _, err := transcriber.TranscribeFile(context.Background(), "bad.wav"); err == nil || err.Error() != "corrupt file" {
+		t.Fatalf("expected decode error, got %v", err)
+	}
+}
+
+func TestFileTranscriberNoTranscript(t *testing.T) {
+	t.Parallel()
+
+	transcriber := NewFileTranscriber(
+		&fakeDecoder{},
+		&fakeProvider{sessions: []ports.StreamingSession{newFakeStreamingSession()}},
+		&fakeRules{},
+		Config{},
+	)
+
+	if _, err := transcriber.TranscribeFile(context.Background(), "silent.wav"); err == nil {
+		t.Fatalf("expected no transcript error")
+	}
+}
+
+func TestFileTranscriberProviderFailureClosesDecoder(t *testing.T) {
+	t.Parallel()
+
+	decoder := &fakeDecoder{data: "pcm"}
+	transcriber := NewFileTranscriber(decoder, &fakeProvider{err: errors.New("offline")}, &fakeRules{}, Config{})
+
+	if _, err := transcriber.TranscribeFile(context.Background(), "a.wav"); err == nil {
+		t.Fatalf("expected provider error")
+	}
+	if !decoder.closed {
+		t.Fatalf("expected decoder to be closed")
+	}
+}
+
+func TestFileTranscriberServesCachedTranscript(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "talk.wav")
+	if err := os.WriteFile(path, []byte("audio-bytes"), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	stream := newFakeStreamingSession()
+	stream.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "hello cache"}
+	provider := &fakeProvider{sessions: []ports.StreamingSession{stream}}
+	cache := &fakeTranscriptCache{entries: map[string]domain.CachedTranscript{}}
+	transcriber := NewFileTranscriber(&fakeDecoder{data: "pcm"}, provider, &fakeRules{}, Config{})
+	transcriber.SetCache(cache, "deepgram|nova-2")
+
+	first, err := transcriber.TranscribeFile(context.Background(), path)
+	if err != nil {
+		t.Fatalf("first transcribe failed: %v", err)
+	}
+	// The provider has no more sessions, so a second stream attempt would fail.
+	second, err := transcriber.TranscribeFile(context.Background(), path)
+	if err != nil {
+		t.Fatalf("cached transcribe failed: %v", err)
+	}
+	if first.RawTranscript != "hello cache" || second.RawTranscript != first.RawTranscript {
+		t.Fatalf("unexpected results: %+v %+v", first, second)
+	}
+
+	transcriber.SetCache(cache, "deepgram|nova-3")
+	if _, err := transcriber.TranscribeFile(context.Background(), path); err == nil {
+		t.Fatalf("expected model change to miss the cache")
+	}
+}
+
+type fakeTranscriptCache struct {
+	mu      sync.Mutex
+	entries map[string]domain.CachedTranscript
+}
+
+func (f *fakeTranscriptCache) Get(_ context.Context, key string) (domain.CachedTranscript, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	entry, ok := f.entries[key]
+	return entry, ok, nil
+}
+
+func (f *fakeTranscriptCache) Put(_ context.Context, key string, transcript domain.CachedTranscript) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.entries[key] = transcript
+	return nil
+}
+
+type fakeDecoder struct {
+	data     string
+	err      error
+	closeErr error
+	closed   bool
+}
+
+func (f *fakeDecoder) Decode(_ context.Context, _ string, _ ports.AudioConfig) (io.ReadCloser, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &fakeDecodeStream{Reader: strings.NewReader(f.data), decoder: f}, nil
+}
+
+type fakeDecodeStream struct {
+	*strings.Reader
+	decoder *fakeDecoder
+}
+
+func (s *fakeDecodeStream) Close() error {
+	s.decoder.closed = true
+	return s.decoder.closeErr
+}
@@ -2,6 +2,9 @@ package usecase
 
 import (
 	"context"
+	"strings"
+	"sync"
+	"time"
 
 	"coldmic/internal/domain"
 	"coldmic/internal/ports"
@@ -11,31 +14,209 @@ type transcriptFinalizer struct {
 	rules     ports.RulesEngine
 	clipboard ports.Clipboard
 	events    ports.EventSink
+	// clock spaces form pieces on the clipboard; nil uses the wall clock.
+	clock ports.Clock
+
+	mu        sync.Mutex
+	form      *formFiller
+	target    ports.OutputTarget
+	copies    ports.CopyListener
+	casing    CasingConfig
+	normalize NormalizeConfig
+}
+
+func newTranscriptFinalizer(rules ports.RulesEngine, clipboard ports.Clipboard, events ports.EventSink) *transcriptFinalizer {
+	return &transcriptFinalizer{rules: rules, clipboard: clipboard, events: events}
+}
+
+// setForm switches to form-filling output; nil restores plain transcripts.
+func (f *transcriptFinalizer) setForm(form *formFiller) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.form = form
+}
+
+// setTarget formats plain transcripts for target; nil copies them unchanged.
+func (f *transcriptFinalizer) setTarget(target ports.OutputTarget) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.target = target
+}
+
+// setCopyListener announces every successful copy to listener; nil disables.
+func (f *transcriptFinalizer) setCopyListener(listener ports.CopyListener) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.copies = listener
+}
+
+// setCasing recases plain transcripts after the rules run.
+func (f *transcriptFinalizer) setCasing(casing CasingConfig) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.casing = casing
+}
+
+// setNormalize tidies plain transcripts after target formatting.
+func (f *transcriptFinalizer) setNormalize(normalize NormalizeConfig) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.normalize = normalize
+}
+
+// rulesFor returns the rules for a transcript in language, or the shared
+// rules when the engine has no per-language rules.
+func (f *transcriptFinalizer) rulesFor(language string) (ports.RulesEngine, error) {
+	if languages, ok := f.rules.(ports.LanguageRules); ok {
+		return languages.ForLanguage(language)
+	}
+	return f.rules, nil
+}
+
+// knownWords merges configured words with those the rules engine knows.
+func knownWords(rules ports.RulesEngine, configured []string) []string {
+	source, ok := rules.(ports.WordSource)
+	if !ok {
+		return configured
+	}
+	return append(append([]string{}, configured...), source.KnownWords()...)
+}
+
+// ruleWarnings returns the skipped rules the rules engine reports.
+func ruleWarnings(rules ports.RulesEngine) []string {
+	if warner, ok := rules.(ports.RuleWarner); ok {
+		return warner.RuleWarnings()
+	}
+	return nil
+}
+
+// transcriptLanguage prefers the language the provider detected over the
+// configured one.
+func transcriptLanguage(detected string, configured string) string {
+	if detected != "" {
+		return detected
+	}
+	return configured
+}
+
+// sessionRules returns temporary, when not nil, layered over the rules for
+// language.
+func (f *transcriptFinalizer) sessionRules(language string, temporary ports.RulesEngine) (ports.RulesEngine, error) {
+	rules, err := f.rulesFor(language)
+	if err != nil || temporary == nil {
+		return rules, err
+	}
+	return layeredRules{first: temporary, rest: rules}, nil
+}
+
+// Finalize applies temporary, when not nil, and then the rules for language,
+// "" for the shared rules, and copies the result.
+func (f *transcriptFinalizer) Finalize(ctx context.Context, sessionID string, raw string, language string, temporary ports.RulesEngine) (domain.StopResult, domain.SessionStateReason, error) {
+	return f.finalize(ctx, sessionID, raw, language, temporary, true)
 }
 
-func newTranscriptFinalizer(rules ports.RulesEngine, clipboard ports.Clipboard, events ports.EventSink) transcriptFinalizer {
-	return transcriptFinalizer{rules: rules, clipboard: clipboard, events: events}
+// FinalizeDelivered builds the result of a transcript already copied segment
+// by segment, without copying it again.
+func (f *transcriptFinalizer) FinalizeDelivered(ctx context.Context, sessionID string, raw string, language string, temporary ports.RulesEngine) (domain.StopResult, domain.SessionStateReason, error) {
+	return f.finalize(ctx, sessionID, raw, language, temporary, false)
 }
 
-func (f transcriptFinalizer) Finalize(ctx context.Context, raw string) (domain.StopResult, domain.SessionStateReason, error) {
-	transformed, err := f.rules.Apply(raw)
+func (f *transcriptFinalizer) finalize(ctx context.Context, sessionID string, raw string, language string, temporary ports.RulesEngine, deliver bool) (domain.StopResult, domain.SessionStateReason, error) {
+	rules, err := f.sessionRules(language, temporary)
+	var transformed string
+	if err == nil {
+		transformed, err = rules.Apply(raw)
+	}
 	if err != nil {
 		f.events.SessionError(domain.ErrorCodeRules, err.Error())
 		return domain.StopResult{}, domain.SessionReasonRulesFailed, err
 	}
 
+	f.mu.Lock()
+	form := f.form
+	target := f.target
+	copies := f.copies
+	casing := f.casing
+	normalize := f.normalize
+	f.mu.Unlock()
+
+	targetName := clipboardTargetName
+	if target != nil {
+		targetName = target.Name()
+	}
+	if form == nil {
+		if policy := casing.policyFor(targetName); policy != CasingPreserve {
+			transformed = applyCasing(policy, transformed, knownWords(rules, casing.Words))
+		}
+	}
+
 	result := domain.StopResult{
 		RawTranscript:   raw,
 		FinalTranscript: transformed,
 		Copied:          true,
+		SessionID:       sessionID,
+	}
+	if skipped := ruleWarnings(rules); len(skipped) > 0 {
+		result.Warnings = addWarning(result.Warnings, domain.ErrorCodeRules, "rules were skipped because they did not parse: "+strings.Join(skipped, "; "))
 	}
 	reason := domain.SessionReasonTranscriptCopied
 
-	if err := f.clipboard.SetText(ctx, transformed); err != nil {
+	copied := transformed
+	if form != nil {
+		values, missing := form.Fill(transformed)
+		if len(missing) > 0 {
+			message := "missing required fields: " + strings.Join(missing, ", ")
+			result.Warnings = addWarning(result.Warnings, domain.ErrorCodeForm, message)
+			f.events.SessionError(domain.ErrorCodeForm, message)
+		}
+		result.Form = form.schema.Name
+		result.Fields = values
+		pieces := form.Render(values)
+		copied = strings.Join(pieces, "\n")
+		err = writePieces(ctx, f.clipboard, pieces, clockOrSystem(f.clock), formPieceDelay)
+	} else {
+		if target != nil {
+			result.Target = target.Name()
+			if formatted, err := target.Format(transformed); err != nil {
+				result.Warnings = addWarning(result.Warnings, domain.ErrorCodeTarget, "the transcript was not formatted for "+target.Name())
+				f.events.SessionError(domain.ErrorCodeTarget, err.Error())
+			} else {
+				result.FinalTranscript = formatted
+			}
+		}
+		result.FinalTranscript = normalizeText(normalize.optionsFor(targetName), result.FinalTranscript)
+		copied = result.FinalTranscript
+		if !deliver {
+			return result, reason, nil
+		}
+		err = f.clipboard.SetText(ctx, copied)
+	}
+	if err != nil {
 		result.Copied = false
 		reason = domain.SessionReasonTranscriptReadyClipboardFailed
+		result.Warnings = addWarning(result.Warnings, domain.ErrorCodeClipboard, "the transcript was not copied to the clipboard")
 		f.events.SessionError(domain.ErrorCodeClipboard, "transcript ready but clipboard write failed")
 	}
+	if result.Copied && copies != nil {
+		err := copies.TranscriptCopied(ctx, domain.CopyEvent{
+			SessionID:     sessionID,
+			Text:          copied,
+			RawTranscript: raw,
+			Target:        result.Target,
+			Form:          result.Form,
+			CopiedAt:      time.Now(),
+		})
+		if err != nil {
+			result.Warnings = addWarning(result.Warnings, domain.ErrorCodeCopyHook, "copy hooks were not run for the transcript")
+			f.events.SessionError(domain.ErrorCodeCopyHook, err.Error())
+		}
+	}
+	if form == nil && target != nil {
+		if err := target.Deliver(ctx, result.FinalTranscript); err != nil {
+			result.Warnings = addWarning(result.Warnings, domain.ErrorCodeTarget, "the transcript was not delivered to "+target.Name())
+			f.events.SessionError(domain.ErrorCodeTarget, err.Error())
+		}
+	}
 
 	return result, reason, nil
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"coldmic/internal/domain"
+	"coldmic/internal/ports"
 )
 
 func TestTranscriptFinalizerRulesFailure(t *testing.T) {
@@ -14,7 +15,7 @@ func TestTranscriptFinalizerRulesFailure(t *testing.T) {
 	events := &fakeEventSink{}
 	f := newTranscriptFinalizer(&fakeRules{err: errors.New("rules")}, &fakeClipboard{}, events)
 
-	_, reason, err := f.Finalize(context.Background(), "raw")
+	_, reason, err := f.Finalize(context.Background(), "session-1", "raw", "", nil)
 	if err == nil {
 		t.Fatalf("expected rules error")
 	}
@@ -30,7 +31,7 @@ func TestTranscriptFinalizerClipboardFailure(t *testing.T) {
 	clipboard := &fakeClipboard{err: errors.New("clipboard")}
 	f := newTranscriptFinalizer(&fakeRules{transform: "final"}, clipboard, events)
 
-	result, reason, err := f.Finalize(context.Background(), "raw")
+	result, reason, err := f.Finalize(context.Background(), "session-1", "raw", "", nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -41,3 +42,84 @@ func TestTranscriptFinalizerClipboardFailure(t *testing.T) {
 		t.Fatalf("unexpected reason: %s", reason)
 	}
 }
+
+type fakeOutputTarget struct {
+	formatErr  error
+	deliverErr error
+	delivered  []string
+}
+
+func (t *fakeOutputTarget) Name() string { return "fake" }
+
+func (t *fakeOutputTarget) Format(text string) (string, error) {
+	return "formatted: " + text, t.formatErr
+}
+
+func (t *fakeOutputTarget) Deliver(_ context.Context, text string) error {
+	t.delivered = append(t.delivered, text)
+	return t.deliverErr
+}
+
+func TestTranscriptFinalizerOutputTarget(t *testing.T) {
+	t.Parallel()
+
+	events := &fakeEventSink{}
+	clipboard := &fakeClipboard{}
+	target := &fakeOutputTarget{deliverErr: errors.New("repo missing")}
+	f := newTranscriptFinalizer(&fakeRules{transform: "final"}, clipboard, events)
+	f.setTarget(target)
+
+	result, reason, err := f.Finalize(context.Background(), "session-1", "raw", "", nil)
+	if err != nil || reason != domain.SessionReasonTranscriptCopied {
+		t.Fatalf("unexpected finalize outcome: %s %v", reason, err)
+	}
+	if result.Target != "fake" || result.FinalTranscript != "formatted: final" || clipboard.lastText != "formatted: final" {
+		t.Fatalf("unexpected target result: %+v clipboard=%q", result, clipboard.lastText)
+	}
+	if len(target.delivered) != 1 || target.delivered[0] != "formatted: final" {
+		t.Fatalf("unexpected deliveries: %q", target.delivered)
+	}
+	errs := events.snapshotErrors()
+	if len(errs) != 1 || errs[0].code != domain.ErrorCodeTarget {
+		t.Fatalf("expected target error event, got %+v", errs)
+	}
+}
+
+type fakeLanguageRules struct {
+	fakeRules
+	languages map[string]ports.RulesEngine
+}
+
+func (f *fakeLanguageRules) ForLanguage(language string) (ports.RulesEngine, error) {
+	if rules, ok := f.languages[language]; ok {
+		return rules, nil
+	}
+	return &f.fakeRules, nil
+}
+
+func TestTranscriptFinalizerLanguageRules(t *testing.T) {
+	t.Parallel()
+
+	rules := &fakeLanguageRules{
+		fakeRules: fakeRules{transform: "shared"},
+		languages: map[string]ports.RulesEngine{"de": &fakeRules{transform: "german"}},
+	}
+	f := newTranscriptFinalizer(rules, &fakeClipboard{}, &fakeEventSink{})
+
+	for language, want := range map[string]string{"de": "german", "fr": "shared", "": "shared"} {
+		result, _, err := f.Finalize(context.Background(), "session-1", "raw", language, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.FinalTranscript != want {
+			t.Fatalf("language %q: expected %q, got %q", language, want, result.FinalTranscript)
+		}
+	}
+
+	if got := transcriptLanguage("de", "en"); got != "de" {
+		t.Fatalf("expected the detected language, got %q", got)
+	}
+	if got := transcriptLanguage("", "en"); got != "en" {
+		t.Fatalf("expected the configured language, got %q", got)
+	}
+}
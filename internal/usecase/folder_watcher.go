@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+)
+
+// DefaultWatchExtensions lists the audio file types picked up by the folder watcher.
+var DefaultWatchExtensions = []string{".wav", ".mp3", ".m4a", ".ogg", ".opus", ".flac", ".webm"}
+
+// WatchConfig controls watch-folder auto-transcription.
+type WatchConfig struct {
+	Dir        string
+	OutputDir  string
+	Interval   time.Duration
+	Extensions []string
+}
+
+// FolderWatcher polls a directory and submits new audio files to a batch pool,
+// which transcribes them into export files.
+type FolderWatcher struct {
+	pool *BatchPool
+	cfg  WatchConfig
+	runState
+
+	mu      sync.Mutex
+	pending map[string]int64
+	// claimed holds files submitted to the pool and not yet exported,
+	// including failed ones, so they are not submitted again.
+	claimed map[string]struct{}
+}
+
+func NewFolderWatcher(pool *BatchPool, cfg WatchConfig) *FolderWatcher {
+	if cfg.OutputDir == "" {
+		cfg.OutputDir = cfg.Dir
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 2 * time.Second
+	}
+	if len(cfg.Extensions) == 0 {
+		cfg.Extensions = DefaultWatchExtensions
+	}
+	return &FolderWatcher{
+		pool:    pool,
+		cfg:     cfg,
+		pending: make(map[string]int64),
+		claimed: make(map[string]struct{}),
+	}
+}
+
+// Run polls until ctx is cancelled. Transcription happens on the batch pool,
+// which the caller runs separately.
+func (w *FolderWatcher) Run(ctx context.Context) error {
+	defer w.begin()()
+	if err := os.MkdirAll(w.cfg.OutputDir, 0o700); err != nil {
+		return fmt.Errorf("failed to create watch output directory: %w", err)
+	}
+	debuglog.Printf("folder watcher started dir=%q output_dir=%q", w.cfg.Dir, w.cfg.OutputDir)
+
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		w.Scan()
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scan submits newly stable audio files to the pool and returns their jobs.
+// Files are considered stable once their size is unchanged between two
+// consecutive scans. Files that were exported or removed are forgotten.
+func (w *FolderWatcher) Scan() []domain.FileJob {
+	entries, err := os.ReadDir(w.cfg.Dir)
+	if err != nil {
+		debuglog.Printf("folder watcher scan failed: %v", err)
+		return nil
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var jobs []domain.FileJob
+	listed := make(map[string]struct{}, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !w.isAudioFile(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(w.cfg.Dir, entry.Name())
+		listed[path] = struct{}{}
+		if _, err := os.Stat(w.outputPath(path)); err == nil {
+			delete(w.claimed, path)
+			delete(w.pending, path)
+			continue
+		}
+		if _, ok := w.claimed[path]; ok {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		previous, seen := w.pending[path]
+		w.pending[path] = info.Size()
+		if !seen || previous != info.Size() {
+			continue
+		}
+
+		delete(w.pending, path)
+		w.claimed[path] = struct{}{}
+		job := w.pool.Submit(path, w.outputPath(path))
+		jobs = append(jobs, job)
+	}
+	for path := range w.claimed {
+		if _, ok := listed[path]; !ok {
+			delete(w.claimed, path)
+		}
+	}
+	for path := range w.pending {
+		if _, ok := listed[path]; !ok {
+			delete(w.pending, path)
+		}
+	}
+	return jobs
+}
+
+// outputPath keeps the source extension, so talk.wav and talk.mp3 in one
+// folder export to different files.
+func (w *FolderWatcher) outputPath(sourcePath string) string {
+	return filepath.Join(w.cfg.OutputDir, filepath.Base(sourcePath)+".txt")
+}
+
+func (w *FolderWatcher) isAudioFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, candidate := range w.cfg.Extensions {
+		if ext == candidate {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+func TestFolderWatcherScanWaitsForStableFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "talk.wav"), "abc")
+	writeTestFile(t, filepath.Join(dir, "notes.txt"), "ignored")
+
+	jobs := &fakeFileJobSink{}
+	pool := NewBatchPool(&fakeFileTranscriber{}, &fakeHistoryStore{}, jobs, nil, BatchConfig{})
+	watcher := NewFolderWatcher(pool, WatchConfig{Dir: dir})
+
+	if got := watcher.Scan(); len(got) != 0 {
+		t.Fatalf("expected first scan to wait for stability, got %+v", got)
+	}
+
+	got := watcher.Scan()
+	if len(got) != 1 {
+		t.Fatalf("expected one stable job, got %+v", got)
+	}
+	if got[0].SourcePath != filepath.Join(dir, "talk.wav") || got[0].OutputPath != filepath.Join(dir, "talk.wav.txt") {
+		t.Fatalf("unexpected job paths: %+v", got[0])
+	}
+	if got[0].State != domain.FileJobStateQueued {
+		t.Fatalf("expected queued job, got %s", got[0].State)
+	}
+
+	if again := watcher.Scan(); len(again) != 0 {
+		t.Fatalf("expected claimed file to be skipped, got %+v", again)
+	}
+}
+
+func TestFolderWatcherScanSkipsExportedFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	out := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "done.mp3"), "abc")
+	writeTestFile(t, filepath.Join(out, "done.mp3.txt"), "already transcribed")
+
+	pool := NewBatchPool(&fakeFileTranscriber{}, &fakeHistoryStore{}, &fakeFileJobSink{}, nil, BatchConfig{})
+	watcher := NewFolderWatcher(pool, WatchConfig{Dir: dir, OutputDir: out})
+	watcher.Scan()
+	if got := watcher.Scan(); len(got) != 0 {
+		t.Fatalf("expected exported file to be skipped, got %+v", got)
+	}
+}
+
+func TestFolderWatcherKeepsSameNamedSourcesApart(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "talk.mp3"), "abc")
+	writeTestFile(t, filepath.Join(dir, "talk.wav"), "abc")
+
+	pool := NewBatchPool(&fakeFileTranscriber{}, &fakeHistoryStore{}, &fakeFileJobSink{}, nil, BatchConfig{})
+	watcher := NewFolderWatcher(pool, WatchConfig{Dir: dir})
+	watcher.Scan()
+	got := watcher.Scan()
+	if len(got) != 2 || got[0].OutputPath == got[1].OutputPath {
+		t.Fatalf("expected two jobs with separate exports, got %+v", got)
+	}
+
+	// Exported and removed files are forgotten.
+	writeTestFile(t, got[0].OutputPath, "done")
+	if err := os.Remove(got[1].SourcePath); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+	watcher.Scan()
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	if len(watcher.claimed) != 0 || len(watcher.pending) != 0 {
+		t.Fatalf("expected processed files to be forgotten, got claimed=%v pending=%v", watcher.claimed, watcher.pending)
+	}
+}
+
+func TestFolderWatcherRunTranscribesIntoExportAndHistory(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	out := filepath.Join(t.TempDir(), "exports")
+	writeTestFile(t, filepath.Join(dir, "a.wav"), "a")
+	writeTestFile(t, filepath.Join(dir, "b.wav"), "b")
+
+	transcriber := &fakeFileTranscriber{text: "hello"}
+	history := &fakeHistoryStore{}
+	jobs := &fakeFileJobSink{}
+	pool := NewBatchPool(transcriber, history, jobs, nil, BatchConfig{Concurrency: 2})
+	watcher := NewFolderWatcher(pool, WatchConfig{
+		Dir:       dir,
+		OutputDir: out,
+		Interval:  5 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := runWatcherAndPool(ctx, watcher, pool)
+
+	waitFor(t, func() bool { return jobs.count(domain.FileJobStateDone) == 2 })
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	contents, err := os.ReadFile(filepath.Join(out, "a.wav.txt"))
+	if err != nil {
+		t.Fatalf("expected export file: %v", err)
+	}
+	if strings.TrimSpace(string(contents)) != "hello" {
+		t.Fatalf("unexpected export contents: %q", string(contents))
+	}
+	if len(history.snapshot()) != 2 {
+		t.Fatalf("expected two history entries, got %d", len(history.snapshot()))
+	}
+	if history.snapshot()[0].Source != domain.HistorySourceFile {
+		t.Fatalf("expected file history source")
+	}
+}
+
+func TestFolderWatcherReportsFailedJobs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "bad.wav"), "x")
+
+	jobs := &fakeFileJobSink{}
+	pool := NewBatchPool(&fakeFileTranscriber{err: errors.New("decode failed")}, &fakeHistoryStore{}, jobs, nil, BatchConfig{})
+	watcher := NewFolderWatcher(pool, WatchConfig{
+		Dir:      dir,
+		Interval: 5 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := runWatcherAndPool(ctx, watcher, pool)
+
+	waitFor(t, func() bool { return jobs.count(domain.FileJobStateFailed) == 1 })
+	cancel()
+	<-done
+
+	failed := jobs.last()
+	if failed.Error != "decode failed" {
+		t.Fatalf("expected failure detail, got %+v", failed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "bad.wav.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected no export for failed job, got %v", err)
+	}
+}
+
+// runWatcherAndPool runs both loops and reports the first error once both exit.
+func runWatcherAndPool(ctx context.Context, watcher *FolderWatcher, pool *BatchPool) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		poolDone := make(chan error, 1)
+		go func() { poolDone <- pool.Run(ctx) }()
+		err := watcher.Run(ctx)
+		if poolErr := <-poolDone; err == nil {
+			err = poolErr
+		}
+		done <- err
+	}()
+	return done
+}
+
+func writeTestFile(t *testing.T, path string, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("condition not met before deadline")
+}
+
+type fakeFileTranscriber struct {
+	text string
+	err  error
+}
+
+func (f *fakeFileTranscriber) TranscribeFile(_ context.Context, path string) (domain.FileTranscript, error) {
+	if f.err != nil {
+		return domain.FileTranscript{}, f.err
+	}
+	return domain.FileTranscript{SourcePath: path, RawTranscript: f.text, FinalTranscript: f.text}, nil
+}
+
+type fakeHistoryStore struct {
+	mu      sync.Mutex
+	entries []domain.HistoryEntry
+	err     error
+}
+
+func (f *fakeHistoryStore) Append(_ context.Context, entry domain.HistoryEntry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+func (f *fakeHistoryStore) List(_ context.Context) ([]domain.HistoryEntry, error) {
+	return f.snapshot(), nil
+}
+
+func (f *fakeHistoryStore) snapshot() []domain.HistoryEntry {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]domain.HistoryEntry, len(f.entries))
+	copy(out, f.entries)
+	return out
+}
+
+type fakeFileJobSink struct {
+	mu   sync.Mutex
+	jobs []domain.FileJob
+}
+
+func (f *fakeFileJobSink) FileJobChanged(job domain.FileJob) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.jobs = append(f.jobs, job)
+}
+
+func (f *fakeFileJobSink) count(state domain.FileJobState) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, job := range f.jobs {
+		if job.State == state {
+			n++
+		}
+	}
+	return n
+}
+
+func (f *fakeFileJobSink) last() domain.FileJob {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.jobs[len(f.jobs)-1]
+}
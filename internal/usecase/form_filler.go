@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+// formPieceDelay separates clipboard entries in FormOutputEntries mode.
+const formPieceDelay = 150 * time.Millisecond
+
+// formFiller extracts labeled values such as "name colon John, email colon
+// john@example.com" from a transcript according to a schema.
+type formFiller struct {
+	schema  domain.FormSchema
+	pattern *regexp.Regexp
+	keys    map[string]int
+}
+
+func newFormFiller(schema domain.FormSchema) (*formFiller, error) {
+	if len(schema.Fields) == 0 {
+		return nil, fmt.Errorf("form %q has no fields", schema.Name)
+	}
+	if schema.Output == "" {
+		schema.Output = domain.FormOutputJSON
+	}
+	switch schema.Output {
+	case domain.FormOutputJSON, domain.FormOutputKeyValue, domain.FormOutputEntries:
+	default:
+		return nil, fmt.Errorf("form %q has unknown output %q", schema.Name, schema.Output)
+	}
+
+	keys := make(map[string]int)
+	var names []string
+	for i := range schema.Fields {
+		field := &schema.Fields[i]
+		if field.Key == "" {
+			return nil, fmt.Errorf("form %q field %d has no key", schema.Name, i+1)
+		}
+		if field.Label == "" {
+			field.Label = field.Key
+		}
+		for _, name := range append([]string{field.Label}, field.Aliases...) {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if _, taken := keys[name]; taken {
+				return nil, fmt.Errorf("form %q uses label %q twice", schema.Name, name)
+			}
+			keys[name] = i
+			names = append(names, name)
+		}
+	}
+
+	// Longest names first so "email address" wins over "email".
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = strings.ReplaceAll(regexp.QuoteMeta(name), " ", `\s+`)
+	}
+	pattern := regexp.MustCompile(`(?i)(?:^|[\s,.;])(` + strings.Join(quoted, "|") + `)\s*(?::|\bcolon\b:?)\s*`)
+	return &formFiller{schema: schema, pattern: pattern, keys: keys}, nil
+}
+
+// Fill returns the dictated values in schema order and the labels of
+// required fields that were not dictated. A field dictated twice keeps its
+// last value; text before the first label is ignored.
+func (f *formFiller) Fill(text string) ([]domain.FormValue, []string) {
+	matches := f.pattern.FindAllStringSubmatchIndex(text, -1)
+	values := make([]string, len(f.schema.Fields))
+	for i, match := range matches {
+		end := len(text)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		name := strings.ToLower(strings.Join(strings.Fields(text[match[2]:match[3]]), " "))
+		values[f.keys[name]] = strings.TrimRight(strings.TrimSpace(text[match[1]:end]), ",;. ")
+	}
+
+	var filled []domain.FormValue
+	var missing []string
+	for i, field := range f.schema.Fields {
+		if values[i] == "" {
+			if field.Required {
+				missing = append(missing, field.Label)
+			}
+			continue
+		}
+		filled = append(filled, domain.FormValue{Key: field.Key, Label: field.Label, Value: values[i]})
+	}
+	return filled, missing
+}
+
+// Render formats values for the clipboard. Entries output renders one piece
+// per value.
+func (f *formFiller) Render(values []domain.FormValue) []string {
+	switch f.schema.Output {
+	case domain.FormOutputKeyValue:
+		lines := make([]string, len(values))
+		for i, value := range values {
+			lines[i] = value.Label + ": " + value.Value
+		}
+		return []string{strings.Join(lines, "\n")}
+	case domain.FormOutputEntries:
+		pieces := make([]string, len(values))
+		for i, value := range values {
+			pieces[i] = value.Value
+		}
+		return pieces
+	default:
+		// Build the object by hand to keep schema order.
+		var b strings.Builder
+		b.WriteString("{")
+		for i, value := range values {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			key, _ := json.Marshal(value.Key)
+			val, _ := json.Marshal(value.Value)
+			b.WriteString("\n  ")
+			b.Write(key)
+			b.WriteString(": ")
+			b.Write(val)
+		}
+		if len(values) > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("}")
+		return []string{b.String()}
+	}
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"coldmic/internal/domain"
+)
+
+func contactSchema(output domain.FormOutput) domain.FormSchema {
+	return domain.FormSchema{
+		Name:   "contact",
+		Output: output,
+		Fields: []domain.FormField{
+			{Key: "name", Label: "Name", Required: true},
+			{Key: "email", Label: "Email", Aliases: []string{"email address"}},
+			{Key: "phone", Label: "Phone", Required: true},
+		},
+	}
+}
+
+func TestFormFillerExtractsLabeledFields(t *testing.T) {
+	t.Parallel()
+
+	form, err := newFormFiller(contactSchema(domain.FormOutputJSON))
+	if err != nil {
+		t.Fatalf("new form failed: %v", err)
+	}
+	values, missing := form.Fill("Okay so name colon John Smith, Email Address: john@example.com.")
+	want := []domain.FormValue{
+		{Key: "name", Label: "Name", Value: "John Smith"},
+		{Key: "email", Label: "Email", Value: "john@example.com"},
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Fatalf("unexpected values: %+v", values)
+	}
+	if !reflect.DeepEqual(missing, []string{"Phone"}) {
+		t.Fatalf("unexpected missing fields: %q", missing)
+	}
+
+	rendered := form.Render(values)
+	if len(rendered) != 1 || rendered[0] != "{\n  \"name\": \"John Smith\",\n  \"email\": \"john@example.com\"\n}" {
+		t.Fatalf("unexpected json: %q", rendered)
+	}
+}
+
+func TestFormFillerRejectsInvalidSchemas(t *testing.T) {
+	t.Parallel()
+
+	if _, err := newFormFiller(domain.FormSchema{Name: "empty"}); err == nil {
+		t.Fatalf("expected schema without fields to be rejected")
+	}
+	dup := domain.FormSchema{Name: "dup", Fields: []domain.FormField{{Key: "a", Label: "x"}, {Key: "b", Aliases: []string{"X"}}}}
+	if _, err := newFormFiller(dup); err == nil {
+		t.Fatalf("expected duplicate label to be rejected")
+	}
+	if _, err := newFormFiller(domain.FormSchema{Name: "bad", Output: "xml", Fields: []domain.FormField{{Key: "a"}}}); err == nil {
+		t.Fatalf("expected unknown output to be rejected")
+	}
+}
+
+func TestSessionFormOutputCopiesFieldsAsEntries(t *testing.T) {
+	t.Parallel()
+
+	events := &fakeEventSink{}
+	clipboard := &recordingClipboard{}
+	finalizer := newTranscriptFinalizer(&fakeRules{}, clipboard, events)
+	form, err := newFormFiller(contactSchema(domain.FormOutputEntries))
+	if err != nil {
+		t.Fatalf("new form failed: %v", err)
+	}
+	finalizer.setForm(form)
+
+	result, reason, err := finalizer.Finalize(context.Background(), "session-1", "name colon Ana phone colon 555 0100", "", nil)
+	if err != nil || reason != domain.SessionReasonTranscriptCopied {
+		t.Fatalf("finalize failed: %v %s", err, reason)
+	}
+	if result.Form != "contact" || len(result.Fields) != 2 || result.FinalTranscript != "name colon Ana phone colon 555 0100" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if !reflect.DeepEqual(clipboard.texts, []string{"555 0100", "Ana"}) {
+		t.Fatalf("expected one clipboard entry per field, got %q", clipboard.texts)
+	}
+	if errs := events.snapshotErrors(); len(errs) != 0 {
+		t.Fatalf("expected no missing field errors, got %+v", errs)
+	}
+}
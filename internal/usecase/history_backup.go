@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// BackupConfig controls scheduled history backups.
+type BackupConfig struct {
+	Interval   time.Duration
+	ObjectName string
+}
+
+// HistoryBackup pushes history bundles to a backup target and restores them.
+// Encryption is the target's concern; see backup.EncryptedTarget.
+type HistoryBackup struct {
+	history ports.HistoryStore
+	target  ports.BackupTarget
+	cfg     BackupConfig
+	runState
+
+	// lastSum is the hash of the entries last uploaded, so edits that keep
+	// the entry count still trigger a backup.
+	lastSum  [sha256.Size]byte
+	uploaded bool
+}
+
+type historyBundle struct {
+	Version   int                   `json:"version"`
+	CreatedAt time.Time             `json:"createdAt"`
+	Entries   []domain.HistoryEntry `json:"entries"`
+}
+
+func NewHistoryBackup(history ports.HistoryStore, target ports.BackupTarget, cfg BackupConfig) *HistoryBackup {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+	if cfg.ObjectName == "" {
+		cfg.ObjectName = "coldmic-history.bundle"
+	}
+	return &HistoryBackup{history: history, target: target, cfg: cfg}
+}
+
+// Run backs up history every Interval until ctx is cancelled. Unchanged
+// history is not re-uploaded.
+func (b *HistoryBackup) Run(ctx context.Context) error {
+	defer b.begin()()
+	ticker := time.NewTicker(b.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if err := b.backupIfChanged(ctx); err != nil {
+			debuglog.Printf("history backup failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (b *HistoryBackup) backupIfChanged(ctx context.Context) error {
+	entries, err := b.history.List(ctx)
+	if err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(encoded)
+	if b.uploaded && sum == b.lastSum {
+		return nil
+	}
+	if err := b.upload(ctx, entries); err != nil {
+		return err
+	}
+	b.lastSum, b.uploaded = sum, true
+	return nil
+}
+
+// Backup uploads the full history immediately.
+func (b *HistoryBackup) Backup(ctx context.Context) error {
+	entries, err := b.history.List(ctx)
+	if err != nil {
+		return err
+	}
+	return b.upload(ctx, entries)
+}
+
+func (b *HistoryBackup) upload(ctx context.Context, entries []domain.HistoryEntry) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(historyBundle{Version: 1, CreatedAt: time.Now().UTC(), Entries: entries}); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if err := b.target.Upload(ctx, b.cfg.ObjectName, buf.Bytes()); err != nil {
+		return err
+	}
+	debuglog.Printf("history backup uploaded entries=%d bytes=%d", len(entries), buf.Len())
+	return nil
+}
+
+// Restore downloads the latest bundle and appends entries missing locally.
+// It returns the number of entries restored.
+func (b *HistoryBackup) Restore(ctx context.Context) (int, error) {
+	data, err := b.target.Download(ctx, b.cfg.ObjectName)
+	if err != nil {
+		return 0, err
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return 0, fmt.Errorf("invalid backup bundle: %w", err)
+	}
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid backup bundle: %w", err)
+	}
+	var bundle historyBundle
+	if err := json.Unmarshal(raw, &bundle); err != nil {
+		return 0, fmt.Errorf("invalid backup bundle: %w", err)
+	}
+
+	existing, err := b.history.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	// IDs restart with each process, so the creation time is part of the identity.
+	seen := make(map[string]struct{}, len(existing))
+	for _, entry := range existing {
+		seen[historyKey(entry)] = struct{}{}
+	}
+
+	restored := 0
+	for _, entry := range bundle.Entries {
+		if _, ok := seen[historyKey(entry)]; ok {
+			continue
+		}
+		if err := b.history.Append(ctx, entry); err != nil {
+			return restored, err
+		}
+		seen[historyKey(entry)] = struct{}{}
+		restored++
+	}
+	return restored, nil
+}
+
+func historyKey(entry domain.HistoryEntry) string {
+	return entry.ID + "@" + entry.CreatedAt.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+func TestHistoryBackupRoundTripMergesMissingEntries(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	source := &fakeHistoryStore{entries: []domain.HistoryEntry{
+		{ID: "session-1", FinalTranscript: "first", CreatedAt: created},
+		{ID: "session-2", FinalTranscript: "second", CreatedAt: created.Add(time.Minute)},
+	}}
+	target := &fakeBackupTarget{}
+	if err := NewHistoryBackup(source, target, BackupConfig{}).Backup(context.Background()); err != nil {
+		t.Fatalf("backup failed: %v", err)
+	}
+	if _, ok := target.objects["coldmic-history.bundle"]; !ok {
+		t.Fatalf("expected default object name, got %v", target.objects)
+	}
+
+	// session-1 exists locally; a later session-1 from another run is distinct.
+	restoredStore := &fakeHistoryStore{entries: []domain.HistoryEntry{
+		{ID: "session-1", FinalTranscript: "first", CreatedAt: created},
+		{ID: "session-1", FinalTranscript: "other run", CreatedAt: created.Add(time.Hour)},
+	}}
+	restored, err := NewHistoryBackup(restoredStore, target, BackupConfig{}).Restore(context.Background())
+	if err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if restored != 1 {
+		t.Fatalf("expected one restored entry, got %d", restored)
+	}
+	entries := restoredStore.snapshot()
+	if len(entries) != 3 || entries[2].FinalTranscript != "second" {
+		t.Fatalf("unexpected merged history: %+v", entries)
+	}
+}
+
+func TestHistoryBackupSkipsUnchangedHistory(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeHistoryStore{entries: []domain.HistoryEntry{{ID: "session-1"}}}
+	target := &fakeBackupTarget{}
+	backup := NewHistoryBackup(store, target, BackupConfig{})
+
+	for i := 0; i < 2; i++ {
+		if err := backup.backupIfChanged(context.Background()); err != nil {
+			t.Fatalf("backup failed: %v", err)
+		}
+	}
+	if target.uploads != 1 {
+		t.Fatalf("expected unchanged history to upload once, got %d", target.uploads)
+	}
+
+	_ = store.Append(context.Background(), domain.HistoryEntry{ID: "session-2"})
+	if err := backup.backupIfChanged(context.Background()); err != nil {
+		t.Fatalf("backup failed: %v", err)
+	}
+	if target.uploads != 2 {
+		t.Fatalf("expected new entry to trigger upload, got %d", target.uploads)
+	}
+}
+
+func TestHistoryBackupUploadsEditedHistory(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeHistoryStore{entries: []domain.HistoryEntry{{ID: "session-1", FinalTranscript: "teh draft"}}}
+	target := &fakeBackupTarget{}
+	backup := NewHistoryBackup(store, target, BackupConfig{})
+	if err := backup.backupIfChanged(context.Background()); err != nil {
+		t.Fatalf("backup failed: %v", err)
+	}
+
+	// A correction rewrites the entry without changing how many there are.
+	store.mu.Lock()
+	store.entries[0].FinalTranscript = "the draft"
+	store.mu.Unlock()
+	if err := backup.backupIfChanged(context.Background()); err != nil {
+		t.Fatalf("backup failed: %v", err)
+	}
+	if target.uploads != 2 {
+		t.Fatalf("expected edited history to upload again, got %d uploads", target.uploads)
+	}
+}
+
+func TestHistoryBackupRestoreRejectsGarbage(t *testing.T) {
+	t.Parallel()
+
+	target := &fakeBackupTarget{objects: map[string][]byte{"coldmic-history.bundle": []byte("nope")}}
+	if _, err := NewHistoryBackup(&fakeHistoryStore{}, target, BackupConfig{}).Restore(context.Background()); err == nil {
+		t.Fatalf("expected invalid bundle error")
+	}
+}
+
+type fakeBackupTarget struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	uploads int
+}
+
+func (f *fakeBackupTarget) Upload(_ context.Context, name string, data []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.objects == nil {
+		f.objects = map[string][]byte{}
+	}
+	f.objects[name] = data
+	f.uploads++
+	return nil
+}
+
+func (f *fakeBackupTarget) Download(_ context.Context, name string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, ok := f.objects[name]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return data, nil
+}
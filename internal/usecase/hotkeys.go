@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// HotkeySession is the session control a hotkey acts on.
+type HotkeySession interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) (domain.StopResult, error)
+	Abort() error
+	Status() domain.Status
+	LastTranscript() (domain.LatestTranscript, error)
+}
+
+// HotkeyTargets switches the output target before a hotkey starts a session.
+type HotkeyTargets interface {
+	Select(name string) (ports.OutputTarget, error)
+}
+
+// HotkeyDispatcher runs configured hotkeys by name. The compositor or window
+// manager owns the key bindings and triggers hotkeys through the desktop app
+// or the daemon API.
+type HotkeyDispatcher struct {
+	hotkeys   []domain.Hotkey
+	session   HotkeySession
+	targets   HotkeyTargets
+	clipboard ports.Clipboard
+}
+
+func NewHotkeyDispatcher(hotkeys []domain.Hotkey, session HotkeySession, targets HotkeyTargets, clipboard ports.Clipboard) *HotkeyDispatcher {
+	return &HotkeyDispatcher{hotkeys: hotkeys, session: session, targets: targets, clipboard: clipboard}
+}
+
+// List returns the configured hotkeys.
+func (d *HotkeyDispatcher) List() []domain.Hotkey {
+	return append([]domain.Hotkey(nil), d.hotkeys...)
+}
+
+// Trigger runs the named hotkey. A hotkey with a target selects it before
+// starting, and the target stays selected for later sessions.
+func (d *HotkeyDispatcher) Trigger(ctx context.Context, name string) (domain.HotkeyResult, error) {
+	hotkey, ok := d.find(name)
+	if !ok {
+		return domain.HotkeyResult{}, fmt.Errorf("%w: %q", domain.ErrHotkeyNotFound, name)
+	}
+	result := domain.HotkeyResult{Hotkey: hotkey.Name, Action: hotkey.Action}
+
+	var err error
+	switch hotkey.Action {
+	case domain.HotkeyStart:
+		err = d.start(ctx, hotkey)
+	case domain.HotkeyStop:
+		result.Result, err = d.stop(ctx)
+	case domain.HotkeyToggle:
+		if d.session.Status().Active {
+			result.Result, err = d.stop(ctx)
+		} else {
+			err = d.start(ctx, hotkey)
+		}
+	case domain.HotkeyAbort:
+		err = d.session.Abort()
+	case domain.HotkeyCopyLast:
+		result.Result, err = d.copyLast(ctx)
+	case domain.HotkeySelect:
+		_, err = d.targets.Select(hotkey.Target)
+	default:
+		err = fmt.Errorf("hotkey %q has unknown action %q", hotkey.Name, hotkey.Action)
+	}
+	if err != nil {
+		return domain.HotkeyResult{}, err
+	}
+	result.Status = d.session.Status()
+	return result, nil
+}
+
+func (d *HotkeyDispatcher) find(name string) (domain.Hotkey, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, hotkey := range d.hotkeys {
+		if hotkey.Name == name {
+			return hotkey, true
+		}
+	}
+	return domain.Hotkey{}, false
+}
+
+func (d *HotkeyDispatcher) start(ctx context.Context, hotkey domain.Hotkey) error {
+	if hotkey.Target != "" {
+		if _, err := d.targets.Select(hotkey.Target); err != nil {
+			return err
+		}
+	}
+	return d.session.Start(ctx)
+}
+
+func (d *HotkeyDispatcher) stop(ctx context.Context) (*domain.StopResult, error) {
+	result, err := d.session.Stop(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// copyLast copies the latest transcript to the clipboard again.
+func (d *HotkeyDispatcher) copyLast(ctx context.Context) (*domain.StopResult, error) {
+	latest, err := d.session.LastTranscript()
+	if err != nil {
+		return nil, err
+	}
+	if err := d.clipboard.SetText(ctx, latest.Result.FinalTranscript); err != nil {
+		return nil, fmt.Errorf("failed to copy the last transcript: %w", err)
+	}
+	result := latest.Result
+	result.Copied = true
+	return &result, nil
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestHotkeyDispatcherRoutesActions(t *testing.T) {
+	t.Parallel()
+
+	session := &fakeHotkeySession{latest: &domain.LatestTranscript{Result: domain.StopResult{SessionID: "session-1", FinalTranscript: "hello"}}}
+	targets := &fakeHotkeyTargets{}
+	clipboard := &fakeClipboard{}
+	dispatcher := NewHotkeyDispatcher([]domain.Hotkey{
+		{Name: "email", Action: domain.HotkeyStart, Target: "email"},
+		{Name: "code", Action: domain.HotkeyToggle, Target: "git-commit"},
+		{Name: "cancel", Action: domain.HotkeyAbort},
+		{Name: "again", Action: domain.HotkeyCopyLast},
+		{Name: "notes", Action: domain.HotkeySelect, Target: "todo"},
+	}, session, targets, clipboard)
+
+	result, err := dispatcher.Trigger(context.Background(), "Email")
+	if err != nil {
+		t.Fatalf("trigger failed: %v", err)
+	}
+	if !session.active || targets.selected != "email" || !result.Status.Active || result.Action != domain.HotkeyStart {
+		t.Fatalf("expected start with the email target, got %+v selected=%q", result, targets.selected)
+	}
+
+	result, err = dispatcher.Trigger(context.Background(), "code")
+	if err != nil {
+		t.Fatalf("trigger failed: %v", err)
+	}
+	if session.active || result.Result == nil || targets.selected != "email" {
+		t.Fatalf("expected toggle to stop the active session without switching target, got %+v selected=%q", result, targets.selected)
+	}
+
+	if _, err := dispatcher.Trigger(context.Background(), "code"); err != nil {
+		t.Fatalf("trigger failed: %v", err)
+	}
+	if !session.active || targets.selected != "git-commit" {
+		t.Fatalf("expected toggle to start with the git-commit target, selected=%q", targets.selected)
+	}
+
+	if _, err := dispatcher.Trigger(context.Background(), "cancel"); err != nil {
+		t.Fatalf("trigger failed: %v", err)
+	}
+	if session.active || session.aborts != 1 {
+		t.Fatalf("expected abort, got aborts=%d", session.aborts)
+	}
+
+	result, err = dispatcher.Trigger(context.Background(), "again")
+	if err != nil {
+		t.Fatalf("trigger failed: %v", err)
+	}
+	if clipboard.lastText != "hello" || result.Result == nil || !result.Result.Copied {
+		t.Fatalf("expected last transcript copied, got %q %+v", clipboard.lastText, result)
+	}
+
+	if _, err := dispatcher.Trigger(context.Background(), "notes"); err != nil {
+		t.Fatalf("trigger failed: %v", err)
+	}
+	if session.active || targets.selected != "todo" {
+		t.Fatalf("expected select to switch target without starting, selected=%q", targets.selected)
+	}
+
+	if _, err := dispatcher.Trigger(context.Background(), "missing"); !errors.Is(err, domain.ErrHotkeyNotFound) {
+		t.Fatalf("expected unknown hotkey to fail")
+	}
+}
+
+func TestHotkeyDispatcherKeepsSessionWhenTargetFails(t *testing.T) {
+	t.Parallel()
+
+	session := &fakeHotkeySession{}
+	targets := &fakeHotkeyTargets{err: errors.New("unknown output target")}
+	dispatcher := NewHotkeyDispatcher([]domain.Hotkey{{Name: "email", Action: domain.HotkeyStart, Target: "email"}}, session, targets, &fakeClipboard{})
+
+	if _, err := dispatcher.Trigger(context.Background(), "email"); err == nil {
+		t.Fatalf("expected target failure")
+	}
+	if session.active {
+		t.Fatalf("expected no session to start")
+	}
+}
+
+type fakeHotkeySession struct {
+	active bool
+	aborts int
+	latest *domain.LatestTranscript
+}
+
+func (f *fakeHotkeySession) Start(context.Context) error {
+	f.active = true
+	return nil
+}
+
+func (f *fakeHotkeySession) Stop(context.Context) (domain.StopResult, error) {
+	f.active = false
+	return domain.StopResult{SessionID: "session-2", Copied: true}, nil
+}
+
+func (f *fakeHotkeySession) Abort() error {
+	f.active = false
+	f.aborts++
+	return nil
+}
+
+func (f *fakeHotkeySession) Status() domain.Status {
+	if f.active {
+		return domain.Status{State: domain.SessionStateRecording, Active: true}
+	}
+	return domain.Status{State: domain.SessionStateIdle}
+}
+
+func (f *fakeHotkeySession) LastTranscript() (domain.LatestTranscript, error) {
+	if f.latest == nil {
+		return domain.LatestTranscript{}, domain.ErrNoTranscriptAvailable
+	}
+	return *f.latest, nil
+}
+
+type fakeHotkeyTargets struct {
+	selected string
+	err      error
+}
+
+func (f *fakeHotkeyTargets) Select(name string) (ports.OutputTarget, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.selected = name
+	return nil, nil
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// IdleAbortConfig ends a recording that stays idle, such as one left
+// running by a stuck hotkey, instead of streaming empty audio indefinitely.
+// A session is idle while no transcript arrives and no audio chunk reaches
+// Threshold RMS amplitude (0-32767). A session that never produced text is
+// discarded; one that did is stopped and keeps it. A zero Timeout disables
+// it.
+type IdleAbortConfig struct {
+	Timeout   time.Duration
+	Threshold int
+}
+
+// idleWatchdog calls abort once a session has been idle for timeout.
+type idleWatchdog struct {
+	clock     ports.Clock
+	timeout   time.Duration
+	threshold float64
+
+	mu    sync.Mutex
+	last  time.Time
+	heard bool
+}
+
+func newIdleWatchdog(cfg IdleAbortConfig, clock ports.Clock) *idleWatchdog {
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = 500
+	}
+	return &idleWatchdog{clock: clock, timeout: cfg.Timeout, threshold: float64(cfg.Threshold), last: clock.Now()}
+}
+
+// touch records activity now.
+func (w *idleWatchdog) touch() {
+	w.mu.Lock()
+	w.last = w.clock.Now()
+	w.mu.Unlock()
+}
+
+// heardText records a transcript now.
+func (w *idleWatchdog) heardText() {
+	w.mu.Lock()
+	w.last = w.clock.Now()
+	w.heard = true
+	w.mu.Unlock()
+}
+
+// run waits until the session has been idle for the timeout and calls
+// abort with whether any transcript arrived, or returns once ctx ends.
+func (w *idleWatchdog) run(ctx context.Context, abort func(heard bool)) {
+	wait := w.timeout
+	for {
+		timer := w.clock.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+		w.mu.Lock()
+		idle, heard := w.clock.Now().Sub(w.last), w.heard
+		w.mu.Unlock()
+		if idle >= w.timeout {
+			abort(heard)
+			return
+		}
+		wait = w.timeout - idle
+	}
+}
+
+// audio counts chunks of speech-level audio from session as activity.
+func (w *idleWatchdog) audio(session ports.AudioSession) ports.AudioSession {
+	return &idleAudioSession{AudioSession: session, watchdog: w}
+}
+
+// stream counts partial and final transcripts from session as activity.
+func (w *idleWatchdog) stream(session ports.StreamingSession) ports.StreamingSession {
+	s := &idleStream{StreamingSession: session, events: make(chan domain.TranscriptEvent, 64)}
+	go func() {
+		defer close(s.events)
+		for event := range session.Events() {
+			if (event.Kind == domain.TranscriptKindPartial || event.Kind == domain.TranscriptKindFinal) && event.Text != "" {
+				w.heardText()
+			}
+			s.events <- event
+		}
+	}()
+	return s
+}
+
+type idleAudioSession struct {
+	ports.AudioSession
+	watchdog *idleWatchdog
+}
+
+func (s *idleAudioSession) Read(p []byte) (int, error) {
+	n, err := s.AudioSession.Read(p)
+	if n > 0 && frameRMS(p[:n]) >= s.watchdog.threshold {
+		s.watchdog.touch()
+	}
+	return n, err
+}
+
+type idleStream struct {
+	ports.StreamingSession
+	events chan domain.TranscriptEvent
+}
+
+func (s *idleStream) Events() <-chan domain.TranscriptEvent {
+	return s.events
+}
+
+// Finalize flushes the provider stream when it supports finalizing.
+func (s *idleStream) Finalize(ctx context.Context) error {
+	if finalizer, ok := s.StreamingSession.(ports.StreamFinalizer); ok {
+		return finalizer.Finalize(ctx)
+	}
+	return errors.ErrUnsupported
+}
+
+// abortIdle ends active after its idle watchdog fired, unless it is no
+// longer recording. A session that produced text is stopped so the text is
+// kept; one that did not is discarded.
+func (c *SessionController) abortIdle(active *activeSession, heard bool) {
+	c.mu.Lock()
+	running := c.sessions[active.id] == active && active.getState() == domain.SessionStateRecording
+	c.mu.Unlock()
+	if !running {
+		return
+	}
+	if heard {
+		if _, err := c.StopSession(context.Background(), active.id); err != nil {
+			debuglog.Printf("idle session stop failed: %v", err)
+		}
+		return
+	}
+	c.stopSession(active)
+	c.finishSession(active, domain.SessionStateIdle, domain.SessionReasonIdleTimeout)
+}
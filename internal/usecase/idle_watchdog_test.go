@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestSessionControllerAbortsIdleSession(t *testing.T) {
+	t.Parallel()
+
+	audio := &fakeAudioSession{}
+	events := &fakeEventSink{}
+	clock := newFakeClock()
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{audio}},
+		&fakeProvider{sessions: []ports.StreamingSession{newFakeStreamingSession()}},
+		&fakeRules{},
+		&fakeClipboard{},
+		events,
+		Config{IdleAbort: IdleAbortConfig{Timeout: 30 * time.Second}, Clock: clock},
+	)
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+
+	clock.waitForTimers(t, 1)
+	clock.Advance(30 * time.Second)
+	waitFor(t, func() bool { return !controller.Status().Active })
+	events.mu.Lock()
+	last := events.states[len(events.states)-1]
+	finals := len(events.finals)
+	events.mu.Unlock()
+	if last.state != domain.SessionStateIdle || last.reason != domain.SessionReasonIdleTimeout {
+		t.Fatalf("expected an idle timeout, got %+v", last)
+	}
+	if finals != 0 {
+		t.Fatalf("expected the idle session discarded, got %d transcripts", finals)
+	}
+	if audio.stopCalls != 1 {
+		t.Fatalf("expected capture stopped once, got %d", audio.stopCalls)
+	}
+}
+
+func TestSessionControllerStopsIdleSessionWithText(t *testing.T) {
+	t.Parallel()
+
+	stream := newFakeStreamingSession()
+	events := &fakeEventSink{}
+	clock := newFakeClock()
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{}}},
+		&fakeProvider{sessions: []ports.StreamingSession{stream}},
+		&fakeRules{},
+		&fakeClipboard{},
+		events,
+		Config{IdleAbort: IdleAbortConfig{Timeout: 30 * time.Second}, Clock: clock},
+	)
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+
+	// A final without partials, as batch providers send, counts as activity.
+	clock.waitForTimers(t, 1)
+	clock.Advance(20 * time.Second)
+	stream.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "hello there"}
+	waitFor(t, func() bool {
+		controller.mu.Lock()
+		defer controller.mu.Unlock()
+		return controller.latest.aggregator.Raw() != ""
+	})
+	clock.Advance(10 * time.Second)
+	clock.waitForTimers(t, 1)
+	if !controller.Status().Active {
+		t.Fatalf("expected the final to keep the session running")
+	}
+
+	clock.Advance(20 * time.Second)
+	waitFor(t, func() bool { return !controller.Status().Active })
+	events.mu.Lock()
+	defer events.mu.Unlock()
+	if len(events.finals) != 1 || events.finals[0].raw != "hello there" {
+		t.Fatalf("expected the transcript kept, got %+v", events.finals)
+	}
+	if last := events.states[len(events.states)-1]; last.reason == domain.SessionReasonIdleTimeout {
+		t.Fatalf("expected the session stopped rather than discarded, got %+v", last)
+	}
+}
+
+func TestIdleWatchdogCountsSpeechLevelAudio(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	watchdog := newIdleWatchdog(IdleAbortConfig{Timeout: time.Minute, Threshold: 1000}, clock)
+	quiet := make([]byte, 320)
+	loud := make([]byte, 320)
+	for i := 0; i < len(loud); i += 2 {
+		binary.LittleEndian.PutUint16(quiet[i:], uint16(200))
+		binary.LittleEndian.PutUint16(loud[i:], uint16(8000))
+	}
+	audio := watchdog.audio(&fakeAudioSession{chunks: [][]byte{quiet, loud}})
+	started := clock.Now()
+	buf := make([]byte, 320)
+
+	clock.Advance(time.Second)
+	if _, err := audio.Read(buf); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if !watchdog.last.Equal(started) {
+		t.Fatalf("expected quiet audio to leave the session idle")
+	}
+	if _, err := audio.Read(buf); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if !watchdog.last.Equal(clock.Now()) {
+		t.Fatalf("expected loud audio to count as activity")
+	}
+}
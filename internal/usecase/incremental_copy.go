@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// IncrementalCopyConfig copies each utterance as the provider marks it
+// speech-final instead of waiting for Stop. Utterances finished less than
+// Interval after the previous copy are joined into the next one, so a fast
+// speaker does not flood the clipboard and its listeners.
+type IncrementalCopyConfig struct {
+	Enabled  bool
+	Interval time.Duration
+}
+
+// incrementalCopier delivers the utterances of one session in order on a
+// single worker. Stop flushes the utterance still being spoken through the
+// same worker, so the final flush never repeats or reorders text that was
+// already copied. A failed copy stops the worker, and Stop copies the whole
+// transcript instead, so no utterance is lost from the clipboard.
+type incrementalCopier struct {
+	deliver  func(ctx context.Context, text string, first bool) error
+	clock    ports.Clock
+	interval time.Duration
+
+	mu        sync.Mutex
+	utterance []string
+	pending   []string
+	copied    int
+	failed    bool
+	closed    bool
+	discarded bool
+	wake      chan struct{}
+	done      chan struct{}
+	ctx       context.Context
+	cancel    context.CancelFunc
+}
+
+func newIncrementalCopier(cfg IncrementalCopyConfig, clock ports.Clock, deliver func(ctx context.Context, text string, first bool) error) *incrementalCopier {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &incrementalCopier{
+		deliver:  deliver,
+		clock:    clock,
+		interval: cfg.Interval,
+		wake:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	go c.run()
+	return c
+}
+
+// stream tees the final segments of session into the copier.
+func (c *incrementalCopier) stream(session ports.StreamingSession) ports.StreamingSession {
+	s := &incrementalStream{StreamingSession: session, events: make(chan domain.TranscriptEvent, 64)}
+	go func() {
+		defer close(s.events)
+		for event := range session.Events() {
+			if event.Kind == domain.TranscriptKindFinal {
+				c.add(event)
+			}
+			s.events <- event
+		}
+	}()
+	return s
+}
+
+// add collects a final segment and queues the utterance once the provider
+// marks it speech-final. Deepgram ends utterances with empty events, so the
+// marker counts even without text.
+func (c *incrementalCopier) add(event domain.TranscriptEvent) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return
+	}
+	if text := strings.TrimSpace(event.Text); text != "" {
+		c.utterance = append(c.utterance, text)
+	}
+	queued := event.IsSpeechFinal && c.queueUtterance()
+	c.mu.Unlock()
+	if queued {
+		c.signal()
+	}
+}
+
+// queueUtterance moves the utterance being spoken to the pending copies.
+// Callers hold c.mu.
+func (c *incrementalCopier) queueUtterance() bool {
+	if len(c.utterance) == 0 {
+		return false
+	}
+	c.pending = append(c.pending, strings.Join(c.utterance, " "))
+	c.utterance = nil
+	return true
+}
+
+func (c *incrementalCopier) signal() {
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Close queues the unfinished utterance, copies everything pending without
+// waiting out the interval, and stops the worker. It reports whether the
+// whole transcript was copied; when a copy failed or ctx ends first, the
+// rest is discarded and the caller copies the transcript again.
+func (c *incrementalCopier) Close(ctx context.Context) bool {
+	c.mu.Lock()
+	c.closed = true
+	c.queueUtterance()
+	c.mu.Unlock()
+	c.signal()
+
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		c.Discard()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.copied > 0 && !c.failed && !c.discarded
+}
+
+// Discard stops the worker at once, dropping pending utterances.
+func (c *incrementalCopier) Discard() {
+	c.mu.Lock()
+	c.closed = true
+	c.discarded = true
+	c.pending = nil
+	c.utterance = nil
+	c.mu.Unlock()
+	c.cancel()
+	c.signal()
+	<-c.done
+}
+
+func (c *incrementalCopier) run() {
+	defer close(c.done)
+	defer c.cancel()
+
+	var last time.Time
+	for {
+		c.mu.Lock()
+		pending, closed, discarded := len(c.pending) > 0, c.closed, c.discarded
+		c.mu.Unlock()
+		if discarded || (closed && !pending) {
+			return
+		}
+		if !pending {
+			<-c.wake
+			continue
+		}
+		if wait := c.interval - c.clock.Now().Sub(last); !closed && !last.IsZero() && wait > 0 {
+			// Utterances finished meanwhile join this copy; Close copies at
+			// once.
+			timer := c.clock.NewTimer(wait)
+			select {
+			case <-timer.C():
+			case <-c.wake:
+				timer.Stop()
+			}
+			continue
+		}
+
+		c.mu.Lock()
+		text := strings.Join(c.pending, " ")
+		c.pending = nil
+		first := c.copied == 0
+		c.mu.Unlock()
+		if err := c.deliver(c.ctx, text, first); err != nil {
+			debuglog.Printf("incremental copy failed: %v", err)
+			c.mu.Lock()
+			c.failed = true
+			c.closed = true
+			c.pending = nil
+			c.utterance = nil
+			c.mu.Unlock()
+			return
+		}
+		last = c.clock.Now()
+		c.mu.Lock()
+		c.copied++
+		c.mu.Unlock()
+	}
+}
+
+type incrementalStream struct {
+	ports.StreamingSession
+	events chan domain.TranscriptEvent
+}
+
+func (s *incrementalStream) Events() <-chan domain.TranscriptEvent {
+	return s.events
+}
+
+// Finalize flushes the provider stream when it supports finalizing.
+func (s *incrementalStream) Finalize(ctx context.Context) error {
+	if finalizer, ok := s.StreamingSession.(ports.StreamFinalizer); ok {
+		return finalizer.Finalize(ctx)
+	}
+	return errors.ErrUnsupported
+}
+
+// plainOutput reports whether transcripts are copied as plain text, the only
+// output delivered incrementally: form pieces and output targets need the
+// whole transcript.
+func (f *transcriptFinalizer) plainOutput() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.form == nil && f.target == nil
+}
+
+// copySegment copies one utterance after the rules, casing and clean-up a
+// plain transcript gets, and announces the copy. Segments after the first
+// start with a space, so pasting them one after another reads as one text.
+func (f *transcriptFinalizer) copySegment(ctx context.Context, sessionID string, raw string, language string, temporary ports.RulesEngine, first bool) error {
+	rules, err := f.sessionRules(language, temporary)
+	var text string
+	if err == nil {
+		text, err = rules.Apply(raw)
+	}
+	if err != nil {
+		f.events.SessionError(domain.ErrorCodeRules, err.Error())
+		return err
+	}
+
+	f.mu.Lock()
+	copies := f.copies
+	casing := f.casing
+	normalize := f.normalize
+	f.mu.Unlock()
+
+	if policy := casing.policyFor(clipboardTargetName); policy != CasingPreserve {
+		text = applyCasing(policy, text, knownWords(rules, casing.Words))
+	}
+	text = normalizeText(normalize.optionsFor(clipboardTargetName), text)
+	if !first {
+		text = " " + text
+	}
+	if err := f.clipboard.SetText(ctx, text); err != nil {
+		f.events.SessionError(domain.ErrorCodeClipboard, "transcript segment ready but clipboard write failed")
+		return err
+	}
+	if copies != nil {
+		err := copies.TranscriptCopied(ctx, domain.CopyEvent{
+			SessionID:     sessionID,
+			Text:          text,
+			RawTranscript: raw,
+			CopiedAt:      time.Now(),
+		})
+		if err != nil {
+			f.events.SessionError(domain.ErrorCodeCopyHook, err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestSessionControllerIncrementalCopy(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	stream := newFakeStreamingSession()
+	clipboard := &recordingClipboard{}
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{}}},
+		&fakeProvider{sessions: []ports.StreamingSession{stream}},
+		&fakeRules{},
+		clipboard,
+		&fakeEventSink{},
+		Config{ChunkSize: 512, Clock: clock, IncrementalCopy: IncrementalCopyConfig{Enabled: true, Interval: time.Second}},
+	)
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	copies := func() []string {
+		clipboard.mu.Lock()
+		defer clipboard.mu.Unlock()
+		return append([]string(nil), clipboard.texts...)
+	}
+	final := func(text string, speechFinal bool) {
+		stream.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: text, IsSpeechFinal: speechFinal}
+	}
+
+	final("hello there", false)
+	final("everyone", true)
+	waitFor(t, func() bool { return len(copies()) == 1 })
+
+	// Within the interval, utterances wait and are joined into one copy.
+	final("second line", true)
+	final("third", true)
+	copier := controller.latest.copier
+	waitFor(t, func() bool {
+		copier.mu.Lock()
+		defer copier.mu.Unlock()
+		return len(copier.pending) == 2
+	})
+	clock.Advance(time.Second)
+	waitFor(t, func() bool { return len(copies()) == 2 })
+
+	// Stop copies only the utterance still being spoken.
+	final("tail", false)
+	result, err := controller.Stop(context.Background())
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	want := []string{"hello there everyone", " second line third", " tail"}
+	if got := copies(); !slices.Equal(got, want) {
+		t.Fatalf("expected copies %q, got %q", want, got)
+	}
+	if result.FinalTranscript != "hello there everyone second line third tail" || !result.Copied {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
+
+func TestSessionControllerIncrementalCopyNeedsPlainOutput(t *testing.T) {
+	t.Parallel()
+
+	stream := newFakeStreamingSession()
+	stream.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "fix the parser", IsSpeechFinal: true}
+	clipboard := &recordingClipboard{}
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{}}},
+		&fakeProvider{sessions: []ports.StreamingSession{stream}},
+		&fakeRules{},
+		clipboard,
+		&fakeEventSink{},
+		Config{ChunkSize: 512, IncrementalCopy: IncrementalCopyConfig{Enabled: true}},
+	)
+	controller.SetTarget(&fakeOutputTarget{})
+
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if controller.latest.copier != nil {
+		t.Fatalf("expected no incremental copy with an output target")
+	}
+	if _, err := controller.Stop(context.Background()); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if len(clipboard.texts) != 1 {
+		t.Fatalf("expected one copy on stop, got %q", clipboard.texts)
+	}
+}
+
+func TestSessionControllerIncrementalCopyFailureCopiesWholeTranscript(t *testing.T) {
+	t.Parallel()
+
+	stream := newFakeStreamingSession()
+	clipboard := &recordingClipboard{failures: 1}
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{}}},
+		&fakeProvider{sessions: []ports.StreamingSession{stream}},
+		&fakeRules{},
+		clipboard,
+		&fakeEventSink{},
+		Config{ChunkSize: 512, IncrementalCopy: IncrementalCopyConfig{Enabled: true}},
+	)
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	copier := controller.latest.copier
+	stream.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "first line", IsSpeechFinal: true}
+	waitFor(t, func() bool {
+		copier.mu.Lock()
+		defer copier.mu.Unlock()
+		return copier.failed
+	})
+	stream.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "second line", IsSpeechFinal: true}
+
+	result, err := controller.Stop(context.Background())
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	want := []string{"first line second line"}
+	if !slices.Equal(clipboard.texts, want) || !result.Copied {
+		t.Fatalf("expected the whole transcript copied on stop, got %q (%+v)", clipboard.texts, result)
+	}
+}
+
+func TestSessionControllerIncrementalCopyWithAccuratePass(t *testing.T) {
+	t.Parallel()
+
+	live := newFakeStreamingSession()
+	accurate := newFakeStreamingSession()
+	accurate.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "Ship it Friday."}
+	clipboard := &recordingClipboard{}
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{chunks: [][]byte{[]byte("pcm")}}}},
+		&fakeProvider{sessions: []ports.StreamingSession{live}},
+		&fakeRules{},
+		clipboard,
+		&fakeEventSink{},
+		Config{ChunkSize: 512, IncrementalCopy: IncrementalCopyConfig{Enabled: true}},
+	)
+	controller.SetAccurateProvider(&fakeProvider{sessions: []ports.StreamingSession{accurate}})
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	live.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "ship it friday", IsSpeechFinal: true}
+	waitFor(t, func() bool {
+		clipboard.mu.Lock()
+		defer clipboard.mu.Unlock()
+		return len(clipboard.texts) == 1
+	})
+
+	result, err := controller.Stop(context.Background())
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	// The accurate transcript replaces the live copy.
+	want := []string{"ship it friday", "Ship it Friday."}
+	if !slices.Equal(clipboard.texts, want) || result.FinalTranscript != "Ship it Friday." {
+		t.Fatalf("expected the accurate transcript copied whole, got %q (%+v)", clipboard.texts, result)
+	}
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// IssueTarget turns a dictation into an issue draft: the first sentence is
+// the title and the rest the body. Nothing is created until the draft is
+// confirmed, so a misheard dictation never reaches the tracker.
+type IssueTarget struct {
+	tracker ports.IssueTracker
+	sink    ports.IssueSink
+
+	mu      sync.Mutex
+	pending *domain.IssueDraft
+}
+
+func NewIssueTarget(tracker ports.IssueTracker, sink ports.IssueSink) *IssueTarget {
+	return &IssueTarget{tracker: tracker, sink: sink}
+}
+
+func (t *IssueTarget) Name() string {
+	return t.tracker.Name() + "-issue"
+}
+
+func (t *IssueTarget) Format(text string) (string, error) {
+	return strings.TrimSpace(text), nil
+}
+
+// Deliver replaces any pending draft with one built from text.
+func (t *IssueTarget) Deliver(_ context.Context, text string) error {
+	draft, err := t.draft(text)
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	t.pending = &draft
+	t.mu.Unlock()
+	t.sink.IssueDraftReady(draft)
+	return nil
+}
+
+// Pending returns the draft awaiting confirmation.
+func (t *IssueTarget) Pending() (domain.IssueDraft, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pending == nil {
+		return domain.IssueDraft{}, false
+	}
+	return *t.pending, true
+}
+
+// Confirm creates the pending draft. The draft stays pending when creation
+// fails so it can be confirmed again.
+func (t *IssueTarget) Confirm(ctx context.Context) (domain.Issue, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pending == nil {
+		return domain.Issue{}, domain.ErrNoIssueDraft
+	}
+	issue, err := t.tracker.CreateIssue(ctx, *t.pending)
+	if err != nil {
+		return domain.Issue{}, err
+	}
+	t.pending = nil
+	t.sink.IssueCreated(issue)
+	return issue, nil
+}
+
+// Discard drops the pending draft.
+func (t *IssueTarget) Discard() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pending == nil {
+		return domain.ErrNoIssueDraft
+	}
+	t.pending = nil
+	return nil
+}
+
+func (t *IssueTarget) draft(text string) (domain.IssueDraft, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return domain.IssueDraft{}, fmt.Errorf("empty dictation cannot become an issue")
+	}
+	firstLine, _, _ := strings.Cut(text, "\n")
+	// The first sentence is a prefix of text, so the body keeps its line breaks.
+	title := splitSentences(firstLine)[0]
+	return domain.IssueDraft{
+		Tracker: t.tracker.Name(),
+		Project: t.tracker.Project(),
+		Title:   strings.TrimRight(strings.TrimSpace(title), "."),
+		Body:    strings.TrimSpace(text[len(title):]),
+	}, nil
+}
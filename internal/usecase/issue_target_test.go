@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"coldmic/internal/domain"
+)
+
+type fakeIssueTracker struct {
+	err    error
+	drafts []domain.IssueDraft
+}
+
+func (f *fakeIssueTracker) Name() string    { return "github" }
+func (f *fakeIssueTracker) Project() string { return "acme/app" }
+
+func (f *fakeIssueTracker) CreateIssue(_ context.Context, draft domain.IssueDraft) (domain.Issue, error) {
+	f.drafts = append(f.drafts, draft)
+	if f.err != nil {
+		return domain.Issue{}, f.err
+	}
+	return domain.Issue{Tracker: "github", Key: "acme/app#7", Title: draft.Title, URL: "https://github.com/acme/app/issues/7"}, nil
+}
+
+type fakeIssueSink struct {
+	mu      sync.Mutex
+	drafts  []domain.IssueDraft
+	created []domain.Issue
+}
+
+func (f *fakeIssueSink) IssueDraftReady(draft domain.IssueDraft) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.drafts = append(f.drafts, draft)
+}
+
+func (f *fakeIssueSink) IssueCreated(issue domain.Issue) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.created = append(f.created, issue)
+}
+
+func TestIssueTargetCreatesOnlyAfterConfirmation(t *testing.T) {
+	t.Parallel()
+
+	tracker := &fakeIssueTracker{}
+	sink := &fakeIssueSink{}
+	target := NewIssueTarget(tracker, sink)
+	if target.Name() != "github-issue" {
+		t.Fatalf("unexpected target name %q", target.Name())
+	}
+
+	if err := target.Deliver(context.Background(), "Export fails on Safari. Clicking export does nothing.\nNo console errors."); err != nil {
+		t.Fatalf("deliver failed: %v", err)
+	}
+	want := domain.IssueDraft{Tracker: "github", Project: "acme/app", Title: "Export fails on Safari", Body: "Clicking export does nothing.\nNo console errors."}
+	if draft, ok := target.Pending(); !ok || draft != want {
+		t.Fatalf("unexpected pending draft: %+v", draft)
+	}
+	if len(sink.drafts) != 1 || len(tracker.drafts) != 0 {
+		t.Fatalf("expected a draft event and no issue before confirmation")
+	}
+
+	issue, err := target.Confirm(context.Background())
+	if err != nil {
+		t.Fatalf("confirm failed: %v", err)
+	}
+	if issue.URL != "https://github.com/acme/app/issues/7" || len(sink.created) != 1 || sink.created[0] != issue {
+		t.Fatalf("unexpected created issue: %+v events=%+v", issue, sink.created)
+	}
+	if _, err := target.Confirm(context.Background()); !errors.Is(err, domain.ErrNoIssueDraft) {
+		t.Fatalf("expected no pending draft after confirmation, got %v", err)
+	}
+}
+
+func TestIssueTargetKeepsDraftWhenCreationFails(t *testing.T) {
+	t.Parallel()
+
+	tracker := &fakeIssueTracker{err: errors.New("bad credentials")}
+	target := NewIssueTarget(tracker, &fakeIssueSink{})
+	if err := target.Deliver(context.Background(), "Single sentence title"); err != nil {
+		t.Fatalf("deliver failed: %v", err)
+	}
+	if _, err := target.Confirm(context.Background()); err == nil {
+		t.Fatalf("expected creation error")
+	}
+	draft, ok := target.Pending()
+	if !ok || draft.Title != "Single sentence title" || draft.Body != "" {
+		t.Fatalf("expected draft to stay pending, got %+v", draft)
+	}
+	if err := target.Discard(); err != nil {
+		t.Fatalf("discard failed: %v", err)
+	}
+	if err := target.Discard(); !errors.Is(err, domain.ErrNoIssueDraft) {
+		t.Fatalf("expected nothing to discard, got %v", err)
+	}
+	if err := target.Deliver(context.Background(), "  "); err == nil {
+		t.Fatalf("expected empty dictation to be rejected")
+	}
+}
@@ -0,0 +1,393 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// LocalRedactionMode selects what replaces sensitive audio before upload.
+type LocalRedactionMode string
+
+const (
+	LocalRedactionOff   LocalRedactionMode = "off"
+	LocalRedactionBleep LocalRedactionMode = "bleep"
+	LocalRedactionSkip  LocalRedactionMode = "skip"
+)
+
+// LocalRedactionConfig controls local screening of audio before it is sent
+// to a cloud provider.
+type LocalRedactionConfig struct {
+	Mode LocalRedactionMode
+	// Segment is how much audio is screened at once; it is also the minimum
+	// delay before audio reaches the provider.
+	Segment time.Duration
+	// MinDigits is the shortest spoken digit run treated as sensitive, such
+	// as a card or account number.
+	MinDigits int
+}
+
+// LocalRedactingProvider screens audio with a local transcriber before it
+// reaches the wrapped provider. Segments that hold part of a long spoken
+// digit run are bleeped or dropped, so card numbers never leave the machine.
+type LocalRedactingProvider struct {
+	provider ports.TranscriptionProvider
+	screen   ports.ClipTranscriber
+	cfg      LocalRedactionConfig
+}
+
+func NewLocalRedactingProvider(provider ports.TranscriptionProvider, screen ports.ClipTranscriber, cfg LocalRedactionConfig) *LocalRedactingProvider {
+	if cfg.Mode == "" {
+		cfg.Mode = LocalRedactionBleep
+	}
+	if cfg.Segment <= 0 {
+		cfg.Segment = 3 * time.Second
+	}
+	if cfg.MinDigits <= 0 {
+		cfg.MinDigits = 12
+	}
+	return &LocalRedactingProvider{provider: provider, screen: screen, cfg: cfg}
+}
+
+// Capabilities reports the wrapped provider's capabilities; screening only
+// delays audio.
+func (p *LocalRedactingProvider) Capabilities() domain.Capabilities {
+	if reporter, ok := p.provider.(ports.CapabilityReporter); ok {
+		return reporter.Capabilities()
+	}
+	return domain.Capabilities{}
+}
+
+// ProviderNames reports the wrapped provider; screening runs locally.
+func (p *LocalRedactingProvider) ProviderNames() []string {
+	return providerNames(p.provider)
+}
+
+func (p *LocalRedactingProvider) StartStreaming(ctx context.Context, cfg ports.StreamingConfig) (ports.StreamingSession, error) {
+	inner, err := p.provider.StartStreaming(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.SampleRate <= 0 {
+		cfg.SampleRate = 16000
+	}
+	if cfg.Channels <= 0 {
+		cfg.Channels = 1
+	}
+	frame := cfg.Channels * 2
+	s := &redactingSession{
+		inner:    inner,
+		screen:   p.screen,
+		cfg:      p.cfg,
+		stream:   cfg,
+		ctx:      context.WithoutCancel(ctx),
+		segment:  max(int(p.cfg.Segment*time.Duration(cfg.SampleRate)/time.Second)*frame, frame),
+		segments: make(chan screenJob, 64),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// screenJob is a segment of audio for run to screen, or, when flushed is
+// set, a request to release everything held so far and close flushed.
+type screenJob struct {
+	pcm     []byte
+	flushed chan struct{}
+}
+
+// heldSegment is screened audio waiting for a digit run to be decided.
+type heldSegment struct {
+	pcm       []byte
+	sensitive bool
+}
+
+type redactingSession struct {
+	inner   ports.StreamingSession
+	screen  ports.ClipTranscriber
+	cfg     LocalRedactionConfig
+	stream  ports.StreamingConfig
+	ctx     context.Context
+	segment int
+
+	mu       sync.Mutex
+	pending  []byte
+	closed   bool
+	segments chan screenJob
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+	err      error
+
+	// Screening state, owned by run.
+	held      []heldSegment
+	runDigits int
+	runStart  int
+}
+
+func (s *redactingSession) SendAudio(chunk []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errors.New("audio stream is already closed")
+	}
+	s.pending = append(s.pending, chunk...)
+	for len(s.pending) >= s.segment {
+		segment := s.pending[:s.segment:s.segment]
+		s.pending = s.pending[s.segment:]
+		if err := s.queue(screenJob{pcm: segment}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queue hands job to run; callers hold s.mu.
+func (s *redactingSession) queue(job screenJob) error {
+	select {
+	case s.segments <- job:
+		return nil
+	case <-s.done:
+		if s.err != nil {
+			return s.err
+		}
+		return errors.New("session closed")
+	}
+}
+
+// Finalize screens and forwards the audio sent so far, then finalizes the
+// provider stream when it supports finalizing. A digit run still being
+// spoken is decided as if the audio ended here.
+func (s *redactingSession) Finalize(ctx context.Context) error {
+	finalizer, ok := s.inner.(ports.StreamFinalizer)
+	if !ok {
+		return errors.ErrUnsupported
+	}
+	flushed := make(chan struct{})
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return errors.New("audio stream is already closed")
+	}
+	var err error
+	if len(s.pending) > 0 {
+		err = s.queue(screenJob{pcm: s.pending})
+		s.pending = nil
+	}
+	if err == nil {
+		err = s.queue(screenJob{flushed: flushed})
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-flushed:
+	case <-s.done:
+		if s.err != nil {
+			return s.err
+		}
+		return errors.New("session closed")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return finalizer.Finalize(ctx)
+}
+
+// OnReconnect forwards fn to the provider stream when it reconnects.
+func (s *redactingSession) OnReconnect(fn func(err error)) {
+	if notifier, ok := s.inner.(ports.ReconnectNotifier); ok {
+		notifier.OnReconnect(fn)
+	}
+}
+
+// CloseSend screens the remaining audio and then ends the provider stream.
+func (s *redactingSession) CloseSend() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if len(s.pending) > 0 {
+		_ = s.queue(screenJob{pcm: s.pending})
+		s.pending = nil
+	}
+	close(s.segments)
+	return nil
+}
+
+func (s *redactingSession) Events() <-chan domain.TranscriptEvent {
+	return s.inner.Events()
+}
+
+func (s *redactingSession) Wait() error {
+	<-s.done
+	if s.err != nil {
+		return s.err
+	}
+	return s.inner.Wait()
+}
+
+func (s *redactingSession) Close() error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	err := s.inner.Close()
+	<-s.done
+	return err
+}
+
+func (s *redactingSession) run() {
+	defer close(s.done)
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case job, ok := <-s.segments:
+			if !ok {
+				s.endRun()
+				if s.release(len(s.held)) {
+					_ = s.inner.CloseSend()
+				}
+				return
+			}
+			if job.flushed != nil {
+				s.endRun()
+				if !s.release(len(s.held)) {
+					return
+				}
+				close(job.flushed)
+				continue
+			}
+			pcm := job.pcm
+			text, err := s.screen.TranscribeClip(s.ctx, s.stream, pcm)
+			if err != nil {
+				// Without a screening result the audio cannot be shown to be
+				// safe, so none of it is uploaded.
+				debuglog.Printf("local redaction screen failed: %v", err)
+				s.err = errors.New("local redaction failed: " + err.Error())
+				_ = s.inner.Close()
+				return
+			}
+			s.screenSegment(pcm, text)
+			decided := len(s.held)
+			if s.runDigits > 0 && s.runDigits < s.cfg.MinDigits {
+				decided = s.runStart
+			}
+			if !s.release(decided) {
+				return
+			}
+		}
+	}
+}
+
+// screenSegment holds pcm and tracks the spoken digit run in its text, which
+// may continue from earlier segments.
+func (s *redactingSession) screenSegment(pcm []byte, text string) {
+	s.held = append(s.held, heldSegment{pcm: pcm})
+	index := len(s.held) - 1
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		s.endRun()
+		return
+	}
+	for _, word := range words {
+		digits := spokenDigits(word)
+		if digits == 0 {
+			s.endRun()
+			continue
+		}
+		if s.runDigits == 0 {
+			s.runStart = index
+		}
+		s.runDigits += digits
+		if s.runDigits >= s.cfg.MinDigits {
+			s.markRun(index)
+		}
+	}
+}
+
+// endRun closes the digit run; a long enough run was marked as it grew.
+func (s *redactingSession) endRun() {
+	s.runDigits = 0
+}
+
+func (s *redactingSession) markRun(through int) {
+	for i := s.runStart; i <= through && i < len(s.held); i++ {
+		s.held[i].sensitive = true
+	}
+}
+
+// release forwards the first n held segments, redacting sensitive ones.
+func (s *redactingSession) release(n int) bool {
+	for _, segment := range s.held[:n] {
+		pcm := segment.pcm
+		if segment.sensitive {
+			debuglog.Printf("local redaction %s bytes=%d", s.cfg.Mode, len(pcm))
+			if s.cfg.Mode == LocalRedactionSkip {
+				continue
+			}
+			pcm = bleep(s.stream, len(pcm))
+		}
+		if err := s.inner.SendAudio(pcm); err != nil {
+			s.err = err
+			return false
+		}
+	}
+	s.held = append(s.held[:0], s.held[n:]...)
+	s.runStart = max(s.runStart-n, 0)
+	return true
+}
+
+// bleep returns size bytes of a quiet 1 kHz tone in the stream's format.
+func bleep(cfg ports.StreamingConfig, size int) []byte {
+	out := make([]byte, size)
+	frame := cfg.Channels * 2
+	for i := 0; i+frame <= size; i += frame {
+		t := float64(i/frame) / float64(cfg.SampleRate)
+		sample := int16(4000 * math.Sin(2*math.Pi*1000*t))
+		for c := 0; c < cfg.Channels; c++ {
+			out[i+c*2] = byte(sample)
+			out[i+c*2+1] = byte(sample >> 8)
+		}
+	}
+	return out
+}
+
+var digitWords = map[string]int{
+	"zero": 1, "oh": 1, "o": 1, "one": 1, "two": 1, "three": 1, "four": 1,
+	"five": 1, "six": 1, "seven": 1, "eight": 1, "nine": 1,
+}
+
+// spokenDigits counts the digits a transcribed word stands for: written
+// digit groups such as "4111-1111" or spoken ones such as "four".
+func spokenDigits(word string) int {
+	word = strings.ToLower(strings.TrimFunc(word, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}))
+	if n, ok := digitWords[word]; ok {
+		return n
+	}
+	count := 0
+	for _, r := range word {
+		switch {
+		case unicode.IsDigit(r):
+			count++
+		case r == '-' || r == '.' || r == ',':
+		default:
+			return 0
+		}
+	}
+	return count
+}
@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestLocalRedactingProviderBleepsDigitRuns(t *testing.T) {
+	t.Parallel()
+
+	for _, mode := range []LocalRedactionMode{LocalRedactionBleep, LocalRedactionSkip} {
+		inner := &recordingProvider{}
+		screen := &scriptedClips{texts: []string{"call me at", "4111 1111", "1111 1111.", "thanks"}}
+		provider := NewLocalRedactingProvider(inner, screen, LocalRedactionConfig{Mode: mode, Segment: 100 * time.Millisecond, MinDigits: 12})
+
+		session, err := provider.StartStreaming(context.Background(), ports.StreamingConfig{SampleRate: 16000, Channels: 1})
+		if err != nil {
+			t.Fatalf("start failed: %v", err)
+		}
+		for fill := byte(1); fill <= 4; fill++ {
+			if err := session.SendAudio(bytes.Repeat([]byte{fill}, 3200)); err != nil {
+				t.Fatalf("send failed: %v", err)
+			}
+		}
+		_ = session.CloseSend()
+		if err := session.Wait(); err != nil {
+			t.Fatalf("wait failed: %v", err)
+		}
+
+		got := inner.session.sent
+		if mode == LocalRedactionSkip {
+			if len(got) != 2 || got[0][0] != 1 || got[1][0] != 4 {
+				t.Fatalf("expected digit segments to be skipped, got %d segments", len(got))
+			}
+			continue
+		}
+		if len(got) != 4 || got[0][0] != 1 || got[3][0] != 4 {
+			t.Fatalf("expected four segments, got %d", len(got))
+		}
+		for i, fill := range []byte{2, 3} {
+			if segment := got[i+1]; len(segment) != 3200 || bytes.Equal(segment, bytes.Repeat([]byte{fill}, 3200)) {
+				t.Fatalf("expected digit segment %d to be bleeped", i+1)
+			}
+		}
+		if !inner.session.closedSend {
+			t.Fatalf("expected provider stream to be closed")
+		}
+	}
+}
+
+func TestLocalRedactingProviderForwardsShortNumbers(t *testing.T) {
+	t.Parallel()
+
+	inner := &recordingProvider{}
+	screen := &scriptedClips{texts: []string{"room 4 0 4", "at 3 pm"}}
+	provider := NewLocalRedactingProvider(inner, screen, LocalRedactionConfig{Segment: 100 * time.Millisecond})
+	session, _ := provider.StartStreaming(context.Background(), ports.StreamingConfig{SampleRate: 16000, Channels: 1})
+	_ = session.SendAudio(bytes.Repeat([]byte{1}, 3200))
+	_ = session.SendAudio(bytes.Repeat([]byte{2}, 1600))
+	_ = session.CloseSend()
+	if err := session.Wait(); err != nil {
+		t.Fatalf("wait failed: %v", err)
+	}
+	got := inner.session.sent
+	if len(got) != 2 || got[0][0] != 1 || len(got[1]) != 1600 || got[1][0] != 2 {
+		t.Fatalf("expected audio to pass unchanged, got %d segments", len(got))
+	}
+}
+
+func TestLocalRedactingProviderFailsClosed(t *testing.T) {
+	t.Parallel()
+
+	inner := &recordingProvider{}
+	screen := &scriptedClips{err: errors.New("model missing")}
+	provider := NewLocalRedactingProvider(inner, screen, LocalRedactionConfig{Segment: 100 * time.Millisecond})
+	session, _ := provider.StartStreaming(context.Background(), ports.StreamingConfig{SampleRate: 16000, Channels: 1})
+	_ = session.SendAudio(make([]byte, 3200))
+	_ = session.CloseSend()
+	if err := session.Wait(); err == nil {
+		t.Fatalf("expected screening error")
+	}
+	if len(inner.session.sent) != 0 {
+		t.Fatalf("expected no audio to be uploaded")
+	}
+}
+
+func TestLocalRedactingProviderFinalizeForwardsHeldAudio(t *testing.T) {
+	t.Parallel()
+
+	inner := &recordingProvider{}
+	screen := &scriptedClips{texts: []string{"call me at", "4111 1111"}}
+	provider := NewLocalRedactingProvider(inner, screen, LocalRedactionConfig{Segment: 100 * time.Millisecond, MinDigits: 12})
+
+	session, err := provider.StartStreaming(context.Background(), ports.StreamingConfig{SampleRate: 16000, Channels: 1})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	// The digit run is held until it is decided, and the tail is shorter
+	// than a segment.
+	for _, chunk := range [][]byte{bytes.Repeat([]byte{1}, 3200), bytes.Repeat([]byte{2}, 3200), bytes.Repeat([]byte{3}, 100)} {
+		if err := session.SendAudio(chunk); err != nil {
+			t.Fatalf("send failed: %v", err)
+		}
+	}
+	if err := session.(ports.StreamFinalizer).Finalize(context.Background()); err != nil {
+		t.Fatalf("finalize failed: %v", err)
+	}
+	if got := inner.session.finalizedAt; got != 3 {
+		t.Fatalf("expected all three segments forwarded before finalizing, got %d", got)
+	}
+	_ = session.CloseSend()
+	if err := session.Wait(); err != nil {
+		t.Fatalf("wait failed: %v", err)
+	}
+}
+
+func TestSpokenDigits(t *testing.T) {
+	t.Parallel()
+
+	for word, want := range map[string]int{"4111-1111,": 8, "Four": 1, "oh.": 1, "4th": 0, "hello": 0, "2,500": 4} {
+		if got := spokenDigits(word); got != want {
+			t.Fatalf("spokenDigits(%q) = %d, want %d", word, got, want)
+		}
+	}
+}
+
+type scriptedClips struct {
+	mu    sync.Mutex
+	texts []string
+	err   error
+}
+
+func (s *scriptedClips) TranscribeClip(_ context.Context, _ ports.StreamingConfig, _ []byte) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return "", s.err
+	}
+	if len(s.texts) == 0 {
+		return "", nil
+	}
+	text := s.texts[0]
+	s.texts = s.texts[1:]
+	return text, nil
+}
+
+type recordingProvider struct {
+	session *recordingSession
+}
+
+func (p *recordingProvider) StartStreaming(_ context.Context, _ ports.StreamingConfig) (ports.StreamingSession, error) {
+	p.session = &recordingSession{events: make(chan domain.TranscriptEvent)}
+	return p.session, nil
+}
+
+type recordingSession struct {
+	sent       [][]byte
+	closedSend bool
+	// finalizedAt is how many chunks were sent when Finalize was called.
+	finalizedAt int
+	events      chan domain.TranscriptEvent
+}
+
+func (s *recordingSession) SendAudio(chunk []byte) error {
+	s.sent = append(s.sent, append([]byte(nil), chunk...))
+	return nil
+}
+
+func (s *recordingSession) CloseSend() error {
+	s.closedSend = true
+	return nil
+}
+
+func (s *recordingSession) Finalize(context.Context) error {
+	s.finalizedAt = len(s.sent)
+	return nil
+}
+
+func (s *recordingSession) Events() <-chan domain.TranscriptEvent { return s.events }
+func (s *recordingSession) Wait() error                           { return nil }
+func (s *recordingSession) Close() error                          { return nil }
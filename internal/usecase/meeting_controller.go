@@ -0,0 +1,449 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// MeetingTrack is one labeled capture source in meeting mode.
+type MeetingTrack struct {
+	Label string
+	Audio ports.AudioConfig
+}
+
+// MeetingConfig controls multi-track meeting capture.
+type MeetingConfig struct {
+	Tracks         []MeetingTrack
+	Streaming      ports.StreamingConfig
+	ChunkSize      int
+	StreamingGrace time.Duration
+	Timestamps     TimestampConfig
+	DiskGuard      DiskGuardConfig
+	// Language is the configured transcript language, which selects
+	// per-language rules.
+	Language string
+	// Clock times the grace period and stream timeouts; nil uses the wall
+	// clock.
+	Clock ports.Clock
+}
+
+// MeetingController records several sources as separate provider streams and
+// interleaves their finals into a dialogue transcript.
+type MeetingController struct {
+	audio     ports.AudioCapture
+	provider  ports.TranscriptionProvider
+	events    ports.EventSink
+	rules     ports.RulesEngine
+	finalizer *transcriptFinalizer
+	cfg       MeetingConfig
+
+	recordings ports.RecordingStore
+
+	mu      sync.Mutex
+	current *meetingSession
+	// starting reserves the meeting slot while Start opens the tracks, so a
+	// concurrent Start is refused instead of opening a second meeting.
+	starting bool
+	nextID   uint64
+	// finished keeps the segments of recent meetings for word navigation.
+	finished []finishedMeeting
+}
+
+// maxFinishedMeetings bounds how many past meetings WordOffset can resolve.
+const maxFinishedMeetings = 20
+
+type finishedMeeting struct {
+	id       string
+	segments []domain.DialogueSegment
+}
+
+type meetingSession struct {
+	id        string
+	ctx       context.Context
+	cancel    func()
+	startedAt time.Time
+	tracks    []*meetingTrackSession
+}
+
+type meetingTrackSession struct {
+	label      string
+	audio      ports.AudioSession
+	stream     *rotatingStream
+	saver      *savingAudioSession
+	eventsDone chan struct{}
+	audioDone  chan struct{}
+
+	mu       sync.Mutex
+	segments []domain.DialogueSegment
+}
+
+func NewMeetingController(
+	audio ports.AudioCapture,
+	provider ports.TranscriptionProvider,
+	rules ports.RulesEngine,
+	clipboard ports.Clipboard,
+	events ports.EventSink,
+	cfg MeetingConfig,
+) *MeetingController {
+	if cfg.ChunkSize < 256 {
+		cfg.ChunkSize = 4096
+	}
+	finalizer := newTranscriptFinalizer(rules, clipboard, events)
+	finalizer.clock = cfg.Clock
+	return &MeetingController{
+		audio:     audio,
+		provider:  provider,
+		events:    events,
+		rules:     rules,
+		finalizer: finalizer,
+		cfg:       cfg,
+	}
+}
+
+// SetRecordingStore enables saving each track's audio for meetings started afterwards.
+func (c *MeetingController) SetRecordingStore(store ports.RecordingStore) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recordings = store
+}
+
+// SetCopyListener announces every copied meeting transcript to listener.
+func (c *MeetingController) SetCopyListener(listener ports.CopyListener) {
+	c.finalizer.setCopyListener(listener)
+}
+
+// SetProvider switches the transcription provider. An active meeting opens a
+// new stream per track and hands over at each track's next pause in speech;
+// later meetings start on provider directly. The previous provider stays in
+// use if any replacement stream fails to open.
+func (c *MeetingController) SetProvider(provider ports.TranscriptionProvider) error {
+	c.mu.Lock()
+	meeting := c.current
+	if meeting == nil {
+		c.provider = provider
+		c.mu.Unlock()
+		return nil
+	}
+	c.mu.Unlock()
+
+	replacements := make([]ports.StreamingSession, 0, len(meeting.tracks))
+	for _, track := range meeting.tracks {
+		stream, err := provider.StartStreaming(meeting.ctx, c.cfg.Streaming)
+		if err != nil {
+			for _, opened := range replacements {
+				_ = opened.Close()
+			}
+			return fmt.Errorf("meeting track %q: %w", track.label, err)
+		}
+		replacements = append(replacements, stream)
+	}
+	for i, track := range meeting.tracks {
+		if err := track.stream.Rotate(replacements[i]); err != nil {
+			_ = replacements[i].Close()
+			debuglog.Printf("meeting track %q provider switch skipped: %v", track.label, err)
+		}
+	}
+	c.mu.Lock()
+	c.provider = provider
+	c.mu.Unlock()
+	c.events.SessionStateChanged(domain.SessionStateRecording, domain.SessionReasonProviderSwitched)
+	return nil
+}
+
+// Start opens one capture and provider stream per configured track.
+func (c *MeetingController) Start(ctx context.Context) error {
+	if len(c.cfg.Tracks) == 0 {
+		return errors.New("no meeting tracks configured")
+	}
+
+	c.mu.Lock()
+	if c.current != nil || c.starting {
+		c.mu.Unlock()
+		return errors.New("meeting already in progress")
+	}
+	c.starting = true
+	c.nextID++
+	id := fmt.Sprintf("meeting-%d", c.nextID)
+	recordings := c.recordings
+	provider := c.provider
+	c.mu.Unlock()
+
+	if recordings != nil {
+		if err := checkFreeSpace(recordings, c.cfg.DiskGuard); err != nil {
+			debuglog.Printf("meeting audio saving disabled: %v", err)
+			c.events.SessionError(domain.ErrorCodeDiskSpace, "audio will not be saved: "+err.Error())
+			recordings = nil
+		}
+	}
+
+	sessionCtx, cancel := context.WithCancel(ctx)
+	meeting := &meetingSession{id: id, ctx: sessionCtx, cancel: cancel, startedAt: clockOrSystem(c.cfg.Clock).Now()}
+
+	for _, track := range c.cfg.Tracks {
+		debuglog.Printf("meeting track start label=%q audio_format=%s audio_device=%s", track.Label, track.Audio.InputFormat, track.Audio.InputDevice)
+		stream, err := provider.StartStreaming(sessionCtx, c.cfg.Streaming)
+		if err != nil {
+			c.abandonStart(meeting)
+			return fmt.Errorf("meeting track %q: %w", track.Label, err)
+		}
+		audioSession, err := c.audio.Start(sessionCtx, track.Audio)
+		if err != nil {
+			_ = stream.Close()
+			c.abandonStart(meeting)
+			return fmt.Errorf("meeting track %q: %w", track.Label, err)
+		}
+
+		ts := &meetingTrackSession{
+			label:      track.Label,
+			audio:      audioSession,
+			stream:     newRotatingStream(stream, c.cfg.Streaming, clockOrSystem(c.cfg.Clock)),
+			eventsDone: make(chan struct{}),
+			audioDone:  make(chan struct{}),
+		}
+		if recordings != nil {
+			name := meeting.startedAt.Format("20060102-150405") + "-" + id + "-" + track.Label
+			if writer, err := recordings.Create(name, track.Audio); err != nil {
+				c.events.SessionError(domain.ErrorCodeDiskSpace, fmt.Sprintf("audio for %s will not be saved: %v", track.Label, err))
+			} else {
+				ts.saver = newSavingAudioSession(audioSession, writer, recordings, c.events, c.cfg.DiskGuard)
+				ts.audio = ts.saver
+			}
+		}
+		meeting.tracks = append(meeting.tracks, ts)
+		go ts.consume(c.events, clockOrSystem(c.cfg.Clock), meeting.startedAt)
+		go pumpAudioChunks(ts.audio, ts.stream, c.cfg.ChunkSize, c.events, nil, ts.audioDone)
+	}
+
+	c.mu.Lock()
+	c.current = meeting
+	c.starting = false
+	c.mu.Unlock()
+
+	c.events.SessionStateChanged(domain.SessionStateRecording, domain.SessionReasonMeetingStarted)
+	return nil
+}
+
+// Stop ends the meeting and returns the dialogue-formatted transcript.
+func (c *MeetingController) Stop(ctx context.Context) (domain.StopResult, error) {
+	meeting, err := c.take()
+	if err != nil {
+		return domain.StopResult{}, err
+	}
+
+	c.events.SessionStateChanged(domain.SessionStateStopping, domain.SessionReasonTranscribing)
+	for _, track := range meeting.tracks {
+		if err := track.audio.Stop(); err != nil {
+			c.events.SessionError(domain.ErrorCodeAudioStop, fmt.Sprintf("failed to stop %s capture cleanly", track.label))
+		}
+	}
+
+	clock := clockOrSystem(c.cfg.Clock)
+	if c.cfg.StreamingGrace > 0 {
+		_ = sleepContext(ctx, clock, c.cfg.StreamingGrace)
+	}
+
+	var streamErr error
+	var segments []domain.DialogueSegment
+	for _, track := range meeting.tracks {
+		_ = track.stream.CloseSend()
+		if err := waitForStream(track.stream, clock, 4*time.Second); err != nil && streamErr == nil {
+			streamErr = err
+		}
+		<-track.eventsDone
+		<-track.audioDone
+		track.finishSaving()
+		segments = append(segments, track.snapshot()...)
+	}
+	meeting.cancel()
+
+	sortSegments(segments)
+	dialogue := formatDialogue(segments)
+	if dialogue == "" {
+		if streamErr != nil {
+			c.events.SessionError(domain.ErrorCodeFor(streamErr, domain.ErrorCodeTranscription), streamErr.Error())
+			c.events.SessionStateChanged(domain.SessionStateError, domain.SessionReasonTranscriptionFailed)
+			return domain.StopResult{}, streamErr
+		}
+		c.events.SessionStateChanged(domain.SessionStateIdle, domain.SessionReasonNoTranscript)
+		return domain.StopResult{}, errors.New("no transcript captured")
+	}
+
+	result, reason, err := c.finalizer.Finalize(ctx, meeting.id, dialogue, c.cfg.Language, nil)
+	if err != nil {
+		c.events.SessionStateChanged(domain.SessionStateError, reason)
+		return domain.StopResult{}, err
+	}
+	result.Segments = segments
+	c.remember(meeting.id, segments)
+	if timestamped, err := formatTimestamped(c.cfg.Timestamps, segments, meeting.startedAt, c.rules); err != nil {
+		result.Warnings = addWarning(result.Warnings, domain.ErrorCodeRules, "rules failed on the timestamped transcript; it was left out")
+		c.events.SessionError(domain.ErrorCodeRules, err.Error())
+	} else {
+		result.TimestampedTranscript = timestamped
+	}
+	c.events.FinalTranscript(result.RawTranscript, result.FinalTranscript, result.SessionID, result.Warnings)
+	c.events.SessionStateChanged(domain.SessionStateIdle, reason)
+	return result, nil
+}
+
+// Abort discards the meeting without transcription.
+func (c *MeetingController) Abort() error {
+	meeting, err := c.take()
+	if err != nil {
+		return err
+	}
+	c.teardown(meeting)
+	c.events.SessionStateChanged(domain.SessionStateIdle, domain.SessionReasonRecordingDiscarded)
+	return nil
+}
+
+// Forget drops the segments kept of recent meetings.
+func (c *MeetingController) Forget() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.finished = nil
+}
+
+// Active reports whether a meeting is being recorded.
+func (c *MeetingController) Active() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.current != nil
+}
+
+// WordOffset returns where word index of a recent meeting starts in its saved
+// audio. Words are numbered across the meeting's segments in order; a word
+// without timing falls back to the offset of its segment.
+func (c *MeetingController) WordOffset(sessionID string, index int) (time.Duration, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := len(c.finished) - 1; i >= 0; i-- {
+		if c.finished[i].id != sessionID {
+			continue
+		}
+		remaining := index
+		for _, segment := range c.finished[i].segments {
+			if remaining < 0 {
+				break
+			}
+			if remaining < len(segment.Words) {
+				if word := segment.Words[remaining]; word.Start > 0 || word.End > 0 {
+					return word.Start, nil
+				}
+				return segment.Offset, nil
+			}
+			remaining -= len(segment.Words)
+		}
+		return 0, fmt.Errorf("meeting %s has no word %d", sessionID, index)
+	}
+	return 0, fmt.Errorf("no recent meeting %s", sessionID)
+}
+
+func (c *MeetingController) remember(id string, segments []domain.DialogueSegment) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.finished = append(c.finished, finishedMeeting{id: id, segments: segments})
+	if len(c.finished) > maxFinishedMeetings {
+		c.finished = c.finished[len(c.finished)-maxFinishedMeetings:]
+	}
+}
+
+func (c *MeetingController) take() (*meetingSession, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.current == nil {
+		return nil, domain.ErrNoActiveSession
+	}
+	meeting := c.current
+	c.current = nil
+	return meeting, nil
+}
+
+// abandonStart tears down a meeting that failed to start and frees the slot
+// Start reserved.
+func (c *MeetingController) abandonStart(meeting *meetingSession) {
+	c.teardown(meeting)
+	c.mu.Lock()
+	c.starting = false
+	c.mu.Unlock()
+}
+
+func (c *MeetingController) teardown(meeting *meetingSession) {
+	meeting.cancel()
+	for _, track := range meeting.tracks {
+		_ = track.audio.Stop()
+		_ = track.stream.Close()
+		<-track.eventsDone
+		<-track.audioDone
+		track.finishSaving()
+	}
+}
+
+func (t *meetingTrackSession) finishSaving() {
+	if t.saver == nil {
+		return
+	}
+	if err := t.saver.Finish(); err != nil {
+		debuglog.Printf("meeting track %q recording close failed: %v", t.label, err)
+	}
+}
+
+func (t *meetingTrackSession) consume(events ports.EventSink, clock ports.Clock, startedAt time.Time) {
+	defer close(t.eventsDone)
+
+	for event := range t.stream.Events() {
+		text := strings.TrimSpace(event.Text)
+		if text == "" {
+			continue
+		}
+		if event.Kind == domain.TranscriptKindPartial {
+			events.PartialTranscript(t.label + ": " + text)
+			continue
+		}
+		// Prefer provider audio offsets; fall back to arrival time when the provider reports none.
+		offset := event.Start
+		if event.Duration == 0 {
+			offset = clock.Now().Sub(startedAt)
+		}
+		t.mu.Lock()
+		t.segments = append(t.segments, domain.DialogueSegment{Label: t.label, Text: text, Offset: offset, Words: event.Words})
+		t.mu.Unlock()
+	}
+}
+
+func (t *meetingTrackSession) snapshot() []domain.DialogueSegment {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]domain.DialogueSegment, len(t.segments))
+	copy(out, t.segments)
+	return out
+}
+
+func sortSegments(segments []domain.DialogueSegment) {
+	sort.SliceStable(segments, func(i, j int) bool { return segments[i].Offset < segments[j].Offset })
+}
+
+// formatDialogue renders ordered segments as "Label: text" lines, merging
+// consecutive utterances from the same source.
+func formatDialogue(segments []domain.DialogueSegment) string {
+	var lines []string
+	lastLabel := ""
+	for _, segment := range segments {
+		if len(lines) > 0 && segment.Label == lastLabel {
+			lines[len(lines)-1] += " " + segment.Text
+			continue
+		}
+		lines = append(lines, segment.Label+": "+segment.Text)
+		lastLabel = segment.Label
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,314 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestMeetingControllerProducesLabeledDialogue(t *testing.T) {
+	t.Parallel()
+
+	mic := newFakeStreamingSession()
+	mic.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindPartial, Text: "can you"}
+	mic.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "can you hear me"}
+	desktop := newFakeStreamingSession()
+	desktop.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "yes loud and clear"}
+
+	events := &fakeEventSink{}
+	clipboard := &fakeClipboard{}
+	controller := NewMeetingController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{}, &fakeAudioSession{}}},
+		&fakeProvider{sessions: []ports.StreamingSession{mic, desktop}},
+		&fakeRules{},
+		clipboard,
+		events,
+		MeetingConfig{Tracks: []MeetingTrack{{Label: "Me"}, {Label: "Them"}}},
+	)
+
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if !controller.Active() {
+		t.Fatalf("expected active meeting")
+	}
+	if err := controller.Start(context.Background()); err == nil {
+		t.Fatalf("expected second start to be rejected")
+	}
+
+	result, err := controller.Stop(context.Background())
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if len(result.Segments) != 2 {
+		t.Fatalf("expected two segments, got %+v", result.Segments)
+	}
+	if !strings.Contains(result.FinalTranscript, "Me: can you hear me") || !strings.Contains(result.FinalTranscript, "Them: yes loud and clear") {
+		t.Fatalf("unexpected dialogue: %q", result.FinalTranscript)
+	}
+	if clipboard.lastText != result.FinalTranscript {
+		t.Fatalf("expected dialogue on clipboard")
+	}
+	if len(events.partials) != 1 || events.partials[0] != "Me: can you" {
+		t.Fatalf("expected labeled partial, got %+v", events.partials)
+	}
+	if !strings.HasPrefix(result.SessionID, "meeting-") {
+		t.Fatalf("unexpected session id: %q", result.SessionID)
+	}
+	if controller.Active() {
+		t.Fatalf("expected meeting to be finished")
+	}
+}
+
+func TestMeetingControllerWarnsWhenTimestampsFail(t *testing.T) {
+	t.Parallel()
+
+	mic := newFakeStreamingSession()
+	mic.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "can you hear me"}
+	events := &fakeEventSink{}
+	controller := NewMeetingController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{}}},
+		&fakeProvider{sessions: []ports.StreamingSession{mic}},
+		dialogueOnlyRules{},
+		&fakeClipboard{},
+		events,
+		MeetingConfig{Tracks: []MeetingTrack{{Label: "Me"}}, Timestamps: TimestampConfig{Mode: TimestampModeOffset}},
+	)
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	result, err := controller.Stop(context.Background())
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if result.TimestampedTranscript != "" || len(result.Warnings) != 1 || result.Warnings[0].Code != domain.ErrorCodeRules {
+		t.Fatalf("expected a rules warning instead of timestamps, got %+v", result)
+	}
+	if len(events.errors) != 1 || events.errors[0].code != domain.ErrorCodeRules {
+		t.Fatalf("expected a rules error event, got %+v", events.errors)
+	}
+}
+
+// dialogueOnlyRules accept labeled dialogue but fail on the bare segment
+// text the timestamped transcript applies them to.
+type dialogueOnlyRules struct{}
+
+func (dialogueOnlyRules) Apply(text string) (string, error) {
+	if !strings.Contains(text, ": ") {
+		return "", errors.New("rule loop limit exceeded")
+	}
+	return text, nil
+}
+
+func TestMeetingControllerStartFailureTearsDownTracks(t *testing.T) {
+	t.Parallel()
+
+	first := newFakeStreamingSession()
+	firstAudio := &fakeAudioSession{}
+	controller := NewMeetingController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{firstAudio}},
+		&fakeProvider{sessions: []ports.StreamingSession{first, newFakeStreamingSession()}},
+		&fakeRules{},
+		&fakeClipboard{},
+		&fakeEventSink{},
+		MeetingConfig{Tracks: []MeetingTrack{{Label: "Me"}, {Label: "Them"}}},
+	)
+
+	err := controller.Start(context.Background())
+	if err == nil || !strings.Contains(err.Error(), `"Them"`) {
+		t.Fatalf("expected second track failure, got %v", err)
+	}
+	if firstAudio.stopCalls == 0 || first.closeCalls == 0 {
+		t.Fatalf("expected first track to be torn down")
+	}
+	if controller.Active() {
+		t.Fatalf("expected no active meeting")
+	}
+	if err := controller.Start(context.Background()); err != nil && strings.Contains(err.Error(), "in progress") {
+		t.Fatalf("expected a failed start to free the meeting slot, got %v", err)
+	}
+}
+
+func TestMeetingControllerRefusesConcurrentStart(t *testing.T) {
+	t.Parallel()
+
+	provider := &gatedProvider{entered: make(chan struct{}), release: make(chan struct{}), stream: newFakeStreamingSession()}
+	controller := NewMeetingController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{}}},
+		provider,
+		&fakeRules{},
+		&fakeClipboard{},
+		&fakeEventSink{},
+		MeetingConfig{Tracks: []MeetingTrack{{Label: "Me"}}},
+	)
+	started := make(chan error, 1)
+	go func() { started <- controller.Start(context.Background()) }()
+	<-provider.entered
+
+	if err := controller.Start(context.Background()); err == nil || !strings.Contains(err.Error(), "in progress") {
+		t.Fatalf("expected a concurrent start to be refused, got %v", err)
+	}
+	close(provider.release)
+	if err := <-started; err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if err := controller.Abort(); err != nil {
+		t.Fatalf("abort failed: %v", err)
+	}
+}
+
+// gatedProvider holds StartStreaming until release is closed.
+type gatedProvider struct {
+	entered chan struct{}
+	release chan struct{}
+	stream  ports.StreamingSession
+}
+
+func (p *gatedProvider) StartStreaming(_ context.Context, _ ports.StreamingConfig) (ports.StreamingSession, error) {
+	close(p.entered)
+	<-p.release
+	return p.stream, nil
+}
+
+func TestMeetingControllerAbortAndNoSession(t *testing.T) {
+	t.Parallel()
+
+	events := &fakeEventSink{}
+	controller := NewMeetingController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{}}},
+		&fakeProvider{sessions: []ports.StreamingSession{newFakeStreamingSession()}},
+		&fakeRules{},
+		&fakeClipboard{},
+		events,
+		MeetingConfig{Tracks: []MeetingTrack{{Label: "Me"}}},
+	)
+
+	if _, err := controller.Stop(context.Background()); !errors.Is(err, domain.ErrNoActiveSession) {
+		t.Fatalf("expected ErrNoActiveSession, got %v", err)
+	}
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if err := controller.Abort(); err != nil {
+		t.Fatalf("abort failed: %v", err)
+	}
+	states := events.snapshotStates()
+	if states[len(states)-1].reason != domain.SessionReasonRecordingDiscarded {
+		t.Fatalf("unexpected final reason: %s", states[len(states)-1].reason)
+	}
+}
+
+func TestMeetingControllerRequiresTracks(t *testing.T) {
+	t.Parallel()
+
+	controller := NewMeetingController(&fakeAudioCapture{}, &fakeProvider{}, &fakeRules{}, &fakeClipboard{}, &fakeEventSink{}, MeetingConfig{})
+	if err := controller.Start(context.Background()); err == nil {
+		t.Fatalf("expected missing tracks error")
+	}
+}
+
+func TestFormatDialogueMergesConsecutiveSpeakers(t *testing.T) {
+	t.Parallel()
+
+	segments := []domain.DialogueSegment{
+		{Label: "Them", Text: "right", Offset: 3 * time.Second},
+		{Label: "Me", Text: "hello", Offset: time.Second},
+		{Label: "Me", Text: "there", Offset: 2 * time.Second},
+	}
+	sortSegments(segments)
+
+	if got := formatDialogue(segments); got != "Me: hello there\nThem: right" {
+		t.Fatalf("unexpected dialogue: %q", got)
+	}
+}
+
+func TestMeetingControllerSetProviderRotatesActiveTracks(t *testing.T) {
+	t.Parallel()
+
+	events := &fakeEventSink{}
+	controller := NewMeetingController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{}, &fakeAudioSession{}}},
+		&fakeProvider{sessions: []ports.StreamingSession{newFakeStreamingSession(), newFakeStreamingSession()}},
+		&fakeRules{},
+		&fakeClipboard{},
+		events,
+		MeetingConfig{Tracks: []MeetingTrack{{Label: "Me"}, {Label: "Them"}}},
+	)
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	defer func() { _ = controller.Abort() }()
+
+	failing := &fakeProvider{sessions: []ports.StreamingSession{newFakeStreamingSession()}}
+	if err := controller.SetProvider(failing); err == nil {
+		t.Fatalf("expected switch to fail when a track cannot open a stream")
+	}
+
+	local := &fakeProvider{sessions: []ports.StreamingSession{newFakeStreamingSession(), newFakeStreamingSession()}}
+	if err := controller.SetProvider(local); err != nil {
+		t.Fatalf("switch failed: %v", err)
+	}
+	if local.calls != 2 {
+		t.Fatalf("expected one replacement stream per track, got %d", local.calls)
+	}
+	last := events.states[len(events.states)-1]
+	if last.state != domain.SessionStateRecording || last.reason != domain.SessionReasonProviderSwitched {
+		t.Fatalf("expected provider switch state event, got %+v", last)
+	}
+}
+
+func TestMeetingControllerWordOffset(t *testing.T) {
+	t.Parallel()
+
+	mic := newFakeStreamingSession()
+	mic.events <- domain.TranscriptEvent{
+		Kind:     domain.TranscriptKindFinal,
+		Text:     "ship it",
+		Start:    2 * time.Second,
+		Duration: time.Second,
+		Words: []domain.TranscriptWord{
+			{Text: "ship", Start: 2 * time.Second, End: 2500 * time.Millisecond},
+			{Text: "it", Start: 2500 * time.Millisecond, End: 3 * time.Second},
+		},
+	}
+	desktop := newFakeStreamingSession()
+	desktop.events <- domain.TranscriptEvent{
+		Kind:     domain.TranscriptKindFinal,
+		Text:     "agreed",
+		Start:    4 * time.Second,
+		Duration: time.Second,
+		Words:    []domain.TranscriptWord{{Text: "agreed"}},
+	}
+	controller := NewMeetingController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{}, &fakeAudioSession{}}},
+		&fakeProvider{sessions: []ports.StreamingSession{mic, desktop}},
+		&fakeRules{},
+		&fakeClipboard{},
+		&fakeEventSink{},
+		MeetingConfig{Tracks: []MeetingTrack{{Label: "Me"}, {Label: "Them"}}},
+	)
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	result, err := controller.Stop(context.Background())
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+
+	for index, want := range []time.Duration{2 * time.Second, 2500 * time.Millisecond, 4 * time.Second} {
+		if got, err := controller.WordOffset(result.SessionID, index); err != nil || got != want {
+			t.Fatalf("word %d: expected %s, got %s %v", index, want, got, err)
+		}
+	}
+	if _, err := controller.WordOffset(result.SessionID, 3); err == nil {
+		t.Fatalf("expected out of range word to fail")
+	}
+	if _, err := controller.WordOffset("meeting-99", 0); err == nil {
+		t.Fatalf("expected unknown meeting to fail")
+	}
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// HotkeyRunner triggers hotkeys by name.
+type HotkeyRunner interface {
+	Trigger(ctx context.Context, name string) (domain.HotkeyResult, error)
+}
+
+// midiKey identifies one control on one channel.
+type midiKey struct {
+	control domain.MIDIControl
+	channel int
+	number  int
+}
+
+// MIDITrigger runs hotkeys from a MIDI controller's pads, keys and pedals.
+// A control only fires when it turns on or off, so a fader sweeping through
+// its range fires once each way.
+type MIDITrigger struct {
+	source   ports.MIDISource
+	bindings []domain.MIDIBinding
+	hotkeys  HotkeyRunner
+	events   ports.EventSink
+	retry    time.Duration
+	runState
+
+	on map[midiKey]bool
+}
+
+func NewMIDITrigger(source ports.MIDISource, bindings []domain.MIDIBinding, hotkeys HotkeyRunner, events ports.EventSink) *MIDITrigger {
+	return &MIDITrigger{
+		source:   source,
+		bindings: bindings,
+		hotkeys:  hotkeys,
+		events:   events,
+		retry:    buttonRetryDelay,
+		on:       map[midiKey]bool{},
+	}
+}
+
+// Run reads the controller until ctx is cancelled, reopening it after a
+// failure such as the controller being unplugged.
+func (t *MIDITrigger) Run(ctx context.Context) error {
+	defer t.begin()()
+	for {
+		err := t.source.Run(ctx, func(message domain.MIDIMessage) { t.received(ctx, message) })
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			debuglog.Printf("MIDI trigger failed: %v", err)
+			t.events.SessionError(domain.ErrorCodeTrigger, err.Error())
+		}
+		// Controls are released when the device goes away.
+		clear(t.on)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(t.retry):
+		}
+	}
+}
+
+func (t *MIDITrigger) received(ctx context.Context, message domain.MIDIMessage) {
+	key := midiKey{control: message.Control, channel: message.Channel, number: message.Number}
+	if t.on[key] == message.On {
+		return
+	}
+	t.on[key] = message.On
+	for _, binding := range t.bindings {
+		if !binding.Matches(message) {
+			continue
+		}
+		name := binding.Release
+		if message.On {
+			name = binding.Press
+		}
+		if name == "" {
+			continue
+		}
+		if _, err := t.hotkeys.Trigger(ctx, name); err != nil {
+			t.events.SessionError(domain.ErrorCodeFor(err, domain.ErrorCodeTranscription), err.Error())
+		}
+	}
+}
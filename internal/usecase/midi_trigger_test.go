@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"coldmic/internal/domain"
+)
+
+func TestMIDITriggerRunsBoundHotkeysOnEdges(t *testing.T) {
+	t.Parallel()
+
+	source := &fakeMIDISource{messages: []domain.MIDIMessage{
+		{Control: domain.MIDINote, Channel: 1, Number: 36, On: true},
+		{Control: domain.MIDINote, Channel: 1, Number: 36, On: true},
+		{Control: domain.MIDINote, Channel: 1, Number: 36},
+		{Control: domain.MIDICC, Channel: 1, Number: 64, On: true},
+		{Control: domain.MIDICC, Channel: 2, Number: 64, On: true},
+		{Control: domain.MIDICC, Channel: 2, Number: 64, On: true},
+		{Control: domain.MIDINote, Channel: 1, Number: 40, On: true},
+	}}
+	hotkeys := &fakeHotkeyRunner{}
+	events := &fakeEventSink{}
+	trigger := NewMIDITrigger(source, []domain.MIDIBinding{
+		{Control: domain.MIDINote, Number: 36, Press: "talk", Release: "done"},
+		{Control: domain.MIDICC, Number: 64, Channel: 2, Press: "code"},
+		{Control: domain.MIDINote, Number: 40, Press: "broken"},
+	}, hotkeys, events)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	source.done = cancel
+	if err := trigger.Run(ctx); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if want := []string{"talk", "done", "code", "broken"}; !slices.Equal(hotkeys.triggered, want) {
+		t.Fatalf("expected %v, got %v", want, hotkeys.triggered)
+	}
+	if errs := events.snapshotErrors(); len(errs) != 1 || errs[0].code != domain.ErrorCodeTranscription {
+		t.Fatalf("expected the failed hotkey to be reported, got %+v", errs)
+	}
+}
+
+type fakeMIDISource struct {
+	messages []domain.MIDIMessage
+	done     func()
+}
+
+func (f *fakeMIDISource) Run(ctx context.Context, received func(domain.MIDIMessage)) error {
+	for _, message := range f.messages {
+		received(message)
+	}
+	f.done()
+	<-ctx.Done()
+	return nil
+}
+
+type fakeHotkeyRunner struct {
+	triggered []string
+}
+
+func (f *fakeHotkeyRunner) Trigger(_ context.Context, name string) (domain.HotkeyResult, error) {
+	f.triggered = append(f.triggered, name)
+	if name == "broken" {
+		return domain.HotkeyResult{}, errors.New("hotkey failed")
+	}
+	return domain.HotkeyResult{Hotkey: name}, nil
+}
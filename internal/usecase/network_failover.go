@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// NetworkConfig controls connectivity-driven provider failover.
+type NetworkConfig struct {
+	Interval time.Duration
+	// FallbackOnMetered also fails over while the connection is metered.
+	FallbackOnMetered bool
+}
+
+// ProviderSwitch installs a provider on one consumer, such as a controller.
+type ProviderSwitch func(provider ports.TranscriptionProvider) error
+
+// NetworkFailover polls connectivity and moves transcription to the fallback
+// provider while the network is degraded, switching back once it recovers.
+// Without a fallback provider it only reports state changes.
+type NetworkFailover struct {
+	monitor  ports.NetworkMonitor
+	sink     ports.NetworkSink
+	primary  ports.TranscriptionProvider
+	fallback ports.TranscriptionProvider
+	switches []ProviderSwitch
+	cfg      NetworkConfig
+	runState
+
+	mu    sync.Mutex
+	last  domain.NetworkState
+	known bool
+}
+
+func NewNetworkFailover(
+	monitor ports.NetworkMonitor,
+	sink ports.NetworkSink,
+	cfg NetworkConfig,
+	primary ports.TranscriptionProvider,
+	fallback ports.TranscriptionProvider,
+	switches ...ProviderSwitch,
+) *NetworkFailover {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 10 * time.Second
+	}
+	return &NetworkFailover{
+		monitor:  monitor,
+		sink:     sink,
+		primary:  primary,
+		fallback: fallback,
+		switches: switches,
+		cfg:      cfg,
+	}
+}
+
+// Run checks connectivity every Interval until ctx is cancelled.
+func (f *NetworkFailover) Run(ctx context.Context) error {
+	defer f.begin()()
+	ticker := time.NewTicker(f.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := f.Check(ctx); err != nil {
+			debuglog.Printf("network check failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check reads the current state, switches providers when the degraded state
+// flips, and notifies the sink of any change.
+func (f *NetworkFailover) Check(ctx context.Context) (domain.NetworkState, error) {
+	state, err := f.monitor.State(ctx)
+	if err != nil {
+		return domain.NetworkState{}, err
+	}
+	degraded := !state.Online || (f.cfg.FallbackOnMetered && state.Metered)
+	state.UsingFallback = degraded && f.fallback != nil
+
+	f.mu.Lock()
+	changed := !f.known || state != f.last
+	wasFallback := f.last.UsingFallback
+	f.last = state
+	f.known = true
+	f.mu.Unlock()
+	if !changed {
+		return state, nil
+	}
+
+	if state.UsingFallback != wasFallback {
+		target := f.primary
+		if state.UsingFallback {
+			target = f.fallback
+		}
+		for _, apply := range f.switches {
+			if err := apply(target); err != nil {
+				debuglog.Printf("network failover provider switch failed: %v", err)
+			}
+		}
+	}
+	debuglog.Printf("network state online=%t metered=%t using_fallback=%t", state.Online, state.Metered, state.UsingFallback)
+	f.sink.NetworkChanged(state)
+	return state, nil
+}
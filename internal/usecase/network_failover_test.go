@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestNetworkFailoverSwitchesProvidersOnDegradation(t *testing.T) {
+	t.Parallel()
+
+	primary := &fakeProvider{}
+	fallback := &fakeProvider{}
+	monitor := &fakeNetworkMonitor{state: domain.NetworkState{Online: true}}
+	sink := &fakeNetworkSink{}
+	var installed []ports.TranscriptionProvider
+	failover := NewNetworkFailover(monitor, sink, NetworkConfig{FallbackOnMetered: true}, primary, fallback,
+		func(provider ports.TranscriptionProvider) error {
+			installed = append(installed, provider)
+			return nil
+		},
+	)
+	ctx := context.Background()
+
+	if _, err := failover.Check(ctx); err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	if len(installed) != 0 || len(sink.snapshot()) != 1 {
+		t.Fatalf("expected initial state to be reported without a switch, got %d switches", len(installed))
+	}
+
+	monitor.set(domain.NetworkState{Online: true, Metered: true})
+	state, _ := failover.Check(ctx)
+	if !state.UsingFallback || len(installed) != 1 || installed[0] != fallback {
+		t.Fatalf("expected metered connection to fail over, got %+v", state)
+	}
+
+	// Going offline while already on the fallback reports the change without switching again.
+	monitor.set(domain.NetworkState{Online: false})
+	failover.Check(ctx)
+	failover.Check(ctx)
+	if len(installed) != 1 || len(sink.snapshot()) != 3 {
+		t.Fatalf("expected one event per change, got %d switches and %d events", len(installed), len(sink.snapshot()))
+	}
+
+	monitor.set(domain.NetworkState{Online: true})
+	failover.Check(ctx)
+	if len(installed) != 2 || installed[1] != primary {
+		t.Fatalf("expected recovery to restore the primary provider")
+	}
+	if last := sink.snapshot()[3]; last.UsingFallback || !last.Online {
+		t.Fatalf("unexpected recovery state: %+v", last)
+	}
+}
+
+func TestNetworkFailoverWithoutFallbackOnlyReports(t *testing.T) {
+	t.Parallel()
+
+	monitor := &fakeNetworkMonitor{state: domain.NetworkState{Online: false}}
+	sink := &fakeNetworkSink{}
+	switched := false
+	failover := NewNetworkFailover(monitor, sink, NetworkConfig{}, &fakeProvider{}, nil,
+		func(ports.TranscriptionProvider) error {
+			switched = true
+			return nil
+		},
+	)
+
+	state, err := failover.Check(context.Background())
+	if err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	if state.UsingFallback || switched || len(sink.snapshot()) != 1 {
+		t.Fatalf("expected offline event without a switch, got %+v", state)
+	}
+
+	monitor.err = errors.New("no system bus")
+	if _, err := failover.Check(context.Background()); err == nil {
+		t.Fatalf("expected monitor error")
+	}
+}
+
+func TestNetworkFailoverReportsRunning(t *testing.T) {
+	t.Parallel()
+
+	failover := NewNetworkFailover(&fakeNetworkMonitor{state: domain.NetworkState{Online: true}}, &fakeNetworkSink{}, NetworkConfig{}, &fakeProvider{}, nil)
+	if failover.Running() {
+		t.Fatalf("expected the failover not to run before Run")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- failover.Run(ctx) }()
+	waitFor(t, failover.Running)
+
+	cancel()
+	<-done
+	if failover.Running() {
+		t.Fatalf("expected the failover to stop running once Run returns")
+	}
+}
+
+type fakeNetworkMonitor struct {
+	mu    sync.Mutex
+	state domain.NetworkState
+	err   error
+}
+
+func (f *fakeNetworkMonitor) State(_ context.Context) (domain.NetworkState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.state, f.err
+}
+
+func (f *fakeNetworkMonitor) set(state domain.NetworkState) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.state = state
+}
+
+type fakeNetworkSink struct {
+	mu     sync.Mutex
+	states []domain.NetworkState
+}
+
+func (f *fakeNetworkSink) NetworkChanged(state domain.NetworkState) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.states = append(f.states, state)
+}
+
+func (f *fakeNetworkSink) snapshot() []domain.NetworkState {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]domain.NetworkState(nil), f.states...)
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NormalizeOptions tidies whitespace and punctuation of final output.
+type NormalizeOptions struct {
+	Trim              bool
+	CollapseSpaces    bool
+	EnsurePunctuation bool
+	TrailingNewline   bool
+}
+
+// NormalizeConfig picks normalization options per output target.
+type NormalizeConfig struct {
+	Default NormalizeOptions
+	Targets map[string]NormalizeOptions
+}
+
+func (c NormalizeConfig) optionsFor(target string) NormalizeOptions {
+	if options, ok := c.Targets[target]; ok {
+		return options
+	}
+	return c.Default
+}
+
+var repeatedSpaces = regexp.MustCompile(`[ \t]{2,}`)
+
+// normalizeText applies options in a fixed order: trim, collapse spaces,
+// terminal punctuation, then the trailing newline.
+func normalizeText(options NormalizeOptions, text string) string {
+	if options.Trim {
+		text = strings.TrimSpace(text)
+	}
+	if options.CollapseSpaces {
+		text = repeatedSpaces.ReplaceAllString(text, " ")
+	}
+	if options.EnsurePunctuation {
+		body := strings.TrimRightFunc(text, unicode.IsSpace)
+		if last, _ := utf8.DecodeLastRuneInString(body); unicode.IsLetter(last) || unicode.IsDigit(last) {
+			text = body + "." + text[len(body):]
+		}
+	}
+	if options.TrailingNewline && text != "" {
+		text = strings.TrimRight(text, "\r\n") + "\n"
+	}
+	return text
+}
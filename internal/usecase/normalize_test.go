@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNormalizeText(t *testing.T) {
+	t.Parallel()
+
+	all := NormalizeOptions{Trim: true, CollapseSpaces: true, EnsurePunctuation: true, TrailingNewline: true}
+	cases := []struct {
+		name    string
+		options NormalizeOptions
+		input   string
+		want    string
+	}{
+		{"none", NormalizeOptions{}, "  ship  it  ", "  ship  it  "},
+		{"all", all, "  ship \t it  now  ", "ship it now.\n"},
+		{"punctuation kept", all, "done?", "done?\n"},
+		{"punctuation before trailing space", NormalizeOptions{EnsurePunctuation: true}, "ship it \n", "ship it. \n"},
+		{"newline replaces existing newlines", NormalizeOptions{TrailingNewline: true}, "line\n\n", "line\n"},
+		{"collapse keeps newlines", NormalizeOptions{CollapseSpaces: true}, "a  b\n\nc", "a b\n\nc"},
+		{"empty stays empty", all, "   ", ""},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := normalizeText(tc.options, tc.input); got != tc.want {
+				t.Fatalf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFinalizerNormalizesPerTarget(t *testing.T) {
+	t.Parallel()
+
+	clipboard := &fakeClipboard{}
+	finalizer := newTranscriptFinalizer(&fakeRules{transform: "see you  soon"}, clipboard, &fakeEventSink{})
+	finalizer.setNormalize(NormalizeConfig{
+		Default: NormalizeOptions{CollapseSpaces: true},
+		Targets: map[string]NormalizeOptions{"fake": {EnsurePunctuation: true, TrailingNewline: true}},
+	})
+
+	if _, _, err := finalizer.Finalize(context.Background(), "session-1", "raw", "", nil); err != nil {
+		t.Fatalf("finalize failed: %v", err)
+	}
+	if clipboard.lastText != "see you soon" {
+		t.Fatalf("unexpected clipboard text: %q", clipboard.lastText)
+	}
+
+	target := &fakeOutputTarget{}
+	finalizer.setTarget(target)
+	result, _, err := finalizer.Finalize(context.Background(), "session-2", "raw", "", nil)
+	if err != nil {
+		t.Fatalf("finalize failed: %v", err)
+	}
+	if result.FinalTranscript != "formatted: see you  soon.\n" || target.delivered[0] != result.FinalTranscript {
+		t.Fatalf("unexpected target output: %q", result.FinalTranscript)
+	}
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// PanicWipe is the emergency action for something sensitive dictated on the
+// wrong machine: it discards running recordings, clears the clipboard and
+// forgets in-memory transcripts, and optionally purges today's history,
+// recordings, overflow files and cached transcripts.
+type PanicWipe struct {
+	session   *SessionService
+	meeting   *MeetingController
+	clipboard ports.Clipboard
+	// history and recordings are purged from the start of today; nil skips
+	// them.
+	history    ports.Purger
+	recordings ports.Purger
+	// overflow and cache are purged the same way; see SetFilePurgers.
+	overflow ports.Purger
+	cache    ports.Purger
+	// clock decides when today started; nil uses the wall clock.
+	clock ports.Clock
+}
+
+// NewPanicWipe wipes session and meeting state; history and recordings may
+// be nil to keep them.
+func NewPanicWipe(session *SessionService, meeting *MeetingController, clipboard ports.Clipboard, history ports.Purger, recordings ports.Purger) *PanicWipe {
+	return &PanicWipe{session: session, meeting: meeting, clipboard: clipboard, history: history, recordings: recordings}
+}
+
+// SetFilePurgers also purges today's clipboard overflow files and cached
+// file transcripts; nil skips either.
+func (w *PanicWipe) SetFilePurgers(overflow ports.Purger, cache ports.Purger) {
+	w.overflow = overflow
+	w.cache = cache
+}
+
+// Wipe runs every step even when one fails, so a failure never leaves the
+// rest behind, and reports what it discarded with the joined errors.
+func (w *PanicWipe) Wipe(ctx context.Context) (domain.PanicWipeResult, error) {
+	var result domain.PanicWipeResult
+	var errs []error
+
+	for _, id := range w.session.Sessions() {
+		if err := w.session.AbortSession(id); err != nil {
+			if !errors.Is(err, domain.ErrNoActiveSession) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		result.SessionsAborted++
+	}
+	if w.meeting != nil {
+		if err := w.meeting.Abort(); err == nil {
+			result.MeetingAborted = true
+		} else if !errors.Is(err, domain.ErrNoActiveSession) {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := w.clipboard.SetText(ctx, ""); err != nil {
+		errs = append(errs, fmt.Errorf("failed to clear clipboard: %w", err))
+	} else {
+		result.ClipboardCleared = true
+	}
+
+	w.session.Forget()
+	if w.meeting != nil {
+		w.meeting.Forget()
+	}
+
+	now := clockOrSystem(w.clock).Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if w.history != nil {
+		purged, err := w.history.PurgeSince(ctx, today)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		result.HistoryPurged = purged
+	}
+	if w.recordings != nil {
+		purged, err := w.recordings.PurgeSince(ctx, today)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		result.RecordingsPurged = purged
+	}
+	if w.overflow != nil {
+		purged, err := w.overflow.PurgeSince(ctx, today)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		result.OverflowPurged = purged
+	}
+	if w.cache != nil {
+		purged, err := w.cache.PurgeSince(ctx, today)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		result.CachePurged = purged
+	}
+	return result, errors.Join(errs...)
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestPanicWipeDiscardsSessionClipboardAndToday(t *testing.T) {
+	t.Parallel()
+
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{}}},
+		&fakeProvider{sessions: []ports.StreamingSession{newFakeStreamingSession()}},
+		&fakeRules{},
+		&fakeClipboard{},
+		&fakeEventSink{},
+		Config{},
+	)
+	session := NewSessionService(controller)
+	session.latest = &domain.LatestTranscript{Result: domain.StopResult{FinalTranscript: "secret"}}
+	if err := session.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	clipboard := &fakeClipboard{lastText: "secret"}
+	history := &fakePurger{purged: 3}
+	recordings := &fakePurger{err: errors.New("busy")}
+	overflow := &fakePurger{purged: 2}
+	wipe := NewPanicWipe(session, nil, clipboard, history, recordings)
+	wipe.SetFilePurgers(overflow, nil)
+	wipe.clock = &fakeClock{now: time.Date(2026, 1, 2, 9, 30, 0, 0, time.UTC)}
+
+	result, err := wipe.Wipe(context.Background())
+	if err == nil {
+		t.Fatalf("expected the recordings error")
+	}
+	if result.SessionsAborted != 1 || !result.ClipboardCleared || result.HistoryPurged != 3 || result.OverflowPurged != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if session.Status().Active || clipboard.lastText != "" {
+		t.Fatalf("expected session stopped and clipboard cleared, clipboard=%q", clipboard.lastText)
+	}
+	if _, err := session.LastTranscript(); !errors.Is(err, domain.ErrNoTranscriptAvailable) {
+		t.Fatalf("expected the latest transcript forgotten, got %v", err)
+	}
+	if _, err := controller.SessionLog(); !errors.Is(err, domain.ErrNoSessionLog) {
+		t.Fatalf("expected the session log forgotten, got %v", err)
+	}
+	today := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	if !history.since.Equal(today) || !recordings.since.Equal(today) || !overflow.since.Equal(today) {
+		t.Fatalf("expected purges from the start of today, got %v, %v and %v", history.since, recordings.since, overflow.since)
+	}
+}
+
+func TestPanicWipeWithoutPurgeKeepsHistory(t *testing.T) {
+	t.Parallel()
+
+	session := NewSessionService(NewSessionController(&fakeAudioCapture{}, &fakeProvider{}, &fakeRules{}, &fakeClipboard{}, &fakeEventSink{}, Config{}))
+	result, err := NewPanicWipe(session, nil, &fakeClipboard{}, nil, nil).Wipe(context.Background())
+	if err != nil {
+		t.Fatalf("wipe failed: %v", err)
+	}
+	if result != (domain.PanicWipeResult{ClipboardCleared: true}) {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
+
+type fakePurger struct {
+	purged int
+	err    error
+	since  time.Time
+}
+
+func (f *fakePurger) PurgeSince(_ context.Context, since time.Time) (int, error) {
+	f.since = since
+	return f.purged, f.err
+}
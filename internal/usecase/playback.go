@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+const defaultPlaybackTick = 250 * time.Millisecond
+
+// AudioPlayback plays saved session audio one session at a time and reports
+// the position while it plays, so a transcript can be checked against what
+// was actually said.
+type AudioPlayback struct {
+	recordings ports.SessionAudioStore
+	player     ports.AudioPlayer
+	sink       ports.PlaybackSink
+	tick       time.Duration
+	now        func() time.Time
+
+	mu      sync.Mutex
+	current ports.Playback
+}
+
+func NewAudioPlayback(recordings ports.SessionAudioStore, player ports.AudioPlayer, sink ports.PlaybackSink) *AudioPlayback {
+	return &AudioPlayback{recordings: recordings, player: player, sink: sink, tick: defaultPlaybackTick, now: time.Now}
+}
+
+// Play stops any playback in progress and starts the session's audio at from.
+// Position events follow until it ends; ctx bounds the playback.
+func (p *AudioPlayback) Play(ctx context.Context, sessionID string, from time.Duration) error {
+	if from < 0 {
+		from = 0
+	}
+	paths, err := p.recordings.SessionAudio(sessionID)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stopLocked()
+	playback, err := p.player.Play(ctx, paths, from)
+	if err != nil {
+		return err
+	}
+	p.current = playback
+	debuglog.Printf("playback session=%s from=%s", sessionID, from)
+	go p.follow(playback, sessionID, from)
+	return nil
+}
+
+// Stop ends the current playback, if any.
+func (p *AudioPlayback) Stop() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stopLocked()
+}
+
+func (p *AudioPlayback) stopLocked() error {
+	if p.current == nil {
+		return nil
+	}
+	err := p.current.Stop()
+	p.current = nil
+	return err
+}
+
+func (p *AudioPlayback) follow(playback ports.Playback, sessionID string, from time.Duration) {
+	done := make(chan error, 1)
+	go func() {
+		done <- playback.Wait()
+	}()
+
+	started := p.now()
+	ticker := time.NewTicker(p.tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.sink.PlaybackPosition(domain.PlaybackPosition{SessionID: sessionID, Position: from + p.now().Sub(started)})
+		case err := <-done:
+			if err != nil {
+				debuglog.Printf("playback session=%s failed: %v", sessionID, err)
+			}
+			p.mu.Lock()
+			if p.current == playback {
+				p.current = nil
+			}
+			p.mu.Unlock()
+			p.sink.PlaybackPosition(domain.PlaybackPosition{SessionID: sessionID, Position: from + p.now().Sub(started), Done: true})
+			return
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+type fakeSessionAudio map[string][]string
+
+func (f fakeSessionAudio) SessionAudio(sessionID string) ([]string, error) {
+	if paths, ok := f[sessionID]; ok {
+		return paths, nil
+	}
+	return nil, os.ErrNotExist
+}
+
+type fakePlayback struct {
+	done    chan struct{}
+	once    sync.Once
+	stopped bool
+}
+
+func (p *fakePlayback) Wait() error {
+	<-p.done
+	return nil
+}
+
+func (p *fakePlayback) Stop() error {
+	p.stopped = true
+	p.once.Do(func() { close(p.done) })
+	return nil
+}
+
+type fakePlayer struct {
+	paths     []string
+	from      time.Duration
+	playbacks []*fakePlayback
+}
+
+func (f *fakePlayer) Play(_ context.Context, paths []string, from time.Duration) (ports.Playback, error) {
+	f.paths, f.from = paths, from
+	playback := &fakePlayback{done: make(chan struct{})}
+	f.playbacks = append(f.playbacks, playback)
+	return playback, nil
+}
+
+type fakePlaybackSink struct {
+	positions chan domain.PlaybackPosition
+}
+
+func (s *fakePlaybackSink) PlaybackPosition(position domain.PlaybackPosition) {
+	s.positions <- position
+}
+
+func TestAudioPlaybackReportsPositionUntilStopped(t *testing.T) {
+	t.Parallel()
+
+	player := &fakePlayer{}
+	sink := &fakePlaybackSink{positions: make(chan domain.PlaybackPosition, 16)}
+	playback := NewAudioPlayback(fakeSessionAudio{"meeting-1": {"me.wav", "them.wav"}}, player, sink)
+	playback.tick = time.Millisecond
+
+	if err := playback.Play(context.Background(), "meeting-1", 3*time.Second); err != nil {
+		t.Fatalf("play failed: %v", err)
+	}
+	if len(player.paths) != 2 || player.from != 3*time.Second {
+		t.Fatalf("expected both tracks from 3s, got %q %s", player.paths, player.from)
+	}
+	if position := <-sink.positions; position.SessionID != "meeting-1" || position.Position < 3*time.Second || position.Done {
+		t.Fatalf("unexpected position: %+v", position)
+	}
+
+	if err := playback.Stop(); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	for position := range sink.positions {
+		if position.Done {
+			break
+		}
+	}
+	if !player.playbacks[0].stopped {
+		t.Fatalf("expected playback to be stopped")
+	}
+}
+
+func TestAudioPlaybackReplacesCurrentPlayback(t *testing.T) {
+	t.Parallel()
+
+	player := &fakePlayer{}
+	sink := &fakePlaybackSink{positions: make(chan domain.PlaybackPosition, 64)}
+	playback := NewAudioPlayback(fakeSessionAudio{"meeting-1": {"me.wav"}}, player, sink)
+
+	_ = playback.Play(context.Background(), "meeting-1", 0)
+	_ = playback.Play(context.Background(), "meeting-1", -time.Second)
+	if !player.playbacks[0].stopped || player.playbacks[1].stopped || player.from != 0 {
+		t.Fatalf("expected the first playback replaced from the start, got %+v", player.playbacks)
+	}
+	_ = playback.Stop()
+
+	if err := playback.Play(context.Background(), "meeting-2", 0); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected missing audio error, got %v", err)
+	}
+}
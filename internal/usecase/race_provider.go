@@ -0,0 +1,321 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// RacePick selects which of two racing providers supplies the transcript.
+type RacePick string
+
+const (
+	// RacePickConfidence waits for both providers and keeps the transcript
+	// with the higher word-weighted confidence.
+	RacePickConfidence RacePick = "confidence"
+	// RacePickFirst keeps the provider that produces the first non-empty
+	// final and streams its finals as they arrive.
+	RacePickFirst RacePick = "first"
+)
+
+// RaceConfig controls a dual-provider race.
+type RaceConfig struct {
+	Pick RacePick
+	// Names label the primary and secondary provider in logs.
+	Names [2]string
+}
+
+// RaceProvider fans audio out to two providers at once and forwards one of
+// their transcripts, which makes it easy to compare providers on the same
+// speech. Partials come from the primary until a winner is known.
+type RaceProvider struct {
+	providers [2]ports.TranscriptionProvider
+	cfg       RaceConfig
+}
+
+func NewRaceProvider(primary ports.TranscriptionProvider, secondary ports.TranscriptionProvider, cfg RaceConfig) *RaceProvider {
+	if cfg.Pick == "" {
+		cfg.Pick = RacePickConfidence
+	}
+	for i, name := range []string{"primary", "secondary"} {
+		if cfg.Names[i] == "" {
+			cfg.Names[i] = name
+		}
+	}
+	return &RaceProvider{providers: [2]ports.TranscriptionProvider{primary, secondary}, cfg: cfg}
+}
+
+// Capabilities reports what both providers support, since either may win.
+// Live partials and streaming follow the primary, whose partials are shown.
+func (p *RaceProvider) Capabilities() domain.Capabilities {
+	var both [2]domain.Capabilities
+	for i, provider := range p.providers {
+		if reporter, ok := provider.(ports.CapabilityReporter); ok {
+			both[i] = reporter.Capabilities()
+		}
+	}
+	capabilities := domain.Capabilities{
+		Streaming:    both[0].Streaming,
+		LivePartials: both[0].LivePartials,
+		Timestamps:   both[0].Timestamps && both[1].Timestamps,
+		WordTimings:  both[0].WordTimings && both[1].WordTimings,
+		Confidence:   both[0].Confidence && both[1].Confidence,
+		Diarization:  both[0].Diarization && both[1].Diarization,
+		Keywords:     both[0].Keywords && both[1].Keywords,
+		Acceleration: both[0].Acceleration,
+	}
+	switch {
+	case len(both[0].Languages) == 0:
+		capabilities.Languages = both[1].Languages
+	case len(both[1].Languages) == 0:
+		capabilities.Languages = both[0].Languages
+	default:
+		for _, language := range both[0].Languages {
+			if slices.Contains(both[1].Languages, language) {
+				capabilities.Languages = append(capabilities.Languages, language)
+			}
+		}
+	}
+	return capabilities
+}
+
+// ProviderNames reports both providers, since both receive all audio.
+func (p *RaceProvider) ProviderNames() []string {
+	return append(providerNames(p.providers[0]), providerNames(p.providers[1])...)
+}
+
+func (p *RaceProvider) StartStreaming(ctx context.Context, cfg ports.StreamingConfig) (ports.StreamingSession, error) {
+	primary, err := p.providers[0].StartStreaming(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	secondary, err := p.providers[1].StartStreaming(ctx, cfg)
+	if err != nil {
+		// The race is a comparison; the primary alone still transcribes.
+		debuglog.Printf("race %s failed to start, using %s alone: %v", p.cfg.Names[1], p.cfg.Names[0], err)
+		return primary, nil
+	}
+
+	s := &raceSession{
+		cfg:    p.cfg,
+		events: make(chan domain.TranscriptEvent, 64),
+		done:   make(chan struct{}),
+		winner: -1,
+	}
+	for i, session := range []ports.StreamingSession{primary, secondary} {
+		s.sides[i] = &raceSide{session: session, aggregator: newTranscriptAggregator()}
+	}
+	var wg sync.WaitGroup
+	for i := range s.sides {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.read(i)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		s.finish()
+	}()
+	return s, nil
+}
+
+type raceSide struct {
+	session    ports.StreamingSession
+	aggregator *transcriptAggregator
+	finals     []domain.TranscriptEvent
+	failed     bool
+	err        error
+}
+
+type raceSession struct {
+	cfg    RaceConfig
+	sides  [2]*raceSide
+	events chan domain.TranscriptEvent
+	done   chan struct{}
+
+	mu     sync.Mutex
+	winner int
+	err    error
+}
+
+// SendAudio feeds both providers; a provider that fails drops out of the
+// race and only a failure of both is reported. The providers are fed
+// without holding mu, since sending may block while their events are read.
+func (s *raceSession) SendAudio(chunk []byte) error {
+	var errs []error
+	for i, side := range s.sides {
+		s.mu.Lock()
+		failed, sideErr := side.failed, side.err
+		s.mu.Unlock()
+		if failed {
+			errs = append(errs, sideErr)
+			continue
+		}
+		if err := side.session.SendAudio(chunk); err != nil {
+			debuglog.Printf("race %s dropped out: %v", s.cfg.Names[i], err)
+			s.mu.Lock()
+			side.failed = true
+			side.err = errors.Join(side.err, err)
+			s.mu.Unlock()
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == len(s.sides) {
+		return errs[0]
+	}
+	return nil
+}
+
+// Finalize flushes both providers that are still racing. It reports
+// errors.ErrUnsupported when either cannot finalize, so the caller still
+// waits out the grace period for it.
+func (s *raceSession) Finalize(ctx context.Context) error {
+	errs := make([]error, len(s.sides))
+	var wg sync.WaitGroup
+	for i, side := range s.sides {
+		s.mu.Lock()
+		failed := side.failed
+		s.mu.Unlock()
+		if failed {
+			continue
+		}
+		finalizer, ok := side.session.(ports.StreamFinalizer)
+		if !ok {
+			errs[i] = errors.ErrUnsupported
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = finalizer.Finalize(ctx)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// OnReconnect forwards fn to each provider stream that reconnects.
+func (s *raceSession) OnReconnect(fn func(err error)) {
+	for _, side := range s.sides {
+		if notifier, ok := side.session.(ports.ReconnectNotifier); ok {
+			notifier.OnReconnect(fn)
+		}
+	}
+}
+
+func (s *raceSession) CloseSend() error {
+	return errors.Join(s.sides[0].session.CloseSend(), s.sides[1].session.CloseSend())
+}
+
+func (s *raceSession) Events() <-chan domain.TranscriptEvent {
+	return s.events
+}
+
+func (s *raceSession) Wait() error {
+	<-s.done
+	return s.err
+}
+
+func (s *raceSession) Close() error {
+	err := errors.Join(s.sides[0].session.Close(), s.sides[1].session.Close())
+	<-s.done
+	return err
+}
+
+func (s *raceSession) read(i int) {
+	side := s.sides[i]
+	for event := range side.session.Events() {
+		s.mu.Lock()
+		switch {
+		case event.Kind != domain.TranscriptKindFinal:
+			if s.winner == i || (s.winner < 0 && i == 0) {
+				s.emit(event)
+			}
+		default:
+			side.aggregator.Add(event)
+			side.finals = append(side.finals, event)
+			if s.cfg.Pick != RacePickFirst {
+				break
+			}
+			if s.winner < 0 && strings.TrimSpace(event.Text) != "" {
+				s.winner = i
+				debuglog.Printf("race %s produced the first final", s.cfg.Names[i])
+				for _, final := range side.finals {
+					s.emit(final)
+				}
+			} else if s.winner == i {
+				s.emit(event)
+			}
+		}
+		s.mu.Unlock()
+	}
+	err := side.session.Wait()
+	s.mu.Lock()
+	side.err = errors.Join(side.err, err)
+	s.mu.Unlock()
+}
+
+// finish picks the confidence winner once both providers are done and
+// forwards its finals.
+func (s *raceSession) finish() {
+	defer close(s.done)
+	defer close(s.events)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, side := range s.sides {
+		confidence, _ := side.aggregator.Confidence()
+		debuglog.Printf("race %s confidence=%.3f err=%v transcript=%q", s.cfg.Names[i], confidence, side.err, side.aggregator.Raw())
+	}
+	if s.winner < 0 {
+		s.winner = s.confidenceWinner()
+		if s.winner >= 0 {
+			debuglog.Printf("race %s won", s.cfg.Names[s.winner])
+			for _, final := range s.sides[s.winner].finals {
+				s.emit(final)
+			}
+		}
+	}
+	if s.winner >= 0 {
+		s.err = s.sides[s.winner].err
+		return
+	}
+	s.err = s.sides[0].err
+	if s.err == nil {
+		s.err = s.sides[1].err
+	}
+}
+
+// confidenceWinner returns the side whose finals have the higher confidence,
+// preferring the primary on a tie or when neither reports confidence, or -1
+// when neither produced text.
+func (s *raceSession) confidenceWinner() int {
+	primary, secondary := s.sides[0].aggregator, s.sides[1].aggregator
+	switch {
+	case primary.Raw() == "" && secondary.Raw() == "":
+		return -1
+	case secondary.Raw() == "":
+		return 0
+	case primary.Raw() == "":
+		return 1
+	}
+	primaryConfidence, _ := primary.Confidence()
+	secondaryConfidence, _ := secondary.Confidence()
+	if secondaryConfidence > primaryConfidence {
+		return 1
+	}
+	return 0
+}
+
+// emit forwards an event; callers hold mu. Events are never dropped, since
+// finals are forwarded only once.
+func (s *raceSession) emit(event domain.TranscriptEvent) {
+	s.events <- event
+}
@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestRaceProviderPicksHigherConfidence(t *testing.T) {
+	t.Parallel()
+
+	primary, secondary := newFakeStreamingSession(), newFakeStreamingSession()
+	primary.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindPartial, Text: "ship it"}
+	primary.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "ship it friday", Confidence: 0.8}
+	secondary.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindPartial, Text: "Ship"}
+	secondary.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "Ship it Friday.", Confidence: 0.95}
+
+	race := NewRaceProvider(
+		&fakeProvider{sessions: []ports.StreamingSession{primary}},
+		&fakeProvider{sessions: []ports.StreamingSession{secondary}},
+		RaceConfig{},
+	)
+	session, err := race.StartStreaming(context.Background(), ports.StreamingConfig{})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if err := session.SendAudio([]byte("pcm")); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	_ = session.CloseSend()
+
+	var events []domain.TranscriptEvent
+	for event := range session.Events() {
+		events = append(events, event)
+	}
+	if err := session.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 || events[0].Text != "ship it" || events[1].Text != "Ship it Friday." {
+		t.Fatalf("expected primary partial and secondary final, got %+v", events)
+	}
+}
+
+func TestRaceProviderPicksFirstFinal(t *testing.T) {
+	t.Parallel()
+
+	primary, secondary := newFakeStreamingSession(), newFakeStreamingSession()
+	secondary.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "first"}
+
+	race := NewRaceProvider(
+		&fakeProvider{sessions: []ports.StreamingSession{primary}},
+		&fakeProvider{sessions: []ports.StreamingSession{secondary}},
+		RaceConfig{Pick: RacePickFirst},
+	)
+	session, err := race.StartStreaming(context.Background(), ports.StreamingConfig{})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if event := <-session.Events(); event.Text != "first" {
+		t.Fatalf("expected the first final to be streamed, got %+v", event)
+	}
+	primary.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "late", Confidence: 0.99}
+	secondary.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "second"}
+	_ = session.CloseSend()
+
+	var rest []string
+	for event := range session.Events() {
+		rest = append(rest, event.Text)
+	}
+	if len(rest) != 1 || rest[0] != "second" {
+		t.Fatalf("expected only the winner's finals, got %q", rest)
+	}
+}
+
+func TestRaceProviderFallsBackToPrimary(t *testing.T) {
+	t.Parallel()
+
+	primary := newFakeStreamingSession()
+	race := NewRaceProvider(
+		&fakeProvider{sessions: []ports.StreamingSession{primary}},
+		&fakeProvider{err: errors.New("no key")},
+		RaceConfig{},
+	)
+	session, err := race.StartStreaming(context.Background(), ports.StreamingConfig{})
+	if err != nil || session != primary {
+		t.Fatalf("expected the primary session alone, got %v %v", session, err)
+	}
+
+	if _, err := NewRaceProvider(&fakeProvider{err: errors.New("down")}, &fakeProvider{}, RaceConfig{}).StartStreaming(context.Background(), ports.StreamingConfig{}); err == nil {
+		t.Fatalf("expected primary start failure")
+	}
+}
+
+func TestRaceProviderReportsFailureWithoutTranscript(t *testing.T) {
+	t.Parallel()
+
+	primary, secondary := newFakeStreamingSession(), newFakeStreamingSession()
+	primary.waitErr = errors.New("primary failed")
+	secondary.waitErr = errors.New("secondary failed")
+	session, err := NewRaceProvider(
+		&fakeProvider{sessions: []ports.StreamingSession{primary}},
+		&fakeProvider{sessions: []ports.StreamingSession{secondary}},
+		RaceConfig{},
+	).StartStreaming(context.Background(), ports.StreamingConfig{})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	_ = session.CloseSend()
+	for range session.Events() {
+	}
+	if err := session.Wait(); err == nil || err.Error() != "primary failed" {
+		t.Fatalf("expected the primary error, got %v", err)
+	}
+}
+
+func TestRaceProviderForwardsFinalizeAndReconnect(t *testing.T) {
+	t.Parallel()
+
+	primary := &finalizingStreamingSession{fakeStreamingSession: newFakeStreamingSession()}
+	secondary := &reconnectingStreamingSession{fakeStreamingSession: newFakeStreamingSession()}
+	race := NewRaceProvider(
+		&fakeProvider{sessions: []ports.StreamingSession{primary}},
+		&fakeProvider{sessions: []ports.StreamingSession{secondary}},
+		RaceConfig{},
+	)
+	session, err := race.StartStreaming(context.Background(), ports.StreamingConfig{})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	defer session.Close()
+
+	// The secondary cannot finalize, so the caller still waits for it.
+	err = session.(ports.StreamFinalizer).Finalize(context.Background())
+	if !errors.Is(err, errors.ErrUnsupported) || primary.finalizeCalls != 1 {
+		t.Fatalf("expected the primary finalized and unsupported reported, got %v (%d calls)", err, primary.finalizeCalls)
+	}
+	session.(ports.ReconnectNotifier).OnReconnect(func(error) {})
+	if secondary.notify == nil {
+		t.Fatalf("expected the reconnect handler forwarded")
+	}
+}
+
+type capabilityProvider struct {
+	fakeProvider
+	capabilities domain.Capabilities
+}
+
+func (p *capabilityProvider) Capabilities() domain.Capabilities { return p.capabilities }
+
+func TestRaceProviderCapabilitiesIntersect(t *testing.T) {
+	t.Parallel()
+
+	race := NewRaceProvider(
+		&capabilityProvider{capabilities: domain.Capabilities{Streaming: true, LivePartials: true, Confidence: true, Keywords: true, Languages: []string{"en", "de"}}},
+		&capabilityProvider{capabilities: domain.Capabilities{Confidence: true, Languages: []string{"de", "fr"}}},
+		RaceConfig{},
+	)
+	got := race.Capabilities()
+	if !got.Streaming || !got.LivePartials || !got.Confidence || got.Keywords || len(got.Languages) != 1 || got.Languages[0] != "de" {
+		t.Fatalf("unexpected capabilities: %+v", got)
+	}
+}
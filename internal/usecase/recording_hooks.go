@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// SetRecordingHooks runs hooks around the capture of sessions started
+// afterwards. Hook failures are reported as recording hook errors and never
+// stop a recording.
+func (c *SessionController) SetRecordingHooks(hooks ...ports.RecordingHook) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks = append([]ports.RecordingHook(nil), hooks...)
+}
+
+// prepareRecording runs every RecordingStarting hook in order and returns the
+// matching restore step, which runs the RecordingStopped hooks in reverse
+// order the first time it is called. Restoring is synchronous so a restarted
+// recording never races the previous session's restore.
+func (c *SessionController) prepareRecording(ctx context.Context) func() {
+	c.mu.Lock()
+	hooks := c.hooks
+	c.mu.Unlock()
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	// Restoring outlives the session that prepared it.
+	ctx = context.WithoutCancel(ctx)
+	for _, hook := range hooks {
+		if err := hook.RecordingStarting(ctx); err != nil {
+			debuglog.Printf("recording start hook failed: %v", err)
+			c.events.SessionError(domain.ErrorCodeRecordingHook, err.Error())
+		}
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			for i := len(hooks) - 1; i >= 0; i-- {
+				if err := hooks[i].RecordingStopped(ctx); err != nil {
+					debuglog.Printf("recording stop hook failed: %v", err)
+					c.events.SessionError(domain.ErrorCodeRecordingHook, err.Error())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+type fakeRecordingHook struct {
+	name  string
+	log   *[]string
+	mu    *sync.Mutex
+	start error
+	stop  error
+}
+
+func (h *fakeRecordingHook) record(call string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	*h.log = append(*h.log, h.name+":"+call)
+}
+
+func (h *fakeRecordingHook) RecordingStarting(context.Context) error {
+	h.record("start")
+	return h.start
+}
+
+func (h *fakeRecordingHook) RecordingStopped(context.Context) error {
+	h.record("stop")
+	return h.stop
+}
+
+func TestSessionControllerRunsRecordingHooksAroundCapture(t *testing.T) {
+	t.Parallel()
+
+	var log []string
+	var mu sync.Mutex
+	music := &fakeRecordingHook{name: "music", log: &log, mu: &mu, start: errors.New("no player running")}
+	speakers := &fakeRecordingHook{name: "speakers", log: &log, mu: &mu}
+
+	streamSession := newFakeStreamingSession()
+	streamSession.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "hello"}
+	events := &fakeEventSink{}
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{}, &fakeAudioSession{}}},
+		&fakeProvider{sessions: []ports.StreamingSession{streamSession, newFakeStreamingSession()}},
+		&fakeRules{transform: "hello"},
+		&fakeClipboard{},
+		events,
+		Config{},
+	)
+	controller.SetRecordingHooks(music, speakers)
+
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if _, err := controller.Stop(context.Background()); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("second start failed: %v", err)
+	}
+	if err := controller.Abort(); err != nil {
+		t.Fatalf("abort failed: %v", err)
+	}
+
+	want := []string{"music:start", "speakers:start", "speakers:stop", "music:stop"}
+	want = append(want, want...)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(log) != len(want) {
+		t.Fatalf("unexpected hook calls: %v", log)
+	}
+	for i := range want {
+		if log[i] != want[i] {
+			t.Fatalf("unexpected hook calls: %v", log)
+		}
+	}
+
+	errs := events.snapshotErrors()
+	if len(errs) != 2 || errs[0].code != domain.ErrorCodeRecordingHook || errs[0].detail != "no player running" {
+		t.Fatalf("expected non-fatal hook errors, got %+v", errs)
+	}
+}
+
+func TestSessionControllerRestoresWhenCaptureFails(t *testing.T) {
+	t.Parallel()
+
+	var log []string
+	var mu sync.Mutex
+	hook := &fakeRecordingHook{name: "music", log: &log, mu: &mu}
+	controller := NewSessionController(
+		&fakeAudioCapture{},
+		&fakeProvider{sessions: []ports.StreamingSession{newFakeStreamingSession()}},
+		&fakeRules{},
+		&fakeClipboard{},
+		&fakeEventSink{},
+		Config{},
+	)
+	controller.SetRecordingHooks(hook)
+
+	if err := controller.Start(context.Background()); err == nil {
+		t.Fatalf("expected capture failure")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(log) != 2 || log[1] != "music:stop" {
+		t.Fatalf("expected restore after failed capture, got %v", log)
+	}
+}
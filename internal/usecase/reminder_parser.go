@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+// defaultReminderHour is used when a dictation names a day but no time.
+const defaultReminderHour = 9
+
+var (
+	reminderLead     = regexp.MustCompile(`(?i)^\s*(?:please\s+)?remind\s+me\s+`)
+	reminderRelative = regexp.MustCompile(`(?i)\bin\s+(half\s+an\s+hour|an?|one|two|three|four|five|six|seven|eight|nine|ten|eleven|twelve|fifteen|twenty|thirty|forty[- ]five|\d+)\s+(minutes?|mins?|hours?|days?|weeks?)\b`)
+	reminderHalfHour = regexp.MustCompile(`(?i)\bin\s+half\s+an\s+hour\b`)
+	reminderDay      = regexp.MustCompile(`(?i)\b(?:on\s+|next\s+)?(today|tonight|tomorrow|monday|tuesday|wednesday|thursday|friday|saturday|sunday)\b`)
+	reminderClock    = regexp.MustCompile(`(?i)\b(?:at\s+)?(noon|midnight|(\d{1,2})(?::(\d{2}))?\s*(a\.?m\.?|p\.?m\.?|o'clock)?)(?:\s|$|[,.!?])`)
+	reminderAtWord   = regexp.MustCompile(`(?i)\bat\s+`)
+	reminderFiller   = regexp.MustCompile(`(?i)^(?:to|that|about)\s+|\s+(?:to|at|on)$`)
+)
+
+var reminderNumbers = map[string]int{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+	"fifteen": 15, "twenty": 20, "thirty": 30, "forty-five": 45, "forty five": 45,
+}
+
+var reminderWeekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+// parseReminder reads dictations such as "remind me to call Sam at 5 pm",
+// "remind me tomorrow at 9:30 to send the report", or "in 20 minutes check
+// the oven". The remaining words, without the time phrases, are the text.
+func parseReminder(text string, now time.Time) (domain.Reminder, error) {
+	body := reminderLead.ReplaceAllString(strings.TrimSpace(text), "")
+
+	if match := reminderHalfHour.FindStringIndex(body); match != nil {
+		return newReminder(cutSpan(body, match), now.Add(30*time.Minute))
+	}
+	if match := reminderRelative.FindStringSubmatchIndex(body); match != nil {
+		count, ok := reminderNumbers[strings.ToLower(body[match[2]:match[3]])]
+		if !ok {
+			count, _ = strconv.Atoi(body[match[2]:match[3]])
+		}
+		unit := strings.ToLower(body[match[4]:match[5]])
+		var step time.Duration
+		switch {
+		case strings.HasPrefix(unit, "min"):
+			step = time.Minute
+		case strings.HasPrefix(unit, "hour"):
+			step = time.Hour
+		case strings.HasPrefix(unit, "day"):
+			step = 24 * time.Hour
+		default:
+			step = 7 * 24 * time.Hour
+		}
+		return newReminder(cutSpan(body, match), now.Add(time.Duration(count)*step))
+	}
+
+	day, dayWord, dayFound := now, "", false
+	if match := reminderDay.FindStringSubmatchIndex(body); match != nil {
+		dayWord = strings.ToLower(body[match[2]:match[3]])
+		dayFound = true
+		switch dayWord {
+		case "today", "tonight":
+		case "tomorrow":
+			day = now.AddDate(0, 0, 1)
+		default:
+			ahead := (int(reminderWeekdays[dayWord]) - int(now.Weekday()) + 7) % 7
+			if ahead == 0 {
+				ahead = 7
+			}
+			day = now.AddDate(0, 0, ahead)
+		}
+		body = cutSpan(body, match)
+	}
+
+	hour, minute, meridiem, clockFound, named := 0, 0, "", false, false
+	for _, match := range reminderClock.FindAllStringSubmatchIndex(body, -1) {
+		word := strings.ToLower(body[match[2]:match[3]])
+		hasAt := reminderAtWord.MatchString(body[match[0]:match[2]])
+		if match[8] < 0 && !hasAt && word != "noon" && word != "midnight" {
+			// A bare number is only a time with "at" or am/pm ("call 3 people").
+			continue
+		}
+		switch word {
+		case "noon":
+			hour, named = 12, true
+		case "midnight":
+			hour, named = 0, true
+		default:
+			hour, _ = strconv.Atoi(body[match[4]:match[5]])
+			if match[6] >= 0 {
+				minute, _ = strconv.Atoi(body[match[6]:match[7]])
+			}
+			if match[8] >= 0 {
+				meridiem = strings.ToLower(strings.NewReplacer(".", "", "'", "").Replace(body[match[8]:match[9]]))
+			}
+		}
+		if hour > 23 || minute > 59 || (meridiem != "" && meridiem != "oclock" && hour > 12) {
+			return domain.Reminder{}, fmt.Errorf("invalid reminder time %q", strings.TrimSpace(body[match[0]:match[1]]))
+		}
+		clockFound = true
+		end := match[3]
+		body = strings.TrimSpace(body[:match[0]] + " " + body[end:])
+		break
+	}
+
+	if !dayFound && !clockFound {
+		return domain.Reminder{}, errors.New("reminder has no time; say e.g. \"at 5 pm\", \"tomorrow\", or \"in 20 minutes\"")
+	}
+
+	switch meridiem {
+	case "pm":
+		if hour < 12 {
+			hour += 12
+		}
+	case "am":
+		if hour == 12 {
+			hour = 0
+		}
+	}
+	if !clockFound {
+		hour = defaultReminderHour
+		if dayWord == "tonight" {
+			hour = 20
+		}
+	} else if dayWord == "tonight" && hour < 12 {
+		hour += 12
+	}
+
+	at := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, now.Location())
+	if clockFound && !named && meridiem != "am" && meridiem != "pm" && hour < 12 && at.Before(now) && !dayFound {
+		// "at 5" said in the afternoon means 5 pm.
+		at = at.Add(12 * time.Hour)
+	}
+	if at.Before(now) && !dayFound {
+		at = at.AddDate(0, 0, 1)
+	}
+	if !at.After(now) {
+		return domain.Reminder{}, fmt.Errorf("reminder time %s is in the past", at.Format("Mon 15:04"))
+	}
+	return newReminder(body, at)
+}
+
+func newReminder(body string, at time.Time) (domain.Reminder, error) {
+	text := strings.Join(strings.Fields(body), " ")
+	for {
+		trimmed := strings.TrimSpace(reminderFiller.ReplaceAllString(strings.Trim(text, " ,.!?"), ""))
+		if trimmed == text {
+			break
+		}
+		text = trimmed
+	}
+	if text == "" {
+		return domain.Reminder{}, errors.New("reminder has no text")
+	}
+	return domain.Reminder{Text: text, At: at}, nil
+}
+
+// cutSpan removes the match spanning loc[0]:loc[1] from text.
+func cutSpan(text string, loc []int) string {
+	return text[:loc[0]] + " " + text[loc[1]:]
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+func TestParseReminder(t *testing.T) {
+	t.Parallel()
+
+	// Thursday afternoon.
+	now := time.Date(2026, 10, 15, 14, 0, 0, 0, time.UTC)
+	cases := []struct {
+		text string
+		want string
+		at   time.Time
+	}{
+		{"Remind me to call Sam at 5 PM.", "call Sam", time.Date(2026, 10, 15, 17, 0, 0, 0, time.UTC)},
+		{"remind me to call 3 people at 5", "call 3 people", time.Date(2026, 10, 15, 17, 0, 0, 0, time.UTC)},
+		{"remind me at 9:30 a.m. to stretch", "stretch", time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)},
+		{"Remind me tomorrow at noon to send the report", "send the report", time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)},
+		{"remind me in 20 minutes to check the oven", "check the oven", now.Add(20 * time.Minute)},
+		{"in half an hour take the bread out", "take the bread out", now.Add(30 * time.Minute)},
+		{"remind me in two hours about the invoice", "the invoice", now.Add(2 * time.Hour)},
+		{"remind me on Friday to water the plants", "water the plants", time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)},
+		{"remind me next thursday to renew the lease", "renew the lease", time.Date(2026, 10, 22, 9, 0, 0, 0, time.UTC)},
+		{"remind me tonight at 8 to take out the trash", "take out the trash", time.Date(2026, 10, 15, 20, 0, 0, 0, time.UTC)},
+		{"remind me at 17:45 to leave", "leave", time.Date(2026, 10, 15, 17, 45, 0, 0, time.UTC)},
+		{"remind me at midnight to lock up", "lock up", time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		reminder, err := parseReminder(tc.text, now)
+		if err != nil {
+			t.Fatalf("%q: parse failed: %v", tc.text, err)
+		}
+		if reminder.Text != tc.want || !reminder.At.Equal(tc.at) {
+			t.Fatalf("%q: got %q at %s, want %q at %s", tc.text, reminder.Text, reminder.At, tc.want, tc.at)
+		}
+	}
+}
+
+func TestParseReminderRejectsUnusableDictation(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 14, 0, 0, 0, time.UTC)
+	for _, text := range []string{
+		"remind me to buy milk",
+		"remind me at 25:00 to sleep",
+		"remind me tomorrow",
+		"remind me today at 9 am to stand up",
+	} {
+		if _, err := parseReminder(text, now); err == nil {
+			t.Fatalf("%q: expected parse error", text)
+		}
+	}
+}
+
+type fakeReminderCreator struct {
+	created []domain.Reminder
+}
+
+func (f *fakeReminderCreator) CreateReminder(_ context.Context, reminder domain.Reminder) error {
+	f.created = append(f.created, reminder)
+	return nil
+}
+
+func TestReminderTargetCreatesParsedReminder(t *testing.T) {
+	t.Parallel()
+
+	creator := &fakeReminderCreator{}
+	target := NewReminderTarget(creator)
+	target.now = func() time.Time { return time.Date(2026, 10, 15, 14, 0, 0, 0, time.UTC) }
+
+	if err := target.Deliver(context.Background(), "Remind me in 5 minutes to stand up."); err != nil {
+		t.Fatalf("deliver failed: %v", err)
+	}
+	if len(creator.created) != 1 || creator.created[0].Text != "stand up" {
+		t.Fatalf("unexpected reminders: %+v", creator.created)
+	}
+	if err := target.Deliver(context.Background(), "buy milk"); err == nil || len(creator.created) != 1 {
+		t.Fatalf("expected dictation without a time to be rejected")
+	}
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"coldmic/internal/ports"
+)
+
+// ReminderTarget creates a reminder from dictations such as "remind me to
+// call Sam at 5 pm". The transcript is still copied, so a dictation that
+// cannot be parsed is not lost.
+type ReminderTarget struct {
+	creator ports.ReminderCreator
+	now     func() time.Time
+}
+
+func NewReminderTarget(creator ports.ReminderCreator) *ReminderTarget {
+	return &ReminderTarget{creator: creator, now: time.Now}
+}
+
+func (t *ReminderTarget) Name() string {
+	return "reminder"
+}
+
+func (t *ReminderTarget) Format(text string) (string, error) {
+	return strings.TrimSpace(text), nil
+}
+
+func (t *ReminderTarget) Deliver(ctx context.Context, text string) error {
+	reminder, err := parseReminder(text, t.now())
+	if err != nil {
+		return err
+	}
+	return t.creator.CreateReminder(ctx, reminder)
+}
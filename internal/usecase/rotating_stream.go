@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// defaultRotateMaxWait bounds how long a pending rotation waits for the
+// speaker to pause before the new session takes over anyway.
+const defaultRotateMaxWait = 5 * time.Second
+
+// rotatingStream presents a chain of provider sessions as one stream. A
+// replacement session takes over at the next utterance boundary, and the
+// retired session keeps delivering its pending finals, so switching providers
+// mid-recording does not drop or reorder transcript text.
+type rotatingStream struct {
+	clock          ports.Clock
+	bytesPerSecond int64
+	maxWait        time.Duration
+	out            chan domain.TranscriptEvent
+	forwarders     sync.WaitGroup
+	closeOut       sync.Once
+
+	mu          sync.Mutex
+	current     *streamLeg
+	next        *streamLeg
+	requestedAt time.Time
+	retired     []*streamLeg
+	sentBytes   int64
+	closed      bool
+}
+
+type streamLeg struct {
+	session ports.StreamingSession
+	// base shifts provider offsets so they stay relative to the first leg.
+	base     time.Duration
+	speaking atomic.Bool
+}
+
+// newRotatingStream wraps session; clock times the wait for a pause.
+func newRotatingStream(session ports.StreamingSession, cfg ports.StreamingConfig, clock ports.Clock) *rotatingStream {
+	r := &rotatingStream{
+		clock:          clock,
+		bytesPerSecond: int64(cfg.SampleRate) * int64(max(cfg.Channels, 1)) * 2,
+		maxWait:        defaultRotateMaxWait,
+		out:            make(chan domain.TranscriptEvent, 64),
+		current:        &streamLeg{session: session},
+	}
+	r.forward(r.current)
+	return r
+}
+
+// Rotate schedules session to replace the current one at the next pause in
+// speech. A rotation still pending is superseded.
+func (r *rotatingStream) Rotate(session ports.StreamingSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return errors.New("stream is already closing")
+	}
+	if r.next != nil {
+		_ = r.next.session.Close()
+	}
+	r.next = &streamLeg{session: session}
+	r.requestedAt = r.clock.Now()
+	return nil
+}
+
+func (r *rotatingStream) SendAudio(chunk []byte) error {
+	r.mu.Lock()
+	var retiring *streamLeg
+	if r.next != nil && (!r.current.speaking.Load() || r.clock.Now().Sub(r.requestedAt) >= r.maxWait) {
+		retiring = r.current
+		r.next.base = r.offsetLocked()
+		r.current = r.next
+		r.next = nil
+		r.retired = append(r.retired, retiring)
+		r.forward(r.current)
+		debuglog.Printf("streaming session rotated at offset=%s", r.current.base)
+	}
+	current := r.current
+	r.sentBytes += int64(len(chunk))
+	r.mu.Unlock()
+
+	if retiring != nil {
+		if err := retiring.session.CloseSend(); err != nil {
+			debuglog.Printf("retired streaming session close send failed: %v", err)
+		}
+	}
+	return current.session.SendAudio(chunk)
+}
+
+func (r *rotatingStream) CloseSend() error {
+	r.mu.Lock()
+	r.closed = true
+	pending := r.next
+	r.next = nil
+	current := r.current
+	r.mu.Unlock()
+
+	if pending != nil {
+		_ = pending.session.Close()
+	}
+	err := current.session.CloseSend()
+	r.closeWhenDrained()
+	return err
+}
+
+func (r *rotatingStream) Events() <-chan domain.TranscriptEvent {
+	return r.out
+}
+
+// Wait waits for every session and reports the error of the active one.
+func (r *rotatingStream) Wait() error {
+	r.mu.Lock()
+	current := r.current
+	retired := append([]*streamLeg(nil), r.retired...)
+	r.mu.Unlock()
+
+	for _, leg := range retired {
+		if err := leg.session.Wait(); err != nil {
+			debuglog.Printf("retired streaming session ended with error: %v", err)
+		}
+	}
+	return current.session.Wait()
+}
+
+func (r *rotatingStream) Close() error {
+	r.mu.Lock()
+	r.closed = true
+	legs := append([]*streamLeg{r.current}, r.retired...)
+	if r.next != nil {
+		legs = append(legs, r.next)
+		r.next = nil
+	}
+	r.mu.Unlock()
+
+	var firstErr error
+	for _, leg := range legs {
+		if err := leg.session.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	r.closeWhenDrained()
+	return firstErr
+}
+
+func (r *rotatingStream) forward(leg *streamLeg) {
+	r.forwarders.Add(1)
+	go func() {
+		defer r.forwarders.Done()
+		for event := range leg.session.Events() {
+			leg.speaking.Store(event.Kind == domain.TranscriptKindPartial)
+			event.Start += leg.base
+			if leg.base > 0 && len(event.Words) > 0 {
+				words := make([]domain.TranscriptWord, len(event.Words))
+				for i, word := range event.Words {
+					word.Start += leg.base
+					word.End += leg.base
+					words[i] = word
+				}
+				event.Words = words
+			}
+			r.out <- event
+		}
+	}()
+}
+
+func (r *rotatingStream) closeWhenDrained() {
+	r.closeOut.Do(func() {
+		go func() {
+			r.forwarders.Wait()
+			close(r.out)
+		}()
+	})
+}
+
+func (r *rotatingStream) offsetLocked() time.Duration {
+	if r.bytesPerSecond <= 0 {
+		return 0
+	}
+	return time.Duration(r.sentBytes * int64(time.Second) / r.bytesPerSecond)
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestRotatingStreamHandsOverAtUtteranceBoundary(t *testing.T) {
+	t.Parallel()
+
+	old := &countingStreamingSession{fakeStreamingSession: newFakeStreamingSession()}
+	next := &countingStreamingSession{fakeStreamingSession: newFakeStreamingSession()}
+	stream := newRotatingStream(old, ports.StreamingConfig{SampleRate: 16000, Channels: 1}, newFakeClock())
+	second := make([]byte, 32000)
+
+	old.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindPartial, Text: "hel"}
+	receiveEvent(t, stream)
+	if err := stream.Rotate(next); err != nil {
+		t.Fatalf("rotate failed: %v", err)
+	}
+
+	// Mid-utterance audio stays on the old session.
+	if err := stream.SendAudio(second); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	if old.sent != 1 || next.sent != 0 {
+		t.Fatalf("expected audio on old session while speaking, got old=%d next=%d", old.sent, next.sent)
+	}
+
+	old.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "hello", Duration: time.Second}
+	receiveEvent(t, stream)
+	if err := stream.SendAudio(second); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	if next.sent != 1 || old.closeSend != 1 {
+		t.Fatalf("expected handover after final, got next=%d old close sends=%d", next.sent, old.closeSend)
+	}
+
+	next.events <- domain.TranscriptEvent{
+		Kind:     domain.TranscriptKindFinal,
+		Text:     "world",
+		Start:    250 * time.Millisecond,
+		Duration: time.Second,
+		Words:    []domain.TranscriptWord{{Text: "world", Start: 250 * time.Millisecond, End: 750 * time.Millisecond}},
+	}
+	if event := receiveEvent(t, stream); event.Text != "world" || event.Start != 1250*time.Millisecond || event.Words[0].Start != 1250*time.Millisecond || event.Words[0].End != 1750*time.Millisecond {
+		t.Fatalf("expected offset relative to the first session, got %+v", event)
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("close send failed: %v", err)
+	}
+	select {
+	case _, ok := <-stream.Events():
+		if ok {
+			t.Fatalf("expected no further events")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected events channel to close once all sessions drain")
+	}
+}
+
+func TestRotatingStreamHandsOverAfterMaxWait(t *testing.T) {
+	t.Parallel()
+
+	old := &countingStreamingSession{fakeStreamingSession: newFakeStreamingSession()}
+	next := &countingStreamingSession{fakeStreamingSession: newFakeStreamingSession()}
+	clock := newFakeClock()
+	stream := newRotatingStream(old, ports.StreamingConfig{SampleRate: 16000, Channels: 1}, clock)
+	chunk := make([]byte, 3200)
+
+	old.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindPartial, Text: "and then"}
+	receiveEvent(t, stream)
+	if err := stream.Rotate(next); err != nil {
+		t.Fatalf("rotate failed: %v", err)
+	}
+	clock.Advance(defaultRotateMaxWait - time.Millisecond)
+	if err := stream.SendAudio(chunk); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	if next.sent != 0 {
+		t.Fatalf("expected the speaker to keep the old session before the wait ends")
+	}
+
+	// A speaker who never pauses is handed over once the wait is up.
+	clock.Advance(time.Millisecond)
+	if err := stream.SendAudio(chunk); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	if next.sent != 1 || old.closeSend != 1 {
+		t.Fatalf("expected handover after the wait, got next=%d old close sends=%d", next.sent, old.closeSend)
+	}
+	_ = stream.CloseSend()
+}
+
+func receiveEvent(t *testing.T, stream *rotatingStream) domain.TranscriptEvent {
+	t.Helper()
+	select {
+	case event := <-stream.Events():
+		return event
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for transcript event")
+		return domain.TranscriptEvent{}
+	}
+}
+
+type countingStreamingSession struct {
+	*fakeStreamingSession
+	sent int
+}
+
+func (f *countingStreamingSession) SendAudio(_ []byte) error {
+	f.sent++
+	return nil
+}
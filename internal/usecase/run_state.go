@@ -0,0 +1,20 @@
+package usecase
+
+import "sync/atomic"
+
+// runState records whether a background subsystem's loop is running, for
+// resource reports.
+type runState struct {
+	running atomic.Bool
+}
+
+// Running reports whether the loop is running now.
+func (s *runState) Running() bool {
+	return s.running.Load()
+}
+
+// begin marks the loop running and returns the func marking it stopped.
+func (s *runState) begin() func() {
+	s.running.Store(true)
+	return func() { s.running.Store(false) }
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"strings"
+	"sync"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// maxSessionLogEntries bounds the log of a long session; about an hour of
+// audio at the default chunk size.
+const maxSessionLogEntries = 50000
+
+// sessionLog records the internal events of one session in order. A nil
+// sessionLog records nothing, so batch transcription can share the helpers
+// that log live sessions.
+type sessionLog struct {
+	clock  ports.Clock
+	mu     sync.Mutex
+	log    domain.SessionLog
+	chunks int
+	// bytes is the audio sent, counted past the entry limit for usage.
+	bytes int64
+	// issues are the warnings reported on the session's result.
+	issues []domain.SessionWarning
+}
+
+// newSessionLog starts a log whose entries are timed on clock.
+func newSessionLog(sessionID string, clock ports.Clock) *sessionLog {
+	return &sessionLog{clock: clock, log: domain.SessionLog{SessionID: sessionID, Started: clock.Now()}}
+}
+
+func (l *sessionLog) state(state domain.SessionState, reason domain.SessionStateReason) {
+	l.add(domain.SessionLogEntry{Kind: domain.SessionLogState, State: state, Reason: reason})
+}
+
+// chunk records an audio chunk of n bytes sent to the provider.
+func (l *sessionLog) chunk(n int) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	l.chunks++
+	l.bytes += int64(n)
+	l.mu.Unlock()
+	l.add(domain.SessionLogEntry{Kind: domain.SessionLogAudio, Bytes: n})
+}
+
+// transcript records a provider event by kind and word count only.
+func (l *sessionLog) transcript(event domain.TranscriptEvent) {
+	l.add(domain.SessionLogEntry{
+		Kind:        domain.SessionLogTranscript,
+		Transcript:  event.Kind,
+		SpeechFinal: event.IsSpeechFinal,
+		Words:       len(strings.Fields(event.Text)),
+		Channel:     event.Channel,
+	})
+}
+
+func (l *sessionLog) error(code domain.ErrorCode) {
+	l.add(domain.SessionLogEntry{Kind: domain.SessionLogError, Code: code})
+}
+
+// warn records a non-fatal issue that may make the transcript less
+// trustworthy, as an error entry and as a warning on the session's result.
+func (l *sessionLog) warn(code domain.ErrorCode, message string) {
+	if l == nil {
+		return
+	}
+	l.error(code)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.issues = addWarning(l.issues, code, message)
+}
+
+// warnings returns the issues recorded with warn, in order.
+func (l *sessionLog) warnings() []domain.SessionWarning {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]domain.SessionWarning(nil), l.issues...)
+}
+
+// addWarning appends a warning, or counts a repeat of an earlier one with
+// the same code and message.
+func addWarning(warnings []domain.SessionWarning, code domain.ErrorCode, message string) []domain.SessionWarning {
+	for i := range warnings {
+		if warnings[i].Code == code && warnings[i].Message == message {
+			warnings[i].Count++
+			return warnings
+		}
+	}
+	return append(warnings, domain.SessionWarning{Code: code, Message: message, Count: 1})
+}
+
+func (l *sessionLog) add(entry domain.SessionLogEntry) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.log.Entries) >= maxSessionLogEntries {
+		l.log.Truncated = true
+		return
+	}
+	entry.Seq = len(l.log.Entries) + 1
+	entry.At = l.clock.Now().Sub(l.log.Started)
+	entry.Chunk = l.chunks
+	l.log.Entries = append(l.log.Entries, entry)
+}
+
+// sentBytes returns the bytes of audio sent to the provider so far.
+func (l *sessionLog) sentBytes() int64 {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.bytes
+}
+
+func (l *sessionLog) snapshot() domain.SessionLog {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := l.log
+	out.Entries = append([]domain.SessionLogEntry(nil), l.log.Entries...)
+	return out
+}
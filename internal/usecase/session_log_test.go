@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestSessionControllerRecordsRedactedSessionLog(t *testing.T) {
+	t.Parallel()
+
+	stream := newFakeStreamingSession()
+	stream.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindPartial, Text: "secret"}
+	stream.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "secret words", IsSpeechFinal: true}
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{&fakeAudioSession{chunks: [][]byte{[]byte("abc")}}}},
+		&fakeProvider{sessions: []ports.StreamingSession{stream}},
+		&fakeRules{},
+		&fakeClipboard{},
+		&fakeEventSink{},
+		Config{},
+	)
+	if _, err := controller.SessionLog(); !errors.Is(err, domain.ErrNoSessionLog) {
+		t.Fatalf("expected no log before a session, got %v", err)
+	}
+
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	result, err := controller.Stop(context.Background())
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+
+	log, err := controller.SessionLog()
+	if err != nil {
+		t.Fatalf("session log failed: %v", err)
+	}
+	if log.SessionID != result.SessionID {
+		t.Fatalf("expected log of %s, got %s", result.SessionID, log.SessionID)
+	}
+	var transcripts []domain.SessionLogEntry
+	for i, entry := range log.Entries {
+		if entry.Seq != i+1 {
+			t.Fatalf("expected entries in order, got %+v", log.Entries)
+		}
+		if entry.Kind == domain.SessionLogTranscript {
+			transcripts = append(transcripts, entry)
+		}
+	}
+	if len(transcripts) != 2 || transcripts[1].Words != 2 || !transcripts[1].SpeechFinal {
+		t.Fatalf("unexpected transcript entries: %+v", transcripts)
+	}
+	first, last := log.Entries[0], log.Entries[len(log.Entries)-1]
+	if first.State != domain.SessionStateRecording || last.State != domain.SessionStateIdle {
+		t.Fatalf("expected the log to span recording to idle, got %+v", log.Entries)
+	}
+	dump, _ := json.Marshal(log)
+	if strings.Contains(string(dump), "secret") {
+		t.Fatalf("expected transcript text to be redacted: %s", dump)
+	}
+}
+
+func TestSessionLogTimesEntriesOnClock(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	log := newSessionLog("session-1", clock)
+	clock.Advance(1500 * time.Millisecond)
+	log.chunk(512)
+
+	snapshot := log.snapshot()
+	if !snapshot.Started.Equal(clock.Now().Add(-1500*time.Millisecond)) || len(snapshot.Entries) != 1 || snapshot.Entries[0].At != 1500*time.Millisecond {
+		t.Fatalf("expected entries timed on the injected clock, got %+v", snapshot)
+	}
+}
+
+func TestSessionLogReplaysDeterministically(t *testing.T) {
+	t.Parallel()
+
+	recorded := domain.SessionLog{Entries: []domain.SessionLogEntry{
+		{Seq: 1, Kind: domain.SessionLogState, State: domain.SessionStateRecording, Reason: domain.SessionReasonRecordingStarted},
+		{Seq: 2, Kind: domain.SessionLogAudio, Chunk: 1, Bytes: 512},
+		{Seq: 3, Kind: domain.SessionLogTranscript, Chunk: 1, Transcript: domain.TranscriptKindPartial, Words: 1},
+		{Seq: 4, Kind: domain.SessionLogAudio, Chunk: 2, Bytes: 512},
+		{Seq: 5, Kind: domain.SessionLogAudio, Chunk: 3, Bytes: 256},
+		{Seq: 6, Kind: domain.SessionLogTranscript, Chunk: 3, Transcript: domain.TranscriptKindFinal, SpeechFinal: true, Words: 3},
+		{Seq: 7, Kind: domain.SessionLogState, Chunk: 3, State: domain.SessionStateStopping, Reason: domain.SessionReasonTranscribing},
+		{Seq: 8, Kind: domain.SessionLogState, Chunk: 3, State: domain.SessionStateIdle, Reason: domain.SessionReasonTranscriptCopied},
+	}}
+
+	for run := 0; run < 3; run++ {
+		replayed := replaySessionLog(t, recorded)
+		if len(replayed.Entries) != len(recorded.Entries) {
+			t.Fatalf("run %d: expected %d entries, got %+v", run, len(recorded.Entries), replayed.Entries)
+		}
+		for i, entry := range replayed.Entries {
+			entry.At = 0
+			if entry != recorded.Entries[i] {
+				t.Fatalf("run %d: entry %d differs: got %+v want %+v", run, i+1, entry, recorded.Entries[i])
+			}
+		}
+	}
+}
+
+// replaySessionLog runs a controller through recorded one entry at a time,
+// waiting for each to be logged before the next, and returns the new log.
+func replaySessionLog(t *testing.T, recorded domain.SessionLog) domain.SessionLog {
+	t.Helper()
+
+	audio := &replayAudioSession{chunks: make(chan []byte), done: make(chan struct{})}
+	stream := newFakeStreamingSession()
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{audio}},
+		&fakeProvider{sessions: []ports.StreamingSession{stream}},
+		&fakeRules{},
+		&fakeClipboard{},
+		&fakeEventSink{},
+		Config{},
+	)
+	logged := func() int {
+		log, _ := controller.SessionLog()
+		return len(log.Entries)
+	}
+
+	stopped := make(chan error, 1)
+	for _, entry := range recorded.Entries {
+		switch {
+		case entry.Kind == domain.SessionLogState && entry.State == domain.SessionStateRecording:
+			if err := controller.Start(context.Background()); err != nil {
+				t.Fatalf("start failed: %v", err)
+			}
+		case entry.Kind == domain.SessionLogState && entry.State == domain.SessionStateStopping:
+			go func() {
+				_, err := controller.Stop(context.Background())
+				stopped <- err
+			}()
+		case entry.Kind == domain.SessionLogAudio:
+			audio.chunks <- make([]byte, entry.Bytes)
+		case entry.Kind == domain.SessionLogTranscript:
+			stream.events <- domain.TranscriptEvent{
+				Kind:          entry.Transcript,
+				Text:          strings.TrimSpace(strings.Repeat("word ", entry.Words)),
+				IsSpeechFinal: entry.SpeechFinal,
+				Channel:       entry.Channel,
+			}
+		}
+		waitFor(t, func() bool { return logged() >= entry.Seq })
+	}
+	if err := <-stopped; err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	log, _ := controller.SessionLog()
+	return log
+}
+
+// replayAudioSession hands out the chunks sent on chunks until stopped.
+type replayAudioSession struct {
+	chunks chan []byte
+	done   chan struct{}
+	once   sync.Once
+}
+
+func (r *replayAudioSession) Read(p []byte) (int, error) {
+	select {
+	case chunk := <-r.chunks:
+		return copy(p, chunk), nil
+	case <-r.done:
+		return 0, io.EOF
+	}
+}
+
+func (r *replayAudioSession) Close() error { return nil }
+
+func (r *replayAudioSession) Stop() error {
+	r.once.Do(func() { close(r.done) })
+	return nil
+}
@@ -24,8 +24,36 @@ func (s *SessionService) Start(ctx context.Context) error {
 	return s.controller.Start(ctx)
 }
 
+// StartSession starts a session alongside any running ones and returns its ID.
+func (s *SessionService) StartSession(ctx context.Context) (string, error) {
+	return s.controller.StartSession(ctx)
+}
+
 func (s *SessionService) Stop(ctx context.Context) (domain.StopResult, error) {
-	result, err := s.controller.Stop(ctx)
+	return s.StopSession(ctx, "")
+}
+
+// StopSession stops the session with id, or the latest when id is empty.
+func (s *SessionService) StopSession(ctx context.Context, id string) (domain.StopResult, error) {
+	result, err := s.controller.StopSession(ctx, id)
+	if err != nil {
+		return domain.StopResult{}, err
+	}
+
+	s.mu.Lock()
+	s.latest = &domain.LatestTranscript{
+		Result:     result,
+		CapturedAt: time.Now().UTC(),
+	}
+	s.mu.Unlock()
+
+	return result, nil
+}
+
+// RetryLastSession re-transcribes the last low-confidence session and makes
+// the new transcript the latest one.
+func (s *SessionService) RetryLastSession(ctx context.Context) (domain.StopResult, error) {
+	result, err := s.controller.RetryLastSession(ctx)
 	if err != nil {
 		return domain.StopResult{}, err
 	}
@@ -44,10 +72,25 @@ func (s *SessionService) Abort() error {
 	return s.controller.Abort()
 }
 
+// AbortSession discards the session with id, or the latest when id is empty.
+func (s *SessionService) AbortSession(id string) error {
+	return s.controller.AbortSession(id)
+}
+
 func (s *SessionService) Status() domain.Status {
 	return s.controller.Status()
 }
 
+// SessionStatus reports the session with id, or the latest when id is empty.
+func (s *SessionService) SessionStatus(id string) domain.Status {
+	return s.controller.SessionStatus(id)
+}
+
+// Sessions returns the IDs of the running sessions in start order.
+func (s *SessionService) Sessions() []string {
+	return s.controller.Sessions()
+}
+
 func (s *SessionService) LastTranscript() (domain.LatestTranscript, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -56,3 +99,27 @@ func (s *SessionService) LastTranscript() (domain.LatestTranscript, error) {
 	}
 	return *s.latest, nil
 }
+
+// Forget drops the latest transcript and the audio and event log the
+// controller keeps of the last session.
+func (s *SessionService) Forget() {
+	s.mu.Lock()
+	s.latest = nil
+	s.mu.Unlock()
+	s.controller.forgetLast()
+}
+
+// AddTemporaryRule adds a rule for the running or next session only.
+func (s *SessionService) AddTemporaryRule(line string) error {
+	return s.controller.AddTemporaryRule(line)
+}
+
+// replaceLatest swaps in a corrected copy of the latest transcript.
+func (s *SessionService) replaceLatest(result domain.StopResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latest = &domain.LatestTranscript{
+		Result:     result,
+		CapturedAt: time.Now().UTC(),
+	}
+}
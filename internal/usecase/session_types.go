@@ -9,14 +9,29 @@ import (
 
 type activeSession struct {
 	id     string
+	seq    uint64
 	cancel func()
 	audio  ports.AudioSession
 	stream ports.StreamingSession
+	// provider is the provider the stream was started on, whose usage the
+	// session is billed as.
+	provider ports.TranscriptionProvider
+	// buffer keeps the capture for a low-confidence retry; nil when disabled.
+	buffer *bufferingAudioSession
+	// restore undoes the recording hooks once capture stops; nil without hooks.
+	restore func()
+	// temporary holds the session's temporary rules, applied before the
+	// rules file.
+	temporary temporaryRules
 
 	stateMu sync.Mutex
 	state   domain.SessionState
 
 	aggregator *transcriptAggregator
+	captions   *captionTranslator
+	// copier copies utterances while recording; nil when disabled.
+	copier     *incrementalCopier
+	log        *sessionLog
 	eventsDone chan struct{}
 	audioDone  chan struct{}
 }
@@ -32,3 +47,10 @@ func (s *activeSession) getState() domain.SessionState {
 	defer s.stateMu.Unlock()
 	return s.state
 }
+
+// restoreEnvironment runs the recording hooks' restore step at most once.
+func (s *activeSession) restoreEnvironment() {
+	if s.restore != nil {
+		s.restore()
+	}
+}
@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"time"
+)
+
+const trimFrameDuration = 20 * time.Millisecond
+
+// SilenceTrimConfig controls leading/trailing silence removal for batch audio.
+// Threshold is the RMS amplitude (0-32767) below which a frame counts as silent;
+// Padding is the amount of silence kept around speech.
+type SilenceTrimConfig struct {
+	Enabled   bool
+	Threshold int
+	Padding   time.Duration
+}
+
+// offsetMap translates positions in trimmed audio back to the original recording.
+type offsetMap struct {
+	spans []removedSpan
+}
+
+type removedSpan struct {
+	at     time.Duration
+	length time.Duration
+}
+
+// Original maps a trimmed-timeline offset to the original timeline.
+func (m *offsetMap) Original(offset time.Duration) time.Duration {
+	if m == nil {
+		return offset
+	}
+	shifted := offset
+	for _, span := range m.spans {
+		if span.at <= offset {
+			shifted += span.length
+		}
+	}
+	return shifted
+}
+
+// silenceTrimmer filters s16le PCM, dropping silence before the first and
+// after the last speech frame while recording what was removed.
+type silenceTrimmer struct {
+	src           io.Reader
+	frameBytes    int
+	threshold     float64
+	paddingFrames int
+
+	started  bool
+	eof      bool
+	lead     [][]byte
+	pending  [][]byte
+	out      bytes.Buffer
+	dropped  int
+	offsets  offsetMap
+	recorded bool
+}
+
+func newSilenceTrimmer(src io.Reader, sampleRate int, channels int, cfg SilenceTrimConfig) *silenceTrimmer {
+	if sampleRate <= 0 {
+		sampleRate = 16000
+	}
+	if channels <= 0 {
+		channels = 1
+	}
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = 500
+	}
+	if cfg.Padding < 0 {
+		cfg.Padding = 0
+	}
+	samplesPerFrame := sampleRate * int(trimFrameDuration/time.Millisecond) / 1000
+	return &silenceTrimmer{
+		src:           src,
+		frameBytes:    samplesPerFrame * channels * 2,
+		threshold:     float64(cfg.Threshold),
+		paddingFrames: int(cfg.Padding / trimFrameDuration),
+	}
+}
+
+func (t *silenceTrimmer) Read(p []byte) (int, error) {
+	for t.out.Len() == 0 && !t.eof {
+		if err := t.step(); err != nil {
+			return 0, err
+		}
+	}
+	if t.out.Len() == 0 {
+		return 0, io.EOF
+	}
+	return t.out.Read(p)
+}
+
+// Offsets returns the offset map; it is complete once the reader hit EOF.
+func (t *silenceTrimmer) Offsets() *offsetMap {
+	return &t.offsets
+}
+
+func (t *silenceTrimmer) step() error {
+	frame := make([]byte, t.frameBytes)
+	n, err := io.ReadFull(t.src, frame)
+	if n > 0 {
+		t.push(frame[:n])
+	}
+	if err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			t.finish()
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func (t *silenceTrimmer) push(frame []byte) {
+	silent := frameRMS(frame) < t.threshold
+	if !t.started {
+		if silent {
+			t.lead = append(t.lead, frame)
+			if len(t.lead) > t.paddingFrames {
+				t.lead = t.lead[1:]
+				t.dropped++
+			}
+			return
+		}
+		t.started = true
+		t.recordLeading()
+		t.writeFrames(t.lead)
+		t.lead = nil
+		t.out.Write(frame)
+		return
+	}
+
+	if silent {
+		t.pending = append(t.pending, frame)
+		return
+	}
+	t.writeFrames(t.pending)
+	t.pending = nil
+	t.out.Write(frame)
+}
+
+func (t *silenceTrimmer) finish() {
+	t.eof = true
+	if !t.started {
+		return
+	}
+	keep := len(t.pending)
+	if keep > t.paddingFrames {
+		keep = t.paddingFrames
+	}
+	t.writeFrames(t.pending[:keep])
+	t.pending = nil
+}
+
+func (t *silenceTrimmer) recordLeading() {
+	if t.recorded || t.dropped == 0 {
+		return
+	}
+	t.recorded = true
+	t.offsets.spans = append(t.offsets.spans, removedSpan{at: 0, length: time.Duration(t.dropped) * trimFrameDuration})
+}
+
+func (t *silenceTrimmer) writeFrames(frames [][]byte) {
+	for _, frame := range frames {
+		t.out.Write(frame)
+	}
+}
+
+func frameRMS(frame []byte) float64 {
+	samples := len(frame) / 2
+	if samples == 0 {
+		return 0
+	}
+	var sum float64
+	for i := 0; i+1 < len(frame); i += 2 {
+		sample := float64(int16(binary.LittleEndian.Uint16(frame[i:])))
+		sum += sample * sample
+	}
+	return math.Sqrt(sum / float64(samples))
+}
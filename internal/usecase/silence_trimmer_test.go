@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+	"time"
+)
+
+// pcmFrames builds 8kHz mono s16le audio where each rune is one 20ms frame:
+// '.' is silence and '#' is a loud tone.
+func pcmFrames(pattern string) []byte {
+	var buf bytes.Buffer
+	for _, r := range pattern {
+		sample := int16(0)
+		if r == '#' {
+			sample = 8000
+		}
+		for i := 0; i < 160; i++ {
+			_ = binary.Write(&buf, binary.LittleEndian, sample)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestSilenceTrimmerDropsLeadingAndTrailingSilence(t *testing.T) {
+	t.Parallel()
+
+	trimmer := newSilenceTrimmer(bytes.NewReader(pcmFrames("......##..#.....")), 8000, 1, SilenceTrimConfig{
+		Threshold: 500,
+		Padding:   40 * time.Millisecond,
+	})
+	out, err := io.ReadAll(trimmer)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if want := pcmFrames("..##..#.."); !bytes.Equal(out, want) {
+		t.Fatalf("unexpected trimmed length: got %d bytes want %d", len(out), len(want))
+	}
+	if got := trimmer.Offsets().Original(time.Second); got != time.Second+80*time.Millisecond {
+		t.Fatalf("expected leading offset of 80ms, got %v", got)
+	}
+}
+
+func TestSilenceTrimmerAllSilence(t *testing.T) {
+	t.Parallel()
+
+	trimmer := newSilenceTrimmer(bytes.NewReader(pcmFrames("........")), 8000, 1, SilenceTrimConfig{})
+	out, err := io.ReadAll(trimmer)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if len(out) != 0 {
+		t.Fatalf("expected silence to be dropped, got %d bytes", len(out))
+	}
+}
+
+func TestOffsetMapNilIsIdentity(t *testing.T) {
+	t.Parallel()
+
+	var offsets *offsetMap
+	if got := offsets.Original(3 * time.Second); got != 3*time.Second {
+		t.Fatalf("expected identity mapping, got %v", got)
+	}
+}
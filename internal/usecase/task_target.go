@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// TaskTarget captures each dictated sentence as a task tagged with the
+// target's project and context.
+type TaskTarget struct {
+	name    string
+	sink    ports.TaskSink
+	project string
+	context string
+}
+
+func NewTaskTarget(name string, sink ports.TaskSink, project string, context string) *TaskTarget {
+	return &TaskTarget{name: name, sink: sink, project: project, context: context}
+}
+
+func (t *TaskTarget) Name() string {
+	return t.name
+}
+
+func (t *TaskTarget) Format(text string) (string, error) {
+	return strings.TrimSpace(text), nil
+}
+
+func (t *TaskTarget) Deliver(ctx context.Context, text string) error {
+	for i, sentence := range splitTranscript(text, ClipboardSplitSentences) {
+		task := domain.Task{
+			Text:    strings.TrimRight(sentence, "."),
+			Project: t.project,
+			Context: t.context,
+		}
+		if err := t.sink.AddTask(ctx, task); err != nil {
+			return fmt.Errorf("failed to add task %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"coldmic/internal/domain"
+)
+
+type fakeTaskSink struct {
+	tasks []domain.Task
+}
+
+func (f *fakeTaskSink) AddTask(_ context.Context, task domain.Task) error {
+	f.tasks = append(f.tasks, task)
+	return nil
+}
+
+func TestTaskTargetAddsOneTaskPerSentence(t *testing.T) {
+	t.Parallel()
+
+	sink := &fakeTaskSink{}
+	target := NewTaskTarget("todo", sink, "work", "office")
+	if err := target.Deliver(context.Background(), "Review the Q3 budget. Email Priya about 3.5 release notes.\nBook flights"); err != nil {
+		t.Fatalf("deliver failed: %v", err)
+	}
+	want := []domain.Task{
+		{Text: "Review the Q3 budget", Project: "work", Context: "office"},
+		{Text: "Email Priya about 3.5 release notes", Project: "work", Context: "office"},
+		{Text: "Book flights", Project: "work", Context: "office"},
+	}
+	if !reflect.DeepEqual(sink.tasks, want) {
+		t.Fatalf("unexpected tasks: %+v", sink.tasks)
+	}
+}
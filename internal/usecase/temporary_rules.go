@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"coldmic/internal/ports"
+)
+
+// temporaryRules are rules added for one session only and never written to
+// the rules file, with the lines kept so each addition recompiles them all.
+type temporaryRules struct {
+	lines  []string
+	engine ports.RulesEngine
+}
+
+// AddTemporaryRule adds one rule, in rules-file syntax, to the running
+// push-to-talk session, or to the next one when none is running. The rule
+// is dropped once that session ends.
+func (c *SessionController) AddTemporaryRule(line string) error {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return errors.New("temporary rule is empty")
+	}
+	if strings.ContainsAny(line, "\r\n") {
+		return errors.New("temporary rule must be a single line")
+	}
+	compiler, ok := c.finalizer.rules.(ports.RuleCompiler)
+	if !ok {
+		return errors.New("rules engine does not support temporary rules")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	target := &c.pendingRules
+	if c.latest != nil {
+		target = &c.latest.temporary
+	}
+	lines := append(append([]string{}, target.lines...), line)
+	engine, err := compiler.CompileRules(lines)
+	if err != nil {
+		return fmt.Errorf("invalid temporary rule: %w", err)
+	}
+	*target = temporaryRules{lines: lines, engine: engine}
+	return nil
+}
+
+// temporaryRulesOf returns the temporary rules of active, or nil.
+func (c *SessionController) temporaryRulesOf(active *activeSession) ports.RulesEngine {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return active.temporary.engine
+}
+
+// layeredRules applies first and then rest, and knows the words of both.
+type layeredRules struct {
+	first ports.RulesEngine
+	rest  ports.RulesEngine
+}
+
+func (r layeredRules) Apply(text string) (string, error) {
+	text, err := r.first.Apply(text)
+	if err != nil {
+		return "", err
+	}
+	return r.rest.Apply(text)
+}
+
+func (r layeredRules) KnownWords() []string {
+	return knownWords(r.rest, knownWords(r.first, nil))
+}
+
+func (r layeredRules) RuleWarnings() []string {
+	return append(ruleWarnings(r.first), ruleWarnings(r.rest)...)
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// fakeCompilingRules compiles "from => to" lines into plain replacements.
+type fakeCompilingRules struct {
+	fakeRules
+}
+
+func (f *fakeCompilingRules) CompileRules(lines []string) (ports.RulesEngine, error) {
+	var pairs []string
+	for _, line := range lines {
+		from, to, ok := strings.Cut(line, "=>")
+		if !ok {
+			return nil, errors.New("unsupported rule format")
+		}
+		pairs = append(pairs, strings.TrimSpace(from), strings.TrimSpace(to))
+	}
+	return replacerRules{strings.NewReplacer(pairs...)}, nil
+}
+
+type replacerRules struct {
+	replacer *strings.Replacer
+}
+
+func (r replacerRules) Apply(text string) (string, error) {
+	return r.replacer.Replace(text), nil
+}
+
+func TestSessionControllerTemporaryRules(t *testing.T) {
+	t.Parallel()
+
+	var audioSessions []ports.AudioSession
+	var streams []ports.StreamingSession
+	for i := 0; i < 3; i++ {
+		stream := newFakeStreamingSession()
+		stream.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "ask sarah kovack"}
+		streams = append(streams, stream)
+		audioSessions = append(audioSessions, &fakeAudioSession{chunks: [][]byte{[]byte("abc")}})
+	}
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: audioSessions},
+		&fakeProvider{sessions: streams},
+		&fakeCompilingRules{},
+		&fakeClipboard{},
+		&fakeEventSink{},
+		Config{ChunkSize: 512},
+	)
+	dictate := func(during string) string {
+		t.Helper()
+		if err := controller.Start(context.Background()); err != nil {
+			t.Fatalf("start failed: %v", err)
+		}
+		if during != "" {
+			if err := controller.AddTemporaryRule(during); err != nil {
+				t.Fatalf("add temporary rule: %v", err)
+			}
+		}
+		result, err := controller.Stop(context.Background())
+		if err != nil {
+			t.Fatalf("stop failed: %v", err)
+		}
+		return result.FinalTranscript
+	}
+
+	// Added while idle, the rule applies to the next session only.
+	if err := controller.AddTemporaryRule("sarah kovack => Sarah Kovač"); err != nil {
+		t.Fatalf("add temporary rule: %v", err)
+	}
+	if got := dictate(""); got != "ask Sarah Kovač" {
+		t.Fatalf("expected pending rule applied, got %q", got)
+	}
+	if got := dictate(""); got != "ask sarah kovack" {
+		t.Fatalf("expected rule gone after its session, got %q", got)
+	}
+	// Added while recording, the rule applies to the running session.
+	if got := dictate("ask => Ask"); got != "Ask sarah kovack" {
+		t.Fatalf("expected rule applied to running session, got %q", got)
+	}
+}
+
+func TestSessionControllerTemporaryRuleRejectsInvalidLines(t *testing.T) {
+	t.Parallel()
+
+	controller := NewSessionController(&fakeAudioCapture{}, &fakeProvider{}, &fakeCompilingRules{}, &fakeClipboard{}, &fakeEventSink{}, Config{})
+	for _, line := range []string{"", "  # comment", "a => b\nc => d", "not a rule"} {
+		if err := controller.AddTemporaryRule(line); err == nil {
+			t.Fatalf("expected %q to be rejected", line)
+		}
+	}
+	if controller.pendingRules.engine != nil {
+		t.Fatalf("expected no pending rules after rejected lines")
+	}
+
+	plain := NewSessionController(&fakeAudioCapture{}, &fakeProvider{}, &fakeRules{}, &fakeClipboard{}, &fakeEventSink{}, Config{})
+	if err := plain.AddTemporaryRule("a => b"); err == nil {
+		t.Fatalf("expected an error from a rules engine without RuleCompiler")
+	}
+}
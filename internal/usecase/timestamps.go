@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// TimestampMode selects how utterance timestamps are rendered.
+type TimestampMode string
+
+const (
+	TimestampModeOff       TimestampMode = "off"
+	TimestampModeOffset    TimestampMode = "offset"
+	TimestampModeWallClock TimestampMode = "wallclock"
+)
+
+// TimestampConfig controls per-utterance timestamps in file and meeting output.
+// Template must contain {ts}; Layout is the Go time layout for wall-clock mode.
+type TimestampConfig struct {
+	Mode     TimestampMode
+	Template string
+	Layout   string
+}
+
+func (c TimestampConfig) enabled() bool {
+	return c.Mode == TimestampModeOffset || c.Mode == TimestampModeWallClock
+}
+
+func (c TimestampConfig) prefix(offset time.Duration, startedAt time.Time) string {
+	template := c.Template
+	if !strings.Contains(template, "{ts}") {
+		template = "[{ts}] "
+	}
+
+	var stamp string
+	if c.Mode == TimestampModeWallClock {
+		layout := c.Layout
+		if layout == "" {
+			layout = "15:04:05"
+		}
+		stamp = startedAt.Add(offset).Format(layout)
+	} else {
+		stamp = formatOffset(offset)
+	}
+	return strings.ReplaceAll(template, "{ts}", stamp)
+}
+
+// formatTimestamped renders one line per segment with a timestamp prefix.
+// Rules are applied per segment so substitutions never touch the prefix.
+func formatTimestamped(cfg TimestampConfig, segments []domain.DialogueSegment, startedAt time.Time, rules ports.RulesEngine) (string, error) {
+	if !cfg.enabled() || len(segments) == 0 {
+		return "", nil
+	}
+
+	lines := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		text, err := rules.Apply(segment.Text)
+		if err != nil {
+			return "", err
+		}
+		line := cfg.prefix(segment.Offset, startedAt)
+		if segment.Label != "" {
+			line += segment.Label + ": "
+		}
+		lines = append(lines, line+text)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func formatOffset(offset time.Duration) string {
+	if offset < 0 {
+		offset = 0
+	}
+	total := int(offset / time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", total/3600, (total/60)%60, total%60)
+}
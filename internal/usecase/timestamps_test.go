@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestFormatTimestampedOffsetMode(t *testing.T) {
+	t.Parallel()
+
+	segments := []domain.DialogueSegment{
+		{Label: "Me", Text: "hello", Offset: 3*time.Minute + 12*time.Second},
+		{Text: "plain", Offset: time.Hour + 2*time.Second},
+	}
+	got, err := formatTimestamped(TimestampConfig{Mode: TimestampModeOffset}, segments, time.Time{}, &fakeRules{})
+	if err != nil {
+		t.Fatalf("format failed: %v", err)
+	}
+	if got != "[00:03:12] Me: hello\n[01:00:02] plain" {
+		t.Fatalf("unexpected output: %q", got)
+	}
+}
+
+func TestFormatTimestampedWallClockTemplate(t *testing.T) {
+	t.Parallel()
+
+	startedAt := time.Date(2026, 1, 2, 14, 0, 0, 0, time.UTC)
+	cfg := TimestampConfig{Mode: TimestampModeWallClock, Template: "{ts} | ", Layout: "15:04"}
+	got, err := formatTimestamped(cfg, []domain.DialogueSegment{{Text: "hi", Offset: 5 * time.Minute}}, startedAt, &fakeRules{})
+	if err != nil {
+		t.Fatalf("format failed: %v", err)
+	}
+	if got != "14:05 | hi" {
+		t.Fatalf("unexpected output: %q", got)
+	}
+}
+
+func TestFormatTimestampedDisabledAndRulesErrors(t *testing.T) {
+	t.Parallel()
+
+	segments := []domain.DialogueSegment{{Text: "x"}}
+	if got, err := formatTimestamped(TimestampConfig{Mode: TimestampModeOff}, segments, time.Now(), &fakeRules{}); got != "" || err != nil {
+		t.Fatalf("expected disabled output, got %q %v", got, err)
+	}
+	if _, err := formatTimestamped(TimestampConfig{Mode: TimestampModeOffset}, segments, time.Now(), &fakeRules{err: errors.New("rules")}); err == nil {
+		t.Fatalf("expected rules error")
+	}
+}
+
+func TestFileTranscriberTimestampsUseProviderOffsets(t *testing.T) {
+	t.Parallel()
+
+	stream := newFakeStreamingSession()
+	stream.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "first", Start: 2 * time.Second, Duration: time.Second}
+	stream.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "second", Start: 65 * time.Second, Duration: time.Second}
+	transcriber := NewFileTranscriber(
+		&fakeDecoder{data: "pcm"},
+		&fakeProvider{sessions: []ports.StreamingSession{stream}},
+		&fakeRules{},
+		Config{Timestamps: TimestampConfig{Mode: TimestampModeOffset}},
+	)
+
+	result, err := transcriber.TranscribeFile(context.Background(), "talk.wav")
+	if err != nil {
+		t.Fatalf("transcribe failed: %v", err)
+	}
+	if result.FinalTranscript != "first second" {
+		t.Fatalf("expected plain final transcript, got %q", result.FinalTranscript)
+	}
+	if result.TimestampedTranscript != "[00:00:02] first\n[00:01:05] second" {
+		t.Fatalf("unexpected timestamped transcript: %q", result.TimestampedTranscript)
+	}
+}
@@ -1,6 +1,9 @@
 package usecase
 
 import (
+	"cmp"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -8,16 +11,62 @@ import (
 	"coldmic/internal/ports"
 )
 
+// ChannelMode is how the transcripts of separately transcribed audio
+// channels, such as a microphone and a loopback of the far side, combine.
+type ChannelMode string
+
+const (
+	// ChannelModeOff transcribes all channels as one mixed stream.
+	ChannelModeOff ChannelMode = "off"
+	// ChannelModeMerge interleaves the channels' utterances by time.
+	ChannelModeMerge ChannelMode = "merge"
+	// ChannelModeLabel writes each utterance on a line labelled with its
+	// channel, like diarized speakers.
+	ChannelModeLabel ChannelMode = "label"
+)
+
+// ChannelConfig controls multichannel transcription. Labels name the
+// channels in order; unnamed channels are "Channel N".
+type ChannelConfig struct {
+	Mode   ChannelMode
+	Labels []string
+}
+
 type transcriptAggregator struct {
 	mu         sync.Mutex
+	channels   ChannelConfig
 	finals     []string
+	segments   []domain.DialogueSegment
 	lastSpoken string
+	// confidenceSum weights each final's confidence by its word count.
+	confidenceSum   float64
+	confidenceWords int
+	words           []domain.TranscriptWord
+	// languageWords counts the words of the finals by detected language.
+	languageWords map[string]int
+	// diarized is set once a final carries speaker labels.
+	diarized bool
+	// multichannel is set once a final carries its channel.
+	multichannel bool
 }
 
+// Word confidences at or above these bounds are high and medium; anything
+// lower is low.
+const (
+	highConfidence   = 0.9
+	mediumConfidence = 0.7
+)
+
 func newTranscriptAggregator() *transcriptAggregator {
 	return &transcriptAggregator{}
 }
 
+// newChannelAggregator combines the channels of a multichannel stream as
+// channels says.
+func newChannelAggregator(channels ChannelConfig) *transcriptAggregator {
+	return &transcriptAggregator{channels: channels}
+}
+
 func (a *transcriptAggregator) Add(event domain.TranscriptEvent) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
@@ -29,13 +78,147 @@ func (a *transcriptAggregator) Add(event domain.TranscriptEvent) {
 	a.lastSpoken = text
 	if event.Kind == domain.TranscriptKindFinal {
 		a.finals = append(a.finals, text)
+		if event.Channel > 0 && a.channels.Mode != "" && a.channels.Mode != ChannelModeOff {
+			segment := domain.DialogueSegment{Text: text, Offset: event.Start, Words: event.Words}
+			if a.channels.Mode == ChannelModeLabel {
+				segment.Label = a.channelLabel(event.Channel)
+			}
+			a.segments = append(a.segments, segment)
+			a.multichannel = true
+		} else if speakerSegments := splitBySpeaker(event.Words); speakerSegments != nil {
+			a.segments = append(a.segments, speakerSegments...)
+			a.diarized = true
+		} else {
+			a.segments = append(a.segments, domain.DialogueSegment{Text: text, Offset: event.Start})
+		}
+		a.words = append(a.words, event.Words...)
+		if event.Confidence > 0 {
+			words := len(strings.Fields(text))
+			a.confidenceSum += event.Confidence * float64(words)
+			a.confidenceWords += words
+		}
+		if event.Language != "" {
+			if a.languageWords == nil {
+				a.languageWords = make(map[string]int)
+			}
+			a.languageWords[event.Language] += len(strings.Fields(text))
+		}
+	}
+}
+
+// Language returns the detected language of most words of the finals, or
+// "" when the provider detected none. Ties go to the lowest code.
+func (a *transcriptAggregator) Language() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	language, most := "", 0
+	for code, words := range a.languageWords {
+		if words > most || (words == most && code < language) {
+			language, most = code, words
+		}
+	}
+	return language
+}
+
+// Confidence returns the word-weighted average confidence of the finals, or
+// false when the provider reported none.
+func (a *transcriptAggregator) Confidence() (float64, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.confidenceWords == 0 {
+		return 0, false
+	}
+	return a.confidenceSum / float64(a.confidenceWords), true
+}
+
+// Spans groups the words of the finals into runs of the same confidence
+// bucket, or returns nil when the provider reported no words.
+func (a *transcriptAggregator) Spans() []domain.TranscriptSpan {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var spans []domain.TranscriptSpan
+	for _, word := range a.words {
+		text := strings.TrimSpace(word.Text)
+		if text == "" {
+			continue
+		}
+		bucket := confidenceBucket(word.Confidence)
+		if n := len(spans); n > 0 && spans[n-1].Bucket == bucket {
+			spans[n-1].Text += " " + text
+			spans[n-1].Confidence = min(spans[n-1].Confidence, word.Confidence)
+			continue
+		}
+		spans = append(spans, domain.TranscriptSpan{Text: text, Confidence: word.Confidence, Bucket: bucket})
 	}
+	return spans
 }
 
+func confidenceBucket(confidence float64) domain.ConfidenceBucket {
+	switch {
+	case confidence >= highConfidence:
+		return domain.ConfidenceHigh
+	case confidence >= mediumConfidence:
+		return domain.ConfidenceMedium
+	default:
+		return domain.ConfidenceLow
+	}
+}
+
+// Words returns the words of the finals, or nil when the provider reported
+// none.
+func (a *transcriptAggregator) Words() []domain.TranscriptWord {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.words) == 0 {
+		return nil
+	}
+	out := make([]domain.TranscriptWord, len(a.words))
+	copy(out, a.words)
+	return out
+}
+
+// Segments returns the final utterances with their provider-reported offsets.
+func (a *transcriptAggregator) Segments() []domain.DialogueSegment {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.orderedSegments()
+}
+
+// orderedSegments copies the segments, sorting those of a multichannel
+// stream by offset since each channel's results arrive on their own
+// schedule.
+func (a *transcriptAggregator) orderedSegments() []domain.DialogueSegment {
+	out := make([]domain.DialogueSegment, len(a.segments))
+	copy(out, a.segments)
+	if a.multichannel {
+		slices.SortStableFunc(out, func(x, y domain.DialogueSegment) int { return cmp.Compare(x.Offset, y.Offset) })
+	}
+	return out
+}
+
+// Raw joins the finals, or renders them as "Speaker N: ..." lines when the
+// provider labelled speakers. Multichannel finals are ordered by time and
+// labelled by channel in ChannelModeLabel.
 func (a *transcriptAggregator) Raw() string {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
+	if a.multichannel {
+		segments := a.orderedSegments()
+		if a.channels.Mode == ChannelModeLabel {
+			return formatDialogue(segments)
+		}
+		texts := make([]string, len(segments))
+		for i, segment := range segments {
+			texts[i] = segment.Text
+		}
+		return strings.Join(texts, " ")
+	}
+	if a.diarized {
+		return formatDialogue(a.segments)
+	}
+
 	joined := strings.TrimSpace(strings.Join(a.finals, " "))
 	if joined == "" {
 		return a.lastSpoken
@@ -56,10 +239,55 @@ func (a *transcriptAggregator) Raw() string {
 	return joined
 }
 
+// splitBySpeaker turns diarized words into one segment per speaker turn, or
+// returns nil when no word has a speaker. Unlabelled words stay with the
+// turn they fall in.
+func splitBySpeaker(words []domain.TranscriptWord) []domain.DialogueSegment {
+	var segments []domain.DialogueSegment
+	speaker := 0
+	for _, word := range words {
+		text := strings.TrimSpace(word.Text)
+		if text == "" {
+			continue
+		}
+		if word.Speaker > 0 && word.Speaker != speaker {
+			if speaker == 0 && len(segments) == 1 {
+				// Leading unlabelled words belong to the first speaker.
+				segments[0].Label = speakerLabel(word.Speaker)
+			} else {
+				segments = append(segments, domain.DialogueSegment{Label: speakerLabel(word.Speaker), Offset: word.Start})
+			}
+			speaker = word.Speaker
+		}
+		if len(segments) == 0 {
+			segments = append(segments, domain.DialogueSegment{Offset: word.Start})
+		}
+		last := &segments[len(segments)-1]
+		last.Text = strings.TrimSpace(last.Text + " " + text)
+		last.Words = append(last.Words, word)
+	}
+	if speaker == 0 {
+		return nil
+	}
+	return segments
+}
+
+func speakerLabel(speaker int) string {
+	return "Speaker " + strconv.Itoa(speaker)
+}
+
+func (a *transcriptAggregator) channelLabel(channel int) string {
+	if channel <= len(a.channels.Labels) && a.channels.Labels[channel-1] != "" {
+		return a.channels.Labels[channel-1]
+	}
+	return "Channel " + strconv.Itoa(channel)
+}
+
 func consumeTranscriptionEvents(
 	session ports.StreamingSession,
 	aggregator *transcriptAggregator,
 	events ports.EventSink,
+	log *sessionLog,
 	done chan struct{},
 ) {
 	defer close(done)
@@ -69,6 +297,7 @@ func consumeTranscriptionEvents(
 		if text == "" {
 			continue
 		}
+		log.transcript(event)
 		aggregator.Add(event)
 		if event.Kind == domain.TranscriptKindPartial {
 			events.PartialTranscript(text)
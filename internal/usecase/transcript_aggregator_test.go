@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"testing"
+	"time"
 
 	"coldmic/internal/domain"
 )
@@ -29,3 +30,126 @@ func TestTranscriptAggregatorIgnoresEmpty(t *testing.T) {
 		t.Fatalf("expected empty, got %q", got)
 	}
 }
+
+func TestTranscriptAggregatorWeightsConfidenceByWords(t *testing.T) {
+	t.Parallel()
+
+	agg := newTranscriptAggregator()
+	if _, ok := agg.Confidence(); ok {
+		t.Fatalf("expected no confidence before finals")
+	}
+	agg.Add(domain.TranscriptEvent{Kind: domain.TranscriptKindPartial, Text: "ignored partial", Confidence: 0.1})
+	agg.Add(domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "one two three", Confidence: 0.9})
+	agg.Add(domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "four", Confidence: 0.5})
+	agg.Add(domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "unscored"})
+
+	got, ok := agg.Confidence()
+	if !ok || got < 0.799 || got > 0.801 {
+		t.Fatalf("unexpected confidence: %v %v", got, ok)
+	}
+}
+
+func TestTranscriptAggregatorReportsMainLanguage(t *testing.T) {
+	t.Parallel()
+
+	agg := newTranscriptAggregator()
+	if got := agg.Language(); got != "" {
+		t.Fatalf("expected no language before finals, got %q", got)
+	}
+	agg.Add(domain.TranscriptEvent{Kind: domain.TranscriptKindPartial, Text: "ignored partial words here", Language: "fr"})
+	agg.Add(domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "hello there", Language: "en"})
+	agg.Add(domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "guten Tag zusammen", Language: "de"})
+
+	if got := agg.Language(); got != "de" {
+		t.Fatalf("expected the language of most words, got %q", got)
+	}
+}
+
+func TestTranscriptAggregatorGroupsWordsIntoConfidenceSpans(t *testing.T) {
+	t.Parallel()
+
+	agg := newTranscriptAggregator()
+	if agg.Spans() != nil {
+		t.Fatalf("expected no spans without words")
+	}
+	agg.Add(domain.TranscriptEvent{Kind: domain.TranscriptKindPartial, Text: "draft", Words: []domain.TranscriptWord{{Text: "draft", Confidence: 0.1}}})
+	agg.Add(domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "Meet Siobhan at", Words: []domain.TranscriptWord{
+		{Text: "Meet", Confidence: 0.98},
+		{Text: "Siobhan", Confidence: 0.42},
+		{Text: "at", Confidence: 0.93},
+	}})
+	agg.Add(domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "noon today.", Words: []domain.TranscriptWord{
+		{Text: "noon", Confidence: 0.91},
+		{Text: "today.", Confidence: 0.75},
+	}})
+
+	want := []domain.TranscriptSpan{
+		{Text: "Meet", Confidence: 0.98, Bucket: domain.ConfidenceHigh},
+		{Text: "Siobhan", Confidence: 0.42, Bucket: domain.ConfidenceLow},
+		{Text: "at noon", Confidence: 0.91, Bucket: domain.ConfidenceHigh},
+		{Text: "today.", Confidence: 0.75, Bucket: domain.ConfidenceMedium},
+	}
+	got := agg.Spans()
+	if len(got) != len(want) {
+		t.Fatalf("unexpected spans: %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("span %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTranscriptAggregatorFormatsSpeakers(t *testing.T) {
+	t.Parallel()
+
+	agg := newTranscriptAggregator()
+	agg.Add(domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "so shall we ship", Words: []domain.TranscriptWord{
+		{Text: "so"},
+		{Text: "shall", Speaker: 1},
+		{Text: "we", Speaker: 1},
+		{Text: "ship?", Speaker: 1, Start: time.Second},
+	}})
+	agg.Add(domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "yes. Friday", Words: []domain.TranscriptWord{
+		{Text: "Yes.", Speaker: 2, Start: 2 * time.Second},
+		{Text: "Friday.", Speaker: 1, Start: 3 * time.Second},
+	}})
+
+	if got := agg.Raw(); got != "Speaker 1: so shall we ship?\nSpeaker 2: Yes.\nSpeaker 1: Friday." {
+		t.Fatalf("unexpected transcript: %q", got)
+	}
+	segments := agg.Segments()
+	if len(segments) != 3 || segments[1].Label != "Speaker 2" || segments[1].Offset != 2*time.Second || len(segments[0].Words) != 4 {
+		t.Fatalf("unexpected segments: %+v", segments)
+	}
+	if splitBySpeaker([]domain.TranscriptWord{{Text: "plain"}}) != nil {
+		t.Fatalf("expected no speaker segments without labels")
+	}
+}
+
+func TestTranscriptAggregatorCombinesChannels(t *testing.T) {
+	t.Parallel()
+
+	events := []domain.TranscriptEvent{
+		{Kind: domain.TranscriptKindFinal, Text: "Can you hear me?", Channel: 2, Start: 1 * time.Second},
+		{Kind: domain.TranscriptKindFinal, Text: "Hello.", Channel: 1, Start: 0},
+		{Kind: domain.TranscriptKindPartial, Text: "Yes", Channel: 1, Start: 2 * time.Second},
+		{Kind: domain.TranscriptKindFinal, Text: "Yes, loud and clear.", Channel: 1, Start: 2 * time.Second},
+	}
+
+	label := newChannelAggregator(ChannelConfig{Mode: ChannelModeLabel, Labels: []string{"Me"}})
+	merge := newChannelAggregator(ChannelConfig{Mode: ChannelModeMerge})
+	for _, event := range events {
+		label.Add(event)
+		merge.Add(event)
+	}
+	if got := label.Raw(); got != "Me: Hello.\nChannel 2: Can you hear me?\nMe: Yes, loud and clear." {
+		t.Fatalf("unexpected labelled transcript: %q", got)
+	}
+	if segments := label.Segments(); len(segments) != 3 || segments[1].Label != "Channel 2" || segments[1].Offset != time.Second {
+		t.Fatalf("unexpected segments: %+v", segments)
+	}
+	if got := merge.Raw(); got != "Hello. Can you hear me? Yes, loud and clear." {
+		t.Fatalf("unexpected merged transcript: %q", got)
+	}
+}
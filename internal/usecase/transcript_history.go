@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// maxDiffCells bounds the word-by-word comparison; longer transcripts that
+// differ throughout are reported as one replacement.
+const maxDiffCells = 4_000_000
+
+// TranscriptHistory keeps every pipeline stage of stored transcripts so users
+// can audit what rules and their own edits changed.
+type TranscriptHistory struct {
+	store ports.HistoryStore
+	rules ports.RulesEngine
+	now   func() time.Time
+}
+
+func NewTranscriptHistory(store ports.HistoryStore, rules ports.RulesEngine) *TranscriptHistory {
+	return &TranscriptHistory{store: store, rules: rules, now: time.Now}
+}
+
+// Edit records text as the user's version of the newest entry with id. The
+// raw and rules versions are kept; an empty text removes the edit.
+func (h *TranscriptHistory) Edit(ctx context.Context, id string, text string) (domain.HistoryEntry, error) {
+	entry, err := h.find(ctx, id)
+	if err != nil {
+		return domain.HistoryEntry{}, err
+	}
+	entry.EditedTranscript = strings.TrimSpace(text)
+	entry.EditedAt = h.now().UTC()
+	if entry.EditedTranscript == "" {
+		entry.EditedAt = time.Time{}
+	}
+	if err := h.store.Append(ctx, entry); err != nil {
+		return domain.HistoryEntry{}, err
+	}
+	return entry, nil
+}
+
+// Compare returns the stored versions of the newest entry with id and what
+// each stage changed.
+func (h *TranscriptHistory) Compare(ctx context.Context, id string) (domain.TranscriptComparison, error) {
+	entry, err := h.find(ctx, id)
+	if err != nil {
+		return domain.TranscriptComparison{}, err
+	}
+	comparison := domain.TranscriptComparison{
+		Entry: entry,
+		Stages: []domain.StageDiff{{
+			From: domain.TranscriptStageRaw,
+			To:   domain.TranscriptStageRules,
+			Ops:  diffWords(entry.RawTranscript, entry.FinalTranscript),
+		}},
+	}
+	if entry.EditedTranscript != "" {
+		comparison.Stages = append(comparison.Stages, domain.StageDiff{
+			From: domain.TranscriptStageRules,
+			To:   domain.TranscriptStageEdited,
+			Ops:  diffWords(entry.FinalTranscript, entry.EditedTranscript),
+		})
+	}
+	return comparison, nil
+}
+
+// Reapply runs the current rules over the raw transcripts of the matching
+// entries and returns how many came out different. User edits are left
+// alone, and the first rules output is kept as the original.
+func (h *TranscriptHistory) Reapply(ctx context.Context, filter domain.HistoryFilter) (int, error) {
+	entries, err := h.store.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	changed := 0
+	for _, entry := range entries {
+		if !filter.Matches(entry) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return changed, err
+		}
+		transformed, err := h.rules.Apply(entry.RawTranscript)
+		if err != nil {
+			return changed, fmt.Errorf("history entry %s: %w", entry.ID, err)
+		}
+		if transformed == entry.FinalTranscript {
+			continue
+		}
+		if entry.OriginalTranscript == "" {
+			entry.OriginalTranscript = entry.FinalTranscript
+		}
+		entry.FinalTranscript = transformed
+		entry.ReappliedAt = h.now().UTC()
+		if err := h.store.Append(ctx, entry); err != nil {
+			return changed, err
+		}
+		changed++
+	}
+	return changed, nil
+}
+
+// find returns the newest entry with id, since IDs restart with each process.
+func (h *TranscriptHistory) find(ctx context.Context, id string) (domain.HistoryEntry, error) {
+	if strings.TrimSpace(id) == "" {
+		return domain.HistoryEntry{}, errors.New("history entry id is required")
+	}
+	entries, err := h.store.List(ctx)
+	if err != nil {
+		return domain.HistoryEntry{}, err
+	}
+	var found *domain.HistoryEntry
+	for i := range entries {
+		if entries[i].ID == id && (found == nil || !entries[i].CreatedAt.Before(found.CreatedAt)) {
+			found = &entries[i]
+		}
+	}
+	if found == nil {
+		return domain.HistoryEntry{}, fmt.Errorf("history entry %s not found", id)
+	}
+	return *found, nil
+}
+
+// diffWords compares two texts word by word and merges consecutive words
+// with the same fate into one op.
+func diffWords(from string, to string) []domain.DiffOp {
+	a, b := strings.Fields(from), strings.Fields(to)
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []domain.DiffOp
+	add := func(kind domain.DiffOpKind, words ...string) {
+		if len(words) == 0 {
+			return
+		}
+		if n := len(ops); n > 0 && ops[n-1].Kind == kind {
+			ops[n-1].Text += " " + strings.Join(words, " ")
+			return
+		}
+		ops = append(ops, domain.DiffOp{Kind: kind, Text: strings.Join(words, " ")})
+	}
+
+	add(domain.DiffOpEqual, a[:prefix]...)
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(midA)*len(midB) > maxDiffCells {
+		add(domain.DiffOpDelete, midA...)
+		add(domain.DiffOpInsert, midB...)
+	} else {
+		// lcs[i][j] is the longest common subsequence of midA[i:] and midB[j:].
+		lcs := make([][]int, len(midA)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(midB)+1)
+		}
+		for i := len(midA) - 1; i >= 0; i-- {
+			for j := len(midB) - 1; j >= 0; j-- {
+				if midA[i] == midB[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < len(midA) || j < len(midB) {
+			switch {
+			case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+				add(domain.DiffOpEqual, midA[i])
+				i++
+				j++
+			case i < len(midA) && (j == len(midB) || lcs[i+1][j] >= lcs[i][j+1]):
+				add(domain.DiffOpDelete, midA[i])
+				i++
+			default:
+				add(domain.DiffOpInsert, midB[j])
+				j++
+			}
+		}
+	}
+	add(domain.DiffOpEqual, a[len(a)-suffix:]...)
+	return ops
+}
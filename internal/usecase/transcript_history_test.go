@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"coldmic/internal/domain"
+)
+
+func TestTranscriptHistoryEditAndCompare(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeHistoryStore{entries: []domain.HistoryEntry{
+		{ID: "file-1", RawTranscript: "old run", FinalTranscript: "old run", CreatedAt: time.Unix(1, 0)},
+		{ID: "file-1", RawTranscript: "ship it on friday at noon", FinalTranscript: "Ship it on Friday at 12:00.", CreatedAt: time.Unix(2, 0)},
+	}}
+	history := NewTranscriptHistory(store, &fakeRules{})
+	history.now = func() time.Time { return time.Unix(10, 0) }
+
+	entry, err := history.Edit(context.Background(), "file-1", " Ship it on Monday at 12:00. ")
+	if err != nil {
+		t.Fatalf("edit failed: %v", err)
+	}
+	if entry.EditedTranscript != "Ship it on Monday at 12:00." || entry.RawTranscript != "ship it on friday at noon" || !entry.EditedAt.Equal(time.Unix(10, 0)) {
+		t.Fatalf("unexpected edited entry: %+v", entry)
+	}
+
+	comparison, err := history.Compare(context.Background(), "file-1")
+	if err != nil {
+		t.Fatalf("compare failed: %v", err)
+	}
+	if len(comparison.Stages) != 2 || comparison.Stages[1].From != domain.TranscriptStageRules || comparison.Stages[1].To != domain.TranscriptStageEdited {
+		t.Fatalf("unexpected stages: %+v", comparison.Stages)
+	}
+	want := []domain.DiffOp{
+		{Kind: domain.DiffOpEqual, Text: "Ship it on"},
+		{Kind: domain.DiffOpDelete, Text: "Friday"},
+		{Kind: domain.DiffOpInsert, Text: "Monday"},
+		{Kind: domain.DiffOpEqual, Text: "at 12:00."},
+	}
+	if !reflect.DeepEqual(comparison.Stages[1].Ops, want) {
+		t.Fatalf("unexpected edit diff: %+v", comparison.Stages[1].Ops)
+	}
+
+	if _, err := history.Compare(context.Background(), "file-9"); err == nil {
+		t.Fatalf("expected unknown entry to fail")
+	}
+}
+
+func TestDiffWords(t *testing.T) {
+	t.Parallel()
+
+	got := diffWords("um so the API is down", "So the API is down.")
+	want := []domain.DiffOp{
+		{Kind: domain.DiffOpDelete, Text: "um so"},
+		{Kind: domain.DiffOpInsert, Text: "So"},
+		{Kind: domain.DiffOpEqual, Text: "the API is"},
+		{Kind: domain.DiffOpDelete, Text: "down"},
+		{Kind: domain.DiffOpInsert, Text: "down."},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected diff: %+v", got)
+	}
+	if ops := diffWords("same text", "same text"); len(ops) != 1 || ops[0].Kind != domain.DiffOpEqual {
+		t.Fatalf("expected one equal op, got %+v", ops)
+	}
+	if ops := diffWords("", ""); ops != nil {
+		t.Fatalf("expected no ops, got %+v", ops)
+	}
+}
+
+func TestTranscriptHistoryReapplyKeepsOriginals(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeHistoryStore{entries: []domain.HistoryEntry{
+		{ID: "file-1", Source: domain.HistorySourceFile, RawTranscript: "ship it", FinalTranscript: "Ship it.", EditedTranscript: "Ship it!", CreatedAt: time.Unix(1, 0)},
+		{ID: "url-1", Source: domain.HistorySourceURL, RawTranscript: "ship it", FinalTranscript: "Ship it.", CreatedAt: time.Unix(2, 0)},
+		{ID: "file-2", Source: domain.HistorySourceFile, RawTranscript: "later", FinalTranscript: "Later.", CreatedAt: time.Unix(5, 0)},
+	}}
+	rules := &fakeRules{transform: "Ship it today."}
+	history := NewTranscriptHistory(store, rules)
+
+	filter := domain.HistoryFilter{Source: domain.HistorySourceFile, Until: time.Unix(3, 0), Contains: "SHIP"}
+	changed, err := history.Reapply(context.Background(), filter)
+	if err != nil || changed != 1 {
+		t.Fatalf("expected one changed entry, got %d %v", changed, err)
+	}
+	entries := store.snapshot()
+	revised := entries[len(entries)-1]
+	if revised.ID != "file-1" || revised.FinalTranscript != "Ship it today." || revised.OriginalTranscript != "Ship it." || revised.EditedTranscript != "Ship it!" || revised.ReappliedAt.IsZero() {
+		t.Fatalf("unexpected revision: %+v", revised)
+	}
+
+	rules.transform = "Ship it now."
+	store.entries = []domain.HistoryEntry{revised}
+	if changed, err := history.Reapply(context.Background(), domain.HistoryFilter{}); err != nil || changed != 1 {
+		t.Fatalf("expected one changed entry, got %d %v", changed, err)
+	}
+	if again := store.snapshot()[1]; again.OriginalTranscript != "Ship it." {
+		t.Fatalf("expected the first rules output to be kept, got %+v", again)
+	}
+	store.entries = store.entries[1:]
+	if changed, _ := history.Reapply(context.Background(), domain.HistoryFilter{IDs: []string{"file-1"}}); changed != 0 {
+		t.Fatalf("expected unchanged output to be skipped, got %d", changed)
+	}
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// URLTranscriber downloads remote media and feeds it through file transcription.
+type URLTranscriber struct {
+	downloader  ports.MediaDownloader
+	transcriber fileTranscriber
+	history     ports.HistoryStore
+	tempDir     string
+
+	nextID atomic.Uint64
+}
+
+func NewURLTranscriber(downloader ports.MediaDownloader, transcriber fileTranscriber, history ports.HistoryStore, tempDir string) *URLTranscriber {
+	return &URLTranscriber{downloader: downloader, transcriber: transcriber, history: history, tempDir: tempDir}
+}
+
+// TranscribeURL downloads sourceURL into a scratch directory, transcribes it,
+// and records the result in history. Downloaded audio is always removed.
+func (t *URLTranscriber) TranscribeURL(ctx context.Context, sourceURL string) (domain.FileTranscript, error) {
+	sourceURL = strings.TrimSpace(sourceURL)
+	parsed, err := url.Parse(sourceURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return domain.FileTranscript{}, errors.New("url must be an absolute http(s) URL")
+	}
+
+	scratch, err := os.MkdirTemp(t.tempDir, "coldmic-url-")
+	if err != nil {
+		return domain.FileTranscript{}, fmt.Errorf("failed to create download directory: %w", err)
+	}
+	defer os.RemoveAll(scratch)
+
+	path, err := t.downloader.Download(ctx, sourceURL, scratch)
+	if err != nil {
+		return domain.FileTranscript{}, err
+	}
+
+	transcript, err := t.transcriber.TranscribeFile(ctx, path)
+	if err != nil {
+		return domain.FileTranscript{}, err
+	}
+	transcript.SourcePath = sourceURL
+
+	entry := domain.HistoryEntry{
+		ID:              fmt.Sprintf("url-%d", t.nextID.Add(1)),
+		Source:          domain.HistorySourceURL,
+		SourcePath:      sourceURL,
+		RawTranscript:   transcript.RawTranscript,
+		FinalTranscript: transcript.FinalTranscript,
+		CreatedAt:       time.Now().UTC(),
+	}
+	if err := t.history.Append(ctx, entry); err != nil {
+		debuglog.Printf("url transcription history append failed url=%q: %v", sourceURL, err)
+	}
+
+	return transcript, nil
+}
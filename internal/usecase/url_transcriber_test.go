@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"coldmic/internal/domain"
+)
+
+func TestURLTranscriberDownloadsTranscribesAndCleansUp(t *testing.T) {
+	t.Parallel()
+
+	downloader := &fakeDownloader{}
+	history := &fakeHistoryStore{}
+	transcriber := NewURLTranscriber(downloader, &fakeFileTranscriber{text: "talk"}, history, t.TempDir())
+
+	result, err := transcriber.TranscribeURL(context.Background(), " https://example.com/watch?v=1 ")
+	if err != nil {
+		t.Fatalf("transcribe failed: %v", err)
+	}
+	if result.SourcePath != "https://example.com/watch?v=1" || result.FinalTranscript != "talk" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	entries := history.snapshot()
+	if len(entries) != 1 || entries[0].Source != domain.HistorySourceURL || entries[0].SourcePath != result.SourcePath {
+		t.Fatalf("unexpected history: %+v", entries)
+	}
+	if _, err := os.Stat(downloader.dir); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected scratch directory removal, got %v", err)
+	}
+}
+
+func TestURLTranscriberRejectsInvalidURL(t *testing.T) {
+	t.Parallel()
+
+	transcriber := NewURLTranscriber(&fakeDownloader{}, &fakeFileTranscriber{}, &fakeHistoryStore{}, t.TempDir())
+	for _, input := range []string{"", "file:///etc/passwd", "not a url", "https://"} {
+		if _, err := transcriber.TranscribeURL(context.Background(), input); err == nil {
+			t.Fatalf("expected %q to be rejected", input)
+		}
+	}
+}
+
+func TestURLTranscriberPropagatesDownloadError(t *testing.T) {
+	t.Parallel()
+
+	history := &fakeHistoryStore{}
+	transcriber := NewURLTranscriber(&fakeDownloader{err: errors.New("geo blocked")}, &fakeFileTranscriber{}, history, t.TempDir())
+
+	if _, err := transcriber.TranscribeURL(context.Background(), "https://example.com/x"); err == nil || err.Error() != "geo blocked" {
+		t.Fatalf("expected download error, got %v", err)
+	}
+	if len(history.snapshot()) != 0 {
+		t.Fatalf("expected no history on failure")
+	}
+}
+
+type fakeDownloader struct {
+	dir string
+	err error
+}
+
+func (f *fakeDownloader) Download(_ context.Context, _ string, destDir string) (string, error) {
+	f.dir = destDir
+	if f.err != nil {
+		return "", f.err
+	}
+	path := filepath.Join(destDir, "audio.wav")
+	return path, os.WriteFile(path, []byte("pcm"), 0o600)
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"coldmic/internal/debuglog"
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+// usageMonthLayout formats the month usage totals are kept under.
+const usageMonthLayout = "2006-01"
+
+// UsageMeter prices the audio each session sends to its provider and keeps
+// the running monthly total.
+type UsageMeter struct {
+	store ports.UsageStore
+	// rates is the cost per audio minute by provider name.
+	rates map[string]float64
+	clock ports.Clock
+}
+
+// NewUsageMeter prices audio at rates, the cost per audio minute by
+// provider; a nil clock uses the wall clock.
+func NewUsageMeter(store ports.UsageStore, rates map[string]float64, clock ports.Clock) *UsageMeter {
+	return &UsageMeter{store: store, rates: rates, clock: clockOrSystem(clock)}
+}
+
+// Record prices seconds of audio sent to provider and adds them to this
+// month's total. A failure to save is logged, since the session itself
+// succeeded.
+func (m *UsageMeter) Record(ctx context.Context, provider string, seconds float64) domain.SessionUsage {
+	usage := domain.SessionUsage{
+		Provider:     provider,
+		AudioSeconds: seconds,
+		Cost:         seconds / 60 * m.rates[provider],
+	}
+	if err := m.store.Add(ctx, m.clock.Now().Format(usageMonthLayout), usage); err != nil {
+		debuglog.Printf("usage record failed: %v", err)
+	}
+	return usage
+}
+
+// Month returns the usage of month, such as "2026-10", or of the current
+// month when month is empty.
+func (m *UsageMeter) Month(ctx context.Context, month string) (domain.UsageTotal, error) {
+	if month == "" {
+		month = m.clock.Now().Format(usageMonthLayout)
+	} else if _, err := time.Parse(usageMonthLayout, month); err != nil {
+		return domain.UsageTotal{}, fmt.Errorf("invalid month %q: use YYYY-MM", month)
+	}
+	return m.store.Month(ctx, month)
+}
+
+// providerNames returns the services provider streams audio to, naming
+// providers that do not report them "unknown".
+func providerNames(provider ports.TranscriptionProvider) []string {
+	if namer, ok := provider.(ports.ProviderNamer); ok {
+		if names := namer.ProviderNames(); len(names) > 0 {
+			return names
+		}
+	}
+	return []string{"unknown"}
+}
+
+// audioSeconds is the duration of n bytes of 16-bit audio in cfg.
+func audioSeconds(n int64, cfg ports.AudioConfig) float64 {
+	sampleRate := cfg.SampleRate
+	if sampleRate <= 0 {
+		sampleRate = 16000
+	}
+	return float64(n) / float64(sampleRate*max(cfg.Channels, 1)*2)
+}
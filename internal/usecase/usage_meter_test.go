@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"coldmic/internal/domain"
+	"coldmic/internal/ports"
+)
+
+func TestSessionControllerReportsUsage(t *testing.T) {
+	t.Parallel()
+
+	stream := newFakeStreamingSession()
+	stream.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "hello"}
+	accurate := newFakeStreamingSession()
+	accurate.events <- domain.TranscriptEvent{Kind: domain.TranscriptKindFinal, Text: "Hello."}
+	// Two seconds of 16 kHz mono audio.
+	audio := &fakeAudioSession{chunks: [][]byte{make([]byte, 32000), make([]byte, 32000)}}
+	store := &fakeUsageStore{}
+	controller := NewSessionController(
+		&fakeAudioCapture{sessions: []ports.AudioSession{audio}},
+		namedProvider{&fakeProvider{sessions: []ports.StreamingSession{stream}}, []string{"openai"}},
+		&fakeRules{},
+		&fakeClipboard{},
+		&fakeEventSink{},
+		Config{Audio: ports.AudioConfig{SampleRate: 16000, Channels: 1}, ChunkSize: 32000},
+	)
+	controller.SetUsage(NewUsageMeter(store, map[string]float64{"deepgram": 0.6, "openai": 0.3}, newFakeClock()))
+	controller.SetAccurateProvider(namedProvider{&fakeProvider{sessions: []ports.StreamingSession{accurate}}, []string{"deepgram"}})
+
+	if err := controller.Start(context.Background()); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	result, err := controller.Stop(context.Background())
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	want := []domain.SessionUsage{
+		{Provider: "openai", AudioSeconds: 2, Cost: 0.01},
+		{Provider: "deepgram", AudioSeconds: 2, Cost: 0.02},
+	}
+	if !slices.Equal(result.Usage, want) {
+		t.Fatalf("expected usage %+v, got %+v", want, result.Usage)
+	}
+
+	total, err := controller.usage.Month(context.Background(), "")
+	if err != nil {
+		t.Fatalf("month failed: %v", err)
+	}
+	if total.Month != "2026-01" || total.Sessions != 2 || total.AudioSeconds != 4 {
+		t.Fatalf("expected both passes in the current month, got %+v", total)
+	}
+	if _, err := controller.usage.Month(context.Background(), "january"); err == nil {
+		t.Fatalf("expected an invalid month to fail")
+	}
+}
+
+func TestProviderNamesCoverWrappedProviders(t *testing.T) {
+	t.Parallel()
+
+	race := NewRaceProvider(
+		namedProvider{&fakeProvider{}, []string{"deepgram"}},
+		namedProvider{&fakeProvider{}, []string{"assemblyai"}},
+		RaceConfig{},
+	)
+	if got := providerNames(race); !slices.Equal(got, []string{"deepgram", "assemblyai"}) {
+		t.Fatalf("expected both racing providers, got %q", got)
+	}
+	redacting := NewLocalRedactingProvider(race, nil, LocalRedactionConfig{})
+	if got := providerNames(redacting); !slices.Equal(got, []string{"deepgram", "assemblyai"}) {
+		t.Fatalf("expected the screened providers, got %q", got)
+	}
+	if got := providerNames(&fakeProvider{}); !slices.Equal(got, []string{"unknown"}) {
+		t.Fatalf("expected an unnamed provider to be unknown, got %q", got)
+	}
+}
+
+// namedProvider reports names for usage metering.
+type namedProvider struct {
+	*fakeProvider
+	names []string
+}
+
+func (p namedProvider) ProviderNames() []string {
+	return p.names
+}
+
+type fakeUsageStore struct {
+	mu     sync.Mutex
+	months map[string][]domain.SessionUsage
+}
+
+func (f *fakeUsageStore) Add(_ context.Context, month string, usage domain.SessionUsage) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.months == nil {
+		f.months = map[string][]domain.SessionUsage{}
+	}
+	f.months[month] = append(f.months[month], usage)
+	return nil
+}
+
+func (f *fakeUsageStore) Month(_ context.Context, month string) (domain.UsageTotal, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	total := domain.UsageTotal{Month: month}
+	for _, usage := range f.months[month] {
+		total.Sessions++
+		total.AudioSeconds += usage.AudioSeconds
+		total.Cost += usage.Cost
+	}
+	return total, nil
+}